@@ -0,0 +1,58 @@
+// Package abi contains helpers for asserting the memory layout of Go types that represent
+// [Component Model] types, such as those in package [cm] and those emitted by wit-bindgen-go.
+// They are intended for use in tests of generated bindings and by third-party generators,
+// and compute sizes and alignments with the same math as the [Canonical ABI].
+//
+// [Component Model]: https://component-model.bytecodealliance.org/introduction.html
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#alignment
+// [cm]: https://pkg.go.dev/github.com/ydnar/wasm-tools-go/cm
+package abi
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// Align aligns ptr with alignment align, which must be a power of 2.
+func Align(ptr, align uintptr) uintptr {
+	return (ptr + align - 1) &^ (align - 1)
+}
+
+// SizeOf returns the size in bytes of a value of type T.
+func SizeOf[T any]() uintptr {
+	var v T
+	return unsafe.Sizeof(v)
+}
+
+// AlignOf returns the alignment in bytes of a value of type T.
+func AlignOf[T any]() uintptr {
+	var v T
+	return unsafe.Alignof(v)
+}
+
+// SizePlusAlignOf returns the size of T plus its alignment. This is the size of a
+// variant or result whose largest case has type T and whose alignment is that of T,
+// as the discriminant is padded to the alignment of T.
+func SizePlusAlignOf[T any]() uintptr {
+	var v T
+	return unsafe.Sizeof(v) + unsafe.Alignof(v)
+}
+
+// OffsetOf returns the offset in bytes of field f within struct s.
+// It is equivalent to [unsafe.Offsetof], which is not supported by TinyGo.
+func OffsetOf[Struct, Field any](s *Struct, f *Field) uintptr {
+	return uintptr(unsafe.Pointer(f)) - uintptr(unsafe.Pointer(s))
+}
+
+// TypeName returns the Go type name of v without spaces, e.g. "cm.Option[uint32]",
+// or "*cm.List[uint8]" if v is a pointer. It is suitable for test names and failure messages.
+func TypeName(v any) string {
+	var name string
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr {
+		name = "*" + t.Elem().String()
+	} else {
+		name = t.String()
+	}
+	return strings.ReplaceAll(name, " ", "")
+}
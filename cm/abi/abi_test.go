@@ -0,0 +1,61 @@
+package abi
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		ptr, align, want uintptr
+	}{
+		{0, 1, 0},
+		{1, 1, 1},
+		{1, 4, 4},
+		{4, 4, 4},
+		{5, 8, 8},
+		{9, 8, 16},
+	}
+	for _, tt := range tests {
+		if got := Align(tt.ptr, tt.align); got != tt.want {
+			t.Errorf("Align(%d, %d): %d, expected %d", tt.ptr, tt.align, got, tt.want)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	type s struct {
+		a uint8
+		b uint64
+	}
+	if got, want := SizeOf[s](), unsafe.Sizeof(s{}); got != want {
+		t.Errorf("SizeOf: %d, expected %d", got, want)
+	}
+	if got, want := AlignOf[s](), unsafe.Alignof(s{}); got != want {
+		t.Errorf("AlignOf: %d, expected %d", got, want)
+	}
+	if got, want := SizePlusAlignOf[uint32](), uintptr(8); got != want {
+		t.Errorf("SizePlusAlignOf[uint32]: %d, expected %d", got, want)
+	}
+	var v s
+	if got, want := OffsetOf(&v, &v.b), unsafe.Offsetof(v.b); got != want {
+		t.Errorf("OffsetOf: %d, expected %d", got, want)
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		v    any
+		want string
+	}{
+		{uint8(0), "uint8"},
+		{struct{ a, b int }{}, "struct{aint;bint}"},
+		{new(string), "*string"},
+		{[]struct{}{}, "[]struct{}"},
+	}
+	for _, tt := range tests {
+		if got := TypeName(tt.v); got != tt.want {
+			t.Errorf("TypeName(%T): %q, expected %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build 386 || arm || mips || mipsle || wasm
+
+package cm
+
+// ArchShape is the storage of a [Variant] whose largest associated type depends on the
+// size of a pointer. It holds Shape32 on 32-bit architectures, including WebAssembly,
+// and Shape64 on 64-bit architectures, where types that contain pointers are larger.
+type ArchShape[Shape32, Shape64 any] struct{ _ Shape32 }
+
+// ArchResult represents a result whose larger type depends on the size of a pointer.
+// It is sized to hold Shape32 on 32-bit architectures, including WebAssembly, and
+// Shape64 on 64-bit architectures. Shape32 and Shape64 must each be OK or Err.
+type ArchResult[Shape32, Shape64, OK, Err any] result[Shape32, OK, Err]
+
+// IsErr returns true if r represents the error case.
+func (r *ArchResult[Shape32, Shape64, OK, Err]) IsErr() bool {
+	return r.isErr
+}
+
+// OK returns a non-nil *OK pointer if r represents the OK case.
+// If r represents an error, then it returns nil.
+func (r *ArchResult[Shape32, Shape64, OK, Err]) OK() *OK {
+	return (*result[Shape32, OK, Err])(r).OK()
+}
+
+// Err returns a non-nil *Err pointer if r represents the error case.
+// If r represents the OK case, then it returns nil.
+func (r *ArchResult[Shape32, Shape64, OK, Err]) Err() *Err {
+	return (*result[Shape32, OK, Err])(r).Err()
+}
@@ -0,0 +1,62 @@
+package cm
+
+import (
+	"testing"
+	"unsafe"
+)
+
+var _ resulter[[3]uint32, string] = &ArchResult[[3]uint32, string, [3]uint32, string]{}
+
+// is64Bit is true on architectures with 64-bit pointers.
+const is64Bit = unsafe.Sizeof(uintptr(0)) == 8
+
+func TestArchShape(t *testing.T) {
+	// variant { a(tuple<u32, u32, u32>), b(string) } is 16 bytes on 32-bit
+	// architectures, with a shape of tuple<u32, u32, u32>, but string is larger
+	// than tuple<u32, u32, u32> with 64-bit pointers.
+	type V Variant[uint8, ArchShape[[3]uint32, string], uint32]
+
+	want := uintptr(16)
+	if is64Bit {
+		want = 24
+	}
+	if got := unsafe.Sizeof(V{}); got != want {
+		t.Errorf("unsafe.Sizeof(V{}): %d, expected %d", got, want)
+	}
+
+	a := New[V](0, [3]uint32{1, 2, 3})
+	if got := Case[[3]uint32](&a, 0); got == nil || *got != [3]uint32{1, 2, 3} {
+		t.Errorf("Case[[3]uint32](&a, 0): %v, expected [1 2 3]", got)
+	}
+	b := New[V](1, "hello")
+	if got := Case[string](&b, 1); got == nil || *got != "hello" {
+		t.Errorf("Case[string](&b, 1): %v, expected hello", got)
+	}
+}
+
+func TestArchResult(t *testing.T) {
+	type R = ArchResult[[3]uint32, string, [3]uint32, string]
+
+	want := uintptr(16)
+	if is64Bit {
+		want = 24
+	}
+	if got := unsafe.Sizeof(R{}); got != want {
+		t.Errorf("unsafe.Sizeof(R{}): %d, expected %d", got, want)
+	}
+
+	ok := OK[R]([3]uint32{1, 2, 3})
+	if got := ok.OK(); got == nil || *got != [3]uint32{1, 2, 3} {
+		t.Errorf("OK(): %v, expected [1 2 3]", got)
+	}
+	if ok.IsErr() {
+		t.Error("IsErr(): true, expected false")
+	}
+	err := Err[R]("error")
+	if got := err.Err(); got == nil || *got != "error" {
+		t.Errorf("Err(): %v, expected error", got)
+	}
+	if got := err.OK(); got != nil {
+		t.Errorf("OK(): %v, expected nil", got)
+	}
+}
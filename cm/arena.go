@@ -0,0 +1,137 @@
+package cm
+
+import "unsafe"
+
+// defaultArenaSize is the initial size in bytes of the storage for each element type
+// allocated by an [Arena] created with [NewArena](0).
+const defaultArenaSize = 1024
+
+// Arena allocates the storage of transient lowered lists, such as the list params of a
+// single call to an imported function, from buffers that are reused after [Arena.Reset],
+// rather than allocating each list on the Go heap. This reduces garbage collection in
+// hot paths that lower data for each call, such as writing to a stream.
+//
+// Lists allocated from an Arena are valid until the next call to Reset, after which
+// their storage is cleared and reused. Storage is allocated separately for each element
+// type, so lists of types that contain Go pointers, such as List[List[uint8]], are safe
+// to hold until Reset. An Arena grows to fit the lists allocated between resets.
+//
+// An Arena is owned by its caller, which lowers the lists for a call with it, then calls
+// Reset after the call returns. Generated bindings never allocate from or reset an Arena.
+// The functions that allocate from an Arena allocate from the Go heap if the Arena is nil.
+// An Arena is not safe for concurrent use, so each goroutine should own its own Arena.
+type Arena struct {
+	size  uintptr
+	slabs map[any]resetter
+}
+
+// NewArena returns a new [Arena] that initially allocates size bytes of storage
+// for each element type. If size is 0, a default size is used.
+func NewArena(size int) *Arena {
+	if size <= 0 {
+		size = defaultArenaSize
+	}
+	return &Arena{size: uintptr(size)}
+}
+
+// Reset clears the storage of the lists allocated from a, which can then be reused.
+// Lists allocated from a before Reset must not be used after Reset.
+func (a *Arena) Reset() {
+	if a == nil {
+		return
+	}
+	for _, s := range a.slabs {
+		s.reset()
+	}
+}
+
+// CopyListString returns a List[uint8] with a copy of the bytes of the Go string s,
+// allocated from a. It is the equivalent of [CopyListString] for an [Arena].
+func (a *Arena) CopyListString(s string) List[uint8] {
+	if a == nil || len(s) == 0 {
+		return CopyListString(s)
+	}
+	b := arenaAlloc[uint8](a, len(s))
+	copy(b, s)
+	return ToList(b)
+}
+
+// CopyListIn returns a List[T] with a copy of the elements of the Go slice s,
+// allocated from a. It is the equivalent of [CopyList] for an [Arena].
+func CopyListIn[S ~[]T, T any](a *Arena, s S) List[T] {
+	if a == nil || len(s) == 0 {
+		return CopyList(s)
+	}
+	elems := arenaAlloc[T](a, len(s))
+	copy(elems, s)
+	return ToList(elems)
+}
+
+// LowerListIn returns a List[T] with the result of calling lower on each element of the
+// Go slice s, allocated from a. It is the equivalent of [LowerList] for an [Arena].
+// Lists returned by lower may also be allocated from a, e.g. with [Arena.CopyListString].
+func LowerListIn[S ~[]E, E any, T any](a *Arena, s S, lower func(E) T) List[T] {
+	if a == nil || len(s) == 0 {
+		return LowerList(s, lower)
+	}
+	elems := arenaAlloc[T](a, len(s))
+	for i := range s {
+		elems[i] = lower(s[i])
+	}
+	return ToList(elems)
+}
+
+// resetter is implemented by the storage of each element type in an [Arena].
+type resetter interface {
+	reset()
+}
+
+// slab is the storage for elements of type T in an [Arena].
+// Elements buf[:used] are allocated.
+type slab[T any] struct {
+	buf  []T
+	used int
+}
+
+// arenaAlloc allocates n elements of type T from a, which must not be nil.
+func arenaAlloc[T any](a *Arena, n int) []T {
+	// A nil *T is a distinct map key for each type T.
+	key := any((*T)(nil))
+	s, _ := a.slabs[key].(*slab[T])
+	if s == nil {
+		if a.slabs == nil {
+			a.slabs = make(map[any]resetter)
+		}
+		s = &slab[T]{}
+		a.slabs[key] = s
+	}
+	return s.alloc(n, a.size)
+}
+
+// alloc allocates n elements from s. If s does not have room for n elements,
+// it replaces buf with a larger buffer. Elements allocated from the previous buffer
+// remain valid, and the larger buffer is reused after reset.
+func (s *slab[T]) alloc(n int, size uintptr) []T {
+	if n > len(s.buf)-s.used {
+		c := 2 * len(s.buf)
+		if c == 0 {
+			var zero T
+			if elem := unsafe.Sizeof(zero); elem > 0 {
+				c = int(size / elem)
+			}
+		}
+		if c < n {
+			c = n
+		}
+		s.buf = make([]T, c)
+		s.used = 0
+	}
+	elems := s.buf[s.used : s.used+n : s.used+n]
+	s.used += n
+	return elems
+}
+
+func (s *slab[T]) reset() {
+	clear(s.buf[:s.used])
+	s.used = 0
+}
@@ -0,0 +1,60 @@
+package cm
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena(16)
+	s := a.CopyListString("hello")
+	l := CopyListIn(a, []int32{1, 2, 3})
+	strs := LowerListIn(a, []string{"a", "bc"}, a.CopyListString)
+	if got := string(s.Slice()); got != "hello" {
+		t.Errorf("CopyListString: %q, expected %q", got, "hello")
+	}
+	if !slices.Equal(l.Slice(), []int32{1, 2, 3}) {
+		t.Errorf("CopyListIn: %v, expected [1 2 3]", l.Slice())
+	}
+	if strs.Len() != 2 || string(strs.Slice()[0].Slice()) != "a" || string(strs.Slice()[1].Slice()) != "bc" {
+		t.Errorf("LowerListIn: %v, expected [a bc]", strs.Slice())
+	}
+
+	// Growing the arena does not move lists allocated before.
+	big := a.CopyListString(string(make([]byte, 100)))
+	if got := string(s.Slice()); got != "hello" {
+		t.Errorf("CopyListString: %q after growing, expected %q", got, "hello")
+	}
+	if big.Len() != 100 {
+		t.Errorf("CopyListString: Len() %d, expected 100", big.Len())
+	}
+
+	// Storage is reused after Reset.
+	a.Reset()
+	if got := a.CopyListString("world"); got.Data() != big.Data() || string(got.Slice()) != "world" {
+		t.Errorf("CopyListString after Reset: %q at %p, expected %q at %p", got.Slice(), got.Data(), "world", big.Data())
+	}
+}
+
+func TestArenaAllocs(t *testing.T) {
+	a := NewArena(0)
+	s := []string{"content-type", "text/plain"}
+	allocs := testing.AllocsPerRun(100, func() {
+		LowerListIn(a, s, a.CopyListString)
+		a.Reset()
+	})
+	if allocs != 0 {
+		t.Errorf("LowerListIn: %v allocs per run, expected 0", allocs)
+	}
+}
+
+func TestArenaNil(t *testing.T) {
+	var a *Arena
+	if got := string(a.CopyListString("hello").Slice()); got != "hello" {
+		t.Errorf("CopyListString: %q, expected %q", got, "hello")
+	}
+	if got := CopyListIn(a, []int32{1, 2}).Slice(); !slices.Equal(got, []int32{1, 2}) {
+		t.Errorf("CopyListIn: %v, expected [1 2]", got)
+	}
+	a.Reset()
+}
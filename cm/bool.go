@@ -0,0 +1,20 @@
+package cm
+
+// BoolToU32 converts a bool into a uint32 for lowering into Core WebAssembly,
+// returning 1 if v is true, otherwise 0. See the [Canonical ABI] for more information.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flat-lowering
+func BoolToU32[B ~bool](v B) uint32 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// U32ToBool converts a uint32 lifted from Core WebAssembly into a bool.
+// Any non-zero value is true, as specified by the [Canonical ABI].
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flat-lifting
+func U32ToBool(v uint32) bool {
+	return v != 0
+}
@@ -0,0 +1,30 @@
+package cm
+
+import "testing"
+
+func TestBoolToU32(t *testing.T) {
+	if got, want := BoolToU32(false), uint32(0); got != want {
+		t.Errorf("BoolToU32(false): %d, expected %d", got, want)
+	}
+	if got, want := BoolToU32(true), uint32(1); got != want {
+		t.Errorf("BoolToU32(true): %d, expected %d", got, want)
+	}
+}
+
+func TestU32ToBool(t *testing.T) {
+	tests := []struct {
+		v    uint32
+		want bool
+	}{
+		{0, false},
+		{1, true},
+		{2, true},
+		{0x80000000, true},
+		{0xffffffff, true},
+	}
+	for _, tt := range tests {
+		if got := U32ToBool(tt.v); got != tt.want {
+			t.Errorf("U32ToBool(%d): %t, expected %t", tt.v, got, tt.want)
+		}
+	}
+}
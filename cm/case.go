@@ -0,0 +1,72 @@
+package cm
+
+import "errors"
+
+// CaseUnmarshaler returns a function that can unmarshal text into
+// [variant] or [enum] case T. The index of each string in cases
+// is the discriminant value of the matching case.
+//
+// [enum]: https://component-model.bytecodealliance.org/design/wit.html#enums
+// [variant]: https://component-model.bytecodealliance.org/design/wit.html#variants
+func CaseUnmarshaler[T ~uint8 | ~uint16 | ~uint32](cases []string) func(v *T, text []byte) error {
+	if len(cases) <= linearScanThreshold {
+		return func(v *T, text []byte) error {
+			if len(text) == 0 {
+				return errEmpty
+			}
+			s := string(text)
+			for i := 0; i < len(cases); i++ {
+				if cases[i] == s {
+					*v = T(i)
+					return nil
+				}
+			}
+			return errNoMatchingCase
+		}
+	}
+
+	m := make(map[string]T, len(cases))
+	for i, v := range cases {
+		m[v] = T(i)
+	}
+
+	return func(v *T, text []byte) error {
+		if len(text) == 0 {
+			return errEmpty
+		}
+		c, ok := m[string(text)]
+		if !ok {
+			return errNoMatchingCase
+		}
+		*v = c
+		return nil
+	}
+}
+
+// CaseString returns the name of case c in cases, or an empty string
+// if c is out of range.
+func CaseString[T ~uint8 | ~uint16 | ~uint32](cases []string, c T) string {
+	if int(c) >= len(cases) {
+		return ""
+	}
+	return cases[c]
+}
+
+// CaseMarshalText returns the name of case c in cases as text,
+// or an error if c is out of range.
+func CaseMarshalText[T ~uint8 | ~uint16 | ~uint32](cases []string, c T) ([]byte, error) {
+	if int(c) >= len(cases) {
+		return nil, errCaseOutOfRange
+	}
+	return []byte(cases[c]), nil
+}
+
+// linearScanThreshold is the maximum number of cases that
+// will be scanned linearly before falling back to a map lookup.
+const linearScanThreshold = 16
+
+var (
+	errEmpty          = errors.New("empty text")
+	errNoMatchingCase = errors.New("no matching case")
+	errCaseOutOfRange = errors.New("case out of range")
+)
@@ -0,0 +1,62 @@
+package cm
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCaseUnmarshaler(t *testing.T) {
+	tests := []struct {
+		name  string
+		cases []string
+	}{
+		{"nil", nil},
+		{"empty slice", []string{}},
+		{"a b c", []string{"a", "b", "c"}},
+		{"a b c d e f g", []string{"a", "b", "c", "d", "e", "f", "g"}},
+		{"many", func() []string {
+			cases := make([]string, 2*linearScanThreshold)
+			for i := range cases {
+				cases[i] = "case-" + strconv.Itoa(i)
+			}
+			return cases
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := CaseUnmarshaler[uint8](tt.cases)
+			for want, c := range tt.cases {
+				var got uint8
+				err := f(&got, []byte(c))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if got != uint8(want) {
+					t.Errorf("f(%q): got %d, expected %d", c, got, want)
+				}
+				if s := CaseString(tt.cases, got); s != c {
+					t.Errorf("CaseString(%d): got %q, expected %q", got, s, c)
+				}
+				if text, err := CaseMarshalText(tt.cases, got); err != nil || string(text) != c {
+					t.Errorf("CaseMarshalText(%d): got %q, %v, expected %q, nil", got, text, err, c)
+				}
+			}
+
+			var v uint8
+			if err := f(&v, nil); err != errEmpty {
+				t.Errorf("f(nil): got %v, expected %v", err, errEmpty)
+			}
+			if err := f(&v, []byte("not-a-case")); err != errNoMatchingCase {
+				t.Errorf("f(%q): got %v, expected %v", "not-a-case", err, errNoMatchingCase)
+			}
+			if s := CaseString(tt.cases, uint8(len(tt.cases))); s != "" {
+				t.Errorf("CaseString(%d): got %q, expected %q", len(tt.cases), s, "")
+			}
+			if text, err := CaseMarshalText(tt.cases, uint8(len(tt.cases))); err != errCaseOutOfRange {
+				t.Errorf("CaseMarshalText(%d): got %q, %v, expected %v", len(tt.cases), text, err, errCaseOutOfRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cm
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// Char converts v into a rune if it is a valid Component Model char: a [Unicode scalar value],
+// at most U+10FFFF and not a surrogate (U+D800 through U+DFFF). Otherwise it returns
+// [utf8.RuneError] and false.
+//
+// [Unicode scalar value]: https://unicode.org/glossary/#unicode_scalar_value
+func Char(v uint32) (rune, bool) {
+	if !utf8.ValidRune(rune(v)) {
+		return utf8.RuneError, false
+	}
+	return rune(v), true
+}
+
+// LowerChar returns r for lowering a char into Core WebAssembly. It panics if r is not
+// a Unicode scalar value, which the [Canonical ABI] traps on when the char is lifted.
+// Generated bindings call LowerChar on each char param of an imported function and
+// each char result of an exported function.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flat-lifting
+func LowerChar[R ~int32](r R) R {
+	if !utf8.ValidRune(rune(r)) {
+		panic("cm: invalid char 0x" + strconv.FormatUint(uint64(uint32(r)), 16) + ": not a Unicode scalar value")
+	}
+	return r
+}
@@ -0,0 +1,47 @@
+package cm
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChar(t *testing.T) {
+	tests := []struct {
+		v    uint32
+		want rune
+		ok   bool
+	}{
+		{0, 0, true},
+		{'a', 'a', true},
+		{0xd7ff, 0xd7ff, true},
+		{0xd800, utf8.RuneError, false},
+		{0xdfff, utf8.RuneError, false},
+		{0xe000, 0xe000, true},
+		{0x10ffff, 0x10ffff, true},
+		{0x110000, utf8.RuneError, false},
+		{0xffffffff, utf8.RuneError, false},
+	}
+	for _, tt := range tests {
+		got, ok := Char(tt.v)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Char(%#x): %#x, %t, expected %#x, %t", tt.v, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestLowerChar(t *testing.T) {
+	type letter rune
+	if got := LowerChar(letter('x')); got != 'x' {
+		t.Errorf("LowerChar('x'): %q, expected %q", got, 'x')
+	}
+	for _, r := range []rune{0xd800, 0x110000, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("LowerChar(%#x): expected panic", r)
+				}
+			}()
+			LowerChar(r)
+		}()
+	}
+}
@@ -0,0 +1,193 @@
+package cmtest
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// LowerString copies s into memory allocated by [Memory.Realloc] with alignment 1,
+// and returns its pointer and length, the lowered representation of a WIT string.
+func (m *Memory) LowerString(s string) (ptr, n uint32) {
+	ptr = m.Alloc(uint32(len(s)), 1)
+	m.WriteString(ptr, s)
+	return ptr, uint32(len(s))
+}
+
+// LiftString returns a copy of the string of n bytes at ptr, and false if out of range.
+func (m *Memory) LiftString(ptr, n uint32) (string, bool) {
+	b, ok := m.Read(ptr, n)
+	return string(b), ok
+}
+
+// LowerStrings lowers each string in s with [Memory.LowerString], and returns the
+// pointer and length of an array of their pointer and length pairs,
+// the lowered representation of a WIT list<string>.
+func (m *Memory) LowerStrings(s []string) (ptr, n uint32) {
+	ptr = m.Alloc(uint32(len(s))*8, 4)
+	for i, v := range s {
+		p, n := m.LowerString(v)
+		m.WriteUint32Le(ptr+uint32(i)*8, p)
+		m.WriteUint32Le(ptr+uint32(i)*8+4, n)
+	}
+	return ptr, uint32(len(s))
+}
+
+// LiftStrings returns the n strings of a lowered WIT list<string> at ptr,
+// and false if any part of the list is out of range.
+func (m *Memory) LiftStrings(ptr, n uint32) ([]string, bool) {
+	s := make([]string, n)
+	for i := range s {
+		p, ok1 := m.ReadUint32Le(ptr + uint32(i)*8)
+		n, ok2 := m.ReadUint32Le(ptr + uint32(i)*8 + 4)
+		var ok3 bool
+		s[i], ok3 = m.LiftString(p, n)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, false
+		}
+	}
+	return s, true
+}
+
+// LowerList copies the elements of s into memory allocated by [Memory.Realloc]
+// with the alignment of T, and returns the pointer and length of the lowered list.
+// The Go memory layout of T must match its Canonical ABI layout, which is true
+// of fixed-size Component Model types that do not contain pointers, such as integers,
+// floats, and records, options, results, and variants of them.
+// It panics if T contains pointers, strings, or lists, which are larger on the host.
+func LowerList[T any](m *Memory, s []T) (ptr, n uint32) {
+	checkFlat(reflect.TypeFor[T]())
+	size := uint32(unsafe.Sizeof(*new(T))) * uint32(len(s))
+	ptr = m.Alloc(size, uint32(unsafe.Alignof(*new(T))))
+	if size > 0 {
+		m.Write(ptr, unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), size))
+	}
+	return ptr, uint32(len(s))
+}
+
+// LiftList returns a copy of the n elements of type T of a lowered list at ptr,
+// and false if out of range. T is subject to the same restrictions as [LowerList].
+func LiftList[T any](m *Memory, ptr, n uint32) ([]T, bool) {
+	checkFlat(reflect.TypeFor[T]())
+	s := make([]T, n)
+	size := uint32(unsafe.Sizeof(*new(T))) * n
+	b, ok := m.Read(ptr, size)
+	if !ok {
+		return nil, false
+	}
+	if size > 0 {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), size), b)
+	}
+	return s, true
+}
+
+// checkFlat panics if values of type t contain pointers or platform-dependent sizes.
+func checkFlat(t reflect.Type) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	case reflect.Array:
+		checkFlat(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			checkFlat(t.Field(i).Type)
+		}
+	default:
+		panic("cmtest: type " + t.String() + " does not have a Canonical ABI memory layout on the host")
+	}
+}
+
+// VariantLayout describes the Canonical ABI memory layout of a variant, including
+// results, options, and enums. Its zero value is invalid.
+type VariantLayout struct {
+	// Cases is the number of cases, which determines the size of the discriminant.
+	Cases int
+
+	// Size and Align are the size and alignment of the largest and most strictly
+	// aligned case payloads, or 0 if no case has a payload.
+	Size, Align uint32
+}
+
+// DiscSize returns the size of the discriminant of the variant: 1 byte for up to
+// 256 cases, 2 bytes for up to 65,536 cases, otherwise 4 bytes.
+func (l VariantLayout) DiscSize() uint32 {
+	switch {
+	case l.Cases <= 1<<8:
+		return 1
+	case l.Cases <= 1<<16:
+		return 2
+	default:
+		return 4
+	}
+}
+
+// PayloadOffset returns the offset of the case payload from the start of the variant.
+func (l VariantLayout) PayloadOffset() uint32 {
+	return alignTo(l.DiscSize(), max(l.Align, 1))
+}
+
+// VariantAlign returns the alignment of the variant.
+func (l VariantLayout) VariantAlign() uint32 {
+	return max(l.DiscSize(), l.Align)
+}
+
+// VariantSize returns the size of the variant, including padding.
+func (l VariantLayout) VariantSize() uint32 {
+	return alignTo(l.PayloadOffset()+l.Size, l.VariantAlign())
+}
+
+// LowerVariant allocates a variant with layout l using [Memory.Realloc], stores tag
+// and the lowered case payload, and returns the pointer to the variant.
+// It panics if tag is not a valid case or payload is larger than l.Size.
+func (m *Memory) LowerVariant(l VariantLayout, tag uint32, payload []byte) uint32 {
+	if int64(tag) >= int64(l.Cases) {
+		panic("cmtest: invalid variant tag")
+	}
+	if uint32(len(payload)) > l.Size {
+		panic("cmtest: variant payload larger than layout")
+	}
+	ptr := m.Alloc(l.VariantSize(), l.VariantAlign())
+	m.StoreVariant(l, ptr, tag, payload)
+	return ptr
+}
+
+// StoreVariant stores tag and the lowered case payload of a variant with layout l at ptr,
+// for example within a record or list. It returns false if out of range.
+func (m *Memory) StoreVariant(l VariantLayout, ptr, tag uint32, payload []byte) bool {
+	var ok bool
+	switch l.DiscSize() {
+	case 1:
+		ok = m.WriteUint8(ptr, uint8(tag))
+	case 2:
+		ok = m.WriteUint16Le(ptr, uint16(tag))
+	default:
+		ok = m.WriteUint32Le(ptr, tag)
+	}
+	return ok && m.Write(ptr+l.PayloadOffset(), payload)
+}
+
+// LiftVariant returns the tag and a view of the l.Size bytes of case payload of the
+// variant with layout l at ptr. It returns false if out of range or if the tag is invalid.
+func (m *Memory) LiftVariant(l VariantLayout, ptr uint32) (tag uint32, payload []byte, ok bool) {
+	switch l.DiscSize() {
+	case 1:
+		var t uint8
+		t, ok = m.ReadUint8(ptr)
+		tag = uint32(t)
+	case 2:
+		var t uint16
+		t, ok = m.ReadUint16Le(ptr)
+		tag = uint32(t)
+	default:
+		tag, ok = m.ReadUint32Le(ptr)
+	}
+	if !ok || int64(tag) >= int64(l.Cases) {
+		return 0, nil, false
+	}
+	payload, ok = m.Read(ptr+l.PayloadOffset(), l.Size)
+	return tag, payload, ok
+}
+
+func alignTo(ptr, align uint32) uint32 {
+	return (ptr + align - 1) &^ (align - 1)
+}
@@ -0,0 +1,177 @@
+// Package cmtest contains helpers for testing Component Model bindings on the host,
+// without a WebAssembly runtime. [Memory] simulates the 32-bit linear memory of a
+// component, with a cabi_realloc function that records each call, and methods that
+// lower and lift strings, lists, and variants with the [Canonical ABI] memory layout.
+//
+// The Read and Write methods of [Memory] have the same signatures as the equivalent
+// methods of [api.Memory] in wazero, so code that loads or stores values in guest memory
+// can be tested against either. ReadUint8 and WriteUint8 are ReadByte and WriteByte in wazero.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+// [api.Memory]: https://pkg.go.dev/github.com/tetratelabs/wazero/api#Memory
+package cmtest
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// PageSize is the size of a WebAssembly memory page, the unit by which [Memory] grows.
+const PageSize = 65536
+
+// Memory is a simulated WebAssembly linear memory with a bump allocator.
+// The zero value is an empty memory, which grows as memory is allocated.
+// Allocations start at a non-zero address, so 0 is never a valid allocation.
+type Memory struct {
+	// Data is the contents of memory, addressed by 32-bit offsets.
+	Data []byte
+
+	// Calls records each call to [Memory.Realloc], in order.
+	Calls []ReallocCall
+
+	next uint32
+}
+
+// ReallocCall is a recorded call to [Memory.Realloc].
+type ReallocCall struct {
+	Ptr, Size, Align, NewSize uint32 // Arguments
+	Result                    uint32 // Returned pointer
+}
+
+// NewMemory returns a [Memory] with pages pages of zeroed memory.
+func NewMemory(pages int) *Memory {
+	return &Memory{Data: make([]byte, pages*PageSize)}
+}
+
+// Reset zeroes m, frees all allocations, and clears recorded calls,
+// retaining the size of m.
+func (m *Memory) Reset() {
+	clear(m.Data)
+	m.Calls = nil
+	m.next = 0
+}
+
+// Size returns the size of m in bytes.
+func (m *Memory) Size() uint32 {
+	return uint32(len(m.Data))
+}
+
+// Realloc implements the Canonical ABI cabi_realloc function. If ptr and size are 0,
+// it allocates newsize bytes aligned to align, otherwise it allocates a new block and
+// copies up to newsize bytes of the block at ptr. Memory is never freed.
+// Each call is appended to m.Calls. It panics if align is not a power of 2,
+// or if the block at ptr is out of range.
+func (m *Memory) Realloc(ptr, size, align, newsize uint32) uint32 {
+	if align == 0 || align&(align-1) != 0 {
+		panic("cmtest: alignment is not a power of 2")
+	}
+	p := m.alloc(newsize, align)
+	if size > 0 {
+		b, ok := m.Read(ptr, size)
+		if !ok {
+			panic("cmtest: realloc out of range")
+		}
+		copy(m.Data[p:p+newsize], b)
+	}
+	m.Calls = append(m.Calls, ReallocCall{Ptr: ptr, Size: size, Align: align, NewSize: newsize, Result: p})
+	return p
+}
+
+// Alloc allocates size bytes aligned to align by calling [Memory.Realloc].
+func (m *Memory) Alloc(size, align uint32) uint32 {
+	return m.Realloc(0, 0, align, size)
+}
+
+func (m *Memory) alloc(size, align uint32) uint32 {
+	p := max(m.next, align)
+	p = (p + align - 1) &^ (align - 1)
+	end := uint64(p) + uint64(size)
+	if end > math.MaxUint32 {
+		panic("cmtest: out of memory")
+	}
+	if end > uint64(len(m.Data)) {
+		pages := (end - uint64(len(m.Data)) + PageSize - 1) / PageSize
+		m.Data = append(m.Data, make([]byte, pages*PageSize)...)
+	}
+	m.next = uint32(end)
+	return p
+}
+
+// Read returns a view of n bytes of m at offset, and false if out of range.
+// Changes to the returned slice are visible in m.
+func (m *Memory) Read(offset, n uint32) ([]byte, bool) {
+	if uint64(offset)+uint64(n) > uint64(len(m.Data)) {
+		return nil, false
+	}
+	return m.Data[offset : offset+n : offset+n], true
+}
+
+// Write copies b into m at offset, and returns false if out of range.
+func (m *Memory) Write(offset uint32, b []byte) bool {
+	dst, ok := m.Read(offset, uint32(len(b)))
+	if ok {
+		copy(dst, b)
+	}
+	return ok
+}
+
+// ReadUint8 returns the byte at offset, and false if out of range.
+func (m *Memory) ReadUint8(offset uint32) (byte, bool) {
+	b, ok := m.Read(offset, 1)
+	if !ok {
+		return 0, false
+	}
+	return b[0], true
+}
+
+// ReadUint16Le returns the little-endian uint16 at offset, and false if out of range.
+func (m *Memory) ReadUint16Le(offset uint32) (uint16, bool) {
+	b, ok := m.Read(offset, 2)
+	if !ok {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint16(b), true
+}
+
+// ReadUint32Le returns the little-endian uint32 at offset, and false if out of range.
+func (m *Memory) ReadUint32Le(offset uint32) (uint32, bool) {
+	b, ok := m.Read(offset, 4)
+	if !ok {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(b), true
+}
+
+// ReadUint64Le returns the little-endian uint64 at offset, and false if out of range.
+func (m *Memory) ReadUint64Le(offset uint32) (uint64, bool) {
+	b, ok := m.Read(offset, 8)
+	if !ok {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(b), true
+}
+
+// WriteUint8 writes v at offset, and returns false if out of range.
+func (m *Memory) WriteUint8(offset uint32, v byte) bool {
+	return m.Write(offset, []byte{v})
+}
+
+// WriteUint16Le writes v in little-endian order at offset, and returns false if out of range.
+func (m *Memory) WriteUint16Le(offset uint32, v uint16) bool {
+	return m.Write(offset, binary.LittleEndian.AppendUint16(nil, v))
+}
+
+// WriteUint32Le writes v in little-endian order at offset, and returns false if out of range.
+func (m *Memory) WriteUint32Le(offset uint32, v uint32) bool {
+	return m.Write(offset, binary.LittleEndian.AppendUint32(nil, v))
+}
+
+// WriteUint64Le writes v in little-endian order at offset, and returns false if out of range.
+func (m *Memory) WriteUint64Le(offset uint32, v uint64) bool {
+	return m.Write(offset, binary.LittleEndian.AppendUint64(nil, v))
+}
+
+// WriteString copies s into m at offset, and returns false if out of range.
+func (m *Memory) WriteString(offset uint32, s string) bool {
+	return m.Write(offset, []byte(s))
+}
@@ -0,0 +1,153 @@
+package cmtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+func TestRealloc(t *testing.T) {
+	var m Memory
+	p1 := m.Alloc(3, 1)
+	if p1 == 0 {
+		t.Errorf("Alloc(3, 1): 0, expected non-zero pointer")
+	}
+	m.WriteString(p1, "abc")
+	p2 := m.Realloc(p1, 3, 8, 16)
+	if p2%8 != 0 {
+		t.Errorf("Realloc(%d, 3, 8, 16): %d, expected 8-byte alignment", p1, p2)
+	}
+	if s, _ := m.LiftString(p2, 3); s != "abc" {
+		t.Errorf("Realloc(%d, 3, 8, 16): copied %q, expected %q", p1, s, "abc")
+	}
+	want := []ReallocCall{
+		{Ptr: 0, Size: 0, Align: 1, NewSize: 3, Result: p1},
+		{Ptr: p1, Size: 3, Align: 8, NewSize: 16, Result: p2},
+	}
+	if !reflect.DeepEqual(m.Calls, want) {
+		t.Errorf("Calls: %v, expected %v", m.Calls, want)
+	}
+	if m.Size() != PageSize {
+		t.Errorf("Size(): %d, expected %d", m.Size(), PageSize)
+	}
+
+	// Memory grows by pages.
+	m.Alloc(PageSize, 4)
+	if m.Size() != 2*PageSize {
+		t.Errorf("Size(): %d, expected %d", m.Size(), 2*PageSize)
+	}
+
+	m.Reset()
+	if len(m.Calls) != 0 || m.Alloc(1, 1) != p1 {
+		t.Errorf("Reset did not reset allocations")
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	m := NewMemory(1)
+	if !m.WriteUint32Le(4, 0x01020304) {
+		t.Fatal("WriteUint32Le: false")
+	}
+	if b, _ := m.Read(4, 4); !reflect.DeepEqual(b, []byte{4, 3, 2, 1}) {
+		t.Errorf("Read(4, 4): %v, expected little-endian bytes", b)
+	}
+	if v, _ := m.ReadUint16Le(6); v != 0x0102 {
+		t.Errorf("ReadUint16Le(6): %#x, expected 0x0102", v)
+	}
+	m.WriteUint64Le(8, 1<<40)
+	if v, _ := m.ReadUint64Le(8); v != 1<<40 {
+		t.Errorf("ReadUint64Le(8): %d, expected %d", v, uint64(1<<40))
+	}
+	if _, ok := m.ReadUint32Le(PageSize - 2); ok {
+		t.Errorf("ReadUint32Le(%d): ok, expected out of range", PageSize-2)
+	}
+	if m.WriteUint8(PageSize, 1) {
+		t.Errorf("WriteUint8(%d): ok, expected out of range", PageSize)
+	}
+}
+
+func TestLowerStrings(t *testing.T) {
+	var m Memory
+	want := []string{"hello", "", "world"}
+	ptr, n := m.LowerStrings(want)
+	if n != 3 {
+		t.Errorf("LowerStrings: length %d, expected 3", n)
+	}
+	got, ok := m.LiftStrings(ptr, n)
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("LiftStrings: %q, %t, expected %q", got, ok, want)
+	}
+	if _, ok := m.LiftStrings(m.Size()-4, 1); ok {
+		t.Errorf("LiftStrings: ok, expected out of range")
+	}
+}
+
+func TestLowerList(t *testing.T) {
+	var m Memory
+	want := []cm.Option[uint32]{cm.Some[uint32](7), cm.None[uint32]()}
+	ptr, n := LowerList(&m, want)
+	if ptr%4 != 0 {
+		t.Errorf("LowerList: pointer %d, expected 4-byte alignment", ptr)
+	}
+
+	// option<u32> is a 1-byte discriminant and a u32 at offset 4.
+	l := VariantLayout{Cases: 2, Size: 4, Align: 4}
+	if size := l.VariantSize(); size != 8 {
+		t.Errorf("VariantSize(): %d, expected 8", size)
+	}
+	tag, payload, ok := m.LiftVariant(l, ptr)
+	if !ok || tag != 1 || !reflect.DeepEqual(payload, []byte{7, 0, 0, 0}) {
+		t.Errorf("LiftVariant: %d, %v, %t, expected 1, [7 0 0 0], true", tag, payload, ok)
+	}
+
+	got, ok := LiftList[cm.Option[uint32]](&m, ptr, n)
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("LiftList: %v, %t, expected %v", got, ok, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("LowerList([]string): expected panic")
+		}
+	}()
+	LowerList(&m, []string{"a"})
+}
+
+func TestVariantLayout(t *testing.T) {
+	tests := []struct {
+		l                              VariantLayout
+		disc, offset, align, totalSize uint32
+	}{
+		{VariantLayout{Cases: 3}, 1, 1, 1, 1},
+		{VariantLayout{Cases: 2, Size: 8, Align: 8}, 1, 8, 8, 16},
+		{VariantLayout{Cases: 300, Size: 1, Align: 1}, 2, 2, 2, 4},
+		{VariantLayout{Cases: 1 << 17, Size: 2, Align: 2}, 4, 4, 4, 8},
+	}
+	for _, tt := range tests {
+		if got := tt.l.DiscSize(); got != tt.disc {
+			t.Errorf("%+v DiscSize(): %d, expected %d", tt.l, got, tt.disc)
+		}
+		if got := tt.l.PayloadOffset(); got != tt.offset {
+			t.Errorf("%+v PayloadOffset(): %d, expected %d", tt.l, got, tt.offset)
+		}
+		if got := tt.l.VariantAlign(); got != tt.align {
+			t.Errorf("%+v VariantAlign(): %d, expected %d", tt.l, got, tt.align)
+		}
+		if got := tt.l.VariantSize(); got != tt.totalSize {
+			t.Errorf("%+v VariantSize(): %d, expected %d", tt.l, got, tt.totalSize)
+		}
+	}
+
+	var m Memory
+	l := VariantLayout{Cases: 300, Size: 4, Align: 4}
+	ptr := m.LowerVariant(l, 257, []byte{1, 2})
+	tag, payload, ok := m.LiftVariant(l, ptr)
+	if !ok || tag != 257 || !reflect.DeepEqual(payload, []byte{1, 2, 0, 0}) {
+		t.Errorf("LiftVariant: %d, %v, %t, expected 257, [1 2 0 0], true", tag, payload, ok)
+	}
+	m.WriteUint16Le(ptr, 300)
+	if _, _, ok := m.LiftVariant(l, ptr); ok {
+		t.Errorf("LiftVariant: ok, expected invalid tag")
+	}
+}
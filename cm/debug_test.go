@@ -1,33 +1,11 @@
 package cm
 
 import (
-	"reflect"
-	"strings"
 	"unsafe"
 
 	"github.com/ydnar/wasm-tools-go/internal/tinyunsafe"
 )
 
-func typeName(v any) string {
-	var name string
-	if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr {
-		name = "*" + t.Elem().String()
-	} else {
-		name = t.String()
-	}
-	return strings.ReplaceAll(name, " ", "")
-}
-
-func sizePlusAlignOf[T any]() uintptr {
-	var v T
-	return unsafe.Sizeof(v) + unsafe.Alignof(v)
-}
-
-func alignOf[T any]() uintptr {
-	var v T
-	return unsafe.Alignof(v)
-}
-
 func zeroPtr[T any]() *T {
 	var zero T
 	return &zero
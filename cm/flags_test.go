@@ -1,9 +1,8 @@
-//go:build cmflagsexperiment
-
 package cm
 
 import (
 	"testing"
+	"unsafe"
 )
 
 func TestFlags(t *testing.T) {
@@ -48,4 +47,33 @@ func TestFlags(t *testing.T) {
 		t.Errorf("expected bit %d to not be set", F0)
 	}
 	// fmt.Printf("flags2: %b\n", flags2.data)
+
+	flags2.Clear(F95)
+	if flags2.Is(F95) {
+		t.Errorf("expected bit %d to be cleared", F95)
+	}
+	if !flags2.Is(F1) {
+		t.Errorf("expected bit %d to be set", F1)
+	}
+}
+
+func TestFlagsLayout(t *testing.T) {
+	type MyFlag Flag
+	var flags33 struct {
+		Flags[[2]uint32, MyFlag]
+	}
+	if got, want := unsafe.Sizeof(flags33), uintptr(8); got != want {
+		t.Errorf("unsafe.Sizeof(flags33): %d, expected %d", got, want)
+	}
+	if got, want := unsafe.Alignof(flags33), uintptr(4); got != want {
+		t.Errorf("unsafe.Alignof(flags33): %d, expected %d", got, want)
+	}
+
+	// On little-endian architectures, including WebAssembly,
+	// bit i is stored in bit i%32 of word i/32.
+	flags33.Set(32)
+	flags33.Set(3)
+	if got, want := flags33.data, [2]uint32{1 << 3, 1}; got != want {
+		t.Errorf("flags33.data: %#x, expected %#x", got, want)
+	}
 }
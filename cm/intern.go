@@ -0,0 +1,63 @@
+package cm
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaxInternLen is the maximum length in bytes of a string interned by [Intern] or [InternBytes].
+// Longer strings are rarely repeated, and are returned without interning.
+const MaxInternLen = 64
+
+// maxInterned is the maximum number of strings in the intern table.
+// The table is cleared when full, so memory is bounded even if strings are not repeated.
+const maxInterned = 4096
+
+var interned struct {
+	sync.Mutex
+	m map[string]string
+}
+
+// Intern returns a string equal to s. If an equal string was previously interned,
+// it is returned instead of s, otherwise a copy of s is interned and returned.
+// Because a copy is interned, s may refer to memory that is later reused,
+// such as a string lifted without copying from linear memory.
+//
+// Interning reduces allocations and memory when the same strings are lifted repeatedly,
+// such as HTTP header names or enum case names. Strings longer than [MaxInternLen]
+// are returned without interning. Intern is safe for concurrent use.
+func Intern(s string) string {
+	if len(s) == 0 || len(s) > MaxInternLen {
+		return s
+	}
+	interned.Lock()
+	defer interned.Unlock()
+	if v, ok := interned.m[s]; ok {
+		return v
+	}
+	return intern(strings.Clone(s))
+}
+
+// InternBytes returns a string equal to string(b), as with [Intern].
+// It does not allocate if an equal string was previously interned.
+// If len(b) > [MaxInternLen], it returns string(b).
+func InternBytes(b []byte) string {
+	if len(b) == 0 || len(b) > MaxInternLen {
+		return string(b)
+	}
+	interned.Lock()
+	defer interned.Unlock()
+	if v, ok := interned.m[string(b)]; ok {
+		return v
+	}
+	return intern(string(b))
+}
+
+// intern adds s to the intern table. The caller must hold the lock.
+func intern(s string) string {
+	if interned.m == nil || len(interned.m) >= maxInterned {
+		interned.m = make(map[string]string)
+	}
+	interned.m[s] = s
+	return s
+}
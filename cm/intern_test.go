@@ -0,0 +1,78 @@
+package cm
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestIntern(t *testing.T) {
+	b := []byte("content-type")
+	s1 := InternBytes(b)
+	s2 := Intern(string(b))
+	if s1 != "content-type" {
+		t.Errorf("InternBytes(%q): %q", b, s1)
+	}
+	if unsafe.StringData(s1) != unsafe.StringData(s2) {
+		t.Errorf("Intern(%q) did not return the interned string", s2)
+	}
+
+	// Interned strings do not alias their input.
+	b[0] = 'C'
+	if s1 != "content-type" {
+		t.Errorf("InternBytes: interned string aliases input: %q", s1)
+	}
+
+	long := strings.Repeat("x", MaxInternLen+1)
+	if s := Intern(long); unsafe.StringData(s) != unsafe.StringData(long) {
+		t.Errorf("Intern: string longer than MaxInternLen was interned")
+	}
+	if s := Intern(""); s != "" {
+		t.Errorf("Intern(\"\"): %q", s)
+	}
+}
+
+func TestInternBounded(t *testing.T) {
+	for i := 0; i < maxInterned*2; i++ {
+		Intern(string(rune(i + 0x100)))
+	}
+	interned.Lock()
+	n := len(interned.m)
+	interned.Unlock()
+	if n > maxInterned {
+		t.Errorf("intern table has %d strings, expected at most %d", n, maxInterned)
+	}
+}
+
+// headerNames are lifted repeatedly by wasi-http style workloads.
+var headerNames = func() [][]byte {
+	var names [][]byte
+	for _, s := range []string{
+		"accept", "accept-encoding", "authorization", "cache-control", "connection",
+		"content-length", "content-type", "cookie", "host", "user-agent",
+	} {
+		names = append(names, []byte(s))
+	}
+	return names
+}()
+
+var sink string
+
+func BenchmarkLiftHeaderNames(b *testing.B) {
+	b.Run("string", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, name := range headerNames {
+				sink = string(name)
+			}
+		}
+	})
+	b.Run("InternBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, name := range headerNames {
+				sink = InternBytes(name)
+			}
+		}
+	})
+}
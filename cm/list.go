@@ -21,7 +21,9 @@ func NewList[T any](data *T, len uint) List[T] {
 
 // ToList returns a List[T] equivalent to the Go slice s.
 // The underlying slice data is not copied, and the resulting List points at the
-// same array storage as the slice.
+// same array storage as the slice. The caller must keep s alive and unmodified
+// for as long as the List is in use, for example until an imported function returns.
+// Use [CopyList] to create a List that does not share memory with s.
 func ToList[S ~[]T, T any](s S) List[T] {
 	return List[T]{
 		data: unsafe.SliceData([]T(s)),
@@ -29,6 +31,80 @@ func ToList[S ~[]T, T any](s S) List[T] {
 	}
 }
 
+// ToListString returns a List[uint8] equivalent to the Go string s.
+// The underlying string data is not copied, and the resulting List points at the
+// same storage as the string. Because Go strings are immutable, the List must not be
+// modified, for example by passing it to an exported function that writes to it.
+// Use [CopyListString] to create a List that does not share memory with s.
+// [UnsafeListString] is the inverse.
+func ToListString(s string) List[uint8] {
+	return List[uint8]{
+		data: unsafe.StringData(s),
+		len:  uint(len(s)),
+	}
+}
+
+// CopyList returns a List[T] with a copy of the elements of the Go slice s.
+// The resulting List owns its storage, which is safe to retain or modify
+// independently of s, at the cost of an allocation.
+func CopyList[S ~[]T, T any](s S) List[T] {
+	if len(s) == 0 {
+		return List[T]{}
+	}
+	return ToList(append([]T(nil), s...))
+}
+
+// CopyListString returns a List[uint8] with a copy of the bytes of the Go string s.
+// The resulting List owns its storage, which is safe to retain or modify.
+// [ListString] is the inverse.
+func CopyListString(s string) List[uint8] {
+	if len(s) == 0 {
+		return List[uint8]{}
+	}
+	return ToList([]uint8(s))
+}
+
+// ListString returns a Go string with a copy of the bytes in list, such as a list<u8>
+// returned by an imported function. The string does not share memory with list, so it is
+// safe to retain after the list is freed or its memory reused, e.g. by a later call,
+// at the cost of an allocation. Go does not permit a String method on only List[uint8].
+func ListString(list List[uint8]) string {
+	if list.len == 0 {
+		return ""
+	}
+	return string(list.Slice())
+}
+
+// UnsafeListString returns a Go string that refers to the bytes in list without copying them.
+// The string is valid only while the memory of list is alive and unmodified: it must not be
+// retained after list is freed, reused by a later call, or written to, because Go assumes
+// strings are immutable. Use [ListString] unless a copy is too expensive, e.g. to look up
+// a map key or parse a number. The Unsafe prefix is intended to draw attention in review.
+func UnsafeListString(list List[uint8]) string {
+	if list.len == 0 {
+		return ""
+	}
+	return unsafe.String(list.data, list.len)
+}
+
+// LowerList returns a List[T] with the result of calling lower on each element of the Go slice s.
+// It lowers lists whose elements require element-wise lowering, such as a []string to a
+// List[List[uint8]] with [ToListString], a [][]E to a List[List[E]], or a slice of Go values
+// to a List of variants. The elements are stored in a single allocation of len(s) elements,
+// as the Go size and alignment of a Component Model type T match its Canonical ABI element
+// size and alignment. The resulting List owns its storage, but elements returned by lower
+// may share memory with s, e.g. with [ToListString]; use [CopyListString] to copy them.
+func LowerList[S ~[]E, E any, T any](s S, lower func(E) T) List[T] {
+	if len(s) == 0 {
+		return List[T]{}
+	}
+	elems := make([]T, len(s))
+	for i := range s {
+		elems[i] = lower(s[i])
+	}
+	return ToList(elems)
+}
+
 // Data returns the data pointer for the list.
 func (list List[T]) Data() *T {
 	return list.data
@@ -0,0 +1,129 @@
+package cm
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestToList(t *testing.T) {
+	s := []int32{1, 2, 3}
+	l := ToList(s)
+	if got, want := l.Len(), uint(len(s)); got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if l.Data() != unsafe.SliceData(s) {
+		t.Errorf("Data(): %p, expected %p (shared storage)", l.Data(), unsafe.SliceData(s))
+	}
+	if !slices.Equal(l.Slice(), s) {
+		t.Errorf("Slice(): %v, expected %v", l.Slice(), s)
+	}
+}
+
+func TestToListString(t *testing.T) {
+	s := "hello"
+	l := ToListString(s)
+	if got, want := l.Len(), uint(len(s)); got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if l.Data() != unsafe.StringData(s) {
+		t.Errorf("Data(): %p, expected %p (shared storage)", l.Data(), unsafe.StringData(s))
+	}
+	if got := string(l.Slice()); got != s {
+		t.Errorf("Slice(): %q, expected %q", got, s)
+	}
+}
+
+func TestCopyList(t *testing.T) {
+	s := []int32{1, 2, 3}
+	l := CopyList(s)
+	if !slices.Equal(l.Slice(), s) {
+		t.Errorf("Slice(): %v, expected %v", l.Slice(), s)
+	}
+	if l.Data() == unsafe.SliceData(s) {
+		t.Errorf("Data(): %p, expected a copy of %p", l.Data(), unsafe.SliceData(s))
+	}
+	s[0] = 99
+	if l.Slice()[0] != 1 {
+		t.Errorf("CopyList: modifying the source slice modified the List")
+	}
+
+	if l := CopyList([]int32(nil)); l.Len() != 0 || l.Data() != nil {
+		t.Errorf("CopyList(nil): got %v, expected zero List", l)
+	}
+}
+
+func TestCopyListString(t *testing.T) {
+	s := "hello"
+	l := CopyListString(s)
+	if got := string(l.Slice()); got != s {
+		t.Errorf("Slice(): %q, expected %q", got, s)
+	}
+	if l.Data() == unsafe.StringData(s) {
+		t.Errorf("Data(): %p, expected a copy of %p", l.Data(), unsafe.StringData(s))
+	}
+
+	if l := CopyListString(""); l.Len() != 0 || l.Data() != nil {
+		t.Errorf("CopyListString(\"\"): got %v, expected zero List", l)
+	}
+}
+
+func TestListString(t *testing.T) {
+	b := []byte("hello")
+	l := ToList(b)
+	s := ListString(l)
+	u := UnsafeListString(l)
+	if s != "hello" || u != "hello" {
+		t.Errorf("ListString: %q, UnsafeListString: %q, expected %q", s, u, "hello")
+	}
+	if unsafe.StringData(u) != unsafe.SliceData(b) {
+		t.Errorf("UnsafeListString: %p, expected %p (shared storage)", unsafe.StringData(u), unsafe.SliceData(b))
+	}
+
+	// Reusing the memory of the list changes only the unsafe string.
+	copy(b, "world")
+	if s != "hello" {
+		t.Errorf("ListString: %q after reuse, expected %q", s, "hello")
+	}
+	if u != "world" {
+		t.Errorf("UnsafeListString: %q after reuse, expected %q", u, "world")
+	}
+
+	if s, u := ListString(List[uint8]{}), UnsafeListString(List[uint8]{}); s != "" || u != "" {
+		t.Errorf("zero List: ListString %q, UnsafeListString %q, expected empty strings", s, u)
+	}
+}
+
+var listSink List[List[uint8]]
+
+func TestLowerList(t *testing.T) {
+	s := []string{"a", "bc", ""}
+	l := LowerList(s, ToListString)
+	if got, want := l.Len(), uint(len(s)); got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	for i, e := range l.Slice() {
+		if got := string(e.Slice()); got != s[i] {
+			t.Errorf("element %d: %q, expected %q", i, got, s[i])
+		}
+	}
+
+	opts := LowerList([]*int32{nil, new(int32)}, func(p *int32) Option[int32] {
+		if p == nil {
+			return None[int32]()
+		}
+		return Some(*p)
+	})
+	if o := opts.Slice(); !o[0].None() || o[1].Some() == nil {
+		t.Errorf("LowerList: %v, expected [none, some(0)]", o)
+	}
+
+	if l := LowerList([]string(nil), ToListString); l.Len() != 0 || l.Data() != nil {
+		t.Errorf("LowerList(nil): got %v, expected zero List", l)
+	}
+
+	// Elements are lowered into a single allocation.
+	if n := testing.AllocsPerRun(10, func() { listSink = LowerList(s, ToListString) }); n != 1 {
+		t.Errorf("LowerList: %v allocations, expected 1", n)
+	}
+}
@@ -0,0 +1,44 @@
+package cm
+
+import (
+	"cmp"
+	"slices"
+)
+
+// LowerMap returns a List of key-value tuples with the entries of map m, sorted by key.
+// WIT has no map type, so maps are represented as list<tuple<k, v>>.
+// Use [LowerMapFunc] for a custom order, or map iteration order.
+func LowerMap[M ~map[K]V, K cmp.Ordered, V any](m M) List[Tuple[K, V]] {
+	return LowerMapFunc(m, cmp.Compare[K])
+}
+
+// LowerMapFunc returns a List of key-value tuples with the entries of map m,
+// sorted by key using the comparison function compare, as in [slices.SortFunc].
+// If compare is nil, entries are in map iteration order, which is not deterministic.
+func LowerMapFunc[M ~map[K]V, K comparable, V any](m M, compare func(a, b K) int) List[Tuple[K, V]] {
+	if len(m) == 0 {
+		return List[Tuple[K, V]]{}
+	}
+	entries := make([]Tuple[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Tuple[K, V]{k, v})
+	}
+	if compare != nil {
+		slices.SortFunc(entries, func(a, b Tuple[K, V]) int {
+			return compare(a.F0, b.F0)
+		})
+	}
+	return ToList(entries)
+}
+
+// LiftMap returns a map with the key-value tuples in list.
+// If a key appears more than once, the last value wins.
+// Keys and values are copied shallowly: a List or string in list
+// may share memory with the result.
+func LiftMap[K comparable, V any](list List[Tuple[K, V]]) map[K]V {
+	m := make(map[K]V, list.Len())
+	for _, e := range list.Slice() {
+		m[e.F0] = e.F1
+	}
+	return m
+}
@@ -0,0 +1,50 @@
+package cm
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLowerMap(t *testing.T) {
+	m := map[string]string{"c": "3", "a": "1", "b": "2"}
+	got := LowerMap(m).Slice()
+	want := []Tuple[string, string]{{"a", "1"}, {"b", "2"}, {"c", "3"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("LowerMap: %v, expected %v", got, want)
+	}
+	if l := LowerMap(map[string]int(nil)); l.Len() != 0 {
+		t.Errorf("LowerMap(nil): Len() = %d, expected 0", l.Len())
+	}
+}
+
+func TestLowerMapFunc(t *testing.T) {
+	m := map[string]int{"a": 1, "bb": 2, "ccc": 3}
+	got := LowerMapFunc(m, func(a, b string) int { return len(b) - len(a) }).Slice()
+	want := []Tuple[string, int]{{"ccc", 3}, {"bb", 2}, {"a", 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("LowerMapFunc: %v, expected %v", got, want)
+	}
+
+	got = LowerMapFunc(m, nil).Slice()
+	slices.SortFunc(got, func(a, b Tuple[string, int]) int { return strings.Compare(a.F0, b.F0) })
+	want = []Tuple[string, int]{{"a", 1}, {"bb", 2}, {"ccc", 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("LowerMapFunc(nil): %v, expected %v", got, want)
+	}
+}
+
+func TestLiftMap(t *testing.T) {
+	l := ToList([]Tuple[string, uint32]{{"a", 1}, {"b", 2}, {"a", 3}})
+	got := LiftMap(l)
+	want := map[string]uint32{"a": 3, "b": 2}
+	if !maps.Equal(got, want) {
+		t.Errorf("LiftMap: %v, expected %v", got, want)
+	}
+
+	m := map[uint8]bool{1: true, 2: false}
+	if got := LiftMap(LowerMap(m)); !maps.Equal(got, m) {
+		t.Errorf("LiftMap(LowerMap(m)): %v, expected %v", got, m)
+	}
+}
@@ -0,0 +1,28 @@
+package cm
+
+// Once caches the value returned by the first call to [Once.Get].
+// Generated bindings use Once for imported functions that must only be called once,
+// or whose result does not change, such as wasi:random/insecure-seed.
+//
+// WebAssembly components are single-threaded, so Once does not use atomic operations
+// or locks. A Once must not be used concurrently, e.g. by goroutines on a native host.
+// The zero value is ready to use.
+type Once[T any] struct {
+	v    T
+	done bool
+}
+
+// Get returns the cached value of o. On the first call, Get calls f and caches its result.
+// If f panics, no value is cached, and the next call to Get calls f again.
+func (o *Once[T]) Get(f func() T) T {
+	if !o.done {
+		o.v = f()
+		o.done = true
+	}
+	return o.v
+}
+
+// Done reports whether o has cached a value.
+func (o *Once[T]) Done() bool {
+	return o.done
+}
@@ -0,0 +1,41 @@
+package cm
+
+import "testing"
+
+func TestOnce(t *testing.T) {
+	var o Once[Tuple[uint64, uint64]]
+	if o.Done() {
+		t.Error("Done: true, expected false")
+	}
+	calls := 0
+	f := func() Tuple[uint64, uint64] {
+		calls++
+		return Tuple[uint64, uint64]{uint64(calls), 2}
+	}
+	want := Tuple[uint64, uint64]{1, 2}
+	for i := 0; i < 3; i++ {
+		if got := o.Get(f); got != want {
+			t.Errorf("Get: %v, expected %v", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("f called %d times, expected 1", calls)
+	}
+	if !o.Done() {
+		t.Error("Done: false, expected true")
+	}
+}
+
+func TestOncePanic(t *testing.T) {
+	var o Once[string]
+	func() {
+		defer func() { recover() }()
+		o.Get(func() string { panic("fail") })
+	}()
+	if o.Done() {
+		t.Error("Done after panic: true, expected false")
+	}
+	if got, want := o.Get(func() string { return "ok" }), "ok"; got != want {
+		t.Errorf("Get: %q, expected %q", got, want)
+	}
+}
@@ -24,6 +24,15 @@ func Some[T any](v T) Option[T] {
 	}
 }
 
+// OptionOf returns Some(*v) if v is non-nil, or None if v is nil.
+// It is the inverse of [Option.Some], which returns a *T.
+func OptionOf[T any](v *T) Option[T] {
+	if v == nil {
+		return Option[T]{}
+	}
+	return Some(*v)
+}
+
 // None returns true if o represents the none case.
 func (o *Option[T]) None() bool {
 	return !o.isSome
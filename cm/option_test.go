@@ -27,3 +27,20 @@ func TestOption(t *testing.T) {
 		t.Errorf("o3.Some: %v, expected %v", got, want)
 	}
 }
+
+func TestOptionOf(t *testing.T) {
+	o1 := OptionOf[string](nil)
+	if !o1.None() {
+		t.Errorf("OptionOf(nil).None: false, expected true")
+	}
+
+	s := "hello"
+	o2 := OptionOf(&s)
+	if got := o2.Some(); got == nil || *got != s {
+		t.Errorf("OptionOf(&s).Some: %v, expected %q", got, s)
+	}
+	s = "changed"
+	if got := o2.Some(); *got != "hello" {
+		t.Errorf("OptionOf(&s) after modifying s: %q, expected a copy of %q", *got, "hello")
+	}
+}
@@ -0,0 +1,36 @@
+package cm
+
+import "unsafe"
+
+// LiftPointer returns ptr as a *T, viewing Canonical ABI memory directly as a Go value
+// of type T without copying it field by field. It returns nil if ptr is nil,
+// and panics if ptr is not aligned to the alignment of T.
+//
+// T must have the same memory layout in Go as its Component Model type in the Canonical ABI.
+// This holds for the types in this package and for types generated by wit-bindgen-go when
+// compiled for 32-bit WebAssembly with TinyGo. Generated packages include compile-time
+// assertions (abi_layout.wit.go) that fail to build if a generated type’s size or alignment
+// differ from its ABI layout. Use [HasLayout] to verify other types.
+//
+// The returned pointer aliases the memory at ptr, which must remain valid and unmodified
+// for as long as the result is in use. This is useful for large records such as HTTP headers,
+// which are otherwise lifted by copying each field.
+func LiftPointer[T any](ptr unsafe.Pointer) *T {
+	if ptr == nil {
+		return nil
+	}
+	var v T
+	if uintptr(ptr)%unsafe.Alignof(v) != 0 {
+		panic("LiftPointer: misaligned pointer")
+	}
+	return (*T)(ptr)
+}
+
+// HasLayout reports whether the Go type T has the specified size and alignment,
+// typically the Canonical ABI size and alignment of the corresponding WIT type
+// as computed by the wit package. A type whose layout matches may be lifted
+// with [LiftPointer].
+func HasLayout[T any](size, align uintptr) bool {
+	var v T
+	return unsafe.Sizeof(v) == size && unsafe.Alignof(v) == align
+}
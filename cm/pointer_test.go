@@ -0,0 +1,53 @@
+package cm
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestLiftPointer(t *testing.T) {
+	type record struct {
+		a uint32
+		b uint8
+		c uint16
+	}
+	if !HasLayout[record](8, 4) {
+		t.Fatalf("HasLayout[record](8, 4): false, expected true")
+	}
+	mem := [2]uint32{0x11223344, 0x55660077}
+	r := LiftPointer[record](unsafe.Pointer(&mem))
+	if r.a != 0x11223344 {
+		t.Errorf("r.a: %#x, expected %#x", r.a, 0x11223344)
+	}
+	r.a = 1
+	if mem[0] != 1 {
+		t.Errorf("LiftPointer copied memory: mem[0]: %d, expected 1", mem[0])
+	}
+	if got := LiftPointer[record](nil); got != nil {
+		t.Errorf("LiftPointer(nil): %v, expected nil", got)
+	}
+}
+
+func TestHasLayout(t *testing.T) {
+	if HasLayout[uint64](4, 4) {
+		t.Errorf("HasLayout[uint64](4, 4): true, expected false")
+	}
+	if !HasLayout[List[uint8]](unsafe.Sizeof(uintptr(0))*2, unsafe.Alignof(uintptr(0))) {
+		t.Errorf("HasLayout[List[uint8]]: false, expected true")
+	}
+}
+
+func TestLiftPointerMisaligned(t *testing.T) {
+	if runtime.Compiler == "tinygo" && strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("LiftPointer did not panic")
+		}
+	}()
+	var mem [2]uint32
+	_ = LiftPointer[uint32](unsafe.Add(unsafe.Pointer(&mem), 1))
+}
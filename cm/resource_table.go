@@ -0,0 +1,71 @@
+package cm
+
+import "sync"
+
+// ResourceTable maps the [Rep] of each instance of a resource implemented by a component
+// to a Go value of type T. Export bindings for a resource defined by a component pass the
+// rep to resource.new when an instance is created, and receive it when the host calls
+// a method or drops the instance, and use the table to find the Go value.
+//
+// Reps are assigned sequentially, starting at 1, and the reps of removed values are reused.
+// Rep 0 is never assigned. ResourceTable is safe for concurrent use.
+// The zero value is an empty table ready to use.
+type ResourceTable[T any] struct {
+	mu    sync.RWMutex
+	slots []resourceSlot[T] // slots[rep-1]
+	free  []Rep             // reps of removed values, reused last in, first out
+	n     int
+}
+
+type resourceSlot[T any] struct {
+	v  T
+	ok bool
+}
+
+// Add adds v to t and returns its rep.
+func (t *ResourceTable[T]) Add(v T) Rep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.n++
+	if n := len(t.free); n > 0 {
+		rep := t.free[n-1]
+		t.free = t.free[:n-1]
+		t.slots[rep-1] = resourceSlot[T]{v, true}
+		return rep
+	}
+	t.slots = append(t.slots, resourceSlot[T]{v, true})
+	return Rep(len(t.slots))
+}
+
+// Get returns the value with rep, and true if t contains rep.
+func (t *ResourceTable[T]) Get(rep Rep) (v T, ok bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if rep == 0 || int(rep) > len(t.slots) {
+		return v, false
+	}
+	s := t.slots[rep-1]
+	return s.v, s.ok
+}
+
+// Remove removes the value with rep from t, and returns it and true if t contained rep.
+// The rep may be returned by a later call to [ResourceTable.Add].
+func (t *ResourceTable[T]) Remove(rep Rep) (v T, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if rep == 0 || int(rep) > len(t.slots) || !t.slots[rep-1].ok {
+		return v, false
+	}
+	v = t.slots[rep-1].v
+	t.slots[rep-1] = resourceSlot[T]{} // release v
+	t.free = append(t.free, rep)
+	t.n--
+	return v, true
+}
+
+// Len returns the number of values in t.
+func (t *ResourceTable[T]) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.n
+}
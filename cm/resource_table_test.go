@@ -0,0 +1,69 @@
+package cm
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestResourceTable(t *testing.T) {
+	var table ResourceTable[string]
+	a := table.Add("a")
+	b := table.Add("b")
+	if a != 1 || b != 2 {
+		t.Errorf("Add: reps %d, %d, expected 1, 2", a, b)
+	}
+	if v, ok := table.Get(b); !ok || v != "b" {
+		t.Errorf("Get(%d): %q, %t, expected %q, true", b, v, ok, "b")
+	}
+	for _, rep := range []Rep{0, 3, 1000} {
+		if v, ok := table.Get(rep); ok {
+			t.Errorf("Get(%d): %q, true, expected false", rep, v)
+		}
+	}
+
+	if v, ok := table.Remove(a); !ok || v != "a" {
+		t.Errorf("Remove(%d): %q, %t, expected %q, true", a, v, ok, "a")
+	}
+	if _, ok := table.Get(a); ok {
+		t.Errorf("Get(%d): true after Remove, expected false", a)
+	}
+	if _, ok := table.Remove(a); ok {
+		t.Errorf("Remove(%d): true after Remove, expected false", a)
+	}
+	if got, want := table.Len(), 1; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+
+	// The rep of a removed value is reused.
+	if c := table.Add("c"); c != a {
+		t.Errorf("Add: rep %d, expected reused rep %d", c, a)
+	}
+	if v, _ := table.Get(a); v != "c" {
+		t.Errorf("Get(%d): %q, expected %q", a, v, "c")
+	}
+}
+
+func TestResourceTableConcurrent(t *testing.T) {
+	var table ResourceTable[int]
+	var wg sync.WaitGroup
+	const n = 100
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				rep := table.Add(j)
+				if v, ok := table.Get(rep); !ok || v != j {
+					t.Errorf("Get(%d): %d, %t, expected %d, true", rep, v, ok, j)
+				}
+				if j%2 == 0 {
+					table.Remove(rep)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := table.Len(), 8*n/2; got != want {
+		t.Errorf("Len: %d, expected %d", got, want)
+	}
+}
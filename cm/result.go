@@ -12,6 +12,8 @@ const (
 
 // Result represents a result with no OK or error type.
 // False represents the OK case and true represents the error case.
+// Like a bool, it is a single byte in memory, and is lowered to a single i32
+// in Core WebAssembly function signatures with [BoolToU32] and [U32ToBool].
 type Result bool
 
 // OKResult represents a result sized to hold the OK type.
@@ -128,7 +130,7 @@ func OK[R ~struct {
 	var r result[Shape, OK, Err]
 	r.validate()
 	r.isErr = ResultOK
-	*((*OK)(unsafe.Pointer(&r.data))) = ok
+	storeData(&r.data, ok)
 	return R(r)
 }
 
@@ -143,6 +145,6 @@ func Err[R ~struct {
 	var r result[Shape, OK, Err]
 	r.validate()
 	r.isErr = ResultErr
-	*((*Err)(unsafe.Pointer(&r.data))) = err
+	storeData(&r.data, err)
 	return R(r)
 }
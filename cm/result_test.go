@@ -4,6 +4,8 @@ import (
 	"runtime"
 	"testing"
 	"unsafe"
+
+	"github.com/ydnar/wasm-tools-go/cm/abi"
 )
 
 var (
@@ -31,32 +33,32 @@ func TestResultLayout(t *testing.T) {
 		{"ok", Result(ResultOK), 1, 0},
 		{"err", Result(ResultErr), 1, 0},
 
-		{"result<string, string>", OKResult[string, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<bool, string>", ErrResult[bool, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<string, _>", OKResult[string, struct{}]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<_, string>", ErrResult[struct{}, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<u64, u64>", OKResult[uint64, uint64]{}, 16, alignOf[uint64]()},
-		{"result<u32, u64>", ErrResult[uint32, uint64]{}, 16, alignOf[uint64]()},
-		{"result<u64, u32>", OKResult[uint64, uint32]{}, 16, alignOf[uint64]()},
-		{"result<u8, u64>", ErrResult[uint8, uint64]{}, 16, alignOf[uint64]()},
-		{"result<u64, u8>", OKResult[uint64, uint8]{}, 16, alignOf[uint64]()},
-		{"result<u8, u32>", ErrResult[uint8, uint32]{}, 8, alignOf[uint32]()},
-		{"result<u32, u8>", OKResult[uint32, uint8]{}, 8, alignOf[uint32]()},
-		{"result<[9]u8, u64>", OKResult[[9]byte, uint64]{}, 24, alignOf[uint64]()},
-
-		{"result<string, _>", OKResult[string, struct{}]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<string, _>", OKResult[string, struct{}]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<string, bool>", OKResult[string, bool]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<[9]u8, u64>", OKResult[[9]byte, uint64]{}, 24, alignOf[uint64]()},
-
-		{"result<_, string>", ErrResult[struct{}, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<_, string>", ErrResult[struct{}, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<bool, string>", ErrResult[bool, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"result<u64, [9]u8>", ErrResult[uint64, [9]byte]{}, 24, alignOf[uint64]()},
+		{"result<string, string>", OKResult[string, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<bool, string>", ErrResult[bool, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<string, _>", OKResult[string, struct{}]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<_, string>", ErrResult[struct{}, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<u64, u64>", OKResult[uint64, uint64]{}, 16, abi.AlignOf[uint64]()},
+		{"result<u32, u64>", ErrResult[uint32, uint64]{}, 16, abi.AlignOf[uint64]()},
+		{"result<u64, u32>", OKResult[uint64, uint32]{}, 16, abi.AlignOf[uint64]()},
+		{"result<u8, u64>", ErrResult[uint8, uint64]{}, 16, abi.AlignOf[uint64]()},
+		{"result<u64, u8>", OKResult[uint64, uint8]{}, 16, abi.AlignOf[uint64]()},
+		{"result<u8, u32>", ErrResult[uint8, uint32]{}, 8, abi.AlignOf[uint32]()},
+		{"result<u32, u8>", OKResult[uint32, uint8]{}, 8, abi.AlignOf[uint32]()},
+		{"result<[9]u8, u64>", OKResult[[9]byte, uint64]{}, 24, abi.AlignOf[uint64]()},
+
+		{"result<string, _>", OKResult[string, struct{}]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<string, _>", OKResult[string, struct{}]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<string, bool>", OKResult[string, bool]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<[9]u8, u64>", OKResult[[9]byte, uint64]{}, 24, abi.AlignOf[uint64]()},
+
+		{"result<_, string>", ErrResult[struct{}, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<_, string>", ErrResult[struct{}, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<bool, string>", ErrResult[bool, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"result<u64, [9]u8>", ErrResult[uint64, [9]byte]{}, 24, abi.AlignOf[uint64]()},
 	}
 
 	for _, tt := range tests {
-		typ := typeName(tt.r)
+		typ := abi.TypeName(tt.r)
 		t.Run(tt.name, func(t *testing.T) {
 			if got, want := tt.r.Size(), tt.size; got != want {
 				t.Errorf("(%s).Size(): %v, expected %v", typ, got, want)
@@ -86,6 +88,13 @@ func TestResultOKOrErr(t *testing.T) {
 	}
 }
 
+func TestResultErrSmallerThanShape(t *testing.T) {
+	r := Err[OKResult[List[uint8], uint32]](0x7ffffffe)
+	if err := r.Err(); err == nil || *err != 0x7ffffffe {
+		t.Errorf("Err(): %v, expected %#x", err, 0x7ffffffe)
+	}
+}
+
 func TestAltResult1(t *testing.T) {
 	type alt1[Shape, OK, Err any] struct {
 		_     [0]OK
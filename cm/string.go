@@ -0,0 +1,55 @@
+package cm
+
+import (
+	"unicode/utf16"
+	"unsafe"
+)
+
+// UTF16Tag is set in the length of a string lifted or lowered with the latin1+utf16
+// string encoding if the string is encoded as UTF-16 rather than Latin-1.
+const UTF16Tag = 1 << 31
+
+// LiftUTF16 returns the Go (UTF-8) string equivalent to the UTF-16 code units in units,
+// for example a string passed by a component that uses the utf16 string encoding.
+// Unpaired surrogates are replaced with U+FFFD.
+func LiftUTF16(units List[uint16]) string {
+	return string(utf16.Decode(units.Slice()))
+}
+
+// LowerUTF16 returns a List of the UTF-16 code units of the Go string s,
+// for passing s to a component that uses the utf16 string encoding.
+// Invalid UTF-8 in s is replaced with U+FFFD.
+func LowerUTF16(s string) List[uint16] {
+	return ToList(utf16.Encode([]rune(s)))
+}
+
+// LiftLatin1UTF16 returns the Go (UTF-8) string equivalent to a string encoded with the
+// latin1+utf16 string encoding at ptr, with length taggedLen. If [UTF16Tag] is set in
+// taggedLen, the string is UTF-16 with taggedLen &^ UTF16Tag code units,
+// otherwise it is Latin-1 with taggedLen bytes.
+func LiftLatin1UTF16(ptr unsafe.Pointer, taggedLen uint32) string {
+	if taggedLen&UTF16Tag != 0 {
+		return LiftUTF16(NewList((*uint16)(ptr), uint(taggedLen&^UTF16Tag)))
+	}
+	latin1 := unsafe.Slice((*byte)(ptr), taggedLen)
+	runes := make([]rune, len(latin1))
+	for i, c := range latin1 {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// LowerLatin1UTF16 returns a pointer to and the tagged length of the Go string s
+// encoded with the latin1+utf16 string encoding: Latin-1 if each character of s
+// is less than U+0100, otherwise UTF-16 with [UTF16Tag] set in taggedLen.
+func LowerLatin1UTF16(s string) (ptr unsafe.Pointer, taggedLen uint32) {
+	latin1 := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xff {
+			units := LowerUTF16(s)
+			return unsafe.Pointer(units.Data()), uint32(units.Len()) | UTF16Tag
+		}
+		latin1 = append(latin1, byte(r))
+	}
+	return unsafe.Pointer(unsafe.SliceData(latin1)), uint32(len(latin1))
+}
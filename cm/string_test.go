@@ -0,0 +1,61 @@
+package cm
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestUTF16(t *testing.T) {
+	tests := []struct {
+		s     string
+		units []uint16
+	}{
+		{"", nil},
+		{"hello", []uint16{'h', 'e', 'l', 'l', 'o'}},
+		{"héllo", []uint16{'h', 0xe9, 'l', 'l', 'o'}},
+		{"€1", []uint16{0x20ac, '1'}},
+		{"🙂", []uint16{0xd83d, 0xde42}},
+	}
+	for _, tt := range tests {
+		if got := LowerUTF16(tt.s).Slice(); !slices.Equal(got, tt.units) {
+			t.Errorf("LowerUTF16(%q): %#x, expected %#x", tt.s, got, tt.units)
+		}
+		if got := LiftUTF16(ToList(tt.units)); got != tt.s {
+			t.Errorf("LiftUTF16(%#x): %q, expected %q", tt.units, got, tt.s)
+		}
+	}
+
+	// Unpaired surrogate
+	if got, want := LiftUTF16(ToList([]uint16{'a', 0xd83d})), "a�"; got != want {
+		t.Errorf("LiftUTF16: %q, expected %q", got, want)
+	}
+}
+
+func TestLatin1UTF16(t *testing.T) {
+	tests := []struct {
+		s         string
+		taggedLen uint32
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"héllo", 5},
+		{"ÿ", 1},
+		{"€1", 2 | UTF16Tag},
+		{"a🙂", 3 | UTF16Tag},
+	}
+	for _, tt := range tests {
+		ptr, taggedLen := LowerLatin1UTF16(tt.s)
+		if taggedLen != tt.taggedLen {
+			t.Errorf("LowerLatin1UTF16(%q): length %#x, expected %#x", tt.s, taggedLen, tt.taggedLen)
+		}
+		if got := LiftLatin1UTF16(ptr, taggedLen); got != tt.s {
+			t.Errorf("LiftLatin1UTF16(LowerLatin1UTF16(%q)): %q", tt.s, got)
+		}
+	}
+
+	latin1 := []byte{'c', 'a', 'f', 0xe9}
+	if got, want := LiftLatin1UTF16(unsafe.Pointer(&latin1[0]), 4), "café"; got != want {
+		t.Errorf("LiftLatin1UTF16(%#x): %q, expected %q", latin1, got, want)
+	}
+}
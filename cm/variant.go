@@ -1,6 +1,9 @@
 package cm
 
-import "unsafe"
+import (
+	"errors"
+	"unsafe"
+)
 
 // Discriminant is the set of types that can represent the tag or discriminator of a variant.
 // Use bool for 2-value variants, results, or option<T> types, uint8 where there are 256 or
@@ -28,22 +31,81 @@ func validate[Disc Discriminant, Shape, Align any, T any]() {
 		panic("result: size of requested type > data type")
 	}
 
+	// Check if alignment of T is greater than the variant
+	if unsafe.Alignof(t) > unsafe.Alignof(v) {
+		panic("variant: alignment of requested type > variant alignment")
+	}
+
 	// Check if Shape is zero-sized, but size of result != 1
 	if unsafe.Sizeof(v.data) == 0 && unsafe.Sizeof(v) != 1 {
 		panic("result: size of data type == 0, but result size != 1")
 	}
 }
 
+var (
+	// ErrVariantLayout is returned by [TryNewVariant] and [TryCase] if a type is larger
+	// or more strictly aligned than the storage of a [Variant].
+	ErrVariantLayout = errors.New("variant: size or alignment of requested type > data type")
+
+	// ErrVariantTag is returned by [TryNewVariant] and [TryCase] if the tag of a [Variant]
+	// is not a valid case, for example a malformed discriminant lifted from untrusted memory.
+	ErrVariantTag = errors.New("variant: invalid tag")
+)
+
+// checkLayout returns [ErrVariantLayout] if a value of type T cannot be stored in a
+// [Variant] with the specified type parameters, where [validate] would panic.
+func checkLayout[Disc Discriminant, Shape, Align any, T any]() error {
+	var v Variant[Disc, Shape, Align]
+	var t T
+	if unsafe.Sizeof(t) > unsafe.Sizeof(v.data) ||
+		unsafe.Alignof(t) > unsafe.Alignof(v) ||
+		(unsafe.Sizeof(v.data) == 0 && unsafe.Sizeof(v) != 1) {
+		return ErrVariantLayout
+	}
+	return nil
+}
+
+// tagIndex returns the integer value of tag. Unlike a conversion,
+// it returns the underlying byte of a bool, which may be > 1 if lifted from memory.
+func tagIndex[Disc Discriminant](tag *Disc) uint32 {
+	switch unsafe.Sizeof(*tag) {
+	case 1:
+		return uint32(*(*uint8)(unsafe.Pointer(tag)))
+	case 2:
+		return uint32(*(*uint16)(unsafe.Pointer(tag)))
+	default:
+		return *(*uint32)(unsafe.Pointer(tag))
+	}
+}
+
 // NewVariant returns a [Variant] with tag of type Disc, storage and GC shape of type Shape,
 // aligned to type Align, with a value of type T.
+// If T overlaps padding bytes in Shape, its value may not survive a copy of the variant,
+// so wit-bindgen-go generates an array Shape if the largest case type has padding.
 func NewVariant[Disc Discriminant, Shape, Align any, T any](tag Disc, data T) Variant[Disc, Shape, Align] {
 	validate[Disc, Shape, Align, T]()
 	var v Variant[Disc, Shape, Align]
 	v.tag = tag
-	v.data = *(*Shape)(unsafe.Pointer(&data))
+	storeData(&v.data, data)
 	return v
 }
 
+// TryNewVariant is like [NewVariant], but returns an error instead of panicking.
+// It returns [ErrVariantTag] if tag is not less than cases, the number of cases in the variant,
+// or [ErrVariantLayout] if T is larger or more strictly aligned than Shape.
+func TryNewVariant[Disc Discriminant, Shape, Align any, T any](tag Disc, data T, cases uint32) (Variant[Disc, Shape, Align], error) {
+	var v Variant[Disc, Shape, Align]
+	if err := checkLayout[Disc, Shape, Align, T](); err != nil {
+		return v, err
+	}
+	if tagIndex(&tag) >= cases {
+		return v, ErrVariantTag
+	}
+	v.tag = tag
+	storeData(&v.data, data)
+	return v, nil
+}
+
 // New returns a [Variant] with tag of type Disc, storage and GC shape of type Shape,
 // aligned to type Align, with a value of type T.
 func New[V ~struct {
@@ -54,10 +116,21 @@ func New[V ~struct {
 	validate[Disc, Shape, Align, T]()
 	var v Variant[Disc, Shape, Align]
 	v.tag = tag
-	v.data = *(*Shape)(unsafe.Pointer(&data))
+	storeData(&v.data, data)
 	return V(v)
 }
 
+// storeData copies the bytes of data into the leading bytes of shape.
+// It does not read past the end of data, which may be smaller than Shape,
+// and copies bytes rather than storing a T through a *T, which the compiler
+// may assume does not alias the memory of a Shape with a different type.
+func storeData[Shape, T any](shape *Shape, data T) {
+	size := unsafe.Sizeof(data)
+	if size > 0 {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(shape)), size), unsafe.Slice((*byte)(unsafe.Pointer(&data)), size))
+	}
+}
+
 // Tag returns the tag of [Variant] v.
 func Tag[V ~struct {
 	tag  Disc
@@ -92,3 +165,58 @@ func Case[T any, V ~struct {
 	}
 	return nil
 }
+
+// TryCase is like [Case], but returns an error instead of panicking.
+// It returns [ErrVariantTag] if the tag of v or tag is not less than cases, the number of
+// cases in the variant, or [ErrVariantLayout] if T is larger or more strictly aligned than
+// the storage of v. Otherwise, it returns a non-nil *T if the case of v is equal to tag,
+// or nil if it is a different case.
+func TryCase[T any, V ~struct {
+	tag  Disc
+	_    [0]Align
+	data Shape
+}, Disc Discriminant, Shape, Align any](v *V, tag Disc, cases uint32) (*T, error) {
+	if err := checkLayout[Disc, Shape, Align, T](); err != nil {
+		return nil, err
+	}
+	v2 := (*Variant[Disc, Shape, Align])(unsafe.Pointer(v))
+	if tagIndex(&v2.tag) >= cases || tagIndex(&tag) >= cases {
+		return nil, ErrVariantTag
+	}
+	if v2.tag == tag {
+		return (*T)(unsafe.Pointer(&v2.data)), nil
+	}
+	return nil, nil
+}
+
+// Payload returns a pointer to the payload of [Variant] v as *T, regardless of its tag.
+// It is a view of the storage shared by all cases of v, useful for lowering a variant
+// without inspecting its case. Use [Case] to read the payload of a known case.
+// It panics if T is larger or more strictly aligned than the storage of v.
+func Payload[T any, V ~struct {
+	tag  Disc
+	_    [0]Align
+	data Shape
+}, Disc Discriminant, Shape, Align any](v *V) *T {
+	validate[Disc, Shape, Align, T]()
+	v2 := (*Variant[Disc, Shape, Align])(unsafe.Pointer(v))
+	return (*T)(unsafe.Pointer(&v2.data))
+}
+
+// Reinterpret returns the bits of from as type T, which must be no larger than From.
+// The leading bytes of from are copied, so the result does not alias from.
+// Because from is passed by value, the contents of any padding in From are undefined.
+// If T contains pointers, then From must contain pointers at the same offsets,
+// otherwise the garbage collector may not observe them.
+func Reinterpret[T, From any](from From) T {
+	var t T
+	if unsafe.Sizeof(t) > unsafe.Sizeof(from) {
+		panic("reinterpret: size of T > size of From")
+	}
+	// Copy bytes rather than dereference a *T, which may be misaligned.
+	size := unsafe.Sizeof(t)
+	if size > 0 {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&t)), size), unsafe.Slice((*byte)(unsafe.Pointer(&from)), size))
+	}
+	return t
+}
@@ -0,0 +1,87 @@
+//go:build ignore
+
+// This program generates variant_matrix_test.go, which tests every pair of
+// case types in a two-case variant with the Shape and Align type parameters
+// chosen by wit-bindgen-go from their Canonical ABI size and alignment.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+)
+
+// caseType is a Go type that represents a WIT type in a variant case.
+type caseType struct {
+	goType  string
+	size    int  // Canonical ABI size on wasm32
+	align   int  // Canonical ABI alignment on wasm32
+	pointer bool // contains a pointer
+	padded  bool // contains padding bytes
+	value   string
+}
+
+var caseTypes = []caseType{
+	{"bool", 1, 1, false, false, "true"},
+	{"uint8", 1, 1, false, false, "0x7f"},
+	{"uint16", 2, 2, false, false, "0x7ffe"},
+	{"uint32", 4, 4, false, false, "0x7ffffffe"},
+	{"uint64", 8, 8, false, false, "0x7ffffffffffffffe"},
+	{"float32", 4, 4, false, false, "3.5"},
+	{"float64", 8, 8, false, false, "-1.25"},
+	{"string", 8, 4, true, false, `"hello"`},
+	{"List[uint8]", 8, 4, true, false, "ToList([]uint8{1, 2, 3})"},
+	{"Option[string]", 12, 4, true, true, `Some("world")`},
+	{"[3]uint32", 12, 4, false, false, "[3]uint32{1, 2, 3}"},
+	{"Tuple[uint8, uint64]", 16, 8, false, true, "Tuple[uint8, uint64]{1, 2}"},
+}
+
+// shape returns the largest type of a and b, preferring types with pointers,
+// as wit-bindgen-go does for the Shape of a variant.
+func shape(a, b caseType) caseType {
+	switch {
+	case b.size > a.size:
+		return b
+	case b.size == a.size && b.pointer && !a.pointer:
+		return b
+	}
+	return a
+}
+
+// align returns the type of a and b with the highest alignment.
+func align(a, b caseType) caseType {
+	if b.align > a.align {
+		return b
+	}
+	return a
+}
+
+func main() {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by variant_matrix_gen.go. DO NOT EDIT.\n\n")
+	b.WriteString("package cm\n\n")
+	b.WriteString("import \"testing\"\n\n")
+	b.WriteString("func TestVariantMatrix(t *testing.T) {\n")
+	for i, a := range caseTypes {
+		for _, c := range caseTypes[i:] {
+			s := shape(a, c)
+			al := align(a, c)
+			name := fmt.Sprintf("variant { %s; %s }", a.goType, c.goType)
+			fmt.Fprintf(&b, "t.Run(%q, func(t *testing.T) {\n", name)
+			fmt.Fprintf(&b, "testVariantPair[%s, %s](t, %t, %s(%s), %s(%s))\n", s.goType, al.goType, s.padded, a.goType, a.value, c.goType, c.value)
+			b.WriteString("})\n")
+		}
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile("variant_matrix_test.go", src, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,242 @@
+// Code generated by variant_matrix_gen.go. DO NOT EDIT.
+
+package cm
+
+import "testing"
+
+func TestVariantMatrix(t *testing.T) {
+	t.Run("variant { bool; bool }", func(t *testing.T) {
+		testVariantPair[bool, bool](t, false, bool(true), bool(true))
+	})
+	t.Run("variant { bool; uint8 }", func(t *testing.T) {
+		testVariantPair[bool, bool](t, false, bool(true), uint8(0x7f))
+	})
+	t.Run("variant { bool; uint16 }", func(t *testing.T) {
+		testVariantPair[uint16, uint16](t, false, bool(true), uint16(0x7ffe))
+	})
+	t.Run("variant { bool; uint32 }", func(t *testing.T) {
+		testVariantPair[uint32, uint32](t, false, bool(true), uint32(0x7ffffffe))
+	})
+	t.Run("variant { bool; uint64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, bool(true), uint64(0x7ffffffffffffffe))
+	})
+	t.Run("variant { bool; float32 }", func(t *testing.T) {
+		testVariantPair[float32, float32](t, false, bool(true), float32(3.5))
+	})
+	t.Run("variant { bool; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, bool(true), float64(-1.25))
+	})
+	t.Run("variant { bool; string }", func(t *testing.T) {
+		testVariantPair[string, string](t, false, bool(true), string("hello"))
+	})
+	t.Run("variant { bool; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], List[uint8]](t, false, bool(true), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { bool; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], Option[string]](t, true, bool(true), Option[string](Some("world")))
+	})
+	t.Run("variant { bool; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, [3]uint32](t, false, bool(true), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { bool; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, bool(true), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { uint8; uint8 }", func(t *testing.T) {
+		testVariantPair[uint8, uint8](t, false, uint8(0x7f), uint8(0x7f))
+	})
+	t.Run("variant { uint8; uint16 }", func(t *testing.T) {
+		testVariantPair[uint16, uint16](t, false, uint8(0x7f), uint16(0x7ffe))
+	})
+	t.Run("variant { uint8; uint32 }", func(t *testing.T) {
+		testVariantPair[uint32, uint32](t, false, uint8(0x7f), uint32(0x7ffffffe))
+	})
+	t.Run("variant { uint8; uint64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint8(0x7f), uint64(0x7ffffffffffffffe))
+	})
+	t.Run("variant { uint8; float32 }", func(t *testing.T) {
+		testVariantPair[float32, float32](t, false, uint8(0x7f), float32(3.5))
+	})
+	t.Run("variant { uint8; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, uint8(0x7f), float64(-1.25))
+	})
+	t.Run("variant { uint8; string }", func(t *testing.T) {
+		testVariantPair[string, string](t, false, uint8(0x7f), string("hello"))
+	})
+	t.Run("variant { uint8; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], List[uint8]](t, false, uint8(0x7f), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { uint8; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], Option[string]](t, true, uint8(0x7f), Option[string](Some("world")))
+	})
+	t.Run("variant { uint8; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, [3]uint32](t, false, uint8(0x7f), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { uint8; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, uint8(0x7f), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { uint16; uint16 }", func(t *testing.T) {
+		testVariantPair[uint16, uint16](t, false, uint16(0x7ffe), uint16(0x7ffe))
+	})
+	t.Run("variant { uint16; uint32 }", func(t *testing.T) {
+		testVariantPair[uint32, uint32](t, false, uint16(0x7ffe), uint32(0x7ffffffe))
+	})
+	t.Run("variant { uint16; uint64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint16(0x7ffe), uint64(0x7ffffffffffffffe))
+	})
+	t.Run("variant { uint16; float32 }", func(t *testing.T) {
+		testVariantPair[float32, float32](t, false, uint16(0x7ffe), float32(3.5))
+	})
+	t.Run("variant { uint16; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, uint16(0x7ffe), float64(-1.25))
+	})
+	t.Run("variant { uint16; string }", func(t *testing.T) {
+		testVariantPair[string, string](t, false, uint16(0x7ffe), string("hello"))
+	})
+	t.Run("variant { uint16; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], List[uint8]](t, false, uint16(0x7ffe), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { uint16; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], Option[string]](t, true, uint16(0x7ffe), Option[string](Some("world")))
+	})
+	t.Run("variant { uint16; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, [3]uint32](t, false, uint16(0x7ffe), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { uint16; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, uint16(0x7ffe), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { uint32; uint32 }", func(t *testing.T) {
+		testVariantPair[uint32, uint32](t, false, uint32(0x7ffffffe), uint32(0x7ffffffe))
+	})
+	t.Run("variant { uint32; uint64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint32(0x7ffffffe), uint64(0x7ffffffffffffffe))
+	})
+	t.Run("variant { uint32; float32 }", func(t *testing.T) {
+		testVariantPair[uint32, uint32](t, false, uint32(0x7ffffffe), float32(3.5))
+	})
+	t.Run("variant { uint32; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, uint32(0x7ffffffe), float64(-1.25))
+	})
+	t.Run("variant { uint32; string }", func(t *testing.T) {
+		testVariantPair[string, uint32](t, false, uint32(0x7ffffffe), string("hello"))
+	})
+	t.Run("variant { uint32; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], uint32](t, false, uint32(0x7ffffffe), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { uint32; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], uint32](t, true, uint32(0x7ffffffe), Option[string](Some("world")))
+	})
+	t.Run("variant { uint32; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, uint32](t, false, uint32(0x7ffffffe), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { uint32; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, uint32(0x7ffffffe), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { uint64; uint64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint64(0x7ffffffffffffffe), uint64(0x7ffffffffffffffe))
+	})
+	t.Run("variant { uint64; float32 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint64(0x7ffffffffffffffe), float32(3.5))
+	})
+	t.Run("variant { uint64; float64 }", func(t *testing.T) {
+		testVariantPair[uint64, uint64](t, false, uint64(0x7ffffffffffffffe), float64(-1.25))
+	})
+	t.Run("variant { uint64; string }", func(t *testing.T) {
+		testVariantPair[string, uint64](t, false, uint64(0x7ffffffffffffffe), string("hello"))
+	})
+	t.Run("variant { uint64; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], uint64](t, false, uint64(0x7ffffffffffffffe), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { uint64; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], uint64](t, true, uint64(0x7ffffffffffffffe), Option[string](Some("world")))
+	})
+	t.Run("variant { uint64; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, uint64](t, false, uint64(0x7ffffffffffffffe), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { uint64; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], uint64](t, true, uint64(0x7ffffffffffffffe), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { float32; float32 }", func(t *testing.T) {
+		testVariantPair[float32, float32](t, false, float32(3.5), float32(3.5))
+	})
+	t.Run("variant { float32; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, float32(3.5), float64(-1.25))
+	})
+	t.Run("variant { float32; string }", func(t *testing.T) {
+		testVariantPair[string, float32](t, false, float32(3.5), string("hello"))
+	})
+	t.Run("variant { float32; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], float32](t, false, float32(3.5), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { float32; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], float32](t, true, float32(3.5), Option[string](Some("world")))
+	})
+	t.Run("variant { float32; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, float32](t, false, float32(3.5), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { float32; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, float32(3.5), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { float64; float64 }", func(t *testing.T) {
+		testVariantPair[float64, float64](t, false, float64(-1.25), float64(-1.25))
+	})
+	t.Run("variant { float64; string }", func(t *testing.T) {
+		testVariantPair[string, float64](t, false, float64(-1.25), string("hello"))
+	})
+	t.Run("variant { float64; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], float64](t, false, float64(-1.25), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { float64; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], float64](t, true, float64(-1.25), Option[string](Some("world")))
+	})
+	t.Run("variant { float64; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, float64](t, false, float64(-1.25), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { float64; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], float64](t, true, float64(-1.25), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { string; string }", func(t *testing.T) {
+		testVariantPair[string, string](t, false, string("hello"), string("hello"))
+	})
+	t.Run("variant { string; List[uint8] }", func(t *testing.T) {
+		testVariantPair[string, string](t, false, string("hello"), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { string; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], string](t, true, string("hello"), Option[string](Some("world")))
+	})
+	t.Run("variant { string; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, string](t, false, string("hello"), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { string; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, string("hello"), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { List[uint8]; List[uint8] }", func(t *testing.T) {
+		testVariantPair[List[uint8], List[uint8]](t, false, List[uint8](ToList([]uint8{1, 2, 3})), List[uint8](ToList([]uint8{1, 2, 3})))
+	})
+	t.Run("variant { List[uint8]; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], List[uint8]](t, true, List[uint8](ToList([]uint8{1, 2, 3})), Option[string](Some("world")))
+	})
+	t.Run("variant { List[uint8]; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, List[uint8]](t, false, List[uint8](ToList([]uint8{1, 2, 3})), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { List[uint8]; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, List[uint8](ToList([]uint8{1, 2, 3})), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { Option[string]; Option[string] }", func(t *testing.T) {
+		testVariantPair[Option[string], Option[string]](t, true, Option[string](Some("world")), Option[string](Some("world")))
+	})
+	t.Run("variant { Option[string]; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[Option[string], Option[string]](t, true, Option[string](Some("world")), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { Option[string]; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, Option[string](Some("world")), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { [3]uint32; [3]uint32 }", func(t *testing.T) {
+		testVariantPair[[3]uint32, [3]uint32](t, false, [3]uint32([3]uint32{1, 2, 3}), [3]uint32([3]uint32{1, 2, 3}))
+	})
+	t.Run("variant { [3]uint32; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, [3]uint32([3]uint32{1, 2, 3}), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+	t.Run("variant { Tuple[uint8, uint64]; Tuple[uint8, uint64] }", func(t *testing.T) {
+		testVariantPair[Tuple[uint8, uint64], Tuple[uint8, uint64]](t, true, Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}), Tuple[uint8, uint64](Tuple[uint8, uint64]{1, 2}))
+	})
+}
@@ -1,10 +1,14 @@
 package cm
 
+//go:generate go run variant_matrix_gen.go
+
 import (
 	"runtime"
 	"strings"
 	"testing"
 	"unsafe"
+
+	"github.com/ydnar/wasm-tools-go/cm/abi"
 )
 
 func TestVariantLayout(t *testing.T) {
@@ -17,22 +21,22 @@ func TestVariantLayout(t *testing.T) {
 		size   uintptr
 		offset uintptr
 	}{
-		{"variant { string; string }", Variant[bool, string, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"variant { bool; string }", Variant[bool, string, bool]{}, sizePlusAlignOf[string](), ptrSize},
-		{"variant { string; _ }", Variant[bool, string, string]{}, sizePlusAlignOf[string](), ptrSize},
-		{"variant { _; _ }", Variant[bool, string, struct{}]{}, sizePlusAlignOf[string](), ptrSize},
-		{"variant { u64; u64 }", Variant[bool, uint64, uint64]{}, 16, alignOf[uint64]()},
-		{"variant { u32; u64 }", Variant[bool, uint64, uint32]{}, 16, alignOf[uint64]()},
-		{"variant { u64; u32 }", Variant[bool, uint64, uint32]{}, 16, alignOf[uint64]()},
-		{"variant { u8; u64 }", Variant[bool, uint64, uint8]{}, 16, alignOf[uint64]()},
-		{"variant { u64; u8 }", Variant[bool, uint64, uint8]{}, 16, alignOf[uint64]()},
-		{"variant { u8; u32 }", Variant[bool, uint32, uint8]{}, 8, alignOf[uint32]()},
-		{"variant { u32; u8 }", Variant[bool, uint32, uint8]{}, 8, alignOf[uint32]()},
-		{"variant { [9]u8, u64 }", Variant[bool, [9]byte, uint64]{}, 24, alignOf[uint64]()},
+		{"variant { string; string }", Variant[bool, string, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"variant { bool; string }", Variant[bool, string, bool]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"variant { string; _ }", Variant[bool, string, string]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"variant { _; _ }", Variant[bool, string, struct{}]{}, abi.SizePlusAlignOf[string](), ptrSize},
+		{"variant { u64; u64 }", Variant[bool, uint64, uint64]{}, 16, abi.AlignOf[uint64]()},
+		{"variant { u32; u64 }", Variant[bool, uint64, uint32]{}, 16, abi.AlignOf[uint64]()},
+		{"variant { u64; u32 }", Variant[bool, uint64, uint32]{}, 16, abi.AlignOf[uint64]()},
+		{"variant { u8; u64 }", Variant[bool, uint64, uint8]{}, 16, abi.AlignOf[uint64]()},
+		{"variant { u64; u8 }", Variant[bool, uint64, uint8]{}, 16, abi.AlignOf[uint64]()},
+		{"variant { u8; u32 }", Variant[bool, uint32, uint8]{}, 8, abi.AlignOf[uint32]()},
+		{"variant { u32; u8 }", Variant[bool, uint32, uint8]{}, 8, abi.AlignOf[uint32]()},
+		{"variant { [9]u8, u64 }", Variant[bool, [9]byte, uint64]{}, 24, abi.AlignOf[uint64]()},
 	}
 
 	for _, tt := range tests {
-		typ := typeName(tt.v)
+		typ := abi.TypeName(tt.v)
 		t.Run(tt.name, func(t *testing.T) {
 			if got, want := tt.v.Size(), tt.size; got != want {
 				t.Errorf("(%s).Size(): %v, expected %v", typ, got, want)
@@ -68,3 +72,176 @@ func TestNewVariantValidates(t *testing.T) {
 	}()
 	_ = NewVariant[uint8, uint8, uint8](0, "hello world")
 }
+
+// testVariantPair tests a two-case variant with case types A and B,
+// storage Shape, and alignment Align. If Shape contains padding, then the cases are
+// only tested in place, because copying a variant may not copy padding bytes.
+// See variant_matrix_gen.go.
+func testVariantPair[Shape, Align any, A, B comparable](t *testing.T, padded bool, a A, b B) {
+	t.Helper()
+	testVariantCase[Shape, Align](t, padded, 0, a)
+	testVariantCase[Shape, Align](t, padded, 1, b)
+
+	// The variant is aligned to its most strictly aligned type,
+	// and its size is a multiple of its alignment.
+	var v Variant[uint8, Shape, Align]
+	align := max(abi.AlignOf[Shape](), abi.AlignOf[Align]())
+	if got, want := v.DataOffset(), align; got != want {
+		t.Errorf("DataOffset(): %d, expected %d", got, want)
+	}
+	if got, want := v.Size(), (align+unsafe.Sizeof(v.data)+align-1)&^(align-1); got != want {
+		t.Errorf("Size(): %d, expected %d", got, want)
+	}
+}
+
+func testVariantCase[Shape, Align any, T comparable](t *testing.T, padded bool, tag uint8, want T) {
+	t.Helper()
+	var shape Shape
+
+	// A case type with pointers can be larger than Shape on 64-bit hosts, where pointers
+	// and lengths are 8 bytes, but the Shape is chosen from its Canonical ABI size on wasm32.
+	if unsafe.Sizeof(want) > unsafe.Sizeof(shape) {
+		if runtime.Compiler == "tinygo" && strings.Contains(runtime.GOARCH, "wasm") {
+			return
+		}
+		defer func() {
+			if recover() == nil {
+				t.Errorf("NewVariant(%d, %T): did not panic with size %d > shape size %d", tag, want, unsafe.Sizeof(want), unsafe.Sizeof(shape))
+			}
+		}()
+		_ = NewVariant[uint8, Shape, Align](tag, want)
+		return
+	}
+
+	var v Variant[uint8, Shape, Align]
+	if padded {
+		v.tag = tag
+		*Payload[T](&v) = want
+	} else {
+		v = NewVariant[uint8, Shape, Align](tag, want)
+	}
+	if got := Tag(&v); got != tag {
+		t.Errorf("Tag: %d, expected %d", got, tag)
+	}
+	if got := Case[T](&v, tag); got == nil || *got != want {
+		t.Errorf("Case[%T](%d): %v, expected %v", want, tag, got, want)
+	}
+	if got := Case[T](&v, tag^1); got != nil {
+		t.Errorf("Case[%T](%d): %v, expected nil", want, tag^1, *got)
+	}
+	if got := *Payload[T](&v); got != want {
+		t.Errorf("Payload[%T]: %v, expected %v", want, got, want)
+	}
+}
+
+// copyVariant returns a copy of v, which the compiler may copy field by field.
+//
+//go:noinline
+func copyVariant[V any](v V) V {
+	return v
+}
+
+// TestVariantCopy verifies that cases stored in an array Shape, which wit-bindgen-go
+// generates if the largest case has padding, survive a copy of the variant.
+func TestVariantCopy(t *testing.T) {
+	type padded struct {
+		A uint8
+		B uint64
+	}
+	type variant = Variant[uint8, [2]uint64, uint64]
+
+	u8 := copyVariant(NewVariant[uint8, [2]uint64, uint64](0, uint8(0xab)))
+	p := copyVariant(NewVariant[uint8, [2]uint64, uint64](1, padded{0xab, 0x0123456789abcdef}))
+	u64 := copyVariant(NewVariant[uint8, [2]uint64, uint64](2, uint64(0x0123456789abcdef)))
+	vs := []variant{u8, p, u64}
+	vs = append([]variant(nil), vs...)
+
+	if got := Case[uint8](&vs[0], 0); got == nil || *got != 0xab {
+		t.Errorf("Case[uint8]: %v, expected 0xab", got)
+	}
+	if got := Case[padded](&vs[1], 1); got == nil || *got != (padded{0xab, 0x0123456789abcdef}) {
+		t.Errorf("Case[padded]: %v, expected {0xab, 0x0123456789abcdef}", got)
+	}
+	if got := Case[uint64](&vs[2], 2); got == nil || *got != 0x0123456789abcdef {
+		t.Errorf("Case[uint64]: %v, expected 0x0123456789abcdef", got)
+	}
+}
+
+func TestReinterpret(t *testing.T) {
+	if got, want := Reinterpret[uint32](float32(1)), uint32(0x3f800000); got != want {
+		t.Errorf("Reinterpret[uint32](float32(1)): %#x, expected %#x", got, want)
+	}
+	if got, want := Reinterpret[uint16]([4]byte{1, 2, 3, 4}), *(*uint16)(unsafe.Pointer(&[2]byte{1, 2})); got != want {
+		t.Errorf("Reinterpret[uint16]: %#x, expected %#x", got, want)
+	}
+	// Unaligned source
+	if got, want := Reinterpret[uint64]([9]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 1}), uint64(1<<64-1); got != want {
+		t.Errorf("Reinterpret[uint64]: %#x, expected %#x", got, want)
+	}
+}
+
+func TestReinterpretValidates(t *testing.T) {
+	if runtime.Compiler == "tinygo" && strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Reinterpret did not panic")
+		}
+	}()
+	_ = Reinterpret[uint64](uint32(0))
+}
+
+func TestPayloadValidatesAlignment(t *testing.T) {
+	if runtime.Compiler == "tinygo" && strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Payload did not panic")
+		}
+	}()
+	var v Variant[uint8, [8]uint8, uint8]
+	_ = Payload[uint64](&v)
+}
+
+func TestTryNewVariant(t *testing.T) {
+	v, err := TryNewVariant[uint8, uint64, uint64](1, uint32(7), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := *Case[uint32](&v, 1), uint32(7); got != want {
+		t.Errorf("TryNewVariant: %d, expected %d", got, want)
+	}
+	if _, err := TryNewVariant[uint8, uint64, uint64](3, uint32(7), 3); err != ErrVariantTag {
+		t.Errorf("TryNewVariant with tag 3 of 3: %v, expected %v", err, ErrVariantTag)
+	}
+	if _, err := TryNewVariant[uint8, uint8, uint8](0, "string", 3); err != ErrVariantLayout {
+		t.Errorf("TryNewVariant with string in uint8 variant: %v, expected %v", err, ErrVariantLayout)
+	}
+}
+
+func TestTryCase(t *testing.T) {
+	v := NewVariant[uint16, uint64, uint64](2, uint64(42))
+	p, err := TryCase[uint64](&v, 2, 3)
+	if err != nil || p == nil || *p != 42 {
+		t.Errorf("TryCase(2): %v, %v, expected 42", p, err)
+	}
+	p, err = TryCase[uint64](&v, 1, 3)
+	if err != nil || p != nil {
+		t.Errorf("TryCase(1): %v, %v, expected nil, nil", p, err)
+	}
+	if _, err := TryCase[uint64](&v, 2, 2); err != ErrVariantTag {
+		t.Errorf("TryCase with tag 2 of 2: %v, expected %v", err, ErrVariantTag)
+	}
+	if _, err := TryCase[[2]uint64](&v, 2, 3); err != ErrVariantLayout {
+		t.Errorf("TryCase with [2]uint64 in uint64 variant: %v, expected %v", err, ErrVariantLayout)
+	}
+
+	// A malformed bool discriminant, e.g. lifted from untrusted memory.
+	b := NewVariant[bool, uint32, uint32](true, uint32(1))
+	*(*uint8)(unsafe.Pointer(&b)) = 2
+	if _, err := TryCase[uint32](&b, true, 2); err != ErrVariantTag {
+		t.Errorf("TryCase with bool tag 2: %v, expected %v", err, ErrVariantTag)
+	}
+}
@@ -0,0 +1,238 @@
+package describe
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Command is the CLI command for describe.
+var Command = &cli.Command{
+	Name:  "describe",
+	Usage: "describe the Core WebAssembly imports and exports of a WIT world, check a module or component against it, or explain a type",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to describe, otherwise the last world",
+		},
+		&cli.StringFlag{
+			Name:      "module",
+			Aliases:   []string{"m"},
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "Core WebAssembly module or component to check against the world",
+		},
+		&cli.StringFlag{
+			Name:     "targets",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to check that the world targets: imports a subset of its imports, and exports all of its exports",
+		},
+		&cli.StringFlag{
+			Name:     "type",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "explain the structure and Canonical ABI layout of a type, e.g. wasi:http/types#request-options",
+		},
+		&cli.StringFlag{
+			Name:     "deps",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "list the interfaces and types an interface depends on, transitively across packages, e.g. wasi:http/types",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	res, err := witcli.Load(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	if cmd.IsSet("type") {
+		t, err := res.TypeDef(cmd.String("type"))
+		if err != nil {
+			return err
+		}
+		fmt.Print(t.Explain())
+		return nil
+	}
+
+	if cmd.IsSet("deps") {
+		i, err := res.Interface(cmd.String("deps"))
+		if err != nil {
+			return err
+		}
+		for _, dep := range i.Dependencies() {
+			fmt.Println(dep)
+		}
+		return nil
+	}
+
+	w, err := witcli.FindWorld(res, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+
+	if cmd.IsSet("targets") {
+		target, err := witcli.FindWorld(res, cmd.String("targets"))
+		if err != nil {
+			return err
+		}
+		err = w.Targets(target)
+		if err, ok := err.(*wit.TargetsError); ok {
+			for _, m := range err.Mismatches {
+				fmt.Println(m)
+			}
+			return fmt.Errorf("world %s does not target %s: %d problem(s)", err.World, err.Target, len(err.Mismatches))
+		}
+		if err != nil {
+			return err
+		}
+		slog.Info("world targets world", "world", worldID(w), "target", worldID(target))
+		return nil
+	}
+
+	module := cmd.String("module")
+	if module == "" {
+		fmt.Printf("world %s\n", worldID(w))
+		for _, f := range witcli.CoreImports(w) {
+			fmt.Printf("  (import %q %q %s)\n", f.Module, f.Name, f.Type)
+		}
+		for _, f := range witcli.CoreExports(w) {
+			fmt.Printf("  (export %q %s)\n", f.Name, f.Type)
+		}
+		return nil
+	}
+
+	b, err := os.ReadFile(module)
+	if err != nil {
+		return err
+	}
+	var problems []string
+	switch {
+	case wasm.IsModule(b):
+		m, err := wasm.DecodeModule(b)
+		if err != nil {
+			return fmt.Errorf("%s: %w", module, err)
+		}
+		problems = checkModule(w, m)
+	case wasm.IsComponent(b):
+		c, err := wasm.DecodeComponent(b)
+		if err != nil {
+			return fmt.Errorf("%s: %w", module, err)
+		}
+		problems = checkComponent(w, c)
+	default:
+		return fmt.Errorf("%s: not a WebAssembly module or component", module)
+	}
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is not compatible with world %s: %d problem(s)", module, worldID(w), len(problems))
+	}
+	slog.Info("compatible with world", "path", module, "world", worldID(w))
+	return nil
+}
+
+// checkModule returns a list of problems that prevent Core WebAssembly module m
+// from implementing world w. Imports from wasi_snapshot_preview1 are assumed to
+// be satisfied by an adapter. Non-function imports and exports are ignored.
+func checkModule(w *wit.World, m *wasm.Module) []string {
+	// Functions imported by world-level items can also use the world name,
+	// as emitted by earlier versions of wit-bindgen-go.
+	id := worldID(w)
+	imports := make(map[[2]string]*wasm.FuncType)
+	for _, f := range witcli.CoreImports(w) {
+		imports[[2]string{f.Module, f.Name}] = f.Type
+		if f.Module == witcli.RootModule {
+			imports[[2]string{id, f.Name}] = f.Type
+		}
+	}
+
+	var problems []string
+	for _, imp := range m.Imports {
+		if imp.Kind != wasm.ExternFunc || imp.Module == "wasi_snapshot_preview1" {
+			continue
+		}
+		want, ok := imports[[2]string{imp.Module, imp.Name}]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("unknown import %q %q: not in world %s", imp.Module, imp.Name, id))
+		case want.String() != imp.Type.String():
+			problems = append(problems, fmt.Sprintf("import %q %q: signature %s, expected %s", imp.Module, imp.Name, imp.Type, want))
+		}
+	}
+
+	exports := make(map[string]*wasm.FuncType)
+	for _, exp := range m.Exports {
+		if exp.Kind == wasm.ExternFunc {
+			exports[exp.Name] = exp.Type
+		}
+	}
+	for _, f := range witcli.CoreExports(w) {
+		got, ok := exports[f.Name]
+		if !ok {
+			// Functions exported by a world can also be prefixed with the world name.
+			got, ok = exports[id+"#"+f.Name]
+		}
+		switch {
+		case !ok && !f.Optional:
+			problems = append(problems, fmt.Sprintf("missing export %q %s", f.Name, f.Type))
+		case ok && got.String() != f.Type.String():
+			problems = append(problems, fmt.Sprintf("export %q: signature %s, expected %s", f.Name, got, f.Type))
+		}
+	}
+	return problems
+}
+
+// checkComponent returns a list of problems that prevent component c from
+// targeting world w. Only import and export names are compared.
+func checkComponent(w *wit.World, c *wasm.Component) []string {
+	var imports, exports []string
+	w.Imports.All()(func(name string, item wit.WorldItem) bool {
+		imports = append(imports, witcli.ItemName(name, item))
+		return true
+	})
+	w.Exports.All()(func(name string, item wit.WorldItem) bool {
+		exports = append(exports, witcli.ItemName(name, item))
+		return true
+	})
+
+	var problems []string
+	for _, name := range c.Imports {
+		if !slices.Contains(imports, name) {
+			problems = append(problems, fmt.Sprintf("unknown import %q: not in world %s", name, worldID(w)))
+		}
+	}
+	for _, name := range exports {
+		if !slices.Contains(c.Exports, name) {
+			problems = append(problems, fmt.Sprintf("missing export %q", name))
+		}
+	}
+	return problems
+}
+
+func worldID(w *wit.World) string {
+	id := w.ID()
+	return id.String()
+}
@@ -0,0 +1,105 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Command is the CLI command for embed.
+var Command = &cli.Command{
+	Name:  "embed",
+	Usage: "embed a WIT world in a WebAssembly module as a component-type custom section",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to embed, otherwise the last world",
+		},
+		&cli.StringFlag{
+			Name:      "module",
+			Aliases:   []string{"m"},
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "Core WebAssembly module to embed the world in, otherwise emit a module with only the custom section",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output file, otherwise the module is modified in place",
+		},
+		&cli.StringFlag{
+			Name:     "encoding",
+			Value:    "utf8",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "string encoding used by the module: utf8, utf16, or latin1+utf16",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	module := cmd.String("module")
+	out := cmd.String("out")
+	if out == "" {
+		if module == "" {
+			return errors.New("--out is required without --module")
+		}
+		out = module
+	}
+
+	enc, err := wit.ParseStringEncoding(cmd.String("encoding"))
+	if err != nil {
+		return err
+	}
+
+	res, err := witcli.Load(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	w, err := witcli.FindWorld(res, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+
+	data, err := w.ComponentTypeWithEncoding(enc)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	name := w.ComponentTypeSectionName()
+	if module != "" {
+		in, err := os.ReadFile(module)
+		if err != nil {
+			return err
+		}
+		b, err = wasm.EmbedCustomSection(in, name, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", module, err)
+		}
+	} else {
+		b = wasm.AppendCustomSection(append([]byte{}, wasm.ModuleHeader...), name, data)
+	}
+
+	slog.Info("embedded world", "section", name, "path", out)
+	return os.WriteFile(out, b, 0o644)
+}
@@ -0,0 +1,95 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/witgen"
+)
+
+// Command is the CLI command for extract.
+var Command = &cli.Command{
+	Name:      "extract",
+	Usage:     "synthesize a WIT world from //wit:world and //wit:export directives in a Go package",
+	ArgsUsage: "[dir]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "fully-qualified WIT world name, overriding the //wit:world directive",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output WIT file, otherwise standard output",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	dir := cmd.Args().First()
+	if dir == "" {
+		dir = "."
+	}
+	fset, files, err := parseDir(dir)
+	if err != nil {
+		return err
+	}
+	res, err := witgen.Synthesize(fset, files, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+	out := res.WIT(nil, "")
+	if path := cmd.String("out"); path != "" {
+		return os.WriteFile(path, []byte(out), 0644)
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// parseDir parses the non-test Go files in dir, in name order.
+// Build constraints are ignored, as exports should not vary by platform.
+func parseDir(dir string) (*token.FileSet, []*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	sort.Strings(names)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, f)
+	}
+	return fset, files, nil
+}
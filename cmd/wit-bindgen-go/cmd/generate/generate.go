@@ -3,15 +3,21 @@ package generate
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/urfave/cli/v3"
 	"github.com/ydnar/wasm-tools-go/internal/codec"
 	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/version"
 	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
 	"github.com/ydnar/wasm-tools-go/wit/bindgen"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 // Command is the CLI command for wit.
@@ -43,23 +49,108 @@ var Command = &cli.Command{
 			Value:    "",
 			OnlyOnce: true,
 			Config:   cli.StringConfig{TrimSpace: true},
-			Usage:    "Go package root, e.g. github.com/org/repo/internal",
+			Usage:    "Go package root, e.g. github.com/org/repo/internal, which must match the output directory (default: derived from go.mod)",
 		},
 		&cli.BoolFlag{
 			Name:  "versioned",
 			Usage: "emit versioned Go package(s) for each WIT version",
 		},
+		&cli.StringFlag{
+			Name:     "target",
+			Value:    bindgen.TargetWASIP2,
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "compilation target for generated bindings: wasip2 or wasip1 (via the preview1 adapter)",
+		},
+		&cli.BoolFlag{
+			Name:  "host",
+			Usage: "emit host bindings that implement the imports of a world with primitive, enum, flags, and list types",
+		},
+		&cli.StringFlag{
+			Name:     "host-runtime",
+			Value:    bindgen.HostWazero,
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WebAssembly runtime for host bindings: wazero or wasmtime (wasmtime-go)",
+		},
+		&cli.BoolFlag{
+			Name:  "intern-strings",
+			Usage: "intern strings lifted by host bindings, to reduce allocations when the same strings are lifted repeatedly",
+		},
+		&cli.BoolFlag{
+			Name:  "stubs",
+			Usage: "emit " + bindgen.StubsFile + " with unimplemented stubs for exported functions, unless it exists",
+		},
+		&cli.BoolFlag{
+			Name:  "result-errors",
+			Usage: "also emit functions that return (T, error) for imported functions that return a result",
+		},
+		&cli.BoolFlag{
+			Name:  "option-pointers",
+			Usage: "represent option<T> params and results of functions as *T instead of cm.Option[T]",
+		},
+		&cli.BoolFlag{
+			Name:  "context-params",
+			Usage: "emit a context.Context as the first param of each Go function that calls an imported function",
+		},
+		&cli.StringSliceFlag{
+			Name:  "cache-import",
+			Usage: "cache the result of an imported function after the first call, e.g. wasi:random/insecure-seed#insecure-seed (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "build-tag",
+			Usage: "add a build constraint to the Go files generated for a WIT interface, e.g. wasi:keyvalue/store=keyvalue (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "interfaces",
+			Usage: "emit a Go interface type for each WIT interface, with a client for imports and an adapter for exports",
+		},
+		&cli.BoolFlag{
+			Name:  "fakes",
+			Usage: "emit in-memory fake implementations of imported interfaces for tests (implies --interfaces and --host-shims)",
+		},
+		&cli.BoolFlag{
+			Name:  "host-shims",
+			Usage: "declare imported functions for WebAssembly only, with stubs that panic on other architectures, so host-side tests link",
+		},
+		&cli.BoolFlag{
+			Name:  "layout-tests",
+			Usage: "emit tests that assert the memory layout of generated types",
+		},
+		&cli.BoolFlag{
+			Name:  "record-funcs",
+			Usage: "emit a constructor for each record type, and a Validate method that checks its handles and enum values",
+		},
+		&cli.StringFlag{
+			Name:     "anonymous-types",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "declare type aliases for anonymous types used by functions, named by structure, position, or hash: structural, positional, or hashed",
+		},
+		&cli.StringFlag{
+			Name:     "casing",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "casing of generated Go identifiers: initialisms (default, e.g. HTTPRequestID) or preserve (e.g. HttpRequestId)",
+		},
 		&cli.BoolFlag{
 			Name:  "dry-run",
-			Usage: "do not write files; print to stdout",
+			Usage: "do not write files; print the files that would be created or updated",
+		},
+		&cli.BoolFlag{
+			Name:  "check",
+			Usage: "do not write files; exit with an error if any generated file would change",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print a summary of the generated packages and files to stdout as JSON",
 		},
 	},
 	Action: action,
 }
 
 func action(ctx context.Context, cmd *cli.Command) error {
-	dryRun := cmd.Bool("dry-run")
-
+	start := time.Now()
 	out := cmd.String("out")
 	info, err := os.Stat(out)
 	if err != nil {
@@ -68,83 +159,226 @@ func action(ctx context.Context, cmd *cli.Command) error {
 	if !info.IsDir() {
 		return fmt.Errorf("%s is not a directory", out)
 	}
-	fmt.Fprintf(os.Stderr, "Output dir: %s\n", out)
+	slog.Info("output directory", "path", out)
 	outPerm := info.Mode().Perm()
 
+	// The package root is the Go package path of the output directory, derived
+	// from go.mod, so generated packages can import each other.
 	pkgRoot := cmd.String("package-root")
-	if !cmd.IsSet("package-root") {
-		pkgRoot, err = gen.PackagePath(out)
-		if err != nil {
-			return err
-		}
+	mod, err := gen.FindModule(out)
+	switch {
+	case err != nil && !cmd.IsSet("package-root"):
+		return err
+	case err != nil:
+		slog.Warn("unable to verify package root", "path", pkgRoot, "error", err)
+	case !cmd.IsSet("package-root"):
+		pkgRoot = mod.Package
+	case pkgRoot != mod.Package:
+		return fmt.Errorf("package root %s does not match Go package %s of output directory %s in module %s", pkgRoot, mod.Package, out, mod.Path)
+	}
+	if mod != nil {
+		slog.Debug("Go module", "path", mod.Path, "dir", mod.Dir, "workspace", mod.Workspace)
+	}
+	slog.Info("package root", "path", pkgRoot)
+
+	namer, err := typeNamer(cmd.String("anonymous-types"))
+	if err != nil {
+		return err
 	}
-	fmt.Fprintf(os.Stderr, "Package root: %s\n", pkgRoot)
 
-	res, err := witcli.LoadOne(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	nameCasing, err := casing(cmd.String("casing"))
 	if err != nil {
 		return err
 	}
 
-	packages, err := bindgen.Go(res,
+	buildTags, err := parseBuildTags(cmd.StringSlice("build-tag"))
+	if err != nil {
+		return err
+	}
+
+	res, err := witcli.Load(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	sources, err := witcli.FindSources(cmd.Bool("force-wit"), res, cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	opts := []bindgen.Option{
 		bindgen.GeneratedBy(cmd.Root().Name),
+		bindgen.GeneratorVersion(releaseVersion()),
 		bindgen.World(cmd.String("world")),
 		bindgen.PackageRoot(pkgRoot),
 		bindgen.Versioned(cmd.Bool("versioned")),
-	)
+		bindgen.Host(cmd.Bool("host")),
+		bindgen.HostRuntime(cmd.String("host-runtime")),
+		bindgen.InternStrings(cmd.Bool("intern-strings")),
+		bindgen.Target(cmd.String("target")),
+		bindgen.Stubs(cmd.Bool("stubs")),
+		bindgen.ResultErrors(cmd.Bool("result-errors")),
+		bindgen.ContextParams(cmd.Bool("context-params")),
+		bindgen.OptionPointers(cmd.Bool("option-pointers")),
+		bindgen.CachedImports(cmd.StringSlice("cache-import")...),
+		bindgen.BuildTags(buildTags),
+		bindgen.Interfaces(cmd.Bool("interfaces")),
+		bindgen.Fakes(cmd.Bool("fakes")),
+		bindgen.HostShims(cmd.Bool("host-shims")),
+		bindgen.LayoutTests(cmd.Bool("layout-tests")),
+		bindgen.RecordFuncs(cmd.Bool("record-funcs")),
+		bindgen.Sources(sources),
+		bindgen.Logger(slog.Default()),
+	}
+	if namer != nil {
+		opts = append(opts, bindgen.AnonymousTypes(namer))
+	}
+	if nameCasing != nil {
+		opts = append(opts, bindgen.NameCasing(nameCasing))
+	}
+
+	packages, err := bindgen.Go(res, opts...)
 	if err != nil {
 		return err
 	}
-	fmt.Fprintf(os.Stderr, "Generated %d package(s)\n", len(packages))
+	slog.Info("generated packages", "count", len(packages))
 
+	sum := &summary{
+		WITPackages: len(res.Packages),
+		DryRun:      cmd.Bool("dry-run"),
+		Check:       cmd.Bool("check"),
+	}
+	files := gen.NewFileSet()
 	for _, pkg := range packages {
 		if !pkg.HasContent() {
-			fmt.Fprintf(os.Stderr, "Skipping empty package: %s\n", pkg.Path)
+			slog.Debug("skipping empty package", "package", pkg.Path)
 			continue
 		}
 
-		fmt.Fprintf(os.Stderr, "Generated package: %s\n", pkg.Path)
+		slog.Info("generated package", "package", pkg.Path)
+		sum.GoPackages++
 
 		for _, filename := range codec.SortedKeys(pkg.Files) {
 			file := pkg.Files[filename]
+			path := filepath.ToSlash(filepath.Join(strings.TrimPrefix(file.Package.Path, pkgRoot), file.Name))
+			path = strings.TrimPrefix(path, "/")
 
-			dir := filepath.Join(out, strings.TrimPrefix(file.Package.Path, pkgRoot))
-			err := os.MkdirAll(dir, outPerm)
-			if err != nil {
-				return err
+			// Stubs are edited by the user, so never overwrite them.
+			if file.Name == bindgen.StubsFile {
+				if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(path))); err == nil {
+					slog.Info("skipping existing file", "path", path)
+					sum.Skipped++
+					continue
+				}
 			}
 
-			path := filepath.Join(dir, file.Name)
-
 			b, err := file.Bytes()
 			if err != nil {
-				if b == nil {
-					return err
-				}
-				fmt.Fprintf(os.Stderr, "Error formatting file: %v\n", err)
-			} else {
-				fmt.Fprintf(os.Stderr, "Generated file: %s\n", path)
+				// Generated code that does not parse is a bug in wit-bindgen-go.
+				// Log the unformatted source to help diagnose it.
+				slog.Debug("unformatted source", "path", path, "source", string(b))
+				return fmt.Errorf("%s: generated Go source is invalid: %w", path, err)
 			}
+			slog.Debug("generated file", "path", path)
+			files.Add(path, b)
+		}
+	}
 
-			if dryRun {
-				fmt.Println(string(b))
-				fmt.Println()
-				continue
-			}
+	changes, err := files.Changes(out)
+	if err != nil {
+		return err
+	}
+	sum.addChanges(files, changes)
 
-			f, err := os.Create(path)
-			if err != nil {
-				return err
-			}
-			n, err := f.Write(b)
-			f.Close()
-			if err != nil {
-				return err
-			}
-			if n != len(b) {
-				return fmt.Errorf("wrote %d bytes to %s, expected %d", n, path, len(b))
+	if cmd.Bool("check") {
+		var n int
+		for _, c := range changes {
+			if c.Op != gen.Unchanged {
+				slog.Warn("file out of date", "path", c.Path, "op", c.Op.String())
+				n++
 			}
 		}
+		if err := report(cmd, sum, start); err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%d generated file(s) out of date in %s", n, out)
+		}
+		return nil
 	}
 
+	var w gen.Writer = &gen.DirWriter{Dir: out, Perm: outPerm}
+	if cmd.Bool("dry-run") {
+		w = &gen.DryRunWriter{Dir: out, Out: os.Stdout}
+	}
+	if err := w.WriteFiles(files); err != nil {
+		return err
+	}
+	return report(cmd, sum, start)
+}
+
+// report logs the summary of generate, and prints it as JSON if --json is set.
+func report(cmd *cli.Command, sum *summary, start time.Time) error {
+	sum.finish(start)
+	sum.log()
+	if cmd.Bool("json") {
+		return sum.writeJSON(os.Stdout)
+	}
 	return nil
 }
+
+// releaseVersion returns the version of wit-bindgen-go if it is a release version,
+// otherwise an empty string, so code generated by development builds does not change
+// with each commit.
+func releaseVersion() string {
+	v := version.Read().Version
+	if !semver.IsValid(v) || module.IsPseudoVersion(v) {
+		return ""
+	}
+	return v
+}
+
+// parseBuildTags parses the values of the --build-tag flag, each an interface name
+// and a build constraint expression separated by =, e.g. wasi:keyvalue/store=keyvalue.
+func parseBuildTags(values []string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, v := range values {
+		name, expr, ok := strings.Cut(v, "=")
+		name, expr = strings.TrimSpace(name), strings.TrimSpace(expr)
+		if !ok || name == "" || expr == "" {
+			return nil, fmt.Errorf("invalid build tag %q: expected interface=constraint", v)
+		}
+		tags[name] = expr
+	}
+	return tags, nil
+}
+
+// typeNamer returns the [wit.TypeNamer] for the value of the --anonymous-types flag,
+// or nil if the flag is not set.
+func typeNamer(name string) (wit.TypeNamer, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "structural":
+		return wit.StructuralTypeNames, nil
+	case "positional":
+		return wit.PositionalTypeNames, nil
+	case "hashed":
+		return wit.HashedTypeNames, nil
+	}
+	return nil, fmt.Errorf("unknown anonymous type naming strategy %q", name)
+}
+
+// casing returns the [bindgen.Casing] for the value of the --casing flag,
+// or nil if the flag is not set.
+func casing(name string) (bindgen.Casing, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "initialisms":
+		return bindgen.InitialismCasing, nil
+	case "preserve":
+		return bindgen.PreserveCasing, nil
+	}
+	return nil, fmt.Errorf("unknown casing %q", name)
+}
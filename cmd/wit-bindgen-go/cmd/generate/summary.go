@@ -0,0 +1,81 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+)
+
+// summary describes the result of generate, printed when it completes.
+type summary struct {
+	// WITPackages is the number of WIT packages loaded.
+	WITPackages int `json:"wit_packages"`
+
+	// GoPackages is the number of Go packages generated, excluding empty packages.
+	GoPackages int `json:"go_packages"`
+
+	// Files is the number of files generated. Each is created, updated, or unchanged.
+	Files     int `json:"files"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+
+	// Skipped is the number of existing stub files that were not overwritten.
+	Skipped int `json:"skipped"`
+
+	// Lines is the total number of lines in the generated files.
+	Lines int `json:"lines"`
+
+	// DryRun and Check report whether files were written.
+	DryRun bool `json:"dry_run,omitempty"`
+	Check  bool `json:"check,omitempty"`
+
+	Elapsed   time.Duration `json:"-"`
+	ElapsedMS int64         `json:"elapsed_ms"`
+}
+
+// addChanges counts the files in changes, and the lines in each file in files.
+func (s *summary) addChanges(files *gen.FileSet, changes []gen.Change) {
+	for _, c := range changes {
+		s.Files++
+		switch c.Op {
+		case gen.Create:
+			s.Created++
+		case gen.Update:
+			s.Updated++
+		case gen.Unchanged:
+			s.Unchanged++
+		}
+		content, _ := files.Content(c.Path)
+		s.Lines += bytes.Count(content, []byte{'\n'})
+	}
+}
+
+// finish records the time elapsed since start.
+func (s *summary) finish(start time.Time) {
+	s.Elapsed = time.Since(start).Round(time.Millisecond)
+	s.ElapsedMS = s.Elapsed.Milliseconds()
+}
+
+// log logs s to the default logger.
+func (s *summary) log() {
+	slog.Info("summary",
+		"wit_packages", s.WITPackages,
+		"go_packages", s.GoPackages,
+		"files", s.Files,
+		"created", s.Created,
+		"updated", s.Updated,
+		"unchanged", s.Unchanged,
+		"skipped", s.Skipped,
+		"lines", s.Lines,
+		"elapsed", s.Elapsed)
+}
+
+// writeJSON writes s to w as a single line of JSON.
+func (s *summary) writeJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
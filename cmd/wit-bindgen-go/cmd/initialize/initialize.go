@@ -0,0 +1,114 @@
+package initialize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/scaffold"
+	"github.com/ydnar/wasm-tools-go/internal/version"
+)
+
+// Command is the CLI command for init.
+var Command = &cli.Command{
+	Name:      "init",
+	Usage:     "scaffold a new Go project that builds a WebAssembly component",
+	ArgsUsage: "[directory]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "module",
+			Aliases:  []string{"m"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "Go module path, otherwise example.com/<name>, where name is derived from the directory",
+		},
+		&cli.StringFlag{
+			Name:     "package",
+			Aliases:  []string{"p"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT package name, otherwise example:<name>",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world name, otherwise <name>",
+		},
+		&cli.BoolFlag{
+			Name:  "reactor",
+			Usage: "build a reactor component (TinyGo -buildmode=c-shared) instead of a command",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "do not write files; print to stdout",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() > 1 {
+		return errors.New("expected at most one directory")
+	}
+	dir := cmd.Args().First()
+	if dir == "" {
+		dir = "."
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	name := scaffold.Name(filepath.Base(abs))
+	if name == "" {
+		name = "app"
+	}
+
+	opts := &scaffold.Options{
+		Module:  cmd.String("module"),
+		Package: cmd.String("package"),
+		World:   cmd.String("world"),
+		Version: version.Read().Version,
+		Reactor: cmd.Bool("reactor"),
+	}
+	if opts.Module == "" {
+		opts.Module = "example.com/" + name
+	}
+	if opts.Package == "" {
+		opts.Package = "example:" + name
+	}
+	if opts.World == "" {
+		opts.World = name
+	}
+
+	files, err := scaffold.Files(opts)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("dry-run") {
+		for _, f := range files {
+			fmt.Printf("// %s\n\n%s\n", f.Path, f.Content)
+		}
+		return nil
+	}
+
+	err = scaffold.Write(dir, files)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		slog.Debug("wrote file", "path", filepath.Join(dir, filepath.FromSlash(f.Path)))
+	}
+	slog.Info("initialized project", "path", dir, "module", opts.Module, "world", opts.Package+"/"+opts.World)
+	fmt.Println("Run make to fetch WIT dependencies, generate bindings, and build the component.")
+	fmt.Println("Requires wit-deps, wasm-tools, and TinyGo 0.33 or later.")
+	return nil
+}
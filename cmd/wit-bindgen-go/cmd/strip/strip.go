@@ -0,0 +1,85 @@
+package strip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Command is the CLI command for strip.
+var Command = &cli.Command{
+	Name:      "strip",
+	Usage:     "removes documentation, unstable items, or items unreachable from a world from WIT",
+	ArgsUsage: "<path>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "docs",
+			Usage: "remove documentation",
+		},
+		&cli.BoolFlag{
+			Name:  "unstable",
+			Usage: "remove items gated by @unstable",
+		},
+		&cli.StringSliceFlag{
+			Name:  "features",
+			Usage: "keep @unstable items gated by these features with --unstable (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "remove other worlds, and interfaces and types unreachable from this world",
+		},
+		&cli.BoolFlag{
+			Name:  "nested-packages",
+			Usage: "print multiple packages as a single WIT file using nested package syntax",
+		},
+		&cli.StringFlag{
+			Name:      "out",
+			Aliases:   []string{"o"},
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "output WIT file, otherwise standard output",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	opts := &wit.StripOptions{
+		Docs:     cmd.Bool("docs"),
+		Unstable: cmd.Bool("unstable"),
+		Features: cmd.StringSlice("features"),
+	}
+	if !opts.Docs && !opts.Unstable && !cmd.IsSet("world") {
+		return errors.New("strip: one of --docs, --unstable, or --world is required")
+	}
+	res, err := witcli.Load(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	if cmd.IsSet("world") {
+		opts.World, err = witcli.FindWorld(res, cmd.String("world"))
+		if err != nil {
+			return err
+		}
+	}
+	if err := res.Strip(opts); err != nil {
+		return err
+	}
+	out := res.PrintWIT(&wit.PrintOptions{NestedPackages: cmd.Bool("nested-packages")})
+	if path := cmd.String("out"); path != "" {
+		return os.WriteFile(path, []byte(out), 0644)
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -0,0 +1,29 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/version"
+)
+
+// Command is the CLI command for version.
+var Command = &cli.Command{
+	Name:   "version",
+	Usage:  "print version, build, and WIT compatibility information",
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	w := cmd.Root().Writer
+	fmt.Fprintf(w, "%s %s\n", cmd.Root().Name, version.Read())
+	fmt.Fprintf(w, "WIT JSON: wasm-tools component wit -j, v%s or later\n", version.WasmTools)
+	fmt.Fprintf(w, "WASI: %s\n", strings.Join(version.WASI, ", "))
+	fmt.Fprintln(w, "Targets:")
+	for _, t := range version.Targets {
+		fmt.Fprintf(w, "  %s: %s\n", t.Name, t.ABI)
+	}
+	return nil
+}
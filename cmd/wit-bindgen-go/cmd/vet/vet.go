@@ -0,0 +1,85 @@
+package vet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/internal/witvet"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Command is the CLI command for vet.
+var Command = &cli.Command{
+	Name:      "vet",
+	Usage:     "check Go packages for edited generated files, wasmimport functions that do not match a WIT world, and unsafe pointer passing",
+	ArgsUsage: "[packages]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "wit",
+			Value:     "",
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "WIT or WIT JSON to check go:wasmimport functions against",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to check go:wasmimport functions against, otherwise the last world",
+		},
+		&cli.StringFlag{
+			Name:     "tags",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "comma-separated list of Go build tags used to select files",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	var w *wit.World
+	if path := cmd.String("wit"); path != "" {
+		res, err := witcli.LoadOne(cmd.Bool("force-wit"), path)
+		if err != nil {
+			return err
+		}
+		w, err = witcli.FindWorld(res, cmd.String("world"))
+		if err != nil {
+			return err
+		}
+	}
+
+	patterns := cmd.Args().Slice()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	fset, pkgs, err := witvet.Load(ctx, "", cmd.String("tags"), patterns)
+	if err != nil {
+		return err
+	}
+
+	findings, vetted := witvet.Vet(fset, w, pkgs)
+	wd, _ := os.Getwd()
+	for _, f := range findings {
+		pos := f.Pos
+		if rel, err := filepath.Rel(wd, pos.Filename); err == nil {
+			pos.Filename = rel
+		}
+		fmt.Printf("%s: %s\n", pos, f.Message)
+	}
+	if len(findings) > 0 {
+		return fmt.Errorf("vet: %d problem(s)", len(findings))
+	}
+	slog.Info("no problems found", "packages", vetted)
+	return nil
+}
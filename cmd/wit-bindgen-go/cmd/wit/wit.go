@@ -6,20 +6,95 @@ import (
 
 	"github.com/urfave/cli/v3"
 	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
 )
 
 // Command is the CLI command for wit.
 var Command = &cli.Command{
-	Name:   "wit",
-	Usage:  "reverses a WIT JSON file into WIT syntax",
+	Name:  "wit",
+	Usage: "reverses one or more WIT JSON files into WIT syntax",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "nested-packages",
+			Usage: "print multiple packages as a single WIT file using nested package syntax",
+		},
+		&cli.BoolFlag{
+			Name:  "elide-versions",
+			Usage: "omit package versions where unambiguous",
+		},
+		&cli.IntFlag{
+			Name:  "max-width",
+			Usage: "maximum width of a record, flags, variant, or enum printed on a single line (0 for default, -1 to never)",
+		},
+		&cli.IntFlag{
+			Name:  "max-lines",
+			Usage: "maximum number of lines of a record, flags, variant, or enum printed on a single line (0 for default)",
+		},
+		&cli.BoolFlag{
+			Name:  "expand-records",
+			Usage: "print each record with one field per line",
+		},
+		&cli.BoolFlag{
+			Name:  "compact-enums",
+			Usage: "print each enum without docs on a single line",
+		},
+		&cli.BoolFlag{
+			Name:  "normalize",
+			Usage: "print a normal form for diffing: strip docs, sort by name, and expand local type aliases",
+		},
+		&cli.StringFlag{
+			Name:     "wit-version",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "print WIT syntax for older or newer tools: pre-resources, 0.2.0 (no stability gates or nested packages), 0.2.1, 0.3 (streams without end types), or latest",
+		},
+	},
 	Action: action,
 }
 
 func action(ctx context.Context, cmd *cli.Command) error {
-	res, err := witcli.LoadOne(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	syntax, err := witSyntax(cmd.String("wit-version"))
 	if err != nil {
 		return err
 	}
-	fmt.Println(res.WIT(nil, ""))
+	if cmd.Bool("nested-packages") && !syntax.AtLeast(wit.SyntaxGates) {
+		return fmt.Errorf("nested packages are not supported by WIT %s syntax", syntax)
+	}
+	res, err := witcli.Load(cmd.Bool("force-wit"), cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	if err := res.CheckSyntax(syntax); err != nil {
+		return err
+	}
+	if cmd.Bool("normalize") {
+		res.Normalize()
+	}
+	fmt.Println(res.PrintWIT(&wit.PrintOptions{
+		NestedPackages: cmd.Bool("nested-packages"),
+		ElideVersions:  cmd.Bool("elide-versions"),
+		MaxWidth:       int(cmd.Int("max-width")),
+		MaxLines:       int(cmd.Int("max-lines")),
+		ExpandRecords:  cmd.Bool("expand-records"),
+		CompactEnums:   cmd.Bool("compact-enums"),
+		Syntax:         syntax,
+	}))
 	return nil
 }
+
+// witSyntax returns the [wit.Syntax] for the value of the --wit-version flag.
+func witSyntax(name string) (wit.Syntax, error) {
+	switch name {
+	case "", "latest":
+		return wit.SyntaxLatest, nil
+	case "pre-resources":
+		return wit.SyntaxPreResources, nil
+	case "0.2.0":
+		return wit.SyntaxResources, nil
+	case "0.2.1":
+		return wit.SyntaxGates, nil
+	case "0.3":
+		return wit.SyntaxAsync, nil
+	}
+	return 0, fmt.Errorf("unknown WIT syntax version %q", name)
+}
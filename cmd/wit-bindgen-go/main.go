@@ -3,27 +3,86 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/describe"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/embed"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/extract"
 	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/generate"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/initialize"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/strip"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/version"
+	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/vet"
 	"github.com/ydnar/wasm-tools-go/cmd/wit-bindgen-go/cmd/wit"
+	internalversion "github.com/ydnar/wasm-tools-go/internal/version"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
 )
 
 func main() {
+	// -v is short for --verbose.
+	cli.VersionFlag = &cli.BoolFlag{
+		Name:  "version",
+		Usage: "print the version",
+	}
+
 	cmd := &cli.Command{
-		Name:  "wit-bindgen-go",
-		Usage: "inspect or manipulate WebAssembly Interface Types for Go",
+		Name:    "wit-bindgen-go",
+		Usage:   "inspect or manipulate WebAssembly Interface Types for Go",
+		Version: internalversion.Read().Version,
 		Commands: []*cli.Command{
 			generate.Command,
 			wit.Command,
+			embed.Command,
+			describe.Command,
+			vet.Command,
+			extract.Command,
+			strip.Command,
+			initialize.Command,
+			version.Command,
 		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "force-wit",
 				Usage: "force loading WIT via wasm-tools",
 			},
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "do not cache WIT processed by wasm-tools in the user cache directory",
+			},
+			&cli.BoolFlag{
+				Name:    "verbose",
+				Aliases: []string{"v"},
+				Usage:   "log debug messages",
+			},
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "log only warnings and errors",
+			},
+			&cli.StringFlag{
+				Name:     "log-format",
+				Value:    witcli.LogFormatText,
+				OnlyOnce: true,
+				Config:   cli.StringConfig{TrimSpace: true},
+				Usage:    "log format: text or json",
+			},
+		},
+		Before: func(ctx context.Context, cmd *cli.Command) error {
+			logger, err := witcli.NewLogger(os.Stderr, cmd.String("log-format"), cmd.Bool("verbose"), cmd.Bool("quiet"))
+			if err != nil {
+				return err
+			}
+			slog.SetDefault(logger)
+			if !cmd.Bool("no-cache") {
+				witcli.CacheDir, err = witcli.DefaultCacheDir()
+				if err != nil {
+					slog.Debug("WIT cache disabled", "error", err)
+				}
+			}
+			return nil
 		},
 	}
 
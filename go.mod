@@ -3,8 +3,10 @@ module github.com/ydnar/wasm-tools-go
 go 1.22
 
 require (
+	github.com/bytecodealliance/wasmtime-go/v22 v22.0.0
 	github.com/coreos/go-semver v0.3.1
 	github.com/sergi/go-diff v1.3.1
+	github.com/tetratelabs/wazero v1.7.0
 	github.com/urfave/cli/v3 v3.0.0-alpha9
 	golang.org/x/mod v0.17.0
 	golang.org/x/tools v0.21.0
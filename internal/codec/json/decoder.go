@@ -22,17 +22,36 @@ func NewDecoder(r io.Reader, resolvers ...codec.Resolver) *Decoder {
 	}
 }
 
+// Decode decodes the next JSON value from the input into v.
+// Empty input is not an error. Input that ends inside an object or array
+// returns [io.ErrUnexpectedEOF].
 func (dec *Decoder) Decode(v any) error {
+	err := dec.decode(v)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// decode decodes the next JSON value into v, returning io.EOF if the input is empty.
+func (dec *Decoder) decode(v any) error {
 	if c := dec.r.ResolveCodec(v); c != nil {
 		v = c
 	}
+	return dec.decodeToken(v)
+}
 
-	err := dec.decodeToken(v)
-	if err != nil && err != io.EOF {
-		return err
-	}
+// decodeNested decodes a JSON value nested in an object or array into v.
+func (dec *Decoder) decodeNested(v any) error {
+	return unexpectedEOF(dec.decode(v))
+}
 
-	return nil
+// unexpectedEOF returns [io.ErrUnexpectedEOF] if err is io.EOF, otherwise err.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
 }
 
 func (dec *Decoder) decodeToken(v any) error {
@@ -76,7 +95,7 @@ func (dec *Decoder) decodeObject(o any) error {
 	for dec.dec.More() {
 		name, err := dec.stringToken()
 		if err != nil {
-			return err
+			return unexpectedEOF(err)
 		}
 		fdec := &onceDecoder{Decoder: dec}
 		err = d.DecodeField(fdec, name)
@@ -84,7 +103,7 @@ func (dec *Decoder) decodeObject(o any) error {
 			return err
 		}
 		if fdec.calls == 0 {
-			err = dec.Decode(nil)
+			err = dec.decodeNested(nil)
 			if err != nil {
 				return err
 			}
@@ -93,7 +112,7 @@ func (dec *Decoder) decodeObject(o any) error {
 
 	tok, err := dec.dec.Token()
 	if err != nil {
-		return err
+		return unexpectedEOF(err)
 	}
 	if tok != json.Delim('}') {
 		return fmt.Errorf("unexpected JSON token %v at offset %d", tok, dec.dec.InputOffset())
@@ -117,7 +136,7 @@ func (dec *Decoder) decodeArray(v any) error {
 			return err
 		}
 		if edec.calls == 0 {
-			err = dec.Decode(nil)
+			err = dec.decodeNested(nil)
 			if err != nil {
 				return err
 			}
@@ -126,7 +145,7 @@ func (dec *Decoder) decodeArray(v any) error {
 
 	tok, err := dec.dec.Token()
 	if err != nil {
-		return err
+		return unexpectedEOF(err)
 	}
 	if tok != json.Delim(']') {
 		return fmt.Errorf("unexpected JSON token %v at offset %d", tok, dec.dec.InputOffset())
@@ -157,7 +176,7 @@ func (dec *onceDecoder) Decode(v any) error {
 	if dec.calls > 1 {
 		return fmt.Errorf("unexpected call to Decode (%d > 1)", dec.calls)
 	}
-	return dec.Decoder.Decode(v)
+	return dec.Decoder.decodeNested(v)
 }
 
 type ignore struct{}
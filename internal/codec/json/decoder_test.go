@@ -0,0 +1,36 @@
+package json
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTruncated(t *testing.T) {
+	tests := []struct {
+		json      string
+		truncated bool
+	}{
+		{``, false},
+		{`{}`, false},
+		{`{"a": [1, {"b": "c"}]}`, false},
+		{`{`, true},
+		{`{"a"`, true},
+		{`{"a": `, true},
+		{`{"a": [`, true},
+		{`{"a": [1, `, true},
+		{`{"a": [1]`, true},
+		{`[{"b": "c"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			err := NewDecoder(strings.NewReader(tt.json)).Decode(nil)
+			switch {
+			case !tt.truncated && err != nil:
+				t.Errorf("Decode(%q): %v, expected nil error", tt.json, err)
+			case tt.truncated && (err == nil || err == io.EOF):
+				t.Errorf("Decode(%q): %v, expected an error for truncated input", tt.json, err)
+			}
+		})
+	}
+}
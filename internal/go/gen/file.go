@@ -2,8 +2,9 @@ package gen
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"go/format"
 	"strings"
 
 	"github.com/ydnar/wasm-tools-go/internal/codec"
@@ -19,6 +20,17 @@ type File struct {
 	// Leave empty to omit the "Code generated by ..." header.
 	GeneratedBy string
 
+	// Source optionally describes the input this file was generated from,
+	// e.g. a WIT world and the version of the generator.
+	// It is serialized as a "Source: ..." comment following the "Code generated by ..." header.
+	// Ignored if GeneratedBy is empty.
+	Source string
+
+	// Checksum, if true, adds a "Checksum: ..." comment to the header with the SHA-256 hash
+	// of the contents following the header, so edits to the generated file can be detected
+	// with [VerifyChecksum]. Ignored if GeneratedBy is empty.
+	Checksum bool
+
 	// Build contains build tags, serialized as //go:build ...
 	// Ignored if this is not a Go file.
 	Build string
@@ -74,7 +86,13 @@ func (f *File) Bytes() ([]byte, error) {
 
 	if f.GeneratedBy != "" {
 		b.WriteString(fmt.Sprintf(HeaderPattern, f.GeneratedBy))
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		if f.Source != "" {
+			b.WriteString("// Source: ")
+			b.WriteString(f.Source)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 	}
 
 	if f.Build != "" {
@@ -98,13 +116,59 @@ func (f *File) Bytes() ([]byte, error) {
 	b.Write(f.Content)
 
 	unformatted := b.Bytes()
-	formatted, err := format.Source(unformatted)
+	formatted, err := FixImports(f.Name, unformatted)
 	if err != nil {
 		return unformatted, fmt.Errorf("error in %s: %w", f.Name, err)
 	}
+	if f.GeneratedBy != "" && f.Checksum {
+		formatted = addChecksum(formatted)
+	}
 	return formatted, nil
 }
 
+// ChecksumPrefix begins the header comment with the checksum of a generated file.
+const ChecksumPrefix = "// Checksum: sha256:"
+
+// addChecksum adds a checksum comment to the end of the header of src,
+// which ends with the first blank line.
+func addChecksum(src []byte) []byte {
+	i := bytes.Index(src, []byte("\n\n"))
+	if i < 0 {
+		return src
+	}
+	header, body := src[:i+1], src[i+2:]
+	sum := sha256.Sum256(body)
+	var b bytes.Buffer
+	b.Grow(len(src) + len(ChecksumPrefix) + 2*len(sum) + 1)
+	b.Write(header)
+	b.WriteString(ChecksumPrefix)
+	b.WriteString(hex.EncodeToString(sum[:]))
+	b.WriteString("\n\n")
+	b.Write(body)
+	return b.Bytes()
+}
+
+// VerifyChecksum verifies the checksum comment in the header of generated Go file src.
+// It returns found == false if the header does not contain a checksum, and ok == false
+// if the contents following the header do not match the checksum, for example if the
+// file was edited after it was generated.
+func VerifyChecksum(src []byte) (found, ok bool) {
+	i := bytes.Index(src, []byte("\n\n"))
+	if i < 0 {
+		return false, false
+	}
+	header, body := src[:i+1], src[i+2:]
+	for _, line := range strings.SplitAfter(string(header), "\n") {
+		want, isChecksum := strings.CutPrefix(strings.TrimSpace(line), ChecksumPrefix)
+		if !isChecksum {
+			continue
+		}
+		sum := sha256.Sum256(body)
+		return true, want == hex.EncodeToString(sum[:])
+	}
+	return false, false
+}
+
 // DeclareName adds a package-scoped identifier to [File] f.
 // It additionally checks the file-scoped declarations (local package names).
 // It returns the package-unique name (which may be different than name).
@@ -1,6 +1,7 @@
 package gen
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -18,6 +19,69 @@ func TestFileBytes(t *testing.T) {
 	}
 }
 
+func TestFileBytesHeader(t *testing.T) {
+	pkg := NewPackage("wasm/wasi/clocks/wallclock")
+	f := pkg.File("wallclock.wit.go")
+	f.GeneratedBy = "wit-bindgen-go"
+	f.Source = "WIT world wasi:clocks/imports@0.2.0"
+	f.Import("encoding/json")
+	io := f.Import("io")
+	f.Write([]byte("var _ " + io + ".Reader\n"))
+	b, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	want := "// Code generated by wit-bindgen-go. DO NOT EDIT.\n// Source: WIT world wasi:clocks/imports@0.2.0\n\npackage wallclock\n"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("Bytes(): expected prefix %q, got:\n%s", want, got)
+	}
+	if strings.Contains(got, "encoding/json") {
+		t.Errorf("Bytes(): expected unused import to be removed, got:\n%s", got)
+	}
+	if !strings.Contains(got, `"io"`) {
+		t.Errorf("Bytes(): expected import of io, got:\n%s", got)
+	}
+
+	f.Write([]byte("func {\n"))
+	_, err = f.Bytes()
+	if err == nil {
+		t.Errorf("Bytes(): expected error for invalid Go source")
+	}
+}
+
+func TestFileChecksum(t *testing.T) {
+	pkg := NewPackage("wasm/wasi/clocks/wallclock")
+	f := pkg.File("wallclock.wit.go")
+	f.GeneratedBy = "wit-bindgen-go"
+	f.Source = "WIT world wasi:clocks/imports@0.2.0"
+	f.Checksum = true
+	f.Write([]byte("var x = 1\n"))
+	b, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := "// Code generated by wit-bindgen-go. DO NOT EDIT.\n// Source: WIT world wasi:clocks/imports@0.2.0\n" + ChecksumPrefix
+	if !strings.HasPrefix(string(b), prefix) {
+		t.Errorf("Bytes(): expected prefix %q, got:\n%s", prefix, b)
+	}
+	if found, ok := VerifyChecksum(b); !found || !ok {
+		t.Errorf("VerifyChecksum(): found=%t ok=%t, expected true, true", found, ok)
+	}
+	edited := []byte(strings.Replace(string(b), "x = 1", "x = 2", 1))
+	if found, ok := VerifyChecksum(edited); !found || ok {
+		t.Errorf("VerifyChecksum(edited): found=%t ok=%t, expected true, false", found, ok)
+	}
+	f.Checksum = false
+	b, err = f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, _ := VerifyChecksum(b); found {
+		t.Errorf("VerifyChecksum(): found checksum in file without Checksum set:\n%s", b)
+	}
+}
+
 func TestFileAddImport(t *testing.T) {
 	pkg := NewPackage("wasm/wasi/clocks/wallclock")
 	f := pkg.File("wallclock.wit.go")
@@ -0,0 +1,250 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// FileSet is a set of generated files, keyed by slash-separated path
+// relative to an output directory.
+type FileSet struct {
+	files map[string][]byte
+}
+
+// NewFileSet returns a new, empty [FileSet].
+func NewFileSet() *FileSet {
+	return &FileSet{files: make(map[string][]byte)}
+}
+
+// Add adds a file with path and content to set, replacing any existing file with the same path.
+func (set *FileSet) Add(path string, content []byte) {
+	set.files[path] = content
+}
+
+// Content returns the content of the file at path, and true if set contains path.
+func (set *FileSet) Content(path string) ([]byte, bool) {
+	content, ok := set.files[path]
+	return content, ok
+}
+
+// Paths returns the paths of the files in set, in sorted order.
+func (set *FileSet) Paths() []string {
+	paths := make([]string, 0, len(set.files))
+	for path := range set.files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// Len returns the number of files in set.
+func (set *FileSet) Len() int {
+	return len(set.files)
+}
+
+// Op describes how writing a file would change an output directory.
+type Op int
+
+const (
+	// Unchanged indicates the file exists with the same content.
+	Unchanged Op = iota
+
+	// Create indicates the file does not exist.
+	Create
+
+	// Update indicates the file exists with different content.
+	Update
+)
+
+// String implements [fmt.Stringer].
+func (op Op) String() string {
+	switch op {
+	case Unchanged:
+		return "unchanged"
+	case Create:
+		return "create"
+	case Update:
+		return "update"
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Change describes how writing a single file in a [FileSet] would change an output directory.
+type Change struct {
+	Path string
+	Op   Op
+}
+
+// Changes compares the files in set to the files in directory dir,
+// and returns a [Change] for each file in set, in sorted order.
+func (set *FileSet) Changes(dir string) ([]Change, error) {
+	var changes []Change
+	for _, path := range set.Paths() {
+		existing, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			changes = append(changes, Change{path, Create})
+		case err != nil:
+			return nil, err
+		case bytes.Equal(existing, set.files[path]):
+			changes = append(changes, Change{path, Unchanged})
+		default:
+			changes = append(changes, Change{path, Update})
+		}
+	}
+	return changes, nil
+}
+
+// Writer writes the files in a [FileSet].
+type Writer interface {
+	WriteFiles(files *FileSet) error
+}
+
+// MemoryWriter is a [Writer] that stores files in memory, keyed by path.
+// It is useful for tests.
+type MemoryWriter map[string][]byte
+
+// WriteFiles implements the [Writer] interface.
+func (w MemoryWriter) WriteFiles(files *FileSet) error {
+	for _, path := range files.Paths() {
+		w[path], _ = files.Content(path)
+	}
+	return nil
+}
+
+// DryRunWriter is a [Writer] that does not write files. Instead, it writes
+// a summary of the files in directory Dir that would be created or updated to Out,
+// one per line, e.g. "create foo/foo.wit.go".
+type DryRunWriter struct {
+	Dir string
+	Out io.Writer
+}
+
+// WriteFiles implements the [Writer] interface.
+func (w *DryRunWriter) WriteFiles(files *FileSet) error {
+	changes, err := files.Changes(w.Dir)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if c.Op == Unchanged {
+			continue
+		}
+		_, err := fmt.Fprintln(w.Out, c.Op, filepath.Join(w.Dir, filepath.FromSlash(c.Path)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DirWriter is a [Writer] that writes files to directory Dir.
+// Files are written atomically: each file is written to a temporary file,
+// then renamed into place after all files are written. If an error occurs,
+// files already renamed are restored to their previous content, or removed,
+// and no changes are left in Dir, other than any directories created.
+// Unchanged files are not rewritten.
+type DirWriter struct {
+	Dir string
+
+	// Perm is the permission used to create directories. Default: 0o755.
+	Perm fs.FileMode
+}
+
+// WriteFiles implements the [Writer] interface.
+func (w *DirWriter) WriteFiles(files *FileSet) (err error) {
+	changes, err := files.Changes(w.Dir)
+	if err != nil {
+		return err
+	}
+	perm := w.Perm
+	if perm == 0 {
+		perm = 0o755
+	}
+
+	type pending struct {
+		path string // destination path
+		tmp  string // temporary file path
+		prev []byte // previous content, if updated
+		op   Op
+		done bool // renamed into place
+	}
+	var writes []*pending
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Roll back in reverse order.
+		for i := len(writes) - 1; i >= 0; i-- {
+			p := writes[i]
+			switch {
+			case !p.done:
+				os.Remove(p.tmp)
+			case p.op == Create:
+				os.Remove(p.path)
+			case p.op == Update:
+				os.WriteFile(p.path, p.prev, 0o644)
+			}
+		}
+	}()
+
+	for _, c := range changes {
+		if c.Op == Unchanged {
+			continue
+		}
+		path := filepath.Join(w.Dir, filepath.FromSlash(c.Path))
+		p := &pending{path: path, op: c.Op}
+		if c.Op == Update {
+			p.prev, err = os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+		}
+		err = os.MkdirAll(filepath.Dir(path), perm)
+		if err != nil {
+			return err
+		}
+		content, _ := files.Content(c.Path)
+		p.tmp, err = writeTemp(path, content)
+		if err != nil {
+			return err
+		}
+		writes = append(writes, p)
+	}
+
+	for _, p := range writes {
+		err = os.Rename(p.tmp, p.path)
+		if err != nil {
+			return err
+		}
+		p.done = true
+	}
+	return nil
+}
+
+// writeTemp writes content to a new temporary file in the same directory as path,
+// returning the path of the temporary file.
+func writeTemp(path string, content []byte) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Chmod(0o644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
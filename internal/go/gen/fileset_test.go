@@ -0,0 +1,122 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileSetChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "same.go", "same")
+	writeFile(t, dir, "pkg/changed.go", "old")
+
+	set := NewFileSet()
+	set.Add("same.go", []byte("same"))
+	set.Add("pkg/changed.go", []byte("new"))
+	set.Add("pkg/new.go", []byte("new"))
+
+	got, err := set.Changes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{{"pkg/changed.go", Update}, {"pkg/new.go", Create}, {"same.go", Unchanged}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes(): %v, expected %v", got, want)
+	}
+
+	var b bytes.Buffer
+	err = (&DryRunWriter{Dir: dir, Out: &b}).WriteFiles(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOut := "update " + filepath.Join(dir, "pkg", "changed.go") + "\n" +
+		"create " + filepath.Join(dir, "pkg", "new.go") + "\n"
+	if got := b.String(); got != wantOut {
+		t.Errorf("DryRunWriter: %q, expected %q", got, wantOut)
+	}
+
+	mem := MemoryWriter{}
+	err = mem.WriteFiles(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mem) != set.Len() || string(mem["pkg/new.go"]) != "new" {
+		t.Errorf("MemoryWriter: %v", mem)
+	}
+}
+
+func TestDirWriter(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "pkg/changed.go", "old")
+
+	set := NewFileSet()
+	set.Add("pkg/changed.go", []byte("new"))
+	set.Add("pkg/sub/new.go", []byte("new"))
+	err := (&DirWriter{Dir: dir}).WriteFiles(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range set.Paths() {
+		if got := readFile(t, dir, path); got != "new" {
+			t.Errorf("%s: %q, expected %q", path, got, "new")
+		}
+	}
+	checkNoTempFiles(t, dir)
+}
+
+func TestDirWriterRollback(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", "old")
+
+	// Writing x/y creates directory x, so renaming the temporary file for x fails
+	// after a.go and b.go are renamed into place.
+	set := NewFileSet()
+	set.Add("a.go", []byte("new"))
+	set.Add("b.go", []byte("new"))
+	set.Add("x", []byte("file"))
+	set.Add("x/y", []byte("file"))
+	err := (&DirWriter{Dir: dir}).WriteFiles(set)
+	if err == nil {
+		t.Fatal("WriteFiles: expected error")
+	}
+	if got := readFile(t, dir, "a.go"); got != "old" {
+		t.Errorf("a.go: %q, expected %q after rollback", got, "old")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.go")); err == nil {
+		t.Errorf("b.go: exists after rollback")
+	}
+	checkNoTempFiles(t, dir)
+}
+
+func writeFile(t *testing.T, dir, path, content string) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, dir, path string) string {
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func checkNoTempFiles(t *testing.T, dir string) {
+	filepath.Walk(dir, func(path string, _ os.FileInfo, err error) error {
+		if err == nil && strings.HasSuffix(path, ".tmp") {
+			t.Errorf("temporary file not removed: %s", path)
+		}
+		return err
+	})
+}
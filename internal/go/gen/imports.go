@@ -0,0 +1,49 @@
+package gen
+
+import (
+	"bytes"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// FixImports parses Go source src, removes unused imports, and returns the formatted source.
+// It returns an error if src is not valid Go source.
+func FixImports(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	type importSpec struct{ name, path string }
+	var unused []importSpec
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		var name string
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch name {
+		case "_", ".":
+			continue
+		}
+		if !astutil.UsesImport(f, path) {
+			unused = append(unused, importSpec{name, path})
+		}
+	}
+	for _, u := range unused {
+		astutil.DeleteNamedImport(fset, f, u.name, u.path)
+	}
+	var b bytes.Buffer
+	err = format.Node(&b, fset, f)
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
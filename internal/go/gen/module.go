@@ -3,72 +3,137 @@ package gen
 import (
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 
 	"github.com/ydnar/wasm-tools-go/internal/relpath"
 	"golang.org/x/mod/modfile"
 )
 
+// Module describes the Go module that contains a directory.
+type Module struct {
+	// Path is the module path declared in go.mod, e.g. example.com/hello.
+	Path string
+
+	// Dir is the absolute path of the directory that contains go.mod.
+	Dir string
+
+	// Package is the Go package path of the directory, e.g. example.com/hello/internal.
+	Package string
+
+	// Workspace is the path of the go.work file that uses the module, if any.
+	Workspace string
+}
+
 // PackagePath returns the Go module path and optional package directory path(s)
 // for the given directory path dir. Returns an error if dir or its parent directories
 // do not contain a go.mod file.
 func PackagePath(dir string) (string, error) {
-	dir, err := relpath.Abs(dir)
+	mod, err := FindModule(dir)
 	if err != nil {
 		return "", err
 	}
+	return mod.Package, nil
+}
+
+// FindModule returns the [Module] that contains directory dir, found by searching dir
+// and its parent directories for a go.mod file. Returns an error if no go.mod file is found.
+//
+// If a go.work file is found in the module directory or its parents, or is set by the
+// GOWORK environment variable, FindModule returns an error if the workspace does not
+// use the module, as packages in the module could not be imported by the workspace.
+// Setting GOWORK=off disables workspace checks.
+func FindModule(dir string) (*Module, error) {
+	dir, err := relpath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
 	info, err := os.Stat(dir)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !info.IsDir() {
-		return "", fmt.Errorf("not a directory: %s", dir)
+		return nil, fmt.Errorf("not a directory: %s", dir)
 	}
 
-	var file string
-	var subdirs string
-	for {
-		// Find a go.mod file in dir
-		file = filepath.Join(dir, "go.mod")
-		info, err := os.Stat(file)
-		if err != nil {
-			if dir == "/" {
-				return "", errors.New("unable to locate a go.mod file")
-			}
-			// Pop up to parent dir
-			var rest string
-			dir, rest = filepath.Split(dir)
-			if dir == "" {
-				return "", errors.New("unable to locate a go.mod file")
-			}
-			dir = filepath.Clean(dir)
-			subdirs = path.Join(rest, subdirs)
-			continue
-		}
-		if info.IsDir() {
-			return "", fmt.Errorf("unexpected directory: %s", file)
-		}
-		break
+	file, subdirs, ok := findUp(dir, "go.mod")
+	if !ok {
+		return nil, errors.New("unable to locate a go.mod file")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	modpath := modfile.ModulePath(data)
+	if modpath == "" {
+		return nil, fmt.Errorf("no module path in %s", file)
+	}
+	mod := &Module{
+		Path:    modpath,
+		Dir:     filepath.Dir(file),
+		Package: path.Join(modpath, subdirs),
 	}
 
-	// Read the go.mod file
-	f, err := os.Open(file)
+	mod.Workspace, err = findWorkspace(mod.Dir)
+	if err != nil || mod.Workspace == "" {
+		return mod, err
+	}
+	data, err = os.ReadFile(mod.Workspace)
 	if err != nil {
-		return "", fmt.Errorf("unable to open %s", file)
+		return nil, err
 	}
-	mod, err := io.ReadAll(f)
-	f.Close()
+	work, err := modfile.ParseWork(mod.Workspace, data, nil)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	workDir := filepath.Dir(mod.Workspace)
+	used := slices.ContainsFunc(work.Use, func(u *modfile.Use) bool {
+		dir := filepath.FromSlash(u.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+		return filepath.Clean(dir) == mod.Dir
+	})
+	if !used {
+		return nil, fmt.Errorf("module %s in %s is not used by workspace %s (run go work use)", mod.Path, mod.Dir, mod.Workspace)
+	}
+	return mod, nil
+}
+
+// findUp searches dir and its parent directories for a file named name.
+// It returns the path of the file and the slash-separated path of dir relative
+// to the directory containing it.
+func findUp(dir, name string) (file, subdirs string, ok bool) {
+	for {
+		file = filepath.Join(dir, name)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, subdirs, true
+		}
+		parent, rest := filepath.Split(dir)
+		parent = filepath.Clean(parent)
+		if rest == "" || parent == dir {
+			return "", "", false
+		}
+		dir = parent
+		subdirs = path.Join(rest, subdirs)
 	}
+}
 
-	// Parse it
-	modpath := modfile.ModulePath(mod)
-	if modpath == "" {
-		return "", fmt.Errorf("no module path in %s", file)
+// findWorkspace returns the path of the go.work file for the module in dir, following
+// the rules of the go command: GOWORK if set, otherwise the first go.work file in dir
+// or its parent directories. It returns an empty path if there is no workspace.
+func findWorkspace(dir string) (string, error) {
+	switch gowork := os.Getenv("GOWORK"); {
+	case gowork == "off":
+		return "", nil
+	case gowork != "":
+		if !filepath.IsAbs(gowork) {
+			return "", fmt.Errorf("GOWORK must be an absolute path: %s", gowork)
+		}
+		return gowork, nil
 	}
-	return path.Join(modpath, subdirs), nil
+	file, _, _ := findUp(dir, "go.work")
+	return file, nil
 }
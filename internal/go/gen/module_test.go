@@ -2,6 +2,9 @@ package gen
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ydnar/wasm-tools-go/internal/relpath"
@@ -28,3 +31,56 @@ func TestPackagePath(t *testing.T) {
 		t.Errorf("PackagePath(%q): expected error, got nil", tmp)
 	}
 }
+
+func TestFindModule(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("app/go.mod", "module example.com/app\n")
+	write("app/internal/wasi/.keep", "")
+	write("lib/go.mod", "module example.com/lib\n")
+
+	mod, err := FindModule(filepath.Join(root, "app", "internal", "wasi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Module{Path: "example.com/app", Dir: filepath.Join(root, "app"), Package: "example.com/app/internal/wasi"}
+	if !reflect.DeepEqual(mod, want) {
+		t.Errorf("FindModule: %+v, expected %+v", mod, want)
+	}
+
+	// A workspace must use the module.
+	write("go.work", "go 1.22\n\nuse ./app\n")
+	mod, err = FindModule(filepath.Join(root, "app", "internal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "go.work"); mod.Workspace != want {
+		t.Errorf("FindModule: Workspace %q, expected %q", mod.Workspace, want)
+	}
+	if mod.Package != "example.com/app/internal" {
+		t.Errorf("FindModule: Package %q, expected example.com/app/internal", mod.Package)
+	}
+	_, err = FindModule(filepath.Join(root, "lib"))
+	if err == nil || !strings.Contains(err.Error(), "not used by workspace") {
+		t.Errorf("FindModule: %v, expected module not used by workspace", err)
+	}
+
+	t.Setenv("GOWORK", "off")
+	if _, err = FindModule(filepath.Join(root, "lib")); err != nil {
+		t.Errorf("FindModule with GOWORK=off: %v", err)
+	}
+
+	if _, err = FindModule(root); err == nil {
+		t.Errorf("FindModule(%q): nil error, expected no go.mod error", root)
+	}
+}
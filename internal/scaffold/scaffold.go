@@ -0,0 +1,265 @@
+// Package scaffold creates the files of a new Go project that builds a WebAssembly component.
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/ydnar/wasm-tools-go/internal/version"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Options describe a new project.
+type Options struct {
+	// Module is the Go module path, e.g. example.com/hello.
+	Module string
+
+	// Package is the WIT package name, e.g. example:hello.
+	Package string
+
+	// World is the name of the WIT world implemented by the component, e.g. hello.
+	World string
+
+	// Version is the version of the wasm-tools-go module required in go.mod.
+	// If it is not a semantic version, e.g. (devel), go.mod does not require it.
+	Version string
+
+	// WASI is the version of WASI imported by the world, e.g. 0.2.0.
+	// Default: 0.2.0, the first version in [version.WASI], which is the version
+	// imported by the TinyGo wasip2 target.
+	WASI string
+
+	// Reactor builds the component in reactor mode (TinyGo -buildmode=c-shared)
+	// instead of command mode. A reactor component does not export wasi:cli/run.
+	// Its main function is never called. Instead, the host calls _initialize,
+	// which runs package initialization before any exported function is called.
+	Reactor bool
+}
+
+// File is a file in a new project.
+type File struct {
+	// Path is the slash-separated path of the file, relative to the project directory.
+	Path string
+
+	// Content is the content of the file.
+	Content []byte
+}
+
+// Files returns the files of a new project described by opts:
+//
+//   - go.mod
+//   - wit/world.wit, a WIT package with a starter world that exports an example interface
+//   - wit/deps.toml, which configures the WIT dependencies fetched by wit-deps
+//   - main.go, which implements the exported interface with generated bindings
+//   - Makefile, with targets to fetch dependencies, generate bindings, and build with TinyGo,
+//     as a command or, if opts.Reactor is set, as a reactor
+func Files(opts *Options) ([]File, error) {
+	if err := module.CheckPath(opts.Module); err != nil {
+		return nil, err
+	}
+	id, err := wit.ParsePackageName(opts.Package)
+	if err != nil {
+		return nil, err
+	}
+	if id.Version != nil {
+		return nil, fmt.Errorf("invalid WIT package name %q: expected namespace:name", opts.Package)
+	}
+	for _, name := range []string{id.Namespace, id.Package, opts.World} {
+		if !IsName(name) {
+			return nil, fmt.Errorf("invalid WIT name %q: expected a kebab-case name, e.g. hello-world", name)
+		}
+	}
+
+	data := struct {
+		Options
+		Namespace   string
+		PackageName string
+		Require     bool
+		GoVersion   string
+	}{
+		Options:     *opts,
+		Namespace:   id.Namespace,
+		PackageName: id.Package,
+		Require:     semver.IsValid(opts.Version),
+		GoVersion:   "1.22",
+	}
+	if data.WASI == "" {
+		data.WASI = version.WASI[0]
+	}
+
+	var files []File
+	for _, path := range paths {
+		var b bytes.Buffer
+		err := templates.ExecuteTemplate(&b, path, &data)
+		if err != nil {
+			return nil, err
+		}
+		content := b.Bytes()
+		if strings.HasSuffix(path, ".go") {
+			content, err = format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		files = append(files, File{Path: path, Content: content})
+	}
+	return files, nil
+}
+
+// Write writes files to directory dir, creating it and any subdirectories if necessary.
+// It returns an error without writing any files if a file already exists.
+func Write(dir string, files []File) error {
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		_, err := os.Stat(path)
+		if err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, f.Content, 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var nameRE = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z][a-z0-9]*)*$`)
+
+// IsName reports whether s is a valid kebab-case WIT name, e.g. hello-world.
+func IsName(s string) bool {
+	return nameRE.MatchString(s)
+}
+
+// Name converts s, e.g. the name of a directory, into a WIT name,
+// or returns an empty string if s has no letters.
+func Name(s string) string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9')
+	}) {
+		// Each word must start with a letter.
+		w = strings.TrimLeft(w, "0123456789")
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+// paths are the paths of the files in a new project, in order.
+// Each is the name of a template in templates.
+var paths = []string{"go.mod", "wit/world.wit", "wit/deps.toml", "main.go", "Makefile"}
+
+var templates = template.Must(template.New("").Parse(`
+{{- define "go.mod" -}}
+module {{.Module}}
+
+go {{.GoVersion}}
+{{- if .Require}}
+
+require github.com/ydnar/wasm-tools-go {{.Version}}
+{{- end}}
+{{end}}
+
+{{- define "wit/world.wit" -}}
+package {{.Package}};
+
+/// An example interface exported by the {{.World}} world.
+interface greeter {
+    /// Returns a greeting for name.
+    greet: func(name: string) -> string;
+}
+
+world {{.World}} {
+    include wasi:cli/imports@{{.WASI}};
+
+    export greeter;
+}
+{{end}}
+
+{{- define "wit/deps.toml" -}}
+# WIT dependencies of package {{.Package}}, fetched into wit/deps by wit-deps:
+# https://github.com/bytecodealliance/wit-deps
+cli = "https://github.com/WebAssembly/wasi-cli/archive/v{{.WASI}}.tar.gz"
+{{end}}
+
+{{- define "main.go" -}}
+// Command {{.World}} is a WebAssembly component that implements the WIT world {{.Package}}/{{.World}}.
+package main
+
+//go:generate wit-bindgen-go generate --world {{.Package}}/{{.World}} --out internal ./wit
+
+import (
+	"{{.Module}}/internal/{{.Namespace}}/{{.PackageName}}/greeter"
+)
+
+{{if .Reactor -}}
+// init assigns the exported functions. In reactor mode, package initialization
+// runs when the host calls _initialize, before it calls any exported function.
+{{end -}}
+func init() {
+	greeter.Greet = func(name string) string {
+		return "Hello, " + name + "!"
+	}
+}
+
+{{if .Reactor -}}
+// main is required by the Go compiler, but is never called in reactor mode.
+{{- else -}}
+// main is required by the Go compiler, but is not called by the component.
+{{- end}}
+func main() {}
+{{end}}
+
+{{- define "Makefile" -}}
+WORLD = {{.World}}
+
+.PHONY: all deps generate build clean
+
+all: build
+
+# deps fetches the WIT dependencies in wit/deps.toml.
+deps:
+	wit-deps
+
+# generate generates Go bindings for the world in ./internal.
+generate: deps
+	mkdir -p internal
+	go generate ./...
+	go mod tidy
+
+{{if .Reactor -}}
+# build builds the component with TinyGo in reactor mode, which exports _initialize instead of wasi:cli/run.
+build: generate
+	tinygo build -target=wasip2 -buildmode=c-shared --wit-package ./wit --wit-world $(WORLD) -o $(WORLD).wasm .
+{{- else -}}
+# build builds the component with TinyGo.
+build: generate
+	tinygo build -target=wasip2 --wit-package ./wit --wit-world $(WORLD) -o $(WORLD).wasm .
+{{- end}}
+
+clean:
+	rm -f $(WORLD).wasm
+{{end}}
+`))
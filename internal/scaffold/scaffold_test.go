@@ -0,0 +1,162 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+func TestFiles(t *testing.T) {
+	opts := &Options{
+		Module:  "example.com/hello",
+		Package: "example:hello",
+		World:   "hello-world",
+		Version: "v0.1.0",
+		WASI:    "0.2.0",
+	}
+	files, err := Files(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+	if got, want := len(contents), len(paths); got != want {
+		t.Errorf("len(files): %d, expected %d", got, want)
+	}
+
+	mod, err := modfile.Parse("go.mod", []byte(contents["go.mod"]), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mod.Module.Mod.Path, opts.Module; got != want {
+		t.Errorf("go.mod module: %q, expected %q", got, want)
+	}
+	if len(mod.Require) != 1 || mod.Require[0].Mod.Version != opts.Version {
+		t.Errorf("go.mod require: %v, expected wasm-tools-go %s", mod.Require, opts.Version)
+	}
+
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", contents["main.go"], 0)
+	if err != nil {
+		t.Error(err)
+	}
+
+	for path, want := range map[string][]string{
+		"main.go":       {`"example.com/hello/internal/example/hello/greeter"`, "--world example:hello/hello-world"},
+		"wit/world.wit": {"package example:hello;", "world hello-world {", "include wasi:cli/imports@0.2.0;"},
+		"wit/deps.toml": {"wasi-cli/archive/v0.2.0.tar.gz"},
+		"Makefile":      {"WORLD = hello-world", "\ttinygo build -target=wasip2"},
+	} {
+		for _, s := range want {
+			if !strings.Contains(contents[path], s) {
+				t.Errorf("%s: expected %q in:\n%s", path, s, contents[path])
+			}
+		}
+	}
+}
+
+func TestFilesReactor(t *testing.T) {
+	files, err := Files(&Options{Module: "example.com/hello", Package: "example:hello", World: "hello", Reactor: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string)
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+	_, err = parser.ParseFile(token.NewFileSet(), "main.go", contents["main.go"], 0)
+	if err != nil {
+		t.Error(err)
+	}
+	for path, want := range map[string]string{
+		"main.go":  "runs when the host calls _initialize",
+		"Makefile": "\ttinygo build -target=wasip2 -buildmode=c-shared --wit-package ./wit",
+	} {
+		if !strings.Contains(contents[path], want) {
+			t.Errorf("%s: expected %q in:\n%s", path, want, contents[path])
+		}
+	}
+}
+
+func TestFilesDevel(t *testing.T) {
+	files, err := Files(&Options{Module: "example.com/hello", Package: "example:hello", World: "hello", Version: "(devel)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(files[0].Content); strings.Contains(got, "require") {
+		t.Errorf("go.mod: %q, expected no require directive", got)
+	}
+}
+
+func TestFilesInvalid(t *testing.T) {
+	tests := []Options{
+		{Module: "", Package: "example:hello", World: "hello"},
+		{Module: "example.com/hello", Package: "hello", World: "hello"},
+		{Module: "example.com/hello", Package: "example:hello/world", World: "hello"},
+		{Module: "example.com/hello", Package: "example:hello@0.1.0", World: "hello"},
+		{Module: "example.com/hello", Package: "example:Hello", World: "hello"},
+		{Module: "example.com/hello", Package: "example:hello", World: "hello_world"},
+	}
+	for _, opts := range tests {
+		_, err := Files(&opts)
+		if err == nil {
+			t.Errorf("Files(%+v): expected error", opts)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	files := []File{
+		{Path: "a.txt", Content: []byte("a")},
+		{Path: "b/c.txt", Content: []byte("c")},
+	}
+	err := Write(dir, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "b", "c.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), "c"; got != want {
+		t.Errorf("b/c.txt: %q, expected %q", got, want)
+	}
+
+	// Existing files are not overwritten.
+	err = Write(dir, []File{{Path: "d.txt"}, {Path: "a.txt", Content: []byte("x")}})
+	if err == nil {
+		t.Error("Write: expected error for existing file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "d.txt")); err == nil {
+		t.Error("Write: wrote d.txt, expected no files written")
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"hello", "hello"},
+		{"Hello World", "hello-world"},
+		{"my_app2", "my-app2"},
+		{"2fast", "fast"},
+		{"go-1", "go"},
+		{"123", ""},
+	}
+	for _, tt := range tests {
+		got := Name(tt.s)
+		if got != tt.want {
+			t.Errorf("Name(%q): %q, expected %q", tt.s, got, tt.want)
+		}
+		if got != "" && !IsName(got) {
+			t.Errorf("IsName(%q): false, expected true", got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package version reports build and compatibility information for wit-bindgen-go.
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+const (
+	// Module is the path of the wasm-tools-go module.
+	Module = "github.com/ydnar/wasm-tools-go"
+
+	// WasmTools is the minimum version of wasm-tools that produces WIT JSON
+	// readable by package wit.
+	WasmTools = "1.0.42"
+
+	// WasmToolsLatest is the latest version of wasm-tools whose WIT JSON
+	// is known to be readable by package wit.
+	WasmToolsLatest = "1.230.0"
+)
+
+// WASI lists the WASI snapshot versions with bindings and testdata in this module.
+var WASI = []string{"0.2.0", "0.2.8"}
+
+// Targets lists the compilation targets for generated bindings,
+// and the ABI used to call imported and exported functions.
+var Targets = []struct {
+	Name string
+	ABI  string
+}{
+	{"wasip2", "Component Model canonical ABI"},
+	{"wasip1", "Core WebAssembly with the wasi_snapshot_preview1 adapter"},
+}
+
+// Info describes how the running binary was built.
+type Info struct {
+	// Version is the module version, e.g. v0.1.0, or (devel) if built from a local checkout.
+	Version string
+
+	// GoVersion is the version of Go used to build the binary.
+	GoVersion string
+
+	// Revision is the VCS revision, if known.
+	Revision string
+
+	// Time is the VCS commit time in RFC 3339 format, if known.
+	Time string
+
+	// Modified reports whether the source tree had local modifications.
+	Modified bool
+}
+
+// Read returns build information for the running binary.
+// If build information is unavailable, Version is (unknown).
+func Read() Info {
+	info := Info{Version: "(unknown)"}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	info.Version = bi.Main.Version
+	if bi.Main.Path != Module {
+		// Built as a dependency of another module.
+		for _, dep := range bi.Deps {
+			if dep.Path == Module {
+				info.Version = dep.Version
+				if dep.Replace != nil {
+					info.Version = dep.Replace.Version
+				}
+				break
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+// String returns a single-line description of info, e.g.
+// v0.1.0 (abc1234, 2024-05-01T00:00:00Z) go1.22.3.
+func (info Info) String() string {
+	s := info.Version
+	rev := info.Revision
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	// Pseudo-versions already contain the revision.
+	if rev != "" && !strings.Contains(s, rev) {
+		if info.Modified {
+			rev += "+dirty"
+		}
+		s += " (" + rev
+		if info.Time != "" {
+			s += ", " + info.Time
+		}
+		s += ")"
+	}
+	if info.GoVersion != "" {
+		s += " " + info.GoVersion
+	}
+	return s
+}
@@ -0,0 +1,26 @@
+package version
+
+import "testing"
+
+func TestInfoString(t *testing.T) {
+	tests := []struct {
+		info Info
+		want string
+	}{
+		{Info{Version: "(devel)"}, "(devel)"},
+		{Info{Version: "v0.1.0", GoVersion: "go1.22.3"}, "v0.1.0 go1.22.3"},
+		{Info{Version: "(devel)", Revision: "0123456789abcdef", Time: "2024-05-01T00:00:00Z", Modified: true}, "(devel) (0123456789ab+dirty, 2024-05-01T00:00:00Z)"},
+		{Info{Version: "v0.0.0-20240501000000-0123456789ab", Revision: "0123456789abcdef"}, "v0.0.0-20240501000000-0123456789ab"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("String(): %q, expected %q", got, tt.want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	if got := Read().Version; got == "" {
+		t.Errorf("Read().Version: empty string")
+	}
+}
@@ -0,0 +1,395 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IsComponent reports whether b starts with a WebAssembly component header.
+func IsComponent(b []byte) bool {
+	return bytes.HasPrefix(b, ComponentHeader)
+}
+
+// ValType is a Core WebAssembly value type.
+type ValType byte
+
+// Core WebAssembly numeric value types.
+const (
+	I32 ValType = 0x7f
+	I64 ValType = 0x7e
+	F32 ValType = 0x7d
+	F64 ValType = 0x7c
+)
+
+// String returns the WebAssembly text format name of t.
+func (t ValType) String() string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	case F32:
+		return "f32"
+	case F64:
+		return "f64"
+	case 0x7b:
+		return "v128"
+	case 0x70:
+		return "funcref"
+	case 0x6f:
+		return "externref"
+	}
+	return fmt.Sprintf("valtype(%#x)", byte(t))
+}
+
+// FuncType is a Core WebAssembly function type.
+type FuncType struct {
+	Params  []ValType
+	Results []ValType
+}
+
+// String returns the WebAssembly text format of t, e.g. "(func (param i32) (result i32))".
+func (t *FuncType) String() string {
+	var b strings.Builder
+	b.WriteString("(func")
+	writeValTypes(&b, "param", t.Params)
+	writeValTypes(&b, "result", t.Results)
+	b.WriteString(")")
+	return b.String()
+}
+
+func writeValTypes(b *strings.Builder, kind string, types []ValType) {
+	if len(types) == 0 {
+		return
+	}
+	b.WriteString(" (")
+	b.WriteString(kind)
+	for _, t := range types {
+		b.WriteString(" ")
+		b.WriteString(t.String())
+	}
+	b.WriteString(")")
+}
+
+// ExternKind is the kind of a Core WebAssembly import or export.
+type ExternKind byte
+
+// Core WebAssembly import and export kinds.
+const (
+	ExternFunc   ExternKind = 0x00
+	ExternTable  ExternKind = 0x01
+	ExternMemory ExternKind = 0x02
+	ExternGlobal ExternKind = 0x03
+	ExternTag    ExternKind = 0x04
+)
+
+// Import is a Core WebAssembly module import.
+type Import struct {
+	Module string
+	Name   string
+	Kind   ExternKind
+	Type   *FuncType // Type of an imported function, otherwise nil
+}
+
+// Export is a Core WebAssembly module export.
+type Export struct {
+	Name string
+	Kind ExternKind
+	Type *FuncType // Type of an exported function, otherwise nil
+}
+
+// Module describes the imports and exports of a Core WebAssembly module.
+type Module struct {
+	Imports []Import
+	Exports []Export
+}
+
+// Core WebAssembly module section IDs read by [DecodeModule].
+const (
+	typeSectionID     = 1
+	importSectionID   = 2
+	functionSectionID = 3
+	exportSectionID   = 7
+)
+
+// DecodeModule decodes the imports and exports of Core WebAssembly module b.
+// Other sections are skipped.
+func DecodeModule(b []byte) (*Module, error) {
+	if !IsModule(b) {
+		return nil, errors.New("not a Core WebAssembly module")
+	}
+	var m Module
+	var types []*FuncType
+	var funcs []*FuncType // function index space
+	err := sections(b[len(ModuleHeader):], func(id byte, r *reader) {
+		switch id {
+		case typeSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				if form := r.byte(); form != 0x60 {
+					r.fail(fmt.Errorf("unsupported type form %#x", form))
+					return
+				}
+				types = append(types, &FuncType{Params: r.valTypes(), Results: r.valTypes()})
+			}
+
+		case importSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				imp := Import{Module: r.name(), Name: r.name(), Kind: ExternKind(r.byte())}
+				switch imp.Kind {
+				case ExternFunc:
+					imp.Type = r.funcType(types)
+					funcs = append(funcs, imp.Type)
+				case ExternTable:
+					r.refType()
+					r.limits()
+				case ExternMemory:
+					r.limits()
+				case ExternGlobal:
+					r.valType()
+					r.byte() // mutability
+				case ExternTag:
+					r.byte() // attribute
+					r.funcType(types)
+				default:
+					r.fail(fmt.Errorf("unknown import kind %#x", byte(imp.Kind)))
+				}
+				m.Imports = append(m.Imports, imp)
+			}
+
+		case functionSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				funcs = append(funcs, r.funcType(types))
+			}
+
+		case exportSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				exp := Export{Name: r.name(), Kind: ExternKind(r.byte())}
+				i := r.u32()
+				if exp.Kind == ExternFunc {
+					if int(i) >= len(funcs) {
+						r.fail(fmt.Errorf("export %q: function index %d out of range", exp.Name, i))
+						return
+					}
+					exp.Type = funcs[i]
+				}
+				m.Exports = append(m.Exports, exp)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Component describes the top-level import and export names of a WebAssembly component.
+type Component struct {
+	Imports []string
+	Exports []string
+}
+
+// Component section IDs read by [DecodeComponent].
+const (
+	componentImportSectionID = 10
+	componentExportSectionID = 11
+)
+
+// DecodeComponent decodes the names of the top-level imports and exports of
+// WebAssembly component b. Nested modules and components are skipped.
+func DecodeComponent(b []byte) (*Component, error) {
+	if !IsComponent(b) {
+		return nil, errors.New("not a WebAssembly component")
+	}
+	var c Component
+	err := sections(b[len(ComponentHeader):], func(id byte, r *reader) {
+		switch id {
+		case componentImportSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				c.Imports = append(c.Imports, r.externName())
+				r.externDesc()
+			}
+
+		case componentExportSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				c.Exports = append(c.Exports, r.externName())
+				if r.byte() == 0x00 { // sort
+					r.byte() // core sort
+				}
+				r.u32() // index
+				if r.byte() == 0x01 {
+					r.externDesc() // optional type ascription
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// sections calls f for each section in b, which follows a module or component header.
+// f may read some or all of the section contents from r.
+func sections(b []byte, f func(id byte, r *reader)) error {
+	r := &reader{b: b}
+	for len(r.b) > 0 && r.err == nil {
+		id := r.byte()
+		contents := r.bytes(int(r.u32()))
+		if r.err != nil {
+			break
+		}
+		sr := &reader{b: contents}
+		f(id, sr)
+		if sr.err != nil {
+			return fmt.Errorf("section %d: %w", id, sr.err)
+		}
+	}
+	return r.err
+}
+
+// reader reads values from the WebAssembly binary format.
+// After the first error, reads return zero values and r.err is set.
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.b = nil
+}
+
+func (r *reader) byte() byte {
+	if len(r.b) == 0 {
+		r.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+func (r *reader) bytes(n int) []byte {
+	if n > len(r.b) {
+		r.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+// u32 reads an unsigned LEB128 integer of up to 32 bits.
+func (r *reader) u32() uint32 {
+	var v uint32
+	for shift := 0; shift < 35; shift += 7 {
+		c := r.byte()
+		v |= uint32(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return v
+		}
+	}
+	r.fail(errors.New("integer too large"))
+	return 0
+}
+
+// leb skips a signed or unsigned LEB128 integer of any size.
+func (r *reader) leb() {
+	for r.byte()&0x80 != 0 {
+	}
+}
+
+func (r *reader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *reader) funcType(types []*FuncType) *FuncType {
+	i := r.u32()
+	if r.err != nil {
+		return nil
+	}
+	if int(i) >= len(types) {
+		r.fail(fmt.Errorf("type index %d out of range", i))
+		return nil
+	}
+	return types[i]
+}
+
+func (r *reader) valTypes() []ValType {
+	n := r.u32()
+	var types []ValType
+	for ; n > 0 && r.err == nil; n-- {
+		types = append(types, r.valType())
+	}
+	return types
+}
+
+// valType reads a value type. Reference types with a heap type
+// are returned as their leading byte.
+func (r *reader) valType() ValType {
+	t := ValType(r.byte())
+	if t == 0x63 || t == 0x64 { // (ref null ht) or (ref ht)
+		r.leb()
+	}
+	return t
+}
+
+func (r *reader) refType() {
+	r.valType()
+}
+
+func (r *reader) limits() {
+	flags := r.byte()
+	r.leb() // min
+	if flags&0x01 != 0 {
+		r.leb() // max
+	}
+	if flags&0x08 != 0 {
+		r.u32() // custom page size
+	}
+}
+
+// externName reads a component import or export name.
+func (r *reader) externName() string {
+	switch kind := r.byte(); kind {
+	case 0x00:
+		return r.name()
+	case 0x01:
+		name := r.name()
+		if r.byte() == 0x01 {
+			r.name() // version suffix
+		}
+		return name
+	default:
+		r.fail(fmt.Errorf("unknown extern name kind %#x", kind))
+		return ""
+	}
+}
+
+// externDesc skips a component extern descriptor.
+func (r *reader) externDesc() {
+	switch kind := r.byte(); kind {
+	case 0x00: // core module
+		r.byte() // 0x11
+		r.u32()
+	case 0x01, 0x04, 0x05: // func, component, instance
+		r.u32()
+	case 0x02: // value
+		if r.byte() == 0x00 {
+			r.u32()
+		} else {
+			r.leb() // valtype
+		}
+	case 0x03: // type
+		if r.byte() == 0x00 {
+			r.u32()
+		}
+	default:
+		r.fail(fmt.Errorf("unknown extern descriptor %#x", kind))
+	}
+}
@@ -0,0 +1,192 @@
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PackageDocsSection is the name of the custom section that contains the
+// documentation of a WIT package encoded as a WebAssembly component.
+const PackageDocsSection = "package-docs"
+
+// TypeExport is a world or interface exported by a component type in the type section
+// of a WebAssembly component.
+type TypeExport struct {
+	// Name is the fully-qualified name of the world or interface, e.g. wasi:http/proxy@0.2.0.
+	Name string
+
+	// World is true if the export is a component type (a WIT world),
+	// or false if it is an instance type (a WIT interface).
+	World bool
+}
+
+// Package describes a WIT package encoded as a WebAssembly component, as produced by
+// wasm-tools component wit --wasm, or a world in a component-type custom section.
+type Package struct {
+	// Exports are the worlds and interfaces in the package, in order.
+	Exports []TypeExport
+
+	// Docs is the contents of the package-docs custom section, if any.
+	Docs []byte
+}
+
+// Component section ID read by [DecodePackage].
+const componentTypeSectionID = 7
+
+// DecodePackage decodes the names of the worlds and interfaces exported by the
+// component types in the top-level type section of component b, and its package
+// documentation. Type definitions are skipped. Other sections are ignored.
+func DecodePackage(b []byte) (*Package, error) {
+	if !IsComponent(b) {
+		return nil, errors.New("not a WebAssembly component")
+	}
+	var p Package
+	err := sections(b[len(ComponentHeader):], func(id byte, r *reader) {
+		switch id {
+		case CustomSectionID:
+			if r.name() == PackageDocsSection {
+				p.Docs = r.b
+			}
+
+		case componentTypeSectionID:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				if len(r.b) == 0 || r.b[0] != 0x41 {
+					r.defType()
+					continue
+				}
+				r.byte()
+				for n := r.u32(); n > 0 && r.err == nil; n-- {
+					decl := r.byte()
+					if decl != 0x04 {
+						r.componentDecl(decl)
+						continue
+					}
+					name := r.externName()
+					if len(r.b) > 0 && (r.b[0] == 0x04 || r.b[0] == 0x05) {
+						p.Exports = append(p.Exports, TypeExport{Name: name, World: r.b[0] == 0x04})
+					}
+					r.externDesc()
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// defType skips a component type definition.
+func (r *reader) defType() {
+	switch form := r.byte(); form {
+	case 0x40: // func
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.name()
+			r.leb() // valtype
+		}
+		switch r.byte() {
+		case 0x00:
+			r.leb() // valtype
+		case 0x01:
+			r.byte() // 0x00: no named results
+		}
+	case 0x41: // component
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.componentDecl(r.byte())
+		}
+	case 0x42: // instance
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.instanceDecl(r.byte())
+		}
+	case 0x3f: // resource
+		r.byte() // 0x7f: i32 representation
+		if r.byte() == 0x01 {
+			r.u32() // destructor
+		}
+	default:
+		r.defValType(form)
+	}
+}
+
+// defValType skips a component value type definition with form.
+func (r *reader) defValType(form byte) {
+	switch {
+	case form >= 0x73 || form == 0x64: // primitive
+	case form == 0x72: // record
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.name()
+			r.leb()
+		}
+	case form == 0x71: // variant
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.name()
+			r.optValType()
+			r.byte() // 0x00
+		}
+	case form == 0x70, form == 0x6b: // list, option
+		r.leb()
+	case form == 0x67: // fixed-size list
+		r.leb()
+		r.u32()
+	case form == 0x6f: // tuple
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.leb()
+		}
+	case form == 0x6e, form == 0x6d: // flags, enum
+		for n := r.u32(); n > 0 && r.err == nil; n-- {
+			r.name()
+		}
+	case form == 0x6a: // result
+		r.optValType()
+		r.optValType()
+	case form == 0x69, form == 0x68: // own, borrow
+		r.u32()
+	case form == 0x66, form == 0x65: // stream, future
+		r.optValType()
+	default:
+		r.fail(fmt.Errorf("unknown type form %#x", form))
+	}
+}
+
+func (r *reader) optValType() {
+	if r.byte() == 0x01 {
+		r.leb()
+	}
+}
+
+// componentDecl skips a component type declaration of kind decl.
+func (r *reader) componentDecl(decl byte) {
+	if decl == 0x03 { // import
+		r.externName()
+		r.externDesc()
+		return
+	}
+	r.instanceDecl(decl)
+}
+
+// instanceDecl skips an instance type declaration of kind decl.
+func (r *reader) instanceDecl(decl byte) {
+	switch decl {
+	case 0x01: // type
+		r.defType()
+	case 0x02: // alias
+		if r.byte() == 0x00 {
+			r.byte() // core sort
+		}
+		switch target := r.byte(); target {
+		case 0x00, 0x01: // export, core export
+			r.u32()
+			r.name()
+		case 0x02: // outer
+			r.u32()
+			r.u32()
+		default:
+			r.fail(fmt.Errorf("unknown alias target %#x", target))
+		}
+	case 0x04: // export
+		r.externName()
+		r.externDesc()
+	default:
+		r.fail(fmt.Errorf("unsupported type declaration %#x", decl))
+	}
+}
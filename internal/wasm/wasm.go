@@ -0,0 +1,83 @@
+// Package wasm contains helpers for reading and writing the WebAssembly binary format,
+// for both Core WebAssembly modules and components.
+package wasm
+
+import (
+	"bytes"
+	"errors"
+)
+
+var (
+	// ModuleHeader is the magic number and version of a Core WebAssembly module.
+	ModuleHeader = []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+
+	// ComponentHeader is the magic number, version, and layer of a WebAssembly component.
+	ComponentHeader = []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}
+)
+
+// CustomSectionID is the section ID of a custom section.
+const CustomSectionID = 0
+
+// IsModule reports whether b starts with a Core WebAssembly module header.
+func IsModule(b []byte) bool {
+	return bytes.HasPrefix(b, ModuleHeader)
+}
+
+// AppendU32 appends the unsigned LEB128 encoding of v to b.
+func AppendU32(b []byte, v uint32) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+// AppendS64 appends the signed LEB128 encoding of v to b.
+func AppendS64(b []byte, v int64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+// AppendName appends the length-prefixed UTF-8 encoding of s to b.
+func AppendName(b []byte, s string) []byte {
+	b = AppendU32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// AppendSection appends a section with id and contents to b.
+func AppendSection(b []byte, id byte, contents []byte) []byte {
+	b = append(b, id)
+	b = AppendU32(b, uint32(len(contents)))
+	return append(b, contents...)
+}
+
+// AppendCustomSection appends a custom section with name and data to b.
+func AppendCustomSection(b []byte, name string, data []byte) []byte {
+	contents := AppendName(nil, name)
+	contents = append(contents, data...)
+	return AppendSection(b, CustomSectionID, contents)
+}
+
+// EmbedCustomSection returns a copy of Core WebAssembly module with a custom section
+// named name containing data appended to it. Custom sections may appear at the end of
+// a module, so the existing sections are not modified.
+func EmbedCustomSection(module []byte, name string, data []byte) ([]byte, error) {
+	if !IsModule(module) {
+		return nil, errors.New("not a Core WebAssembly module")
+	}
+	b := make([]byte, 0, len(module)+len(name)+len(data)+16)
+	b = append(b, module...)
+	return AppendCustomSection(b, name, data), nil
+}
@@ -0,0 +1,133 @@
+package wasm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAppendU32(t *testing.T) {
+	tests := []struct {
+		v    uint32
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{0x7f, []byte{0x7f}},
+		{0x80, []byte{0x80, 0x01}},
+		{624485, []byte{0xe5, 0x8e, 0x26}},
+		{0xffffffff, []byte{0xff, 0xff, 0xff, 0xff, 0x0f}},
+	}
+	for _, tt := range tests {
+		if got := AppendU32(nil, tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("AppendU32(%d): % x, expected % x", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestAppendS64(t *testing.T) {
+	tests := []struct {
+		v    int64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{0x3f, []byte{0x3f}},
+		{0x40, []byte{0xc0, 0x00}},
+		{-1, []byte{0x7f}},
+		{-64, []byte{0x40}},
+		{-65, []byte{0xbf, 0x7f}},
+		{-123456, []byte{0xc0, 0xbb, 0x78}},
+	}
+	for _, tt := range tests {
+		if got := AppendS64(nil, tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("AppendS64(%d): % x, expected % x", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestEmbedCustomSection(t *testing.T) {
+	b, err := EmbedCustomSection(ModuleHeader, "name", []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(append([]byte{}, ModuleHeader...), 0x00, 0x07, 0x04, 'n', 'a', 'm', 'e', 0x01, 0x02)
+	if !bytes.Equal(b, want) {
+		t.Errorf("EmbedCustomSection: % x, expected % x", b, want)
+	}
+	if _, err := EmbedCustomSection(ComponentHeader, "name", nil); err == nil {
+		t.Errorf("EmbedCustomSection(component): expected error")
+	}
+}
+
+func TestDecodeModule(t *testing.T) {
+	types := []byte{0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x00, 0x00}
+	imports := AppendName(AppendName([]byte{0x02}, "m"), "f")
+	imports = append(imports, 0x00, 0x00)
+	imports = AppendName(AppendName(imports, "env"), "memory")
+	imports = append(imports, 0x02, 0x00, 0x01)
+	funcs := []byte{0x01, 0x01}
+	exports := append(AppendName([]byte{0x02}, "g"), 0x00, 0x01)
+	exports = append(AppendName(exports, "memory"), 0x02, 0x00)
+
+	b := append([]byte{}, ModuleHeader...)
+	b = AppendSection(b, typeSectionID, types)
+	b = AppendSection(b, importSectionID, imports)
+	b = AppendSection(b, functionSectionID, funcs)
+	b = AppendSection(b, exportSectionID, exports)
+	b = AppendCustomSection(b, "name", nil)
+
+	m, err := DecodeModule(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(m.Imports), 2; got != want {
+		t.Fatalf("len(Imports): %d, expected %d", got, want)
+	}
+	if imp := m.Imports[0]; imp.Module != "m" || imp.Name != "f" || imp.Kind != ExternFunc || imp.Type.String() != "(func (param i32) (result i32))" {
+		t.Errorf("Imports[0]: %+v", imp)
+	}
+	if imp := m.Imports[1]; imp.Module != "env" || imp.Name != "memory" || imp.Kind != ExternMemory || imp.Type != nil {
+		t.Errorf("Imports[1]: %+v", imp)
+	}
+	if got, want := len(m.Exports), 2; got != want {
+		t.Fatalf("len(Exports): %d, expected %d", got, want)
+	}
+	if exp := m.Exports[0]; exp.Name != "g" || exp.Kind != ExternFunc || exp.Type.String() != "(func)" {
+		t.Errorf("Exports[0]: %+v", exp)
+	}
+	if exp := m.Exports[1]; exp.Name != "memory" || exp.Kind != ExternMemory {
+		t.Errorf("Exports[1]: %+v", exp)
+	}
+
+	if _, err := DecodeModule(b[:len(b)-3]); err == nil {
+		t.Errorf("DecodeModule(truncated): expected error")
+	}
+	if _, err := DecodeModule(ComponentHeader); err == nil {
+		t.Errorf("DecodeModule(component): expected error")
+	}
+}
+
+func TestDecodeComponent(t *testing.T) {
+	imports := append(AppendName([]byte{0x01, 0x00}, "wasi:cli/environment@0.2.0"), 0x05, 0x00)
+	exports := append(AppendName([]byte{0x01, 0x00}, "run"), 0x01, 0x00, 0x00)
+
+	b := append([]byte{}, ComponentHeader...)
+	b = AppendSection(b, 1, ModuleHeader) // nested core module
+	b = AppendSection(b, componentImportSectionID, imports)
+	b = AppendSection(b, componentExportSectionID, exports)
+
+	c, err := DecodeComponent(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(c.Imports, ","), "wasi:cli/environment@0.2.0"; got != want {
+		t.Errorf("Imports: %s, expected %s", got, want)
+	}
+	if got, want := strings.Join(c.Exports, ","), "run"; got != want {
+		t.Errorf("Exports: %s, expected %s", got, want)
+	}
+	if _, err := DecodeComponent(ModuleHeader); err == nil {
+		t.Errorf("DecodeComponent(module): expected error")
+	}
+}
@@ -0,0 +1,152 @@
+package witcli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/ydnar/wasm-tools-go/internal/version"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// CacheDir is the directory where [LoadOne] caches WIT resolved through wasm-tools,
+// keyed by a hash of the WIT input. If CacheDir is empty, caching is disabled.
+var CacheDir string
+
+// DefaultCacheDir returns the default cache directory, wit-bindgen-go in [os.UserCacheDir].
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "wit-bindgen-go"), nil
+}
+
+// cacheFormat is incremented when the format of cached files changes.
+const cacheFormat = 1
+
+// loadWIT loads WIT from path through wasm-tools, using the cache in [CacheDir] if set.
+// Input read from stdin is never cached.
+func loadWIT(path string) (*wit.Resolve, error) {
+	if CacheDir == "" || path == "" || path == "-" {
+		return wit.LoadWIT(path)
+	}
+	key, err := cacheKey(path)
+	if err != nil {
+		slog.Debug("WIT cache disabled", "path", path, "error", err)
+		return wit.LoadWIT(path)
+	}
+	cached := filepath.Join(CacheDir, key[:2], key+".json")
+	if f, err := os.Open(cached); err == nil {
+		res, err := wit.DecodeJSON(f)
+		f.Close()
+		if err == nil {
+			slog.Debug("loaded WIT from cache", "path", path, "cache", cached)
+			return res, nil
+		}
+		slog.Warn("ignoring invalid WIT cache entry", "cache", cached, "error", err)
+	}
+
+	res, err := wit.LoadWIT(path)
+	if err != nil {
+		return nil, err
+	}
+	err = writeCache(cached, res)
+	if err != nil {
+		slog.Warn("unable to write WIT cache", "cache", cached, "error", err)
+	}
+	return res, nil
+}
+
+// writeCache writes res as JSON to the file at cached.
+// The file is written to a temporary file first, then renamed,
+// so concurrent invocations never observe a partial cache entry.
+func writeCache(cached string, res *wit.Resolve) error {
+	var b bytes.Buffer
+	err := wit.EncodeJSON(&b, res)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(cached), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(cached), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b.Bytes())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), cached)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// cacheKey returns a hex-encoded SHA-256 hash of the WIT at path, which is
+// either a file or a directory, and the version of wasm-tools that processes it.
+// Directories are hashed recursively, including file names, so any change to
+// a package or its deps produces a different key.
+func cacheKey(path string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "wit-bindgen-go cache %d %s\n", cacheFormat, version.Read().Version)
+
+	wasmTools, err := exec.LookPath("wasm-tools")
+	if err != nil {
+		return "", err
+	}
+	err = hashFileInfo(h, wasmTools)
+	if err != nil {
+		return "", err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fmt.Fprintf(h, "file %q\n", filepath.ToSlash(rel))
+		n, err := io.Copy(h, f)
+		fmt.Fprintf(h, "\n%d\n", n)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFileInfo writes the name, size, and modification time of the file at path to w.
+// It is used to identify a wasm-tools binary without hashing or executing it.
+func hashFileInfo(w io.Writer, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "wasm-tools %q %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	return nil
+}
@@ -0,0 +1,80 @@
+package witcli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	if _, err := exec.LookPath("wasm-tools"); err != nil {
+		t.Skip("wasm-tools not in $PATH")
+	}
+	dir := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := func() string {
+		t.Helper()
+		k, err := cacheKey(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	write("a.wit", "package a:b;\n")
+	k1 := key()
+	if k := key(); k != k1 {
+		t.Errorf("cacheKey not stable: %s != %s", k, k1)
+	}
+	write("deps/c/c.wit", "package a:c;\n")
+	k2 := key()
+	if k2 == k1 {
+		t.Error("cacheKey unchanged after adding a dependency")
+	}
+	write("deps/c/c.wit", "package a:d;\n")
+	if k := key(); k == k2 {
+		t.Error("cacheKey unchanged after editing a dependency")
+	}
+}
+
+func TestLoadWITCache(t *testing.T) {
+	if _, err := exec.LookPath("wasm-tools"); err != nil {
+		t.Skip("wasm-tools not in $PATH")
+	}
+	defer func(dir string) { CacheDir = dir }(CacheDir)
+	CacheDir = t.TempDir()
+
+	path := filepath.Join(t.TempDir(), "a.wit")
+	err := os.WriteFile(path, []byte("package a:b;\n\ninterface i {\n\tf: func();\n}\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res1, err := loadWIT(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := cacheKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(CacheDir, key[:2], key+".json")); err != nil {
+		t.Fatalf("cache entry not written: %v", err)
+	}
+	res2, err := loadWIT(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res2.WIT(nil, ""), res1.WIT(nil, ""); got != want {
+		t.Errorf("cached WIT:\n%s\nexpected:\n%s", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package witcli
+
+import (
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// CoreFunc is a Core WebAssembly function imported or exported
+// by a module that implements a WIT world.
+type CoreFunc struct {
+	Module   string // Import module name, empty for exports
+	Name     string
+	Type     *wasm.FuncType
+	Optional bool // Exports only: the function need not be exported
+}
+
+// RootModule is the import module name for functions and types imported
+// directly by a world, rather than by an interface.
+const RootModule = wit.RootModule
+
+// CoreImports returns the Core WebAssembly functions that a module
+// implementing w may import, in world order.
+func CoreImports(w *wit.World) []CoreFunc {
+	var funcs []CoreFunc
+	for _, imp := range w.CoreImports() {
+		funcs = append(funcs, CoreFunc{Module: imp.Module, Name: imp.Name, Type: funcType(imp.Function.CoreSignature(imp.Direction))})
+	}
+	return funcs
+}
+
+// CoreExports returns the Core WebAssembly functions that a module
+// implementing w must or may export, in world order.
+func CoreExports(w *wit.World) []CoreFunc {
+	var funcs []CoreFunc
+	exp := func(name string, f *wit.Function) {
+		sig := f.CoreSignature(wit.Exported)
+		funcs = append(funcs, CoreFunc{Name: name, Type: funcType(sig)})
+		if f.PostReturn() != nil {
+			post := wit.CoreSignature{Params: sig.Results}
+			funcs = append(funcs, CoreFunc{Name: "cabi_post_" + name, Type: funcType(post), Optional: true})
+		}
+	}
+	w.Exports.All()(func(name string, item wit.WorldItem) bool {
+		switch item := item.(type) {
+		case *wit.Interface:
+			prefix := ItemName(name, item) + "#"
+			item.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+				if f := t.Destructor(); f != nil {
+					funcs = append(funcs, CoreFunc{Name: prefix + f.Name, Type: funcType(f.CoreSignature(wit.Exported)), Optional: true})
+				}
+				return true
+			})
+			item.Functions.All()(func(_ string, f *wit.Function) bool {
+				exp(prefix+f.Name, f)
+				return true
+			})
+		case *wit.Function:
+			exp(name, item)
+		}
+		return true
+	})
+	return funcs
+}
+
+// ItemName returns the import or export name of a world item:
+// the fully-qualified name of a named interface, otherwise its name in the world.
+func ItemName(name string, item wit.WorldItem) string {
+	if i, ok := item.(*wit.Interface); ok {
+		if id, ok := i.ID(); ok {
+			return id.String()
+		}
+	}
+	return name
+}
+
+func funcType(sig wit.CoreSignature) *wasm.FuncType {
+	return &wasm.FuncType{Params: valTypes(sig.Params), Results: valTypes(sig.Results)}
+}
+
+func valTypes(types []wit.CoreType) []wasm.ValType {
+	var out []wasm.ValType
+	for _, t := range types {
+		out = append(out, valType(t))
+	}
+	return out
+}
+
+func valType(t wit.CoreType) wasm.ValType {
+	switch t {
+	case wit.CoreI32:
+		return wasm.I32
+	case wit.CoreI64:
+		return wasm.I64
+	case wit.CoreF32:
+		return wasm.F32
+	case wit.CoreF64:
+		return wasm.F64
+	}
+	panic("BUG: unknown core type " + t.String())
+}
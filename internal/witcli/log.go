@@ -0,0 +1,43 @@
+package witcli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Log formats accepted by [NewLogger].
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// NewLogger returns a [slog.Logger] that writes to w in format, which is either
+// [LogFormatText] or [LogFormatJSON]. Messages are logged at [slog.LevelInfo] and above,
+// [slog.LevelDebug] and above if verbose is true, or [slog.LevelWarn] and above if quiet is true.
+// The text format omits timestamps, as it is intended to be read by people.
+func NewLogger(w io.Writer, format string, verbose, quiet bool) (*slog.Logger, error) {
+	if verbose && quiet {
+		return nil, fmt.Errorf("verbose and quiet are mutually exclusive")
+	}
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	switch {
+	case verbose:
+		opts.Level = slog.LevelDebug
+	case quiet:
+		opts.Level = slog.LevelWarn
+	}
+	switch format {
+	case LogFormatText, "":
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, expected %q or %q", format, LogFormatText, LogFormatJSON)
+}
@@ -0,0 +1,74 @@
+package witcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		verbose bool
+		quiet   bool
+		want    []string // messages logged, in order
+	}{
+		{"default", "", false, false, []string{"info", "warn"}},
+		{"text", LogFormatText, false, false, []string{"info", "warn"}},
+		{"json", LogFormatJSON, false, false, []string{"info", "warn"}},
+		{"verbose", LogFormatText, true, false, []string{"debug", "info", "warn"}},
+		{"quiet", LogFormatJSON, false, true, []string{"warn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := NewLogger(&buf, tt.format, tt.verbose, tt.quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				if line == "" {
+					continue
+				}
+				if tt.format == LogFormatJSON {
+					var v struct {
+						Time string `json:"time"`
+						Msg  string `json:"msg"`
+					}
+					if err := json.Unmarshal([]byte(line), &v); err != nil {
+						t.Fatalf("invalid JSON log line %q: %v", line, err)
+					}
+					if v.Time == "" {
+						t.Errorf("JSON log line %q has no time", line)
+					}
+					got = append(got, v.Msg)
+					continue
+				}
+				if strings.Contains(line, "time=") {
+					t.Errorf("text log line %q has a time", line)
+				}
+				_, msg, _ := strings.Cut(line, " msg=")
+				got = append(got, msg)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("logged %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLoggerErrors(t *testing.T) {
+	if _, err := NewLogger(&bytes.Buffer{}, "xml", false, false); err == nil {
+		t.Error("NewLogger: expected error for unknown format")
+	}
+	if _, err := NewLogger(&bytes.Buffer{}, LogFormatText, true, true); err == nil {
+		t.Error("NewLogger: expected error for verbose and quiet")
+	}
+}
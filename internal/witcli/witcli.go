@@ -1,6 +1,7 @@
 package witcli
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 // If the resolved path doesn’t end in ".json", it will attempt to load
 // WIT indirectly by processing the input through wasm-tools.
 // If forceWIT is true, it will always process input through wasm-tools.
+// WIT processed through wasm-tools is cached in [CacheDir], if set.
 func LoadOne(forceWIT bool, paths ...string) (*wit.Resolve, error) {
 	var path string
 	switch len(paths) {
@@ -24,7 +26,71 @@ func LoadOne(forceWIT bool, paths ...string) (*wit.Resolve, error) {
 		return nil, fmt.Errorf("found %d path arguments, expecting 0 or 1", len(paths))
 	}
 	if forceWIT || !strings.HasSuffix(path, ".json") {
-		return wit.LoadWIT(path)
+		return loadWIT(path)
 	}
 	return wit.LoadJSON(path)
 }
+
+// Load loads one or more paths and merges them into a single [wit.Resolve].
+// Each path is loaded as with [LoadOne]. A directory is processed through wasm-tools.
+// If paths is empty, it reads from stdin.
+func Load(forceWIT bool, paths ...string) (*wit.Resolve, error) {
+	if len(paths) <= 1 {
+		return LoadOne(forceWIT, paths...)
+	}
+	var res *wit.Resolve
+	for _, path := range paths {
+		r, err := LoadOne(forceWIT, path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if res == nil {
+			res = r
+			continue
+		}
+		err = res.Merge(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return res, nil
+}
+
+// FindSources returns the locations of the definitions in res declared in WIT source files
+// in paths, as with [wit.FindSources]. Paths that are loaded as JSON, or read from stdin, are ignored.
+func FindSources(forceWIT bool, res *wit.Resolve, paths ...string) (wit.SourceMap, error) {
+	var witPaths []string
+	for _, path := range paths {
+		if path == "" || path == "-" || (!forceWIT && strings.HasSuffix(path, ".json")) {
+			continue
+		}
+		witPaths = append(witPaths, path)
+	}
+	return wit.FindSources(res, witPaths...)
+}
+
+// FindWorld returns the world in res matching name, which is either a world name
+// (e.g. "command") or a fully-qualified world name with or without a version
+// (e.g. "wasi:cli/command@0.2.0"). If name is empty, it returns the last world.
+func FindWorld(res *wit.Resolve, name string) (*wit.World, error) {
+	if name == "" {
+		if len(res.Worlds) == 0 {
+			return nil, errors.New("no worlds")
+		}
+		return res.Worlds[len(res.Worlds)-1], nil
+	}
+	for _, w := range res.Worlds {
+		if name == w.Name {
+			return w, nil
+		}
+		id := w.ID()
+		if name == id.String() {
+			return w, nil
+		}
+		id.Version = nil
+		if name == id.String() {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("world %s not found", name)
+}
@@ -0,0 +1,625 @@
+// Package witgen synthesizes a WIT world from directives in Go source,
+// for Go-first development of WebAssembly components, where WIT is generated
+// from Go declarations rather than written by hand.
+//
+// A Go file declares the world with a //wit:world directive, e.g.:
+//
+//	//wit:world example:app/app@0.1.0
+//
+// Go functions and types are exported with a //wit:export directive in their doc comments:
+//
+//	//wit:export
+//	func Greet(name string) string
+//
+//	//wit:export api
+//	func Lookup(key string) (Entry, error)
+//
+//	//wit:export
+//	type Entry struct { ... }
+//
+// A function with no interface name is exported directly by the world, otherwise by
+// the named interface. A type with no interface name is declared in interface types.
+package witgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Directives recognized in Go source.
+const (
+	WorldDirective  = "//wit:world"
+	ExportDirective = "//wit:export"
+)
+
+// DefaultInterface is the name of the interface that declares exported Go types
+// with no interface name in their //wit:export directive.
+const DefaultInterface = "types"
+
+// Synthesize returns a [wit.Resolve] with a single package and world synthesized from the
+// //wit: directives in Go files, which must be parsed with comments, e.g. [parser.ParseComments].
+// If world is not empty, e.g. example:app/app@0.1.0, it overrides a //wit:world directive.
+//
+// Go types map to WIT types: sized integers, floats, bool, and string to the equivalent
+// WIT primitive types, rune to char, []T and cm.List[T] to list<T>, *T and cm.Option[T] to
+// option<T>, and structs to records. Other exported Go types are referred to by name.
+// A function that returns an error returns a WIT result with a string error.
+//
+// [parser.ParseComments]: https://pkg.go.dev/go/parser#ParseComments
+func Synthesize(fset *token.FileSet, files []*ast.File, world string) (*wit.Resolve, error) {
+	s := &synth{
+		fset:       fset,
+		res:        &wit.Resolve{},
+		types:      make(map[string]*goType),
+		interfaces: make(map[string]*wit.Interface),
+		uses:       make(map[useKey]*wit.TypeDef),
+		worldFuncs: make(map[string]bool),
+	}
+	var funcs []*ast.FuncDecl
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				if arg, ok := directive(c.Text, WorldDirective); ok && world == "" {
+					if s.worldName != "" && s.worldName != arg {
+						return nil, s.errorf(c.Pos(), "conflicting %s directives: %s and %s", WorldDirective, s.worldName, arg)
+					}
+					s.worldName = arg
+				}
+			}
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if _, ok := exportDirective(decl.Doc); ok {
+					funcs = append(funcs, decl)
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					spec := spec.(*ast.TypeSpec)
+					doc := spec.Doc
+					if doc == nil && len(decl.Specs) == 1 {
+						doc = decl.Doc
+					}
+					iface, ok := exportDirective(doc)
+					if !ok {
+						continue
+					}
+					if prev, ok := s.types[spec.Name.Name]; ok {
+						return nil, s.errorf(spec.Pos(), "type %s already declared at %s", spec.Name.Name, s.fset.Position(prev.spec.Pos()))
+					}
+					if iface == "" {
+						iface = DefaultInterface
+					}
+					t := &goType{spec: spec, doc: doc, iface: iface}
+					s.types[spec.Name.Name] = t
+					s.order = append(s.order, t)
+				}
+			}
+		}
+	}
+	if world != "" {
+		s.worldName = world
+	}
+	if s.worldName == "" {
+		return nil, fmt.Errorf("no %s directive found; declare a world, e.g. %s example:app/app@0.1.0", WorldDirective, WorldDirective)
+	}
+	if err := s.declareWorld(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range s.order {
+		if _, err := s.typeDef(t); err != nil {
+			return nil, err
+		}
+	}
+	var exports []*wit.Function
+	for _, decl := range funcs {
+		f, err := s.function(decl)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			exports = append(exports, f)
+		}
+	}
+
+	// Interfaces with functions are exported, and interfaces with only types are imported.
+	// World-level uses follow imported interfaces, which they refer to.
+	// Exported functions precede exported interfaces, in the order of a resolved world.
+	for _, i := range s.res.Interfaces {
+		id, _ := i.ID()
+		if i.Functions.Len() == 0 {
+			s.world.Imports.Set(id.String(), i)
+		}
+	}
+	for _, t := range s.worldUses {
+		s.world.Imports.Set(*t.Name, t)
+	}
+	for _, f := range exports {
+		s.world.Exports.Set(f.Name, f)
+	}
+	for _, i := range s.res.Interfaces {
+		id, _ := i.ID()
+		if i.Functions.Len() != 0 {
+			s.world.Exports.Set(id.String(), i)
+		}
+	}
+	return s.res, nil
+}
+
+// synth holds the state of [Synthesize].
+type synth struct {
+	fset       *token.FileSet
+	res        *wit.Resolve
+	pkg        *wit.Package
+	world      *wit.World
+	worldName  string
+	types      map[string]*goType // exported Go types, by Go name
+	order      []*goType          // exported Go types, in source order
+	interfaces map[string]*wit.Interface
+	uses       map[useKey]*wit.TypeDef
+	worldUses  []*wit.TypeDef
+	worldFuncs map[string]bool // names of functions exported by the world
+}
+
+// goType is a Go type declaration with a //wit:export directive.
+type goType struct {
+	spec     *ast.TypeSpec
+	doc      *ast.CommentGroup
+	iface    string
+	def      *wit.TypeDef
+	defining bool
+}
+
+// useKey identifies a use of a [wit.TypeDef] in a [wit.TypeOwner].
+type useKey struct {
+	owner wit.TypeOwner
+	t     *wit.TypeDef
+}
+
+func (s *synth) errorf(pos token.Pos, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", s.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+func (s *synth) declareWorld() error {
+	id, err := wit.ParseIdent(s.worldName)
+	if err != nil {
+		return fmt.Errorf("invalid world %q: %w", s.worldName, err)
+	}
+	if id.Extension == "" {
+		return fmt.Errorf("invalid world %q: missing world name, e.g. %s:%s/app", s.worldName, id.Namespace, id.Package)
+	}
+	name := id.Extension
+	id.Extension = ""
+	s.pkg = &wit.Package{Name: id}
+	s.world = &wit.World{Name: name, Package: s.pkg}
+	s.pkg.Worlds.Set(name, s.world)
+	s.res.Packages = append(s.res.Packages, s.pkg)
+	s.res.Worlds = append(s.res.Worlds, s.world)
+	return nil
+}
+
+// iface returns the interface with name, creating it if necessary.
+func (s *synth) iface(name string) *wit.Interface {
+	if i, ok := s.interfaces[name]; ok {
+		return i
+	}
+	i := &wit.Interface{Name: &name, Package: s.pkg}
+	s.interfaces[name] = i
+	s.pkg.Interfaces.Set(name, i)
+	s.res.Interfaces = append(s.res.Interfaces, i)
+	return i
+}
+
+// typeDef returns the WIT type definition of exported Go type t, defining it if necessary.
+func (s *synth) typeDef(t *goType) (*wit.TypeDef, error) {
+	if t.def != nil {
+		return t.def, nil
+	}
+	if t.defining {
+		return nil, s.errorf(t.spec.Pos(), "type %s is recursive, which WIT does not support", t.spec.Name.Name)
+	}
+	t.defining = true
+	if t.spec.TypeParams != nil {
+		return nil, s.errorf(t.spec.Pos(), "generic type %s cannot be exported", t.spec.Name.Name)
+	}
+	if !validName(t.iface) {
+		return nil, s.errorf(t.spec.Pos(), "invalid interface name %q", t.iface)
+	}
+	owner := s.iface(t.iface)
+	name := Name(t.spec.Name.Name)
+	if _, ok := owner.TypeDefs.GetOK(name); ok {
+		return nil, s.errorf(t.spec.Pos(), "type %s: %s already declared in interface %s", t.spec.Name.Name, name, t.iface)
+	}
+
+	var kind wit.TypeDefKind
+	if st, ok := t.spec.Type.(*ast.StructType); ok {
+		r, err := s.record(owner, st)
+		if err != nil {
+			return nil, err
+		}
+		kind = r
+	} else {
+		var err error
+		kind, err = s.kind(owner, t.spec.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+	td := &wit.TypeDef{Name: &name, Kind: kind, Owner: owner, Docs: docs(t.doc)}
+	owner.TypeDefs.Set(name, td)
+	s.res.TypeDefs = append(s.res.TypeDefs, td)
+	t.def = td
+	return td, nil
+}
+
+func (s *synth) record(owner wit.TypeOwner, st *ast.StructType) (*wit.Record, error) {
+	r := &wit.Record{}
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, s.errorf(field.Pos(), "embedded field cannot be exported")
+		}
+		var typ wit.Type
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			if typ == nil {
+				var err error
+				typ, err = s.typ(owner, field.Type)
+				if err != nil {
+					return nil, err
+				}
+			}
+			name := Name(ident.Name)
+			if field.Tag != nil {
+				tag, _ := strconv.Unquote(field.Tag.Value)
+				if v, ok := reflect.StructTag(tag).Lookup("wit"); ok {
+					if v == "-" {
+						continue
+					}
+					name = v
+				}
+			}
+			if !validName(name) {
+				return nil, s.errorf(ident.Pos(), "invalid field name %q", name)
+			}
+			r.Fields = append(r.Fields, wit.Field{Name: name, Type: typ, Docs: docs(field.Doc)})
+		}
+	}
+	if len(r.Fields) == 0 {
+		return nil, s.errorf(st.Pos(), "struct has no exported fields; a WIT record must have at least one field")
+	}
+	return r, nil
+}
+
+// function returns the WIT function for Go function decl. It returns nil if
+// the function is exported by an interface, to which it is added.
+func (s *synth) function(decl *ast.FuncDecl) (*wit.Function, error) {
+	if decl.Recv != nil {
+		return nil, s.errorf(decl.Pos(), "method %s cannot be exported", decl.Name.Name)
+	}
+	if decl.Type.TypeParams != nil {
+		return nil, s.errorf(decl.Pos(), "generic function %s cannot be exported", decl.Name.Name)
+	}
+	ifaceName, _ := exportDirective(decl.Doc)
+	var owner wit.TypeOwner = s.world
+	var iface *wit.Interface
+	if ifaceName != "" {
+		if !validName(ifaceName) {
+			return nil, s.errorf(decl.Pos(), "invalid interface name %q", ifaceName)
+		}
+		iface = s.iface(ifaceName)
+		owner = iface
+	}
+
+	f := &wit.Function{Name: Name(decl.Name.Name), Kind: &wit.Freestanding{}, Docs: docs(decl.Doc)}
+	for _, field := range decl.Type.Params.List {
+		typ, err := s.typ(owner, field.Type)
+		if err != nil {
+			return nil, err
+		}
+		if len(field.Names) == 0 {
+			return nil, s.errorf(field.Pos(), "function %s: params must be named", decl.Name.Name)
+		}
+		for _, ident := range field.Names {
+			name := Name(ident.Name)
+			if !validName(name) {
+				return nil, s.errorf(ident.Pos(), "function %s: invalid param name %q", decl.Name.Name, ident.Name)
+			}
+			f.Params = append(f.Params, wit.Param{Name: name, Type: typ})
+		}
+	}
+	if err := s.results(owner, decl, f); err != nil {
+		return nil, err
+	}
+
+	if iface == nil {
+		if s.worldFuncs[f.Name] {
+			return nil, s.errorf(decl.Pos(), "function %s already exported", f.Name)
+		}
+		s.worldFuncs[f.Name] = true
+		return f, nil
+	}
+	if _, ok := iface.Functions.GetOK(f.Name); ok {
+		return nil, s.errorf(decl.Pos(), "function %s already declared in interface %s", f.Name, ifaceName)
+	}
+	iface.Functions.Set(f.Name, f)
+	return nil, nil
+}
+
+// results sets the result of f from the results of Go function decl.
+// A trailing error result is lowered to a WIT result with a string error.
+func (s *synth) results(owner wit.TypeOwner, decl *ast.FuncDecl, f *wit.Function) error {
+	var exprs []ast.Expr
+	if decl.Type.Results != nil {
+		for _, field := range decl.Type.Results.List {
+			n := max(len(field.Names), 1)
+			for i := 0; i < n; i++ {
+				exprs = append(exprs, field.Type)
+			}
+		}
+	}
+	var isErr bool
+	if n := len(exprs); n > 0 {
+		if ident, ok := exprs[n-1].(*ast.Ident); ok && ident.Name == "error" {
+			isErr = true
+			exprs = exprs[:n-1]
+		}
+	}
+	if len(exprs) > 1 {
+		return s.errorf(decl.Pos(), "function %s: multiple results are not supported; return a struct", decl.Name.Name)
+	}
+	var typ wit.Type
+	if len(exprs) == 1 {
+		var err error
+		typ, err = s.typ(owner, exprs[0])
+		if err != nil {
+			return err
+		}
+	}
+	if isErr {
+		typ = s.anonymous(&wit.Result{OK: typ, Err: wit.String{}})
+	}
+	if typ != nil {
+		f.Results = []wit.Param{{Type: typ}}
+	}
+	return nil
+}
+
+// typ returns the WIT type of Go type expression expr used in owner.
+func (s *synth) typ(owner wit.TypeOwner, expr ast.Expr) (wit.Type, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if t, ok := s.types[expr.Name]; ok {
+			td, err := s.typeDef(t)
+			if err != nil {
+				return nil, err
+			}
+			return s.use(owner, td), nil
+		}
+		switch expr.Name {
+		case "bool":
+			return wit.Bool{}, nil
+		case "int8":
+			return wit.S8{}, nil
+		case "int16":
+			return wit.S16{}, nil
+		case "int32":
+			return wit.S32{}, nil
+		case "int64":
+			return wit.S64{}, nil
+		case "uint8", "byte":
+			return wit.U8{}, nil
+		case "uint16":
+			return wit.U16{}, nil
+		case "uint32":
+			return wit.U32{}, nil
+		case "uint64":
+			return wit.U64{}, nil
+		case "float32":
+			return wit.F32{}, nil
+		case "float64":
+			return wit.F64{}, nil
+		case "rune":
+			return wit.Char{}, nil
+		case "string":
+			return wit.String{}, nil
+		case "int", "uint", "uintptr":
+			return nil, s.errorf(expr.Pos(), "type %s has a platform-dependent size; use a sized integer type, e.g. %s32", expr.Name, expr.Name)
+		}
+		return nil, s.errorf(expr.Pos(), "unsupported type %s: add a %s directive to its declaration", expr.Name, ExportDirective)
+
+	case *ast.ParenExpr:
+		return s.typ(owner, expr.X)
+	}
+	kind, err := s.kind(owner, expr)
+	if err != nil {
+		return nil, err
+	}
+	return s.anonymous(kind), nil
+}
+
+// kind returns the WIT type definition kind of Go type expression expr used in owner,
+// e.g. list<T> for []T.
+func (s *synth) kind(owner wit.TypeOwner, expr ast.Expr) (wit.TypeDefKind, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident, *ast.ParenExpr:
+		return s.typ(owner, expr)
+
+	case *ast.ArrayType:
+		if expr.Len != nil {
+			break
+		}
+		elem, err := s.typ(owner, expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &wit.List{Type: elem}, nil
+
+	case *ast.StarExpr:
+		elem, err := s.typ(owner, expr.X)
+		if err != nil {
+			return nil, err
+		}
+		return &wit.Option{Type: elem}, nil
+
+	case *ast.IndexExpr:
+		sel, ok := expr.X.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "cm" {
+			break
+		}
+		switch sel.Sel.Name {
+		case "List", "Option":
+		default:
+			return nil, s.errorf(expr.Pos(), "unsupported type cm.%s", sel.Sel.Name)
+		}
+		elem, err := s.typ(owner, expr.Index)
+		if err != nil {
+			return nil, err
+		}
+		if sel.Sel.Name == "List" {
+			return &wit.List{Type: elem}, nil
+		}
+		return &wit.Option{Type: elem}, nil
+	}
+	return nil, s.errorf(expr.Pos(), "unsupported type %s", describeType(expr))
+}
+
+// anonymous returns an anonymous [wit.TypeDef] of kind.
+func (s *synth) anonymous(kind wit.TypeDefKind) *wit.TypeDef {
+	td := &wit.TypeDef{Kind: kind}
+	s.res.TypeDefs = append(s.res.TypeDefs, td)
+	return td
+}
+
+// use returns td if it is owned by owner, otherwise a [wit.TypeDef] in owner
+// that uses td, e.g. use types.{entry}.
+func (s *synth) use(owner wit.TypeOwner, td *wit.TypeDef) *wit.TypeDef {
+	if td.Owner == owner {
+		return td
+	}
+	key := useKey{owner, td}
+	if u, ok := s.uses[key]; ok {
+		return u
+	}
+	u := &wit.TypeDef{Name: td.Name, Kind: td, Owner: owner}
+	s.uses[key] = u
+	s.res.TypeDefs = append(s.res.TypeDefs, u)
+	switch owner := owner.(type) {
+	case *wit.Interface:
+		owner.TypeDefs.Set(*u.Name, u)
+	case *wit.World:
+		s.worldUses = append(s.worldUses, u)
+	}
+	return u
+}
+
+// Name returns the WIT name of Go identifier name in kebab case,
+// e.g. HandleRequest is handle-request and HTTPServer is http-server.
+func Name(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if r == '_' {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteRune('-')
+			}
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) && b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteRune('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// validName returns true if s is a valid WIT identifier in kebab case,
+// where each word starts with a lowercase letter.
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, word := range strings.Split(s, "-") {
+		if word == "" || word[0] < 'a' || word[0] > 'z' {
+			return false
+		}
+		for _, c := range word {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// directive returns the argument of comment text if it is directive prefix.
+func directive(text, prefix string) (arg string, ok bool) {
+	rest, ok := strings.CutPrefix(text, prefix)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// exportDirective returns the interface name of the //wit:export directive in doc, if any.
+func exportDirective(doc *ast.CommentGroup) (iface string, ok bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, c := range doc.List {
+		if arg, ok := directive(c.Text, ExportDirective); ok {
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// docs returns the WIT docs for Go doc comment doc, without directives.
+func docs(doc *ast.CommentGroup) wit.Docs {
+	if doc == nil {
+		return wit.Docs{}
+	}
+	return wit.Docs{Contents: strings.TrimSpace(doc.Text())}
+}
+
+// describeType returns a short description of Go type expression expr for error messages.
+func describeType(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.ArrayType:
+		return "array"
+	case *ast.MapType:
+		return "map"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.FuncType:
+		return "func"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.StructType:
+		return "struct (declare a named type with a " + ExportDirective + " directive)"
+	case *ast.SelectorExpr:
+		if x, ok := expr.X.(*ast.Ident); ok {
+			return x.Name + "." + expr.Sel.Name
+		}
+	}
+	return fmt.Sprintf("%T", expr)
+}
@@ -0,0 +1,173 @@
+package witgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+const appSource = `// Package app is a component.
+//
+//wit:world example:app/app@0.1.0
+package app
+
+import "github.com/ydnar/wasm-tools-go/cm"
+
+// Entry is a cache entry.
+//
+//wit:export
+type Entry struct {
+	// Key is the entry key.
+	Key   string
+	Value []byte
+	TTL   *uint32 ` + "`wit:\"ttl-seconds\"`" + `
+	internal int
+}
+
+//wit:export
+type Tags cm.List[string]
+
+// Greet returns a greeting.
+//
+//wit:export
+func Greet(name string, initial rune) string { return "" }
+
+//wit:export
+func Ping() {}
+
+//wit:export cache
+func Lookup(key string) (Entry, error) { return Entry{}, nil }
+
+//wit:export cache
+func Store(e Entry, tags Tags) error { return nil }
+
+//wit:export cache
+func HTTPStatus() uint16 { return 0 }
+
+func notExported() {}
+`
+
+const appWIT = `package example:app@0.1.0;
+
+interface types {
+	/// Entry is a cache entry.
+	record entry {
+		/// Key is the entry key.
+		key: string,
+		value: list<u8>,
+		ttl-seconds: option<u32>,
+	}
+	type tags = list<string>;
+}
+
+interface cache {
+	use types.{entry};
+	use types.{tags};
+	lookup: func(key: string) -> result<entry, string>;
+	store: func(e: entry, tags: tags) -> result<_, string>;
+	http-status: func() -> u16;
+}
+
+world app {
+	import types;
+	/// Greet returns a greeting.
+	export greet: func(name: string, initial: char) -> string;
+	export ping: func();
+	export cache;
+}
+`
+
+func parseFiles(t *testing.T, srcs ...string) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, src := range srcs {
+		f, err := parser.ParseFile(fset, "app.go", src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	return fset, files
+}
+
+func TestSynthesize(t *testing.T) {
+	fset, files := parseFiles(t, appSource)
+	res, err := Synthesize(fset, files, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := res.WIT(nil, "")
+	if got != appWIT {
+		t.Errorf("Synthesize:\n%s\nexpected:\n%s", got, appWIT)
+	}
+
+	// The synthesized WIT is valid.
+	res2, err := wit.LoadFS(fstest.MapFS{"wit/app.wit": {Data: []byte(got)}}, "wit")
+	if err != nil {
+		t.Fatalf("LoadFS: %v\n%s", err, got)
+	}
+	if got2 := res2.WIT(nil, ""); got2 != got {
+		t.Errorf("LoadFS(Synthesize):\n%s\nexpected:\n%s", got2, got)
+	}
+
+	// The world can be overridden.
+	res, err = Synthesize(fset, files, "example:other/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := res.Worlds[0].ID(); w.String() != "example:other/other" {
+		t.Errorf("Synthesize: world %s, expected example:other/other", w.String())
+	}
+}
+
+func TestSynthesizeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no world", "package app\n", "no //wit:world directive"},
+		{"invalid world", "//wit:world app\npackage app\n", "invalid world"},
+		{"int", "//wit:world a:b/c\npackage app\n//wit:export\nfunc F(x int) {}\n", "platform-dependent size"},
+		{"map", "//wit:world a:b/c\npackage app\n//wit:export\nfunc F(x map[string]string) {}\n", "unsupported type map"},
+		{"unexported type", "//wit:world a:b/c\npackage app\ntype T struct{ X uint32 }\n//wit:export\nfunc F(x T) {}\n", "add a //wit:export directive"},
+		{"recursive", "//wit:world a:b/c\npackage app\n//wit:export\ntype T struct{ Next *T }\n", "recursive"},
+		{"method", "//wit:world a:b/c\npackage app\ntype T struct{}\n//wit:export\nfunc (T) F() {}\n", "method F cannot be exported"},
+		{"multiple results", "//wit:world a:b/c\npackage app\n//wit:export\nfunc F() (uint32, uint32) { return 0, 0 }\n", "multiple results"},
+		{"interface name", "//wit:world a:b/c\npackage app\n//wit:export My_API\nfunc F() {}\n", "invalid interface name"},
+		{"duplicate", "//wit:world a:b/c\npackage app\n//wit:export\nfunc F() {}\n//wit:export\nfunc f() {}\n", "already exported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, files := parseFiles(t, tt.src)
+			_, err := Synthesize(fset, files, "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Synthesize: %v, expected error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Greet", "greet"},
+		{"HandleRequest", "handle-request"},
+		{"HTTPServer", "http-server"},
+		{"userID", "user-id"},
+		{"UTF8String", "utf8-string"},
+		{"snake_case", "snake-case"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.in); got != tt.want {
+			t.Errorf("Name(%q): %q, expected %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Package witvet checks Go packages for generated files that were edited by hand,
+// go:wasmimport functions that do not match a WIT world, and calls that pass a Go
+// pointer to the host as an integer without keeping the pointed-to value alive.
+package witvet
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+	"github.com/ydnar/wasm-tools-go/internal/witcli"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Finding is a problem found by [Vet].
+type Finding struct {
+	Pos     token.Position
+	Message string
+}
+
+// Vet checks each package in pkgs that is not only a dependency of another package,
+// and returns the problems found, sorted by position, and the number of packages checked.
+// If w is not nil, go:wasmimport functions are checked against the Core WebAssembly
+// imports of world w.
+func Vet(fset *token.FileSet, w *wit.World, pkgs []*Package) (findings []Finding, vetted int) {
+	v := newVetter(fset, w, pkgs)
+	for _, p := range pkgs {
+		if !p.DepOnly {
+			v.vetPackage(p)
+		}
+	}
+	sort.SliceStable(v.findings, func(i, j int) bool {
+		a, b := v.findings[i].Pos, v.findings[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return v.findings, v.vetted
+}
+
+// vetter checks Go packages, optionally against a WIT world.
+type vetter struct {
+	fset     *token.FileSet
+	world    *wit.World
+	worldID  string
+	imports  map[[2]string]*wasm.FuncType // Core imports of world, if set
+	modules  map[string]bool              // Import modules of world, if set
+	wasm     map[types.Object]string      // wasmimport functions in all packages, with linker names
+	findings []Finding
+	vetted   int
+}
+
+func newVetter(fset *token.FileSet, w *wit.World, pkgs []*Package) *vetter {
+	v := &vetter{
+		fset:  fset,
+		world: w,
+		wasm:  make(map[types.Object]string),
+	}
+	if w != nil {
+		id := w.ID()
+		v.worldID = id.String()
+		v.imports = make(map[[2]string]*wasm.FuncType)
+		v.modules = make(map[string]bool)
+		for _, f := range witcli.CoreImports(w) {
+			v.imports[[2]string{f.Module, f.Name}] = f.Type
+			v.modules[f.Module] = true
+			if f.Module == witcli.RootModule {
+				// Functions imported by world-level items can also use the world name,
+				// as emitted by earlier versions of wit-bindgen-go.
+				v.imports[[2]string{v.worldID, f.Name}] = f.Type
+				v.modules[v.worldID] = true
+			}
+		}
+	}
+	for _, p := range pkgs {
+		if p.info == nil {
+			continue
+		}
+		for _, f := range p.files {
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				if module, name, ok := wasmImport(fn); ok {
+					if obj := p.info.Defs[fn.Name]; obj != nil {
+						v.wasm[obj] = module + " " + name
+					}
+				}
+			}
+		}
+	}
+	return v
+}
+
+func (v *vetter) report(pos token.Pos, format string, args ...any) {
+	v.findings = append(v.findings, Finding{Pos: v.fset.Position(pos), Message: fmt.Sprintf(format, args...)})
+}
+
+// vetPackage checks the files in package p.
+func (v *vetter) vetPackage(p *Package) {
+	if p.info == nil {
+		return
+	}
+	v.vetted++
+	for _, f := range p.files {
+		v.checkGenerated(f)
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if module, name, ok := wasmImport(fn); ok {
+				v.checkWasmImport(p, fn, module, name)
+			}
+			if fn.Body != nil && !ast.IsGenerated(f) {
+				v.checkKeepAlive(p, fn)
+			}
+		}
+	}
+}
+
+// checkGenerated reports a generated file whose contents do not match the checksum in its header,
+// which indicates the file was edited by hand and changes will be lost when it is regenerated.
+func (v *vetter) checkGenerated(f *ast.File) {
+	if !ast.IsGenerated(f) {
+		return
+	}
+	name := v.fset.Position(f.Package).Filename
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return
+	}
+	if found, ok := gen.VerifyChecksum(src); found && !ok {
+		v.report(f.FileStart, "generated file was edited: contents do not match checksum; regenerate it, or move hand-written code to another file")
+	}
+}
+
+// checkWasmImport reports a go:wasmimport function that is not imported by the world,
+// or whose flattened Go signature does not match the Core WebAssembly signature in the world.
+func (v *vetter) checkWasmImport(p *Package, fn *ast.FuncDecl, module, name string) {
+	if v.world == nil {
+		return
+	}
+	want, ok := v.imports[[2]string{module, name}]
+	if !ok {
+		// Only report imports that look like Component Model imports.
+		if v.modules[module] || strings.Contains(module, ":") || strings.HasPrefix(module, "[export]") || module == witcli.RootModule {
+			v.report(fn.Pos(), "go:wasmimport %q %q: not imported by world %s", module, name, v.worldID)
+		}
+		return
+	}
+	obj, ok := p.info.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	got, ok := flatSignature(obj.Type().(*types.Signature))
+	if !ok {
+		// The signature contains a variant or result passed by value,
+		// whose Go representation need not flatten to the same Core WebAssembly types.
+		return
+	}
+	if got.String() != want.String() {
+		v.report(fn.Pos(), "go:wasmimport %q %q: %s has signature %s, expected %s", module, name, fn.Name.Name, got, want)
+	}
+}
+
+// checkKeepAlive reports calls in fn to wasmimport functions that pass a Go pointer
+// converted to an integer, e.g. uintptr(unsafe.Pointer(p)), if fn does not call
+// runtime.KeepAlive. The garbage collector does not treat an integer as a reference,
+// so the memory it points to may be freed or moved while the host is using it.
+func (v *vetter) checkKeepAlive(p *Package, fn *ast.FuncDecl) {
+	var calls []*ast.CallExpr
+	keepAlive := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if isPkgFunc(p.info, call.Fun, "runtime", "KeepAlive") {
+			keepAlive = true
+		}
+		if _, ok := v.wasm[callee(p.info, call.Fun)]; ok {
+			calls = append(calls, call)
+		}
+		return true
+	})
+	if keepAlive {
+		return
+	}
+	for _, call := range calls {
+		name := v.wasm[callee(p.info, call.Fun)]
+		for _, arg := range call.Args {
+			if pointerToInt(p.info, arg) {
+				v.report(call.Pos(), "call to wasmimport %q passes a pointer as an integer; add runtime.KeepAlive after the call so the pointed-to value is not freed", name)
+				break
+			}
+		}
+	}
+}
+
+// wasmImport returns the module and name in the go:wasmimport directive of fn, if any.
+func wasmImport(fn *ast.FuncDecl) (module, name string, ok bool) {
+	if fn.Doc == nil {
+		return "", "", false
+	}
+	for _, c := range fn.Doc.List {
+		args, ok := strings.CutPrefix(c.Text, "//go:wasmimport ")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(args)
+		if len(fields) != 2 {
+			return "", "", false
+		}
+		return fields[0], fields[1], true
+	}
+	return "", "", false
+}
+
+// callee returns the function or method called by expression fun, if known.
+func callee(info *types.Info, fun ast.Expr) types.Object {
+	switch fun := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return info.Uses[fun]
+	case *ast.SelectorExpr:
+		return info.Uses[fun.Sel]
+	}
+	return nil
+}
+
+// isPkgFunc returns true if fun refers to the function name in package path.
+func isPkgFunc(info *types.Info, fun ast.Expr, path, name string) bool {
+	sel, ok := ast.Unparen(fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkg, ok := info.Uses[x].(*types.PkgName)
+	return ok && pkg.Imported().Path() == path
+}
+
+// pointerToInt returns true if expression x contains a conversion of an
+// unsafe.Pointer to uintptr or another integer type.
+func pointerToInt(info *types.Info, x ast.Expr) bool {
+	found := false
+	ast.Inspect(x, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found || len(call.Args) != 1 {
+			return !found
+		}
+		tv, ok := info.Types[call.Fun]
+		if !ok || !tv.IsType() {
+			return true
+		}
+		to, ok := tv.Type.Underlying().(*types.Basic)
+		if !ok || to.Info()&types.IsInteger == 0 {
+			return true
+		}
+		from, ok := info.Types[call.Args[0]].Type.(*types.Basic)
+		found = ok && from.Kind() == types.UnsafePointer
+		return !found
+	})
+	return found
+}
+
+// flatSignature returns the Core WebAssembly function type of a go:wasmimport
+// function with signature sig, as lowered by TinyGo for wasm32: the receiver,
+// params, and results flattened into Core WebAssembly value types.
+// It returns false if sig contains a type that cannot be checked.
+func flatSignature(sig *types.Signature) (*wasm.FuncType, bool) {
+	var ft wasm.FuncType
+	if recv := sig.Recv(); recv != nil {
+		flat, ok := flatType(recv.Type())
+		if !ok {
+			return nil, false
+		}
+		ft.Params = append(ft.Params, flat...)
+	}
+	for _, tuple := range []*types.Tuple{sig.Params(), sig.Results()} {
+		for i := 0; i < tuple.Len(); i++ {
+			flat, ok := flatType(tuple.At(i).Type())
+			if !ok {
+				return nil, false
+			}
+			if tuple == sig.Params() {
+				ft.Params = append(ft.Params, flat...)
+			} else {
+				ft.Results = append(ft.Results, flat...)
+			}
+		}
+	}
+	return &ft, true
+}
+
+// flatType returns the Core WebAssembly value types of Go type t on wasm32.
+// It returns false if t is not valid in a go:wasmimport function, or is a
+// variant or result, such as [cm.Variant], which are detected by a zero-length
+// array field used to align their storage.
+func flatType(t types.Type) ([]wasm.ValType, bool) {
+	switch t := t.Underlying().(type) {
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Int64, types.Uint64:
+			return []wasm.ValType{wasm.I64}, true
+		case types.Float32:
+			return []wasm.ValType{wasm.F32}, true
+		case types.Float64:
+			return []wasm.ValType{wasm.F64}, true
+		case types.String:
+			return []wasm.ValType{wasm.I32, wasm.I32}, true
+		case types.Bool, types.Int, types.Int8, types.Int16, types.Int32,
+			types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uintptr, types.UnsafePointer:
+			return []wasm.ValType{wasm.I32}, true
+		}
+	case *types.Pointer:
+		return []wasm.ValType{wasm.I32}, true
+	case *types.Struct:
+		var flat []wasm.ValType
+		for i := 0; i < t.NumFields(); i++ {
+			f := t.Field(i)
+			if a, ok := f.Type().Underlying().(*types.Array); ok && a.Len() == 0 {
+				return nil, false
+			}
+			ft, ok := flatType(f.Type())
+			if !ok {
+				return nil, false
+			}
+			flat = append(flat, ft...)
+		}
+		return flat, true
+	case *types.Array:
+		elem, ok := flatType(t.Elem())
+		if !ok {
+			return nil, false
+		}
+		var flat []wasm.ValType
+		for i := int64(0); i < t.Len(); i++ {
+			flat = append(flat, elem...)
+		}
+		return flat, true
+	}
+	return nil, false
+}
@@ -0,0 +1,124 @@
+package witvet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Package is a Go package reported by go list, and its syntax and type information.
+type Package struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Standard   bool
+	DepOnly    bool
+
+	files []*ast.File
+	types *types.Package
+	info  *types.Info
+}
+
+// Load lists the Go packages matching patterns in directory dir and their dependencies,
+// then parses and type-checks them from source. Type errors are ignored, as
+// [Vet] only needs the declarations of wasmimport functions and the types they use.
+// Dependencies are checked without function bodies. If dir is empty, the current
+// directory is used.
+func Load(ctx context.Context, dir, tags string, patterns []string) (*token.FileSet, []*Package, error) {
+	args := []string{"list", "-e", "-deps", "-json=ImportPath,Dir,GoFiles,Standard,DepOnly"}
+	if tags != "" {
+		args = append(args, "-tags", tags)
+	}
+	args = append(args, patterns...)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("go list: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	l := &loader{
+		fset: token.NewFileSet(),
+		pkgs: make(map[string]*Package),
+	}
+	var pkgs []*Package
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		p := &Package{}
+		err := dec.Decode(p)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("go list: %w", err)
+		}
+		l.pkgs[p.ImportPath] = p
+		pkgs = append(pkgs, p)
+	}
+	for _, p := range pkgs {
+		if !p.DepOnly {
+			l.check(p)
+		}
+	}
+	return l.fset, pkgs, nil
+}
+
+// loader type-checks Go packages from source. It implements [types.Importer].
+type loader struct {
+	fset *token.FileSet
+	pkgs map[string]*Package
+}
+
+func (l *loader) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	p, ok := l.pkgs[path]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found", path)
+	}
+	return l.check(p), nil
+}
+
+func (l *loader) check(p *Package) *types.Package {
+	if p.types != nil {
+		return p.types
+	}
+	// Set a placeholder to break import cycles in invalid code.
+	p.types = types.NewPackage(p.ImportPath, "")
+	for _, name := range p.GoFiles {
+		f, err := parser.ParseFile(l.fset, filepath.Join(p.Dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if f != nil {
+			p.files = append(p.files, f)
+		}
+		_ = err // Syntax errors are reported by the Go toolchain.
+	}
+	conf := types.Config{
+		Importer:         l,
+		IgnoreFuncBodies: p.DepOnly,
+		FakeImportC:      true,
+		Error:            func(error) {},
+	}
+	p.info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	pkg, _ := conf.Check(p.ImportPath, l.fset, p.files, p.info)
+	if pkg != nil {
+		p.types = pkg
+	}
+	return p.types
+}
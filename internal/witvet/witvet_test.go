@@ -0,0 +1,192 @@
+package witvet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+const testWIT = `package example:app;
+
+interface host {
+	get: func(x: u32) -> u64;
+}
+
+world app {
+	import host;
+	import ping: func();
+}
+`
+
+const imports = `package x
+
+//go:wasmimport example:app/host get
+func get(x uint32) uint64
+
+//go:wasmimport $root ping
+func ping()
+`
+
+// generated returns a generated Go file with body and a checksum header.
+// If valid is false, the checksum does not match body.
+func generated(body string, valid bool) string {
+	sum := sha256.Sum256([]byte(body))
+	if !valid {
+		sum[0] ^= 0xff
+	}
+	return "// Code generated by test. DO NOT EDIT.\n" + gen.ChecksumPrefix + hex.EncodeToString(sum[:]) + "\n\n" + body
+}
+
+func TestVet(t *testing.T) {
+	if err := exec.Command("go", "version").Run(); err != nil {
+		t.Skip("go not available:", err)
+	}
+	res, err := wit.LoadFS(fstest.MapFS{"app.wit": {Data: []byte(testWIT)}}, "app.wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := res.Worlds[0]
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		world *wit.World
+		want  []string // file name and message of each finding, e.g. "x.go: not imported"
+	}{
+		{
+			name: "clean",
+			files: map[string]string{
+				"imports.go": imports,
+				"alias.go": `package x
+
+//go:wasmimport example:app/app ping
+func pingWorld()
+
+//go:wasmimport env other
+func other()
+`,
+				"use.go": `package x
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+func f(v *uint32) uint64 {
+	r := get(uint32(uintptr(unsafe.Pointer(v))))
+	runtime.KeepAlive(v)
+	return r
+}
+`,
+				"gen.wit.go": generated("package x\n\nfunc g() {}\n", true),
+			},
+			world: world,
+		},
+		{
+			name: "no world",
+			files: map[string]string{
+				"x.go": "package x\n\n//go:wasmimport example:app/host put\nfunc put(x uint64)\n",
+			},
+		},
+		{
+			name: "edited generated file",
+			files: map[string]string{
+				"gen.wit.go": generated("package x\n\nfunc g() {}\n", false),
+			},
+			want: []string{"gen.wit.go: generated file was edited"},
+		},
+		{
+			name: "not imported",
+			files: map[string]string{
+				"imports.go": imports,
+				"x.go":       "package x\n\n//go:wasmimport example:app/host put\nfunc put(x uint64)\n\n//go:wasmimport $root pong\nfunc pong()\n",
+			},
+			world: world,
+			want: []string{
+				`x.go: go:wasmimport "example:app/host" "put": not imported by world example:app/app`,
+				`x.go: go:wasmimport "$root" "pong": not imported by world example:app/app`,
+			},
+		},
+		{
+			name: "signature",
+			files: map[string]string{
+				"x.go": "package x\n\n//go:wasmimport example:app/host get\nfunc get(x uint64) uint64\n",
+			},
+			world: world,
+			want:  []string{`x.go: go:wasmimport "example:app/host" "get": get has signature (func (param i64) (result i64)), expected (func (param i32) (result i64))`},
+		},
+		{
+			name: "variant signature",
+			files: map[string]string{
+				"x.go": "package x\n\ntype v struct {\n\ttag uint8\n\t_ [0]uint64\n\tdata uint64\n}\n\n//go:wasmimport example:app/host get\nfunc get(x v) uint64\n",
+			},
+			world: world,
+		},
+		{
+			name: "keep alive",
+			files: map[string]string{
+				"imports.go": imports,
+				"use.go": `package x
+
+import "unsafe"
+
+func f(v *uint32) uint64 {
+	return get(uint32(uintptr(unsafe.Pointer(v))))
+}
+`,
+			},
+			want: []string{`use.go: call to wasmimport "example:app/host get" passes a pointer as an integer`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.files["go.mod"] = "module example.com/x\n\ngo 1.22\n"
+			for name, src := range tt.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			fset, pkgs, err := Load(context.Background(), dir, "", []string{"."})
+			if err != nil {
+				t.Fatal(err)
+			}
+			findings, vetted := Vet(fset, tt.world, pkgs)
+			if vetted != 1 {
+				t.Errorf("Vet: %d packages vetted, expected 1", vetted)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, filepath.Base(f.Pos.Filename)+": "+f.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Vet: %d findings, expected %d:\n%s", len(got), len(tt.want), strings.Join(got, "\n"))
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("finding %d: %q, expected prefix %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadError(t *testing.T) {
+	if err := exec.Command("go", "version").Run(); err != nil {
+		t.Skip("go not available:", err)
+	}
+	_, _, err := Load(context.Background(), t.TempDir(), "", []string{"."})
+	if err == nil {
+		t.Error("Load: expected error outside a Go module")
+	}
+}
@@ -0,0 +1,193 @@
+//go:build !wasip1
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/ydnar/wasm-tools-go/wasi/blobstore/v0.2.0-draft/container"
+	"github.com/ydnar/wasm-tools-go/wasi/blobstore/v0.2.0-draft/types"
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+	"github.com/ydnar/wasm-tools-go/wit/iterate"
+)
+
+var errBucketClosed = errors.New("wasi:blobstore: bucket closed")
+
+// listBatchSize is the number of object names [Bucket.List] reads from the host at a time.
+const listBatchSize = 100
+
+// Bucket is a blobstore [container.Container] with methods that read and write objects
+// as Go streams. Errors from the host are returned as a [types.Error].
+//
+// Calls to the host are synchronous and cannot be interrupted. Each method returns
+// ctx.Err() without calling the host if ctx is done.
+type Bucket struct {
+	container container.Container
+	closed    bool
+}
+
+// OpenBucket opens the existing container name.
+func OpenBucket(ctx context.Context, name string) (*Bucket, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := GetContainer(types.ContainerName(name))
+	if err := result.Err(); err != nil {
+		return nil, *err
+	}
+	return NewBucket(*result.OK()), nil
+}
+
+// NewBucket returns a [Bucket] that takes ownership of c.
+func NewBucket(c container.Container) *Bucket {
+	return &Bucket{container: c}
+}
+
+// Put creates or replaces the object key with the contents of r, which is read until [io.EOF].
+// The contents are streamed to the host, rather than buffered in memory.
+// If reading r fails, the object is not finished, and the host discards it.
+func (b *Bucket) Put(ctx context.Context, key string, r io.Reader) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	value := types.OutgoingValueNewOutgoingValue()
+	body := value.OutgoingValueWriteBody()
+	if body.IsErr() {
+		value.ResourceDrop()
+		return errors.New("wasi:blobstore: outgoing-value stream already taken")
+	}
+	w := streams.NewWriter(*body.OK())
+	result := b.container.WriteData(types.ObjectName(key), value)
+	if err := result.Err(); err != nil {
+		w.Close()
+		value.ResourceDrop()
+		return *err
+	}
+	_, err := io.Copy(w, r)
+	if err == nil {
+		err = w.Flush()
+	}
+	// The stream is a child of the outgoing value, and must be dropped first.
+	w.Close()
+	if err != nil {
+		value.ResourceDrop()
+		return err
+	}
+	finish := types.OutgoingValueFinish(value)
+	if err := finish.Err(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Get returns a reader for the contents of object key. The caller must close it.
+// The contents are streamed from the host, rather than buffered in memory.
+func (b *Bucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := b.check(ctx); err != nil {
+		return nil, err
+	}
+	info := b.container.ObjectInfo(types.ObjectName(key))
+	if err := info.Err(); err != nil {
+		return nil, *err
+	}
+	size := info.OK().Size
+	if size == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	// Start and end offsets are inclusive.
+	data := b.container.GetData(types.ObjectName(key), 0, uint64(size)-1)
+	if err := data.Err(); err != nil {
+		return nil, *err
+	}
+	body := types.IncomingValueIncomingValueConsumeAsync(*data.OK())
+	if err := body.Err(); err != nil {
+		return nil, *err
+	}
+	return streams.NewReader(*body.OK()), nil
+}
+
+// List returns a sequence of the names of the objects in b, in an order chosen by the host.
+// If an error occurs, the sequence yields it with an empty name and stops.
+func (b *Bucket) List(ctx context.Context) iterate.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		if err := b.check(ctx); err != nil {
+			yield("", err)
+			return
+		}
+		result := b.container.ListObjects()
+		if err := result.Err(); err != nil {
+			yield("", *err)
+			return
+		}
+		names := *result.OK()
+		defer names.ResourceDrop()
+		for {
+			if err := ctx.Err(); err != nil {
+				yield("", err)
+				return
+			}
+			read := names.ReadStreamObjectNames(listBatchSize)
+			if err := read.Err(); err != nil {
+				yield("", *err)
+				return
+			}
+			batch := read.OK()
+			for _, name := range batch.F0.Slice() {
+				if !yield(string(name), nil) {
+					return
+				}
+			}
+			if batch.F1 {
+				return
+			}
+		}
+	}
+}
+
+// Delete deletes object key. Deleting an object that does not exist is not an error.
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	if err := b.check(ctx); err != nil {
+		return err
+	}
+	result := b.container.DeleteObject(types.ObjectName(key))
+	if err := result.Err(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Exists reports whether object key exists.
+func (b *Bucket) Exists(ctx context.Context, key string) (bool, error) {
+	if err := b.check(ctx); err != nil {
+		return false, err
+	}
+	result := b.container.HasObject(types.ObjectName(key))
+	if err := result.Err(); err != nil {
+		return false, *err
+	}
+	return *result.OK(), nil
+}
+
+// Container returns the underlying container of b, which remains owned by b.
+func (b *Bucket) Container() container.Container {
+	return b.container
+}
+
+// Close drops the underlying container of b. Subsequent calls to Close do nothing.
+func (b *Bucket) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.container.ResourceDrop()
+	}
+	return nil
+}
+
+func (b *Bucket) check(ctx context.Context) error {
+	if b.closed {
+		return errBucketClosed
+	}
+	return ctx.Err()
+}
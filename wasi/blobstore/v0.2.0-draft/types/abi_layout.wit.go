@@ -0,0 +1,24 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:blobstore/imports@0.2.0-draft
+// Checksum: sha256:9cd2ed352f0e422c90bf31a03384f697604876b6bd0964df09d69ff0d237cd7e
+
+//go:build tinygo.wasm
+
+package types
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(ContainerMetadata{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ContainerMetadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(ObjectMetadata{}) - 32]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ObjectMetadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(ObjectID{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ObjectID{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(IncomingValueSyncBody{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(IncomingValueSyncBody{}) - 4]struct{}{}
@@ -0,0 +1,25 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:blobstore/imports@0.2.0-draft
+// Checksum: sha256:6805f841510d056f5c6c5a162a3fd6598fce8a707d51df4ebc2bfe7452efc4b9
+
+//go:build !wasip1 && tinygo.wasm
+
+package types
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(ContainerMetadata{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ContainerMetadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(ObjectMetadata{}) - 32]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ObjectMetadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(ObjectID{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ObjectID{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(IncomingValueSyncBody{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(IncomingValueSyncBody{}) - 4]struct{}{}
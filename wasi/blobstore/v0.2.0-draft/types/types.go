@@ -0,0 +1,8 @@
+//go:build !wasip1
+
+package types
+
+// Error implements the error interface.
+func (e Error) Error() string {
+	return "wasi:blobstore: " + string(e)
+}
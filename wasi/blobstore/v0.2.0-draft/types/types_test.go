@@ -0,0 +1,12 @@
+//go:build !wasip1
+
+package types
+
+import "testing"
+
+func TestError(t *testing.T) {
+	var err error = Error("container not found")
+	if got, want := err.Error(), "wasi:blobstore: container not found"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+}
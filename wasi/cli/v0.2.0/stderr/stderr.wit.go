@@ -0,0 +1,25 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.0
+// Checksum: sha256:6e704818a8a5344a6133c58f5b37e89ac3e043d21bb8a0bd1a83712833052421
+
+//go:build !wasip1
+
+// Package stderr represents the imported interface "wasi:cli/stderr@0.2.0".
+package stderr
+
+import (
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+)
+
+// GetStderr represents the imported function "get-stderr".
+//
+//	get-stderr: func() -> output-stream
+//
+//go:nosplit
+func GetStderr() streams.OutputStream {
+	return wasmimport_GetStderr()
+}
+
+//go:wasmimport wasi:cli/stderr@0.2.0 get-stderr
+//go:noescape
+func wasmimport_GetStderr() streams.OutputStream
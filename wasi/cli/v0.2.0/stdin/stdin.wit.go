@@ -0,0 +1,25 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.0
+// Checksum: sha256:954ca22b5bf8e9729fc9f6715af58067c00210096a912486f136c59740c52c94
+
+//go:build !wasip1
+
+// Package stdin represents the imported interface "wasi:cli/stdin@0.2.0".
+package stdin
+
+import (
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+)
+
+// GetStdin represents the imported function "get-stdin".
+//
+//	get-stdin: func() -> input-stream
+//
+//go:nosplit
+func GetStdin() streams.InputStream {
+	return wasmimport_GetStdin()
+}
+
+//go:wasmimport wasi:cli/stdin@0.2.0 get-stdin
+//go:noescape
+func wasmimport_GetStdin() streams.InputStream
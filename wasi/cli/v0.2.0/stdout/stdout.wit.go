@@ -0,0 +1,25 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.0
+// Checksum: sha256:0b30bad908188e46e582fd678e3bf454be061fe18f32a1939f32f5c3e0c480e7
+
+//go:build !wasip1
+
+// Package stdout represents the imported interface "wasi:cli/stdout@0.2.0".
+package stdout
+
+import (
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+)
+
+// GetStdout represents the imported function "get-stdout".
+//
+//	get-stdout: func() -> output-stream
+//
+//go:nosplit
+func GetStdout() streams.OutputStream {
+	return wasmimport_GetStdout()
+}
+
+//go:wasmimport wasi:cli/stdout@0.2.0 get-stdout
+//go:noescape
+func wasmimport_GetStdout() streams.OutputStream
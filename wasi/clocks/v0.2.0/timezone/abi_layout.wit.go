@@ -0,0 +1,18 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:clocks/imports@0.2.0
+// Checksum: sha256:f745689494955a599a63e68b0ac4b6ef595bf146f2032750fd28be4af33c2892
+
+//go:build tinygo.wasm
+
+package timezone
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(TimezoneDisplay{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(TimezoneDisplay{}) - 4]struct{}{}
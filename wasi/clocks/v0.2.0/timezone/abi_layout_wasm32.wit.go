@@ -0,0 +1,19 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:clocks/imports@0.2.0
+// Checksum: sha256:8d0b63d738e69c5558f8311989013c2ec5c88ca517460fae4c6371d9672baebe
+
+//go:build !wasip1 && tinygo.wasm
+
+package timezone
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(TimezoneDisplay{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(TimezoneDisplay{}) - 4]struct{}{}
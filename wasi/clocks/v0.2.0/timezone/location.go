@@ -0,0 +1,33 @@
+//go:build !wasip1
+
+package timezone
+
+import (
+	"time"
+
+	wallclock "github.com/ydnar/wasm-tools-go/wasi/clocks/v0.2.0/wall-clock"
+)
+
+// Location returns a [time.Location] for the time zone in effect at t, as reported by the host.
+// The returned Location has a fixed UTC offset, which may not apply to other times,
+// such as across a daylight saving time transition.
+func Location(t time.Time) *time.Location {
+	return Display(DateTime(t)).Location()
+}
+
+// Location returns a [time.Location] with the name and UTC offset of d.
+func (d TimezoneDisplay) Location() *time.Location {
+	return time.FixedZone(d.Name, int(d.UtcOffset))
+}
+
+// DateTime converts t to a [wallclock.DateTime].
+// Times before 1970-01-01T00:00:00Z are clamped to zero.
+func DateTime(t time.Time) wallclock.DateTime {
+	if t.Unix() < 0 {
+		return wallclock.DateTime{}
+	}
+	return wallclock.DateTime{
+		Seconds:     uint64(t.Unix()),
+		Nanoseconds: uint32(t.Nanosecond()),
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !wasip1
+
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimezoneDisplayLocation(t *testing.T) {
+	d := TimezoneDisplay{UtcOffset: -4 * 60 * 60, Name: "EDT", InDaylightSavingTime: true}
+	loc := d.Location()
+	when := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC).In(loc)
+	name, offset := when.Zone()
+	if name != "EDT" {
+		t.Errorf("Zone(): name %q, expected %q", name, "EDT")
+	}
+	if offset != -4*60*60 {
+		t.Errorf("Zone(): offset %d, expected %d", offset, -4*60*60)
+	}
+	if got, want := when.Hour(), 8; got != want {
+		t.Errorf("Hour(): %d, expected %d", got, want)
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	when := time.Unix(1717243200, 123)
+	dt := DateTime(when)
+	if dt.Seconds != 1717243200 || dt.Nanoseconds != 123 {
+		t.Errorf("DateTime(%v): %+v, expected {Seconds:1717243200 Nanoseconds:123}", when, dt)
+	}
+	if dt := DateTime(time.Unix(-1, 0)); dt.Seconds != 0 || dt.Nanoseconds != 0 {
+		t.Errorf("DateTime before epoch: %+v, expected zero", dt)
+	}
+}
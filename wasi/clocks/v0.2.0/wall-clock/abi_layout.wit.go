@@ -0,0 +1,19 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.0
+// Checksum: sha256:cf60b395d7059f06f07f6efc330ef62a5322bc102af6c97148966b8574788934
+
+//go:build !wasip1 && !(386 || arm || mips || mipsle)
+
+package wallclock
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation. Types in this file do not contain pointers,
+// and have the same layout on 64-bit architectures and 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(DateTime{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(DateTime{}) - 8]struct{}{}
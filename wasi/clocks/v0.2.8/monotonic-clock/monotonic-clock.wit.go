@@ -0,0 +1,108 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.8
+// Checksum: sha256:bb675656a69b8fc1d0522851c0a339ebf871c85d3d5fe14fecd1fd4ceed6680e
+
+//go:build !wasip1
+
+// Package monotonicclock represents the imported interface "wasi:clocks/monotonic-clock@0.2.8".
+//
+// WASI Monotonic Clock is a clock API intended to let users measure elapsed
+// time.
+//
+// It is intended to be portable at least between Unix-family platforms and
+// Windows.
+//
+// A monotonic clock is a clock which has an unspecified initial value, and
+// successive reads of the clock will produce non-decreasing values.
+package monotonicclock
+
+import (
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.8/poll"
+)
+
+// Instant represents the imported type "wasi:clocks/monotonic-clock@0.2.8#instant".
+//
+// An instant in time, in nanoseconds. An instant is relative to an
+// unspecified initial value, and can only be compared to instances from the
+// same monotonic-clock.
+//
+//	type instant = u64
+type Instant uint64
+
+// Duration represents the imported type "wasi:clocks/monotonic-clock@0.2.8#duration".
+//
+// A duration of time, in nanoseconds.
+//
+//	type duration = u64
+type Duration uint64
+
+// Now represents the imported function "now".
+//
+// Read the current value of the clock.
+//
+// The clock is monotonic, therefore calling this function repeatedly will
+// produce a sequence of non-decreasing values.
+//
+// For completeness, this function traps if it's not possible to represent
+// the value of the clock in an `instant`. Consequently, implementations
+// should ensure that the starting time is low enough to avoid the
+// possibility of overflow in practice.
+//
+//	now: func() -> instant
+//
+//go:nosplit
+func Now() Instant {
+	return wasmimport_Now()
+}
+
+//go:wasmimport wasi:clocks/monotonic-clock@0.2.8 now
+//go:noescape
+func wasmimport_Now() Instant
+
+// Resolution represents the imported function "resolution".
+//
+// Query the resolution of the clock. Returns the duration of time
+// corresponding to a clock tick.
+//
+//	resolution: func() -> duration
+//
+//go:nosplit
+func Resolution() Duration {
+	return wasmimport_Resolution()
+}
+
+//go:wasmimport wasi:clocks/monotonic-clock@0.2.8 resolution
+//go:noescape
+func wasmimport_Resolution() Duration
+
+// SubscribeInstant represents the imported function "subscribe-instant".
+//
+// Create a `pollable` which will resolve once the specified instant
+// has occurred.
+//
+//	subscribe-instant: func(when: instant) -> pollable
+//
+//go:nosplit
+func SubscribeInstant(when Instant) poll.Pollable {
+	return wasmimport_SubscribeInstant(when)
+}
+
+//go:wasmimport wasi:clocks/monotonic-clock@0.2.8 subscribe-instant
+//go:noescape
+func wasmimport_SubscribeInstant(when Instant) poll.Pollable
+
+// SubscribeDuration represents the imported function "subscribe-duration".
+//
+// Create a `pollable` that will resolve after the specified duration has
+// elapsed from the time this function is invoked.
+//
+//	subscribe-duration: func(when: duration) -> pollable
+//
+//go:nosplit
+func SubscribeDuration(when Duration) poll.Pollable {
+	return wasmimport_SubscribeDuration(when)
+}
+
+//go:wasmimport wasi:clocks/monotonic-clock@0.2.8 subscribe-duration
+//go:noescape
+func wasmimport_SubscribeDuration(when Duration) poll.Pollable
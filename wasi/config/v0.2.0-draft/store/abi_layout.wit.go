@@ -0,0 +1,18 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:config/imports@0.2.0-draft
+// Checksum: sha256:dd9eb66b5ac1e2a778a092bf433be65b7e4b54087217bbffd17525901e1333b6
+
+//go:build tinygo.wasm
+
+package store
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
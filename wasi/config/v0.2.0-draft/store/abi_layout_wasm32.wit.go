@@ -0,0 +1,19 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:config/imports@0.2.0-draft
+// Checksum: sha256:e978e33d3770ce5c513b1423e5796bf1149e29661d6fd4ce96a8d58992f3a3ae
+
+//go:build !wasip1 && tinygo.wasm
+
+package store
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
@@ -0,0 +1,90 @@
+//go:build !wasip1
+
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error implements the error interface.
+func (v Error) Error() string {
+	if s := v.Upstream(); s != nil {
+		return "wasi:config/store: upstream: " + *s
+	}
+	if s := v.IO(); s != nil {
+		return "wasi:config/store: io: " + *s
+	}
+	return "wasi:config/store: " + v.String()
+}
+
+// All returns all configuration values supplied by the host.
+func All() (map[string]string, error) {
+	result := GetAll()
+	if err := result.Err(); err != nil {
+		return nil, *err
+	}
+	return toMap(result.OK().Slice()), nil
+}
+
+// Lookup returns the configuration value for key. If key is not found, ok is false.
+func Lookup(key string) (value string, ok bool, err error) {
+	result := Get(key)
+	if err := result.Err(); err != nil {
+		return "", false, *err
+	}
+	v := result.OK().Some()
+	if v == nil {
+		return "", false, nil
+	}
+	return *v, true, nil
+}
+
+// LookupBool returns the configuration value for key parsed with [strconv.ParseBool].
+// If key is not found, ok is false.
+func LookupBool(key string) (value bool, ok bool, err error) {
+	return lookup(key, strconv.ParseBool)
+}
+
+// LookupInt returns the configuration value for key parsed as a base 10,
+// or prefixed base 2, 8, or 16 integer. If key is not found, ok is false.
+func LookupInt(key string) (value int64, ok bool, err error) {
+	return lookup(key, func(s string) (int64, error) {
+		return strconv.ParseInt(s, 0, 64)
+	})
+}
+
+// LookupDuration returns the configuration value for key parsed with [time.ParseDuration].
+// If key is not found, ok is false.
+func LookupDuration(key string) (value time.Duration, ok bool, err error) {
+	return lookup(key, time.ParseDuration)
+}
+
+func lookup[T any](key string, parse func(string) (T, error)) (T, bool, error) {
+	s, ok, err := Lookup(key)
+	if !ok || err != nil {
+		var zero T
+		return zero, ok, err
+	}
+	v, err := parseValue(key, s, parse)
+	return v, true, err
+}
+
+// parseValue parses s, the value for key, with parse, ignoring leading and trailing white space.
+func parseValue[T any](key, s string, parse func(string) (T, error)) (T, error) {
+	v, err := parse(strings.TrimSpace(s))
+	if err != nil {
+		return v, fmt.Errorf("wasi:config/store: key %q: %w", key, err)
+	}
+	return v, nil
+}
+
+func toMap(entries [][2]string) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, e := range entries {
+		m[e[0]] = e[1]
+	}
+	return m
+}
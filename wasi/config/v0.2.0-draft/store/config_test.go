@@ -0,0 +1,44 @@
+//go:build !wasip1
+
+package store
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestError(t *testing.T) {
+	var err error = ErrorUpstream("vault sealed")
+	if got, want := err.Error(), "wasi:config/store: upstream: vault sealed"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+	err = ErrorIO("read failed")
+	if got, want := err.Error(), "wasi:config/store: io: read failed"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	got := toMap([][2]string{{"a", "1"}, {"b", "2"}, {"a", "3"}})
+	want := map[string]string{"a": "3", "b": "2"}
+	if !maps.Equal(got, want) {
+		t.Errorf("toMap: %v, expected %v", got, want)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	d, err := parseValue("timeout", " 1m30s\n", time.ParseDuration)
+	if err != nil || d != 90*time.Second {
+		t.Errorf("parseValue(timeout): %v, %v, expected %v", d, err, 90*time.Second)
+	}
+	_, err = parseValue("debug", "maybe", strconv.ParseBool)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("parseValue(debug): %v, expected %v", err, strconv.ErrSyntax)
+	}
+	if got, want := err.Error(), `wasi:config/store: key "debug": strconv.ParseBool: parsing "maybe": invalid syntax`; got != want {
+		t.Errorf("parseValue(debug): %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:config/imports@0.2.0-draft
+// Checksum: sha256:2e3e8f065a62a3d5aede872b18875513010fb0b6e957b35f120e73f8fb68a98a
+
+//go:build !wasip1
+
+// Package store represents the imported interface "wasi:config/store@0.2.0-draft".
+package store
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Error represents the imported variant "wasi:config/store@0.2.0-draft#error".
+//
+// An error type that encapsulates the different errors that can occur fetching configuration
+// values.
+//
+//	variant error {
+//		upstream(string),
+//		io(string),
+//	}
+type Error cm.Variant[uint8, string, string]
+
+// ErrorUpstream returns a [Error] of case "upstream".
+//
+// This indicates an error from an "upstream" config source.
+// As this could be almost _anything_ (such as Vault, Kubernetes ConfigMaps, KeyValue
+// buckets, etc),
+// the error message is a string.
+func ErrorUpstream(data string) Error {
+	return cm.New[Error](0, data)
+}
+
+// Upstream returns a non-nil *[string] if [Error] represents the variant case "upstream".
+func (self *Error) Upstream() *string {
+	return cm.Case[string](self, 0)
+}
+
+// ErrorIO returns a [Error] of case "io".
+//
+// This indicates an error from an I/O operation.
+// As this could be almost _anything_ (such as a file read, network connection, etc),
+// the error message is a string.
+// Depending on how this ends up being consumed,
+// we may consider moving this to use the `wasi:io/error` type instead.
+// For simplicity right now in supporting multiple implementations, it is being left
+// as a string.
+func ErrorIO(data string) Error {
+	return cm.New[Error](1, data)
+}
+
+// IO returns a non-nil *[string] if [Error] represents the variant case "io".
+func (self *Error) IO() *string {
+	return cm.Case[string](self, 1)
+}
+
+var stringsError = [2]string{
+	"upstream",
+	"io",
+}
+
+// String implements [fmt.Stringer], returning the variant case name of v.
+func (v Error) String() string {
+	return cm.CaseString(stringsError[:], cm.Tag(&v))
+}
+
+// Get represents the imported function "get".
+//
+// Gets a configuration value of type `string` associated with the `key`.
+//
+// The value is returned as an `option<string>`. If the key is not found,
+// `Ok(none)` is returned. If an error occurs, an `Err(error)` is returned.
+//
+//	get: func(key: string) -> result<option<string>, error>
+//
+//go:nosplit
+func Get(key string) cm.OKResult[cm.Option[string], Error] {
+	var result cm.OKResult[cm.Option[string], Error]
+	wasmimport_Get(key, &result)
+	return result
+}
+
+//go:wasmimport wasi:config/store@0.2.0-draft get
+//go:noescape
+func wasmimport_Get(key string, result *cm.OKResult[cm.Option[string], Error])
+
+// GetAll represents the imported function "get-all".
+//
+// Gets a list of configuration key-value pairs of type `string`.
+//
+// If an error occurs, an `Err(error)` is returned.
+//
+//	get-all: func() -> result<list<tuple<string, string>>, error>
+//
+//go:nosplit
+func GetAll() cm.ErrResult[cm.List[[2]string], Error] {
+	var result cm.ErrResult[cm.List[[2]string], Error]
+	wasmimport_GetAll(&result)
+	return result
+}
+
+//go:wasmimport wasi:config/store@0.2.0-draft get-all
+//go:noescape
+func wasmimport_GetAll(result *cm.ErrResult[cm.List[[2]string], Error])
@@ -0,0 +1,144 @@
+//go:build !wasip1
+
+package outgoinghandler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	monotonicclock "github.com/ydnar/wasm-tools-go/wasi/clocks/v0.2.0/monotonic-clock"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/poll"
+)
+
+// Options are the transport timeouts of an outgoing request.
+// A zero value means no timeout, unless one is implied by the deadline of a context.
+type Options struct {
+	// ConnectTimeout is the timeout for the initial connect to the HTTP server.
+	ConnectTimeout time.Duration
+
+	// FirstByteTimeout is the timeout for receiving the first byte of the response body.
+	FirstByteTimeout time.Duration
+
+	// BetweenBytesTimeout is the timeout for receiving subsequent chunks
+	// of bytes in the response body stream.
+	BetweenBytesTimeout time.Duration
+}
+
+// pollInterval is how often [Await] checks for cancellation of a
+// context that can be canceled while waiting for a response.
+const pollInterval = 10 * time.Millisecond
+
+// HandleContext sends request and waits for its response, like [Handle].
+// It takes ownership of request. The deadline of ctx, if any, caps each timeout
+// in opts, which may be nil. If ctx is done before the response arrives,
+// the pending response is dropped, and HandleContext returns ctx.Err().
+// An error from the host is returned as a [types.ErrorCode].
+//
+// Hosts that do not support a timeout ignore it. The deadline of ctx
+// is enforced regardless.
+func HandleContext(ctx context.Context, request types.OutgoingRequest, opts *Options) (types.IncomingResponse, error) {
+	future, err := Send(ctx, request, opts)
+	if err != nil {
+		return 0, err
+	}
+	return Await(ctx, future)
+}
+
+// Send sends request with the timeouts in opts, capped by the deadline of ctx,
+// and returns the pending response without waiting for it. It takes ownership
+// of request. The caller may write the body of request, then wait for the
+// response with [Await]. See [HandleContext].
+func Send(ctx context.Context, request types.OutgoingRequest, opts *Options) (types.FutureIncomingResponse, error) {
+	if err := ctx.Err(); err != nil {
+		request.ResourceDrop()
+		return 0, err
+	}
+
+	options := cm.None[types.RequestOptions]()
+	if t := timeouts(ctx, opts, time.Now()); t != (Options{}) {
+		ro := types.NewRequestOptions()
+		setTimeout(ro.SetConnectTimeout, t.ConnectTimeout)
+		setTimeout(ro.SetFirstByteTimeout, t.FirstByteTimeout)
+		setTimeout(ro.SetBetweenBytesTimeout, t.BetweenBytesTimeout)
+		options = cm.Some(ro)
+	}
+
+	result := Handle(request, options)
+	if err := result.Err(); err != nil {
+		return 0, *err
+	}
+	return *result.OK(), nil
+}
+
+// Await waits for and returns the response of future, which it takes ownership of.
+// If ctx is done before the response arrives, future is dropped, and Await returns ctx.Err().
+// An error from the host is returned as a [types.ErrorCode].
+func Await(ctx context.Context, future types.FutureIncomingResponse) (types.IncomingResponse, error) {
+	pollable := future.Subscribe()
+	if ctx.Done() == nil {
+		// ctx can never be canceled, so block until the response arrives.
+		pollable.Block()
+	}
+	for !pollable.Ready() {
+		if err := ctx.Err(); err != nil {
+			// The pollable is a child of the future, and must be dropped first.
+			pollable.ResourceDrop()
+			future.ResourceDrop()
+			return 0, err
+		}
+		wait := pollInterval
+		if deadline, ok := ctx.Deadline(); ok {
+			wait = min(wait, time.Until(deadline))
+		}
+		timer := monotonicclock.SubscribeDuration(monotonicclock.Duration(max(wait, 0)))
+		poll.Poll(cm.ToList([]poll.Pollable{pollable, timer}))
+		timer.ResourceDrop()
+	}
+	pollable.ResourceDrop()
+	defer future.ResourceDrop()
+
+	get := future.Get()
+	once := get.Some()
+	if once == nil || once.IsErr() {
+		return 0, errors.New("wasi:http/outgoing-handler: response not available")
+	}
+	response := once.OK()
+	if err := response.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, ctxErr
+		}
+		return 0, *err
+	}
+	return *response.OK(), nil
+}
+
+// timeouts returns the timeouts in opts, each capped to the time remaining
+// until the deadline of ctx, if any, at now.
+func timeouts(ctx context.Context, opts *Options, now time.Time) Options {
+	var t Options
+	if opts != nil {
+		t = *opts
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return t
+	}
+	// A timeout of zero would disable the timeout, so wait at least 1ns.
+	remaining := max(deadline.Sub(now), 1)
+	for _, d := range []*time.Duration{&t.ConnectTimeout, &t.FirstByteTimeout, &t.BetweenBytesTimeout} {
+		if *d <= 0 || *d > remaining {
+			*d = remaining
+		}
+	}
+	return t
+}
+
+func setTimeout(set func(cm.Option[monotonicclock.Duration]) cm.Result, d time.Duration) {
+	if d > 0 {
+		// An error means the host does not support this timeout.
+		set(cm.Some(monotonicclock.Duration(d)))
+	}
+}
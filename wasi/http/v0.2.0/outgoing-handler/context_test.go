@@ -0,0 +1,40 @@
+//go:build !wasip1
+
+package outgoinghandler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	opts := &Options{ConnectTimeout: time.Second, FirstByteTimeout: time.Minute}
+
+	got := timeouts(context.Background(), opts, now)
+	if got != *opts {
+		t.Errorf("timeouts without deadline: %+v, expected %+v", got, *opts)
+	}
+	if got := timeouts(context.Background(), nil, now); got != (Options{}) {
+		t.Errorf("timeouts(nil) without deadline: %+v, expected zero", got)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(5*time.Second))
+	defer cancel()
+	got = timeouts(ctx, opts, now)
+	want := Options{
+		ConnectTimeout:      time.Second,
+		FirstByteTimeout:    5 * time.Second,
+		BetweenBytesTimeout: 5 * time.Second,
+	}
+	if got != want {
+		t.Errorf("timeouts with deadline: %+v, expected %+v", got, want)
+	}
+
+	got = timeouts(ctx, nil, now.Add(time.Hour))
+	want = Options{ConnectTimeout: 1, FirstByteTimeout: 1, BetweenBytesTimeout: 1}
+	if got != want {
+		t.Errorf("timeouts after deadline: %+v, expected %+v", got, want)
+	}
+}
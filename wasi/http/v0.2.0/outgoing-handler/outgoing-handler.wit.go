@@ -0,0 +1,43 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.0
+// Checksum: sha256:943b63f7d0cde1151f60962b593490d89b7ec63f4a33d792578390e5c734d8aa
+
+//go:build !wasip1
+
+// Package outgoinghandler represents the imported interface "wasi:http/outgoing-handler@0.2.0".
+//
+// This interface defines a handler of outgoing HTTP Requests. It should be
+// imported by components which wish to make HTTP Requests.
+package outgoinghandler
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+)
+
+// Handle represents the imported function "handle".
+//
+// This function is invoked with an outgoing HTTP Request, and it returns
+// a resource `future-incoming-response` which represents an HTTP Response
+// which may arrive in the future.
+//
+// The `options` argument accepts optional parameters for the HTTP
+// protocol's transport layer.
+//
+// This function may return an error if the `outgoing-request` is invalid
+// or not allowed to be made. Otherwise, protocol errors are reported
+// through the `future-incoming-response`.
+//
+//	handle: func(request: outgoing-request, options: option<request-options>) -> result<future-incoming-response,
+//	error-code>
+//
+//go:nosplit
+func Handle(request types.OutgoingRequest, options cm.Option[types.RequestOptions]) cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode] {
+	var result cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode]
+	wasmimport_Handle(request, options, &result)
+	return result
+}
+
+//go:wasmimport wasi:http/outgoing-handler@0.2.0 handle
+//go:noescape
+func wasmimport_Handle(request types.OutgoingRequest, options cm.Option[types.RequestOptions], result *cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode])
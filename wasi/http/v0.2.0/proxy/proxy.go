@@ -0,0 +1,115 @@
+//go:build !wasip1
+
+// Package proxy adapts the wasi:http/proxy world to [net/http], so a component that
+// handles or forwards HTTP requests, such as middleware or a proxy, is a few lines of Go.
+//
+// [Serve] handles the requests the host sends to the exported wasi:http/incoming-handler
+// interface with an [http.Handler]. [Transport] is an [http.RoundTripper] that sends requests
+// with the imported wasi:http/outgoing-handler interface, for use with an [http.Client]
+// or [httputil.ReverseProxy]:
+//
+//	func init() {
+//		proxy.Serve(&httputil.ReverseProxy{
+//			Rewrite: func(r *httputil.ProxyRequest) {
+//				r.SetURL(backend)
+//			},
+//			Transport: &proxy.Transport{},
+//		})
+//	}
+//
+// Bodies are streamed with [types.BodyReader] and [types.BodyWriter].
+// Connection-specific headers, which the host manages, are omitted (see [types.IsForbiddenHeader]).
+//
+// [httputil.ReverseProxy]: https://pkg.go.dev/net/http/httputil#ReverseProxy
+package proxy
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+)
+
+var (
+	errBodyTaken     = errors.New("wasi:http/proxy: body already taken")
+	errInvalidStatus = errors.New("wasi:http/proxy: invalid status code")
+)
+
+// methodString returns the Go name of method m, e.g. GET.
+func methodString(m types.Method) string {
+	if other := m.Other(); other != nil {
+		return *other
+	}
+	return strings.ToUpper(m.String())
+}
+
+// toMethod returns the [types.Method] for the Go name of a method, e.g. GET.
+// An empty name is GET, as in [http.Request].
+func toMethod(method string) types.Method {
+	switch method {
+	case "", "GET":
+		return types.MethodGet()
+	case "HEAD":
+		return types.MethodHead()
+	case "POST":
+		return types.MethodPost()
+	case "PUT":
+		return types.MethodPut()
+	case "DELETE":
+		return types.MethodDelete()
+	case "CONNECT":
+		return types.MethodConnect()
+	case "OPTIONS":
+		return types.MethodOptions()
+	case "TRACE":
+		return types.MethodTrace()
+	case "PATCH":
+		return types.MethodPatch()
+	}
+	return types.MethodOther(method)
+}
+
+// schemeString returns the URL scheme of s, e.g. https.
+func schemeString(s types.Scheme) string {
+	switch {
+	case s.HTTP():
+		return "http"
+	case s.HTTPS():
+		return "https"
+	}
+	return *s.Other()
+}
+
+// toScheme returns the [types.Scheme] for URL scheme s. An empty scheme is http.
+func toScheme(s string) types.Scheme {
+	switch strings.ToLower(s) {
+	case "", "http":
+		return types.SchemeHTTP()
+	case "https":
+		return types.SchemeHTTPS()
+	}
+	return types.SchemeOther(s)
+}
+
+// requestURL returns the URL of an incoming request from its scheme, authority,
+// and path with query, each of which may be none. The default scheme is http,
+// and the default path is /.
+func requestURL(scheme cm.Option[types.Scheme], authority, pathWithQuery cm.Option[string]) (*url.URL, error) {
+	u := &url.URL{Scheme: "http", Path: "/"}
+	if s := scheme.Some(); s != nil {
+		u.Scheme = schemeString(*s)
+	}
+	if a := authority.Some(); a != nil {
+		u.Host = *a
+	}
+	if p := pathWithQuery.Some(); p != nil && *p != "" {
+		ref, err := url.ParseRequestURI(*p)
+		if err != nil {
+			return nil, err
+		}
+		u.Path, u.RawPath, u.RawQuery = ref.Path, ref.RawPath, ref.RawQuery
+	}
+	return u, nil
+}
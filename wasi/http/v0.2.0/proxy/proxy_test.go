@@ -0,0 +1,107 @@
+//go:build !wasip1
+
+package proxy
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+)
+
+func TestMethod(t *testing.T) {
+	for _, method := range []string{"GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH", "PROPFIND"} {
+		if got := methodString(toMethod(method)); got != method {
+			t.Errorf("methodString(toMethod(%q)): %q", method, got)
+		}
+	}
+	if got := methodString(toMethod("")); got != "GET" {
+		t.Errorf("methodString(toMethod(\"\")): %q, expected GET", got)
+	}
+}
+
+func TestScheme(t *testing.T) {
+	for _, tt := range []struct{ scheme, want string }{
+		{"", "http"},
+		{"http", "http"},
+		{"HTTPS", "https"},
+		{"ws", "ws"},
+	} {
+		if got := schemeString(toScheme(tt.scheme)); got != tt.want {
+			t.Errorf("schemeString(toScheme(%q)): %q, expected %q", tt.scheme, got, tt.want)
+		}
+	}
+}
+
+func TestRequestURL(t *testing.T) {
+	tests := []struct {
+		scheme        cm.Option[types.Scheme]
+		authority     cm.Option[string]
+		pathWithQuery cm.Option[string]
+		want          string
+	}{
+		{cm.None[types.Scheme](), cm.None[string](), cm.None[string](), "http:///"},
+		{cm.Some(types.SchemeHTTPS()), cm.Some("example.com"), cm.Some("/a%2Fb?q=1"), "https://example.com/a%2Fb?q=1"},
+		{cm.Some(types.SchemeHTTP()), cm.Some("localhost:8080"), cm.Some(""), "http://localhost:8080/"},
+	}
+	for _, tt := range tests {
+		u, err := requestURL(tt.scheme, tt.authority, tt.pathWithQuery)
+		if err != nil {
+			t.Errorf("requestURL: %v", err)
+			continue
+		}
+		if got := u.String(); got != tt.want {
+			t.Errorf("requestURL: %q, expected %q", got, tt.want)
+		}
+	}
+	if _, err := requestURL(cm.None[types.Scheme](), cm.None[string](), cm.Some("not a path")); err == nil {
+		t.Error("requestURL: expected error for invalid path")
+	}
+}
+
+func TestTrailers(t *testing.T) {
+	h := http.Header{
+		"Content-Type":                  {"text/plain"},
+		"Trailer":                       {"Checksum, Expires"},
+		"Checksum":                      {"abc"},
+		http.TrailerPrefix + "Duration": {"1s"},
+	}
+	want := http.Header{"Checksum": {"abc"}, "Duration": {"1s"}}
+	if got := trailers(h); !reflect.DeepEqual(got, want) {
+		t.Errorf("trailers: %v, expected %v", got, want)
+	}
+	if got := trailers(http.Header{"Content-Type": {"text/plain"}}); got != nil {
+		t.Errorf("trailers: %v, expected nil", got)
+	}
+
+	got := responseHeader(h)
+	if _, ok := got[http.TrailerPrefix+"Duration"]; ok {
+		t.Errorf("responseHeader: %v, expected no %s prefixed keys", got, http.TrailerPrefix)
+	}
+	if _, ok := h[http.TrailerPrefix+"Duration"]; !ok {
+		t.Error("responseHeader modified its argument")
+	}
+}
+
+func TestContentLength(t *testing.T) {
+	for _, tt := range []struct {
+		value string
+		want  int64
+	}{
+		{"", -1},
+		{"0", 0},
+		{"42", 42},
+		{"-1", -1},
+		{"x", -1},
+	} {
+		h := http.Header{}
+		if tt.value != "" {
+			h.Set("Content-Length", tt.value)
+		}
+		if got := contentLength(h); got != tt.want {
+			t.Errorf("contentLength(%q): %d, expected %d", tt.value, got, tt.want)
+		}
+	}
+}
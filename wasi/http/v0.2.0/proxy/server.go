@@ -0,0 +1,203 @@
+//go:build !wasip1
+
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	incominghandler "github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/incoming-handler"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+)
+
+// Serve sets h as the handler for requests sent to the component by assigning
+// [incominghandler.Handle]. It is typically called from an init function.
+//
+// The response is sent to the host when h first writes to the body, calls WriteHeader,
+// or flushes the [http.ResponseWriter], or when h returns. Headers set after the
+// response is sent are ignored, except trailers declared with the Trailer header or
+// prefixed with [http.TrailerPrefix], which are sent when h returns.
+// If the request is malformed, h is not called, and the host responds with an error.
+func Serve(h http.Handler) {
+	incominghandler.Handle = func(request types.IncomingRequest, responseOut types.ResponseOutparam) {
+		serve(h, request, responseOut)
+	}
+}
+
+func serve(h http.Handler, request types.IncomingRequest, responseOut types.ResponseOutparam) {
+	req, body, err := newRequest(request)
+	if err != nil {
+		request.ResourceDrop()
+		types.ResponseOutparamSet(responseOut, cm.Err[cm.ErrResult[types.OutgoingResponse, types.ErrorCode]](types.ErrorCodeHTTPRequestURIInvalid()))
+		return
+	}
+	w := &responseWriter{out: responseOut, header: make(http.Header)}
+	h.ServeHTTP(w, req)
+	w.finish()
+	// The body is a child of the request, and must be finished first.
+	body.Close()
+	request.ResourceDrop()
+}
+
+// newRequest returns an [http.Request] for an incoming request, and the reader of its body.
+func newRequest(request types.IncomingRequest) (*http.Request, *types.BodyReader, error) {
+	u, err := requestURL(request.Scheme(), request.Authority(), request.PathWithQuery())
+	if err != nil {
+		return nil, nil, err
+	}
+	headers := request.Headers()
+	header := headers.Header()
+	headers.ResourceDrop()
+
+	consume := request.Consume()
+	if consume.IsErr() {
+		return nil, nil, errBodyTaken
+	}
+	body, err := types.NewBodyReader(*consume.OK())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), methodString(request.Method()), u.String(), body)
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+	req.Header = header
+	req.RequestURI = u.RequestURI()
+	req.ContentLength = contentLength(header)
+	return req, body, nil
+}
+
+// contentLength returns the value of the Content-Length header in h, or -1 if unknown.
+func contentLength(h http.Header) int64 {
+	n, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// responseWriter implements [http.ResponseWriter] and [http.Flusher]
+// for a [types.ResponseOutparam].
+type responseWriter struct {
+	out    types.ResponseOutparam
+	header http.Header
+	body   *types.BodyWriter
+	err    error
+}
+
+// Header implements [http.ResponseWriter].
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader implements [http.ResponseWriter]. It sends the response to the host.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.body != nil || w.err != nil {
+		return
+	}
+	fields, err := types.FieldsFromHeader(responseHeader(w.header))
+	if err != nil {
+		w.fail(err)
+		return
+	}
+	response := types.NewOutgoingResponse(fields)
+	if response.SetStatusCode(types.StatusCode(status)) == cm.ResultErr {
+		response.ResourceDrop()
+		w.fail(errInvalidStatus)
+		return
+	}
+	result := response.Body()
+	w.body, w.err = types.NewBodyWriter(*result.OK())
+	if w.err != nil {
+		response.ResourceDrop()
+		w.fail(w.err)
+		return
+	}
+	types.ResponseOutparamSet(w.out, cm.OK[cm.ErrResult[types.OutgoingResponse, types.ErrorCode]](response))
+}
+
+// Write implements [http.ResponseWriter]. It sends the response with status 200 OK
+// if it has not been sent.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.body == nil {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+	return w.body.Write(p)
+}
+
+// Flush implements [http.Flusher].
+func (w *responseWriter) Flush() {
+	if w.body == nil {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.err == nil {
+		w.body.Flush()
+	}
+}
+
+// finish sends the response if it has not been sent, then finishes its body with trailers.
+func (w *responseWriter) finish() {
+	if w.body == nil {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.err == nil {
+		w.body.Finish(trailers(w.header))
+	}
+}
+
+// fail responds to the host with an internal error.
+func (w *responseWriter) fail(err error) {
+	w.err = err
+	types.ResponseOutparamSet(w.out, cm.Err[cm.ErrResult[types.OutgoingResponse, types.ErrorCode]](types.ErrorCodeInternalError(cm.Some(err.Error()))))
+}
+
+// responseHeader returns h without the trailers prefixed with [http.TrailerPrefix].
+func responseHeader(h http.Header) http.Header {
+	for name := range h {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			h = h.Clone()
+			for name := range h {
+				if strings.HasPrefix(name, http.TrailerPrefix) {
+					delete(h, name)
+				}
+			}
+			break
+		}
+	}
+	return h
+}
+
+// trailers returns the trailers in h, declared with the Trailer header or prefixed
+// with [http.TrailerPrefix], or nil if there are none.
+func trailers(h http.Header) http.Header {
+	var t http.Header
+	add := func(name string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		if t == nil {
+			t = make(http.Header)
+		}
+		t[http.CanonicalHeaderKey(name)] = values
+	}
+	for _, v := range h["Trailer"] {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			add(name, h.Values(name))
+		}
+	}
+	for name, values := range h {
+		if after, ok := strings.CutPrefix(name, http.TrailerPrefix); ok {
+			add(after, values)
+		}
+	}
+	return t
+}
@@ -0,0 +1,162 @@
+//go:build !wasip1
+
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	outgoinghandler "github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/outgoing-handler"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.0/types"
+)
+
+// Transport is an [http.RoundTripper] that sends requests with wasi:http/outgoing-handler.
+// The zero value is ready to use. The context of each request cancels it,
+// and its deadline caps the timeouts in Options.
+type Transport struct {
+	// Options are the transport timeouts of each request.
+	Options outgoinghandler.Options
+}
+
+var _ http.RoundTripper = &Transport{}
+
+// RoundTrip implements [http.RoundTripper]. The request body is streamed to the host
+// after the request is sent, and the response body is streamed from the host as it is read.
+// Closing the response body releases the response.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	// Send drops the request if the context is done, which must not
+	// happen while its body is open, so check the context first.
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	request, body, err := newOutgoingRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	future, err := outgoinghandler.Send(req.Context(), request, &t.Options)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	if req.Body != nil {
+		_, err = io.Copy(body, req.Body)
+	}
+	if err == nil {
+		err = body.Close()
+	} else {
+		body.Close()
+	}
+	if err != nil {
+		future.ResourceDrop()
+		return nil, err
+	}
+
+	response, err := outgoinghandler.Await(req.Context(), future)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(req, response)
+}
+
+// newOutgoingRequest returns a [types.OutgoingRequest] for req, and the writer of its body.
+func newOutgoingRequest(req *http.Request) (types.OutgoingRequest, *types.BodyWriter, error) {
+	if req.URL == nil {
+		return 0, nil, errors.New("wasi:http/proxy: nil request URL")
+	}
+	header := req.Header
+	if req.ContentLength > 0 && header.Get("Content-Length") == "" {
+		header = header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+	}
+	fields, err := types.FieldsFromHeader(header)
+	if err != nil {
+		return 0, nil, err
+	}
+	request := types.NewOutgoingRequest(fields)
+	authority := req.Host
+	if authority == "" {
+		authority = req.URL.Host
+	}
+	switch {
+	case request.SetMethod(toMethod(req.Method)) == cm.ResultErr:
+		err = errors.New("wasi:http/proxy: invalid method " + strconv.Quote(req.Method))
+	case request.SetScheme(cm.Some(toScheme(req.URL.Scheme))) == cm.ResultErr:
+		err = errors.New("wasi:http/proxy: invalid scheme " + strconv.Quote(req.URL.Scheme))
+	case request.SetAuthority(cm.Some(authority)) == cm.ResultErr:
+		err = errors.New("wasi:http/proxy: invalid authority " + strconv.Quote(authority))
+	case request.SetPathWithQuery(cm.Some(req.URL.RequestURI())) == cm.ResultErr:
+		err = errors.New("wasi:http/proxy: invalid path " + strconv.Quote(req.URL.RequestURI()))
+	}
+	if err != nil {
+		request.ResourceDrop()
+		return 0, nil, err
+	}
+
+	// The body must be taken before the request is sent.
+	result := request.Body()
+	body, err := types.NewBodyWriter(*result.OK())
+	if err != nil {
+		request.ResourceDrop()
+		return 0, nil, err
+	}
+	return request, body, nil
+}
+
+// newResponse returns an [http.Response] to req for an incoming response.
+func newResponse(req *http.Request, response types.IncomingResponse) (*http.Response, error) {
+	headers := response.Headers()
+	header := headers.Header()
+	headers.ResourceDrop()
+
+	consume := response.Consume()
+	if consume.IsErr() {
+		response.ResourceDrop()
+		return nil, errBodyTaken
+	}
+	body, err := types.NewBodyReader(*consume.OK())
+	if err != nil {
+		response.ResourceDrop()
+		return nil, err
+	}
+
+	status := int(response.Status())
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          &responseBody{BodyReader: body, response: response},
+		ContentLength: contentLength(header),
+		Request:       req,
+	}, nil
+}
+
+// responseBody is the body of an [http.Response]. Closing it releases the response.
+type responseBody struct {
+	*types.BodyReader
+	response types.IncomingResponse
+}
+
+// Close implements [io.Closer]. Close is idempotent.
+func (b *responseBody) Close() error {
+	if b.response == 0 {
+		return nil
+	}
+	// The body is a child of the response, and must be finished first.
+	err := b.BodyReader.Close()
+	b.response.ResourceDrop()
+	b.response = 0
+	return err
+}
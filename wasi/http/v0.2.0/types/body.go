@@ -0,0 +1,203 @@
+//go:build !wasip1
+
+package types
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+)
+
+// Error implements the error interface.
+func (v ErrorCode) Error() string {
+	return "wasi:http/types: " + v.String()
+}
+
+var errBodyClosed = errors.New("wasi:http/types: body closed")
+
+// BodyReader reads the contents of an [IncomingBody] as a stream of bytes.
+// It implements [io.ReadCloser]. Reads wait for data with a pollable,
+// rather than buffering the whole body in memory.
+//
+// After the body is read to [io.EOF] and closed, [BodyReader.Trailers]
+// returns its trailers. If reading the stream fails, Read returns a *[streams.Error],
+// which owns a wasi:io/error resource that the caller may drop with [streams.Error.Close].
+type BodyReader struct {
+	body     IncomingBody
+	stream   io.ReadCloser // a *streams.Reader
+	trailers FutureTrailers
+	closed   bool
+}
+
+// NewBodyReader returns a [BodyReader] that takes ownership of body.
+// It returns an error if the stream of body has already been taken.
+func NewBodyReader(body IncomingBody) (*BodyReader, error) {
+	result := body.Stream()
+	if result.IsErr() {
+		return nil, errors.New("wasi:http/types: incoming-body stream already taken")
+	}
+	return &BodyReader{body: body, stream: streams.NewReader(*result.OK())}, nil
+}
+
+// Read implements [io.Reader]. It blocks until at least one byte is available,
+// the stream is closed, or an error occurs.
+func (r *BodyReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errBodyClosed
+	}
+	return r.stream.Read(p)
+}
+
+// Close implements [io.Closer]. It releases the stream and finishes the body.
+// It does not wait for the trailers. Close is idempotent.
+func (r *BodyReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	// The stream is a child of the body, and must be dropped first.
+	r.stream.Close()
+	r.trailers = finishIncomingBody(r.body)
+	return nil
+}
+
+// Trailers closes r if necessary, then waits for and returns the trailers of the body.
+// It returns nil if the body has no trailers, or an [ErrorCode] if an error occurred
+// while receiving the body. Trailers may only be called once.
+func (r *BodyReader) Trailers() (http.Header, error) {
+	if err := r.Close(); err != nil {
+		return nil, err
+	}
+	if r.trailers == 0 {
+		return nil, errors.New("wasi:http/types: trailers already received")
+	}
+	trailers := r.trailers
+	r.trailers = 0
+	defer trailers.ResourceDrop()
+
+	pollable := trailers.Subscribe()
+	pollable.Block()
+	pollable.ResourceDrop()
+
+	get := trailers.Get()
+	once := get.Some()
+	if once == nil || once.IsErr() {
+		return nil, errors.New("wasi:http/types: trailers not available")
+	}
+	result := once.OK()
+	if err := result.Err(); err != nil {
+		return nil, *err
+	}
+	fields := result.OK().Some()
+	if fields == nil {
+		return nil, nil
+	}
+	defer fields.ResourceDrop()
+	return fields.Header(), nil
+}
+
+// BodyWriter writes the contents of an [OutgoingBody] as a stream of bytes.
+// It implements [io.WriteCloser]. Writes wait until the stream is ready
+// to accept more data, applying backpressure instead of buffering the
+// whole body in memory.
+//
+// A body must be finished with [BodyWriter.Close] or [BodyWriter.Finish],
+// otherwise the host treats it as incomplete. If writing to the stream fails,
+// methods return a *[streams.Error], as with [BodyReader].
+type BodyWriter struct {
+	body   OutgoingBody
+	stream flushWriteCloser // a *streams.Writer
+	closed bool
+}
+
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// NewBodyWriter returns a [BodyWriter] that takes ownership of body.
+// It returns an error if the stream of body has already been taken.
+func NewBodyWriter(body OutgoingBody) (*BodyWriter, error) {
+	result := body.Write()
+	if result.IsErr() {
+		return nil, errors.New("wasi:http/types: outgoing-body stream already taken")
+	}
+	return &BodyWriter{body: body, stream: streams.NewWriter(*result.OK())}, nil
+}
+
+// Write implements [io.Writer]. It writes as much of p as the stream accepts,
+// waiting for the stream to be ready as needed, until all of p is written
+// or an error occurs.
+func (w *BodyWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errBodyClosed
+	}
+	return w.stream.Write(p)
+}
+
+// Flush blocks until all data written to w has been flushed to the host.
+func (w *BodyWriter) Flush() error {
+	if w.closed {
+		return errBodyClosed
+	}
+	return w.stream.Flush()
+}
+
+// Close implements [io.Closer]. It is equivalent to Finish(nil).
+func (w *BodyWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	return w.Finish(nil)
+}
+
+// Finish flushes w, then finishes the body with optional trailers.
+// Forbidden trailers are omitted (see [IsForbiddenHeader]).
+// It returns an [ErrorCode] if the host rejects the body, for example if
+// fewer bytes were written than specified in a Content-Length header.
+// After Finish, writes to w return an error.
+func (w *BodyWriter) Finish(trailers http.Header) error {
+	if w.closed {
+		return errBodyClosed
+	}
+	err := w.Flush()
+	w.closed = true
+	// The stream is a child of the body, and must be dropped first.
+	w.stream.Close()
+	if err != nil {
+		dropOutgoingBody(w.body)
+		return err
+	}
+	return finishOutgoingBody(w.body, trailers)
+}
+
+// finishOutgoing finishes body with optional trailers, or drops body if
+// the trailers are invalid.
+func finishOutgoing(body OutgoingBody, trailers http.Header) error {
+	t := cm.None[Fields]()
+	if trailers != nil {
+		fields, err := FieldsFromHeader(trailers)
+		if err != nil {
+			body.ResourceDrop()
+			return err
+		}
+		t = cm.Some(fields)
+	}
+	result := OutgoingBodyFinish(body, t)
+	if err := result.Err(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// finishIncomingBody, dropOutgoingBody, and finishOutgoingBody call the host.
+// They are set in body_wasm.go, and replaced by fakes in tests,
+// which run without WebAssembly.
+var (
+	finishIncomingBody func(IncomingBody) FutureTrailers
+	dropOutgoingBody   func(OutgoingBody)
+	finishOutgoingBody func(OutgoingBody, http.Header) error
+)
@@ -0,0 +1,178 @@
+//go:build !wasip1
+
+package types
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestErrorCode(t *testing.T) {
+	var err error = ErrorCodeDNSTimeout()
+	if got, want := err.Error(), "wasi:http/types: DNS-timeout"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+}
+
+func TestBodyReader(t *testing.T) {
+	var log []string
+	fakeBody(&log)
+	s := &fakeStream{Reader: strings.NewReader("hello"), log: &log}
+	r := &BodyReader{body: 1, stream: s}
+	got, err := readAll(r)
+	if string(got) != "hello" || err != io.EOF {
+		t.Errorf("Read: %q, %v, expected %q, io.EOF", got, err, "hello")
+	}
+	r.Close()
+	r.Close()
+	if want := []string{"close stream", "finish incoming 1"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Close: %q, expected %q", log, want)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != errBodyClosed {
+		t.Errorf("Read after Close: %v, expected %v", err, errBodyClosed)
+	}
+}
+
+func TestBodyReaderError(t *testing.T) {
+	s := &fakeStream{Reader: iotest.ErrReader(errFailed)}
+	r := &BodyReader{body: 1, stream: s}
+	if _, err := r.Read(make([]byte, 1)); err != errFailed {
+		t.Errorf("Read: %v, expected %v", err, errFailed)
+	}
+}
+
+func TestBodyWriter(t *testing.T) {
+	var log []string
+	fakeBody(&log)
+	tests := []struct {
+		name     string
+		flushErr error
+		trailers http.Header // if nil, Close the writer instead of calling Finish
+		wantErr  error
+		want     []string
+	}{
+		{
+			name: "close",
+			want: []string{"write hello", "flush", "close stream", "finish outgoing 2"},
+		},
+		{
+			name:     "trailers",
+			trailers: http.Header{"X-Sum": {"1"}},
+			want:     []string{"write hello", "flush", "close stream", "finish outgoing 2 X-Sum"},
+		},
+		{
+			name:     "flush error",
+			flushErr: errFailed,
+			wantErr:  errFailed,
+			want:     []string{"write hello", "flush", "close stream", "drop outgoing 2"},
+		},
+		{
+			name:     "closed stream",
+			flushErr: io.ErrClosedPipe,
+			wantErr:  io.ErrClosedPipe,
+			want:     []string{"write hello", "flush", "close stream", "drop outgoing 2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log = nil
+			w := &BodyWriter{body: 2, stream: &fakeStream{log: &log, err: tt.flushErr}}
+			if _, err := w.Write([]byte("hello")); err != nil {
+				t.Errorf("Write: %v", err)
+			}
+			var err error
+			if tt.trailers != nil {
+				err = w.Finish(tt.trailers)
+			} else {
+				err = w.Close()
+			}
+			if err != tt.wantErr {
+				t.Errorf("Finish: %v, expected %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(log, tt.want) {
+				t.Errorf("calls: %q, expected %q", log, tt.want)
+			}
+			if _, err := w.Write([]byte("x")); err != errBodyClosed {
+				t.Errorf("Write after Finish: %v, expected %v", err, errBodyClosed)
+			}
+			if err := w.Flush(); err != errBodyClosed {
+				t.Errorf("Flush after Finish: %v, expected %v", err, errBodyClosed)
+			}
+			if err := w.Finish(nil); err != errBodyClosed {
+				t.Errorf("Finish after Finish: %v, expected %v", err, errBodyClosed)
+			}
+			if err := w.Close(); err != nil {
+				t.Errorf("Close after Finish: %v", err)
+			}
+		})
+	}
+}
+
+// readAll reads from r until an error occurs, returning the error.
+// A *BodyReader cannot be used as an [io.Reader] in tests that run without WebAssembly,
+// because its methods refer to methods of resources that call the host.
+func readAll(r *BodyReader) ([]byte, error) {
+	var data []byte
+	buf := make([]byte, 2)
+	for {
+		n, err := r.Read(buf)
+		data = append(data, buf[:n]...)
+		if err != nil {
+			return data, err
+		}
+	}
+}
+
+// errFailed is returned by fake streams in place of a *[streams.Error], which cannot
+// be used as an error in tests that run without WebAssembly.
+var errFailed = errors.New("last operation failed")
+
+// fakeStream is a body stream that reads from Reader, and returns err from Flush.
+// Writes, flushes, and closes are appended to log.
+type fakeStream struct {
+	io.Reader
+	err error
+	log *[]string
+}
+
+func (s *fakeStream) Write(p []byte) (int, error) {
+	*s.log = append(*s.log, "write "+string(p))
+	return len(p), nil
+}
+
+func (s *fakeStream) Flush() error {
+	*s.log = append(*s.log, "flush")
+	return s.err
+}
+
+func (s *fakeStream) Close() error {
+	*s.log = append(*s.log, "close stream")
+	return nil
+}
+
+// fakeBody sets the host calls that finish and drop bodies to fakes that append to log.
+// They are not restored, because they are unset in tests that run without WebAssembly.
+// (Saving them in a closure would refer to methods of resources that call the host.)
+func fakeBody(log *[]string) {
+	finishIncomingBody = func(body IncomingBody) FutureTrailers {
+		*log = append(*log, "finish incoming "+strconv.Itoa(int(body)))
+		return 3
+	}
+	dropOutgoingBody = func(body OutgoingBody) {
+		*log = append(*log, "drop outgoing "+strconv.Itoa(int(body)))
+	}
+	finishOutgoingBody = func(body OutgoingBody, trailers http.Header) error {
+		s := "finish outgoing " + strconv.Itoa(int(body))
+		for k := range trailers {
+			s += " " + k
+		}
+		*log = append(*log, s)
+		return nil
+	}
+}
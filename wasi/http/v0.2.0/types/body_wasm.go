@@ -0,0 +1,9 @@
+//go:build !wasip1
+
+package types
+
+func init() {
+	finishIncomingBody = IncomingBodyFinish
+	dropOutgoingBody = OutgoingBody.ResourceDrop
+	finishOutgoingBody = finishOutgoing
+}
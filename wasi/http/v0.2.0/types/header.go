@@ -0,0 +1,115 @@
+//go:build !wasip1
+
+package types
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Error implements the error interface.
+func (e HeaderError) Error() string {
+	return "wasi:http/types: header error: " + e.String()
+}
+
+// forbiddenHeaders are the connection-specific headers that hosts typically reject
+// with [HeaderErrorForbidden], because the host manages the connection.
+var forbiddenHeaders = map[string]bool{
+	"connection":          true,
+	"host":                true,
+	"http2-settings":      true,
+	"keep-alive":          true,
+	"proxy-authenticate":  true,
+	"proxy-authorization": true,
+	"proxy-connection":    true,
+	"te":                  true,
+	"transfer-encoding":   true,
+	"upgrade":             true,
+}
+
+// IsForbiddenHeader reports whether name is a connection-specific header that
+// a host may reject as forbidden, such as Connection or Transfer-Encoding.
+// The comparison is case-insensitive.
+func IsForbiddenHeader(name string) bool {
+	return forbiddenHeaders[strings.ToLower(name)]
+}
+
+// HeaderEntries returns the entries of h in the form accepted by [FieldsFromList],
+// with one entry for each value. Names are lowercase, as in HTTP/2, and sorted.
+// Forbidden headers (see [IsForbiddenHeader]) are omitted.
+//
+// The returned list shares memory with the names and values in h,
+// which must not be modified until the list is no longer used.
+func HeaderEntries(h http.Header) cm.List[cm.Tuple[FieldKey, FieldValue]] {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		if !IsForbiddenHeader(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var entries []cm.Tuple[FieldKey, FieldValue]
+	for _, name := range names {
+		key := FieldKey(strings.ToLower(name))
+		for _, v := range h[name] {
+			entries = append(entries, cm.Tuple[FieldKey, FieldValue]{F0: key, F1: FieldValue(cm.ToListString(v))})
+		}
+	}
+	// Names that differ only in case, e.g. if h was not built with [http.Header.Add],
+	// are adjacent after sorting by lowercase name.
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].F0 < entries[j].F0 })
+	return cm.ToList(entries)
+}
+
+// HeaderFromEntries returns an [http.Header] with the entries of a [Fields].
+// Names are converted to their canonical form with [http.CanonicalHeaderKey],
+// and multiple entries with the same name are combined in order.
+// Values are copied, so the result does not share memory with entries.
+func HeaderFromEntries(entries cm.List[cm.Tuple[FieldKey, FieldValue]]) http.Header {
+	h := make(http.Header, entries.Len())
+	for _, e := range entries.Slice() {
+		name := http.CanonicalHeaderKey(string(e.F0))
+		h[name] = append(h[name], cm.ListString(cm.List[uint8](e.F1)))
+	}
+	return h
+}
+
+// FieldsFromHeader returns a new, mutable [Fields] with the entries of h.
+// Forbidden headers are omitted. It returns a [HeaderError] if the host rejects
+// a name or value, for example if it is syntactically invalid.
+func FieldsFromHeader(h http.Header) (Fields, error) {
+	result := FieldsFromList(HeaderEntries(h))
+	if err := result.Err(); err != nil {
+		return 0, *err
+	}
+	return *result.OK(), nil
+}
+
+// Header returns the entries of f as an [http.Header].
+// See [HeaderFromEntries].
+func (self Fields) Header() http.Header {
+	return HeaderFromEntries(self.Entries())
+}
+
+// SetHeader replaces the values of each header in h in mutable Fields f.
+// Headers in f that are not in h are not modified. Forbidden headers are skipped.
+// It returns a [HeaderError] if the host rejects a name or value,
+// or if f is immutable.
+func (self Fields) SetHeader(h http.Header) error {
+	entries := HeaderEntries(h).Slice()
+	for i := 0; i < len(entries); {
+		key := entries[i].F0
+		var values []FieldValue
+		for ; i < len(entries) && entries[i].F0 == key; i++ {
+			values = append(values, entries[i].F1)
+		}
+		result := self.Set(key, cm.ToList(values))
+		if err := result.Err(); err != nil {
+			return *err
+		}
+	}
+	return nil
+}
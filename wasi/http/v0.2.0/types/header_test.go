@@ -0,0 +1,78 @@
+//go:build !wasip1
+
+package types
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+func TestHeaderEntries(t *testing.T) {
+	h := http.Header{
+		"Content-Type": {"text/plain"},
+		"X-Multi":      {"a", "b"},
+		"x-multi":      {"c"},
+		"Connection":   {"close"},
+		"Host":         {"example.com"},
+	}
+	var got [][2]string
+	for _, e := range HeaderEntries(h).Slice() {
+		got = append(got, [2]string{string(e.F0), string(cm.List[uint8](e.F1).Slice())})
+	}
+	want := [][2]string{
+		{"content-type", "text/plain"},
+		{"x-multi", "a"},
+		{"x-multi", "b"},
+		{"x-multi", "c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HeaderEntries: %v, expected %v", got, want)
+	}
+}
+
+func TestHeaderFromEntries(t *testing.T) {
+	entry := func(k, v string) cm.Tuple[FieldKey, FieldValue] {
+		return cm.Tuple[FieldKey, FieldValue]{F0: FieldKey(k), F1: FieldValue(cm.ToListString(v))}
+	}
+	entries := cm.ToList([]cm.Tuple[FieldKey, FieldValue]{
+		entry("content-type", "text/plain"),
+		entry("set-cookie", "a=1"),
+		entry("Set-Cookie", "b=2"),
+		entry("x-empty", ""),
+	})
+	got := HeaderFromEntries(entries)
+	want := http.Header{
+		"Content-Type": {"text/plain"},
+		"Set-Cookie":   {"a=1", "b=2"},
+		"X-Empty":      {""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HeaderFromEntries: %v, expected %v", got, want)
+	}
+	if got.Get("content-type") != "text/plain" {
+		t.Errorf("Get(content-type): %q, expected %q", got.Get("content-type"), "text/plain")
+	}
+}
+
+func TestIsForbiddenHeader(t *testing.T) {
+	for _, name := range []string{"Connection", "transfer-encoding", "HOST", "Upgrade"} {
+		if !IsForbiddenHeader(name) {
+			t.Errorf("IsForbiddenHeader(%q): false, expected true", name)
+		}
+	}
+	for _, name := range []string{"Content-Type", "X-Connection"} {
+		if IsForbiddenHeader(name) {
+			t.Errorf("IsForbiddenHeader(%q): true, expected false", name)
+		}
+	}
+}
+
+func TestHeaderError(t *testing.T) {
+	var err error = HeaderErrorForbidden
+	if got, want := err.Error(), "wasi:http/types: header error: forbidden"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.8
+// Checksum: sha256:9d71ba39b210332fdb879b18332de995d520a0dd4e9e52f6ddce5e098b39d3ce
+
+//go:build !wasip1
+
+// Package outgoinghandler represents the imported interface "wasi:http/outgoing-handler@0.2.8".
+//
+// This interface defines a handler of outgoing HTTP Requests. It should be
+// imported by components which wish to make HTTP Requests.
+package outgoinghandler
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/http/v0.2.8/types"
+)
+
+// Handle represents the imported function "handle".
+//
+// This function is invoked with an outgoing HTTP Request, and it returns
+// a resource `future-incoming-response` which represents an HTTP Response
+// which may arrive in the future.
+//
+// The `options` argument accepts optional parameters for the HTTP
+// protocol's transport layer.
+//
+// This function may return an error if the `outgoing-request` is invalid
+// or not allowed to be made. Otherwise, protocol errors are reported
+// through the `future-incoming-response`.
+//
+//	handle: func(request: outgoing-request, options: option<request-options>) -> result<future-incoming-response,
+//	error-code>
+//
+//go:nosplit
+func Handle(request types.OutgoingRequest, options cm.Option[types.RequestOptions]) cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode] {
+	var result cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode]
+	wasmimport_Handle(request, options, &result)
+	return result
+}
+
+//go:wasmimport wasi:http/outgoing-handler@0.2.8 handle
+//go:noescape
+func wasmimport_Handle(request types.OutgoingRequest, options cm.Option[types.RequestOptions], result *cm.ErrResult[types.FutureIncomingResponse, types.ErrorCode])
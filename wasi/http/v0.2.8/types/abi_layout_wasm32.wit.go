@@ -0,0 +1,31 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.8
+// Checksum: sha256:fa808fc7d49d5109c3c493b05ae0e5fffc5e1d2607e7314356ba752645320297
+
+//go:build !wasip1 && tinygo.wasm
+
+package types
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Method{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Method{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(Scheme{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Scheme{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(DNSErrorPayload{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(DNSErrorPayload{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(TLSAlertReceivedPayload{}) - 16]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(TLSAlertReceivedPayload{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(FieldSizePayload{}) - 20]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(FieldSizePayload{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(ErrorCode{}) - 32]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(ErrorCode{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(FieldValue{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(FieldValue{}) - 4]struct{}{}
@@ -0,0 +1,66 @@
+//go:build !wasip1
+
+package streams
+
+import (
+	ioerror "github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/error"
+)
+
+// Error is a Go error for a failed stream operation, represented by case
+// "last-operation-failed" of a [StreamError]. The debug string of its [ioerror.Error]
+// resource is read when the Error is created, so calling Error does not call the host.
+//
+// An Error owns the resource, which can be passed to a function that downcasts it,
+// such as wasi:http/types#http-error-code, until the Error is closed.
+// Call [Error.Close] to drop the resource when it is no longer needed.
+type Error struct {
+	resource ioerror.Error
+	debug    string
+	closed   bool
+}
+
+// NewError returns a Go error for v. If v represents case "closed", it returns closed,
+// typically [io.EOF] for an input stream. Otherwise it returns an *[Error], which takes
+// ownership of the [ioerror.Error] resource of the failed operation, so [errors.As] can
+// recover it.
+//
+// [io.EOF]: https://pkg.go.dev/io#EOF
+// [errors.As]: https://pkg.go.dev/errors#As
+func NewError(v StreamError, closed error) error {
+	if v.Closed() {
+		return closed
+	}
+	return newError(*v.LastOperationFailed())
+}
+
+func newError(resource ioerror.Error) *Error {
+	return &Error{resource: resource, debug: toDebugString(resource)}
+}
+
+// Error implements the error interface, returning the debug string of the failed operation.
+func (e *Error) Error() string {
+	return "wasi:io/streams: last operation failed: " + e.debug
+}
+
+// Resource returns the [ioerror.Error] resource owned by e.
+// It must not be used after e is closed.
+func (e *Error) Resource() ioerror.Error {
+	return e.resource
+}
+
+// Close drops the [ioerror.Error] resource owned by e. Subsequent calls to Close do nothing.
+// The debug string of e remains available after Close.
+func (e *Error) Close() error {
+	if !e.closed {
+		e.closed = true
+		dropError(e.resource)
+	}
+	return nil
+}
+
+// toDebugString and dropError call the host. They are set in errors_wasm.go,
+// and replaced by fakes in tests, which run without WebAssembly.
+var (
+	toDebugString func(ioerror.Error) string
+	dropError     func(ioerror.Error)
+)
@@ -0,0 +1,47 @@
+//go:build !wasip1
+
+package streams
+
+import (
+	"strconv"
+	"testing"
+
+	ioerror "github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/error"
+)
+
+func TestError(t *testing.T) {
+	var dropped []uint32
+	defer fakeIOError(func(e ioerror.Error) { dropped = append(dropped, uint32(e)) })()
+
+	e := newError(7)
+	want := "wasi:io/streams: last operation failed: error 7"
+	if got := e.Error(); got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+	if got := e.Resource(); got != 7 {
+		t.Errorf("Resource(): %d, expected 7", uint32(got))
+	}
+	e.Close()
+	e.Close()
+	if len(dropped) != 1 || dropped[0] != 7 {
+		t.Errorf("Close dropped %d resources, expected resource 7 once", len(dropped))
+	}
+	if got := e.Error(); got != want {
+		t.Errorf("Error() after Close: %q, expected %q", got, want)
+	}
+}
+
+// fakeIOError replaces the host calls for ioerror.Error resources with fakes
+// that describe and drop them, returning a function that restores them.
+// Tests that run without WebAssembly cannot call the host, or use the runtime type
+// of ioerror.Error, e.g. in a slice, which refers to its methods that call the host.
+func fakeIOError(drop func(ioerror.Error)) func() {
+	debug, prevDrop := toDebugString, dropError
+	toDebugString = func(e ioerror.Error) string {
+		return "error " + strconv.Itoa(int(e))
+	}
+	dropError = drop
+	return func() {
+		toDebugString, dropError = debug, prevDrop
+	}
+}
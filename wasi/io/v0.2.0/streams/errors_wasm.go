@@ -0,0 +1,12 @@
+//go:build !wasip1
+
+package streams
+
+import (
+	ioerror "github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/error"
+)
+
+func init() {
+	toDebugString = ioerror.Error.ToDebugString
+	dropError = ioerror.Error.ResourceDrop
+}
@@ -0,0 +1,214 @@
+//go:build !wasip1
+
+package streams
+
+import (
+	"errors"
+	"io"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/poll"
+)
+
+var errClosed = errors.New("wasi:io/streams: stream closed")
+
+// Reader reads from an [InputStream] as a Go stream of bytes. It implements [io.ReadCloser].
+// Reads wait for data with a pollable, rather than returning zero bytes.
+//
+// When the stream is closed, Read returns [io.EOF]. If reading the stream fails,
+// Read returns a *[Error], which owns a wasi:io/error resource that the caller
+// may drop with [Error.Close].
+type Reader struct {
+	stream input
+	closed bool
+}
+
+// NewReader returns a [Reader] that takes ownership of s.
+func NewReader(s InputStream) *Reader {
+	return &Reader{stream: &inputStream{stream: s}}
+}
+
+// Read implements [io.Reader]. It blocks until at least one byte is available,
+// the stream is closed, or an error occurs.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, errClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		data, err := r.stream.read(uint64(len(p)))
+		if err != nil {
+			return 0, err
+		}
+		if len(data) > 0 {
+			return copy(p, data), nil
+		}
+		r.stream.wait()
+	}
+}
+
+// Close implements [io.Closer]. It drops the stream. Close is idempotent.
+func (r *Reader) Close() error {
+	if !r.closed {
+		r.closed = true
+		r.stream.drop()
+	}
+	return nil
+}
+
+// Writer writes to an [OutputStream] as a Go stream of bytes. It implements [io.WriteCloser].
+// Writes wait until the stream is ready to accept more data, applying backpressure
+// instead of buffering in memory.
+//
+// If the host closes the stream, methods return [io.ErrClosedPipe]. If writing to
+// the stream fails, methods return a *[Error], as with [Reader].
+type Writer struct {
+	stream output
+	closed bool
+}
+
+// NewWriter returns a [Writer] that takes ownership of s.
+func NewWriter(s OutputStream) *Writer {
+	return &Writer{stream: &outputStream{stream: s}}
+}
+
+// Write implements [io.Writer]. It writes as much of p as the stream accepts,
+// waiting for the stream to be ready as needed, until all of p is written
+// or an error occurs.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errClosed
+	}
+	var n int
+	for n < len(p) {
+		size, err := w.stream.checkWrite()
+		if err != nil {
+			return n, err
+		}
+		if size == 0 {
+			w.stream.wait()
+			continue
+		}
+		chunk := p[n:]
+		if uint64(len(chunk)) > size {
+			chunk = chunk[:size]
+		}
+		if err := w.stream.write(chunk); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Flush blocks until all data written to w has been flushed.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return errClosed
+	}
+	return w.stream.flush()
+}
+
+// Close implements [io.Closer]. It drops the stream without flushing it;
+// call [Writer.Flush] first to wait until written data is accepted by the host.
+// Close is idempotent.
+func (w *Writer) Close() error {
+	if !w.closed {
+		w.closed = true
+		w.stream.drop()
+	}
+	return nil
+}
+
+// input and output are the operations on streams used by [Reader] and [Writer],
+// with results converted to Go values. Tests replace them with fakes,
+// which run without WebAssembly.
+type (
+	input interface {
+		read(n uint64) ([]byte, error)
+		wait()
+		drop()
+	}
+
+	output interface {
+		checkWrite() (uint64, error)
+		write(p []byte) error
+		flush() error
+		wait()
+		drop()
+	}
+)
+
+type inputStream struct {
+	stream   InputStream
+	pollable poll.Pollable
+}
+
+func (s *inputStream) read(n uint64) ([]byte, error) {
+	result := s.stream.Read(n)
+	if err := result.Err(); err != nil {
+		return nil, NewError(*err, io.EOF)
+	}
+	return result.OK().Slice(), nil
+}
+
+func (s *inputStream) wait() {
+	if s.pollable == 0 {
+		s.pollable = s.stream.Subscribe()
+	}
+	s.pollable.Block()
+}
+
+func (s *inputStream) drop() {
+	// The pollable is a child of the stream, and must be dropped first.
+	if s.pollable != 0 {
+		s.pollable.ResourceDrop()
+	}
+	s.stream.ResourceDrop()
+}
+
+type outputStream struct {
+	stream   OutputStream
+	pollable poll.Pollable
+}
+
+func (s *outputStream) checkWrite() (uint64, error) {
+	result := s.stream.CheckWrite()
+	if err := result.Err(); err != nil {
+		return 0, NewError(*err, io.ErrClosedPipe)
+	}
+	return *result.OK(), nil
+}
+
+func (s *outputStream) write(p []byte) error {
+	result := s.stream.Write(cm.ToList(p))
+	if err := result.Err(); err != nil {
+		return NewError(*err, io.ErrClosedPipe)
+	}
+	return nil
+}
+
+func (s *outputStream) flush() error {
+	result := s.stream.BlockingFlush()
+	if err := result.Err(); err != nil {
+		return NewError(*err, io.ErrClosedPipe)
+	}
+	return nil
+}
+
+func (s *outputStream) wait() {
+	if s.pollable == 0 {
+		s.pollable = s.stream.Subscribe()
+	}
+	s.pollable.Block()
+}
+
+func (s *outputStream) drop() {
+	// The pollable is a child of the stream, and must be dropped first.
+	if s.pollable != 0 {
+		s.pollable.ResourceDrop()
+	}
+	s.stream.ResourceDrop()
+}
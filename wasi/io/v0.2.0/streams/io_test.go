@@ -0,0 +1,177 @@
+//go:build !wasip1
+
+package streams
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	s := &fakeInput{reads: [][]byte{[]byte("hello, "), nil, nil, []byte("world")}, err: io.EOF}
+	r := &Reader{stream: s}
+	if n, err := r.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil): %d, %v, expected 0, nil", n, err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Errorf("ReadAll: %v", err)
+	}
+	if string(got) != "hello, world" {
+		t.Errorf("ReadAll: %q, expected %q", got, "hello, world")
+	}
+	if s.waits != 2 {
+		t.Errorf("Reader waited %d times, expected 2", s.waits)
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read after EOF: %d, %v, expected 0, io.EOF", n, err)
+	}
+	r.Close()
+	r.Close()
+	if s.drops != 1 {
+		t.Errorf("Close dropped the stream %d times, expected 1", s.drops)
+	}
+	if _, err := r.Read(make([]byte, 1)); err != errClosed {
+		t.Errorf("Read after Close: %v, expected %v", err, errClosed)
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	s := &fakeInput{reads: [][]byte{[]byte("partial")}, err: errFailed}
+	r := &Reader{stream: s}
+	got, err := io.ReadAll(r)
+	if string(got) != "partial" {
+		t.Errorf("ReadAll: %q, expected %q", got, "partial")
+	}
+	if err != errFailed {
+		t.Errorf("ReadAll: %v, expected %v", err, errFailed)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	s := &fakeOutput{sizes: []uint64{4, 0, 0, 100}}
+	w := &Writer{stream: s}
+	n, err := w.Write([]byte("hello, world"))
+	if n != 12 || err != nil {
+		t.Errorf("Write: %d, %v, expected 12, nil", n, err)
+	}
+	if got := s.buf.String(); got != "hello, world" {
+		t.Errorf("wrote %q, expected %q", got, "hello, world")
+	}
+	if s.waits != 2 {
+		t.Errorf("Writer waited %d times, expected 2", s.waits)
+	}
+	if err := w.Flush(); err != nil {
+		t.Errorf("Flush: %v", err)
+	}
+	if s.flushes != 1 {
+		t.Errorf("Flush flushed the stream %d times, expected 1", s.flushes)
+	}
+	w.Close()
+	w.Close()
+	if s.drops != 1 {
+		t.Errorf("Close dropped the stream %d times, expected 1", s.drops)
+	}
+	if _, err := w.Write([]byte("x")); err != errClosed {
+		t.Errorf("Write after Close: %v, expected %v", err, errClosed)
+	}
+	if err := w.Flush(); err != errClosed {
+		t.Errorf("Flush after Close: %v, expected %v", err, errClosed)
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	s := &fakeOutput{sizes: []uint64{5}, err: io.ErrClosedPipe}
+	w := &Writer{stream: s}
+	n, err := w.Write([]byte("hello, world"))
+	if n != 5 || err != io.ErrClosedPipe {
+		t.Errorf("Write: %d, %v, expected 5, io.ErrClosedPipe", n, err)
+	}
+	if err := w.Flush(); err != io.ErrClosedPipe {
+		t.Errorf("Flush: %v, expected io.ErrClosedPipe", err)
+	}
+}
+
+func TestWriterError(t *testing.T) {
+	s := &fakeOutput{sizes: []uint64{100}, writeErr: errFailed}
+	w := &Writer{stream: s}
+	n, err := w.Write([]byte("hello"))
+	if n != 0 || err != errFailed {
+		t.Errorf("Write: %d, %v, expected 0, %v", n, err, errFailed)
+	}
+}
+
+// errFailed is returned by fake streams in place of an *[Error], which cannot be used
+// as an error in tests that run without WebAssembly, because its methods refer to
+// methods of [ioerror.Error] that call the host.
+var errFailed = errors.New("last operation failed")
+
+// fakeInput is an input stream that returns each of reads in turn,
+// split to the length requested, then err.
+// An empty read is returned as zero bytes, as if the stream were not ready.
+type fakeInput struct {
+	reads [][]byte
+	err   error
+	waits int
+	drops int
+}
+
+func (s *fakeInput) read(n uint64) ([]byte, error) {
+	if len(s.reads) == 0 {
+		return nil, s.err
+	}
+	data := s.reads[0]
+	if uint64(len(data)) > n {
+		s.reads[0] = data[n:]
+		return data[:n], nil
+	}
+	s.reads = s.reads[1:]
+	return data, nil
+}
+
+func (s *fakeInput) wait() { s.waits++ }
+func (s *fakeInput) drop() { s.drops++ }
+
+// fakeOutput is an output stream that permits writes of each of sizes in turn,
+// then returns err from check-write and flush. Data written is appended to buf.
+type fakeOutput struct {
+	sizes    []uint64
+	err      error
+	writeErr error
+	permit   uint64
+	buf      bytes.Buffer
+	waits    int
+	flushes  int
+	drops    int
+}
+
+func (s *fakeOutput) checkWrite() (uint64, error) {
+	if len(s.sizes) == 0 {
+		return 0, s.err
+	}
+	s.permit = s.sizes[0]
+	s.sizes = s.sizes[1:]
+	return s.permit, nil
+}
+
+func (s *fakeOutput) write(p []byte) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	if uint64(len(p)) > s.permit {
+		return errors.New("write exceeds check-write")
+	}
+	s.permit = 0
+	s.buf.Write(p)
+	return nil
+}
+
+func (s *fakeOutput) flush() error {
+	s.flushes++
+	return s.err
+}
+
+func (s *fakeOutput) wait() { s.waits++ }
+func (s *fakeOutput) drop() { s.drops++ }
@@ -0,0 +1,106 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.8
+// Checksum: sha256:e4c101a9266a443f91894f75f9c4762ea38f59b730a5293ea27988117455d59a
+
+//go:build !wasip1
+
+// Package poll represents the imported interface "wasi:io/poll@0.2.8".
+//
+// A poll API intended to let users wait for I/O events on multiple handles
+// at once.
+package poll
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Pollable represents the imported resource "wasi:io/poll@0.2.8#pollable".
+//
+// `pollable` represents a single I/O event which may be ready, or not.
+//
+//	resource pollable
+type Pollable cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "pollable".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self Pollable) ResourceDrop() {
+	self.wasmimport_ResourceDrop()
+}
+
+//go:wasmimport wasi:io/poll@0.2.8 [resource-drop]pollable
+//go:noescape
+func (self Pollable) wasmimport_ResourceDrop()
+
+// Block represents the imported method "block".
+//
+// `block` returns immediately if the pollable is ready, and otherwise
+// blocks until ready.
+//
+// This function is equivalent to calling `poll.poll` on a list
+// containing only this pollable.
+//
+//	block: func()
+//
+//go:nosplit
+func (self Pollable) Block() {
+	self.wasmimport_Block()
+}
+
+//go:wasmimport wasi:io/poll@0.2.8 [method]pollable.block
+//go:noescape
+func (self Pollable) wasmimport_Block()
+
+// Ready represents the imported method "ready".
+//
+// Return the readiness of a pollable. This function never blocks.
+//
+// Returns `true` when the pollable is ready, and `false` otherwise.
+//
+//	ready: func() -> bool
+//
+//go:nosplit
+func (self Pollable) Ready() bool {
+	return cm.U32ToBool(self.wasmimport_Ready())
+}
+
+//go:wasmimport wasi:io/poll@0.2.8 [method]pollable.ready
+//go:noescape
+func (self Pollable) wasmimport_Ready() uint32
+
+// Poll represents the imported function "poll".
+//
+// Poll for completion on a set of pollables.
+//
+// This function takes a list of pollables, which identify I/O sources of
+// interest, and waits until one or more of the events is ready for I/O.
+//
+// The result `list<u32>` contains one or more indices of handles in the
+// argument list that is ready for I/O.
+//
+// This function traps if either:
+// - the list is empty, or:
+// - the list contains more elements than can be indexed with a `u32` value.
+//
+// A timeout can be implemented by adding a pollable from the
+// wasi-clocks API to the list.
+//
+// This function does not return a `result`; polling in itself does not
+// do any I/O so it doesn't fail. If any of the I/O sources identified by
+// the pollables has an error, it is indicated by marking the source as
+// being ready for I/O.
+//
+//	poll: func(in: list<borrow<pollable>>) -> list<u32>
+//
+//go:nosplit
+func Poll(in cm.List[Pollable]) cm.List[uint32] {
+	var result cm.List[uint32]
+	wasmimport_Poll(in, &result)
+	return result
+}
+
+//go:wasmimport wasi:io/poll@0.2.8 poll
+//go:noescape
+func wasmimport_Poll(in cm.List[Pollable], result *cm.List[uint32])
@@ -0,0 +1,19 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:http/proxy@0.2.8
+// Checksum: sha256:405ae3f2d98787d18487b26f52b1d09d6fb3d5fc2b8ed9dcba07ab6e2d6f5334
+
+//go:build !wasip1 && !(386 || arm || mips || mipsle)
+
+package streams
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation. Types in this file do not contain pointers,
+// and have the same layout on 64-bit architectures and 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(StreamError{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(StreamError{}) - 4]struct{}{}
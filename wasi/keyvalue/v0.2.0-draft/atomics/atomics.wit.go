@@ -0,0 +1,39 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:keyvalue/imports@0.2.0-draft
+// Checksum: sha256:41a5aca61840348eff35ce836be9738a6202b51e27ad0145324264356d3c95a5
+
+//go:build !wasip1
+
+// Package atomics represents the imported interface "wasi:keyvalue/atomics@0.2.0-draft".
+//
+// A keyvalue interface that provides atomic operations.
+package atomics
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/keyvalue/v0.2.0-draft/store"
+)
+
+// Increment represents the imported function "increment".
+//
+// Atomically increment the value associated with the key in the store by the given
+// delta. It
+// returns the new value.
+//
+// If the key does not exist in the store, it creates a new key-value pair with the
+// value set
+// to the given delta.
+//
+//	increment: func(bucket: borrow<bucket>, key: string, delta: u64) -> result<u64,
+//	error>
+//
+//go:nosplit
+func Increment(bucket store.Bucket, key string, delta uint64) cm.ErrResult[uint64, store.Error] {
+	var result cm.ErrResult[uint64, store.Error]
+	wasmimport_Increment(bucket, key, delta, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/atomics@0.2.0-draft increment
+//go:noescape
+func wasmimport_Increment(bucket store.Bucket, key string, delta uint64, result *cm.ErrResult[uint64, store.Error])
@@ -0,0 +1,78 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:keyvalue/imports@0.2.0-draft
+// Checksum: sha256:b97716dc89105c2fde5441fd8a3245b84b7c4ac675b01c67aad7c250691a3695
+
+//go:build !wasip1
+
+// Package batch represents the imported interface "wasi:keyvalue/batch@0.2.0-draft".
+//
+// A keyvalue interface that provides batch operations.
+package batch
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/keyvalue/v0.2.0-draft/store"
+)
+
+// GetMany represents the imported function "get-many".
+//
+// Get the key-value pairs associated with the keys in the store. It returns a list
+// of
+// key-value pairs.
+//
+// If any of the keys do not exist in the store, it returns a `none` value for that
+// pair in the
+// list.
+//
+//	get-many: func(bucket: borrow<bucket>, keys: list<string>) -> result<list<option<tuple<string,
+//	list<u8>>>>, error>
+//
+//go:nosplit
+func GetMany(bucket store.Bucket, keys cm.List[string]) cm.ErrResult[cm.List[cm.Option[cm.Tuple[string, cm.List[uint8]]]], store.Error] {
+	var result cm.ErrResult[cm.List[cm.Option[cm.Tuple[string, cm.List[uint8]]]], store.Error]
+	wasmimport_GetMany(bucket, keys, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/batch@0.2.0-draft get-many
+//go:noescape
+func wasmimport_GetMany(bucket store.Bucket, keys cm.List[string], result *cm.ErrResult[cm.List[cm.Option[cm.Tuple[string, cm.List[uint8]]]], store.Error])
+
+// SetMany represents the imported function "set-many".
+//
+// Set the values associated with the keys in the store. If the key already exists
+// in the
+// store, it overwrites the value.
+//
+//	set-many: func(bucket: borrow<bucket>, key-values: list<tuple<string, list<u8>>>)
+//	-> result<_, error>
+//
+//go:nosplit
+func SetMany(bucket store.Bucket, keyValues cm.List[cm.Tuple[string, cm.List[uint8]]]) cm.ErrResult[struct{}, store.Error] {
+	var result cm.ErrResult[struct{}, store.Error]
+	wasmimport_SetMany(bucket, keyValues, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/batch@0.2.0-draft set-many
+//go:noescape
+func wasmimport_SetMany(bucket store.Bucket, keyValues cm.List[cm.Tuple[string, cm.List[uint8]]], result *cm.ErrResult[struct{}, store.Error])
+
+// DeleteMany represents the imported function "delete-many".
+//
+// Delete the key-value pairs associated with the keys in the store.
+//
+// If any of the keys do not exist in the store, it skips the key.
+//
+//	delete-many: func(bucket: borrow<bucket>, keys: list<string>) -> result<_, error>
+//
+//go:nosplit
+func DeleteMany(bucket store.Bucket, keys cm.List[string]) cm.ErrResult[struct{}, store.Error] {
+	var result cm.ErrResult[struct{}, store.Error]
+	wasmimport_DeleteMany(bucket, keys, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/batch@0.2.0-draft delete-many
+//go:noescape
+func wasmimport_DeleteMany(bucket store.Bucket, keys cm.List[string], result *cm.ErrResult[struct{}, store.Error])
@@ -0,0 +1,20 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:keyvalue/imports@0.2.0-draft
+// Checksum: sha256:90784cab65eb19817d2aac0a6023a172f25ee614c10b4d2b7d2ab3b957a1f4c2
+
+//go:build tinygo.wasm
+
+package store
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(KeyResponse{}) - 24]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(KeyResponse{}) - 8]struct{}{}
@@ -0,0 +1,21 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:keyvalue/imports@0.2.0-draft
+// Checksum: sha256:8cb473fe9a561a80588bbdba86030bdf5e60ae36120f2cf0e696bc25666824bb
+
+//go:build !wasip1 && tinygo.wasm
+
+package store
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(KeyResponse{}) - 24]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(KeyResponse{}) - 8]struct{}{}
@@ -0,0 +1,124 @@
+//go:build !wasip1
+
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Error implements the error interface.
+func (v Error) Error() string {
+	if s := v.Other(); s != nil {
+		return "wasi:keyvalue/store: " + *s
+	}
+	return "wasi:keyvalue/store: " + v.String()
+}
+
+// ErrNotFound is returned by [Store.Get] if a key does not exist.
+var ErrNotFound = errors.New("wasi:keyvalue/store: key not found")
+
+var errStoreClosed = errors.New("wasi:keyvalue/store: store closed")
+
+// Store is a key-value [Bucket] with methods that take and return Go types.
+// Errors from the host are returned as an [Error].
+//
+// Calls to the host are synchronous and cannot be interrupted. Each method returns
+// ctx.Err() without calling the host if ctx is done.
+type Store struct {
+	bucket Bucket
+	closed bool
+}
+
+// OpenStore opens the bucket with identifier, which must refer to a bucket provided by the host.
+func OpenStore(ctx context.Context, identifier string) (*Store, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := Open(identifier)
+	if err := result.Err(); err != nil {
+		return nil, *err
+	}
+	return NewStore(*result.OK()), nil
+}
+
+// NewStore returns a [Store] that takes ownership of bucket.
+func NewStore(bucket Bucket) *Store {
+	return &Store{bucket: bucket}
+}
+
+// Get returns the value associated with key, or [ErrNotFound] if key does not exist.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	result := s.bucket.Get(key)
+	if err := result.Err(); err != nil {
+		return nil, *err
+	}
+	value := result.OK().Some()
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	return value.Slice(), nil
+}
+
+// Set sets the value associated with key, overwriting any existing value.
+// The host copies value, which may be modified after Set returns.
+func (s *Store) Set(ctx context.Context, key string, value []byte) error {
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	result := s.bucket.Set(key, cm.ToList(value))
+	if err := result.Err(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Delete deletes the value associated with key. Deleting a key that does not exist is not an error.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	result := s.bucket.Delete(key)
+	if err := result.Err(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Exists reports whether key exists.
+func (s *Store) Exists(ctx context.Context, key string) (bool, error) {
+	if err := s.check(ctx); err != nil {
+		return false, err
+	}
+	result := s.bucket.Exists(key)
+	if err := result.Err(); err != nil {
+		return false, *err
+	}
+	return *result.OK(), nil
+}
+
+// Bucket returns the underlying bucket of s, which remains owned by s.
+func (s *Store) Bucket() Bucket {
+	return s.bucket
+}
+
+// Close drops the underlying bucket of s. Subsequent calls to Close do nothing.
+func (s *Store) Close() error {
+	if !s.closed {
+		s.closed = true
+		s.bucket.ResourceDrop()
+	}
+	return nil
+}
+
+func (s *Store) check(ctx context.Context) error {
+	if s.closed {
+		return errStoreClosed
+	}
+	return ctx.Err()
+}
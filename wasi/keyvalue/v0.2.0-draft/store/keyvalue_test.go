@@ -0,0 +1,22 @@
+//go:build !wasip1
+
+package store
+
+import "testing"
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err  Error
+		want string
+	}{
+		{ErrorNoSuchStore(), "wasi:keyvalue/store: no-such-store"},
+		{ErrorAccessDenied(), "wasi:keyvalue/store: access-denied"},
+		{ErrorOther("disk full"), "wasi:keyvalue/store: disk full"},
+	}
+	for _, tt := range tests {
+		var err error = tt.err
+		if got := err.Error(); got != tt.want {
+			t.Errorf("Error(): %q, expected %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:keyvalue/imports@0.2.0-draft
+// Checksum: sha256:0aa73f7837cb8612a46fff71c823612aa90c8f0d5ae477e4eda28c4f82d23a42
+
+//go:build !wasip1
+
+// Package store represents the imported interface "wasi:keyvalue/store@0.2.0-draft".
+//
+// A keyvalue interface that provides eventually consistent key-value operations.
+//
+// Each of these operations acts on a single key-value pair.
+//
+// The value in the key-value pair is defined as a `u8` byte array and the intention
+// is that it is
+// the common denominator for all data types defined by different key-value stores
+// to handle data,
+// ensuring compatibility between different key-value stores.
+package store
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Error represents the imported variant "wasi:keyvalue/store@0.2.0-draft#error".
+//
+// The set of errors which may be raised by functions in this package
+//
+//	variant error {
+//		no-such-store,
+//		access-denied,
+//		other(string),
+//	}
+type Error cm.Variant[uint8, string, string]
+
+// ErrorNoSuchStore returns a [Error] of case "no-such-store".
+//
+// The host does not recognize the store identifier requested.
+func ErrorNoSuchStore() Error {
+	var data struct{}
+	return cm.New[Error](0, data)
+}
+
+// NoSuchStore returns true if [Error] represents the variant case "no-such-store".
+func (self *Error) NoSuchStore() bool {
+	return cm.Tag(self) == 0
+}
+
+// ErrorAccessDenied returns a [Error] of case "access-denied".
+//
+// The requesting component does not have access to the specified store
+// (which may or may not exist).
+func ErrorAccessDenied() Error {
+	var data struct{}
+	return cm.New[Error](1, data)
+}
+
+// AccessDenied returns true if [Error] represents the variant case "access-denied".
+func (self *Error) AccessDenied() bool {
+	return cm.Tag(self) == 1
+}
+
+// ErrorOther returns a [Error] of case "other".
+//
+// Some implementation-specific error has occurred (e.g. I/O)
+func ErrorOther(data string) Error {
+	return cm.New[Error](2, data)
+}
+
+// Other returns a non-nil *[string] if [Error] represents the variant case "other".
+func (self *Error) Other() *string {
+	return cm.Case[string](self, 2)
+}
+
+var stringsError = [3]string{
+	"no-such-store",
+	"access-denied",
+	"other",
+}
+
+// String implements [fmt.Stringer], returning the variant case name of v.
+func (v Error) String() string {
+	return cm.CaseString(stringsError[:], cm.Tag(&v))
+}
+
+// KeyResponse represents the imported record "wasi:keyvalue/store@0.2.0-draft#key-response".
+//
+// A response to a `list-keys` operation.
+//
+//	record key-response {
+//		keys: list<string>,
+//		cursor: option<u64>,
+//	}
+type KeyResponse struct {
+	// The list of keys returned by the query.
+	Keys cm.List[string]
+
+	// The continuation token to use to fetch the next page of keys. If this is `null`,
+	// then
+	// there are no more keys to fetch.
+	Cursor cm.Option[uint64]
+}
+
+// Bucket represents the imported resource "wasi:keyvalue/store@0.2.0-draft#bucket".
+//
+// A bucket is a collection of key-value pairs. Each key-value pair is stored as a
+// entry in the
+// bucket, and the bucket itself acts as a collection of all these entries.
+//
+//	resource bucket
+type Bucket cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "bucket".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self Bucket) ResourceDrop() {
+	self.wasmimport_ResourceDrop()
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [resource-drop]bucket
+//go:noescape
+func (self Bucket) wasmimport_ResourceDrop()
+
+// Delete represents the imported method "delete".
+//
+// Delete the key-value pair associated with the key in the store.
+//
+// If the key does not exist in the store, it does nothing.
+//
+//	delete: func(key: string) -> result<_, error>
+//
+//go:nosplit
+func (self Bucket) Delete(key string) cm.ErrResult[struct{}, Error] {
+	var result cm.ErrResult[struct{}, Error]
+	self.wasmimport_Delete(key, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [method]bucket.delete
+//go:noescape
+func (self Bucket) wasmimport_Delete(key string, result *cm.ErrResult[struct{}, Error])
+
+// Exists represents the imported method "exists".
+//
+// Check if the key exists in the store.
+//
+//	exists: func(key: string) -> result<bool, error>
+//
+//go:nosplit
+func (self Bucket) Exists(key string) cm.ErrResult[bool, Error] {
+	var result cm.ErrResult[bool, Error]
+	self.wasmimport_Exists(key, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [method]bucket.exists
+//go:noescape
+func (self Bucket) wasmimport_Exists(key string, result *cm.ErrResult[bool, Error])
+
+// Get represents the imported method "get".
+//
+// Get the value associated with the specified `key`
+//
+// The value is returned as an option. If the key-value pair exists in the
+// store, it returns `Ok(value)`. If the key does not exist in the
+// store, it returns `Ok(none)`.
+//
+//	get: func(key: string) -> result<option<list<u8>>, error>
+//
+//go:nosplit
+func (self Bucket) Get(key string) cm.OKResult[cm.Option[cm.List[uint8]], Error] {
+	var result cm.OKResult[cm.Option[cm.List[uint8]], Error]
+	self.wasmimport_Get(key, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [method]bucket.get
+//go:noescape
+func (self Bucket) wasmimport_Get(key string, result *cm.OKResult[cm.Option[cm.List[uint8]], Error])
+
+// ListKeys represents the imported method "list-keys".
+//
+// Get all the keys in the store with an optional cursor (for use in pagination).
+// It
+// returns a list of keys. Please note that for most KeyValue implementations, this
+// is a
+// can be a very expensive operation and so it should be used judiciously.
+//
+//	list-keys: func(cursor: option<u64>) -> result<key-response, error>
+//
+//go:nosplit
+func (self Bucket) ListKeys(cursor cm.Option[uint64]) cm.OKResult[KeyResponse, Error] {
+	var result cm.OKResult[KeyResponse, Error]
+	self.wasmimport_ListKeys(cursor, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [method]bucket.list-keys
+//go:noescape
+func (self Bucket) wasmimport_ListKeys(cursor cm.Option[uint64], result *cm.OKResult[KeyResponse, Error])
+
+// Set represents the imported method "set".
+//
+// Set the value associated with the key in the store. If the key already
+// exists in the store, it overwrites the value.
+//
+//	set: func(key: string, value: list<u8>) -> result<_, error>
+//
+//go:nosplit
+func (self Bucket) Set(key string, value cm.List[uint8]) cm.ErrResult[struct{}, Error] {
+	var result cm.ErrResult[struct{}, Error]
+	self.wasmimport_Set(key, value, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft [method]bucket.set
+//go:noescape
+func (self Bucket) wasmimport_Set(key string, value cm.List[uint8], result *cm.ErrResult[struct{}, Error])
+
+// Open represents the imported function "open".
+//
+// Get the bucket with the specified identifier.
+//
+// `identifier` must refer to a bucket provided by the host.
+//
+// `error::no-such-store` will be raised if the `identifier` is not recognized.
+//
+//	open: func(identifier: string) -> result<bucket, error>
+//
+//go:nosplit
+func Open(identifier string) cm.ErrResult[Bucket, Error] {
+	var result cm.ErrResult[Bucket, Error]
+	wasmimport_Open(identifier, &result)
+	return result
+}
+
+//go:wasmimport wasi:keyvalue/store@0.2.0-draft open
+//go:noescape
+func wasmimport_Open(identifier string, result *cm.ErrResult[Bucket, Error])
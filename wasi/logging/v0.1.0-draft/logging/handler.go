@@ -0,0 +1,139 @@
+//go:build !wasip1
+
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// HandlerOptions are options for a [Handler]. A zero HandlerOptions uses the defaults.
+type HandlerOptions struct {
+	// Level is the minimum level of records that are logged.
+	// Default: [slog.LevelInfo].
+	Level slog.Leveler
+
+	// Context is the context string passed to the host with each message,
+	// which hosts may use to group similar messages, e.g. the name of a component.
+	Context string
+
+	// AttrsInContext determines if the attributes of each record are appended to the
+	// context string instead of the message.
+	AttrsInContext bool
+}
+
+// Handler is a [slog.Handler] that forwards records to the host with [Log],
+// so structured logging in Go flows to the host.
+//
+// The level of each record is mapped to the nearest [Level] that does not exceed it,
+// e.g. [slog.LevelWarn] to [LevelWarn]. Levels below [slog.LevelDebug] are logged
+// as [LevelTrace], and levels of [slog.LevelError]+4 and above as [LevelCritical].
+//
+// Attributes are formatted as key=value pairs, as by [slog.TextHandler], and appended
+// to the message, or to the context string if HandlerOptions.AttrsInContext is set.
+// The time of each record is not logged, as the host is expected to record it.
+type Handler struct {
+	opts HandlerOptions
+	log  func(level Level, context string, message string)
+
+	mu   *sync.Mutex
+	buf  *bytes.Buffer
+	text slog.Handler // formats attrs into buf
+}
+
+// NewHandler returns a [Handler] that logs with opts, which may be nil.
+func NewHandler(opts *HandlerOptions) *Handler {
+	return newHandler(opts, Log)
+}
+
+func newHandler(opts *HandlerOptions, log func(Level, string, string)) *Handler {
+	h := &Handler{
+		log: log,
+		mu:  &sync.Mutex{},
+		buf: &bytes.Buffer{},
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.text = slog.NewTextHandler(h.buf, &slog.HandlerOptions{
+		Level: slog.Level(-1 << 31), // h.Enabled filters records
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return h
+}
+
+// Enabled reports whether h logs records at level.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle formats r and logs it with the host.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	h.buf.Reset()
+	err := h.text.Handle(ctx, r)
+	attrs := strings.TrimSpace(h.buf.String())
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	context, message := h.opts.Context, r.Message
+	if h.opts.AttrsInContext {
+		context = join(context, attrs)
+	} else {
+		message = join(message, attrs)
+	}
+	h.log(level(r.Level), context, message)
+	return nil
+}
+
+// WithAttrs returns a [Handler] that logs attrs with each record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.text = h.text.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup returns a [Handler] that qualifies the keys of subsequent attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.text = h.text.WithGroup(name)
+	return &h2
+}
+
+// level returns the [Level] for slog level l.
+func level(l slog.Level) Level {
+	switch {
+	case l < slog.LevelDebug:
+		return LevelTrace
+	case l < slog.LevelInfo:
+		return LevelDebug
+	case l < slog.LevelWarn:
+		return LevelInfo
+	case l < slog.LevelError:
+		return LevelWarn
+	case l < slog.LevelError+4:
+		return LevelError
+	}
+	return LevelCritical
+}
+
+func join(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + " " + b
+}
@@ -0,0 +1,68 @@
+//go:build !wasip1
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+type logEntry struct {
+	level   Level
+	context string
+	message string
+}
+
+func TestHandler(t *testing.T) {
+	var got []logEntry
+	log := func(level Level, context string, message string) {
+		got = append(got, logEntry{level, context, message})
+	}
+
+	logger := slog.New(newHandler(&HandlerOptions{Context: "app"}, log))
+	logger.Debug("not logged")
+	logger.Info("hello")
+	logger.With("user", "alice").WithGroup("req").Warn("slow request", "path", "/a b", "ms", 1500)
+
+	logger = slog.New(newHandler(&HandlerOptions{Level: slog.LevelDebug, AttrsInContext: true}, log))
+	logger.Debug("debug", "n", 1)
+	logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+	want := []logEntry{
+		{LevelInfo, "app", "hello"},
+		{LevelWarn, "app", `slow request user=alice req.path="/a b" req.ms=1500`},
+		{LevelDebug, "n=1", "debug"},
+		{LevelCritical, "", "fatal"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("logged %d entries, expected %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d: %+v, expected %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		l    slog.Level
+		want Level
+	}{
+		{slog.LevelDebug - 4, LevelTrace},
+		{slog.LevelDebug - 1, LevelTrace},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{slog.LevelError + 3, LevelError},
+		{slog.LevelError + 4, LevelCritical},
+	}
+	for _, tt := range tests {
+		if got := level(tt.l); got != tt.want {
+			t.Errorf("level(%v): %v, expected %v", tt.l, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:logging/imports@0.1.0-draft
+// Checksum: sha256:14a9d15e24e9af82bdfd3d0bedb8d511b3a914152061c57eb48dd2815678fdc8
+
+//go:build !wasip1
+
+// Package logging represents the imported interface "wasi:logging/logging@0.1.0-draft".
+//
+// WASI Logging is a logging API intended to let users emit log messages with
+// simple priority levels and context values.
+package logging
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Level represents the imported enum "wasi:logging/logging@0.1.0-draft#level".
+//
+// A log level, describing a kind of message.
+//
+//	enum level {
+//		trace,
+//		debug,
+//		info,
+//		warn,
+//		error,
+//		critical
+//	}
+type Level uint8
+
+const (
+	// Describes messages about the values of variables and the flow of
+	// control within a program.
+	LevelTrace Level = iota
+
+	// Describes messages likely to be of interest to someone debugging a
+	// program.
+	LevelDebug
+
+	// Describes messages likely to be of interest to someone monitoring a
+	// program.
+	LevelInfo
+
+	// Describes messages indicating hazardous situations.
+	LevelWarn
+
+	// Describes messages indicating serious errors.
+	LevelError
+
+	// Describes messages indicating fatal errors.
+	LevelCritical
+)
+
+var stringsLevel = [6]string{
+	"trace",
+	"debug",
+	"info",
+	"warn",
+	"error",
+	"critical",
+}
+
+// String implements [fmt.Stringer], returning the enum case name of e.
+func (e Level) String() string {
+	return cm.CaseString(stringsLevel[:], e)
+}
+
+// MarshalText implements [encoding.TextMarshaler], returning the enum case name of e.
+// Returns an error if e is not one of the enum cases.
+func (e Level) MarshalText() ([]byte, error) {
+	return cm.CaseMarshalText(stringsLevel[:], e)
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], unmarshaling into an enum
+// case. Returns an error if the supplied text is not one of the enum cases.
+func (e *Level) UnmarshalText(text []byte) error {
+	return _LevelUnmarshalCase(e, text)
+}
+
+var _LevelUnmarshalCase = cm.CaseUnmarshaler[Level](stringsLevel[:])
+
+// Log represents the imported function "log".
+//
+// Emit a log message.
+//
+// A log message has a `level` describing what kind of message is being
+// sent, a context, which is an uninterpreted string meant to help
+// consumers group similar messages, and a string containing the message
+// text.
+//
+//	log: func(level: level, context: string, message: string)
+//
+//go:nosplit
+func Log(level Level, context string, message string) {
+	wasmimport_Log(level, context, message)
+}
+
+//go:wasmimport wasi:logging/logging@0.1.0-draft log
+//go:noescape
+func wasmimport_Log(level Level, context string, message string)
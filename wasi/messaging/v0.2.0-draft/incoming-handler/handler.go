@@ -0,0 +1,55 @@
+//go:build !wasip1
+
+package incominghandler
+
+import (
+	"errors"
+
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/messaging/v0.2.0-draft/types"
+)
+
+// Handler handles a message delivered to the component by the host.
+// The message is dropped after HandleMessage returns, so it must not be retained.
+type Handler interface {
+	HandleMessage(msg types.Message) error
+}
+
+// HandlerFunc adapts an ordinary function to a [Handler].
+type HandlerFunc func(msg types.Message) error
+
+// HandleMessage calls f(msg).
+func (f HandlerFunc) HandleMessage(msg types.Message) error {
+	return f(msg)
+}
+
+// Register sets h as the handler for messages delivered to the component by assigning
+// [Handle]. It is typically called from an init function. The component receives messages
+// on the topics the host subscribes it to, so h should check the topic of each message.
+//
+// If h returns an error that is, or wraps, a [types.Error], it is returned to the host.
+// Other errors are returned to the host as [types.ErrorOther] with the error message.
+func Register(h Handler) {
+	Handle = func(msg types.Message) cm.ErrResult[struct{}, types.Error] {
+		defer msg.ResourceDrop()
+		if err := h.HandleMessage(msg); err != nil {
+			return cm.Err[cm.ErrResult[struct{}, types.Error]](toError(err))
+		}
+		return cm.ErrResult[struct{}, types.Error]{}
+	}
+}
+
+// RegisterFunc sets f as the handler for messages delivered to the component.
+// See [Register].
+func RegisterFunc(f func(msg types.Message) error) {
+	Register(HandlerFunc(f))
+}
+
+// toError converts err into a [types.Error] to return to the host.
+func toError(err error) types.Error {
+	var e types.Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return types.ErrorOther(err.Error())
+}
@@ -0,0 +1,30 @@
+//go:build !wasip1
+
+package incominghandler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ydnar/wasm-tools-go/wasi/messaging/v0.2.0-draft/types"
+)
+
+func TestToError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want types.Error
+	}{
+		{"types.Error", types.ErrorTimeout(), types.ErrorTimeout()},
+		{"wrapped", fmt.Errorf("send reply: %w", types.ErrorConnection("closed")), types.ErrorConnection("closed")},
+		{"other", errors.New("invalid payload"), types.ErrorOther("invalid payload")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toError(tt.err); got.Error() != tt.want.Error() {
+				t.Errorf("toError(%v): %v, expected %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:messaging/messaging-request-reply@0.2.0-draft
+// Checksum: sha256:b9971129012cba6872c238b46af2069bf1830fcff58d126125eb446462faadc5
+
+//go:build !wasip1
+
+// Package incominghandler represents the exported interface "wasi:messaging/incoming-handler@0.2.0-draft".
+//
+// The interface for handling incoming messages
+package incominghandler
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/messaging/v0.2.0-draft/types"
+)
+
+// Handle represents the caller-defined, exported function "handle".
+//
+// Whenever this guest receives a message in one of the subscribed topics, the message
+// is
+// sent to this handler. The guest is responsible for matching on the topic and handling
+// the
+// message accordingly. Implementors (such as hosts) calling this interface should
+// make their
+// own decisions on how to handle errors returned from this function.
+//
+//	handle: func(message: message) -> result<_, error>
+var Handle = func(message types.Message) cm.ErrResult[struct{}, types.Error] {
+	panic("unimplemented export: wasi:messaging/incoming-handler@0.2.0-draft#handle")
+}
+
+//go:wasmexport wasi:messaging/incoming-handler@0.2.0-draft#handle
+//export wasi:messaging/incoming-handler@0.2.0-draft#handle
+func wasmexport_Handle(message types.Message) *cm.ErrResult[struct{}, types.Error] {
+	result := Handle(message)
+	return &result
+}
+
+// HandlePostReturn represents the caller-defined, exported function "cabi_post_handle".
+//
+// Post-return cleanup function.
+var HandlePostReturn = func(result cm.ErrResult[struct{}, types.Error]) {}
+
+//go:wasmexport wasi:messaging/incoming-handler@0.2.0-draft#cabi_post_handle
+//export wasi:messaging/incoming-handler@0.2.0-draft#cabi_post_handle
+func wasmexport_HandlePostReturn(result cm.ErrResult[struct{}, types.Error]) {
+	HandlePostReturn(result)
+}
@@ -0,0 +1,32 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:messaging/messaging-request-reply@0.2.0-draft
+// Checksum: sha256:455b32a3bfbee3631c93bc1b3b5342bdfd26950d3ea92408ed0cc303f8394fc8
+
+//go:build !wasip1
+
+// Package producer represents the imported interface "wasi:messaging/producer@0.2.0-draft".
+//
+// The producer interface is used to send messages to a channel/topic.
+package producer
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+	"github.com/ydnar/wasm-tools-go/wasi/messaging/v0.2.0-draft/types"
+)
+
+// Send represents the imported function "send".
+//
+// Sends the message using the given client.
+//
+//	send: func(c: borrow<client>, topic: topic, message: message) -> result<_, error>
+//
+//go:nosplit
+func Send(c types.Client, topic types.Topic, message types.Message) cm.ErrResult[struct{}, types.Error] {
+	var result cm.ErrResult[struct{}, types.Error]
+	wasmimport_Send(c, topic, message, &result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/producer@0.2.0-draft send
+//go:noescape
+func wasmimport_Send(c types.Client, topic types.Topic, message types.Message, result *cm.ErrResult[struct{}, types.Error])
@@ -0,0 +1,20 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:messaging/messaging-request-reply@0.2.0-draft
+// Checksum: sha256:e623acc807d0e5c390c837257053719e0ae4ac989231df81e98ef044717b5ed2
+
+//go:build tinygo.wasm
+
+package types
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(Metadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Metadata{}) - 4]struct{}{}
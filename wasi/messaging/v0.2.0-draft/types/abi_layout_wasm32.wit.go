@@ -0,0 +1,21 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:messaging/messaging-request-reply@0.2.0-draft
+// Checksum: sha256:e0ad16db46d6553f698dfd1e5c16cf7f9566ec4fc3327c31fb97f5e1e66147c3
+
+//go:build !wasip1 && tinygo.wasm
+
+package types
+
+import (
+	"unsafe"
+)
+
+// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+var _ [0]struct{} = [unsafe.Sizeof(Error{}) - 12]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Error{}) - 4]struct{}{}
+var _ [0]struct{} = [unsafe.Sizeof(Metadata{}) - 8]struct{}{}
+var _ [0]struct{} = [unsafe.Alignof(Metadata{}) - 4]struct{}{}
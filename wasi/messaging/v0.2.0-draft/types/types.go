@@ -0,0 +1,17 @@
+//go:build !wasip1
+
+package types
+
+// Error implements the error interface.
+func (v Error) Error() string {
+	if s := v.Connection(); s != nil {
+		return "wasi:messaging/types: connection: " + *s
+	}
+	if s := v.PermissionDenied(); s != nil {
+		return "wasi:messaging/types: permission-denied: " + *s
+	}
+	if s := v.Other(); s != nil {
+		return "wasi:messaging/types: " + *s
+	}
+	return "wasi:messaging/types: " + v.String()
+}
@@ -0,0 +1,338 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+// Source: WIT world wasi:messaging/messaging-request-reply@0.2.0-draft
+// Checksum: sha256:8d9c4f2d0ad93b2a6e135cbb30bbf18d29573f5a525ee22a6679e747047a201b
+
+//go:build !wasip1
+
+// Package types represents the imported interface "wasi:messaging/types@0.2.0-draft".
+package types
+
+import (
+	"github.com/ydnar/wasm-tools-go/cm"
+)
+
+// Client represents the imported resource "wasi:messaging/types@0.2.0-draft#client".
+//
+// A connection to a message-exchange service (e.g., buffer, broker, etc.).
+//
+//	resource client
+type Client cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "client".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self Client) ResourceDrop() {
+	self.wasmimport_ResourceDrop()
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [resource-drop]client
+//go:noescape
+func (self Client) wasmimport_ResourceDrop()
+
+// ClientConnect represents the imported static function "connect".
+//
+//	connect: static func(name: string) -> result<client, error>
+//
+//go:nosplit
+func ClientConnect(name string) cm.ErrResult[Client, Error] {
+	var result cm.ErrResult[Client, Error]
+	wasmimport_ClientConnect(name, &result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [static]client.connect
+//go:noescape
+func wasmimport_ClientConnect(name string, result *cm.ErrResult[Client, Error])
+
+// Disconnect represents the imported method "disconnect".
+//
+//	disconnect: func() -> result<_, error>
+//
+//go:nosplit
+func (self Client) Disconnect() cm.ErrResult[struct{}, Error] {
+	var result cm.ErrResult[struct{}, Error]
+	self.wasmimport_Disconnect(&result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]client.disconnect
+//go:noescape
+func (self Client) wasmimport_Disconnect(result *cm.ErrResult[struct{}, Error])
+
+// Error represents the imported variant "wasi:messaging/types@0.2.0-draft#error".
+//
+// Errors that can occur when using the messaging interface.
+//
+//	variant error {
+//		timeout,
+//		connection(string),
+//		permission-denied(string),
+//		other(string),
+//	}
+type Error cm.Variant[uint8, string, string]
+
+// ErrorTimeout returns a [Error] of case "timeout".
+//
+// The request or operation timed out.
+func ErrorTimeout() Error {
+	var data struct{}
+	return cm.New[Error](0, data)
+}
+
+// Timeout returns true if [Error] represents the variant case "timeout".
+func (self *Error) Timeout() bool {
+	return cm.Tag(self) == 0
+}
+
+// ErrorConnection returns a [Error] of case "connection".
+//
+// An error occurred with the connection. Includes a message for additional context
+func ErrorConnection(data string) Error {
+	return cm.New[Error](1, data)
+}
+
+// Connection returns a non-nil *[string] if [Error] represents the variant case "connection".
+func (self *Error) Connection() *string {
+	return cm.Case[string](self, 1)
+}
+
+// ErrorPermissionDenied returns a [Error] of case "permission-denied".
+//
+// A permission error occurred. Includes a message for additional context
+func ErrorPermissionDenied(data string) Error {
+	return cm.New[Error](2, data)
+}
+
+// PermissionDenied returns a non-nil *[string] if [Error] represents the variant case "permission-denied".
+func (self *Error) PermissionDenied() *string {
+	return cm.Case[string](self, 2)
+}
+
+// ErrorOther returns a [Error] of case "other".
+//
+// A catch all for other types of errors
+func ErrorOther(data string) Error {
+	return cm.New[Error](3, data)
+}
+
+// Other returns a non-nil *[string] if [Error] represents the variant case "other".
+func (self *Error) Other() *string {
+	return cm.Case[string](self, 3)
+}
+
+var stringsError = [4]string{
+	"timeout",
+	"connection",
+	"permission-denied",
+	"other",
+}
+
+// String implements [fmt.Stringer], returning the variant case name of v.
+func (v Error) String() string {
+	return cm.CaseString(stringsError[:], cm.Tag(&v))
+}
+
+// Topic represents the imported type "wasi:messaging/types@0.2.0-draft#topic".
+//
+// There are two types of channels:
+// - publish-subscribe channel, which is a broadcast channel, and
+// - point-to-point channel, which is a unicast channel.
+//
+// The interface doesn't highlight this difference in the type itself as that's uniquely
+// a consumer issue.
+//
+//	type topic = string
+type Topic string
+
+// Metadata represents the imported list "wasi:messaging/types@0.2.0-draft#metadata".
+//
+// Metadata (also called headers or attributes) attached to a message.
+//
+//	type metadata = list<tuple<string, string>>
+type Metadata cm.List[[2]string]
+
+// Message represents the imported resource "wasi:messaging/types@0.2.0-draft#message".
+//
+// A message with a binary payload and additional information
+//
+//	resource message
+type Message cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "message".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self Message) ResourceDrop() {
+	self.wasmimport_ResourceDrop()
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [resource-drop]message
+//go:noescape
+func (self Message) wasmimport_ResourceDrop()
+
+// NewMessage represents the imported constructor for resource "message".
+//
+//	constructor(data: list<u8>)
+//
+//go:nosplit
+func NewMessage(data cm.List[uint8]) Message {
+	return wasmimport_NewMessage(data)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [constructor]message
+//go:noescape
+func wasmimport_NewMessage(data cm.List[uint8]) Message
+
+// AddMetadata represents the imported method "add-metadata".
+//
+// Add a new key-value pair to the metadata, overwriting any existing value for the
+// same key
+//
+//	add-metadata: func(key: string, value: string)
+//
+//go:nosplit
+func (self Message) AddMetadata(key string, value string) {
+	self.wasmimport_AddMetadata(key, value)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.add-metadata
+//go:noescape
+func (self Message) wasmimport_AddMetadata(key string, value string)
+
+// ContentType represents the imported method "content-type".
+//
+// An optional content-type describing the format of the data in the message. This
+// is
+// sometimes described as the "format" type
+//
+//	content-type: func() -> option<string>
+//
+//go:nosplit
+func (self Message) ContentType() cm.Option[string] {
+	var result cm.Option[string]
+	self.wasmimport_ContentType(&result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.content-type
+//go:noescape
+func (self Message) wasmimport_ContentType(result *cm.Option[string])
+
+// Data represents the imported method "data".
+//
+// An opaque blob of data
+//
+//	data: func() -> list<u8>
+//
+//go:nosplit
+func (self Message) Data() cm.List[uint8] {
+	var result cm.List[uint8]
+	self.wasmimport_Data(&result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.data
+//go:noescape
+func (self Message) wasmimport_Data(result *cm.List[uint8])
+
+// Metadata represents the imported method "metadata".
+//
+// Optional metadata (also called headers or attributes in some systems) attached
+// to the
+// message. This metadata is simply decoration and should not be interpreted by a
+// host
+// to ensure portability across different implementors (e.g., Kafka -> NATS, etc.).
+//
+//	metadata: func() -> option<metadata>
+//
+//go:nosplit
+func (self Message) Metadata() cm.Option[Metadata] {
+	var result cm.Option[Metadata]
+	self.wasmimport_Metadata(&result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.metadata
+//go:noescape
+func (self Message) wasmimport_Metadata(result *cm.Option[Metadata])
+
+// RemoveMetadata represents the imported method "remove-metadata".
+//
+// Remove a key-value pair from the metadata
+//
+//	remove-metadata: func(key: string)
+//
+//go:nosplit
+func (self Message) RemoveMetadata(key string) {
+	self.wasmimport_RemoveMetadata(key)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.remove-metadata
+//go:noescape
+func (self Message) wasmimport_RemoveMetadata(key string)
+
+// SetContentType represents the imported method "set-content-type".
+//
+// Set the content-type describing the format of the data in the message. This is
+// sometimes described as the "format" type
+//
+//	set-content-type: func(content-type: string)
+//
+//go:nosplit
+func (self Message) SetContentType(contentType string) {
+	self.wasmimport_SetContentType(contentType)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.set-content-type
+//go:noescape
+func (self Message) wasmimport_SetContentType(contentType string)
+
+// SetData represents the imported method "set-data".
+//
+// Set the opaque blob of data for this message, discarding the old value
+//
+//	set-data: func(data: list<u8>)
+//
+//go:nosplit
+func (self Message) SetData(data cm.List[uint8]) {
+	self.wasmimport_SetData(data)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.set-data
+//go:noescape
+func (self Message) wasmimport_SetData(data cm.List[uint8])
+
+// SetMetadata represents the imported method "set-metadata".
+//
+// Set the metadata
+//
+//	set-metadata: func(meta: metadata)
+//
+//go:nosplit
+func (self Message) SetMetadata(meta Metadata) {
+	self.wasmimport_SetMetadata(meta)
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.set-metadata
+//go:noescape
+func (self Message) wasmimport_SetMetadata(meta Metadata)
+
+// Topic represents the imported method "topic".
+//
+// The topic/subject/channel this message was received on, if any
+//
+//	topic: func() -> option<topic>
+//
+//go:nosplit
+func (self Message) Topic() cm.Option[Topic] {
+	var result cm.Option[Topic]
+	self.wasmimport_Topic(&result)
+	return result
+}
+
+//go:wasmimport wasi:messaging/types@0.2.0-draft [method]message.topic
+//go:noescape
+func (self Message) wasmimport_Topic(result *cm.Option[Topic])
@@ -0,0 +1,23 @@
+//go:build !wasip1
+
+package types
+
+import "testing"
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		err  Error
+		want string
+	}{
+		{ErrorTimeout(), "wasi:messaging/types: timeout"},
+		{ErrorConnection("broker unavailable"), "wasi:messaging/types: connection: broker unavailable"},
+		{ErrorPermissionDenied("topic a.b"), "wasi:messaging/types: permission-denied: topic a.b"},
+		{ErrorOther("queue full"), "wasi:messaging/types: queue full"},
+	}
+	for _, tt := range tests {
+		var err error = tt.err
+		if got := err.Error(); got != tt.want {
+			t.Errorf("Error(): %q, expected %q", got, tt.want)
+		}
+	}
+}
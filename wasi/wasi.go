@@ -0,0 +1,22 @@
+// Package wasi contains Go bindings for [WASI] interfaces, generated by wit-bindgen-go,
+// along with helpers that adapt them to idiomatic Go types.
+//
+// Bindings for each WASI version are generated into packages with a version-suffixed
+// import path, e.g. wasi/clocks/v0.2.0/wall-clock, so multiple versions can be used
+// in the same program. Each version is generated from the WIT JSON in testdata/wasi/<version>.
+// Draft proposals are generated from testdata/wasi/0.2.0-draft and testdata/wasi/0.1.0-draft.
+// To add a version, add its WIT JSON, a go:generate line for it below,
+// and the version to [version.WASI].
+//
+// [WASI]: https://wasi.dev
+// [version.WASI]: https://pkg.go.dev/github.com/ydnar/wasm-tools-go/internal/version#WASI
+package wasi
+
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:blobstore/imports -o .. ../testdata/wasi/0.2.0-draft/blobstore.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -o .. ../testdata/wasi/0.2.0/clocks-timezone.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:config/imports -o .. ../testdata/wasi/0.2.0-draft/config.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:http/proxy -o .. ../testdata/wasi/0.2.0/http.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:http/proxy -o .. ../testdata/wasi/0.2.8/http-minimal.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:keyvalue/imports -o .. ../testdata/wasi/0.2.0-draft/keyvalue.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:logging/imports -o .. ../testdata/wasi/0.1.0-draft/logging.wit.json
+//go:generate go run ../cmd/wit-bindgen-go generate --versioned -w wasi:messaging/messaging-request-reply -o .. ../testdata/wasi/0.2.0-draft/messaging.wit.json
@@ -0,0 +1,39 @@
+package wasi_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	streams020 "github.com/ydnar/wasm-tools-go/wasi/io/v0.2.0/streams"
+	streams028 "github.com/ydnar/wasm-tools-go/wasi/io/v0.2.8/streams"
+)
+
+// Bindings for two WASI versions can be used in the same program.
+var (
+	_ streams020.InputStream
+	_ streams028.InputStream
+)
+
+// TestVersions verifies that the bindings for each WASI version import the functions
+// of that version.
+func TestVersions(t *testing.T) {
+	tests := []struct {
+		src        string
+		wasmimport string
+	}{
+		{"io/v0.2.0/streams/streams.wit.go", "//go:wasmimport wasi:io/streams@0.2.0 [method]input-stream.read\n"},
+		{"io/v0.2.8/streams/streams.wit.go", "//go:wasmimport wasi:io/streams@0.2.8 [method]input-stream.read\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			src, err := os.ReadFile(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(src), tt.wasmimport) {
+				t.Errorf("%s does not contain %q", tt.src, tt.wasmimport)
+			}
+		})
+	}
+}
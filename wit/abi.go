@@ -3,6 +3,7 @@ package wit
 import (
 	"slices"
 	"strconv"
+	"strings"
 )
 
 // Align aligns ptr with alignment align.
@@ -285,6 +286,158 @@ func (f *Function) CoreFunction(op Direction) *Function {
 	return &cf
 }
 
+// CoreType represents a [Core WebAssembly value type] used in the flattened
+// Canonical ABI representation of a function: i32, i64, f32, or f64.
+//
+// [Core WebAssembly value type]: https://webassembly.github.io/spec/core/syntax/types.html#number-types
+type CoreType uint8
+
+const (
+	CoreI32 CoreType = iota + 1
+	CoreI64
+	CoreF32
+	CoreF64
+)
+
+// String returns the Core WebAssembly name for t, e.g. "i32".
+func (t CoreType) String() string {
+	switch t {
+	case CoreI32:
+		return "i32"
+	case CoreI64:
+		return "i64"
+	case CoreF32:
+		return "f32"
+	case CoreF64:
+		return "f64"
+	}
+	return "CoreType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// CoreSignature represents the [flattened] Core WebAssembly params and results of a [Function].
+//
+// [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
+type CoreSignature struct {
+	Params  []CoreType
+	Results []CoreType
+}
+
+// String returns the signature in WebAssembly text format, e.g. "(param i32 i32) (result i32)".
+func (sig CoreSignature) String() string {
+	var b strings.Builder
+	write := func(kind string, types []CoreType) {
+		if len(types) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteString("(" + kind)
+		for _, t := range types {
+			b.WriteString(" " + t.String())
+		}
+		b.WriteRune(')')
+	}
+	write("param", sig.Params)
+	write("result", sig.Results)
+	return b.String()
+}
+
+// CoreSignature returns the [flattened] Core WebAssembly signature of [Function] f
+// when imported (lowered) or exported (lifted), as in go:wasmimport or go:wasmexport.
+//
+// If f has more than [MaxFlatParams] flattened params, they are passed indirectly
+// via a single i32 pointer. If f has more than [MaxFlatResults] flattened results,
+// an imported function receives an additional i32 return pointer param, and an
+// exported function returns an i32 pointer.
+//
+// [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
+func (f *Function) CoreSignature(dir Direction) CoreSignature {
+	var sig CoreSignature
+	for _, p := range f.Params {
+		sig.Params = append(sig.Params, coreFlat(p.Type)...)
+	}
+	if len(sig.Params) > MaxFlatParams {
+		sig.Params = []CoreType{CoreI32}
+	}
+	for _, r := range f.Results {
+		sig.Results = append(sig.Results, coreFlat(r.Type)...)
+	}
+	if len(sig.Results) > MaxFlatResults {
+		if dir == Exported {
+			sig.Results = []CoreType{CoreI32}
+		} else {
+			sig.Params = append(sig.Params, CoreI32)
+			sig.Results = nil
+		}
+	}
+	return sig
+}
+
+// coreFlat returns the flattened Core WebAssembly types for t.
+// Unlike [ABI.Flat], variant payloads are joined according to the Canonical ABI.
+func coreFlat(t Type) []CoreType {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return coreTypes(t.Flat())
+	}
+	switch k := Despecialize(td.Kind).(type) {
+	case Type:
+		return coreFlat(k)
+	case *Record:
+		var flat []CoreType
+		for _, f := range k.Fields {
+			flat = append(flat, coreFlat(f.Type)...)
+		}
+		return flat
+	case *Variant:
+		var flat []CoreType
+		for _, c := range k.Cases {
+			if c.Type == nil {
+				continue
+			}
+			for i, ct := range coreFlat(c.Type) {
+				if i < len(flat) {
+					flat[i] = coreJoin(flat[i], ct)
+				} else {
+					flat = append(flat, ct)
+				}
+			}
+		}
+		return append(coreFlat(Discriminant(len(k.Cases))), flat...)
+	default:
+		return coreTypes(k.Flat())
+	}
+}
+
+// coreJoin returns the Core WebAssembly type that can hold values of both a and b.
+func coreJoin(a, b CoreType) CoreType {
+	switch {
+	case a == b:
+		return a
+	case (a == CoreI32 && b == CoreF32) || (a == CoreF32 && b == CoreI32):
+		return CoreI32
+	}
+	return CoreI64
+}
+
+func coreTypes(flat []Type) []CoreType {
+	types := make([]CoreType, len(flat))
+	for i, t := range flat {
+		switch t.(type) {
+		case F32:
+			types[i] = CoreF32
+		case F64:
+			types[i] = CoreF64
+		case S64, U64:
+			types[i] = CoreI64
+		default:
+			types[i] = CoreI32
+		}
+	}
+	return types
+}
+
 func flatParams(params []Param) []Type {
 	flat := make([]Type, 0, len(params))
 	for _, p := range params {
@@ -3,6 +3,7 @@ package wit
 import (
 	"fmt"
 	"math"
+	"reflect"
 	"testing"
 )
 
@@ -99,6 +100,8 @@ func TestDiscriminant(t *testing.T) {
 }
 
 func TestTypeSize(t *testing.T) {
+	listU8 := &TypeDef{Kind: &List{Type: U8{}}}
+	recordU64U8 := &TypeDef{Kind: &Record{Fields: []Field{{Type: U64{}}, {Type: U8{}}}}}
 	tests := []struct {
 		name  string
 		v     Type
@@ -118,6 +121,17 @@ func TestTypeSize(t *testing.T) {
 		{"f64", F64{}, 8, 8},
 		{"char", Char{}, 4, 4},
 		{"string", String{}, 8, 4},
+		{"list<u8>", &TypeDef{Kind: &List{Type: U8{}}}, 8, 4},
+		{"record{u64, u32}", &TypeDef{Kind: &Record{Fields: []Field{{Type: U64{}}, {Type: U32{}}}}}, 16, 8},
+		{"record{u32, u8}", &TypeDef{Kind: &Record{Fields: []Field{{Type: U32{}}, {Type: U8{}}}}}, 8, 4},
+		{"tuple<u16, u8>", &TypeDef{Kind: &Tuple{Types: []Type{U16{}, U8{}}}}, 4, 2},
+		{"record{u8, list<u8>}", &TypeDef{Kind: &Record{Fields: []Field{{Type: U8{}}, {Type: listU8}}}}, 12, 4},
+		{"record{record{u64, u8}, u8}", &TypeDef{Kind: &Record{Fields: []Field{{Type: recordU64U8}, {Type: U8{}}}}}, 24, 8},
+		{"tuple<list<u8>, u8>", &TypeDef{Kind: &Tuple{Types: []Type{listU8, U8{}}}}, 12, 4},
+		{"list<record{u64, u8}>", &TypeDef{Kind: &List{Type: recordU64U8}}, 8, 4},
+		{"option<record{u64, u8}>", &TypeDef{Kind: &Option{Type: recordU64U8}}, 24, 8},
+		{"option<list<u8>>", &TypeDef{Kind: &Option{Type: listU8}}, 12, 4},
+		{"variant{a(record{u64, u8}), b}", &TypeDef{Kind: &Variant{Cases: []Case{{Name: "a", Type: recordU64U8}, {Name: "b"}}}}, 24, 8},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -132,3 +146,74 @@ func TestTypeSize(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionCoreSignature(t *testing.T) {
+	record := func(types ...Type) *TypeDef {
+		r := &Record{}
+		for i, t := range types {
+			r.Fields = append(r.Fields, Field{Name: fmt.Sprintf("f%d", i), Type: t})
+		}
+		return &TypeDef{Kind: r}
+	}
+	variant := func(types ...Type) *TypeDef {
+		v := &Variant{}
+		for i, t := range types {
+			v.Cases = append(v.Cases, Case{Name: fmt.Sprintf("c%d", i), Type: t})
+		}
+		return &TypeDef{Kind: v}
+	}
+	params := func(types ...Type) []Param {
+		var params []Param
+		for i, t := range types {
+			params = append(params, Param{Name: fmt.Sprintf("p%d", i), Type: t})
+		}
+		return params
+	}
+	many := make([]Type, 17)
+	for i := range many {
+		many[i] = U8{}
+	}
+
+	tests := []struct {
+		name     string
+		params   []Param
+		results  []Param
+		imported string
+		exported string
+	}{
+		{"empty", nil, nil, "", ""},
+		{"scalars", params(Bool{}, S64{}, F32{}, F64{}), params(U16{}), "(param i32 i64 f32 f64) (result i32)", "(param i32 i64 f32 f64) (result i32)"},
+		{"string", params(String{}), params(String{}), "(param i32 i32 i32)", "(param i32 i32) (result i32)"},
+		{"list", params(&TypeDef{Kind: &List{Type: U64{}}}), nil, "(param i32 i32)", "(param i32 i32)"},
+		{"option<f32>", params(&TypeDef{Kind: &Option{Type: F32{}}}), nil, "(param i32 f32)", "(param i32 f32)"},
+		{"result<u32, f32>", nil, params(&TypeDef{Kind: &Result{OK: U32{}, Err: F32{}}}), "(param i32)", "(result i32)"},
+		{"variant join i32 f32", params(variant(U32{}, F32{})), nil, "(param i32 i32)", "(param i32 i32)"},
+		{"variant join f32 f64", params(variant(F32{}, F64{})), nil, "(param i32 i64)", "(param i32 i64)"},
+		{"variant join u8 f64", params(variant(nil, U8{}, F64{})), nil, "(param i32 i64)", "(param i32 i64)"},
+		{"variant join record", params(variant(record(F32{}, U8{}), record(F64{}))), nil, "(param i32 i64 i32)", "(param i32 i64 i32)"},
+		{"flags", params(&TypeDef{Kind: &Flags{Flags: make([]Flag, 33)}}), nil, "(param i32 i32)", "(param i32 i32)"},
+		{"16 params", params(many[:16]...), nil, "(param i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32)", "(param i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32)"},
+		{"17 params", params(many...), nil, "(param i32)", "(param i32)"},
+		{"17 params, string result", params(many...), params(String{}), "(param i32 i32)", "(param i32) (result i32)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Function{Name: "f", Kind: &Freestanding{}, Params: tt.params, Results: tt.results}
+			if got := f.CoreSignature(Imported).String(); got != tt.imported {
+				t.Errorf("CoreSignature(Imported): %s, expected %s", got, tt.imported)
+			}
+			if got := f.CoreSignature(Exported).String(); got != tt.exported {
+				t.Errorf("CoreSignature(Exported): %s, expected %s", got, tt.exported)
+			}
+		})
+	}
+}
+
+func TestVariantFlat(t *testing.T) {
+	v := &Variant{Cases: []Case{{Name: "a", Type: U8{}}, {Name: "b", Type: String{}}}}
+	got := v.Flat()
+	want := []Type{U32{}, U32{}, U32{}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Flat(): %v, expected %v", got, want)
+	}
+}
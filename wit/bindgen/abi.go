@@ -8,7 +8,7 @@ import (
 
 // variantShape returns the largest associated type in v.
 // If v has multiple types with the same size, it returns the
-// type that contains a pointer.
+// type that contains a pointer, then a type without padding.
 func variantShape(v *wit.Variant) wit.Type {
 	types := v.Types()
 	if len(types) == 0 {
@@ -24,6 +24,10 @@ func variantShape(v *wit.Variant) wit.Type {
 			return -1
 		case !wit.HasPointer(a) && wit.HasPointer(b):
 			return 1
+		case !hasPadding(a) && hasPadding(b):
+			return -1
+		case hasPadding(a) && !hasPadding(b):
+			return 1
 		default:
 			return 0
 		}
@@ -31,6 +35,93 @@ func variantShape(v *wit.Variant) wit.Type {
 	return types[0]
 }
 
+// variantShapeArray returns the size and element type of an array to use as the shape
+// of v instead of [variantShape], if the shape has padding and v has no pointers, otherwise 0, nil.
+// A copy of a struct may skip its padding, which would lose the bytes of another case
+// stored there. The array has the same size and alignment as the shape, and no padding.
+func variantShapeArray(v *wit.Variant) (n uintptr, elem wit.Type) {
+	shape := variantShape(v)
+	if shape == nil || wit.HasPointer(v) || !hasPadding(shape) {
+		return 0, nil
+	}
+	switch shape.Align() {
+	case 1:
+		elem = wit.U8{}
+	case 2:
+		elem = wit.U16{}
+	case 4:
+		elem = wit.U32{}
+	default:
+		elem = wit.U64{}
+	}
+	return shape.Size() / elem.Size(), elem
+}
+
+// hasPadding reports whether the Go representation of t has padding bytes,
+// which a copy of t may not preserve.
+func hasPadding(t wit.TypeDefKind) bool {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		return hasPadding(t.Kind)
+	case *wit.Record:
+		types := make([]wit.Type, len(t.Fields))
+		for i, f := range t.Fields {
+			types[i] = f.Type
+		}
+		return structPadding(types...)
+	case *wit.Tuple:
+		return structPadding(t.Types...)
+	case *wit.Option:
+		return structPadding(wit.Bool{}, t.Type)
+	case *wit.Result:
+		shape, _ := resultShape(t)
+		switch {
+		case t.OK == nil:
+			shape = t.Err
+		case t.Err == nil:
+			shape = t.OK
+		}
+		return dataPadding(t, wit.Bool{}, shape, shape != nil && hasPadding(shape))
+	case *wit.Variant:
+		if t.Enum() != nil {
+			return false
+		}
+		shape := variantShape(t)
+		n, _ := variantShapeArray(t)
+		return dataPadding(t, wit.Discriminant(len(t.Cases)), shape, n == 0 && hasPadding(shape))
+	}
+	return false
+}
+
+// structPadding reports whether a struct with fields of types has padding.
+func structPadding(types ...wit.Type) bool {
+	var size, align uintptr = 0, 1
+	for _, t := range types {
+		if t == nil {
+			continue
+		}
+		if hasPadding(t) {
+			return true
+		}
+		if wit.Align(size, t.Align()) != size {
+			return true
+		}
+		size += t.Size()
+		align = max(align, t.Align())
+	}
+	return wit.Align(size, align) != size
+}
+
+// dataPadding reports whether variant or result t, with a tag of type tag followed by
+// data of type shape, has padding between or after them, or if shapePadding is true.
+func dataPadding(t wit.TypeDefKind, tag, shape wit.Type, shapePadding bool) bool {
+	var size uintptr
+	if shape != nil {
+		size = shape.Size()
+	}
+	return shapePadding || tag.Size()+size != t.Size()
+}
+
 // variantAlign returns the type with the highest align value in v.
 func variantAlign(v *wit.Variant) wit.Type {
 	types := v.Types()
@@ -49,3 +140,113 @@ func variantAlign(v *wit.Variant) wit.Type {
 	})
 	return types[0]
 }
+
+// variantShape64 returns the associated type in v to use as its shape on 64-bit
+// architectures, if it is larger than the shape returned by [variantShape],
+// otherwise nil. Types that contain pointers are larger with 64-bit pointers.
+func variantShape64(v *wit.Variant) wit.Type {
+	return archShape(variantShape(v), v.Types()...)
+}
+
+// resultShape returns the type that r is sized to hold on 32-bit architectures,
+// and if it differs on 64-bit architectures, the type it is sized to hold on 64-bit
+// architectures. It returns nil, nil if r does not have both an OK and Err type.
+func resultShape(r *wit.Result) (shape32, shape64 wit.Type) {
+	if r.OK == nil || r.Err == nil {
+		return nil, nil
+	}
+	shape32 = r.OK
+	if r.Err.Size() > r.OK.Size() {
+		shape32 = r.Err
+	}
+	return shape32, archShape(shape32, r.OK, r.Err)
+}
+
+// archShape returns the largest of types with 64-bit pointers,
+// if it is larger than shape, otherwise nil.
+func archShape(shape wit.Type, types ...wit.Type) wit.Type {
+	var shape64 wit.Type
+	size, _ := layout64(shape)
+	for _, t := range types {
+		if s, _ := layout64(t); s > size {
+			shape64, size = t, s
+		}
+	}
+	return shape64
+}
+
+// layout64 returns the size and alignment of the Go representation of t with 64-bit pointers.
+// Types without pointers have the same layout as their Canonical ABI representation.
+func layout64(t wit.TypeDefKind) (size, align uintptr) {
+	if t == nil || !wit.HasPointer(t) {
+		return sizeAlign(t)
+	}
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		return layout64(t.Kind)
+	case wit.String, *wit.List:
+		return 16, 8
+	case *wit.Record:
+		types := make([]wit.Type, len(t.Fields))
+		for i, f := range t.Fields {
+			types[i] = f.Type
+		}
+		return structLayout64(types...)
+	case *wit.Tuple:
+		return structLayout64(t.Types...)
+	case *wit.Option:
+		return structLayout64(wit.Bool{}, t.Type)
+	case *wit.Result:
+		shape, shape64 := resultShape(t)
+		switch {
+		case shape64 != nil:
+			shape = shape64
+		case t.OK == nil:
+			shape = t.Err
+		case t.Err == nil:
+			shape = t.OK
+		}
+		_, okAlign := layout64(t.OK)
+		_, errAlign := layout64(t.Err)
+		s, a := layout64(shape)
+		return dataLayout64(1, s, max(okAlign, errAlign, a))
+	case *wit.Variant:
+		shape := variantShape(t)
+		if shape64 := variantShape64(t); shape64 != nil {
+			shape = shape64
+		}
+		_, a := layout64(variantAlign(t))
+		s, shapeAlign := layout64(shape)
+		return dataLayout64(wit.Discriminant(len(t.Cases)).Size(), s, max(a, shapeAlign))
+	}
+	return sizeAlign(t)
+}
+
+// structLayout64 returns the size and alignment of a Go struct with fields of types
+// with 64-bit pointers. Nil types are skipped.
+func structLayout64(types ...wit.Type) (size, align uintptr) {
+	align = 1
+	for _, t := range types {
+		if t == nil {
+			continue
+		}
+		s, a := layout64(t)
+		size = wit.Align(size, a) + s
+		align = max(align, a)
+	}
+	return wit.Align(size, align), align
+}
+
+// dataLayout64 returns the size and alignment of a variant or result with a tag
+// of size tagSize, followed by data of size dataSize, aligned to align.
+func dataLayout64(tagSize, dataSize, align uintptr) (size, _ uintptr) {
+	return wit.Align(wit.Align(tagSize, align)+dataSize, align), align
+}
+
+// sizeAlign returns the size and alignment of t, or 0, 1 if t is nil.
+func sizeAlign(t wit.TypeDefKind) (size, align uintptr) {
+	if t == nil {
+		return 0, 1
+	}
+	return t.Size(), t.Align()
+}
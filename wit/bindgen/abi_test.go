@@ -0,0 +1,71 @@
+package bindgen
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+func TestVariantShapeArray(t *testing.T) {
+	res, err := wit.LoadFS(fstest.MapFS{"shapes.wit": {Data: []byte(`package foo:shapes;
+
+interface shapes {
+	variant no-padding { a(u8), b(u64) }
+	variant padded-tuple { a(u8), b(tuple<u8, u64>), c(u64) }
+	variant padded-record { a(u16), b(pair) }
+	record pair { x: u8, y: u16 }
+	variant padded-option { a(option<u32>), b(u8) }
+	variant pointer { a(tuple<u8, string>), b(u32) }
+	variant prefer-unpadded { a(tuple<u8, u16>), b(u32) }
+}
+
+world w {
+	import shapes;
+}
+`)}}, "shapes.wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		n     uintptr
+		elem  wit.Type
+		shape string
+	}{
+		{"no-padding", 0, nil, "u64"},
+		{"padded-tuple", 2, wit.U64{}, "tuple<u8, u64>"},
+		{"padded-record", 2, wit.U16{}, "pair"},
+		{"padded-option", 2, wit.U32{}, "option<u32>"},
+		{"pointer", 0, nil, "tuple<u8, string>"},
+		{"prefer-unpadded", 0, nil, "u32"},
+	}
+	face, err := res.Interface("foo:shapes/shapes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := face.TypeDefs.Get(tt.name).Kind.(*wit.Variant)
+			shape := variantShape(v)
+			got := shape.WIT(nil, "")
+			if td, ok := shape.(*wit.TypeDef); ok && td.Name != nil {
+				got = *td.Name
+			}
+			if got != tt.shape {
+				t.Errorf("variantShape: %s, expected %s", got, tt.shape)
+			}
+			n, elem := variantShapeArray(v)
+			if n != tt.n || elem != tt.elem {
+				t.Errorf("variantShapeArray: [%d]%T, expected [%d]%T", n, elem, tt.n, tt.elem)
+			}
+		})
+	}
+
+	src, _ := matchFiles(generateGo(t, res), "")
+	if want := "type PaddedTuple cm.Variant[uint8, [2]uint64, cm.Tuple[uint8, uint64]]"; !strings.Contains(src, want) {
+		t.Errorf("generated code does not contain %q:\n%s", want, src)
+	}
+	validateGeneratedGo(t, res, "wit/bindgen/shapes")
+}
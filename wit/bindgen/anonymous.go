@@ -0,0 +1,55 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// declareAnonymousTypes declares a Go type alias in file for each anonymous tuple,
+// option, result, or list used by a param or result of f, if the AnonymousTypes option is set.
+func (g *generator) declareAnonymousTypes(file *gen.File, dir wit.Direction, f *wit.Function) {
+	if g.opts.typeNamer == nil {
+		return
+	}
+	for _, p := range f.Params {
+		g.declareAnonymousType(file, dir, f, p.Name, p.Type)
+	}
+	for _, r := range f.Results {
+		g.declareAnonymousType(file, dir, f, r.Name, r.Type)
+	}
+}
+
+// declareAnonymousType declares a Go type alias in file for t, if t is an anonymous
+// tuple, option, result, or list. An alias with the same name and Go type is declared
+// once per Go package. If the name is already declared, a numeric suffix is added.
+func (g *generator) declareAnonymousType(file *gen.File, dir wit.Direction, f *wit.Function, param string, t wit.Type) {
+	td, ok := t.(*wit.TypeDef)
+	if !ok || td.Name != nil {
+		return
+	}
+	switch td.Kind.(type) {
+	case *wit.Tuple, *wit.Option, *wit.Result, *wit.List:
+	default:
+		return
+	}
+
+	goName := g.goName(g.opts.typeNamer.TypeName(td, f, param), true)
+	rep := g.typeRep(file, dir, td)
+	key := goName + " = " + rep
+	if g.anonymousTypes[file.Package] == nil {
+		g.anonymousTypes[file.Package] = make(map[string]bool)
+	}
+	if g.anonymousTypes[file.Package][key] {
+		return
+	}
+	g.anonymousTypes[file.Package][key] = true
+	name := file.DeclareName(goName)
+
+	var b strings.Builder
+	stringio.Write(&b, "// ", name, " is an alias for the anonymous WIT type \"", td.WIT(nil, ""), "\".\n")
+	stringio.Write(&b, "type ", name, " = ", rep, "\n\n")
+	file.Write([]byte(b.String()))
+}
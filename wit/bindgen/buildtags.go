@@ -0,0 +1,26 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// buildTag returns the build constraint for the Go files generated for the WIT
+// interface or world id, as specified by the [BuildTags] option, or an empty string.
+// An expression with || is parenthesized, so it can be joined with && to other constraints.
+func (g *generator) buildTag(id wit.Ident) string {
+	if len(g.opts.buildTags) == 0 {
+		return ""
+	}
+	expr, ok := g.opts.buildTags[id.String()]
+	if !ok {
+		unversioned := id
+		unversioned.Version = nil
+		expr = g.opts.buildTags[unversioned.String()]
+	}
+	if strings.Contains(expr, "||") {
+		expr = "(" + expr + ")"
+	}
+	return expr
+}
@@ -0,0 +1,187 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// resultErrorsResult returns the anonymous [wit.Result] returned by imported function f
+// if result errors are enabled, otherwise nil. Functions that return a result
+// as their only, unnamed result are wrapped to return (T, error).
+func (g *generator) resultErrorsResult(dir wit.Direction, f *wit.Function) *wit.Result {
+	if !g.opts.resultErrors || dir != wit.Imported || g.opts.target == TargetWASIP1 {
+		return nil
+	}
+	if len(f.Results) != 1 || f.Results[0].Name != "" {
+		return nil
+	}
+	t, ok := f.Results[0].Type.(*wit.TypeDef)
+	if !ok || t.Name != nil {
+		return nil
+	}
+	r, _ := t.Kind.(*wit.Result)
+	return r
+}
+
+// resultErrorName returns the name of the Go error type that wraps the error type of r,
+// declaring it in file if necessary. goName is used to name error types for anonymous types.
+func (g *generator) resultErrorName(file *gen.File, r *wit.Result, goName string) string {
+	key := r.Err // nil for result without an error type
+	if g.resultErrors[file.Package] == nil {
+		g.resultErrors[file.Package] = make(map[wit.Type]string)
+	}
+	if name, ok := g.resultErrors[file.Package][key]; ok {
+		return name
+	}
+
+	var b strings.Builder
+	var name string
+	switch t := r.Err.(type) {
+	case nil:
+		name = file.DeclareName("ResultError")
+		stringio.Write(&b, "// ", name, " is returned by functions that return a result without an error type, if the result is an error.\n")
+		stringio.Write(&b, "type ", name, " struct{}\n\n")
+		b.WriteString("// Error implements the error interface.\n")
+		stringio.Write(&b, "func (", name, ") Error() string {\n")
+		b.WriteString("return \"result error\"\n")
+		b.WriteString("}\n\n")
+		g.resultErrors[file.Package][key] = name
+		file.Write([]byte(b.String()))
+		return name
+
+	case *wit.TypeDef:
+		if t.Name != nil {
+			name = file.DeclareName(g.goName(*t.Name, true) + "Error")
+		} else {
+			name = file.DeclareName(goName + "Error")
+		}
+
+	default:
+		name = file.DeclareName(g.goName(t.TypeName(), true) + "Error")
+	}
+
+	witName := r.Err.TypeName()
+	if witName == "" {
+		witName = r.Err.WIT(nil, "")
+	}
+	stringio.Write(&b, "// ", name, " wraps the WIT type \"", witName, "\" as an error,\n")
+	b.WriteString("// returned by functions that return a result with this error type.\n")
+	stringio.Write(&b, "type ", name, " struct {\n")
+	stringio.Write(&b, "Err ", g.typeRep(file, wit.Imported, r.Err), "\n")
+	b.WriteString("}\n\n")
+	b.WriteString("// Error implements the error interface.\n")
+	stringio.Write(&b, "func (e ", name, ") Error() string {\n")
+	switch {
+	case r.Err.TypeName() == "string":
+		b.WriteString("return e.Err\n")
+	case g.hasStringMethod(r.Err):
+		b.WriteString("return e.Err.String()\n")
+	default:
+		stringio.Write(&b, "return \"", witName, "\"\n")
+	}
+	b.WriteString("}\n\n")
+
+	g.resultErrors[file.Package][key] = name
+	file.Write([]byte(b.String()))
+	return name
+}
+
+// hasStringMethod reports whether the Go type for t has a generated String method.
+func (g *generator) hasStringMethod(t wit.Type) bool {
+	td, ok := t.(*wit.TypeDef)
+	if !ok || td.Name == nil {
+		return false
+	}
+	switch k := td.Root().Kind.(type) {
+	case *wit.Enum:
+		return true
+	case *wit.Variant:
+		for _, c := range k.Cases {
+			if g.goName(c.Name, true) == "String" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// resultErrorsFunction returns a Go function that calls the raw function in decl,
+// returning its result r as (T, error) or error.
+func (g *generator) resultErrorsFunction(f *wit.Function, decl funcDecl, r *wit.Result) string {
+	file := decl.f.file
+	scope := decl.f.scope
+	goName := decl.errName
+	if decl.f.isMethod() {
+		goName = g.typeRep(file, decl.f.receiver.dir, decl.f.receiver.typ) + goName
+	}
+	errName := g.resultErrorName(file, r, goName)
+
+	var b strings.Builder
+	b.WriteString(g.functionDocs(wit.Imported, f, decl.errName))
+	rawName := decl.f.name
+	if decl.f.isMethod() {
+		rawName = g.typeRep(file, decl.f.receiver.dir, decl.f.receiver.typ) + "." + rawName
+	}
+	stringio.Write(&b, "//\n// ", decl.errName, " returns the result as ")
+	if r.OK != nil {
+		b.WriteString("(T, error)")
+	} else {
+		b.WriteString("an error")
+	}
+	stringio.Write(&b, " of type [", errName, "]. Use [", rawName, "] for the raw result.\n")
+
+	b.WriteString("func ")
+	if decl.f.isMethod() {
+		stringio.Write(&b, "(", decl.f.receiver.name, " ", g.typeRep(file, decl.f.receiver.dir, decl.f.receiver.typ), ") ")
+	}
+	b.WriteString(decl.errName)
+	stringio.Write(&b, "(", g.paramList(file, decl.f), ") ")
+
+	result := scope.DeclareName("result")
+	err := scope.DeclareName("err")
+	if r.OK != nil {
+		stringio.Write(&b, "(", result, " ", g.typeRep(file, wit.Imported, r.OK), ", ", err, " error)")
+	} else {
+		stringio.Write(&b, "(", err, " error)")
+	}
+	b.WriteString(" {\n")
+
+	raw := scope.DeclareName("r")
+	stringio.Write(&b, raw, " := ")
+	if decl.f.isMethod() {
+		stringio.Write(&b, decl.f.receiver.name, ".")
+	}
+	stringio.Write(&b, decl.f.name, "(", decl.f.args(), ")\n")
+
+	switch {
+	case r.OK == nil && r.Err == nil:
+		stringio.Write(&b, "if ", raw, " {\n")
+		stringio.Write(&b, err, " = ", errName, "{}\n")
+		b.WriteString("}\n")
+		b.WriteString("return\n")
+	case r.Err == nil:
+		stringio.Write(&b, "if ", raw, ".IsErr() {\n")
+		stringio.Write(&b, "return ", result, ", ", errName, "{}\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return *", raw, ".OK(), nil\n")
+	default:
+		stringio.Write(&b, "if e := ", raw, ".Err(); e != nil {\n")
+		if r.OK != nil {
+			stringio.Write(&b, "return ", result, ", ", errName, "{Err: *e}\n")
+		} else {
+			stringio.Write(&b, "return ", errName, "{Err: *e}\n")
+		}
+		b.WriteString("}\n")
+		if r.OK != nil {
+			stringio.Write(&b, "return *", raw, ".OK(), nil\n")
+		} else {
+			b.WriteString("return nil\n")
+		}
+	}
+	b.WriteString("}\n\n")
+	return b.String()
+}
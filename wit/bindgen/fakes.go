@@ -0,0 +1,118 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// FakeSuffix is the suffix of files generated by the [Fakes] option.
+const FakeSuffix = ".fake" + GoSuffix
+
+// defineFake emits a Fake type that implements the Go interface ifaceName
+// for the imported functions in decls, if the Fakes option is set.
+// The Fake records each call and returns programmable results.
+func (g *generator) defineFake(id wit.Ident, ifaceName string, decls []funcDecl) {
+	if !g.opts.fakes {
+		return
+	}
+
+	main := g.fileFor(id)
+	file := main.Package.File(strings.TrimSuffix(main.Name, GoSuffix) + FakeSuffix)
+	file.GeneratedBy = main.GeneratedBy
+	file.Build = main.Build
+
+	fakeName := file.DeclareName("Fake")
+	callName := file.DeclareName("FakeCall")
+	sync := file.Import("sync")
+
+	// Fields and methods of the Fake share a scope.
+	scope := gen.NewScope(nil)
+	for _, decl := range decls {
+		scope.DeclareName(decl.f.name)
+	}
+	callsName := scope.DeclareName("Calls")
+	funcNames := make([]string, len(decls))
+	for i, decl := range decls {
+		funcNames[i] = scope.DeclareName(decl.f.name + "Func")
+	}
+
+	var b strings.Builder
+	stringio.Write(&b, "// ", fakeName, " is an in-memory fake implementation of [", ifaceName, "] for tests.\n")
+	b.WriteString("// Each method records its call, then calls the corresponding function field\n")
+	b.WriteString("// if set, otherwise it returns zero values.\n")
+	stringio.Write(&b, "type ", fakeName, " struct {\n")
+	for i, decl := range decls {
+		stringio.Write(&b, "// ", funcNames[i], ", if set, implements [", fakeName, ".", decl.f.name, "].\n")
+		stringio.Write(&b, funcNames[i], " func", g.functionSignature(file, decl.f), "\n\n")
+	}
+	stringio.Write(&b, "mu ", sync, ".Mutex\n")
+	stringio.Write(&b, "calls []", callName, "\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "var _ ", ifaceName, " = &", fakeName, "{}\n\n")
+
+	stringio.Write(&b, "// ", callName, " records a call to a method of [", fakeName, "].\n")
+	stringio.Write(&b, "type ", callName, " struct {\n")
+	b.WriteString("Method string\n")
+	b.WriteString("Args []any\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "// ", callsName, " returns the calls made to the methods of f, in order.\n")
+	stringio.Write(&b, "func (f *", fakeName, ") ", callsName, "() []", callName, " {\n")
+	b.WriteString("f.mu.Lock()\n")
+	b.WriteString("defer f.mu.Unlock()\n")
+	stringio.Write(&b, "return append([]", callName, "(nil), f.calls...)\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "func (f *", fakeName, ") record(method string, args ...any) {\n")
+	b.WriteString("f.mu.Lock()\n")
+	stringio.Write(&b, "f.calls = append(f.calls, ", callName, "{method, args})\n")
+	b.WriteString("f.mu.Unlock()\n")
+	b.WriteString("}\n\n")
+
+	for i, decl := range decls {
+		recv := decl.f.scope.DeclareName("f")
+		stringio.Write(&b, "// ", decl.f.name, " implements [", ifaceName, "].\n")
+		stringio.Write(&b, "func (", recv, " *", fakeName, ") ", decl.f.name, g.namedResultsSignature(file, decl.f), " {\n")
+		stringio.Write(&b, recv, ".record(\"", decl.f.name, "\"")
+		for _, p := range decl.f.params {
+			b.WriteString(", ")
+			b.WriteString(p.name)
+		}
+		b.WriteString(")\n")
+		stringio.Write(&b, "if ", recv, ".", funcNames[i], " != nil {\n")
+		if len(decl.f.results) > 0 {
+			b.WriteString("return ")
+		}
+		stringio.Write(&b, recv, ".", funcNames[i], "(", decl.f.args(), ")\n")
+		if len(decl.f.results) == 0 {
+			b.WriteString("}\n")
+		} else {
+			b.WriteString("}\nreturn\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	file.Write([]byte(b.String()))
+}
+
+// namedResultsSignature returns the Go signature of f with named results,
+// so a function body can return zero values with a bare return.
+func (g *generator) namedResultsSignature(file *gen.File, f function) string {
+	var b strings.Builder
+	stringio.Write(&b, "(", g.paramList(file, f), ")")
+	if len(f.results) > 0 {
+		b.WriteString(" (")
+		for i, r := range f.results {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, r.name, " ", g.typeRep(file, r.dir, r.typ))
+		}
+		b.WriteRune(')')
+	}
+	return b.String()
+}
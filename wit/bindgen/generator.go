@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"go/token"
+	"log/slog"
+	"path"
 	"path/filepath"
 	"runtime"
 	"slices"
@@ -19,6 +21,7 @@ import (
 	"github.com/ydnar/wasm-tools-go/internal/go/gen"
 	"github.com/ydnar/wasm-tools-go/internal/stringio"
 	"github.com/ydnar/wasm-tools-go/wit"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
 )
 
 const (
@@ -49,6 +52,7 @@ type funcDecl struct {
 	f          function // The exported Go function
 	wasm       function // The wasmimport or wasmexport function
 	linkerName string   // The wasmimport or wasmexport mangled linker name
+	errName    string   // The Go function that returns (T, error), if any
 }
 
 // function represents a Go function created from a Component Model function
@@ -57,6 +61,7 @@ type function struct {
 	scope    gen.Scope // Scope for function-local declarations
 	name     string    // The scoped unique Go name for this function (method names are scoped to receiver type)
 	receiver param     // The method receiver, if any
+	ctx      string    // The name of the leading context.Context param, if any
 	params   []param   // Function param(s), with unique Go name(s)
 	results  []param   // Function result(s), with unique Go name(s)
 }
@@ -65,22 +70,51 @@ func (f *function) isMethod() bool {
 	return f.receiver.typ != nil
 }
 
+// args returns the comma-separated names of the params of f, including its
+// context param, if any, for a call that forwards the params of f.
+func (f *function) args() string {
+	var b strings.Builder
+	if f.ctx != "" {
+		b.WriteString(f.ctx)
+	}
+	for _, p := range f.params {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(p.name)
+	}
+	return b.String()
+}
+
 // param represents a Go function parameter or result.
 // name is a unique Go name within the function scope.
 type param struct {
 	name string
 	typ  wit.Type
 	dir  wit.Direction
+
+	// option is the anonymous option<T> type of a param represented as *T in typ,
+	// if the OptionPointers option is set.
+	option *wit.TypeDef
+}
+
+// witType returns the WIT type of p, which differs from typ
+// if p is an option<T> represented as *T.
+func (p *param) witType() wit.Type {
+	if p.option != nil {
+		return p.option
+	}
+	return p.typ
 }
 
-func goFunction(file *gen.File, tdir, dir wit.Direction, f *wit.Function, goName string) function {
+func (g *generator) goFunction(file *gen.File, tdir, dir wit.Direction, f *wit.Function, goName string) function {
 	scope := gen.NewScope(file)
 	out := function{
 		file:    file,
 		scope:   scope,
 		name:    goName,
-		params:  goParams(scope, tdir, f.Params),
-		results: goParams(scope, tdir, f.Results),
+		params:  g.goParams(scope, tdir, f.Params),
+		results: g.goParams(scope, tdir, f.Results),
 	}
 	if len(out.results) == 1 && out.results[0].name == "" {
 		out.results[0].name = "result"
@@ -92,10 +126,10 @@ func goFunction(file *gen.File, tdir, dir wit.Direction, f *wit.Function, goName
 	return out
 }
 
-func goParams(scope gen.Scope, dir wit.Direction, params []wit.Param) []param {
+func (g *generator) goParams(scope gen.Scope, dir wit.Direction, params []wit.Param) []param {
 	out := make([]param, len(params))
 	for i := range params {
-		out[i].name = scope.DeclareName(GoName(params[i].Name, false))
+		out[i].name = scope.DeclareName(g.goName(params[i].Name, false))
 		out[i].typ = params[i].Type
 		out[i].dir = dir
 	}
@@ -106,6 +140,9 @@ type generator struct {
 	opts options
 	res  *wit.Resolve
 
+	// world is the first world generated, recorded in the header of generated files.
+	world *wit.World
+
 	// versioned is set to true if there are multiple versions of a WIT package in res,
 	// which affects the generated Go package paths.
 	versioned bool
@@ -127,12 +164,48 @@ type generator struct {
 	// defined represent whether a world, interface, type, or function has been defined.
 	// It is indexed on [wit.Direction], either [Imported] or [Exported].
 	defined [2]map[any]bool
+
+	// stubs collects stub implementations of exported functions for the current world.
+	stubs *stubs
+
+	// resultErrors map Go packages and WIT error types to Go error types
+	// returned by functions that return a result.
+	resultErrors map[*gen.Package]map[wit.Type]string
+
+	// packageBuildTags map Go packages to the build constraint of the WIT interface
+	// generated into them, as specified by the [BuildTags] option.
+	packageBuildTags map[*gen.Package]string
+
+	// pluginErr collects errors returned by plugins.
+	pluginErr error
+
+	// caseNames map enum, flags, and variant types to the Go names of their
+	// constants or constructors, in case order, if the LayoutTests option is set.
+	caseNames map[caseKey][]string
+
+	// anonymousTypes records the Go type aliases declared in each Go package for
+	// anonymous types, keyed on "Name = Type", if the AnonymousTypes option is set.
+	anonymousTypes map[*gen.Package]map[string]bool
+
+	// inlineIDs map anonymous interfaces declared inline in worlds to their synthetic IDs.
+	inlineIDs map[*wit.Interface]wit.Ident
+
+	// moduleNames map the ID strings of worlds and anonymous interfaces to the
+	// Core WebAssembly module names of their functions: [wit.RootModule] for worlds,
+	// or the names of the world items that declare anonymous interfaces.
+	moduleNames map[string]string
 }
 
 func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 	g := &generator{
-		packages:    make(map[string]*gen.Package),
-		witPackages: make(map[string]*gen.Package),
+		packages:         make(map[string]*gen.Package),
+		witPackages:      make(map[string]*gen.Package),
+		resultErrors:     make(map[*gen.Package]map[wit.Type]string),
+		packageBuildTags: make(map[*gen.Package]string),
+		anonymousTypes:   make(map[*gen.Package]map[string]bool),
+		caseNames:        make(map[caseKey][]string),
+		inlineIDs:        make(map[*wit.Interface]wit.Ident),
+		moduleNames:      make(map[string]string),
 	}
 	for i := 0; i < 2; i++ {
 		g.types[i] = make(map[*wit.TypeDef]typeDecl)
@@ -150,23 +223,88 @@ func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 	if g.opts.cmPackage == "" {
 		g.opts.cmPackage = cmPackage
 	}
+	if g.opts.logger == nil {
+		g.opts.logger = slog.Default()
+	}
+	if g.opts.casing == nil {
+		g.opts.casing = DefaultCasing
+	}
 	g.res = res
+	g.detectModuleNames()
 	return g, nil
 }
 
+// detectModuleNames records the Core WebAssembly module names of functions imported directly
+// by worlds, and assigns a deterministic synthetic ID to each anonymous interface declared
+// inline in a world, derived from the world and item names. See [wit.World.InterfaceID].
+func (g *generator) detectModuleNames() {
+	for _, w := range g.res.Worlds {
+		if w.Package != nil {
+			id := w.ID()
+			g.moduleNames[id.String()] = wit.RootModule
+		}
+		for _, items := range []*ordered.Map[string, wit.WorldItem]{&w.Imports, &w.Exports} {
+			items.All()(func(name string, item wit.WorldItem) bool {
+				i, ok := item.(*wit.Interface)
+				if !ok || i.Name != nil {
+					return true
+				}
+				if _, ok := g.inlineIDs[i]; ok {
+					return true
+				}
+				if id, ok := w.InterfaceID(i); ok {
+					g.inlineIDs[i] = id
+					g.moduleNames[id.String()] = name
+				}
+				return true
+			})
+		}
+	}
+}
+
 func (g *generator) generate() ([]*gen.Package, error) {
 	g.detectVersionedPackages()
-	err := g.defineWorlds()
+	var err error
+	if g.opts.host {
+		err = g.defineHostWorlds()
+	} else {
+		err = g.defineWorlds()
+	}
 	if err != nil {
 		return nil, err
 	}
+	if g.pluginErr != nil {
+		return nil, g.pluginErr
+	}
+	source := g.source()
 	var packages []*gen.Package
 	for _, path := range codec.SortedKeys(g.packages) {
-		packages = append(packages, g.packages[path])
+		pkg := g.packages[path]
+		for _, file := range pkg.Files {
+			if file.Source == "" {
+				file.Source = source
+			}
+			file.Checksum = true
+		}
+		packages = append(packages, pkg)
 	}
 	return packages, nil
 }
 
+// source returns the source of generated files, e.g.
+// "WIT world wasi:cli/command@0.2.0, wit-bindgen-go v0.1.0".
+func (g *generator) source() string {
+	if g.world == nil {
+		return ""
+	}
+	id := g.world.ID()
+	s := "WIT world " + id.String()
+	if g.opts.generatorVersion != "" {
+		s += ", " + g.opts.generatedBy + " " + g.opts.generatorVersion
+	}
+	return s
+}
+
 func (g *generator) detectVersionedPackages() {
 	if g.opts.versioned {
 		g.versioned = true
@@ -206,7 +344,10 @@ func (g *generator) defineWorlds() error {
 	// fmt.Fprintf(os.Stderr, "Generating Go for %d world(s)\n", len(g.res.Worlds))
 	for i, w := range g.res.Worlds {
 		if matchWorld(w, g.opts.world) || (g.opts.world == "" && i == len(g.res.Worlds)-1) {
-			g.defineWorld(w)
+			err := g.defineWorld(w)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -216,8 +357,7 @@ func matchWorld(w *wit.World, name string) bool {
 	if name == w.Name {
 		return true
 	}
-	id := w.Package.Name
-	id.Extension = w.Name
+	id := w.ID()
 	if name == id.String() {
 		return true
 	}
@@ -229,8 +369,11 @@ func (g *generator) defineWorld(w *wit.World) error {
 	if !g.define(wit.Exported, w) {
 		return nil
 	}
-	id := w.Package.Name
-	id.Extension = w.Name
+	if g.world == nil {
+		g.world = w
+	}
+	id := w.ID()
+	g.opts.logger.Debug("generating world", "world", id.String())
 	pkg := g.packageFor(id)
 	file := g.fileFor(id)
 
@@ -241,6 +384,7 @@ func (g *generator) defineWorld(w *wit.World) error {
 			b.WriteString("\n")
 			b.WriteString(w.Docs.Contents)
 		}
+		writeDeprecatedPackageDocs(&b, w.WITKind(), w.Stability)
 		file.PackageDocs = b.String()
 	}
 
@@ -262,6 +406,8 @@ func (g *generator) defineWorld(w *wit.World) error {
 		return err
 	}
 
+	g.beginStubs(id)
+	defer g.endStubs()
 	w.Exports.All()(func(name string, v wit.WorldItem) bool {
 		switch v := v.(type) {
 		case *wit.Interface:
@@ -284,11 +430,8 @@ func (g *generator) defineInterface(dir wit.Direction, i *wit.Interface, name st
 	if !g.define(dir, i) {
 		return nil
 	}
-	if i.Name != nil {
-		name = *i.Name
-	}
-	id := i.Package.Name
-	id.Extension = name
+	id := g.interfaceID(i, name)
+	g.opts.logger.Debug("generating interface", "interface", id.String(), "direction", dir.String())
 	pkg := g.packageFor(id)
 	file := g.fileFor(id)
 
@@ -299,6 +442,7 @@ func (g *generator) defineInterface(dir wit.Direction, i *wit.Interface, name st
 			b.WriteString("\n")
 			b.WriteString(i.Docs.Contents)
 		}
+		writeDeprecatedPackageDocs(&b, i.WITKind(), i.Stability)
 		file.PackageDocs = b.String()
 	}
 
@@ -322,11 +466,21 @@ func (g *generator) defineInterface(dir wit.Direction, i *wit.Interface, name st
 	// })
 
 	// Define standalone functions
+	var err error
 	i.Functions.All()(func(_ string, f *wit.Function) bool {
 		if f.IsFreestanding() {
-			g.defineFunction(id, dir, f)
+			err = g.defineFunction(id, dir, f)
 		}
-		return true
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.defineInterfaceWrappers(file, id, dir, i)
+
+	g.callPlugins(file, func(p Plugin, file *File) error {
+		return p.Interface(file, dir, i)
 	})
 
 	return nil
@@ -348,11 +502,11 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 	if err != nil {
 		return err
 	}
-	owner := typeDefOwner(t)
+	owner := g.typeDefOwner(t)
 
 	// If an alias, get root
 	root := t.Root()
-	rootOwner := typeDefOwner(root)
+	rootOwner := g.typeDefOwner(root)
 	rootName := name
 	if root.Name != nil {
 		rootName = *root.Name
@@ -370,7 +524,12 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		b.WriteString(formatDocComments(t.Docs.Contents, false))
 		b.WriteString("//\n")
 		b.WriteString(formatDocComments(t.WIT(nil, ""), true))
+		b.WriteString(deprecatedDocs(t.WITKind(), t.Stability))
+		b.WriteString(g.sourceDocs(t))
 		stringio.Write(&b, "type ", decl.name, " ", g.typeDefRep(decl.file, dir, t, decl.name), "\n\n")
+		if r, ok := t.Kind.(*wit.Record); ok && g.opts.recordFuncs {
+			b.WriteString(g.recordFuncs(decl, dir, t, r))
+		}
 	}
 
 	_, err = decl.file.Write(b.Bytes())
@@ -378,6 +537,18 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		return err
 	}
 
+	if root == t {
+		err = g.defineLayoutAssertions(decl, t)
+		if err != nil {
+			return err
+		}
+		g.defineLayoutTest(decl, dir, t)
+	}
+
+	g.callPlugins(decl.file, func(p Plugin, file *File) error {
+		return p.TypeDef(file, dir, t, decl.name)
+	})
+
 	// Define any associated functions
 	switch dir {
 	case wit.Imported:
@@ -454,10 +625,10 @@ func (g *generator) declareTypeDef(file *gen.File, dir wit.Direction, t *wit.Typ
 		if t.Name == nil {
 			return typeDecl{}, errors.New("BUG: cannot declare unnamed wit.TypeDef")
 		}
-		goName = GoName(*t.Name, true)
+		goName = g.goName(*t.Name, true)
 	}
 	if file == nil {
-		file = g.fileFor(typeDefOwner(t))
+		file = g.fileFor(g.typeDefOwner(t))
 	}
 	decl = typeDecl{
 		file:  file,
@@ -528,23 +699,44 @@ func (g *generator) typeDecl(dir wit.Direction, t *wit.TypeDef) (typeDecl, bool)
 	return decl, ok
 }
 
-func typeDefOwner(t *wit.TypeDef) wit.Ident {
+func (g *generator) typeDefOwner(t *wit.TypeDef) wit.Ident {
 	var id wit.Ident
 	switch owner := t.Owner.(type) {
 	case *wit.World:
-		id = owner.Package.Name
-		id.Extension = owner.Name
+		id = owner.ID()
 	case *wit.Interface:
-		id = owner.Package.Name
-		if owner.Name == nil {
-			id.Extension = "unknown"
-		} else {
-			id.Extension = *owner.Name
-		}
+		id = g.interfaceID(owner, "unknown")
+	}
+	return id
+}
+
+// interfaceID returns the [wit.Ident] of interface i. If i is an anonymous
+// interface declared in a world, the returned Ident has a synthetic extension
+// derived from the world and item names, e.g. foo:bar/world/item.
+// If i is not declared in a world in g.res, the returned Ident has extension name.
+func (g *generator) interfaceID(i *wit.Interface, name string) wit.Ident {
+	if id, ok := i.ID(); ok {
+		return id
 	}
+	if id, ok := g.inlineIDs[i]; ok {
+		return id
+	}
+	id := i.Package.Name
+	id.Extension = name
 	return id
 }
 
+// moduleName returns the Core WebAssembly module name of the functions in the
+// interface or world identified by id. For worlds, this is [wit.RootModule].
+// For anonymous interfaces declared inline in a world, this is the name of the
+// world item, e.g. foo, rather than the synthetic ID. See [wit.World.CoreImports].
+func (g *generator) moduleName(id wit.Ident) string {
+	if name, ok := g.moduleNames[id.String()]; ok {
+		return name
+	}
+	return id.String()
+}
+
 func (g *generator) typeDefRep(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
 	return g.typeDefKindRep(file, dir, t.Kind, goName)
 }
@@ -658,22 +850,150 @@ func (g *generator) recordRep(file *gen.File, dir wit.Direction, r *wit.Record,
 			b.WriteRune('\n')
 		}
 		b.WriteString(formatDocComments(f.Docs.Contents, false))
-		stringio.Write(&b, fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type), "\n")
+		stringio.Write(&b, g.fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type), "\n")
 	}
 	b.WriteRune('}')
 	return b.String()
 }
 
+// recordFuncs returns the constructor and Validate method for record type t, declared as decl.
+// The constructor is omitted if its name collides with a function in the same interface or world,
+// and Validate is omitted if t has no fields to validate or a field named "validate".
+func (g *generator) recordFuncs(decl typeDecl, dir wit.Direction, t *wit.TypeDef, r *wit.Record) string {
+	file := decl.file
+	exported := token.IsExported(decl.name)
+	var b strings.Builder
+
+	// Emit constructor
+	constructorName := "New" + decl.name
+	if !exported || len(r.Fields) == 0 || file.HasName(constructorName) {
+		constructorName = ""
+	}
+	t.Owner.AllFunctions()(func(f *wit.Function) bool {
+		if f.IsFreestanding() && g.goName(f.BaseName(), true) == constructorName {
+			constructorName = ""
+		}
+		return constructorName != ""
+	})
+	if constructorName != "" {
+		constructorName = file.DeclareName(constructorName)
+		scope := gen.NewScope(file)
+		params := make([]string, len(r.Fields))
+		for i, f := range r.Fields {
+			params[i] = scope.DeclareName(g.fieldName(f.Name, false))
+		}
+		stringio.Write(&b, "// ", constructorName, " returns a [", decl.name, "] with the specified fields.\n")
+		stringio.Write(&b, "func ", constructorName, "(")
+		for i, f := range r.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, params[i], " ", g.typeRep(file, dir, f.Type))
+		}
+		stringio.Write(&b, ") ", decl.name, " {\n")
+		stringio.Write(&b, "return ", decl.name, "{")
+		for i, f := range r.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, g.fieldName(f.Name, exported), ": ", params[i])
+		}
+		b.WriteString("}\n")
+		b.WriteString("}\n\n")
+	}
+
+	// Emit Validate method
+	var checks strings.Builder
+	for _, f := range r.Fields {
+		if g.goName(f.Name, true) == "Validate" {
+			return b.String()
+		}
+		var cond, msg string
+		field := "self." + g.fieldName(f.Name, exported)
+		switch kind := rootKind(f.Type).(type) {
+		case *wit.Own, *wit.Borrow:
+			cond = field + " == " + file.Import(g.opts.cmPackage) + ".ResourceNone"
+			msg = "invalid handle"
+		case *wit.Enum:
+			cond = field + " >= " + strconv.Itoa(len(kind.Cases))
+			msg = "enum value out of range"
+		case *wit.Variant:
+			if e := kind.Enum(); e != nil {
+				cond = field + " >= " + strconv.Itoa(len(e.Cases))
+				msg = "enum value out of range"
+			}
+		case *wit.Record:
+			if g.recordValidates(kind) {
+				stringio.Write(&checks, "if err := ", field, ".Validate(); err != nil {\n")
+				stringio.Write(&checks, "return ", file.Import("fmt"), ".Errorf(", strconv.Quote(*t.Name+"."+f.Name+": %w"), ", err)\n")
+				checks.WriteString("}\n")
+			}
+		}
+		if cond == "" {
+			continue
+		}
+		stringio.Write(&checks, "if ", cond, " {\n")
+		stringio.Write(&checks, "return ", file.Import("errors"), ".New(", strconv.Quote(*t.Name+"."+f.Name+": "+msg), ")\n")
+		checks.WriteString("}\n")
+	}
+	if checks.Len() == 0 {
+		return b.String()
+	}
+	stringio.Write(&b, "// Validate returns an error if [", decl.name, "] contains a zero handle or an out-of-range enum value.\n")
+	stringio.Write(&b, "func (self *", decl.name, ") Validate() error {\n")
+	b.WriteString(checks.String())
+	b.WriteString("return nil\n")
+	b.WriteString("}\n\n")
+	return b.String()
+}
+
+// recordValidates returns true if recordFuncs generates a Validate method for a record type with kind r.
+func (g *generator) recordValidates(r *wit.Record) bool {
+	for _, f := range r.Fields {
+		if g.goName(f.Name, true) == "Validate" {
+			return false
+		}
+	}
+	for _, f := range r.Fields {
+		switch kind := rootKind(f.Type).(type) {
+		case *wit.Own, *wit.Borrow, *wit.Enum:
+			return true
+		case *wit.Variant:
+			if kind.Enum() != nil {
+				return true
+			}
+		case *wit.Record:
+			if g.recordValidates(kind) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rootKind returns the [wit.TypeDefKind] of the root of t if t is a [wit.TypeDef], otherwise nil.
+func rootKind(t wit.Type) wit.TypeDefKind {
+	if t, ok := t.(*wit.TypeDef); ok {
+		return t.Root().Kind
+	}
+	return nil
+}
+
+// goName returns a Go identifier for a WIT name, converted with the configured [Casing].
+func (g *generator) goName(name string, export bool) string {
+	return g.opts.casing.GoName(name, export)
+}
+
 // Field names are implicitly scoped to their parent struct,
 // so we don't need to track the mapping between WIT names and Go names.
-func fieldName(name string, export bool) string {
+func (g *generator) fieldName(name string, export bool) string {
 	if name == "" {
 		return ""
 	}
 	if name[0] >= '0' && name[0] <= '9' {
 		name = "f" + name
 	}
-	return GoName(name, export)
+	return g.goName(name, export)
 }
 
 func (g *generator) tupleRep(file *gen.File, dir wit.Direction, t *wit.Tuple) string {
@@ -713,10 +1033,8 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 		typ = wit.U16{}
 	case 4:
 		typ = wit.U32{}
-	case 8:
-		typ = wit.U64{}
 	default:
-		panic(fmt.Sprintf("FIXME: cannot emit a flags type with %d cases", len(flags.Flags)))
+		return g.largeFlagsRep(file, flags, goName)
 	}
 
 	b.WriteString(g.typeRep(file, dir, typ))
@@ -727,7 +1045,8 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 			b.WriteRune('\n')
 		}
 		b.WriteString(formatDocComments(flag.Docs.Contents, false))
-		flagName := file.DeclareName(goName + GoName(flag.Name, true))
+		flagName := file.DeclareName(goName + g.goName(flag.Name, true))
+		g.recordCaseName(file, goName, flagName)
 		b.WriteString(flagName)
 		if i == 0 {
 			stringio.Write(&b, " ", goName, " = 1 << iota")
@@ -738,6 +1057,35 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 	return b.String()
 }
 
+// largeFlagsRep returns the Go representation of a flags type with more than 32 flags,
+// which the Canonical ABI represents as [N]uint32. The Go type embeds [cm.Flags],
+// with a separate Go type for the index of each flag.
+func (g *generator) largeFlagsRep(file *gen.File, flags *wit.Flags, goName string) string {
+	var b strings.Builder
+	cm := file.Import(g.opts.cmPackage)
+	flagType := file.DeclareName(goName + "Flag")
+	words := strconv.Itoa(int(flags.Size() / 4))
+	stringio.Write(&b, "struct {\n", cm, ".Flags[[", words, "]uint32, ", flagType, "]\n}\n\n")
+	stringio.Write(&b, "// ", flagType, " represents the index of a flag in [", goName, "].\n")
+	stringio.Write(&b, "type ", flagType, " ", cm, ".Flag\n\n")
+	b.WriteString("const (\n")
+	for i, flag := range flags.Flags {
+		if i > 0 && flag.Docs.Contents != "" {
+			b.WriteRune('\n')
+		}
+		b.WriteString(formatDocComments(flag.Docs.Contents, false))
+		flagName := file.DeclareName(goName + g.goName(flag.Name, true))
+		g.recordCaseName(file, goName, flagName)
+		b.WriteString(flagName)
+		if i == 0 {
+			stringio.Write(&b, " ", flagType, " = iota")
+		}
+		b.WriteRune('\n')
+	}
+	b.WriteString(")\n")
+	return b.String()
+}
+
 func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goName string) string {
 	var b strings.Builder
 	disc := wit.Discriminant(len(e.Cases))
@@ -749,7 +1097,9 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 			b.WriteRune('\n')
 		}
 		b.WriteString(formatDocComments(c.Docs.Contents, false))
-		b.WriteString(file.DeclareName(goName + GoName(c.Name, true)))
+		caseName := file.DeclareName(goName + g.goName(c.Name, true))
+		g.recordCaseName(file, goName, caseName)
+		b.WriteString(caseName)
 		if i == 0 {
 			b.WriteRune(' ')
 			b.WriteString(goName)
@@ -757,7 +1107,37 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 		}
 		b.WriteRune('\n')
 	}
-	b.WriteString(")\n")
+	b.WriteString(")\n\n")
+
+	// Emit case names, used by String, MarshalText, and UnmarshalText
+	cm := file.Import(g.opts.cmPackage)
+	stringsName := file.DeclareName("strings" + goName)
+	stringio.Write(&b, "var ", stringsName, " = [", strconv.Itoa(len(e.Cases)), "]string {\n")
+	for _, c := range e.Cases {
+		stringio.Write(&b, strconv.Quote(c.Name), ",\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// String implements [fmt.Stringer], returning the enum case name of e.\n")
+	stringio.Write(&b, "func (e ", goName, ") String() string {\n")
+	stringio.Write(&b, "return ", cm, ".CaseString(", stringsName, "[:], e)\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("// MarshalText implements [encoding.TextMarshaler], returning the enum case name of e.\n")
+	b.WriteString("// Returns an error if e is not one of the enum cases.\n")
+	stringio.Write(&b, "func (e ", goName, ") MarshalText() ([]byte, error) {\n")
+	stringio.Write(&b, "return ", cm, ".CaseMarshalText(", stringsName, "[:], e)\n")
+	b.WriteString("}\n\n")
+
+	unmarshalName := file.DeclareName("_" + goName + "UnmarshalCase")
+	b.WriteString("// UnmarshalText implements [encoding.TextUnmarshaler], unmarshaling into an enum\n")
+	b.WriteString("// case. Returns an error if the supplied text is not one of the enum cases.\n")
+	stringio.Write(&b, "func (e *", goName, ") UnmarshalText(text []byte) error {\n")
+	stringio.Write(&b, "return ", unmarshalName, "(e, text)\n")
+	b.WriteString("}\n\n")
+
+	stringio.Write(&b, "var ", unmarshalName, " = ", cm, ".CaseUnmarshaler[", goName, "](", stringsName, "[:])\n")
+
 	return b.String()
 }
 
@@ -774,13 +1154,22 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 	// Emit type
 	var b strings.Builder
 	cm := file.Import(g.opts.cmPackage)
-	stringio.Write(&b, cm, ".Variant[", g.typeRep(file, dir, disc), ", ", g.typeRep(file, dir, shape), ", ", g.typeRep(file, dir, align), "]\n\n")
+	shapeRep := g.typeRep(file, dir, shape)
+	if shape64 := variantShape64(v); shape64 != nil {
+		// The largest associated type depends on the size of a pointer.
+		shapeRep = cm + ".ArchShape[" + shapeRep + ", " + g.typeRep(file, dir, shape64) + "]"
+	} else if n, elem := variantShapeArray(v); n > 0 {
+		// The padding of the largest associated type would not survive a copy.
+		shapeRep = "[" + strconv.Itoa(int(n)) + "]" + g.typeRep(file, dir, elem)
+	}
+	stringio.Write(&b, cm, ".Variant[", g.typeRep(file, dir, disc), ", ", shapeRep, ", ", g.typeRep(file, dir, align), "]\n\n")
 
 	// Emit cases
 	for i, c := range v.Cases {
 		caseNum := strconv.Itoa(i)
-		caseName := GoName(c.Name, true)
+		caseName := g.goName(c.Name, true)
 		constructorName := file.DeclareName(goName + caseName)
+		g.recordCaseName(file, goName, constructorName)
 		typeRep := g.typeRep(file, dir, c.Type)
 
 		// Emit constructor
@@ -814,13 +1203,34 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, v *wit.Variant
 			b.WriteString("}\n\n")
 		}
 	}
+
+	// Emit String method, unless it would collide with a case getter
+	for _, c := range v.Cases {
+		if g.goName(c.Name, true) == "String" {
+			return b.String()
+		}
+	}
+	stringsName := file.DeclareName("strings" + goName)
+	stringio.Write(&b, "var ", stringsName, " = [", strconv.Itoa(len(v.Cases)), "]string {\n")
+	for _, c := range v.Cases {
+		stringio.Write(&b, strconv.Quote(c.Name), ",\n")
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("// String implements [fmt.Stringer], returning the variant case name of v.\n")
+	stringio.Write(&b, "func (v ", goName, ") String() string {\n")
+	stringio.Write(&b, "return ", cm, ".CaseString(", stringsName, "[:], ", cm, ".Tag(&v))\n")
+	b.WriteString("}\n\n")
+
 	return b.String()
 }
 
 func (g *generator) resultRep(file *gen.File, dir wit.Direction, r *wit.Result) string {
 	var b strings.Builder
 	b.WriteString(file.Import(g.opts.cmPackage))
-	if r.OK == nil && r.Err == nil {
+	if shape32, shape64 := resultShape(r); shape64 != nil {
+		// The larger of OK and Err depends on the size of a pointer.
+		stringio.Write(&b, ".ArchResult[", g.typeRep(file, dir, shape32), ", ", g.typeRep(file, dir, shape64), ", ", g.typeRep(file, dir, r.OK), ", ", g.typeRep(file, dir, r.Err), "]")
+	} else if r.OK == nil && r.Err == nil {
 		b.WriteString(".Result")
 	} else if r.OK == nil || (r.Err != nil && r.Err.Size() > r.OK.Size()) {
 		stringio.Write(&b, ".ErrResult[", g.typeRep(file, dir, r.OK), ", ", g.typeRep(file, dir, r.Err), "]")
@@ -871,17 +1281,21 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 	switch dir {
 	case wit.Imported:
 		pfx = "wasmimport_"
-		linkerName = owner.String() + " " + f.Name
+		linkerName = g.moduleName(owner) + " " + f.Name
 
 	case wit.Exported:
 		pfx = "wasmexport_"
-		linkerName = owner.String() + "#" + f.Name
+		linkerName = g.moduleName(owner) + "#" + f.Name
+		if g.moduleName(owner) == wit.RootModule {
+			// Functions exported directly by a world are not qualified.
+			linkerName = f.Name
+		}
 
 	case importedWithExportedTypes:
 		dir = wit.Imported  // Imported function...
 		tdir = wit.Exported // ...with exported types
 		pfx = "wasmimport_"
-		linkerName = "[export]" + owner.String() + " " + f.Name
+		linkerName = "[export]" + g.moduleName(owner) + " " + f.Name
 
 	default:
 		return funcDecl{}, errors.New("BUG: unknown direction " + dir.String())
@@ -894,7 +1308,7 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 	var funcName, wasmName string
 	switch f.Kind.(type) {
 	case *wit.Freestanding:
-		baseName := GoName(f.BaseName(), true)
+		baseName := g.goName(f.BaseName(), true)
 		funcName = g.declareDirectedName(file, dir, baseName)
 		wasmName = file.DeclareName(pfx + baseName)
 
@@ -908,7 +1322,7 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 	case *wit.Static:
 		t := f.Type().(*wit.TypeDef)
 		td, _ := g.typeDecl(tdir, t)
-		baseName := td.name + GoName(f.BaseName(), true)
+		baseName := td.name + g.goName(f.BaseName(), true)
 		funcName = g.declareDirectedName(file, dir, baseName)
 		wasmName = file.DeclareName(pfx + baseName)
 
@@ -920,14 +1334,14 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 		}
 		switch dir {
 		case wit.Imported:
-			funcName = td.scope.DeclareName(GoName(f.BaseName(), true))
+			funcName = td.scope.DeclareName(g.goName(f.BaseName(), true))
 			if wasm.IsMethod() {
 				wasmName = td.scope.DeclareName(pfx + funcName)
 			} else {
 				wasmName = file.DeclareName(pfx + td.name + funcName)
 			}
 		case wit.Exported:
-			baseName := td.name + GoName(f.BaseName(), true)
+			baseName := td.name + g.goName(f.BaseName(), true)
 			funcName = g.declareDirectedName(file, dir, baseName)
 			wasmName = file.DeclareName(pfx + baseName)
 		default:
@@ -935,10 +1349,27 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 		}
 	}
 
+	// Functions that return a result can also return (T, error).
+	// The raw function keeps the exact shape of the result.
+	var errName string
+	if g.resultErrorsResult(dir, f) != nil {
+		errName = funcName
+		if _, ok := f.Kind.(*wit.Method); ok {
+			td, _ := g.typeDecl(tdir, f.Type().(*wit.TypeDef))
+			funcName = td.scope.DeclareName(funcName + "Result")
+		} else {
+			funcName = g.declareDirectedName(file, dir, funcName+"Result")
+		}
+	}
+
 	fdecl := funcDecl{
-		f:          goFunction(file, tdir, dir, f, funcName),
-		wasm:       goFunction(file, tdir, dir, wasm, wasmName),
+		f:          g.optionPointers(g.goFunction(file, tdir, dir, f, funcName)),
+		wasm:       g.goFunction(file, tdir, dir, wasm, wasmName),
 		linkerName: linkerName,
+		errName:    errName,
+	}
+	if g.opts.contextParams && dir == wit.Imported {
+		fdecl.f.ctx = fdecl.f.scope.DeclareName("ctx")
 	}
 	g.functions[dir][f] = fdecl
 	return fdecl, nil
@@ -948,13 +1379,36 @@ func (g *generator) declareFunction(owner wit.Ident, dir wit.Direction, f *wit.F
 const importedWithExportedTypes = 2
 
 func (g *generator) defineFunction(owner wit.Ident, dir wit.Direction, f *wit.Function) error {
+	if g.opts.target == TargetWASIP1 {
+		if dir == wit.Exported {
+			g.opts.logger.Warn("skipping exported function: target wasip1 does not support go:wasmexport", "owner", owner.String(), "function", f.Name)
+			return nil
+		}
+		if dir != wit.Imported {
+			return nil
+		}
+		if !isWASIP1Function(f) {
+			g.opts.logger.Warn("skipping imported function: target wasip1 supports only scalar params and results", "owner", owner.String(), "function", f.Name)
+			return nil
+		}
+	}
+
 	decl, err := g.declareFunction(owner, dir, f)
 	if err != nil {
 		return err
 	}
 
+	if dir == importedWithExportedTypes {
+		g.declareAnonymousTypes(decl.f.file, wit.Exported, f)
+	} else {
+		g.declareAnonymousTypes(decl.f.file, dir, f)
+	}
+
 	switch dir {
 	case wit.Imported, importedWithExportedTypes:
+		if g.opts.target == TargetWASIP1 {
+			return g.defineWASIP1ImportedFunction(f, decl)
+		}
 		return g.defineImportedFunction(owner, f, decl)
 	case wit.Exported:
 		err := g.defineExportedFunction(owner, f, decl)
@@ -971,7 +1425,25 @@ func (g *generator) defineFunction(owner wit.Ident, dir wit.Direction, f *wit.Fu
 	return nil
 }
 
-func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl funcDecl) error {
+// cachedImport reports whether the result of imported function f in owner
+// is cached, as specified by the [CachedImports] option.
+func (g *generator) cachedImport(owner wit.Ident, f *wit.Function) (bool, error) {
+	if len(g.opts.cachedImports) == 0 {
+		return false, nil
+	}
+	unversioned := owner
+	unversioned.Version = nil
+	name := owner.String() + "#" + f.Name
+	if !g.opts.cachedImports[name] && !g.opts.cachedImports[unversioned.String()+"#"+f.Name] {
+		return false, nil
+	}
+	if !f.IsFreestanding() || len(f.Params) != 0 || len(f.Results) != 1 {
+		return false, fmt.Errorf("cannot cache import %s: must be a freestanding function with no params and a single result", name)
+	}
+	return true, nil
+}
+
+func (g *generator) defineImportedFunction(owner wit.Ident, f *wit.Function, decl funcDecl) error {
 	dir := wit.Imported
 	if !g.define(dir, f) {
 		return nil
@@ -979,6 +1451,11 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 
 	file := decl.f.file
 
+	cached, err := g.cachedImport(owner, f)
+	if err != nil {
+		return err
+	}
+
 	// Bridging between Go and wasm function
 	callParams := slices.Clone(decl.wasm.params)
 	for i := range callParams {
@@ -1031,10 +1508,18 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 
 	// Emit function body
 	b.WriteString(" {\n")
+	var onceName string
+	if cached {
+		onceName = file.DeclareName(g.goName(f.BaseName(), false) + "Once")
+		// The cached function has no params, other than a context.
+		once := decl.f
+		once.ctx = ""
+		stringio.Write(&b, "return ", onceName, ".Get(func", g.functionSignature(file, once), " {\n")
+	}
 	sameResults := slices.Equal(decl.f.results, decl.wasm.results)
 	if len(decl.f.results) == 1 && !sameResults {
 		for _, r := range decl.f.results {
-			stringio.Write(&b, "var ", r.name, " ", g.typeRep(file, r.dir, r.typ), "\n")
+			stringio.Write(&b, "var ", r.name, " ", g.typeRep(file, r.dir, r.witType()), "\n")
 		}
 	}
 
@@ -1048,7 +1533,7 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			b.WriteString(p.name)
+			b.WriteString(g.lowerChar(file, p.typ, g.lowerOptionPointer(file, p, p.name)))
 		}
 		b.WriteString(" }\n")
 	}
@@ -1057,23 +1542,40 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 	}
 
 	// Emit call to wasmimport function
+	liftBool := sameResults && len(decl.wasm.results) == 1 && isBool(decl.wasm.results[0].typ)
 	if sameResults && len(decl.wasm.results) > 0 {
 		b.WriteString("return ")
 	}
+	var call strings.Builder
 	if decl.wasm.isMethod() {
-		stringio.Write(&b, decl.wasm.receiver.name, ".")
+		stringio.Write(&call, decl.wasm.receiver.name, ".")
 	}
-	stringio.Write(&b, decl.wasm.name, "(")
+	stringio.Write(&call, decl.wasm.name, "(")
 	for i, p := range callParams {
 		if i > 0 {
-			b.WriteString(", ")
+			call.WriteString(", ")
 		}
 		if isPointer(p.typ) {
-			b.WriteRune('&')
+			call.WriteRune('&')
+		}
+		if isBool(p.typ) {
+			stringio.Write(&call, file.Import(g.opts.cmPackage), ".BoolToU32(", callParams[i].name, ")")
+			continue
 		}
-		b.WriteString(callParams[i].name)
+		if compoundParams.typ == nil && i < len(decl.f.params) {
+			call.WriteString(g.lowerChar(file, p.typ, g.lowerOptionPointer(file, decl.f.params[i], callParams[i].name)))
+			continue
+		}
+		call.WriteString(callParams[i].name)
 	}
-	b.WriteString(")\n")
+	call.WriteString(")")
+	if liftBool {
+		r := decl.wasm.results[0]
+		b.WriteString(g.boolFromU32(file, r.dir, r.typ, call.String()))
+	} else {
+		b.WriteString(call.String())
+	}
+	b.WriteString("\n")
 	if !sameResults {
 		b.WriteString("return ")
 		if resultsRecord != nil {
@@ -1081,32 +1583,39 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 				if i > 0 {
 					b.WriteString(", ")
 				}
-				stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
+				stringio.Write(&b, compoundResults.name, ".", g.fieldName(f.Name, false))
 			}
 		} else {
 			for i, r := range decl.f.results {
 				if i > 0 {
 					b.WriteString(", ")
 				}
-				b.WriteString(r.name)
+				b.WriteString(liftOptionPointer(r, r.name))
 			}
 		}
 		b.WriteRune('\n')
 	}
+	if cached {
+		b.WriteString("})\n")
+	}
 	b.WriteString("}\n\n")
 
+	if cached {
+		stringio.Write(&b, "// ", onceName, " caches the result of [", decl.f.name, "].\n")
+		stringio.Write(&b, "var ", onceName, " ", file.Import(g.opts.cmPackage), ".Once[", g.typeRep(file, decl.f.results[0].dir, decl.f.results[0].typ), "]\n\n")
+	}
+
 	// Emit wasmimport function
-	stringio.Write(&b, "//go:wasmimport ", decl.linkerName, "\n")
-	b.WriteString("//go:noescape\n")
-	b.WriteString("func ")
-	if decl.wasm.isMethod() {
-		stringio.Write(&b, "(", decl.wasm.receiver.name, " ", g.typeRep(file, decl.wasm.receiver.dir, decl.wasm.receiver.typ), ") ", decl.wasm.name)
+	if g.opts.fakes || g.opts.hostShims {
+		g.defineWasmImportWithHostStub(file, decl)
 	} else {
-		b.WriteString(decl.wasm.name)
+		b.WriteString(g.wasmImportDecl(file, decl))
 	}
-	b.WriteString(g.functionSignature(file, decl.wasm))
 
-	b.WriteString("\n\n")
+	// Emit (T, error) function
+	if r := g.resultErrorsResult(dir, f); r != nil {
+		b.WriteString(g.resultErrorsFunction(f, decl, r))
+	}
 
 	// Emit shared types
 	if t, ok := compoundParams.typ.(*wit.TypeDef); ok {
@@ -1125,6 +1634,9 @@ func (g *generator) defineImportedFunction(_ wit.Ident, f *wit.Function, decl fu
 
 	// Write to file
 	file.Write(b.Bytes())
+	g.callPlugins(file, func(p Plugin, file *File) error {
+		return p.Function(file, dir, f, decl.f.name)
+	})
 
 	return g.ensureEmptyAsm(file.Package)
 }
@@ -1166,6 +1678,7 @@ func (g *generator) defineExportedFunction(_ wit.Ident, f *wit.Function, decl fu
 	b.WriteString(g.functionDocs(dir, f, decl.f.name))
 
 	// Emit var for caller-defined Go func
+	g.defineStub(f, decl)
 	stringio.Write(&b, "var ", decl.f.name, " = func", g.functionSignature(file, decl.f), " {")
 
 	// Emit default function body with panic
@@ -1178,15 +1691,21 @@ func (g *generator) defineExportedFunction(_ wit.Ident, f *wit.Function, decl fu
 	// Emit wasmexport function
 	stringio.Write(&b, "//go:wasmexport ", decl.linkerName, "\n")
 	stringio.Write(&b, "//export ", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
-	stringio.Write(&b, "func ", decl.wasm.name, g.functionSignature(file, decl.wasm))
+	stringio.Write(&b, "func ", decl.wasm.name, g.functionSignature(file, coreBools(decl.wasm)))
 
 	// Emit function body
 	b.WriteString(" {\n")
 	sameResults := slices.Equal(decl.f.results, decl.wasm.results)
 
 	// Emit call to caller-defined Go function
+	lowerBool := sameResults && len(decl.wasm.results) == 1 && isBool(decl.wasm.results[0].typ)
+	lowerChar := sameResults && len(decl.wasm.results) == 1 && isChar(decl.wasm.results[0].typ)
 	if len(decl.f.results) > 0 {
-		if sameResults {
+		if lowerBool {
+			stringio.Write(&b, "return ", file.Import(g.opts.cmPackage), ".BoolToU32(")
+		} else if lowerChar {
+			stringio.Write(&b, "return ", file.Import(g.opts.cmPackage), ".LowerChar(")
+		} else if sameResults {
 			b.WriteString("return ")
 		} else if resultsRecord != nil {
 			stringio.Write(&b, "var ", compoundResults.name, " ", g.typeRep(file, compoundResults.dir, compoundResults.typ), "\n")
@@ -1194,7 +1713,7 @@ func (g *generator) defineExportedFunction(_ wit.Ident, f *wit.Function, decl fu
 				if i > 0 {
 					b.WriteString(", ")
 				}
-				stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
+				stringio.Write(&b, compoundResults.name, ".", g.fieldName(f.Name, false))
 			}
 			b.WriteString(" = ")
 		} else {
@@ -1207,26 +1726,42 @@ func (g *generator) defineExportedFunction(_ wit.Ident, f *wit.Function, decl fu
 			b.WriteString(" := ")
 		}
 	}
+	lowerOption := len(decl.f.results) == 1 && decl.f.results[0].option != nil && !sameResults
+	if lowerOption {
+		stringio.Write(&b, file.Import(g.opts.cmPackage), ".OptionOf(")
+	}
 	stringio.Write(&b, decl.f.name, "(")
 	if paramsRecord != nil {
 		for i, f := range paramsRecord.Fields {
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			stringio.Write(&b, compoundParams.name, ".", fieldName(f.Name, false))
+			b.WriteString(liftOptionPointer(decl.f.params[i], compoundParams.name+"."+g.fieldName(f.Name, false)))
 		}
 	} else {
 		for i, p := range decl.wasm.params {
 			if i > 0 {
 				b.WriteString(", ")
 			}
+			if i < len(decl.f.params) && decl.f.params[i].option != nil {
+				b.WriteString(liftOptionPointer(decl.f.params[i], p.name))
+				continue
+			}
 			if isPointer(p.typ) {
 				b.WriteRune('*')
 			}
+			if isBool(p.typ) {
+				b.WriteString(g.boolFromU32(file, p.dir, p.typ, p.name))
+				continue
+			}
 			b.WriteString(p.name)
 		}
 	}
-	b.WriteString(")\n")
+	b.WriteString(")")
+	if lowerBool || lowerChar || lowerOption {
+		b.WriteString(")")
+	}
+	b.WriteString("\n")
 	if !sameResults {
 		b.WriteString("return ")
 		if resultsRecord != nil {
@@ -1264,22 +1799,56 @@ func (g *generator) defineExportedFunction(_ wit.Ident, f *wit.Function, decl fu
 
 	// Write to file
 	file.Write(b.Bytes())
+	g.callPlugins(file, func(p Plugin, file *File) error {
+		return p.Function(file, dir, f, decl.f.name)
+	})
 
 	return g.ensureEmptyAsm(file.Package)
 }
 
-func (g *generator) functionSignature(file *gen.File, f function) string {
-	var b strings.Builder
+// boolFromU32 returns Go code that converts v, a u32 lifted from Core WebAssembly,
+// into t, a bool or another type represented as a Go bool, such as a named bool type
+// or a result with no payloads.
+func (g *generator) boolFromU32(file *gen.File, dir wit.Direction, t wit.Type, v string) string {
+	s := file.Import(g.opts.cmPackage) + ".U32ToBool(" + v + ")"
+	if _, ok := t.(wit.Bool); ok {
+		return s
+	}
+	return g.typeRep(file, dir, t) + "(" + s + ")"
+}
 
-	b.WriteRune('(')
+// lowerChar returns Go code that validates v, a char or another type represented as
+// a Go rune, such as a named char type, for lowering into Core WebAssembly.
+// If t is not a char, it returns v.
+func (g *generator) lowerChar(file *gen.File, t wit.Type, v string) string {
+	if !isChar(t) {
+		return v
+	}
+	return file.Import(g.opts.cmPackage) + ".LowerChar(" + v + ")"
+}
 
-	// Emit params
-	for i, p := range f.params {
-		if i > 0 {
-			b.WriteString(", ")
+// coreBools returns a copy of Core WebAssembly function f with bool params and results
+// represented as u32, which are converted with cm.BoolToU32 and cm.U32ToBool.
+// This includes other types represented as a Go bool, such as results with no payloads,
+// which are flattened to a single i32.
+func coreBools(f function) function {
+	f.params = slices.Clone(f.params)
+	f.results = slices.Clone(f.results)
+	for _, params := range [][]param{f.params, f.results} {
+		for i := range params {
+			if isBool(params[i].typ) {
+				params[i].typ = wit.U32{}
+			}
 		}
-		stringio.Write(&b, p.name, " ", g.typeRep(file, p.dir, p.typ))
 	}
+	return f
+}
+
+func (g *generator) functionSignature(file *gen.File, f function) string {
+	var b strings.Builder
+
+	b.WriteRune('(')
+	b.WriteString(g.paramList(file, f))
 	b.WriteString(") ")
 
 	// Emit results
@@ -1299,6 +1868,22 @@ func (g *generator) functionSignature(file *gen.File, f function) string {
 	return b.String()
 }
 
+// paramList returns the comma-separated params of f with their Go types,
+// including its context param, if any.
+func (g *generator) paramList(file *gen.File, f function) string {
+	var b strings.Builder
+	if f.ctx != "" {
+		stringio.Write(&b, f.ctx, " ", file.Import("context"), ".Context")
+	}
+	for _, p := range f.params {
+		if b.Len() > 0 {
+			b.WriteString(", ")
+		}
+		stringio.Write(&b, p.name, " ", g.typeRep(file, p.dir, p.typ))
+	}
+	return b.String()
+}
+
 func last[S ~[]E, E any](s S) *E {
 	if len(s) == 0 {
 		return nil
@@ -1360,9 +1945,42 @@ func (g *generator) functionDocs(dir wit.Direction, f *wit.Function, goName stri
 		w := strings.TrimSuffix(f.WIT(nil, f.BaseName()), ";")
 		b.WriteString(formatDocComments(w, true))
 	}
+	b.WriteString(deprecatedDocs(f.WITKind(), f.Stability))
+	b.WriteString(g.sourceDocs(f))
 	return b.String()
 }
 
+// writeDeprecatedPackageDocs appends a paragraph that marks a package as deprecated
+// to package docs b, if stability is deprecated.
+func writeDeprecatedPackageDocs(b *strings.Builder, kind string, stability *wit.Stability) {
+	if !stability.IsDeprecated() {
+		return
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteString("\n")
+	}
+	stringio.Write(b, "\nDeprecated: this WIT ", kind, " is deprecated as of version ", stability.Deprecated.String(), ".\n")
+}
+
+// deprecatedDocs returns a doc comment paragraph that marks a declaration as deprecated,
+// recognized by Go tools such as gopls and staticcheck, if stability is deprecated.
+func deprecatedDocs(kind string, stability *wit.Stability) string {
+	if !stability.IsDeprecated() {
+		return ""
+	}
+	return "//\n// Deprecated: this WIT " + kind + " is deprecated as of version " + stability.Deprecated.String() + ".\n"
+}
+
+// sourceDocs returns a doc comment paragraph with the location of the WIT source of node,
+// or an empty string if the location is not known.
+func (g *generator) sourceDocs(node wit.Node) string {
+	pos, ok := g.opts.sources[node]
+	if !ok {
+		return ""
+	}
+	return "//\n// Source: " + pos.String() + "\n"
+}
+
 func (g *generator) ensureEmptyAsm(pkg *gen.Package) error {
 	f := pkg.File("empty.s")
 	if len(f.Content) > 0 {
@@ -1374,9 +1992,17 @@ func (g *generator) ensureEmptyAsm(pkg *gen.Package) error {
 
 func (g *generator) fileFor(id wit.Ident) *gen.File {
 	pkg := g.packageFor(id)
-	file := pkg.File(id.Extension + GoSuffix)
+	file := pkg.File(path.Base(id.Extension) + GoSuffix)
 	file.GeneratedBy = g.opts.generatedBy
-	file.Build = BuildDefault
+	var build string
+	if !g.opts.host && g.opts.target != TargetWASIP1 {
+		build = BuildDefault
+	}
+	if tag := g.buildTag(id); tag != "" {
+		build = joinBuild(build, tag)
+		g.packageBuildTags[pkg] = tag
+	}
+	file.Build = build
 	return file
 }
 
@@ -1388,6 +2014,8 @@ func (g *generator) packageFor(id wit.Ident) *gen.Package {
 	}
 
 	// Create the package path and name
+	// The extension of an anonymous interface declared in a world is a path, e.g. world/item.
+	base := path.Base(id.Extension)
 	var segments []string
 	if g.opts.packageRoot != "" && g.opts.packageRoot != "std" {
 		segments = append(segments, g.opts.packageRoot)
@@ -1400,7 +2028,7 @@ func (g *generator) packageFor(id wit.Ident) *gen.Package {
 	path := strings.Join(segments, "/")
 
 	// TODO: write tests for this
-	name := GoPackageName(id.Extension)
+	name := GoPackageName(base)
 	// Ensure local name doesn’t conflict with Go keywords or predeclared identifiers
 	if gen.UniqueName(name, gen.IsReserved) != name {
 		// Try with package prefix, like error -> ioerror
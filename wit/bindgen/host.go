@@ -0,0 +1,812 @@
+package bindgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+const (
+	wazeroPackage    = "github.com/tetratelabs/wazero"
+	wazeroAPIPackage = "github.com/tetratelabs/wazero/api"
+	hostABIFile      = "host_abi" + GoSuffix
+)
+
+// errUnsupportedHost is returned when generating host bindings for a function
+// with a param or result type that cannot be lifted or lowered by host bindings.
+var errUnsupportedHost = errors.New("unsupported by host bindings")
+
+// defineHostWorlds defines host bindings for the world(s) selected by the generator options.
+// Host bindings implement the imports of a world with [wazero] host modules,
+// dispatching calls from a guest to a Go implementation of each imported interface.
+//
+// [wazero]: https://wazero.io
+func (g *generator) defineHostWorlds() error {
+	for i, w := range g.res.Worlds {
+		if matchWorld(w, g.opts.world) || (g.opts.world == "" && i == len(g.res.Worlds)-1) {
+			err := g.defineHostWorld(w)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) defineHostWorld(w *wit.World) error {
+	if !g.define(wit.Imported, w) {
+		return nil
+	}
+	if g.world == nil {
+		g.world = w
+	}
+	id := w.ID()
+	g.opts.logger.Debug("generating host world", "world", id.String())
+
+	var funcs []*wit.Function
+	var err error
+	w.Imports.All()(func(name string, v wit.WorldItem) bool {
+		switch v := v.(type) {
+		case *wit.Interface:
+			err = g.defineHostInterface(v, name)
+		case *wit.TypeDef:
+			err = g.defineHostTypeDef(v)
+			if f := v.ResourceDrop(); f != nil {
+				funcs = append(funcs, f)
+			}
+		case *wit.Function:
+			funcs = append(funcs, v)
+		}
+		return err == nil
+	})
+	if err != nil || len(funcs) == 0 {
+		return err
+	}
+
+	return g.defineHostModule(id, w.WITKind(), w.Docs, funcs)
+}
+
+func (g *generator) defineHostInterface(i *wit.Interface, name string) error {
+	if !g.define(wit.Imported, i) {
+		return nil
+	}
+	id := g.interfaceID(i, name)
+
+	var funcs []*wit.Function
+	var err error
+	i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+		err = g.defineHostTypeDef(t)
+		if f := t.ResourceDrop(); f != nil {
+			funcs = append(funcs, f)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		funcs = append(funcs, f)
+		return true
+	})
+
+	return g.defineHostModule(id, i.WITKind(), i.Docs, funcs)
+}
+
+// defineHostTypeDef defines the Go type for t if t is a named type that
+// can be represented in host bindings, such as an enum or flags.
+// Other types are either represented inline, or are not supported.
+func (g *generator) defineHostTypeDef(t *wit.TypeDef) error {
+	if t.Root() != t || !isHostNamedType(t) {
+		return nil
+	}
+	if !g.define(wit.Imported, t) {
+		return nil
+	}
+	dir := wit.Imported
+	decl, err := g.declareTypeDef(nil, dir, t, "")
+	if err != nil {
+		return err
+	}
+
+	owner := g.typeDefOwner(t)
+
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", decl.name, " represents the ", dir.String(), " ", t.WITKind(), " \"", owner.String(), "#", *t.Name, "\".\n")
+	b.WriteString("//\n")
+	b.WriteString(formatDocComments(t.Docs.Contents, false))
+	b.WriteString("//\n")
+	b.WriteString(formatDocComments(t.WIT(nil, ""), true))
+	stringio.Write(&b, "type ", decl.name, " ", g.typeDefRep(decl.file, dir, t, decl.name), "\n\n")
+
+	_, err = decl.file.Write(b.Bytes())
+	return err
+}
+
+func (g *generator) defineHostModule(id wit.Ident, kind string, docs wit.Docs, funcs []*wit.Function) error {
+	for _, f := range funcs {
+		if !isHostFunction(f) {
+			return fmt.Errorf("function %q in %s %s: %w: only primitive, enum, flags, and list types are supported", f.Name, kind, id.String(), errUnsupportedHost)
+		}
+	}
+
+	pkg := g.packageFor(id)
+	file := g.fileFor(id)
+
+	{
+		var b strings.Builder
+		stringio.Write(&b, "Package ", pkg.Name, " represents the host implementation of the imported ", kind, " \"", id.String(), "\".\n")
+		if docs.Contents != "" {
+			b.WriteString("\n")
+			b.WriteString(docs.Contents)
+		}
+		file.PackageDocs = b.String()
+	}
+
+	wasmtime := g.opts.hostRuntime == HostWasmtime
+	context := file.Import("context")
+	moduleName := file.DeclareName("ModuleName")
+	hostName := file.DeclareName("Host")
+	methods := gen.NewScope(nil)
+
+	var b bytes.Buffer
+
+	// Emit module name
+	stringio.Write(&b, "// ", moduleName, " is the Core WebAssembly module name for the imported ", kind, " \"", id.String(), "\".\n")
+	stringio.Write(&b, "const ", moduleName, " = ", strconv.Quote(g.moduleName(id)), "\n\n")
+
+	// Emit host interface
+	var hb bytes.Buffer
+	stringio.Write(&hb, "// ", hostName, " represents the host implementation of the imported ", kind, " \"", id.String(), "\".\n")
+	stringio.Write(&hb, "type ", hostName, " interface {\n")
+
+	// Emit the Instantiate (wazero) or Define (wasmtime) function
+	var ib bytes.Buffer
+	var api, rt string // runtime package names
+	if wasmtime {
+		rt = file.Import(wasmtimePackage)
+		defineName := file.DeclareName("Define")
+		stringio.Write(&ib, "// ", defineName, " defines the functions of the imported ", kind, " \"", id.String(), "\"\n")
+		stringio.Write(&ib, "// in wasmtime linker l, dispatching calls to h.\n")
+		stringio.Write(&ib, "func ", defineName, "(ctx ", context, ".Context, l *", rt, ".Linker, h ", hostName, ") error {\n")
+	} else {
+		rt = file.Import(wazeroPackage)
+		api = file.Import(wazeroAPIPackage)
+		instantiateName := file.DeclareName("Instantiate")
+		stringio.Write(&ib, "// ", instantiateName, " instantiates a host module for the imported ", kind, " \"", id.String(), "\"\n")
+		stringio.Write(&ib, "// in runtime r, dispatching calls to h.\n")
+		stringio.Write(&ib, "func ", instantiateName, "(ctx ", context, ".Context, r ", rt, ".Runtime, h ", hostName, ") (", api, ".Module, error) {\n")
+		stringio.Write(&ib, "b := r.NewHostModuleBuilder(", moduleName, ")\n")
+	}
+
+	for _, f := range funcs {
+		scope := gen.NewScope(file)
+		for _, name := range []string{"ctx", "r", "h", "b", "l", "err", "mod", "stack", "caller", "args", "trap", "mem"} {
+			scope.DeclareName(name)
+		}
+		params := g.goParams(scope, wit.Imported, f.Params)
+		results := g.goParams(scope, wit.Imported, f.Results)
+		if len(results) == 1 && results[0].name == "" {
+			results[0].name = scope.DeclareName("result")
+		}
+
+		methodName := methods.DeclareName(g.hostMethodName(f))
+		hb.WriteString(g.functionDocs(wit.Imported, f, methodName))
+		stringio.Write(&hb, methodName, "(ctx ", context, ".Context")
+		for _, p := range params {
+			stringio.Write(&hb, ", ", p.name, " ", g.hostTypeRep(file, p.typ))
+		}
+		hb.WriteString(") ")
+		if len(results) == 1 {
+			hb.WriteString(g.hostTypeRep(file, results[0].typ))
+		} else if len(results) > 0 {
+			hb.WriteRune('(')
+			for i, r := range results {
+				if i > 0 {
+					hb.WriteString(", ")
+				}
+				stringio.Write(&hb, r.name, " ", g.hostTypeRep(file, r.typ))
+			}
+			hb.WriteRune(')')
+		}
+		hb.WriteString("\n\n")
+
+		// Core function signature
+		sig := f.CoreSignature(wit.Imported)
+		spillParams := len(hostFlat(params)) > wit.MaxFlatParams
+		spillResults := len(hostFlat(results)) > wit.MaxFlatResults
+
+		// slot returns a Go expression for the Core WebAssembly param at index i.
+		slot := func(i int) string {
+			if wasmtime {
+				return "args[" + strconv.Itoa(i) + "]"
+			}
+			return "stack[" + strconv.Itoa(i) + "]"
+		}
+		// slotU32 returns a Go expression for the i32 param at index i as a uint32.
+		slotU32 := func(i int) string {
+			if wasmtime {
+				return "uint32(" + slot(i) + ".I32())"
+			}
+			return "uint32(" + slot(i) + ")"
+		}
+
+		if wasmtime {
+			stringio.Write(&ib, "if err := l.FuncNew(", moduleName, ", ", strconv.Quote(f.Name), ", ", wasmtimeFuncType(rt, sig), ", ")
+			stringio.Write(&ib, "func(caller *", rt, ".Caller, args []", rt, ".Val) (_ []", rt, ".Val, trap *", rt, ".Trap) {\n")
+			ib.WriteString("defer hostRecover(&trap)\n")
+		} else {
+			stringio.Write(&ib, "b.NewFunctionBuilder().WithGoModuleFunction(", api, ".GoModuleFunc(func(ctx ", context, ".Context, mod ", api, ".Module, stack []uint64) {\n")
+		}
+
+		var fb bytes.Buffer
+
+		// Lift params
+		if spillParams {
+			ptr := scope.DeclareName("params")
+			stringio.Write(&fb, ptr, " := ", slotU32(0), "\n")
+			offsets := hostOffsets(params)
+			for i, p := range params {
+				stringio.Write(&fb, p.name, " := ", g.hostLoad(file, p.typ, offsetExpr(ptr, offsets[i])), "\n")
+			}
+		} else {
+			n := 0
+			for _, p := range params {
+				slots := make([]string, len(p.typ.Flat()))
+				for i := range slots {
+					slots[i] = slot(n + i)
+				}
+				n += len(slots)
+				if wasmtime {
+					stringio.Write(&fb, p.name, " := ", g.wasmtimeLiftFlat(file, p.typ, slots), "\n")
+				} else {
+					stringio.Write(&fb, p.name, " := ", g.hostLiftFlat(file, api, p.typ, slots), "\n")
+				}
+			}
+		}
+
+		var retptr string
+		if spillResults {
+			retptr = scope.DeclareName("retptr")
+			stringio.Write(&fb, retptr, " := ", slotU32(len(sig.Params)-1), "\n")
+		}
+
+		// Call host implementation
+		for i, r := range results {
+			if i > 0 {
+				fb.WriteString(", ")
+			}
+			fb.WriteString(r.name)
+		}
+		if len(results) > 0 {
+			fb.WriteString(" := ")
+		}
+		stringio.Write(&fb, "h.", methodName, "(ctx")
+		for _, p := range params {
+			stringio.Write(&fb, ", ", p.name)
+		}
+		fb.WriteString(")\n")
+
+		// Lower results
+		if spillResults {
+			offsets := hostOffsets(results)
+			for i, r := range results {
+				fb.WriteString(g.hostStore(file, r.typ, offsetExpr(retptr, offsets[i]), r.name))
+				fb.WriteRune('\n')
+			}
+		} else if len(results) > 0 {
+			if wasmtime {
+				stringio.Write(&fb, "return []", rt, ".Val{", g.wasmtimeLowerFlat(file, rt, results[0].typ, results[0].name), "}, nil\n")
+			} else {
+				stringio.Write(&fb, "stack[0] = ", g.hostLowerFlat(file, api, results[0].typ, results[0].name), "\n")
+			}
+		}
+
+		if usesMem.Match(fb.Bytes()) {
+			if wasmtime {
+				ib.WriteString("mem := hostMemoryOf(caller)\n")
+			} else {
+				ib.WriteString("mem := mod.Memory()\n")
+			}
+		}
+		ib.Write(fb.Bytes())
+		if wasmtime {
+			if spillResults || len(results) == 0 {
+				ib.WriteString("return nil, nil\n")
+			}
+			ib.WriteString("}); err != nil {\nreturn err\n}\n")
+		} else {
+			stringio.Write(&ib, "}), ", hostValueTypes(api, sig.Params), ", ", hostValueTypes(api, sig.Results), ").\n")
+			stringio.Write(&ib, "Export(", strconv.Quote(f.Name), ")\n")
+		}
+	}
+
+	hb.WriteString("}\n\n")
+	if wasmtime {
+		ib.WriteString("return nil\n")
+	} else {
+		ib.WriteString("return b.Instantiate(ctx)\n")
+	}
+	ib.WriteString("}\n\n")
+
+	b.Write(hb.Bytes())
+	b.Write(ib.Bytes())
+
+	_, err := file.Write(b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return g.ensureHostABI(pkg)
+}
+
+// usesMem matches generated code that refers to guest memory.
+var usesMem = regexp.MustCompile(`\bmem\b`)
+
+// hostMethodName returns the Go method name for f in a generated Host interface.
+func (g *generator) hostMethodName(f *wit.Function) string {
+	if f.IsFreestanding() {
+		return g.goName(f.Name, true)
+	}
+	return g.goName(f.Type().TypeName(), true) + g.goName(f.BaseName(), true)
+}
+
+// isHostNamedType returns true if t is represented as a named Go type in host bindings.
+func isHostNamedType(t *wit.TypeDef) bool {
+	switch kind := t.Kind.(type) {
+	case *wit.Enum:
+		return true
+	case *wit.Flags:
+		return kind.Size() <= 4
+	case *wit.Variant:
+		return kind.Enum() != nil
+	}
+	return false
+}
+
+// isHostFunction returns true if all params and results of f
+// can be lifted and lowered by host bindings.
+func isHostFunction(f *wit.Function) bool {
+	for _, p := range f.Params {
+		if !isHostType(p.Type) {
+			return false
+		}
+	}
+	for _, r := range f.Results {
+		if !isHostType(r.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostType returns true if t can be lifted and lowered by host bindings.
+// Supported types are primitive types, enums, flags with up to 32 flags,
+// and lists of supported types.
+func isHostType(t wit.Type) bool {
+	switch t := hostRoot(t).(type) {
+	case wit.Primitive:
+		return true
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			return isHostType(l.Type)
+		}
+		return isHostNamedType(t)
+	}
+	return false
+}
+
+// hostRoot returns the root [wit.TypeDef] of t, or the underlying
+// [wit.Primitive] type if t is an alias of a primitive type.
+func hostRoot(t wit.Type) wit.Type {
+	if td, ok := t.(*wit.TypeDef); ok {
+		td = td.Root()
+		if p, ok := td.Kind.(wit.Primitive); ok {
+			return p
+		}
+		return td
+	}
+	return t
+}
+
+// hostTypeRep returns the Go representation of t in host bindings.
+func (g *generator) hostTypeRep(file *gen.File, t wit.Type) string {
+	switch t := hostRoot(t).(type) {
+	case wit.Primitive:
+		return g.primitiveRep(t)
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			return "[]" + g.hostTypeRep(file, l.Type)
+		}
+	}
+	return g.typeRep(file, wit.Imported, t)
+}
+
+// hostLiftFlat returns a Go expression that lifts a value of type t
+// from Core WebAssembly values in stack slots.
+func (g *generator) hostLiftFlat(file *gen.File, api string, t wit.Type, slots []string) string {
+	switch t := hostRoot(t).(type) {
+	case wit.Bool:
+		return file.Import(g.opts.cmPackage) + ".U32ToBool(" + api + ".DecodeU32(" + slots[0] + "))"
+	case wit.U64:
+		return slots[0]
+	case wit.F32:
+		return api + ".DecodeF32(" + slots[0] + ")"
+	case wit.F64:
+		return api + ".DecodeF64(" + slots[0] + ")"
+	case wit.String:
+		return "hostLoadString(mem, uint32(" + slots[0] + "), uint32(" + slots[1] + "))"
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			return g.hostLoadList(file, l, "uint32("+slots[0]+")", "uint32("+slots[1]+")")
+		}
+	}
+	return g.hostTypeRep(file, t) + "(" + slots[0] + ")"
+}
+
+// hostLowerFlat returns a Go expression that lowers v of type t into a single Core WebAssembly value.
+func (g *generator) hostLowerFlat(file *gen.File, api string, t wit.Type, v string) string {
+	switch hostRoot(t).(type) {
+	case wit.Bool:
+		return "uint64(" + file.Import(g.opts.cmPackage) + ".BoolToU32(" + v + "))"
+	case wit.S8, wit.S16, wit.S32:
+		return api + ".EncodeI32(int32(" + v + "))"
+	case wit.S64:
+		return api + ".EncodeI64(" + v + ")"
+	case wit.U64:
+		return v
+	case wit.Char:
+		return api + ".EncodeU32(uint32(" + g.lowerChar(file, t, v) + "))"
+	case wit.F32:
+		return api + ".EncodeF32(" + v + ")"
+	case wit.F64:
+		return api + ".EncodeF64(" + v + ")"
+	}
+	return api + ".EncodeU32(uint32(" + v + "))"
+}
+
+// hostLoad returns a Go expression that loads a value of type t from guest memory at addr.
+func (g *generator) hostLoad(file *gen.File, t wit.Type, addr string) string {
+	switch t := hostRoot(t).(type) {
+	case wit.Bool:
+		return file.Import(g.opts.cmPackage) + ".U32ToBool(uint32(hostLoadU8(mem, " + addr + ")))"
+	case wit.S8:
+		return "int8(hostLoadU8(mem, " + addr + "))"
+	case wit.U8:
+		return "hostLoadU8(mem, " + addr + ")"
+	case wit.S16:
+		return "int16(hostLoadU16(mem, " + addr + "))"
+	case wit.U16:
+		return "hostLoadU16(mem, " + addr + ")"
+	case wit.S32:
+		return "int32(hostLoadU32(mem, " + addr + "))"
+	case wit.U32:
+		return "hostLoadU32(mem, " + addr + ")"
+	case wit.S64:
+		return "int64(hostLoadU64(mem, " + addr + "))"
+	case wit.U64:
+		return "hostLoadU64(mem, " + addr + ")"
+	case wit.F32:
+		return "hostLoadF32(mem, " + addr + ")"
+	case wit.F64:
+		return "hostLoadF64(mem, " + addr + ")"
+	case wit.Char:
+		return "rune(hostLoadU32(mem, " + addr + "))"
+	case wit.String:
+		return "hostLoadString(mem, hostLoadU32(mem, " + addr + "), hostLoadU32(mem, " + offsetExpr(addr, 4) + "))"
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			return g.hostLoadList(file, l, "hostLoadU32(mem, "+addr+")", "hostLoadU32(mem, "+offsetExpr(addr, 4)+")")
+		}
+		return g.hostTypeRep(file, t) + "(hostLoadU" + strconv.Itoa(int(t.Size()*8)) + "(mem, " + addr + "))"
+	}
+	panic("BUG: unsupported host type " + t.TypeName())
+}
+
+func (g *generator) hostLoadList(file *gen.File, l *wit.List, ptr, n string) string {
+	var b strings.Builder
+	elem := g.hostTypeRep(file, l.Type)
+	size := strconv.Itoa(int(l.Type.Size()))
+	stringio.Write(&b, "hostLoadList(mem, ", ptr, ", ", n, ", ", size, ", func(ptr uint32) ", elem, " {\n")
+	stringio.Write(&b, "return ", g.hostLoad(file, l.Type, "ptr"), "\n")
+	b.WriteString("})")
+	return b.String()
+}
+
+// hostStore returns a Go statement that stores v of type t into guest memory at addr.
+func (g *generator) hostStore(file *gen.File, t wit.Type, addr, v string) string {
+	switch t := hostRoot(t).(type) {
+	case wit.Bool:
+		return "hostStoreU8(mem, " + addr + ", uint8(" + file.Import(g.opts.cmPackage) + ".BoolToU32(" + v + ")))"
+	case wit.S8, wit.U8:
+		return "hostStoreU8(mem, " + addr + ", uint8(" + v + "))"
+	case wit.S16, wit.U16:
+		return "hostStoreU16(mem, " + addr + ", uint16(" + v + "))"
+	case wit.S32, wit.U32:
+		return "hostStoreU32(mem, " + addr + ", uint32(" + v + "))"
+	case wit.Char:
+		return "hostStoreU32(mem, " + addr + ", uint32(" + g.lowerChar(file, t, v) + "))"
+	case wit.S64, wit.U64:
+		return "hostStoreU64(mem, " + addr + ", uint64(" + v + "))"
+	case wit.F32:
+		return "hostStoreF32(mem, " + addr + ", " + v + ")"
+	case wit.F64:
+		return "hostStoreF64(mem, " + addr + ", " + v + ")"
+	case wit.String:
+		return "hostStoreString(" + g.hostCaller() + ", " + addr + ", " + v + ")"
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			var b strings.Builder
+			elem := g.hostTypeRep(file, l.Type)
+			size := strconv.Itoa(int(l.Type.Size()))
+			align := strconv.Itoa(int(l.Type.Align()))
+			stringio.Write(&b, "hostStoreList(", g.hostCaller(), ", ", addr, ", ", v, ", ", size, ", ", align, ", func(ptr uint32, v ", elem, ") {\n")
+			stringio.Write(&b, g.hostStore(file, l.Type, "ptr", "v"), "\n")
+			b.WriteString("})")
+			return b.String()
+		}
+		bits := strconv.Itoa(int(t.Size() * 8))
+		return "hostStoreU" + bits + "(mem, " + addr + ", uint" + bits + "(" + v + "))"
+	}
+	panic("BUG: unsupported host type " + t.TypeName())
+}
+
+// hostCaller returns the arguments that identify the calling guest instance
+// to the generated helpers that call its cabi_realloc function.
+func (g *generator) hostCaller() string {
+	if g.opts.hostRuntime == HostWasmtime {
+		return "caller"
+	}
+	return "ctx, mod"
+}
+
+// hostFlat returns the flattened Core WebAssembly types for params.
+func hostFlat(params []param) []wit.Type {
+	var flat []wit.Type
+	for _, p := range params {
+		flat = append(flat, p.typ.Flat()...)
+	}
+	return flat
+}
+
+// hostOffsets returns the memory offsets of params
+// laid out as a record in linear memory.
+func hostOffsets(params []param) []uintptr {
+	offsets := make([]uintptr, len(params))
+	var offset uintptr
+	for i, p := range params {
+		offset = wit.Align(offset, p.typ.Align())
+		offsets[i] = offset
+		offset += p.typ.Size()
+	}
+	return offsets
+}
+
+func offsetExpr(base string, offset uintptr) string {
+	if offset == 0 {
+		return base
+	}
+	return base + "+" + strconv.Itoa(int(offset))
+}
+
+// hostValueTypes returns a Go expression for a slice of wazero value types for types.
+func hostValueTypes(api string, types []wit.CoreType) string {
+	if len(types) == 0 {
+		return "nil"
+	}
+	var b strings.Builder
+	stringio.Write(&b, "[]", api, ".ValueType{")
+	for i, t := range types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch t {
+		case wit.CoreF32:
+			stringio.Write(&b, api, ".ValueTypeF32")
+		case wit.CoreF64:
+			stringio.Write(&b, api, ".ValueTypeF64")
+		case wit.CoreI64:
+			stringio.Write(&b, api, ".ValueTypeI64")
+		default:
+			stringio.Write(&b, api, ".ValueTypeI32")
+		}
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+// ensureHostABI emits the helper functions used by host bindings in pkg.
+func (g *generator) ensureHostABI(pkg *gen.Package) error {
+	file := pkg.File(hostABIFile)
+	if len(file.Content) > 0 {
+		return nil
+	}
+	file.GeneratedBy = g.opts.generatedBy
+	file.Build = g.packageBuildTags[pkg]
+	if g.opts.hostRuntime == HostWasmtime {
+		return g.ensureWasmtimeABI(file)
+	}
+	r := strings.NewReplacer(
+		"context.", file.Import("context")+".",
+		"api.", file.Import(wazeroAPIPackage)+".",
+	)
+	_, err := r.WriteString(file, g.internHostStrings(file, hostABI))
+	return err
+}
+
+// internHostStrings returns host ABI helpers abi, modified to intern lifted strings
+// if the [InternStrings] option is set.
+func (g *generator) internHostStrings(file *gen.File, abi string) string {
+	if !g.opts.internStrings {
+		return abi
+	}
+	return strings.Replace(abi, "\treturn string(", "\treturn "+file.Import(g.opts.cmPackage)+".InternBytes(", 1)
+}
+
+const hostABI = `
+// hostLoadU8 loads a uint8 from guest memory at ptr.
+func hostLoadU8(mem api.Memory, ptr uint32) uint8 {
+	v, ok := mem.ReadByte(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadU16 loads a little-endian uint16 from guest memory at ptr.
+func hostLoadU16(mem api.Memory, ptr uint32) uint16 {
+	v, ok := mem.ReadUint16Le(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadU32 loads a little-endian uint32 from guest memory at ptr.
+func hostLoadU32(mem api.Memory, ptr uint32) uint32 {
+	v, ok := mem.ReadUint32Le(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadU64 loads a little-endian uint64 from guest memory at ptr.
+func hostLoadU64(mem api.Memory, ptr uint32) uint64 {
+	v, ok := mem.ReadUint64Le(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadF32 loads a little-endian float32 from guest memory at ptr.
+func hostLoadF32(mem api.Memory, ptr uint32) float32 {
+	v, ok := mem.ReadFloat32Le(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadF64 loads a little-endian float64 from guest memory at ptr.
+func hostLoadF64(mem api.Memory, ptr uint32) float64 {
+	v, ok := mem.ReadFloat64Le(ptr)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return v
+}
+
+// hostLoadString copies a string of n bytes from guest memory at ptr.
+func hostLoadString(mem api.Memory, ptr, n uint32) string {
+	b, ok := mem.Read(ptr, n)
+	if !ok {
+		panic(hostOutOfRange)
+	}
+	return string(b)
+}
+
+// hostLoadList lifts a list of n elements of size bytes each from guest memory at ptr.
+func hostLoadList[T any](mem api.Memory, ptr, n, size uint32, load func(ptr uint32) T) []T {
+	if n == 0 {
+		return nil
+	}
+	if uint64(ptr)+uint64(n)*uint64(size) > uint64(mem.Size()) {
+		panic(hostOutOfRange)
+	}
+	s := make([]T, n)
+	for i := range s {
+		s[i] = load(ptr + uint32(i)*size)
+	}
+	return s
+}
+
+// hostStoreU8 stores a uint8 into guest memory at ptr.
+func hostStoreU8(mem api.Memory, ptr uint32, v uint8) {
+	if !mem.WriteByte(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreU16 stores a little-endian uint16 into guest memory at ptr.
+func hostStoreU16(mem api.Memory, ptr uint32, v uint16) {
+	if !mem.WriteUint16Le(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreU32 stores a little-endian uint32 into guest memory at ptr.
+func hostStoreU32(mem api.Memory, ptr uint32, v uint32) {
+	if !mem.WriteUint32Le(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreU64 stores a little-endian uint64 into guest memory at ptr.
+func hostStoreU64(mem api.Memory, ptr uint32, v uint64) {
+	if !mem.WriteUint64Le(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreF32 stores a little-endian float32 into guest memory at ptr.
+func hostStoreF32(mem api.Memory, ptr uint32, v float32) {
+	if !mem.WriteFloat32Le(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreF64 stores a little-endian float64 into guest memory at ptr.
+func hostStoreF64(mem api.Memory, ptr uint32, v float64) {
+	if !mem.WriteFloat64Le(ptr, v) {
+		panic(hostOutOfRange)
+	}
+}
+
+// hostStoreString copies s into memory allocated by the guest,
+// and stores its pointer and length into guest memory at ptr.
+func hostStoreString(ctx context.Context, mod api.Module, ptr uint32, s string) {
+	data := hostRealloc(ctx, mod, uint32(len(s)), 1)
+	mem := mod.Memory()
+	if !mem.WriteString(data, s) {
+		panic(hostOutOfRange)
+	}
+	hostStoreU32(mem, ptr, data)
+	hostStoreU32(mem, ptr+4, uint32(len(s)))
+}
+
+// hostStoreList lowers s into memory allocated by the guest,
+// and stores its pointer and length into guest memory at ptr.
+func hostStoreList[T any](ctx context.Context, mod api.Module, ptr uint32, s []T, size, align uint32, store func(ptr uint32, v T)) {
+	data := hostRealloc(ctx, mod, uint32(len(s))*size, align)
+	for i := range s {
+		store(data+uint32(i)*size, s[i])
+	}
+	mem := mod.Memory()
+	hostStoreU32(mem, ptr, data)
+	hostStoreU32(mem, ptr+4, uint32(len(s)))
+}
+
+// hostRealloc allocates size bytes with alignment align in guest memory
+// by calling the cabi_realloc function exported by the guest.
+func hostRealloc(ctx context.Context, mod api.Module, size, align uint32) uint32 {
+	realloc := mod.ExportedFunction("cabi_realloc")
+	if realloc == nil {
+		panic("guest does not export cabi_realloc")
+	}
+	results, err := realloc.Call(ctx, 0, 0, uint64(align), uint64(size))
+	if err != nil {
+		panic(err)
+	}
+	return uint32(results[0])
+}
+
+const hostOutOfRange = "out of range guest memory access"
+`
@@ -0,0 +1,247 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+const wasmtimePackage = "github.com/bytecodealliance/wasmtime-go/v22#wasmtime"
+
+// wasmtimeFuncType returns a Go expression for the wasmtime function type of sig.
+func wasmtimeFuncType(rt string, sig wit.CoreSignature) string {
+	return rt + ".NewFuncType(" + wasmtimeValTypes(rt, sig.Params) + ", " + wasmtimeValTypes(rt, sig.Results) + ")"
+}
+
+// wasmtimeValTypes returns a Go expression for a slice of wasmtime value types for types.
+func wasmtimeValTypes(rt string, types []wit.CoreType) string {
+	if len(types) == 0 {
+		return "nil"
+	}
+	var b strings.Builder
+	stringio.Write(&b, "[]*", rt, ".ValType{")
+	for i, t := range types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		kind := "KindI32"
+		switch t {
+		case wit.CoreF32:
+			kind = "KindF32"
+		case wit.CoreF64:
+			kind = "KindF64"
+		case wit.CoreI64:
+			kind = "KindI64"
+		}
+		stringio.Write(&b, rt, ".NewValType(", rt, ".", kind, ")")
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+// wasmtimeLiftFlat returns a Go expression that lifts a value of type t
+// from the wasmtime values in args slots.
+func (g *generator) wasmtimeLiftFlat(file *gen.File, t wit.Type, slots []string) string {
+	switch t := hostRoot(t).(type) {
+	case wit.Bool:
+		return file.Import(g.opts.cmPackage) + ".U32ToBool(uint32(" + slots[0] + ".I32()))"
+	case wit.S64:
+		return slots[0] + ".I64()"
+	case wit.U64:
+		return "uint64(" + slots[0] + ".I64())"
+	case wit.F32:
+		return slots[0] + ".F32()"
+	case wit.F64:
+		return slots[0] + ".F64()"
+	case wit.String:
+		return "hostLoadString(mem, uint32(" + slots[0] + ".I32()), uint32(" + slots[1] + ".I32()))"
+	case *wit.TypeDef:
+		if l, ok := t.Kind.(*wit.List); ok {
+			return g.hostLoadList(file, l, "uint32("+slots[0]+".I32())", "uint32("+slots[1]+".I32())")
+		}
+	}
+	return g.hostTypeRep(file, t) + "(" + slots[0] + ".I32())"
+}
+
+// wasmtimeLowerFlat returns a Go expression that lowers v of type t into a single wasmtime value.
+func (g *generator) wasmtimeLowerFlat(file *gen.File, rt string, t wit.Type, v string) string {
+	switch hostRoot(t).(type) {
+	case wit.Bool:
+		return rt + ".ValI32(int32(" + file.Import(g.opts.cmPackage) + ".BoolToU32(" + v + ")))"
+	case wit.S64, wit.U64:
+		return rt + ".ValI64(int64(" + v + "))"
+	case wit.F32:
+		return rt + ".ValF32(" + v + ")"
+	case wit.F64:
+		return rt + ".ValF64(" + v + ")"
+	}
+	return rt + ".ValI32(int32(" + v + "))"
+}
+
+// ensureWasmtimeABI emits the helper functions used by wasmtime host bindings into file.
+func (g *generator) ensureWasmtimeABI(file *gen.File) error {
+	r := strings.NewReplacer(
+		"binary.", file.Import("encoding/binary")+".",
+		"fmt.", file.Import("fmt")+".",
+		"math.", file.Import("math")+".",
+		"wasmtime.", file.Import(wasmtimePackage)+".",
+	)
+	_, err := r.WriteString(file, g.internHostStrings(file, wasmtimeABI))
+	return err
+}
+
+const wasmtimeABI = `
+// hostMemory is the linear memory exported by the guest calling a host function.
+type hostMemory struct {
+	caller *wasmtime.Caller
+	memory *wasmtime.Memory
+}
+
+// hostMemoryOf returns the memory exported by the guest of caller.
+func hostMemoryOf(caller *wasmtime.Caller) hostMemory {
+	ext := caller.GetExport("memory")
+	if ext == nil || ext.Memory() == nil {
+		panic("guest does not export memory")
+	}
+	return hostMemory{caller, ext.Memory()}
+}
+
+// slice returns n bytes of guest memory at ptr. The returned slice
+// is invalidated if guest memory grows, for example by a call to cabi_realloc.
+func (mem hostMemory) slice(ptr, n uint32) []byte {
+	data := mem.memory.UnsafeData(mem.caller)
+	if uint64(ptr)+uint64(n) > uint64(len(data)) {
+		panic(hostOutOfRange)
+	}
+	return data[ptr : ptr+n]
+}
+
+// hostLoadU8 loads a uint8 from guest memory at ptr.
+func hostLoadU8(mem hostMemory, ptr uint32) uint8 {
+	return mem.slice(ptr, 1)[0]
+}
+
+// hostLoadU16 loads a little-endian uint16 from guest memory at ptr.
+func hostLoadU16(mem hostMemory, ptr uint32) uint16 {
+	return binary.LittleEndian.Uint16(mem.slice(ptr, 2))
+}
+
+// hostLoadU32 loads a little-endian uint32 from guest memory at ptr.
+func hostLoadU32(mem hostMemory, ptr uint32) uint32 {
+	return binary.LittleEndian.Uint32(mem.slice(ptr, 4))
+}
+
+// hostLoadU64 loads a little-endian uint64 from guest memory at ptr.
+func hostLoadU64(mem hostMemory, ptr uint32) uint64 {
+	return binary.LittleEndian.Uint64(mem.slice(ptr, 8))
+}
+
+// hostLoadF32 loads a little-endian float32 from guest memory at ptr.
+func hostLoadF32(mem hostMemory, ptr uint32) float32 {
+	return math.Float32frombits(hostLoadU32(mem, ptr))
+}
+
+// hostLoadF64 loads a little-endian float64 from guest memory at ptr.
+func hostLoadF64(mem hostMemory, ptr uint32) float64 {
+	return math.Float64frombits(hostLoadU64(mem, ptr))
+}
+
+// hostLoadString copies a string of n bytes from guest memory at ptr.
+func hostLoadString(mem hostMemory, ptr, n uint32) string {
+	return string(mem.slice(ptr, n))
+}
+
+// hostLoadList lifts a list of n elements of size bytes each from guest memory at ptr.
+func hostLoadList[T any](mem hostMemory, ptr, n, size uint32, load func(ptr uint32) T) []T {
+	if n == 0 {
+		return nil
+	}
+	if uint64(n)*uint64(size) > math.MaxUint32 {
+		panic(hostOutOfRange)
+	}
+	mem.slice(ptr, n*size) // bounds check
+	s := make([]T, n)
+	for i := range s {
+		s[i] = load(ptr + uint32(i)*size)
+	}
+	return s
+}
+
+// hostStoreU8 stores a uint8 into guest memory at ptr.
+func hostStoreU8(mem hostMemory, ptr uint32, v uint8) {
+	mem.slice(ptr, 1)[0] = v
+}
+
+// hostStoreU16 stores a little-endian uint16 into guest memory at ptr.
+func hostStoreU16(mem hostMemory, ptr uint32, v uint16) {
+	binary.LittleEndian.PutUint16(mem.slice(ptr, 2), v)
+}
+
+// hostStoreU32 stores a little-endian uint32 into guest memory at ptr.
+func hostStoreU32(mem hostMemory, ptr uint32, v uint32) {
+	binary.LittleEndian.PutUint32(mem.slice(ptr, 4), v)
+}
+
+// hostStoreU64 stores a little-endian uint64 into guest memory at ptr.
+func hostStoreU64(mem hostMemory, ptr uint32, v uint64) {
+	binary.LittleEndian.PutUint64(mem.slice(ptr, 8), v)
+}
+
+// hostStoreF32 stores a little-endian float32 into guest memory at ptr.
+func hostStoreF32(mem hostMemory, ptr uint32, v float32) {
+	hostStoreU32(mem, ptr, math.Float32bits(v))
+}
+
+// hostStoreF64 stores a little-endian float64 into guest memory at ptr.
+func hostStoreF64(mem hostMemory, ptr uint32, v float64) {
+	hostStoreU64(mem, ptr, math.Float64bits(v))
+}
+
+// hostStoreString copies s into memory allocated by the guest,
+// and stores its pointer and length into guest memory at ptr.
+func hostStoreString(caller *wasmtime.Caller, ptr uint32, s string) {
+	data := hostRealloc(caller, uint32(len(s)), 1)
+	mem := hostMemoryOf(caller)
+	copy(mem.slice(data, uint32(len(s))), s)
+	hostStoreU32(mem, ptr, data)
+	hostStoreU32(mem, ptr+4, uint32(len(s)))
+}
+
+// hostStoreList lowers s into memory allocated by the guest,
+// and stores its pointer and length into guest memory at ptr.
+func hostStoreList[T any](caller *wasmtime.Caller, ptr uint32, s []T, size, align uint32, store func(ptr uint32, v T)) {
+	data := hostRealloc(caller, uint32(len(s))*size, align)
+	for i := range s {
+		store(data+uint32(i)*size, s[i])
+	}
+	mem := hostMemoryOf(caller)
+	hostStoreU32(mem, ptr, data)
+	hostStoreU32(mem, ptr+4, uint32(len(s)))
+}
+
+// hostRealloc allocates size bytes with alignment align in guest memory
+// by calling the cabi_realloc function exported by the guest.
+func hostRealloc(caller *wasmtime.Caller, size, align uint32) uint32 {
+	ext := caller.GetExport("cabi_realloc")
+	if ext == nil || ext.Func() == nil {
+		panic("guest does not export cabi_realloc")
+	}
+	v, err := ext.Func().Call(caller, int32(0), int32(0), int32(align), int32(size))
+	if err != nil {
+		panic(err)
+	}
+	return uint32(v.(int32))
+}
+
+// hostRecover converts a panic in a host function into a wasmtime trap.
+// It must be deferred.
+func hostRecover(trap **wasmtime.Trap) {
+	if r := recover(); r != nil {
+		*trap = wasmtime.NewTrap(fmt.Sprint(r))
+	}
+}
+
+const hostOutOfRange = "out of range guest memory access"
+`
@@ -0,0 +1,80 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// defineInterfaceWrappers emits a Go interface type with a method for each freestanding
+// function in WIT interface i, if the Interfaces option is set. For imported interfaces,
+// it also emits a Client type that implements the interface by calling the imported
+// functions. For exported interfaces, it emits an Export function that assigns the
+// exported functions to the methods of an implementation. The Fakes option implies Interfaces.
+func (g *generator) defineInterfaceWrappers(file *gen.File, id wit.Ident, dir wit.Direction, i *wit.Interface) {
+	if !g.opts.interfaces && !g.opts.fakes {
+		return
+	}
+
+	var decls []funcDecl
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		if !f.IsFreestanding() || !g.defined[dir][f] {
+			return true
+		}
+		if decl, ok := g.functions[dir][f]; ok {
+			decls = append(decls, decl)
+		}
+		return true
+	})
+	if len(decls) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	ifaceName := file.DeclareName("Interface")
+	stringio.Write(&b, "// ", ifaceName, " represents the functions in the ", dir.String(), " ", i.WITKind(), " \"", id.String(), "\".\n")
+	switch dir {
+	case wit.Imported:
+		clientName := file.DeclareName("Client")
+		stringio.Write(&b, "// It is implemented by [", clientName, "], or by a fake implementation in tests.\n")
+		g.interfaceType(&b, file, ifaceName, decls)
+
+		stringio.Write(&b, "// ", clientName, " implements [", ifaceName, "] by calling the functions imported by this package.\n")
+		stringio.Write(&b, "type ", clientName, " struct{}\n\n")
+		stringio.Write(&b, "var _ ", ifaceName, " = ", clientName, "{}\n\n")
+		for _, decl := range decls {
+			stringio.Write(&b, "// ", decl.f.name, " calls the imported function [", decl.f.name, "].\n")
+			stringio.Write(&b, "func (", clientName, ") ", decl.f.name, g.functionSignature(file, decl.f), " {\n")
+			if len(decl.f.results) > 0 {
+				b.WriteString("return ")
+			}
+			stringio.Write(&b, decl.f.name, "(", decl.f.args(), ")\n}\n\n")
+		}
+		defer g.defineFake(id, ifaceName, decls)
+
+	case wit.Exported:
+		exportName := file.DeclareName("Export")
+		stringio.Write(&b, "// Pass an implementation to [", exportName, "] to handle calls to the exported functions.\n")
+		g.interfaceType(&b, file, ifaceName, decls)
+
+		stringio.Write(&b, "// ", exportName, " sets the caller-defined, exported functions in this package\n")
+		stringio.Write(&b, "// to the methods of impl.\n")
+		stringio.Write(&b, "func ", exportName, "(impl ", ifaceName, ") {\n")
+		for _, decl := range decls {
+			stringio.Write(&b, decl.f.name, " = impl.", decl.f.name, "\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	file.Write([]byte(b.String()))
+}
+
+func (g *generator) interfaceType(b *strings.Builder, file *gen.File, name string, decls []funcDecl) {
+	stringio.Write(b, "type ", name, " interface {\n")
+	for _, decl := range decls {
+		stringio.Write(b, decl.f.name, g.functionSignature(file, decl.f), "\n")
+	}
+	b.WriteString("}\n\n")
+}
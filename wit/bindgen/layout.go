@@ -0,0 +1,185 @@
+package bindgen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+const (
+	layoutFile      = "abi_layout" + GoSuffix
+	layoutWasmFile  = "abi_layout_wasm32" + GoSuffix
+	layoutTestFile  = "abi_layout.wit_test.go"
+	layoutBuild     = "!(386 || arm || mips || mipsle)"
+	layoutWasmBuild = "tinygo.wasm"
+	layoutDocs      = `// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation. Types in this file do not contain pointers,
+// and have the same layout on 64-bit architectures and 32-bit WebAssembly.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+`
+	layoutWasmDocs = `// Compile-time assertions that the size and alignment of generated types
+// match their Canonical ABI representation on 32-bit WebAssembly.
+// Types in this file contain pointers, and have a different layout on 64-bit architectures.
+// A failed assertion is reported as an invalid array length or mismatched array type.
+
+`
+)
+
+// hasLayout returns true if t is represented by a Go struct or array type
+// with a memory layout that must match its Canonical ABI representation.
+func hasLayout(t *wit.TypeDef) bool {
+	switch kind := t.Kind.(type) {
+	case *wit.Record, *wit.Tuple, *wit.Option, *wit.Result, *wit.List:
+		return true
+	case *wit.Variant:
+		return kind.Enum() == nil
+	}
+	return false
+}
+
+// defineLayoutAssertions emits compile-time assertions that the size and alignment
+// of the Go type declared by decl match the Canonical ABI size and alignment of t.
+// Assertions for types without pointers are constrained to architectures where
+// 64-bit integers are 8-byte aligned, including WebAssembly. Types with pointers
+// have a different layout on 64-bit architectures, so their assertions are emitted
+// into a separate file, constrained to TinyGo on WebAssembly.
+func (g *generator) defineLayoutAssertions(decl typeDecl, t *wit.TypeDef) error {
+	if !hasLayout(t) {
+		return nil
+	}
+
+	name, build, docs := layoutFile, layoutBuild, layoutDocs
+	if wit.HasPointer(t.Kind) {
+		name, build, docs = layoutWasmFile, layoutWasmBuild, layoutWasmDocs
+	}
+
+	file := decl.file.Package.File(name)
+	if len(file.Content) == 0 {
+		file.GeneratedBy = g.opts.generatedBy
+		file.Build = joinBuild(decl.file.Build, build)
+		_, err := file.Write([]byte(docs))
+		if err != nil {
+			return err
+		}
+	}
+	unsafe := file.Import("unsafe")
+
+	var b strings.Builder
+	stringio.Write(&b, "var _ [0]struct{} = [", unsafe, ".Sizeof(", decl.name, "{}) - ", strconv.Itoa(int(t.Size())), "]struct{}{}\n")
+	stringio.Write(&b, "var _ [0]struct{} = [", unsafe, ".Alignof(", decl.name, "{}) - ", strconv.Itoa(int(t.Align())), "]struct{}{}\n")
+	_, err := file.Write([]byte(b.String()))
+	return err
+}
+
+// caseKey identifies a Go type declared in a file.
+type caseKey struct {
+	file *gen.File
+	name string
+}
+
+// recordCaseName records the Go name of the next enum case, flag, or variant constructor
+// of the Go type goName declared in file, if the LayoutTests option is set.
+func (g *generator) recordCaseName(file *gen.File, goName, caseName string) {
+	if g.opts.layoutTests {
+		k := caseKey{file, goName}
+		g.caseNames[k] = append(g.caseNames[k], caseName)
+	}
+}
+
+// defineLayoutTest emits a test function that asserts the layout of the Go type declared
+// by decl, if the LayoutTests option is set: its size and alignment, and the values of its
+// enum cases, the discriminants of its variant cases, or the bit positions of its flags.
+// The size and alignment of a type that contains pointers are only checked on 32-bit architectures.
+func (g *generator) defineLayoutTest(decl typeDecl, dir wit.Direction, t *wit.TypeDef) {
+	if !g.opts.layoutTests {
+		return
+	}
+	enum := wit.KindOf[*wit.Enum](t)
+	variant := wit.KindOf[*wit.Variant](t)
+	if variant != nil {
+		enum = variant.Enum()
+	}
+	flags := wit.KindOf[*wit.Flags](t)
+	if !hasLayout(t) && enum == nil && flags == nil {
+		return
+	}
+
+	file := decl.file.Package.File(layoutTestFile)
+	if len(file.Content) == 0 {
+		file.GeneratedBy = g.opts.generatedBy
+		file.Build = decl.file.Build
+	}
+	testing := file.Import("testing")
+	unsafe := file.Import("unsafe")
+	names := g.caseNames[caseKey{decl.file, decl.name}]
+
+	var b strings.Builder
+	stringio.Write(&b, "func ", file.DeclareName("TestLayout"+decl.name), "(t *", testing, ".T) {\n")
+	stringio.Write(&b, "var v ", decl.name, "\n")
+	if wit.HasPointer(t.Kind) {
+		stringio.Write(&b, "if ", unsafe, ".Sizeof(uintptr(0)) == 4 {\n")
+	}
+	for _, check := range []struct{ fn, want string }{
+		{"Sizeof", strconv.Itoa(int(t.Size()))},
+		{"Alignof", strconv.Itoa(int(t.Align()))},
+	} {
+		stringio.Write(&b, "if got, want := ", unsafe, ".", check.fn, "(v), uintptr(", check.want, "); got != want {\n")
+		stringio.Write(&b, "t.Errorf(\"unsafe.", check.fn, "(", decl.name, "): %d, expected %d\", got, want)\n")
+		b.WriteString("}\n")
+	}
+	if wit.HasPointer(t.Kind) {
+		b.WriteString("}\n")
+	}
+
+	switch {
+	case enum != nil && len(names) == len(enum.Cases):
+		for i, c := range enum.Cases {
+			stringio.Write(&b, "if got, want := ", names[i], ", ", decl.name, "(", strconv.Itoa(i), "); got != want {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ": %d, expected %d\", got, want)\n")
+			b.WriteString("}\n")
+			stringio.Write(&b, "if got, want := ", names[i], ".String(), ", strconv.Quote(c.Name), "; got != want {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ".String(): %q, expected %q\", got, want)\n")
+			b.WriteString("}\n")
+		}
+
+	case flags != nil && len(names) == len(flags.Flags) && flags.Size() > 4:
+		// Flags with more than 32 flags are indexes into a [cm.Flags].
+		for i := range flags.Flags {
+			stringio.Write(&b, "if got, want := uint(", names[i], "), uint(", strconv.Itoa(i), "); got != want {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ": %d, expected %d\", got, want)\n")
+			b.WriteString("}\n")
+			stringio.Write(&b, "if v.Set(", names[i], "); !v.Is(", names[i], ") {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ": not set\")\n")
+			b.WriteString("}\n")
+		}
+
+	case flags != nil && len(names) == len(flags.Flags):
+		for i := range flags.Flags {
+			stringio.Write(&b, "if got, want := ", names[i], ", ", decl.name, "(1) << ", strconv.Itoa(i), "; got != want {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ": %#x, expected %#x\", got, want)\n")
+			b.WriteString("}\n")
+		}
+
+	case variant != nil && len(names) == len(variant.Cases):
+		cm := file.Import(g.opts.cmPackage)
+		for i, c := range variant.Cases {
+			b.WriteString("{\n")
+			if c.Type == nil {
+				stringio.Write(&b, "v := ", names[i], "()\n")
+			} else {
+				stringio.Write(&b, "var data ", g.typeRep(file, dir, c.Type), "\n")
+				stringio.Write(&b, "v := ", names[i], "(data)\n")
+			}
+			stringio.Write(&b, "if got, want := ", cm, ".Tag(&v), ", strconv.Itoa(i), "; int(got) != want {\n")
+			stringio.Write(&b, "t.Errorf(\"", names[i], ": tag %d, expected %d\", got, want)\n")
+			b.WriteString("}\n")
+			b.WriteString("}\n")
+		}
+	}
+	b.WriteString("}\n\n")
+	file.Write([]byte(b.String()))
+}
@@ -17,6 +17,39 @@ func GoPackageName(name string) string {
 	}, strings.ToLower(name))
 }
 
+// Casing converts WIT names to Go identifiers in generated code.
+// Changing the casing of generated code renames its exported identifiers,
+// which breaks code that uses them, so a project should choose one casing and keep it.
+type Casing interface {
+	// GoName returns a Go identifier for a kebab-case WIT name, exported if export is true.
+	// The identifier may conflict with a Go keyword or another identifier;
+	// callers must handle collisions.
+	GoName(name string, export bool) string
+}
+
+// CasingFunc is a function that implements [Casing].
+type CasingFunc func(name string, export bool) string
+
+// GoName implements the [Casing] interface.
+func (fn CasingFunc) GoName(name string, export bool) string {
+	return fn(name, export)
+}
+
+var (
+	// InitialismCasing converts a WIT name to camelCase or PascalCase with common initialisms
+	// in upper case, and the segments in [Segments] and [ExportedSegments] replaced,
+	// e.g. HTTPRequestID for http-request-id. It is the casing of [GoName].
+	InitialismCasing Casing = CasingFunc(GoName)
+
+	// PreserveCasing converts a WIT name to camelCase or PascalCase, preserving the case of
+	// each segment after its first letter, e.g. HttpRequestId for http-request-id,
+	// and HTTPRequestId for HTTP-request-id.
+	PreserveCasing Casing = CasingFunc(preserveName)
+)
+
+// DefaultCasing is the default [Casing], [InitialismCasing].
+var DefaultCasing = InitialismCasing
+
 // GoName returns an idiomatic (exported CamelCase) Go name for a WIT name.
 func GoName(name string, export bool) string {
 	var b strings.Builder
@@ -42,6 +75,21 @@ func GoName(name string, export bool) string {
 	return b.String()
 }
 
+func preserveName(name string, export bool) string {
+	var b strings.Builder
+	for i, segment := range segments(name) {
+		if i == 0 && !export {
+			// WIT segments are either lower or upper case.
+			b.WriteString(strings.ToLower(segment))
+			continue
+		}
+		runes := []rune(segment)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
 // SnakeName returns a snake_case equivalent of a WIT name.
 // It may conflict with a Go keyword or predeclared identifier.
 func SnakeName(name string) string {
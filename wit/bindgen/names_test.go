@@ -31,3 +31,29 @@ func TestGoName(t *testing.T) {
 		})
 	}
 }
+
+func TestPreserveCasing(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string
+		exported string
+	}{
+		{"cabi", "cabi", "Cabi"},
+		{"http-request-id", "httpRequestId", "HttpRequestId"},
+		{"HTTP-request-id", "httpRequestId", "HTTPRequestId"},
+		{"ipv4-socket", "ipv4Socket", "Ipv4Socket"},
+		{"URL", "url", "URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PreserveCasing.GoName(tt.name, false)
+			if got != tt.want {
+				t.Errorf("GoName(%q, false): %q, expected %q", tt.name, got, tt.want)
+			}
+			exported := PreserveCasing.GoName(tt.name, true)
+			if exported != tt.exported {
+				t.Errorf("GoName(%q, true): %q, expected %q", tt.name, exported, tt.exported)
+			}
+		})
+	}
+}
@@ -1,5 +1,14 @@
 package bindgen
 
+import (
+	"fmt"
+	"go/build/constraint"
+	"log/slog"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
 // Option represents a single configuration option for this package.
 type Option interface {
 	applyOption(*options) error
@@ -15,6 +24,10 @@ type options struct {
 	// generatedBy is the name of the program that generates code with this package.
 	generatedBy string
 
+	// generatorVersion is the version of the program that generates code with this package.
+	// If set, it appears with the source WIT world in the header of generated files.
+	generatorVersion string
+
 	// world is the name of the WIT world to generate, e.g. "command" or "wasi:cli/command".
 	// Default: all worlds in the Resolve will be generated.
 	world string
@@ -28,6 +41,79 @@ type options struct {
 
 	// versioned determines if Go packages are generated with version numbers.
 	versioned bool
+
+	// host determines if host bindings are generated instead of guest bindings.
+	host bool
+
+	// hostRuntime is the WebAssembly runtime used by host bindings.
+	// Default: [HostWazero].
+	hostRuntime string
+
+	// internStrings determines if strings lifted by host bindings are interned.
+	internStrings bool
+
+	// target is the compilation target for generated guest bindings.
+	// Default: [TargetWASIP2].
+	target string
+
+	// stubs determines if stub implementations of exported functions are generated.
+	stubs bool
+
+	// resultErrors determines if imported functions that return a result
+	// are also generated as functions that return (T, error).
+	resultErrors bool
+
+	// contextParams determines if each Go function that calls an imported function
+	// takes a context.Context as its first param.
+	contextParams bool
+
+	// optionPointers determines if anonymous option<T> params and results of functions
+	// are represented as *T rather than cm.Option[T].
+	optionPointers bool
+
+	// interfaces determines if a Go interface type is generated for each WIT interface,
+	// with a client for imported interfaces and an adapter for exported interfaces.
+	interfaces bool
+
+	// fakes determines if fake implementations of imported interfaces are generated.
+	fakes bool
+
+	// hostShims determines if imported functions are declared in files constrained to
+	// WebAssembly, with stubs that panic on other architectures.
+	hostShims bool
+
+	// layoutTests determines if tests of the layout of generated types are generated.
+	layoutTests bool
+
+	// recordFuncs determines if a constructor and Validate method are generated for each record type.
+	recordFuncs bool
+
+	// typeNamer names Go type aliases declared for anonymous types used by functions.
+	// Default: nil, no type aliases are declared.
+	typeNamer wit.TypeNamer
+
+	// casing converts WIT names to Go identifiers.
+	// Default: [DefaultCasing].
+	casing Casing
+
+	// cachedImports is the set of imported functions, e.g. wasi:random/insecure-seed#insecure-seed,
+	// whose results are cached after the first call.
+	cachedImports map[string]bool
+
+	// buildTags maps WIT interface names, e.g. wasi:keyvalue/store, with an optional version,
+	// to a build constraint added to the Go files generated for the interface.
+	buildTags map[string]string
+
+	// sources maps WIT definitions to their location in WIT source files.
+	// If set, generated declarations include a comment with the location.
+	sources wit.SourceMap
+
+	// plugins are called to extend generated code.
+	plugins []Plugin
+
+	// logger receives debug messages that report the progress of code generation.
+	// Default: [slog.Default].
+	logger *slog.Logger
 }
 
 func (opts *options) apply(o ...Option) error {
@@ -49,6 +135,16 @@ func GeneratedBy(name string) Option {
 	})
 }
 
+// GeneratorVersion returns an [Option] that specifies the version of the program
+// that generates code, e.g. v0.1.0. If set, the version appears with the source WIT world
+// in the "Source: ..." comment following the header on generated files.
+func GeneratorVersion(version string) Option {
+	return optionFunc(func(opts *options) error {
+		opts.generatorVersion = version
+		return nil
+	})
+}
+
 // World returns an [Option] that specifies the WIT world to generate.
 func World(world string) Option {
 	return optionFunc(func(opts *options) error {
@@ -82,3 +178,315 @@ func Versioned(versioned bool) Option {
 		return nil
 	})
 }
+
+// Host returns an [Option] that specifies that host bindings will be generated
+// instead of guest bindings. Host bindings implement the imports of a WIT world
+// as [wazero] host modules, dispatching calls to Go implementations of each
+// imported interface. See [HostRuntime] to generate bindings for other runtimes.
+// Params and results of imported functions must be primitive, enum, flags, or list types.
+// [Go] returns an error for any other imported function, such as one with a record
+// or resource handle param.
+//
+// [wazero]: https://wazero.io
+func Host(host bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.host = host
+		return nil
+	})
+}
+
+const (
+	// HostWazero is the default runtime for host bindings. Each imported interface has an
+	// Instantiate function that instantiates a [wazero] host module.
+	//
+	// [wazero]: https://wazero.io
+	HostWazero = "wazero"
+
+	// HostWasmtime generates host bindings for [wasmtime-go]. Each imported interface has
+	// a Define function that defines its functions in a wasmtime Linker.
+	//
+	// [wasmtime-go]: https://github.com/bytecodealliance/wasmtime-go
+	HostWasmtime = "wasmtime"
+)
+
+// HostRuntime returns an [Option] that specifies the WebAssembly runtime used by
+// host bindings, either [HostWazero] (default) or [HostWasmtime].
+// The Host interface of each imported interface is the same for either runtime.
+// It has no effect unless the [Host] option is set.
+func HostRuntime(runtime string) Option {
+	return optionFunc(func(opts *options) error {
+		switch runtime {
+		case "", HostWazero, HostWasmtime:
+		default:
+			return fmt.Errorf("unknown host runtime %q", runtime)
+		}
+		opts.hostRuntime = runtime
+		return nil
+	})
+}
+
+// InternStrings returns an [Option] that specifies that host bindings intern the strings
+// they lift from guest memory with [cm.InternBytes], which avoids allocating a new string
+// each time the same string is lifted, such as an HTTP header name.
+// It has no effect unless the [Host] option is set.
+//
+// [cm.InternBytes]: https://pkg.go.dev/github.com/ydnar/wasm-tools-go/cm#InternBytes
+func InternStrings(internStrings bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.internStrings = internStrings
+		return nil
+	})
+}
+
+// Stubs returns an [Option] that specifies that a [StubsFile] is generated in the
+// Go package for each world with exports. The file assigns a stub implementation to
+// each exported function and resource method, which panics until it is replaced,
+// so a component compiles before its exports are implemented.
+func Stubs(stubs bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.stubs = stubs
+		return nil
+	})
+}
+
+// ResultErrors returns an [Option] that specifies that imported functions that return
+// a result<T, E> also have a Go function that returns (T, error), with a generated
+// error type that wraps E. Functions that return result<_, E> return error.
+// The Go function with the exact shape of the result is renamed with a Result suffix.
+func ResultErrors(resultErrors bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.resultErrors = resultErrors
+		return nil
+	})
+}
+
+// ContextParams returns an [Option] that specifies that each generated Go function that
+// calls an imported function takes a [context.Context] as its first param, including
+// functions generated by the [ResultErrors], [Interfaces], and [Fakes] options.
+// The context is currently unused, but reserves a place in the API of generated bindings
+// for cancellation and the async Component Model, which can then be supported without
+// breaking call sites. Exported functions and the host bindings are not affected.
+//
+// [context.Context]: https://pkg.go.dev/context#Context
+func ContextParams(contextParams bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.contextParams = contextParams
+		return nil
+	})
+}
+
+// OptionPointers returns an [Option] that specifies that params and results of generated
+// functions with an anonymous option<T> type are represented as a Go pointer *T, where nil
+// represents none, rather than as [cm.Option][T]. This applies to the Go functions that
+// call imported functions, and the caller-defined Go functions that implement exports.
+//
+// Pointers are idiomatic Go, but the tradeoff is an allocation or copy: a *T lifted from an
+// option<T> points into a copy of the option, and a *T lowered to an option<T> is copied.
+// The shape of option<T> in memory is fixed by the Canonical ABI, so options nested in other
+// types, such as a record field or list<option<T>>, named option types, and the results of
+// functions with more than one result are unchanged. An option of an option, such as
+// option<option<T>>, is unchanged, because a nil pointer could not distinguish none from some(none).
+//
+// [cm.Option]: https://pkg.go.dev/github.com/ydnar/wasm-tools-go/cm#Option
+func OptionPointers(optionPointers bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.optionPointers = optionPointers
+		return nil
+	})
+}
+
+const (
+	// TargetWASIP2 is the default target for generated bindings,
+	// for toolchains that support the Component Model natively, such as TinyGo.
+	TargetWASIP2 = "wasip2"
+
+	// TargetWASIP1 targets the Go wasip1 port. The resulting Core WebAssembly module
+	// is converted into a component with the wasi_snapshot_preview1 adapter.
+	// Because the Go wasip1 port restricts go:wasmimport to scalar types and does not
+	// support go:wasmexport, only imported functions with scalar params and results
+	// (integers, floats, bool, enum, flags, and resource handles) are generated.
+	// Each skipped function, imported or exported, is logged as a warning to the [Logger].
+	TargetWASIP1 = "wasip1"
+)
+
+// Target returns an [Option] that specifies the compilation target for generated bindings,
+// either [TargetWASIP2] (default) or [TargetWASIP1].
+func Target(target string) Option {
+	return optionFunc(func(opts *options) error {
+		switch target {
+		case "", TargetWASIP2, TargetWASIP1:
+		default:
+			return fmt.Errorf("unknown target %q", target)
+		}
+		opts.target = target
+		return nil
+	})
+}
+
+// Interfaces returns an [Option] that specifies that a Go interface type is generated
+// for the freestanding functions in each WIT interface, so application code can depend
+// on an interface and be tested with fakes. For imported WIT interfaces, a Client type
+// implements the Go interface by calling the imported functions. For exported WIT
+// interfaces, an Export function sets the exported functions to the methods of an
+// implementation of the Go interface.
+func Interfaces(interfaces bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.interfaces = interfaces
+		return nil
+	})
+}
+
+// Fakes returns an [Option] that specifies that a Fake type is generated for each
+// imported WIT interface, in a file with [FakeSuffix]. A Fake is an in-memory
+// implementation of the Go interface generated by the [Interfaces] option,
+// which records calls and returns programmable results, for unit tests of
+// guest code without a WebAssembly runtime. Fakes implies Interfaces and [HostShims].
+func Fakes(fakes bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.fakes = fakes
+		return nil
+	})
+}
+
+// HostShims returns an [Option] that specifies that the go:wasmimport declaration of each
+// imported function is generated in a file with a .wasm.wit.go suffix, constrained to
+// WebAssembly, with a stub that panics in a file with a .host.wit.go suffix for other
+// architectures. This allows host-side tests of packages that use generated types to link,
+// as long as they do not call imported functions. The Fakes option implies HostShims.
+func HostShims(hostShims bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.hostShims = hostShims
+		return nil
+	})
+}
+
+// LayoutTests returns an [Option] that specifies that a test file named abi_layout.wit_test.go
+// is generated for each Go package, with tests that assert the size and alignment of
+// generated types, the values of enum cases, the discriminants of variant cases, and the
+// bit positions of flags. Sizes and alignments of types that contain pointers are only
+// checked on 32-bit architectures such as WebAssembly.
+func LayoutTests(layoutTests bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.layoutTests = layoutTests
+		return nil
+	})
+}
+
+// RecordFuncs returns an [Option] that specifies that each exported Go type for a WIT record
+// has a NewT constructor with a param for each field, and each record type with a field that
+// can hold an invalid value has a Validate method. Validate returns an error if a handle field
+// is zero or an enum field is out of range, and calls Validate on each field of a record type
+// that has one. Fields of other types, such as lists, options, or variants, are not checked.
+func RecordFuncs(recordFuncs bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.recordFuncs = recordFuncs
+		return nil
+	})
+}
+
+// AnonymousTypes returns an [Option] that specifies that a Go type alias is declared
+// for each anonymous tuple, option, result, or list used by a param or result of a
+// generated function, e.g. TupleStringU32 for tuple<string, u32>. The alias is named
+// by namer, converted to a Go name. Code that refers to an anonymous type by its alias
+// does not need to change if the Go representation of the type changes.
+// If namer is nil, [wit.DefaultTypeNamer] is used.
+func AnonymousTypes(namer wit.TypeNamer) Option {
+	return optionFunc(func(opts *options) error {
+		if namer == nil {
+			namer = wit.DefaultTypeNamer
+		}
+		opts.typeNamer = namer
+		return nil
+	})
+}
+
+// NameCasing returns an [Option] that specifies the [Casing] that converts WIT names
+// to Go identifiers, e.g. [InitialismCasing] or [PreserveCasing].
+// If casing is nil, [DefaultCasing] is used.
+func NameCasing(casing Casing) Option {
+	return optionFunc(func(opts *options) error {
+		if casing == nil {
+			casing = DefaultCasing
+		}
+		opts.casing = casing
+		return nil
+	})
+}
+
+// Sources returns an [Option] that specifies the locations of WIT definitions in WIT
+// source files, e.g. as returned by [wit.FindSources]. The doc comment of each generated
+// type and function declared by a definition in sources ends with its location,
+// e.g. "Source: wit/world.wit:12", so generated code can be traced back to its WIT source.
+func Sources(sources wit.SourceMap) Option {
+	return optionFunc(func(opts *options) error {
+		opts.sources = sources
+		return nil
+	})
+}
+
+// CachedImports returns an [Option] that specifies imported functions whose result is
+// cached after the first call, such as functions that return a value that does not change,
+// or must only be called once, e.g. wasi:random/insecure-seed#insecure-seed.
+// Each name is a WIT interface or world name, with an optional version, followed by #
+// and the function name. The generated Go function calls the imported function once, then
+// returns the value held in a [cm.Once]. Named functions must be freestanding functions
+// with no params and a single result.
+//
+// [cm.Once]: https://pkg.go.dev/github.com/ydnar/wasm-tools-go/cm#Once
+func CachedImports(names ...string) Option {
+	return optionFunc(func(opts *options) error {
+		if opts.cachedImports == nil {
+			opts.cachedImports = make(map[string]bool)
+		}
+		for _, name := range names {
+			if _, _, ok := strings.Cut(name, "#"); !ok {
+				return fmt.Errorf("invalid cached import %q: expected interface#function", name)
+			}
+			opts.cachedImports[name] = true
+		}
+		return nil
+	})
+}
+
+// BuildTags returns an [Option] that adds a build constraint to the Go files generated
+// for WIT interfaces, so the bindings for optional interfaces, such as unstable WASI proposals,
+// compile only when a build tag is set. Each key of tags is a WIT interface name, e.g.
+// wasi:keyvalue/store, with an optional version, and each value is a build constraint expression,
+// e.g. keyvalue or keyvalue && !tinygo. A version-specific name takes precedence.
+//
+// Go packages that use types from, or export, an interface with a build constraint
+// must only be built with the same tags.
+func BuildTags(tags map[string]string) Option {
+	return optionFunc(func(opts *options) error {
+		if opts.buildTags == nil {
+			opts.buildTags = make(map[string]string)
+		}
+		for name, expr := range tags {
+			if _, err := constraint.Parse("//go:build " + expr); err != nil {
+				return fmt.Errorf("invalid build constraint for %s: %w", name, err)
+			}
+			opts.buildTags[name] = expr
+		}
+		return nil
+	})
+}
+
+// Plugins returns an [Option] that adds one or more plugins to the code generator.
+// Plugins are called in order.
+func Plugins(plugins ...Plugin) Option {
+	return optionFunc(func(opts *options) error {
+		opts.plugins = append(opts.plugins, plugins...)
+		return nil
+	})
+}
+
+// Logger returns an [Option] that specifies the [slog.Logger] used to report
+// the progress of code generation, such as each world and interface generated.
+// Messages are logged at [slog.LevelDebug], except for warnings about
+// functions skipped by the target, such as exports with [TargetWASIP1].
+func Logger(logger *slog.Logger) Option {
+	return optionFunc(func(opts *options) error {
+		opts.logger = logger
+		return nil
+	})
+}
@@ -0,0 +1,96 @@
+package bindgen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// Plugin is implemented by extensions to the code generator, for example to emit
+// extra methods, logging, or metrics wrappers alongside the generated bindings.
+// The generator calls each method after it emits the Go code for a WIT interface,
+// type definition, or function. A Plugin adds code to the same Go file with [File].
+// Plugins are called for guest bindings only. See the [Plugins] option.
+//
+// Embed [BasePlugin] in a Plugin implementation to implement only some of its methods.
+type Plugin interface {
+	// Interface is called after all types and freestanding functions in interface i are defined.
+	Interface(file *File, dir wit.Direction, i *wit.Interface) error
+
+	// TypeDef is called after type t is defined as Go type goName.
+	TypeDef(file *File, dir wit.Direction, t *wit.TypeDef, goName string) error
+
+	// Function is called after function f is defined as Go function goName.
+	// If f is a method, goName is the name of the method on the Go type for f.Type().
+	Function(file *File, dir wit.Direction, f *wit.Function, goName string) error
+}
+
+// BasePlugin implements [Plugin] with methods that do nothing.
+type BasePlugin struct{}
+
+var _ Plugin = BasePlugin{}
+
+// Interface implements [Plugin].
+func (BasePlugin) Interface(file *File, dir wit.Direction, i *wit.Interface) error { return nil }
+
+// TypeDef implements [Plugin].
+func (BasePlugin) TypeDef(file *File, dir wit.Direction, t *wit.TypeDef, goName string) error {
+	return nil
+}
+
+// Function implements [Plugin].
+func (BasePlugin) Function(file *File, dir wit.Direction, f *wit.Function, goName string) error {
+	return nil
+}
+
+// File is a generated Go file that a [Plugin] can add code to.
+type File struct {
+	g    *generator
+	file *gen.File
+}
+
+// PackagePath returns the Go package path of f, e.g. "example.com/wasi/cli/environment".
+func (f *File) PackagePath() string {
+	return f.file.Package.Path
+}
+
+// PackageName returns the Go package name of f, e.g. "environment".
+func (f *File) PackageName() string {
+	return f.file.Package.Name
+}
+
+// Import imports the Go package specified by path, and returns its local name in f.
+// The path may have an optional "#name" suffix to specify the local name.
+func (f *File) Import(path string) string {
+	return f.file.Import(path)
+}
+
+// DeclareName declares a package-scoped identifier, and returns a name that
+// does not conflict with other declarations in the Go package.
+func (f *File) DeclareName(name string) string {
+	return f.file.DeclareName(name)
+}
+
+// TypeRep returns the Go representation of WIT type t in direction dir,
+// importing Go packages into f as necessary.
+func (f *File) TypeRep(dir wit.Direction, t wit.Type) string {
+	return f.g.typeRep(f.file, dir, t)
+}
+
+// Write appends Go source code to f. It must contain complete declarations.
+func (f *File) Write(code string) {
+	f.file.Write([]byte(code))
+}
+
+// callPlugins calls fn for each plugin with a [File] for file.
+// Errors are collected and returned by [generator.generate].
+func (g *generator) callPlugins(file *gen.File, fn func(p Plugin, file *File) error) {
+	for _, p := range g.opts.plugins {
+		err := fn(p, &File{g: g, file: file})
+		if err != nil {
+			g.pluginErr = errors.Join(g.pluginErr, fmt.Errorf("plugin %T: %w", p, err))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package bindgen
+
+import (
+	"slices"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// optionPointers returns a copy of Go function f with its anonymous option<T> params,
+// and its result if it has a single result, represented as *T if the [OptionPointers]
+// option is set. Otherwise it returns f unchanged.
+func (g *generator) optionPointers(f function) function {
+	if !g.opts.optionPointers || g.opts.target == TargetWASIP1 {
+		return f
+	}
+	f.params = slices.Clone(f.params)
+	f.results = slices.Clone(f.results)
+	for i := range f.params {
+		optionPointer(&f.params[i])
+	}
+	if len(f.results) == 1 {
+		optionPointer(&f.results[0])
+	}
+	return f
+}
+
+// optionPointer changes the type of p to *T if it is an anonymous option<T>,
+// unless T is also an option, keeping the option type in p.option.
+func optionPointer(p *param) {
+	td, ok := p.typ.(*wit.TypeDef)
+	if !ok || td.Name != nil {
+		return
+	}
+	o, ok := td.Kind.(*wit.Option)
+	if !ok {
+		return
+	}
+	if t, ok := o.Type.(*wit.TypeDef); ok {
+		if _, ok := t.Root().Kind.(*wit.Option); ok {
+			return
+		}
+	}
+	p.option = td
+	p.typ = &wit.TypeDef{Kind: &wit.Pointer{Type: o.Type}}
+}
+
+// lowerOptionPointer returns a Go expression that converts expr, the value of p,
+// to a cm.Option if p is represented as a pointer. Otherwise it returns expr.
+func (g *generator) lowerOptionPointer(file *gen.File, p param, expr string) string {
+	if p.option == nil {
+		return expr
+	}
+	return file.Import(g.opts.cmPackage) + ".OptionOf(" + expr + ")"
+}
+
+// liftOptionPointer returns a Go expression that converts expr, an addressable cm.Option,
+// to a pointer if p is represented as a pointer. Otherwise it returns expr.
+func liftOptionPointer(p param, expr string) string {
+	if p.option == nil {
+		return expr
+	}
+	return expr + ".Some()"
+}
@@ -0,0 +1,81 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+)
+
+// wasmImportDecl returns the bodyless go:wasmimport declaration of decl.wasm.
+func (g *generator) wasmImportDecl(file *gen.File, decl funcDecl) string {
+	var b strings.Builder
+	stringio.Write(&b, "//go:wasmimport ", decl.linkerName, "\n")
+	b.WriteString("//go:noescape\n")
+	b.WriteString(g.wasmImportFunc(file, decl))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// wasmImportFunc returns the func keyword, receiver, name, and signature of decl.wasm.
+func (g *generator) wasmImportFunc(file *gen.File, decl funcDecl) string {
+	var b strings.Builder
+	b.WriteString("func ")
+	if decl.wasm.isMethod() {
+		stringio.Write(&b, "(", decl.wasm.receiver.name, " ", g.typeRep(file, decl.wasm.receiver.dir, decl.wasm.receiver.typ), ") ", decl.wasm.name)
+	} else {
+		b.WriteString(decl.wasm.name)
+	}
+	b.WriteString(g.functionSignature(file, coreBools(decl.wasm)))
+	return b.String()
+}
+
+// defineWasmImportWithHostStub emits the go:wasmimport declaration of decl.wasm into a
+// file constrained to WebAssembly, and a stub that panics into a file for other
+// architectures. Otherwise, host-side tests of guest code, such as tests that use a Fake,
+// would fail to link when a method of a resource type is reachable.
+func (g *generator) defineWasmImportWithHostStub(file *gen.File, decl funcDecl) {
+	name := strings.TrimSuffix(file.Name, GoSuffix)
+	wasmFile := file.Package.File(name + ".wasm" + GoSuffix)
+	hostFile := file.Package.File(name + ".host" + GoSuffix)
+	for _, f := range []*gen.File{wasmFile, hostFile} {
+		f.GeneratedBy = file.GeneratedBy
+	}
+	wasmFile.Build = joinBuild(file.Build, "wasm")
+	hostFile.Build = joinBuild(file.Build, "!wasm")
+
+	wasmFile.Write([]byte(g.wasmImportDecl(wasmFile, decl)))
+
+	var b strings.Builder
+	stringio.Write(&b, g.wasmImportFunc(hostFile, decl), " {\n")
+	stringio.Write(&b, "panic(\"imported function not available on this architecture: ", decl.linkerName, "\")\n")
+	b.WriteString("}\n\n")
+	hostFile.Write([]byte(b.String()))
+}
+
+// joinBuild returns the conjunction of build constraints a and b.
+// A constraint with a top-level disjunction is parenthesized.
+func joinBuild(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return parenBuild(a) + " && " + parenBuild(b)
+}
+
+// parenBuild parenthesizes build constraint s if it contains a top-level || operator.
+func parenBuild(s string) string {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '|':
+			if depth == 0 {
+				return "(" + s + ")"
+			}
+		}
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package bindgen
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/go/gen"
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// StubsFile is the name of the file generated by the [Stubs] option.
+// Unlike other generated files, it is intended to be edited, and should
+// not be overwritten when bindings are regenerated.
+const StubsFile = "stubs.go"
+
+// stubs collects unimplemented skeletons for the exports of a single world.
+type stubs struct {
+	id    wit.Ident // The world
+	file  *gen.File
+	init  strings.Builder
+	funcs strings.Builder
+}
+
+// beginStubs prepares a stubs file in the Go package for world id,
+// if the Stubs option is set.
+func (g *generator) beginStubs(id wit.Ident) {
+	if !g.opts.stubs || g.opts.host || g.opts.target == TargetWASIP1 {
+		return
+	}
+	file := g.packageFor(id).File(StubsFile)
+	file.Build = g.fileFor(id).Build
+	g.stubs = &stubs{id: id, file: file}
+}
+
+// defineStub emits a stub implementation of the exported function f,
+// and assigns it to the Go function variable declared by decl.
+func (g *generator) defineStub(f *wit.Function, decl funcDecl) {
+	s := g.stubs
+	if s == nil || strings.HasPrefix(f.Name, "cabi_post_") {
+		return
+	}
+	name := s.file.DeclareName(strings.ToLower(decl.f.name[:1]) + decl.f.name[1:])
+	stringio.Write(&s.init, s.file.RelativeName(decl.f.file.Package, decl.f.name), " = ", name, "\n")
+
+	stringio.Write(&s.funcs, "\n// ", name, " implements the exported ", f.WITKind(), " \"", decl.linkerName, "\".\n")
+	stringio.Write(&s.funcs, "func ", name, g.functionSignature(s.file, decl.f), " {\n")
+	if strings.HasPrefix(f.Name, "[dtor]") {
+		s.funcs.WriteString("// TODO: release resources associated with self.\n")
+	} else {
+		s.funcs.WriteString("panic(\"unimplemented\")\n")
+	}
+	s.funcs.WriteString("}\n")
+}
+
+// endStubs writes the stubs for the current world, if any.
+func (g *generator) endStubs() {
+	s := g.stubs
+	g.stubs = nil
+	if s == nil {
+		return
+	}
+	if s.init.Len() == 0 {
+		delete(s.file.Package.Files, s.file.Name)
+		return
+	}
+	var b strings.Builder
+	stringio.Write(&b, "// This file contains stubs for the exports of world \"", s.id.String(), "\".\n")
+	b.WriteString("// Replace each panic with an implementation. This file is not overwritten\n")
+	b.WriteString("// when bindings are regenerated.\n\n")
+	b.WriteString("func init() {\n")
+	b.WriteString(s.init.String())
+	b.WriteString("}\n")
+	b.WriteString(s.funcs.String())
+	s.file.Write([]byte(b.String()))
+}
@@ -4,22 +4,34 @@
 package bindgen
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
 	"go/token"
+	"go/types"
+	"io"
 	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/ydnar/wasm-tools-go/internal/codec"
 	"github.com/ydnar/wasm-tools-go/internal/go/gen"
 	"github.com/ydnar/wasm-tools-go/internal/relpath"
 	"github.com/ydnar/wasm-tools-go/wit"
 	"golang.org/x/tools/go/packages"
+
+	// Generated host bindings are type-checked against wazero.
+	_ "github.com/tetratelabs/wazero"
 )
 
 var writeGoFiles = flag.Bool("write", false, "write generated Go files")
@@ -72,13 +84,22 @@ func writeFile(t *testing.T, dir, pkgPath string, file *gen.File) {
 	}
 }
 
+// discardLogger discards warnings logged by generated testdata, such as functions skipped by the target.
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
 var canGo = sync.OnceValue[bool](func() bool {
 	err := exec.Command("go", "version").Run()
 	return err == nil
 })
 
+// canCgo reports whether cgo is enabled, which is required to type-check host bindings for wasmtime.
+var canCgo = sync.OnceValue[bool](func() bool {
+	out, err := exec.Command("go", "env", "CGO_ENABLED").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "1"
+})
+
 // validateGeneratedGo loads the Go package(s) generated
-func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
+func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string, opts ...Option) {
 	if !canGo() {
 		t.Log("skipping test: can't run go (TinyGo without fork?)")
 		return
@@ -103,10 +124,11 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		return
 	}
 
-	pkgs, err := Go(res,
+	pkgs, err := Go(res, append([]Option{
 		GeneratedBy("test"),
 		PackageRoot(pkgPath),
-	)
+		Logger(discardLogger),
+	}, opts...)...)
 	if err != nil {
 		t.Error(err)
 		return
@@ -141,6 +163,9 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 				t.Error(err)
 			}
 			cfg.Overlay[path] = src // Keep unformatted file for more testing
+			if strings.HasSuffix(file.Name, "_test.go") {
+				cfg.Tests = true
+			}
 		}
 	}
 
@@ -169,7 +194,15 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		}
 
 		// Verify number of files
-		count := len(goPkg.OtherFiles)
+		count := len(goPkg.OtherFiles) + len(goPkg.IgnoredFiles) // e.g. files excluded by build constraints
+		if goPkg.ID == goPkg.PkgPath {
+			// Test files are only in the test variant of a package.
+			for name := range pkg.Files {
+				if strings.HasSuffix(name, "_test.go") {
+					count++
+				}
+			}
+		}
 		// t.Logf("Go package: %s %t", goPkg.PkgPath, goPkg.Types.Complete())
 		for _, f := range goPkg.GoFiles {
 			count++
@@ -181,7 +214,7 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		}
 		if count < len(pkg.Files) {
 			t.Errorf("%d files in package %s; expected %d:\n%s", count, pkg.Path, len(pkg.Files),
-				strings.Join(append(goPkg.GoFiles, goPkg.OtherFiles...), "\n"))
+				strings.Join(append(append(goPkg.GoFiles, goPkg.OtherFiles...), goPkg.IgnoredFiles...), "\n"))
 		}
 
 		// Verify generated names
@@ -206,21 +239,899 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 			}
 		}
 	}
+
+	validateWasm32Layout(t, out, pkgMap, cfg.Overlay)
+}
+
+// wasm32Sizes implements [types.Sizes] for TinyGo on 32-bit WebAssembly,
+// with 4-byte pointers and 8-byte alignment of 64-bit integers and floats.
+// Unlike [types.StdSizes], the size of a struct includes its trailing padding.
+type wasm32Sizes struct{}
+
+var wasm32Std = types.StdSizes{WordSize: 4, MaxAlign: 8}
+
+func (s wasm32Sizes) Alignof(T types.Type) int64 {
+	switch t := T.Underlying().(type) {
+	case *types.Array:
+		return s.Alignof(t.Elem())
+	case *types.Struct:
+		var align int64 = 1
+		for i := 0; i < t.NumFields(); i++ {
+			align = max(align, s.Alignof(t.Field(i).Type()))
+		}
+		return align
+	}
+	return wasm32Std.Alignof(T)
+}
+
+func (s wasm32Sizes) Offsetsof(fields []*types.Var) []int64 {
+	offsets := make([]int64, len(fields))
+	var offset int64
+	for i, f := range fields {
+		offset = alignTo(offset, s.Alignof(f.Type()))
+		offsets[i] = offset
+		offset += s.Sizeof(f.Type())
+	}
+	return offsets
+}
+
+func (s wasm32Sizes) Sizeof(T types.Type) int64 {
+	switch t := T.Underlying().(type) {
+	case *types.Array:
+		return t.Len() * s.Sizeof(t.Elem())
+	case *types.Struct:
+		n := t.NumFields()
+		if n == 0 {
+			return 0
+		}
+		fields := make([]*types.Var, n)
+		for i := range fields {
+			fields[i] = t.Field(i)
+		}
+		offsets := s.Offsetsof(fields)
+		return alignTo(offsets[n-1]+s.Sizeof(fields[n-1].Type()), s.Alignof(t))
+	}
+	return wasm32Std.Sizeof(T)
+}
+
+func alignTo(x, a int64) int64 {
+	return (x + a - 1) / a * a
+}
+
+// validateWasm32Layout type-checks generated packages with layout assertions for
+// types with pointers, which are constrained to TinyGo on WebAssembly.
+// The packages are loaded for GOARCH=wasm with the tinygo.wasm build tag, then
+// type-checked again with the sizes of TinyGo, which has 4-byte pointers.
+// GOOS=js is used as the Go toolchain does not support GOOS=wasip2.
+func validateWasm32Layout(t *testing.T, dir string, pkgMap map[string]*gen.Package, overlay map[string][]byte) {
+	var paths []string
+	for path, pkg := range pkgMap {
+		if pkg.Files[layoutWasmFile] != nil {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	cfg := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedTypes | packages.NeedSyntax,
+		Dir:        dir,
+		Env:        append(os.Environ(), "GOOS=js", "GOARCH=wasm"),
+		BuildFlags: []string{"-tags=" + layoutWasmBuild},
+		Fset:       token.NewFileSet(),
+		Overlay:    overlay,
+	}
+	goPackages, err := packages.Load(cfg, paths...)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, goPkg := range goPackages {
+		if !slices.ContainsFunc(goPkg.GoFiles, func(f string) bool { return filepath.Base(f) == layoutWasmFile }) {
+			t.Errorf("%s not loaded in package %s for GOARCH=wasm", layoutWasmFile, goPkg.PkgPath)
+			continue
+		}
+		for _, err := range goPkg.Errors {
+			// Type errors are reported with the sizes of GOARCH=wasm, which has 8-byte pointers.
+			if err.Kind == packages.TypeError || (err.Kind == packages.ListError && err.Pos == "") {
+				continue
+			}
+			t.Error(err)
+		}
+		imports := make(map[string]*types.Package)
+		for _, imp := range goPkg.Types.Imports() {
+			imports[imp.Path()] = imp
+		}
+		conf := types.Config{
+			Importer: importerFunc(func(path string) (*types.Package, error) {
+				if imp := imports[path]; imp != nil {
+					return imp, nil
+				}
+				return nil, fmt.Errorf("package %s not imported by %s", path, goPkg.PkgPath)
+			}),
+			Sizes: wasm32Sizes{},
+			Error: func(err error) { t.Error(err) },
+		}
+		conf.Check(goPkg.PkgPath, cfg.Fset, goPkg.Syntax, nil)
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// testdataOptions are the sets of options used to generate Go for each WIT file in testdata.
+// Host bindings are generated for a host runtime, if set, and type-checked against it.
+// Host bindings for wasmtime are only type-checked if cgo is enabled, as wasmtime-go requires it.
+var testdataOptions = []struct {
+	name string
+	host string
+	opts []Option
+}{
+	{"", "", nil},
+	{"wasip1", "", []Option{Target(TargetWASIP1)}},
+	{"interfaces", "", []Option{Fakes(true), ResultErrors(true), Stubs(true)}},
+	{"context-params", "", []Option{ContextParams(true), Fakes(true), ResultErrors(true)}},
+	{"option-pointers", "", []Option{OptionPointers(true), Fakes(true), ResultErrors(true), Stubs(true), CachedImports("wasi:random/insecure-seed#insecure-seed")}},
+	{"layout-tests", "", []Option{LayoutTests(true), Plugins(&testPlugin{}), RecordFuncs(true)}},
+	{"anonymous-types/positional", "", []Option{AnonymousTypes(wit.PositionalTypeNames)}},
+	{"anonymous-types/hashed", "", []Option{AnonymousTypes(wit.HashedTypeNames)}},
+	{"host/wazero", HostWazero, []Option{Host(true), HostRuntime(HostWazero)}},
+	{"host/wasmtime", HostWasmtime, []Option{Host(true), HostRuntime(HostWasmtime)}},
 }
 
+// TestGenerateTestdata generates Go for each WIT file in testdata once per option set,
+// and type-checks the generated packages. Host bindings are only generated for worlds
+// whose imported functions are all supported by host bindings.
 func TestGenerateTestdata(t *testing.T) {
 	if testing.Short() {
 		// t.Skip is not available in TinyGo, requires runtime.Goexit()
 		return
 	}
-	err := loadTestdata(func(path string, res *wit.Resolve) error {
-		t.Run(path, func(t *testing.T) {
-			origin := "wit/bindgen/" + strings.TrimSuffix(strings.TrimPrefix(path, testdataPath), ".wit.json")
-			validateGeneratedGo(t, res, origin)
+	for _, set := range testdataOptions {
+		name := set.name
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := loadTestdata(func(file string, res *wit.Resolve) error {
+				t.Run(file, func(t *testing.T) {
+					if set.host != "" {
+						_, err := Go(res, set.opts...)
+						if errors.Is(err, errUnsupportedHost) {
+							t.Log(err)
+							return
+						}
+						if set.host == HostWasmtime && !canCgo() {
+							t.Log("skipping type check: wasmtime-go requires cgo")
+							generateGo(t, res, set.opts...)
+							return
+						}
+					}
+					origin := path.Join("wit/bindgen", set.name, strings.TrimSuffix(strings.TrimPrefix(file, testdataPath), ".wit.json"))
+					validateGeneratedGo(t, res, origin, set.opts...)
+				})
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
 		})
+	}
+}
+
+// loadWIT returns a [wit.Resolve] for src, which is either JSON or WIT text.
+// If src starts with "testdata/", the JSON file at that path is loaded.
+func loadWIT(t *testing.T, src string) *wit.Resolve {
+	var res *wit.Resolve
+	var err error
+	switch {
+	case strings.HasPrefix(src, "testdata/"):
+		res, err = wit.LoadJSON(path.Join(testdataPath, strings.TrimPrefix(src, "testdata/")))
+	case strings.HasPrefix(strings.TrimSpace(src), "{"):
+		res, err = wit.DecodeJSON(strings.NewReader(src))
+	default:
+		res, err = wit.LoadFS(fstest.MapFS{"test.wit": {Data: []byte(src)}}, "test.wit")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// generateGo generates Go from res with opts, rooted at example.com,
+// and returns the source of each file keyed by its package path and file name.
+func generateGo(t *testing.T, res *wit.Resolve, opts ...Option) map[string]string {
+	pkgs, err := Go(res, append([]Option{GeneratedBy("test"), PackageRoot("example.com"), Logger(discardLogger)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			b, err := f.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[pkg.Path+"/"+f.Name] = string(b)
+		}
+	}
+	return files
+}
+
+// TestGenerate generates Go from WIT, checks the generated source of matching files,
+// then type-checks the generated packages.
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string // WIT, JSON, or a testdata path
+		opts []Option
+		// want maps a file name, or a path suffix relative to example.com, to strings
+		// the file must contain. The empty key matches every file.
+		want    map[string][]string
+		notWant map[string][]string
+	}{
+		{
+			// Flags with more than 32 flags are represented as cm.Flags with [N]uint32 storage.
+			name: "large-flags",
+			src: `package foo:large-flags;
+
+interface i {
+	flags big {` + largeFlags(40) + `}
+	record holder { a: u8, b: big }
+	get: func(v: big) -> big;
+}
+
+world w {
+	import i;
+}
+`,
+			opts: []Option{LayoutTests(true)},
+			want: map[string][]string{"i.wit.go": {"type Big struct {\n\tcm.Flags[[2]uint32, BigFlag]\n}\n"}},
+		},
+		{
+			name: "deprecated",
+			src: `{
+		"worlds": [{"name": "w", "imports": {"interface-0": {"interface": 0}}, "exports": {}, "package": 0}],
+		"interfaces": [{
+			"name": "i",
+			"types": {"t": 0},
+			"functions": {"f": {"name": "f", "kind": "freestanding", "params": [], "results": [], "stability": {"stable": {"since": "0.2.0", "deprecated": "0.2.1"}}}},
+			"stability": {"stable": {"since": "0.2.0", "deprecated": "0.2.2"}},
+			"package": 0
+		}],
+		"types": [{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}, "stability": {"stable": {"since": "0.2.0", "deprecated": "0.2.2"}}}],
+		"packages": [{"name": "foo:deprecated@0.2.2", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+	}`,
+			want: map[string][]string{"i.wit.go": {
+				"//\n// Deprecated: this WIT interface is deprecated as of version 0.2.2.\npackage i\n",
+				"//\n// Deprecated: this WIT type is deprecated as of version 0.2.2.\ntype T uint32\n",
+				"//\n// Deprecated: this WIT function is deprecated as of version 0.2.1.\n",
+			}},
+		},
+		{
+			// Results with no payloads and named bool types are represented as Go bools,
+			// and lowered to a single i32.
+			name: "bool-results",
+			src: `package foo:bools;
+
+interface i {
+	type flag = bool;
+	f: func(r: result) -> result;
+	g: func(x: flag) -> flag;
+}
+
+world w {
+	import i;
+	export i;
+}
+`,
+			want: map[string][]string{"": {
+				"return cm.Result(cm.U32ToBool(wasmimport_F(cm.BoolToU32(r))))\n",
+				"func wasmimport_F(r uint32) uint32\n",
+				"return Flag(cm.U32ToBool(wasmimport_G(cm.BoolToU32(x))))\n",
+				"func wasmexport_F(r uint32) uint32 {\n\treturn cm.BoolToU32(ExportF(cm.Result(cm.U32ToBool(r))))\n",
+				"return cm.BoolToU32(ExportG(Flag(cm.U32ToBool(x))))\n",
+			}},
+		},
+		{
+			// Anonymous options in params and single results are represented as pointers,
+			// except for options of options. Options nested in other types are unchanged.
+			name: "option-pointers-mode",
+			src: `package foo:options;
+
+interface i {
+	type maybe = option<u32>;
+	resource r {
+		get: func(key: option<string>) -> option<string>;
+	}
+	f: func(x: option<u32>, y: maybe) -> option<string>;
+	g: func(x: option<option<u32>>) -> option<option<u32>>;
+	h: func(a: option<u64>, b: u64, c: u64, d: u64, e: u64, f: u64, g: u64, h: u64, i: u64, j: u64, k: u64, l: u64, m: u64, n: u64, o: u64, p: u64, q: u64);
+	l: func(x: list<option<u32>>) -> list<option<u32>>;
+}
+
+world w {
+	import i;
+	export i;
+}
+`,
+			opts: []Option{OptionPointers(true)},
+			want: map[string][]string{"": {
+				"func (self R) Get(key *string) *string {\n\tvar result cm.Option[string]\n\tself.wasmimport_Get(cm.OptionOf(key), &result)\n\treturn result.Some()\n",
+				"func F(x *uint32, y Maybe) *string {\n",
+				"func G(x cm.Option[cm.Option[uint32]]) cm.Option[cm.Option[uint32]] {\n",
+				"params := wasmimport_HParams{cm.OptionOf(a), b,",
+				"func L(x cm.List[cm.Option[uint32]]) cm.List[cm.Option[uint32]] {\n",
+				"result := cm.OptionOf(ExportF(x.Some(), y))\n",
+				"ExportH(params.a.Some(), params.b,",
+				"var FPostReturn = func(result *string) {}\n",
+			}},
+		},
+		{
+			name: "char",
+			src: `package foo:chars;
+
+interface text {
+	type letter = char;
+	upper: func(c: letter) -> letter;
+	contains: func(s: string, c: char) -> bool;
+}
+
+world w {
+	import text;
+	export next: func(c: char) -> char;
+}
+`,
+			want: map[string][]string{"": {
+				"type Letter rune\n",
+				"return wasmimport_Upper(cm.LowerChar(c))\n",
+				"wasmimport_Contains(s, cm.LowerChar(c))",
+				"return cm.LowerChar(Next(c))\n",
+			}},
+		},
+		{
+			name: "casing/default",
+			src:  casingWIT,
+			want: map[string][]string{"": {"type HTTPRequest struct", "RequestID uint64", "\tURL ", "func GetHTTPRequest(id uint64)"}},
+		},
+		{
+			name: "casing/initialisms",
+			src:  casingWIT,
+			opts: []Option{NameCasing(InitialismCasing)},
+			want: map[string][]string{"": {"type HTTPRequest struct", "RequestID uint64", "\tURL ", "func GetHTTPRequest(id uint64)"}},
+		},
+		{
+			name: "casing/preserve",
+			src:  casingWIT,
+			opts: []Option{NameCasing(PreserveCasing)},
+			want: map[string][]string{"": {"type HttpRequest struct", "RequestId uint64", "\tUrl ", "func GetHttpRequest(id uint64)"}},
+		},
+		{
+			name: "cached-imports",
+			src:  insecureSeedWIT,
+			opts: []Option{CachedImports("foo:random/insecure-seed#insecure-seed")},
+			want: map[string][]string{"insecure-seed.wit.go": {
+				"return insecureSeedOnce.Get(func() [2]uint64 {\n",
+				"var insecureSeedOnce cm.Once[[2]uint64]\n",
+			}},
+			notWant: map[string][]string{"insecure-seed.wit.go": {"getU64Once"}},
+		},
+		{
+			name: "context-params",
+			src:  insecureSeedWIT,
+			opts: []Option{ContextParams(true), Interfaces(true), CachedImports("foo:random/insecure-seed#insecure-seed")},
+			want: map[string][]string{"insecure-seed.wit.go": {
+				"func InsecureSeed(ctx context.Context) [2]uint64 {\n",
+				"return insecureSeedOnce.Get(func() [2]uint64 {\n",
+				"func GetU64(ctx_ context.Context, ctx uint64) uint64 {\n",
+				"func (Client) GetU64(ctx_ context.Context, ctx uint64) uint64 {\n\treturn GetU64(ctx_, ctx)\n",
+				"wasmimport_GetU64(ctx)",
+			}},
+		},
+		{
+			// The shape of variant v and the result of f is tuple<u32, u32, u32> with 32-bit
+			// pointers, and string with 64-bit pointers.
+			name: "host-shims",
+			src: `package foo:shims;
+
+interface i {
+	variant v { a(tuple<u32, u32, u32>), b(string) }
+	f: func(x: v) -> result<tuple<u32, u32, u32>, string>;
+}
+
+world w {
+	import i;
+}
+`,
+			opts: []Option{HostShims(true)},
+			want: map[string][]string{
+				"i.wit.go": {
+					"type V cm.Variant[uint8, cm.ArchShape[[3]uint32, string], [3]uint32]\n",
+					"var result cm.ArchResult[[3]uint32, string, [3]uint32, string]\n",
+				},
+				"i.wasm.wit.go": {"//go:build !wasip1 && wasm\n", "//go:wasmimport foo:shims/i f\n"},
+				"i.host.wit.go": {"//go:build !wasip1 && !wasm\n", "panic(\"imported function not available on this architecture: foo:shims/i f\")"},
+			},
+		},
+		{
+			name: "host/wazero",
+			src:  hostWIT,
+			opts: []Option{Host(true)},
+			want: map[string][]string{
+				"logging/logging.wit.go": {
+					"Log(ctx context.Context, level Level, msg string)\n",
+					"Many(ctx context.Context, a uint64, b_ uint64,",
+					"func Instantiate(ctx context.Context, r wazero.Runtime, h Host) (api.Module, error) {\n",
+				},
+				"w/w.wit.go": {"Ping(ctx context.Context, n int32) int8\n"},
+			},
+		},
+		{
+			name: "host/wasmtime",
+			src:  hostWIT,
+			opts: []Option{Host(true), HostRuntime(HostWasmtime)},
+			want: map[string][]string{
+				"logging/logging.wit.go": {
+					"Log(ctx context.Context, level Level, msg string)\n",
+					"func Define(ctx context.Context, l *wasmtime.Linker, h Host) error {\n",
+					"if err := l.FuncNew(ModuleName, \"many\", ",
+				},
+				"w/w.wit.go": {"Ping(ctx context.Context, n int32) int8\n"},
+			},
+		},
+		{
+			// Enums marshal as text, returning an error for values out of range.
+			name: "enum-text",
+			src:  "package foo:enums;\n\ninterface i {\n\tenum color { red, green }\n}\n\nworld w {\n\timport i;\n}\n",
+			want: map[string][]string{"i.wit.go": {
+				"func (e Color) MarshalText() ([]byte, error) {\n\treturn cm.CaseMarshalText(stringsColor[:], e)\n}\n",
+			}},
+		},
+		{
+			// Records have a NewX constructor unless they have no fields or it would conflict
+			// with a freestanding function, and a Validate method if they have handle or enum fields,
+			// or fields of record types with a Validate method.
+			name: "record-funcs",
+			src: `package foo:records;
+
+interface i {
+	enum color { red, green }
+	resource r;
+	record point { x: s32, y: s32 }
+	record pixel { at: point, color: color, owner: own<r> }
+	record shape { kind: color }
+	record empty {}
+	record checked { validate: color }
+	record canvas { origin: point, pixel: pixel }
+	new-shape: func(kind: color) -> shape;
+}
+
+world w {
+	import i;
+}
+`,
+			opts: []Option{RecordFuncs(true)},
+			want: map[string][]string{"i.wit.go": {
+				"// NewPoint returns a [Point] with the specified fields.\nfunc NewPoint(x int32, y int32) Point {\n\treturn Point{X: x, Y: y}\n}\n",
+				"func NewPixel(at Point, color Color, owner R) Pixel {\n\treturn Pixel{At: at, Color: color, Owner: owner}\n}\n",
+				"func (self *Pixel) Validate() error {\n\tif self.Color >= 2 {\n\t\treturn errors.New(\"pixel.color: enum value out of range\")\n\t}\n\tif self.Owner == cm.ResourceNone {\n\t\treturn errors.New(\"pixel.owner: invalid handle\")\n\t}\n\treturn nil\n}\n",
+				"func (self *Shape) Validate() error {\n",
+				"func NewChecked(validate Color) Checked {\n",
+				"func (self *Canvas) Validate() error {\n\tif err := self.Pixel.Validate(); err != nil {\n\t\treturn fmt.Errorf(\"canvas.pixel: %w\", err)\n\t}\n\treturn nil\n}\n",
+			}},
+			notWant: map[string][]string{"i.wit.go": {
+				"// NewShape returns a [Shape] with the specified fields.",
+				"return Shape{Kind: kind}",
+				"func NewEmpty(",
+				"func (self *Point) Validate() error",
+				"func (self *Checked) Validate() error",
+			}},
+		},
+		{
+			// Record constructors and Validate methods are not generated by default.
+			name:    "record-funcs/default",
+			src:     "testdata/wasi/0.2.0/clocks-timezone.wit.json",
+			notWant: map[string][]string{"": {"func NewDateTime(", "func NewTimezoneDisplay(", ") Validate() error"}},
+		},
+		{
+			name: "inline-interfaces",
+			src:  "testdata/wit-parser/shared-types.wit.json",
+			want: map[string][]string{
+				"foo/shared-items/foo/foo/foo.wit.go": {"//go:wasmimport foo a\n"},
+				"foo/shared-items/foo/bar/bar.wit.go": {"//go:wasmexport bar#a\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := loadWIT(t, tt.src)
+			files := generateGo(t, res, tt.opts...)
+			for key, wants := range tt.want {
+				src, ok := matchFiles(files, key)
+				if !ok {
+					t.Errorf("file %s not generated", key)
+					continue
+				}
+				for _, want := range wants {
+					if !strings.Contains(src, want) {
+						t.Errorf("%s does not contain %q:\n%s", key, want, src)
+					}
+				}
+			}
+			for key, notWants := range tt.notWant {
+				src, _ := matchFiles(files, key)
+				for _, notWant := range notWants {
+					if strings.Contains(src, notWant) {
+						t.Errorf("%s contains %q:\n%s", key, notWant, src)
+					}
+				}
+			}
+			if strings.HasPrefix(tt.name, "host/wasmtime") && !canCgo() {
+				t.Log("skipping type check: wasmtime-go requires cgo")
+				return
+			}
+			validateGeneratedGo(t, res, "wit/bindgen/"+tt.name, tt.opts...)
+		})
+	}
+}
+
+// matchFiles returns the concatenated source of files whose path ends with key.
+// The empty key matches all files.
+func matchFiles(files map[string]string, key string) (string, bool) {
+	var src strings.Builder
+	var ok bool
+	for _, name := range codec.Keys(files) {
+		if key == "" || strings.HasSuffix(name, "/"+key) {
+			src.WriteString(files[name])
+			ok = true
+		}
+	}
+	return src.String(), ok
+}
+
+func largeFlags(n int) string {
+	var flags []string
+	for i := 0; i < n; i++ {
+		flags = append(flags, "flag"+strconv.Itoa(i))
+	}
+	return strings.Join(flags, ", ")
+}
+
+const casingWIT = `package foo:casing;
+
+interface requests {
+	record http-request {
+		request-id: u64,
+		url: string,
+	}
+	get-http-request: func(id: u64) -> http-request;
+}
+
+world w {
+	import requests;
+}
+`
+
+const insecureSeedWIT = `package foo:random@0.2.0;
+
+interface insecure-seed {
+	insecure-seed: func() -> tuple<u64, u64>;
+	get-u64: func(ctx: u64) -> u64;
+}
+
+world w {
+	import insecure-seed;
+}
+`
+
+const hostWIT = `package foo:host;
+
+interface logging {
+	enum level { debug, info, warn }
+	flags caps { read, write }
+	log: func(level: level, msg: string);
+	get-caps: func() -> caps;
+	names: func(prefix: string) -> list<string>;
+	sum: func(values: list<u64>) -> u64;
+	next: func(c: char, x: f32, y: f64) -> char;
+	many: func(a: u64, b: u64, c: u64, d: u64, e: u64, f: u64, g: u64, h: u64, i: u64, j: u64, k: u64, l: u64, m: u64, n: u64, o: u64, p: u64, q: bool) -> bool;
+}
+
+world w {
+	import logging;
+	import ping: func(n: s32) -> s8;
+}
+`
+
+// TestGenerateErrors verifies that [Go] returns an error for invalid options.
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		opts []Option
+		is   error // if not nil, the expected error
+	}{
+		{"cached import with params", insecureSeedWIT, []Option{CachedImports("foo:random/insecure-seed@0.2.0#get-u64")}, nil},
+		{"invalid build constraint", insecureSeedWIT, []Option{BuildTags(map[string]string{"foo:random/insecure-seed": "a b"})}, nil},
+		{"host resource", "package foo:host;\n\ninterface i {\n\tresource r;\n}\n\nworld w {\n\timport i;\n}\n", []Option{Host(true)}, errUnsupportedHost},
+		{"host record", "package foo:host;\n\ninterface i {\n\trecord r { x: u32 }\n\tf: func(r: r);\n}\n\nworld w {\n\timport i;\n}\n", []Option{Host(true)}, errUnsupportedHost},
+		{"host record wasmtime", "package foo:host;\n\nworld w {\n\timport f: func() -> tuple<u32, u32>;\n}\n", []Option{Host(true), HostRuntime(HostWasmtime)}, errUnsupportedHost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Go(loadWIT(t, tt.src), tt.opts...)
+			if err == nil {
+				t.Errorf("Go: expected error")
+			} else if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("Go: %v, expected %v", err, tt.is)
+			}
+		})
+	}
+}
+
+func TestGenerateBuildTags(t *testing.T) {
+	res, err := wit.LoadFS(fstest.MapFS{"tags.wit": {Data: []byte(`package foo:tags@0.1.0;
+
+interface stable {
+	f: func() -> u32;
+}
+
+interface unstable {
+	record r { x: u32 }
+	record s { name: string }
+	g: func() -> r;
+	k: func() -> s;
+}
+
+interface other {
+	h: func();
+}
+
+world w {
+	import stable;
+	import unstable;
+	import other;
+}
+`)}}, "tags.wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com"), LayoutTests(true), BuildTags(map[string]string{
+		"foo:tags/unstable":      "unstable",
+		"foo:tags/other":         "a || b",
+		"foo:tags/other@0.1.0":   "other || b",
+		"foo:tags/unknown@0.1.0": "unknown",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	builds := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			if f.IsGo() {
+				builds[path.Base(pkg.Path)+"/"+f.Name] = f.Build
+			}
+		}
+	}
+	for name, want := range map[string]string{
+		"stable/stable.wit.go":              BuildDefault,
+		"unstable/unstable.wit.go":          BuildDefault + " && unstable",
+		"unstable/abi_layout.wit.go":        BuildDefault + " && unstable && " + layoutBuild,
+		"unstable/abi_layout_wasm32.wit.go": BuildDefault + " && unstable && tinygo.wasm",
+		"unstable/abi_layout.wit_test.go":   BuildDefault + " && unstable",
+		"other/other.wit.go":                BuildDefault + " && (other || b)",
+	} {
+		if got, ok := builds[name]; !ok || got != want {
+			t.Errorf("%s: build %q, expected %q (files: %v)", name, got, want, builds)
+		}
+	}
+}
+
+func TestGenerateHostInternStrings(t *testing.T) {
+	res := loadWIT(t, hostWIT)
+	for _, runtime := range []string{HostWazero, HostWasmtime} {
+		files := generateGo(t, res, Host(true), HostRuntime(runtime), InternStrings(true))
+		src, ok := matchFiles(files, hostABIFile)
+		if !ok {
+			t.Errorf("%s: no %s generated", runtime, hostABIFile)
+		} else if !strings.Contains(src, "return cm.InternBytes(") {
+			t.Errorf("%s: %s does not intern lifted strings:\n%s", runtime, hostABIFile, src)
+		}
+	}
+}
+
+// TestGenerateWASIP1Skipped verifies that exported functions and imported functions
+// with non-scalar params are skipped with a warning for [TargetWASIP1], rather than silently dropped.
+func TestGenerateWASIP1Skipped(t *testing.T) {
+	res := loadWIT(t, `package foo:wasip1;
+
+interface i {
+	f: func(x: u32) -> u32;
+}
+
+world w {
+	import g: func(x: u32);
+	import h: func(s: string);
+	export i;
+}
+`)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	files := generateGo(t, res, Target(TargetWASIP1), Logger(logger))
+	if src, ok := matchFiles(files, "w.wit.go"); !ok || !strings.Contains(src, "//go:wasmimport $root g\n") {
+		t.Errorf("imported function g not generated")
+	}
+	for _, want := range []string{"level=WARN", `owner=foo:wasip1/i function=f`, `owner=foo:wasip1/w function=h`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestGenerateCoreImports verifies that each go:wasmimport directive generated for a world
+// is a Core WebAssembly import returned by [wit.World.CoreImports].
+func TestGenerateCoreImports(t *testing.T) {
+	err := loadTestdata(func(path string, res *wit.Resolve) error {
+		for _, w := range res.Worlds {
+			if w.Package == nil {
+				continue
+			}
+			id := w.ID()
+			imports := make(map[string]bool)
+			for _, imp := range w.CoreImports() {
+				imports[imp.Module+" "+imp.Name] = true
+			}
+			pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com/x"), World(id.String()))
+			if err != nil {
+				t.Errorf("%s: %s: %v", path, id.String(), err)
+				continue
+			}
+			for _, pkg := range pkgs {
+				for _, file := range pkg.Files {
+					b, err := file.Bytes()
+					if err != nil {
+						t.Fatal(err)
+					}
+					for _, line := range strings.Split(string(b), "\n") {
+						name, ok := strings.CutPrefix(line, "//go:wasmimport ")
+						if ok && !imports[name] {
+							t.Errorf("%s: %s: %s: go:wasmimport %s not in World.CoreImports", path, id.String(), file.Name, name)
+						}
+					}
+				}
+			}
+		}
 		return nil
 	})
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+// testPlugin declares a constant with the WIT name of each imported type,
+// and references each imported freestanding function.
+type testPlugin struct {
+	BasePlugin
+	interfaces int
+}
+
+func (p *testPlugin) Interface(file *File, dir wit.Direction, i *wit.Interface) error {
+	p.interfaces++
+	return nil
+}
+
+func (p *testPlugin) TypeDef(file *File, dir wit.Direction, t *wit.TypeDef, goName string) error {
+	if dir != wit.Imported || t.Root() != t {
+		return nil
+	}
+	file.Write("const " + file.DeclareName(goName+"WITName") + " = " + strconv.Quote(t.TypeName()) + "\n\n")
+	return nil
+}
+
+func (p *testPlugin) Function(file *File, dir wit.Direction, f *wit.Function, goName string) error {
+	if dir != wit.Imported || !f.IsFreestanding() {
+		return nil
+	}
+	file.Write("var _ = " + goName + "\n\n")
+	return nil
+}
+
+func TestGenerateSourceHeader(t *testing.T) {
+	res, err := wit.LoadJSON(testdataPath + "/wasi/0.2.0/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), GeneratorVersion("v1.2.3"), PackageRoot("example.com"), World("wasi:cli/command"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by test. DO NOT EDIT.\n// Source: WIT world wasi:cli/command@0.2.0, test v1.2.3\n"
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			if !file.IsGo() {
+				continue
+			}
+			b, err := file.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(b), want) {
+				t.Errorf("%s/%s: expected header %q", pkg.Path, file.Name, want)
+			}
+		}
+	}
+}
+
+func TestGenerateSources(t *testing.T) {
+	path := testdataPath + "/wasi/0.2.0/cli.wit.json"
+	res, err := wit.LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := wit.FindSources(res, path+".golden.wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com"), World("wasi:cli/command"), Sources(sources))
+	if err != nil {
+		t.Fatal(err)
+	}
+	streams, err := res.Interface("wasi:io/streams@0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range []wit.Node{streams.TypeDefs.Get("input-stream"), streams.Functions.Get("[method]input-stream.read")} {
+		pos, ok := sources[node]
+		if !ok {
+			t.Fatalf("%s: no source", node.WIT(nil, ""))
+		}
+		want := "//\n// Source: " + pos.String() + "\n"
+		found := false
+		for _, pkg := range pkgs {
+			for _, file := range pkg.Files {
+				if strings.Contains(string(file.Content), want) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: generated code does not contain %q", node.WIT(nil, ""), want)
+		}
+	}
+}
+
+func TestPluginInterface(t *testing.T) {
+	res, err := wit.LoadJSON(testdataPath + "/wasi/0.2.0/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testPlugin{}
+	_, err = Go(res, GeneratedBy("test"), PackageRoot("example.com"), World("wasi:cli/command"), Plugins(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.interfaces == 0 {
+		t.Errorf("Plugin.Interface: not called")
+	}
+}
+
+type errorPlugin struct{ BasePlugin }
+
+func (errorPlugin) Interface(file *File, dir wit.Direction, i *wit.Interface) error {
+	return errors.New("plugin error")
+}
+
+func TestPluginError(t *testing.T) {
+	res, err := wit.LoadJSON(testdataPath + "/wasi/0.2.0/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Go(res, GeneratedBy("test"), PackageRoot("example.com"), Plugins(errorPlugin{}))
+	if err == nil || !strings.Contains(err.Error(), "plugin error") {
+		t.Errorf("Go: %v, expected plugin error", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build tools
+
+package bindgen
+
+// Host bindings generated for wasmtime are type-checked against wasmtime-go in tests.
+// It is imported here rather than in a test, so the module requires it without linking
+// its cgo library into the test binary.
+import _ "github.com/bytecodealliance/wasmtime-go/v22"
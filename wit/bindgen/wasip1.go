@@ -0,0 +1,172 @@
+package bindgen
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/stringio"
+	"github.com/ydnar/wasm-tools-go/wit"
+)
+
+// isWASIP1Function returns true if f can be imported with go:wasmimport by the Go wasip1 port,
+// which restricts params and results to scalar types, and permits at most one result.
+func isWASIP1Function(f *wit.Function) bool {
+	if len(f.Results) > 1 {
+		return false
+	}
+	for _, p := range f.Params {
+		if !isWASIP1Scalar(p.Type) {
+			return false
+		}
+	}
+	for _, r := range f.Results {
+		if !isWASIP1Scalar(r.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWASIP1Scalar returns true if t is represented by a single Core WebAssembly value
+// without a pointer, such as an integer, float, bool, enum, flags, or resource handle.
+func isWASIP1Scalar(t wit.Type) bool {
+	switch t := t.(type) {
+	case wit.String:
+		return false
+	case wit.Primitive:
+		return true
+	case *wit.TypeDef:
+		switch kind := t.Root().Kind.(type) {
+		case wit.Type:
+			return isWASIP1Scalar(kind)
+		case *wit.Enum, *wit.Resource, *wit.Own, *wit.Borrow:
+			return true
+		case *wit.Flags:
+			return kind.Size() <= 4
+		case *wit.Variant:
+			return kind.Enum() != nil
+		}
+	}
+	return false
+}
+
+// wasip1CoreRep returns the Go type used to pass a value of type t to or from
+// a go:wasmimport function in the Go wasip1 port.
+func wasip1CoreRep(t wit.Type) string {
+	switch t.Flat()[0].(type) {
+	case wit.S64:
+		return "int64"
+	case wit.U64:
+		return "uint64"
+	case wit.F32:
+		return "float32"
+	case wit.F64:
+		return "float64"
+	}
+	switch hostRoot(t).(type) {
+	case wit.S8, wit.S16, wit.S32, wit.Char:
+		return "int32"
+	}
+	return "uint32"
+}
+
+func (g *generator) defineWASIP1ImportedFunction(f *wit.Function, decl funcDecl) error {
+	dir := wit.Imported
+	if !g.define(dir, f) {
+		return nil
+	}
+
+	file := decl.f.file
+	wasmName := decl.wasm.name
+	if decl.wasm.isMethod() {
+		// The Go wasip1 port does not permit go:wasmimport on methods.
+		wasmName = file.DeclareName("wasmimport_" + g.typeRep(file, decl.f.receiver.dir, decl.f.receiver.typ) + decl.f.name)
+	}
+
+	params := decl.f.params
+	if decl.f.isMethod() {
+		params = append([]param{decl.f.receiver}, params...)
+	}
+
+	var b bytes.Buffer
+
+	// Emit docs
+	b.WriteString(g.functionDocs(dir, f, decl.f.name))
+
+	// Emit Go function
+	b.WriteString("func ")
+	if decl.f.isMethod() {
+		stringio.Write(&b, "(", decl.f.receiver.name, " ", g.typeRep(file, decl.f.receiver.dir, decl.f.receiver.typ), ") ", decl.f.name)
+	} else {
+		b.WriteString(decl.f.name)
+	}
+	b.WriteString(g.functionSignature(file, decl.f))
+	b.WriteString(" {\n")
+
+	// Convert params to Core WebAssembly types
+	args := make([]string, len(params))
+	for i, p := range params {
+		if isBool(p.typ) {
+			args[i] = file.Import(g.opts.cmPackage) + ".BoolToU32(" + p.name + ")"
+			continue
+		}
+		args[i] = wasip1CoreRep(p.typ) + "(" + g.lowerChar(file, p.typ, p.name) + ")"
+	}
+
+	// Emit call to wasmimport function
+	call := wasmName + "(" + strings.Join(args, ", ") + ")"
+	if len(decl.f.results) == 0 {
+		stringio.Write(&b, call, "\n")
+	} else if r := decl.f.results[0]; isBool(r.typ) {
+		stringio.Write(&b, "return ", g.boolFromU32(file, r.dir, r.typ, call), "\n")
+	} else {
+		stringio.Write(&b, "return ", g.typeRep(file, r.dir, r.typ), "(", call, ")\n")
+	}
+	b.WriteString("}\n\n")
+
+	// Emit wasmimport function
+	stringio.Write(&b, "//go:wasmimport ", decl.linkerName, "\n")
+	b.WriteString("//go:noescape\n")
+	stringio.Write(&b, "func ", wasmName, "(")
+	for i, p := range params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		stringio.Write(&b, p.name, " ", wasip1CoreRep(p.typ))
+	}
+	b.WriteString(")")
+	if len(decl.f.results) > 0 {
+		stringio.Write(&b, " ", wasip1CoreRep(decl.f.results[0].typ))
+	}
+	b.WriteString("\n\n")
+
+	// Write to file
+	_, err := file.Write(b.Bytes())
+	if err != nil {
+		return err
+	}
+	g.callPlugins(file, func(p Plugin, file *File) error {
+		return p.Function(file, dir, f, decl.f.name)
+	})
+
+	return g.ensureEmptyAsm(file.Package)
+}
+
+// isBool reports whether t is represented as a Go bool: a bool, a named bool type,
+// or a result with no payloads (cm.Result).
+func isBool(t wit.Type) bool {
+	switch t := hostRoot(t).(type) {
+	case wit.Bool:
+		return true
+	case *wit.TypeDef:
+		r, ok := t.Kind.(*wit.Result)
+		return ok && r.OK == nil && r.Err == nil
+	}
+	return false
+}
+
+// isChar reports whether t is a char or a named char type, represented as a Go rune.
+func isChar(t wit.Type) bool {
+	_, ok := hostRoot(t).(wit.Char)
+	return ok
+}
@@ -0,0 +1,258 @@
+package wit
+
+import (
+	"slices"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Clone returns a deep copy of res. References between definitions in the copy,
+// such as the [Package] of an [Interface], the Owner of a [TypeDef], or the types
+// of [Function] params, point to their counterparts in the copy, not to res.
+// This allows the copy to be modified, for example with [Resolve.Rename] or
+// [Resolve.Merge], without modifying res.
+func (res *Resolve) Clone() *Resolve {
+	c := &cloner{
+		packages:   make(map[*Package]*Package, len(res.Packages)),
+		worlds:     make(map[*World]*World, len(res.Worlds)),
+		interfaces: make(map[*Interface]*Interface, len(res.Interfaces)),
+		typeDefs:   make(map[*TypeDef]*TypeDef, len(res.TypeDefs)),
+		functions:  make(map[*Function]*Function),
+	}
+	out := &Resolve{}
+	for _, w := range res.Worlds {
+		out.Worlds = append(out.Worlds, c.world(w))
+	}
+	for _, i := range res.Interfaces {
+		out.Interfaces = append(out.Interfaces, c.iface(i))
+	}
+	for _, t := range res.TypeDefs {
+		out.TypeDefs = append(out.TypeDefs, c.typeDef(t))
+	}
+	for _, pkg := range res.Packages {
+		out.Packages = append(out.Packages, c.pkg(pkg))
+	}
+	return out
+}
+
+// cloner maps definitions in a [Resolve] to their copies.
+// Each definition is copied once, the first time it is referenced.
+type cloner struct {
+	packages   map[*Package]*Package
+	worlds     map[*World]*World
+	interfaces map[*Interface]*Interface
+	typeDefs   map[*TypeDef]*TypeDef
+	functions  map[*Function]*Function
+}
+
+func (c *cloner) pkg(p *Package) *Package {
+	if p == nil {
+		return nil
+	}
+	if x, ok := c.packages[p]; ok {
+		return x
+	}
+	x := &Package{Name: p.Name, Docs: p.Docs}
+	c.packages[p] = x
+	p.Interfaces.All()(func(name string, i *Interface) bool {
+		x.Interfaces.Set(name, c.iface(i))
+		return true
+	})
+	p.Worlds.All()(func(name string, w *World) bool {
+		x.Worlds.Set(name, c.world(w))
+		return true
+	})
+	return x
+}
+
+func (c *cloner) world(w *World) *World {
+	if w == nil {
+		return nil
+	}
+	if x, ok := c.worlds[w]; ok {
+		return x
+	}
+	x := &World{
+		Name:            w.Name,
+		Docs:            w.Docs,
+		Stability:       clonePtr(w.Stability),
+		ImportStability: cloneStability(w.ImportStability),
+		ExportStability: cloneStability(w.ExportStability),
+	}
+	c.worlds[w] = x
+	c.worldItems(&x.Imports, &w.Imports)
+	c.worldItems(&x.Exports, &w.Exports)
+	x.Package = c.pkg(w.Package)
+	return x
+}
+
+func (c *cloner) worldItems(dst, src *ordered.Map[string, WorldItem]) {
+	src.All()(func(name string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *Interface:
+			dst.Set(name, c.iface(item))
+		case *TypeDef:
+			dst.Set(name, c.typeDef(item))
+		case *Function:
+			dst.Set(name, c.function(item))
+		default:
+			dst.Set(name, item)
+		}
+		return true
+	})
+}
+
+func (c *cloner) iface(i *Interface) *Interface {
+	if i == nil {
+		return nil
+	}
+	if x, ok := c.interfaces[i]; ok {
+		return x
+	}
+	x := &Interface{Name: clonePtr(i.Name), Docs: i.Docs, Stability: clonePtr(i.Stability)}
+	c.interfaces[i] = x
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		x.TypeDefs.Set(name, c.typeDef(t))
+		return true
+	})
+	i.Functions.All()(func(name string, f *Function) bool {
+		x.Functions.Set(name, c.function(f))
+		return true
+	})
+	x.Package = c.pkg(i.Package)
+	return x
+}
+
+func (c *cloner) typeDef(t *TypeDef) *TypeDef {
+	if t == nil {
+		return nil
+	}
+	if x, ok := c.typeDefs[t]; ok {
+		return x
+	}
+	x := &TypeDef{Name: clonePtr(t.Name), Docs: t.Docs, Stability: clonePtr(t.Stability)}
+	c.typeDefs[t] = x
+	switch owner := t.Owner.(type) {
+	case *Interface:
+		x.Owner = c.iface(owner)
+	case *World:
+		x.Owner = c.world(owner)
+	default:
+		x.Owner = owner
+	}
+	x.Kind = c.kind(t.Kind)
+	return x
+}
+
+func (c *cloner) kind(kind TypeDefKind) TypeDefKind {
+	switch kind := kind.(type) {
+	case *TypeDef:
+		return c.typeDef(kind)
+	case *Pointer:
+		return &Pointer{Type: c.typ(kind.Type)}
+	case *Record:
+		x := &Record{Fields: slices.Clone(kind.Fields)}
+		for i := range x.Fields {
+			x.Fields[i].Type = c.typ(x.Fields[i].Type)
+		}
+		return x
+	case *Resource:
+		return &Resource{}
+	case *Own:
+		return &Own{Type: c.typeDef(kind.Type)}
+	case *Borrow:
+		return &Borrow{Type: c.typeDef(kind.Type)}
+	case *Flags:
+		return &Flags{Flags: slices.Clone(kind.Flags)}
+	case *Tuple:
+		x := &Tuple{Types: slices.Clone(kind.Types)}
+		for i := range x.Types {
+			x.Types[i] = c.typ(x.Types[i])
+		}
+		return x
+	case *Variant:
+		x := &Variant{Cases: slices.Clone(kind.Cases)}
+		for i := range x.Cases {
+			x.Cases[i].Type = c.typ(x.Cases[i].Type)
+		}
+		return x
+	case *Enum:
+		return &Enum{Cases: slices.Clone(kind.Cases)}
+	case *Option:
+		return &Option{Type: c.typ(kind.Type)}
+	case *Result:
+		return &Result{OK: c.typ(kind.OK), Err: c.typ(kind.Err)}
+	case *List:
+		return &List{Type: c.typ(kind.Type)}
+	case *Future:
+		return &Future{Type: c.typ(kind.Type)}
+	case *Stream:
+		return &Stream{Element: c.typ(kind.Element), End: c.typ(kind.End)}
+	}
+	// Primitive types are values.
+	return kind
+}
+
+func (c *cloner) typ(t Type) Type {
+	if t, ok := t.(*TypeDef); ok {
+		return c.typeDef(t)
+	}
+	return t
+}
+
+func (c *cloner) function(f *Function) *Function {
+	if f == nil {
+		return nil
+	}
+	if x, ok := c.functions[f]; ok {
+		return x
+	}
+	x := &Function{
+		Name:      f.Name,
+		Params:    c.params(f.Params),
+		Results:   c.params(f.Results),
+		Docs:      f.Docs,
+		Stability: clonePtr(f.Stability),
+	}
+	c.functions[f] = x
+	switch kind := f.Kind.(type) {
+	case *Freestanding:
+		x.Kind = &Freestanding{}
+	case *Method:
+		x.Kind = &Method{Type: c.typ(kind.Type)}
+	case *Static:
+		x.Kind = &Static{Type: c.typ(kind.Type)}
+	case *Constructor:
+		x.Kind = &Constructor{Type: c.typ(kind.Type)}
+	default:
+		x.Kind = kind
+	}
+	return x
+}
+
+func (c *cloner) params(params []Param) []Param {
+	out := slices.Clone(params)
+	for i := range out {
+		out[i].Type = c.typ(out[i].Type)
+	}
+	return out
+}
+
+func cloneStability(m map[string]*Stability) map[string]*Stability {
+	if m == nil {
+		return nil
+	}
+	x := make(map[string]*Stability, len(m))
+	for name, s := range m {
+		x[name] = clonePtr(s)
+	}
+	return x
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	x := *p
+	return &x
+}
@@ -0,0 +1,125 @@
+package wit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			want, err := res.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			clone := res.Clone()
+			got, err := clone.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Clone: JSON encoding differs from original")
+			}
+			if got, want := clone.WIT(nil, ""), res.WIT(nil, ""); got != want {
+				t.Errorf("Clone: WIT differs from original")
+			}
+			checkCloneShares(t, res, clone)
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// checkCloneShares reports an error if clone references a definition in res.
+func checkCloneShares(t *testing.T, res, clone *Resolve) {
+	orig := make(map[any]bool)
+	for _, w := range res.Worlds {
+		orig[w] = true
+		w.AllFunctions()(func(f *Function) bool {
+			orig[f] = true
+			return true
+		})
+	}
+	for _, i := range res.Interfaces {
+		orig[i] = true
+		i.Functions.All()(func(_ string, f *Function) bool {
+			orig[f] = true
+			return true
+		})
+	}
+	for _, td := range res.TypeDefs {
+		orig[td] = true
+		orig[td.Kind] = !isValueKind(td.Kind)
+	}
+	for _, p := range res.Packages {
+		orig[p] = true
+	}
+
+	check := func(kind string, v any) {
+		if orig[v] {
+			t.Errorf("Clone: %s is shared with the original", kind)
+		}
+	}
+	for _, w := range clone.Worlds {
+		check("world", w)
+		check("package", w.Package)
+		w.AllFunctions()(func(f *Function) bool {
+			check("function", f)
+			return true
+		})
+	}
+	for _, i := range clone.Interfaces {
+		check("interface", i)
+		check("package", i.Package)
+		i.Functions.All()(func(_ string, f *Function) bool {
+			check("function", f)
+			for _, p := range f.Params {
+				check("param type", p.Type)
+			}
+			for _, r := range f.Results {
+				check("result type", r.Type)
+			}
+			return true
+		})
+	}
+	for _, td := range clone.TypeDefs {
+		check("type", td)
+		check("type owner", td.Owner)
+		check("type kind", td.Kind)
+	}
+	for _, p := range clone.Packages {
+		check("package", p)
+	}
+}
+
+// isValueKind returns true if kind is a primitive type or a pointer to
+// a zero-sized type, which are indistinguishable from their copies.
+func isValueKind(kind TypeDefKind) bool {
+	switch kind.(type) {
+	case Primitive, *Resource:
+		return true
+	}
+	return false
+}
+
+func TestCloneRename(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(renameJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := res.WIT(nil, "")
+	clone := res.Clone()
+	r := clone.Interfaces[0].TypeDefs.Get("r")
+	if err := clone.Rename(r, "s"); err != nil {
+		t.Fatal(err)
+	}
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("Rename of clone modified the original:\n%s", got)
+	}
+	if got := clone.WIT(nil, ""); !strings.Contains(got, "resource s") {
+		t.Errorf("Rename of clone: resource s not found:\n%s", got)
+	}
+}
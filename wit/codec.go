@@ -1,18 +1,44 @@
 package wit
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/coreos/go-semver/semver"
+
 	"github.com/ydnar/wasm-tools-go/internal/codec"
 	"github.com/ydnar/wasm-tools-go/internal/codec/json"
+	"github.com/ydnar/wasm-tools-go/internal/version"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
 )
 
 // DecodeJSON decodes JSON from r into a [Resolve] struct.
 // It returns any error that may occur during decoding.
+//
+// DecodeJSON reads r incrementally, one JSON token at a time, so memory use is
+// proportional to the decoded [Resolve] rather than the size of the JSON document.
+// It is safe to call concurrently with different readers.
+//
+// The shape of WIT JSON varies between releases of wasm-tools. DecodeJSON accepts
+// the shapes produced by the versions of wasm-tools between [version.WasmTools] and
+// [version.WasmToolsLatest], inclusive. It returns an error, rather than silently
+// dropping data, if r contains a function kind, type kind, or type it does not recognize.
 func DecodeJSON(r io.Reader) (*Resolve, error) {
 	res := &Resolve{}
 	dec := json.NewDecoder(r, res)
 	err := dec.Decode(res)
+	if err == nil {
+		err = res.checkDecoded()
+	}
+	if err == nil {
+		err = res.checkHandleTargets()
+	}
+	if err == nil {
+		err = res.checkCycles()
+	}
+	if err == nil {
+		err = res.checkHandles()
+	}
 	return res, err
 }
 
@@ -42,29 +68,29 @@ func (res *Resolve) ResolveCodec(v any) codec.Codec {
 	case *Type:
 		return &typeCodec{v, res}
 	case *TypeDefKind:
-		return &typeDefKindCodec{v}
+		return &typeDefKindCodec{v, res}
 	case *TypeOwner:
 		return &typeOwnerCodec{v}
-	case *WorldItem:
-		return &worldItemCodec{v}
+	case **Stability:
+		return &stabilityCodec{v}
 	}
 
 	return nil
 }
 
-func (c *Resolve) getWorld(i int) *World {
+func (c *Resolve) getWorld(i int) (*World, error) {
 	return mustElement(&c.Worlds, i)
 }
 
-func (c *Resolve) getInterface(i int) *Interface {
+func (c *Resolve) getInterface(i int) (*Interface, error) {
 	return mustElement(&c.Interfaces, i)
 }
 
-func (c *Resolve) getTypeDef(i int) *TypeDef {
+func (c *Resolve) getTypeDef(i int) (*TypeDef, error) {
 	return mustElement(&c.TypeDefs, i)
 }
 
-func (c *Resolve) getPackage(i int) *Package {
+func (c *Resolve) getPackage(i int) (*Package, error) {
 	return mustElement(&c.Packages, i)
 }
 
@@ -91,8 +117,9 @@ type worldCodec struct {
 }
 
 func (c *worldCodec) DecodeInt(i int) error {
-	*c.w = c.getWorld(i)
-	return nil
+	var err error
+	*c.w, err = c.getWorld(i)
+	return err
 }
 
 func (c *worldCodec) DecodeField(dec codec.Decoder, name string) error {
@@ -101,13 +128,15 @@ func (c *worldCodec) DecodeField(dec codec.Decoder, name string) error {
 	case "name":
 		return dec.Decode(&w.Name)
 	case "imports":
-		return dec.Decode(&w.Imports)
+		return dec.Decode(&worldItemsCodec{&w.Imports, &w.ImportStability, c.Resolve})
 	case "exports":
-		return dec.Decode(&w.Exports)
+		return dec.Decode(&worldItemsCodec{&w.Exports, &w.ExportStability, c.Resolve})
 	case "package":
 		return dec.Decode(&w.Package)
 	case "docs":
 		return dec.Decode(&w.Docs)
+	case "stability":
+		return dec.Decode(&w.Stability)
 	}
 	return nil
 }
@@ -119,8 +148,9 @@ type interfaceCodec struct {
 }
 
 func (c *interfaceCodec) DecodeInt(i int) error {
-	*c.i = c.getInterface(i)
-	return nil
+	var err error
+	*c.i, err = c.getInterface(i)
+	return err
 }
 
 func (c *interfaceCodec) DecodeField(dec codec.Decoder, name string) error {
@@ -136,6 +166,8 @@ func (c *interfaceCodec) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&i.Package)
 	case "docs":
 		return dec.Decode(&i.Docs)
+	case "stability":
+		return dec.Decode(&i.Stability)
 	}
 	return nil
 }
@@ -147,8 +179,9 @@ type typeDefCodec struct {
 }
 
 func (c *typeDefCodec) DecodeInt(i int) error {
-	*c.t = c.getTypeDef(i)
-	return nil
+	var err error
+	*c.t, err = c.getTypeDef(i)
+	return err
 }
 
 func (c *typeDefCodec) DecodeField(dec codec.Decoder, name string) error {
@@ -162,6 +195,8 @@ func (c *typeDefCodec) DecodeField(dec codec.Decoder, name string) error {
 		return dec.Decode(&t.Owner)
 	case "docs":
 		return dec.Decode(&t.Docs)
+	case "stability":
+		return dec.Decode(&t.Stability)
 	}
 	return nil
 }
@@ -173,8 +208,9 @@ type packageCodec struct {
 }
 
 func (c *packageCodec) DecodeInt(i int) error {
-	*c.p = c.getPackage(i)
-	return nil
+	var err error
+	*c.p, err = c.getPackage(i)
+	return err
 }
 
 func (c *packageCodec) DecodeField(dec codec.Decoder, name string) error {
@@ -210,10 +246,81 @@ func (d *Docs) DecodeField(dec codec.Decoder, name string) error {
 	return nil
 }
 
+// stabilityCodec translates a WIT stability enum into a *Stability.
+// The value "unknown" is decoded as nil.
+type stabilityCodec struct {
+	s **Stability
+}
+
+func (c *stabilityCodec) DecodeString(s string) error {
+	switch s {
+	case "unknown":
+		*c.s = nil
+	default:
+		return errUnsupportedJSON("stability %q", s)
+	}
+	return nil
+}
+
+func (c *stabilityCodec) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "stable", "unstable":
+		return dec.Decode(codec.Must(c.s))
+	}
+	return errUnsupportedJSON("stability %q", name)
+}
+
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object.
+func (s *Stability) DecodeField(dec codec.Decoder, name string) error {
+	var err error
+	switch name {
+	case "since":
+		s.Since, err = decodeVersion(dec)
+	case "feature":
+		err = dec.Decode(&s.Feature)
+	case "deprecated":
+		s.Deprecated, err = decodeVersion(dec)
+	}
+	return err
+}
+
+func decodeVersion(dec codec.Decoder) (*semver.Version, error) {
+	var s string
+	err := dec.Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	return semver.NewVersion(s)
+}
+
+// worldItemsCodec translates the imports or exports of a world into an ordered map
+// of WorldItem, and the gates of imported or exported interfaces into a map of *Stability.
+type worldItemsCodec struct {
+	items     *ordered.Map[string, WorldItem]
+	stability *map[string]*Stability
+	*Resolve
+}
+
+func (c *worldItemsCodec) DecodeField(dec codec.Decoder, name string) error {
+	var item WorldItem
+	var s *Stability
+	err := dec.Decode(&worldItemCodec{&item, &s, c.Resolve})
+	if err != nil {
+		return err
+	}
+	c.items.Set(name, item)
+	setStability(c.stability, name, s)
+	return nil
+}
+
 // worldItemCodec translates typed WorldItem references into a WorldItem,
-// currently either an Interface or a TypeDef.
+// currently either an Interface or a TypeDef. The gates of an interface are
+// decoded into s.
 type worldItemCodec struct {
 	v *WorldItem
+	s **Stability
+	*Resolve
 }
 
 func (c *worldItemCodec) DecodeField(dec codec.Decoder, name string) error {
@@ -221,7 +328,7 @@ func (c *worldItemCodec) DecodeField(dec codec.Decoder, name string) error {
 	switch name {
 	case "interface":
 		var v *Interface
-		err = dec.Decode(&v)
+		err = dec.Decode(&worldInterfaceCodec{&v, c.s, c.Resolve})
 		*c.v = v
 	case "function":
 		var v *Function
@@ -235,6 +342,31 @@ func (c *worldItemCodec) DecodeField(dec codec.Decoder, name string) error {
 	return err
 }
 
+// worldInterfaceCodec translates an interface imported or exported by a world into an *Interface.
+// Older versions of wasm-tools encode the interface as an index; newer versions encode
+// an object with the index in field "id", and the gates of the import or export in field "stability".
+type worldInterfaceCodec struct {
+	i **Interface
+	s **Stability
+	*Resolve
+}
+
+func (c *worldInterfaceCodec) DecodeInt(i int) error {
+	var err error
+	*c.i, err = c.getInterface(i)
+	return err
+}
+
+func (c *worldInterfaceCodec) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "id":
+		return dec.Decode(c.i)
+	case "stability":
+		return dec.Decode(c.s)
+	}
+	return nil
+}
+
 // typeCodec translates WIT type strings or reference Idents into a Type.
 type typeCodec struct {
 	t *Type
@@ -245,12 +377,16 @@ type typeCodec struct {
 func (c *typeCodec) DecodeString(s string) error {
 	var err error
 	*c.t, err = ParseType(s)
-	return err
+	if err != nil {
+		return featureError(s, errUnsupportedJSON("type %q", s))
+	}
+	return nil
 }
 
 func (c *typeCodec) DecodeInt(i int) error {
-	*c.t = c.getTypeDef(i)
-	return nil
+	var err error
+	*c.t, err = c.getTypeDef(i)
+	return err
 }
 
 // typeOwnerCodec translates WIT type owner enums into a [TypeOwner].
@@ -276,12 +412,15 @@ func (c *typeOwnerCodec) DecodeField(dec codec.Decoder, name string) error {
 // typeDefKindCodec translates WIT type owner enums into a [TypeDefKind].
 type typeDefKindCodec struct {
 	v *TypeDefKind
+	*Resolve
 }
 
 func (c *typeDefKindCodec) DecodeString(s string) error {
 	switch s {
 	case "resource":
 		*c.v = &Resource{}
+	default:
+		return errUnsupportedJSON("type kind %q", s)
 	}
 	return nil
 }
@@ -335,16 +474,38 @@ func (c *typeDefKindCodec) DecodeField(dec codec.Decoder, name string) error {
 		*c.v = v
 	case "stream":
 		v := &Stream{}
-		err = dec.Decode(v)
+		err = dec.Decode(&streamCodec{v, c.Resolve})
 		*c.v = v
 	case "type":
 		var v Type
 		err = dec.Decode(&v)
 		*c.v = v
+	default:
+		return featureError(name, errUnsupportedJSON("type kind %q", name))
 	}
 	return err
 }
 
+// streamCodec translates a WIT stream type into a [Stream].
+// Older versions of wasm-tools encode a stream as an object with fields "element" and "end";
+// newer versions encode only the element type, or null.
+type streamCodec struct {
+	s *Stream
+	*Resolve
+}
+
+func (c *streamCodec) DecodeString(s string) error {
+	return (&typeCodec{&c.s.Element, c.Resolve}).DecodeString(s)
+}
+
+func (c *streamCodec) DecodeInt(i int) error {
+	return (&typeCodec{&c.s.Element, c.Resolve}).DecodeInt(i)
+}
+
+func (c *streamCodec) DecodeField(dec codec.Decoder, name string) error {
+	return c.s.DecodeField(dec, name)
+}
+
 // DecodeField implements the [codec.FieldDecoder] interface
 // to decode a struct or JSON object.
 func (r *Record) DecodeField(dec codec.Decoder, name string) error {
@@ -502,8 +663,18 @@ func (f *Function) DecodeField(dec codec.Decoder, name string) error {
 		return codec.DecodeSlice(dec, &f.Params)
 	case "results":
 		return codec.DecodeSlice(dec, &f.Results)
+	case "result":
+		// Newer versions of wasm-tools encode at most one unnamed result.
+		var t Type
+		err := dec.Decode(&t)
+		if err == nil && t != nil {
+			f.Results = []Param{{Type: t}}
+		}
+		return err
 	case "docs":
 		return dec.Decode(&f.Docs)
+	case "stability":
+		return dec.Decode(&f.Stability)
 	}
 	return nil
 }
@@ -516,6 +687,8 @@ func (c *functionKindCodec) DecodeString(s string) error {
 	switch s {
 	case "freestanding":
 		*c.v = &Freestanding{}
+	default:
+		return featureError(s, errUnsupportedJSON("function kind %q", s))
 	}
 	return nil
 }
@@ -535,6 +708,8 @@ func (c *functionKindCodec) DecodeField(dec codec.Decoder, name string) error {
 		v := &Constructor{}
 		err = dec.Decode(&v.Type)
 		*c.v = v
+	default:
+		return featureError(name, errUnsupportedJSON("function kind %q", name))
 	}
 	return err
 }
@@ -551,13 +726,170 @@ func (p *Param) DecodeField(dec codec.Decoder, name string) error {
 	return nil
 }
 
+// errUnsupportedJSON returns an error for WIT JSON that cannot be decoded,
+// naming the range of wasm-tools versions that produce WIT JSON this package supports.
+// Unrecognized values are typically produced by a newer version of wasm-tools.
+func errUnsupportedJSON(format string, args ...any) error {
+	return fmt.Errorf("unsupported WIT JSON: %s: supported WIT JSON is produced by wasm-tools %s through %s",
+		fmt.Sprintf(format, args...), version.WasmTools, version.WasmToolsLatest)
+}
+
+// maxIndex is the maximum index of a world, interface, type, or package
+// in WIT JSON, which guards against excessive allocation from malformed input.
+const maxIndex = 1 << 20
+
 // mustElement resizes s and allocates a new instance of T if necessary.
-func mustElement[S ~[]*E, E any](s *S, i int) *E {
-	if i < 0 {
-		return nil
+func mustElement[S ~[]*E, E any](s *S, i int) (*E, error) {
+	if i < 0 || i > maxIndex {
+		return nil, fmt.Errorf("index %d out of range", i)
 	}
 	if codec.Resize(s, i) == nil {
 		(*s)[i] = new(E)
 	}
-	return (*s)[i]
+	return (*s)[i], nil
+}
+
+// checkDecoded returns an error if res contains nil or incomplete definitions,
+// such as a [TypeDef] without a kind, or a reference to a definition that is
+// not in res. Well-formed JSON from wasm-tools never produces these, but malformed input can.
+func (res *Resolve) checkDecoded() error {
+	c := &decodeChecker{
+		worlds:     set(res.Worlds),
+		interfaces: set(res.Interfaces),
+		typeDefs:   set(res.TypeDefs),
+		packages:   set(res.Packages),
+		ok:         true,
+	}
+	for i, w := range res.Worlds {
+		if w == nil {
+			return fmt.Errorf("world %d: missing definition", i)
+		}
+		c.pkg(w.Package)
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			items.All()(func(_ string, item WorldItem) bool {
+				switch item := item.(type) {
+				case *Interface:
+					c.iface(item)
+				case *TypeDef:
+					c.typeDef(item)
+					c.ok = c.ok && item.Owner == TypeOwner(w)
+				case *Function:
+					c.function(item)
+				default:
+					c.fail()
+				}
+				return c.ok
+			})
+		}
+		if !c.ok {
+			return fmt.Errorf("world %s: invalid reference", w.Name)
+		}
+	}
+	for i, face := range res.Interfaces {
+		if face == nil {
+			return fmt.Errorf("interface %d: missing definition", i)
+		}
+		c.pkg(face.Package)
+		face.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			c.typeDef(t)
+			c.ok = c.ok && t.Owner == TypeOwner(face)
+			return c.ok
+		})
+		face.Functions.All()(func(_ string, f *Function) bool {
+			c.function(f)
+			return c.ok
+		})
+		if !c.ok {
+			return fmt.Errorf("interface %d: invalid reference", i)
+		}
+	}
+	for i, t := range res.TypeDefs {
+		if t == nil || t.Kind == nil {
+			return fmt.Errorf("type %d: missing definition", i)
+		}
+		switch owner := t.Owner.(type) {
+		case *Interface:
+			c.iface(owner)
+		case *World:
+			c.world(owner)
+		}
+		for _, t := range typeDefChildren(t) {
+			c.typ(t)
+		}
+		if !c.ok {
+			return fmt.Errorf("type %d: invalid reference", i)
+		}
+	}
+	for i, p := range res.Packages {
+		if p == nil {
+			return fmt.Errorf("package %d: missing definition", i)
+		}
+		p.Interfaces.All()(func(_ string, face *Interface) bool {
+			c.iface(face)
+			return c.ok
+		})
+		p.Worlds.All()(func(_ string, w *World) bool {
+			c.world(w)
+			return c.ok
+		})
+		if !c.ok {
+			return fmt.Errorf("package %s: invalid reference", p.Name.String())
+		}
+	}
+	return nil
+}
+
+// decodeChecker verifies that references in a decoded [Resolve] are non-nil
+// and refer to definitions contained in the Resolve.
+type decodeChecker struct {
+	worlds     map[*World]bool
+	interfaces map[*Interface]bool
+	typeDefs   map[*TypeDef]bool
+	packages   map[*Package]bool
+	ok         bool
+}
+
+func (c *decodeChecker) fail()              { c.ok = false }
+func (c *decodeChecker) world(w *World)     { c.ok = c.ok && c.worlds[w] }
+func (c *decodeChecker) iface(i *Interface) { c.ok = c.ok && c.interfaces[i] }
+func (c *decodeChecker) typeDef(t *TypeDef) { c.ok = c.ok && c.typeDefs[t] }
+func (c *decodeChecker) pkg(p *Package)     { c.ok = c.ok && (p == nil || c.packages[p]) }
+
+// typ checks t, which must be a primitive type or a [TypeDef] in the Resolve.
+func (c *decodeChecker) typ(t Type) {
+	switch t := t.(type) {
+	case nil:
+		c.fail()
+	case *TypeDef:
+		c.typeDef(t)
+	}
+}
+
+func (c *decodeChecker) function(f *Function) {
+	if f == nil || f.Kind == nil {
+		c.fail()
+		return
+	}
+	switch kind := f.Kind.(type) {
+	case *Method:
+		c.typ(kind.Type)
+	case *Static:
+		c.typ(kind.Type)
+	case *Constructor:
+		c.typ(kind.Type)
+	}
+	for _, p := range f.Params {
+		c.typ(p.Type)
+	}
+	for _, p := range f.Results {
+		c.typ(p.Type)
+	}
+}
+
+func set[T comparable](s []T) map[T]bool {
+	m := make(map[T]bool, len(s))
+	for _, v := range s {
+		m[v] = true
+	}
+	return m
 }
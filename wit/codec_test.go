@@ -0,0 +1,341 @@
+package wit
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/ydnar/wasm-tools-go/internal/version"
+)
+
+// TestDecodeJSONShapes verifies that the WIT JSON shapes produced by
+// older and newer versions of wasm-tools decode into the same [Resolve].
+func TestDecodeJSONShapes(t *testing.T) {
+	const format = `{
+		"worlds": [{"name": "w", "imports": {"interface-0": {"interface": %s}}, "exports": {"h": {"function": %s}}, "package": 0}],
+		"interfaces": [{"name": "i", "types": {"s": 0}, "functions": {"f": %s, "g": %s}, "package": 0}],
+		"types": [{"name": "s", "kind": {"stream": %s}, "owner": {"interface": 0}}],
+		"packages": [{"name": "a:b@0.1.0", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+	}`
+	older := fmt.Sprintf(format,
+		`0`,
+		`{"name": "h", "kind": "freestanding", "params": [], "results": [{"type": "u8"}]}`,
+		`{"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": "u32"}], "results": [{"type": 0}]}`,
+		`{"name": "g", "kind": "freestanding", "params": [], "results": []}`,
+		`{"element": "u8", "end": null}`)
+	newer := fmt.Sprintf(format,
+		`{"id": 0, "stability": "unknown"}`,
+		`{"name": "h", "kind": "freestanding", "params": [], "result": "u8"}`,
+		`{"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": "u32"}], "result": 0}`,
+		`{"name": "g", "kind": "freestanding", "params": []}`,
+		`"u8"`)
+
+	want := decodeWIT(t, older)
+	got := decodeWIT(t, newer)
+	if got != want {
+		t.Errorf("newer WIT JSON:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestDecodeJSONUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			"async function",
+			`{"interfaces": [{"name": "i", "functions": {"f": {"name": "f", "kind": "async-freestanding", "params": []}}}]}`,
+			`function kind "async-freestanding"`,
+		},
+		{
+			"async method",
+			`{"interfaces": [{"name": "i", "functions": {"f": {"name": "f", "kind": {"async-method": 0}, "params": []}}}]}`,
+			`function kind "async-method"`,
+		},
+		{
+			"type kind",
+			`{"types": [{"name": "l", "kind": {"fixed-size-list": ["u8", 4]}, "owner": null}]}`,
+			`type kind "fixed-size-list"`,
+		},
+		{
+			"primitive type",
+			`{"types": [{"name": "e", "kind": {"type": "error-context"}, "owner": null}]}`,
+			`type "error-context"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeJSON(strings.NewReader(tt.json))
+			if err == nil {
+				t.Fatal("DecodeJSON: expected error")
+			}
+			got := err.Error()
+			if !strings.Contains(got, tt.want) || !strings.Contains(got, version.WasmToolsLatest) {
+				t.Errorf("DecodeJSON: %q, expected an error containing %q and %q", got, tt.want, version.WasmToolsLatest)
+			}
+		})
+	}
+}
+
+// TestDecodeJSONStream verifies that [DecodeJSON] decodes input delivered in small
+// pieces, as from a pipe, and reports an error if the input is truncated.
+func TestDecodeJSONStream(t *testing.T) {
+	err := loadTestdata(func(path string, want *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := DecodeJSON(iotest.OneByteReader(bytes.NewReader(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res.WIT(nil, ""), want.WIT(nil, ""); got != want {
+				t.Errorf("DecodeJSON(OneByteReader):\n%s\nexpected:\n%s", got, want)
+			}
+			_, err = DecodeJSON(bytes.NewReader(data[:len(data)/2]))
+			if err == nil {
+				t.Errorf("DecodeJSON(truncated): nil error, expected an error")
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// benchmarkResolveSize is the approximate size of the synthetic WIT JSON decoded by
+// [BenchmarkDecodeJSON], large enough that buffering the document would dominate memory.
+const benchmarkResolveSize = 100 << 20
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	var n countWriter
+	interfaces := 1
+	writeSyntheticResolve(&n, interfaces)
+	interfaces = benchmarkResolveSize / int(n)
+	n = 0
+	writeSyntheticResolve(&n, interfaces)
+	b.SetBytes(int64(n))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(writeSyntheticResolve(pw, interfaces))
+		}()
+		res, err := DecodeJSON(pr)
+		pr.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(res.Interfaces) != interfaces {
+			b.Fatalf("decoded %d interfaces, expected %d", len(res.Interfaces), interfaces)
+		}
+	}
+}
+
+// writeSyntheticResolve writes WIT JSON for a package with n interfaces to w,
+// without holding the document in memory. Each interface has a documented record,
+// a list type, and functions that refer to them.
+func writeSyntheticResolve(w io.Writer, n int) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"worlds": [], "interfaces": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			bw.WriteString(", ")
+		}
+		fmt.Fprintf(bw, `{"name": "i%d", "docs": {"contents": "Interface i%d is synthetic."}, "types": {"r": %d, "l": %d}, "functions": {`, i, i, 2*i, 2*i+1)
+		for j := 0; j < 4; j++ {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			fmt.Fprintf(bw, `"f%d": {"name": "f%d", "kind": "freestanding", "params": [{"name": "a", "type": %d}, {"name": "b", "type": "string"}], "result": %d, "docs": {"contents": "Function f%d."}}`, j, j, 2*i, 2*i+1, j)
+		}
+		bw.WriteString(`}, "package": 0}`)
+	}
+	bw.WriteString(`], "types": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			bw.WriteString(", ")
+		}
+		fmt.Fprintf(bw, `{"name": "r", "kind": {"record": {"fields": [{"name": "x", "type": "u32", "docs": {"contents": "Field x."}}, {"name": "y", "type": "u64"}, {"name": "z", "type": "string"}]}}, "owner": {"interface": %d}, "docs": {"contents": "Record r."}}, `, i)
+		fmt.Fprintf(bw, `{"name": "l", "kind": {"list": %d}, "owner": {"interface": %d}}`, 2*i, i)
+	}
+	bw.WriteString(`], "packages": [{"name": "bench:synthetic@0.1.0", "interfaces": {`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			bw.WriteString(", ")
+		}
+		fmt.Fprintf(bw, `"i%d": %d`, i, i)
+	}
+	bw.WriteString(`}, "worlds": {}}]}`)
+	return bw.Flush()
+}
+
+type countWriter int
+
+func (n *countWriter) Write(p []byte) (int, error) {
+	*n += countWriter(len(p))
+	return len(p), nil
+}
+
+func decodeWIT(t *testing.T, s string) string {
+	res, err := DecodeJSON(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.WIT(nil, "")
+}
+
+func TestStability(t *testing.T) {
+	const data = `{
+		"worlds": [{"name": "w", "imports": {}, "exports": {}, "package": 0, "stability": {"stable": {"since": "0.2.0"}}}],
+		"interfaces": [{
+			"name": "i",
+			"types": {"t": 0},
+			"functions": {
+				"f": {"name": "f", "kind": "freestanding", "params": [], "results": [], "stability": {"unstable": {"feature": "foo", "deprecated": "0.2.1"}}},
+				"g": {"name": "g", "kind": "freestanding", "params": [], "results": [], "stability": "unknown"}
+			},
+			"stability": {"stable": {"since": "0.2.0"}},
+			"package": 0
+		}],
+		"types": [{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}, "stability": {"stable": {"since": "0.2.0", "deprecated": "0.2.2"}}}],
+		"packages": [{"name": "a:b@0.2.2", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+	}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	if s := i.TypeDefs.Get("t").Stability; !s.IsDeprecated() || s.Deprecated.String() != "0.2.2" || s.Since.String() != "0.2.0" {
+		t.Errorf("type t: stability %+v", s)
+	}
+	if s := i.Functions.Get("f").Stability; !s.IsDeprecated() || s.Feature != "foo" {
+		t.Errorf("function f: stability %+v", s)
+	}
+	if s := i.Functions.Get("g").Stability; s != nil {
+		t.Errorf("function g: stability %+v, expected nil", s)
+	}
+
+	want := `package a:b@0.2.2;
+
+@since(version = 0.2.0)
+interface i {
+	@since(version = 0.2.0)
+	@deprecated(version = 0.2.2)
+	type t = u32;
+	@unstable(feature = foo)
+	@deprecated(version = 0.2.1)
+	f: func();
+	g: func();
+}
+
+@since(version = 0.2.0)
+world w {}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+
+	// Stability survives a round trip through JSON.
+	b, err := res.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeWIT(t, string(b)); got != want {
+		t.Errorf("WIT after round trip:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestWorldItemStability(t *testing.T) {
+	// Output of wasm-tools component wit -j for the WIT in want.
+	// Interfaces imported or exported by reference are keyed by ID.
+	const data = `{
+		"worlds": [{
+			"name": "w",
+			"imports": {
+				"interface-0": {"interface": {"id": 0, "stability": {"stable": {"since": "0.2.0"}}}},
+				"x": {"interface": {"id": 1, "stability": {"stable": {"since": "0.2.0"}}}}
+			},
+			"exports": {
+				"interface-0": {"interface": {"id": 0, "stability": {"unstable": {"feature": "x"}}}}
+			},
+			"package": 0
+		}],
+		"interfaces": [
+			{"name": "i", "types": {}, "functions": {"f": {"name": "f", "kind": "freestanding", "params": []}}, "package": 0},
+			{"name": null, "types": {}, "functions": {"g": {"name": "g", "kind": "freestanding", "params": []}}, "stability": {"stable": {"since": "0.2.0"}}, "package": 0}
+		],
+		"types": [],
+		"packages": [{"name": "foo:bar@0.2.0", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+	}`
+	const want = `package foo:bar@0.2.0;
+
+interface i {
+	f: func();
+}
+
+world w {
+	@since(version = 0.2.0)
+	import i;
+	@since(version = 0.2.0)
+	import x: interface {
+		g: func();
+	}
+	@unstable(feature = x)
+	export i;
+}
+`
+	check := func(t *testing.T, res *Resolve) {
+		w := res.Worlds[0]
+		if s := w.ImportStability["interface-0"]; s == nil || s.Since.String() != "0.2.0" {
+			t.Errorf("import i: stability %+v", s)
+		}
+		if s := w.ImportStability["x"]; s == nil || s.Since.String() != "0.2.0" {
+			t.Errorf("import x: stability %+v", s)
+		}
+		if s := w.ExportStability["interface-0"]; s == nil || s.Feature != "x" {
+			t.Errorf("export i: stability %+v", s)
+		}
+		if got := res.WIT(nil, ""); got != want {
+			t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+		}
+	}
+
+	t.Run("DecodeJSON", func(t *testing.T) {
+		res, err := DecodeJSON(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, res)
+
+		// Stability survives a round trip through JSON.
+		b, err := res.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err = DecodeJSON(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, res)
+	})
+
+	t.Run("LoadFS", func(t *testing.T) {
+		res, err := LoadFS(fstest.MapFS{"w.wit": {Data: []byte(want)}}, "w.wit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, res)
+	})
+}
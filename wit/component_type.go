@@ -0,0 +1,559 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+)
+
+// ComponentTypeSection is the prefix of the name of the custom section that
+// embeds a WIT world in a Core WebAssembly module. The wasm-tools component new
+// command uses it to create a component from the module.
+const ComponentTypeSection = "component-type"
+
+// ComponentTypeSectionName returns the name of the custom section for world w,
+// e.g. component-type:wasi:cli/command@0.2.0.
+func (w *World) ComponentTypeSectionName() string {
+	return ComponentTypeSection + ":" + worldID(w)
+}
+
+// ComponentType returns the binary encoding of [World] w as the contents of a
+// component-type custom section: a WebAssembly component that exports a WIT package
+// containing w, the interfaces it imports and exports, and their types.
+// Strings are encoded as UTF-8.
+//
+// ComponentType returns an error if w, or an interface or type it uses, cannot be
+// represented in the Component Model binary format.
+func (w *World) ComponentType() ([]byte, error) {
+	return w.ComponentTypeWithEncoding(UTF8)
+}
+
+// ComponentTypeWithEncoding is like [World.ComponentType], but records enc
+// as the string encoding used by the module that embeds the section, so that
+// wasm-tools component new lifts and lowers strings with enc.
+func (w *World) ComponentTypeWithEncoding(enc StringEncoding) ([]byte, error) {
+	if enc > Latin1UTF16 {
+		return nil, fmt.Errorf("world %s: unknown string encoding %d", w.Name, enc)
+	}
+	if w.Package == nil {
+		return nil, fmt.Errorf("world %s: no package", w.Name)
+	}
+	e := &componentTypeEncoder{
+		outer:        newTypeScope(),
+		instances:    make(map[*Interface]uint32),
+		outerAliases: make(map[*TypeDef]uint32),
+	}
+	err := e.world(w)
+	if err != nil {
+		return nil, fmt.Errorf("world %s: %w", w.Name, err)
+	}
+
+	// The world is wrapped in an outer component type, exported as
+	// the world’s fully-qualified name, e.g. wasi:cli/command@0.2.0.
+	worldType := e.outer.componentType()
+	outer := newTypeScope()
+	outer.define(worldType)
+	outer.decl(0x04, worldID(w), 0x04, 0)
+
+	var types []byte
+	types = wasm.AppendU32(types, 1)
+	types = append(types, outer.componentType()...)
+
+	var exports []byte
+	exports = wasm.AppendU32(exports, 1)
+	exports = append(exports, 0x00) // plain name
+	exports = wasm.AppendName(exports, w.Name)
+	exports = append(exports, sortType, 0x00, 0x00) // type 0, no type ascription
+
+	b := append([]byte{}, wasm.ComponentHeader...)
+	// The values of StringEncoding match the encoding of string encodings in wit-component metadata.
+	b = wasm.AppendCustomSection(b, "wit-component-encoding", []byte{componentTypeVersion, byte(enc)})
+	b = wasm.AppendSection(b, componentTypeSectionID, types)
+	b = wasm.AppendSection(b, componentExportSectionID, exports)
+	return b, nil
+}
+
+const (
+	// componentTypeVersion is the version of the wit-component metadata encoding.
+	componentTypeVersion = 0x04
+
+	componentTypeSectionID   = 0x07
+	componentExportSectionID = 0x0b
+
+	sortType = 0x03
+)
+
+// componentTypeEncoder encodes a [World] as a Component Model component type.
+// It follows the conventions of the wit-component crate, which decodes it.
+type componentTypeEncoder struct {
+	// outer is the component type representing the world.
+	outer *typeScope
+
+	// instance is the instance type under construction for an interface, or nil.
+	instance *typeScope
+
+	// iface is the interface being encoded. Types owned by other interfaces
+	// are aliased from the instance that imports or exports them.
+	iface *Interface
+
+	// importTypes is true if world-level types are imported rather than exported.
+	importTypes bool
+
+	// instances maps interfaces to their instance index in the outer component type.
+	instances map[*Interface]uint32
+
+	// instanceCount is the number of instances in the outer component type.
+	instanceCount uint32
+
+	// outerAliases maps types aliased from an instance to their outer type index.
+	outerAliases map[*TypeDef]uint32
+}
+
+func (e *componentTypeEncoder) world(w *World) error {
+	var err error
+	w.Imports.All()(func(name string, item WorldItem) bool {
+		err = e.worldItem(0x03, worldItemName(name, item), item)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	w.Exports.All()(func(name string, item WorldItem) bool {
+		err = e.worldItem(0x04, worldItemName(name, item), item)
+		return err == nil
+	})
+	return err
+}
+
+// worldItem emits an import (0x03) or export (0x04) of item.
+func (e *componentTypeEncoder) worldItem(decl byte, name string, item WorldItem) error {
+	switch item := item.(type) {
+	case *Interface:
+		e.iface = item
+		idx, err := e.instanceType(item)
+		if err != nil {
+			return fmt.Errorf("interface %s: %w", name, err)
+		}
+		e.outer.decl(decl, name, 0x05, idx)
+		e.instances[item] = e.instanceCount
+		e.instanceCount++
+	case *Function:
+		e.iface = nil
+		idx, err := e.funcType(item)
+		if err != nil {
+			return fmt.Errorf("function %s: %w", name, err)
+		}
+		e.outer.decl(decl, name, 0x01, idx)
+	case *TypeDef:
+		if decl != 0x03 {
+			return fmt.Errorf("cannot export type %s", name)
+		}
+		e.iface = nil
+		e.importTypes = true
+		_, err := e.valType(item)
+		e.importTypes = false
+		if err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// instanceType defines an instance type for interface i in the outer component type,
+// returning its type index.
+func (e *componentTypeEncoder) instanceType(i *Interface) (uint32, error) {
+	e.instance = newTypeScope()
+	defer func() { e.instance = nil }()
+
+	var order []*TypeDef
+	var err error
+	i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+		_, err = e.valType(t)
+		order = append(order, t)
+		return err == nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Freestanding functions first, then resource functions in type order.
+	var funcs []*Function
+	i.Functions.All()(func(_ string, f *Function) bool {
+		funcs = append(funcs, f)
+		return true
+	})
+	typeOrder := func(f *Function) int {
+		if t, ok := f.Type().(*TypeDef); ok && !f.IsFreestanding() {
+			return slices.Index(order, t)
+		}
+		return -1
+	}
+	slices.SortStableFunc(funcs, func(a, b *Function) int {
+		return typeOrder(a) - typeOrder(b)
+	})
+	for _, f := range funcs {
+		idx, err := e.funcType(f)
+		if err != nil {
+			return 0, fmt.Errorf("function %s: %w", f.Name, err)
+		}
+		e.instance.decl(0x04, f.Name, 0x01, idx)
+	}
+
+	return e.outer.define(e.instance.instanceType()), nil
+}
+
+// scope returns the type scope under construction.
+func (e *componentTypeEncoder) scope() *typeScope {
+	if e.instance != nil {
+		return e.instance
+	}
+	return e.outer
+}
+
+// funcType defines a function type for f, returning its type index.
+func (e *componentTypeEncoder) funcType(f *Function) (uint32, error) {
+	s := e.scope()
+	var key strings.Builder
+	for _, p := range f.Params {
+		fmt.Fprintf(&key, "%s:%p,", p.Name, p.Type)
+	}
+	key.WriteString("->")
+	for _, p := range f.Results {
+		fmt.Fprintf(&key, "%s:%p,", p.Name, p.Type)
+	}
+	if idx, ok := s.funcs[key.String()]; ok {
+		return idx, nil
+	}
+
+	params, err := e.namedValTypes(f.Params)
+	if err != nil {
+		return 0, err
+	}
+	var results []byte
+	if len(f.Results) == 1 && f.Results[0].Name == "" {
+		v, err := e.valType(f.Results[0].Type)
+		if err != nil {
+			return 0, err
+		}
+		results = v.append([]byte{0x00})
+	} else {
+		named, err := e.namedValTypes(f.Results)
+		if err != nil {
+			return 0, err
+		}
+		results = append([]byte{0x01}, named...)
+	}
+
+	def := append([]byte{0x40}, params...)
+	def = append(def, results...)
+	idx := s.define(def)
+	s.funcs[key.String()] = idx
+	return idx, nil
+}
+
+func (e *componentTypeEncoder) namedValTypes(params []Param) ([]byte, error) {
+	b := wasm.AppendU32(nil, uint32(len(params)))
+	for _, p := range params {
+		v, err := e.valType(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		b = wasm.AppendName(b, p.Name)
+		b = v.append(b)
+	}
+	return b, nil
+}
+
+// valType returns the value type for t, defining any types necessary to represent it.
+func (e *componentTypeEncoder) valType(t Type) (valType, error) {
+	switch t := t.(type) {
+	case nil:
+		return valType{}, errors.New("missing type")
+	case *TypeDef:
+		return e.typeDef(t)
+	}
+	code, ok := primitiveCodes[t.TypeName()]
+	if !ok {
+		return valType{}, fmt.Errorf("unsupported type %s", t.TypeName())
+	}
+	return valType{prim: code}, nil
+}
+
+func (e *componentTypeEncoder) typeDef(t *TypeDef) (valType, error) {
+	s := e.scope()
+	if idx, ok := s.types[t]; ok {
+		return valType{index: idx}, nil
+	}
+
+	// Types owned by another interface are aliased from its instance.
+	if owner, ok := t.Owner.(*Interface); ok && owner != e.iface {
+		idx, err := e.aliasType(owner, t)
+		if err != nil {
+			return valType{}, err
+		}
+		s.types[t] = idx
+		return valType{index: idx}, nil
+	}
+
+	var v valType
+	var err error
+	switch kind := t.Kind.(type) {
+	case *Resource:
+		if t.Name == nil {
+			return valType{}, errors.New("anonymous resource")
+		}
+		idx := e.exportOrImport(*t.Name, 0x01, 0) // sub resource
+		s.types[t] = idx
+		return valType{index: idx}, nil
+	case Type:
+		v, err = e.valType(kind)
+	case *Own:
+		v, err = e.handle(0x69, kind.Type)
+	case *Borrow:
+		v, err = e.handle(0x68, kind.Type)
+	case *Record:
+		b := wasm.AppendU32([]byte{0x72}, uint32(len(kind.Fields)))
+		for _, f := range kind.Fields {
+			fv, err := e.valType(f.Type)
+			if err != nil {
+				return valType{}, err
+			}
+			b = wasm.AppendName(b, f.Name)
+			b = fv.append(b)
+		}
+		v = valType{index: s.define(b)}
+	case *Tuple:
+		b := wasm.AppendU32([]byte{0x6f}, uint32(len(kind.Types)))
+		for _, t := range kind.Types {
+			tv, err := e.valType(t)
+			if err != nil {
+				return valType{}, err
+			}
+			b = tv.append(b)
+		}
+		v = valType{index: s.define(b)}
+	case *Flags:
+		b := wasm.AppendU32([]byte{0x6e}, uint32(len(kind.Flags)))
+		for _, f := range kind.Flags {
+			b = wasm.AppendName(b, f.Name)
+		}
+		v = valType{index: s.define(b)}
+	case *Variant:
+		b := wasm.AppendU32([]byte{0x71}, uint32(len(kind.Cases)))
+		for _, c := range kind.Cases {
+			b = wasm.AppendName(b, c.Name)
+			b, err = e.appendOptionalValType(b, c.Type)
+			if err != nil {
+				return valType{}, err
+			}
+			b = append(b, 0x00) // no refinement
+		}
+		v = valType{index: s.define(b)}
+	case *Enum:
+		b := wasm.AppendU32([]byte{0x6d}, uint32(len(kind.Cases)))
+		for _, c := range kind.Cases {
+			b = wasm.AppendName(b, c.Name)
+		}
+		v = valType{index: s.define(b)}
+	case *Option:
+		var ov valType
+		ov, err = e.valType(kind.Type)
+		if err == nil {
+			v = valType{index: s.define(ov.append([]byte{0x6b}))}
+		}
+	case *Result:
+		b := []byte{0x6a}
+		b, err = e.appendOptionalValType(b, kind.OK)
+		if err == nil {
+			b, err = e.appendOptionalValType(b, kind.Err)
+		}
+		if err == nil {
+			v = valType{index: s.define(b)}
+		}
+	case *List:
+		var lv valType
+		lv, err = e.valType(kind.Type)
+		if err == nil {
+			v = valType{index: s.define(lv.append([]byte{0x70}))}
+		}
+	default:
+		return valType{}, fmt.Errorf("unsupported %s type", t.Kind.WITKind())
+	}
+	if err != nil {
+		return valType{}, err
+	}
+
+	// Named types are exported, or imported by worlds.
+	if t.Name != nil {
+		if v.prim != 0 {
+			v = valType{index: s.define([]byte{v.prim})}
+		}
+		v = valType{index: e.exportOrImport(*t.Name, 0x00, v.index)} // eq
+	}
+	if v.prim == 0 {
+		s.types[t] = v.index
+	}
+	return v, nil
+}
+
+func (e *componentTypeEncoder) handle(code byte, t *TypeDef) (valType, error) {
+	v, err := e.typeDef(t)
+	if err != nil {
+		return valType{}, err
+	}
+	return valType{index: e.scope().define(wasm.AppendU32([]byte{code}, v.index))}, nil
+}
+
+func (e *componentTypeEncoder) appendOptionalValType(b []byte, t Type) ([]byte, error) {
+	if t == nil {
+		return append(b, 0x00), nil
+	}
+	v, err := e.valType(t)
+	if err != nil {
+		return nil, err
+	}
+	return v.append(append(b, 0x01)), nil
+}
+
+// exportOrImport declares a named type with the type bound (eq index, or sub resource),
+// returning its new type index. Types in an instance are exported. Types in a world
+// are imported when encoding world imports.
+func (e *componentTypeEncoder) exportOrImport(name string, bound byte, index uint32) uint32 {
+	var decl byte = 0x04
+	if e.instance == nil && e.importTypes {
+		decl = 0x03
+	}
+	s := e.scope()
+	if bound == 0x01 {
+		s.decl(decl, name, sortType, 0x01)
+	} else {
+		s.declBytes(decl, name, wasm.AppendU32([]byte{sortType, bound}, index))
+	}
+	idx := s.typeCount
+	s.typeCount++
+	return idx
+}
+
+// aliasType aliases type t exported from the instance of interface owner,
+// returning its index in the current scope.
+func (e *componentTypeEncoder) aliasType(owner *Interface, t *TypeDef) (uint32, error) {
+	if t.Name == nil {
+		return 0, errors.New("cannot alias anonymous type")
+	}
+	instance, ok := e.instances[owner]
+	if !ok {
+		return 0, fmt.Errorf("type %s: interface %s not imported", *t.Name, interfacePathName(owner))
+	}
+	outerIdx, ok := e.outerAliases[t]
+	if !ok {
+		// alias export instance name (type)
+		b := wasm.AppendU32([]byte{0x02, sortType, 0x00}, instance)
+		b = wasm.AppendName(b, *t.Name)
+		e.outer.decls = append(e.outer.decls, b)
+		outerIdx = e.outer.typeCount
+		e.outer.typeCount++
+		e.outerAliases[t] = outerIdx
+	}
+	if e.instance == nil {
+		return outerIdx, nil
+	}
+	// alias outer 1 idx (type)
+	e.instance.decls = append(e.instance.decls, wasm.AppendU32([]byte{0x02, sortType, 0x02, 0x01}, outerIdx))
+	idx := e.instance.typeCount
+	e.instance.typeCount++
+	return idx, nil
+}
+
+// typeScope is a component or instance type under construction.
+type typeScope struct {
+	decls     [][]byte
+	typeCount uint32
+	types     map[*TypeDef]uint32
+	funcs     map[string]uint32
+}
+
+func newTypeScope() *typeScope {
+	return &typeScope{
+		types: make(map[*TypeDef]uint32),
+		funcs: make(map[string]uint32),
+	}
+}
+
+// define appends a type definition, returning its type index.
+func (s *typeScope) define(def []byte) uint32 {
+	s.decls = append(s.decls, append([]byte{0x01}, def...))
+	idx := s.typeCount
+	s.typeCount++
+	return idx
+}
+
+// decl appends an import (0x03) or export (0x04) declaration of name
+// with an extern descriptor of kind and index.
+func (s *typeScope) decl(decl byte, name string, kind byte, index uint32) {
+	s.declBytes(decl, name, wasm.AppendU32([]byte{kind}, index))
+}
+
+func (s *typeScope) declBytes(decl byte, name string, desc []byte) {
+	b := []byte{decl, 0x00} // plain name
+	b = wasm.AppendName(b, name)
+	s.decls = append(s.decls, append(b, desc...))
+}
+
+func (s *typeScope) componentType() []byte { return s.encode(0x41) }
+func (s *typeScope) instanceType() []byte  { return s.encode(0x42) }
+
+func (s *typeScope) encode(code byte) []byte {
+	b := wasm.AppendU32([]byte{code}, uint32(len(s.decls)))
+	for _, d := range s.decls {
+		b = append(b, d...)
+	}
+	return b
+}
+
+// valType is a Component Model value type: either a primitive type code, or a type index.
+type valType struct {
+	prim  byte
+	index uint32
+}
+
+func (v valType) append(b []byte) []byte {
+	if v.prim != 0 {
+		return append(b, v.prim)
+	}
+	return wasm.AppendS64(b, int64(v.index))
+}
+
+var primitiveCodes = map[string]byte{
+	"bool":   0x7f,
+	"s8":     0x7e,
+	"u8":     0x7d,
+	"s16":    0x7c,
+	"u16":    0x7b,
+	"s32":    0x7a,
+	"u32":    0x79,
+	"s64":    0x78,
+	"u64":    0x77,
+	"f32":    0x76,
+	"f64":    0x75,
+	"char":   0x74,
+	"string": 0x73,
+}
+
+// worldItemName returns the import or export name of a world item with key name.
+// Named interfaces are identified by their fully-qualified name, e.g. wasi:io/streams@0.2.0.
+func worldItemName(name string, item WorldItem) string {
+	if i, ok := item.(*Interface); ok {
+		if id, ok := i.ID(); ok {
+			return id.String()
+		}
+	}
+	return name
+}
+
+func worldID(w *World) string {
+	id := w.ID()
+	return id.String()
+}
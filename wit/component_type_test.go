@@ -0,0 +1,117 @@
+package wit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// componentTypeJSON is the JSON representation of:
+//
+//	package a:b;
+//	interface i {
+//		f: func(x: u32) -> string;
+//	}
+//	world w {
+//		import i;
+//		type t = u32;
+//		record r { a: t, b: list<t> }
+//		import g: func(x: r) -> option<string>;
+//		export h: func() -> result<_, string>;
+//	}
+const componentTypeJSON = `{
+	"worlds": [
+		{
+			"name": "w",
+			"imports": {
+				"interface-0": {"interface": 0},
+				"t": {"type": 0},
+				"r": {"type": 1},
+				"g": {"function": {"name": "g", "kind": "freestanding", "params": [{"name": "x", "type": 1}], "results": [{"type": 3}]}}
+			},
+			"exports": {
+				"h": {"function": {"name": "h", "kind": "freestanding", "params": [], "results": [{"type": 4}]}}
+			},
+			"package": 0
+		}
+	],
+	"interfaces": [
+		{
+			"name": "i",
+			"types": {},
+			"functions": {
+				"f": {"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": "u32"}], "results": [{"type": "string"}]}
+			},
+			"package": 0
+		}
+	],
+	"types": [
+		{"name": "t", "kind": {"type": "u32"}, "owner": {"world": 0}},
+		{"name": "r", "kind": {"record": {"fields": [{"name": "a", "type": 0}, {"name": "b", "type": 2}]}}, "owner": {"world": 0}},
+		{"name": null, "kind": {"list": 0}, "owner": null},
+		{"name": null, "kind": {"option": "string"}, "owner": null},
+		{"name": null, "kind": {"result": {"ok": null, "err": "string"}}, "owner": null}
+	],
+	"packages": [{"name": "a:b", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+}`
+
+func TestWorldComponentType(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(componentTypeJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+
+	if got, want := w.ComponentTypeSectionName(), "component-type:a:b/w"; got != want {
+		t.Errorf("ComponentTypeSectionName(): %q, expected %q", got, want)
+	}
+
+	got, err := w.ComponentType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Output of wasm-tools component embed --dummy, without the producers section.
+	want := []byte{
+		0x00, 0x61, 0x73, 0x6d, 0x0d, 0x00, 0x01, 0x00,
+		0x00, 0x19, 0x16, 'w', 'i', 't', '-', 'c', 'o', 'm', 'p', 'o', 'n', 'e', 'n', 't', '-', 'e', 'n', 'c', 'o', 'd', 'i', 'n', 'g', 0x04, 0x00,
+		0x07, 0x68, 0x01, 0x41, 0x02, 0x01, 0x41, 0x0d,
+		0x01, 0x42, 0x02, 0x01, 0x40, 0x01, 0x01, 'x', 0x79, 0x00, 0x73, 0x04, 0x00, 0x01, 'f', 0x01, 0x00,
+		0x03, 0x00, 0x05, 'a', ':', 'b', '/', 'i', 0x05, 0x00,
+		0x01, 0x79,
+		0x03, 0x00, 0x01, 't', 0x03, 0x00, 0x01,
+		0x01, 0x70, 0x02,
+		0x01, 0x72, 0x02, 0x01, 'a', 0x02, 0x01, 'b', 0x03,
+		0x03, 0x00, 0x01, 'r', 0x03, 0x00, 0x04,
+		0x01, 0x6b, 0x73,
+		0x01, 0x40, 0x01, 0x01, 'x', 0x05, 0x00, 0x06,
+		0x03, 0x00, 0x01, 'g', 0x01, 0x07,
+		0x01, 0x6a, 0x00, 0x01, 0x73,
+		0x01, 0x40, 0x00, 0x00, 0x08,
+		0x04, 0x00, 0x01, 'h', 0x01, 0x09,
+		0x04, 0x00, 0x05, 'a', ':', 'b', '/', 'w', 0x04, 0x00,
+		0x0b, 0x07, 0x01, 0x00, 0x01, 'w', 0x03, 0x00, 0x00,
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("ComponentType():\n% x\nexpected:\n% x", got, want)
+	}
+}
+
+func TestWorldComponentTypeTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		for _, w := range res.Worlds {
+			b, err := w.ComponentType()
+			if err != nil {
+				t.Errorf("%s: %v", path, err)
+				continue
+			}
+			if !bytes.HasPrefix(b, []byte{0x00, 'a', 's', 'm', 0x0d, 0x00, 0x01, 0x00}) {
+				t.Errorf("%s: world %s: invalid component header", path, w.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
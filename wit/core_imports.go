@@ -0,0 +1,90 @@
+package wit
+
+// RootModule is the Core WebAssembly import module name of functions and resource
+// intrinsics imported directly by a [World], rather than by an [Interface].
+const RootModule = "$root"
+
+// CoreImport represents a Core WebAssembly function imported by a module that
+// implements a [World], such as a go:wasmimport function.
+type CoreImport struct {
+	// Module is the Core WebAssembly module name, e.g. wasi:io/streams@0.2.0.
+	// Intrinsics of resources in exported interfaces have the prefix [export].
+	// Functions imported directly by a world have module name [RootModule].
+	Module string
+
+	// Name is the Core WebAssembly function name, e.g. [method]input-stream.read
+	// or [resource-drop]input-stream.
+	Name string
+
+	// Function is the WIT function, which may be a resource intrinsic
+	// returned by [TypeDef.ResourceDrop], [TypeDef.ResourceNew], or [TypeDef.ResourceRep].
+	Function *Function
+
+	// Direction is Imported for imported functions, or Exported for the
+	// intrinsics of resources in exported interfaces, which use exported types.
+	// The Core WebAssembly signature is returned by Function.CoreSignature(Direction).
+	Direction Direction
+}
+
+// CoreImports returns the Core WebAssembly functions that a module implementing [World] w
+// may import, in world order. Each has the exact module and function name lowered by the
+// Canonical ABI, which is the go:wasmimport directive of a generated Go function.
+// This includes resource intrinsics, such as [resource-drop]input-stream.
+//
+// The module name of an anonymous interface declared inline in w is its name in w.
+func (w *World) CoreImports() []CoreImport {
+	var imports []CoreImport
+	add := func(module string, f *Function, dir Direction) {
+		imports = append(imports, CoreImport{Module: module, Name: f.Name, Function: f, Direction: dir})
+	}
+	w.Imports.All()(func(name string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *Interface:
+			module := coreModuleName(name, item)
+			item.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+				if f := t.ResourceDrop(); f != nil {
+					add(module, f, Imported)
+				}
+				return true
+			})
+			item.Functions.All()(func(_ string, f *Function) bool {
+				add(module, f, Imported)
+				return true
+			})
+		case *TypeDef:
+			if f := item.ResourceDrop(); f != nil {
+				add(RootModule, f, Imported)
+			}
+		case *Function:
+			// A world function may be renamed when its world is included.
+			imports = append(imports, CoreImport{Module: RootModule, Name: name, Function: item, Direction: Imported})
+		}
+		return true
+	})
+
+	// Exported resources are created, dropped, and unwrapped via imported functions.
+	w.Exports.All()(func(name string, item WorldItem) bool {
+		if i, ok := item.(*Interface); ok {
+			module := "[export]" + coreModuleName(name, i)
+			i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+				for _, f := range []*Function{t.ResourceNew(), t.ResourceRep(), t.ResourceDrop()} {
+					if f != nil {
+						add(module, f, Exported)
+					}
+				}
+				return true
+			})
+		}
+		return true
+	})
+	return imports
+}
+
+// coreModuleName returns the Core WebAssembly module name of interface i with name in a world:
+// the fully-qualified name of a named interface, otherwise its name in the world.
+func coreModuleName(name string, i *Interface) string {
+	if id, ok := i.ID(); ok {
+		return id.String()
+	}
+	return name
+}
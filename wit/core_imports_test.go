@@ -0,0 +1,60 @@
+package wit
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWorldCoreImports(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wit/world.wit": {Data: []byte(`package ex:root@0.1.0;
+
+interface i {
+	resource r;
+	f: func(x: borrow<r>);
+}
+
+world w {
+	resource res;
+	import f: func(x: u32);
+	import g: func() -> res;
+	import i;
+	import inline: interface {
+		h: func();
+	}
+	export h: func() -> u32;
+	export i;
+}
+`)},
+	}
+	res, err := LoadFS(fsys, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := res.World("ex:root/w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, imp := range w.CoreImports() {
+		got = append(got, imp.Module+" "+imp.Name+" "+imp.Direction.String())
+		if imp.Function == nil {
+			t.Errorf("CoreImport %s %s: nil Function", imp.Module, imp.Name)
+		}
+	}
+	want := []string{
+		"ex:root/i@0.1.0 [resource-drop]r imported",
+		"ex:root/i@0.1.0 f imported",
+		"inline h imported",
+		"$root [resource-drop]res imported",
+		"$root f imported",
+		"$root g imported",
+		"[export]ex:root/i@0.1.0 [resource-new]r exported",
+		"[export]ex:root/i@0.1.0 [resource-rep]r exported",
+		"[export]ex:root/i@0.1.0 [resource-drop]r exported",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("World.CoreImports():\n%v\nexpected:\n%v", got, want)
+	}
+}
@@ -0,0 +1,204 @@
+package wit
+
+import "strings"
+
+// CycleError is returned when a [Resolve] contains a cyclic definition,
+// such as a [TypeDef] that contains itself, or an [Interface] that
+// transitively uses types from itself.
+type CycleError struct {
+	// Kind is the kind of definition, either "type" or "interface".
+	Kind string
+
+	// Path is the chain of definitions that form the cycle.
+	// The first and last elements are the same.
+	Path []string
+}
+
+// Error implements the error interface.
+func (err *CycleError) Error() string {
+	return "cyclic " + err.Kind + " definition: " + strings.Join(err.Path, " → ")
+}
+
+// checkCycles returns a [CycleError] if res contains a cyclic
+// type definition or cyclic interface dependency. WIT does not permit recursive types,
+// and a cycle would otherwise overflow the stack when computing ABI layout or printing WIT.
+func (res *Resolve) checkCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	// Types
+	state := make(map[*TypeDef]int, len(res.TypeDefs))
+	var path []*TypeDef
+	var visitType func(t Type) error
+	visitType = func(t Type) error {
+		td, ok := t.(*TypeDef)
+		if !ok {
+			return nil
+		}
+		switch state[td] {
+		case visited:
+			return nil
+		case visiting:
+			return typeCycleError(path, td)
+		}
+		state[td] = visiting
+		path = append(path, td)
+		for _, t := range typeDefChildren(td) {
+			if err := visitType(t); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[td] = visited
+		return nil
+	}
+	for _, td := range res.TypeDefs {
+		if err := visitType(td); err != nil {
+			return err
+		}
+	}
+
+	// Interfaces
+	istate := make(map[*Interface]int, len(res.Interfaces))
+	var ipath []*Interface
+	var visitInterface func(i *Interface) error
+	visitInterface = func(i *Interface) error {
+		switch istate[i] {
+		case visited:
+			return nil
+		case visiting:
+			return interfaceCycleError(ipath, i)
+		}
+		istate[i] = visiting
+		ipath = append(ipath, i)
+		var err error
+		i.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+			used, ok := td.Kind.(*TypeDef)
+			if !ok {
+				return true
+			}
+			if dep, ok := used.Owner.(*Interface); ok && dep != i {
+				err = visitInterface(dep)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		ipath = ipath[:len(ipath)-1]
+		istate[i] = visited
+		return nil
+	}
+	for _, i := range res.Interfaces {
+		if err := visitInterface(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// typeDefChildren returns the types directly referenced by td.
+func typeDefChildren(td *TypeDef) []Type {
+	switch kind := td.Kind.(type) {
+	case *TypeDef:
+		return []Type{kind}
+	case *Record:
+		types := make([]Type, 0, len(kind.Fields))
+		for _, f := range kind.Fields {
+			types = append(types, f.Type)
+		}
+		return types
+	case *Tuple:
+		return kind.Types
+	case *Variant:
+		types := make([]Type, 0, len(kind.Cases))
+		for _, c := range kind.Cases {
+			if c.Type != nil {
+				types = append(types, c.Type)
+			}
+		}
+		return types
+	case *Option:
+		return []Type{kind.Type}
+	case *Result:
+		return nonNil(kind.OK, kind.Err)
+	case *List:
+		return []Type{kind.Type}
+	case *Future:
+		return nonNil(kind.Type)
+	case *Stream:
+		return nonNil(kind.Element, kind.End)
+	case *Own:
+		return []Type{kind.Type}
+	case *Borrow:
+		return []Type{kind.Type}
+	}
+	return nil
+}
+
+func nonNil(types ...Type) []Type {
+	out := types[:0]
+	for _, t := range types {
+		if t != nil {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func typeCycleError(path []*TypeDef, td *TypeDef) error {
+	err := &CycleError{Kind: "type"}
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == td {
+			for _, t := range path[i:] {
+				err.Path = append(err.Path, typeDefPathName(t))
+			}
+			break
+		}
+	}
+	err.Path = append(err.Path, typeDefPathName(td))
+	return err
+}
+
+func typeDefPathName(td *TypeDef) string {
+	if td.Name == nil {
+		return "(anonymous " + td.Kind.WITKind() + ")"
+	}
+	switch owner := td.Owner.(type) {
+	case *Interface:
+		if id, ok := owner.ID(); ok {
+			return id.String() + "#" + *td.Name
+		}
+	case *World:
+		if owner.Package != nil {
+			return worldID(owner) + "#" + *td.Name
+		}
+	}
+	return *td.Name
+}
+
+func interfaceCycleError(path []*Interface, i *Interface) error {
+	err := &CycleError{Kind: "interface"}
+	for j := len(path) - 1; j >= 0; j-- {
+		if path[j] == i {
+			for _, i := range path[j:] {
+				err.Path = append(err.Path, interfacePathName(i))
+			}
+			break
+		}
+	}
+	err.Path = append(err.Path, interfacePathName(i))
+	return err
+}
+
+func interfacePathName(i *Interface) string {
+	id, ok := i.ID()
+	if !ok {
+		return "(anonymous interface)"
+	}
+	return id.String()
+}
@@ -0,0 +1,88 @@
+package wit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckCycles(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			"no cycle",
+			`{
+				"interfaces": [{"name": "a", "types": {"x": 0, "y": 1}, "functions": {}, "package": 0}],
+				"types": [
+					{"name": "x", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+					{"name": "y", "kind": {"record": {"fields": [{"name": "f", "type": 0}]}}, "owner": {"interface": 0}}
+				],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {}}]
+			}`,
+			"",
+		},
+		{
+			"record contains itself",
+			`{
+				"interfaces": [{"name": "a", "types": {"x": 0}, "functions": {}, "package": 0}],
+				"types": [
+					{"name": "x", "kind": {"record": {"fields": [{"name": "f", "type": 0}]}}, "owner": {"interface": 0}}
+				],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {}}]
+			}`,
+			"cyclic type definition: foo:bar/a#x → foo:bar/a#x",
+		},
+		{
+			"record contains itself via anonymous list",
+			`{
+				"interfaces": [{"name": "a", "types": {"x": 0}, "functions": {}, "package": 0}],
+				"types": [
+					{"name": "x", "kind": {"record": {"fields": [{"name": "f", "type": 1}]}}, "owner": {"interface": 0}},
+					{"name": null, "kind": {"list": 0}, "owner": null}
+				],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {}}]
+			}`,
+			"cyclic type definition: foo:bar/a#x → (anonymous list) → foo:bar/a#x",
+		},
+		{
+			"interfaces use each other",
+			`{
+				"interfaces": [
+					{"name": "a", "types": {"x": 0, "y": 1}, "functions": {}, "package": 0},
+					{"name": "b", "types": {"x": 2, "y": 3}, "functions": {}, "package": 0}
+				],
+				"types": [
+					{"name": "x", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+					{"name": "y", "kind": {"type": 3}, "owner": {"interface": 0}},
+					{"name": "x", "kind": {"type": 0}, "owner": {"interface": 1}},
+					{"name": "y", "kind": {"type": "u32"}, "owner": {"interface": 1}}
+				],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0, "b": 1}, "worlds": {}}]
+			}`,
+			"cyclic interface definition: foo:bar/a → foo:bar/b → foo:bar/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeJSON(strings.NewReader(tt.json))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("DecodeJSON: unexpected error: %v", err)
+				}
+				return
+			}
+			var cerr *CycleError
+			if !errors.As(err, &cerr) {
+				t.Errorf("DecodeJSON: got %v, expected a *CycleError", err)
+				return
+			}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("DecodeJSON: got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package wit
+
+import "strings"
+
+// Dependency is an [Interface] in the import closure of another interface.
+// It is returned by [Interface.Dependencies].
+type Dependency struct {
+	// Interface is the interface depended on.
+	Interface *Interface
+
+	// TypeDefs are the types owned by Interface that are used by the other interfaces
+	// in the closure, directly or through other types, in the order declared in Interface.
+	TypeDefs []*TypeDef
+}
+
+// Dependencies returns the import closure of i: the interfaces, other than i, that i
+// uses types from, directly or transitively, across packages. A WIT package that
+// declares i resolves only if the packages of these interfaces are present, e.g. in
+// its deps directory.
+//
+// The dependencies are ordered so that each interface follows the interfaces it depends on.
+// The order is stable: it follows the order types and functions are declared in i.
+func (i *Interface) Dependencies() []Dependency {
+	used := make(map[*TypeDef]bool)
+	visited := map[*Interface]bool{i: true}
+	var order []*Interface
+
+	var visit func(face *Interface)
+	var markType func(td *TypeDef)
+	markType = func(td *TypeDef) {
+		if used[td] {
+			return
+		}
+		used[td] = true
+		walkNamedTypeDefs(td, func(dep *TypeDef) {
+			if dep != td {
+				markType(dep)
+			}
+		})
+		if owner, ok := td.Owner.(*Interface); ok && !visited[owner] {
+			visited[owner] = true
+			visit(owner)
+			order = append(order, owner)
+		}
+	}
+	visit = func(face *Interface) {
+		useType := func(t Type) {
+			walkNamedTypeDefs(t, func(td *TypeDef) {
+				if td.Owner != TypeOwner(face) {
+					markType(td)
+				}
+			})
+		}
+		face.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+			useType(td)
+			return true
+		})
+		face.Functions.All()(func(_ string, f *Function) bool {
+			for _, p := range f.Params {
+				useType(p.Type)
+			}
+			for _, r := range f.Results {
+				useType(r.Type)
+			}
+			return true
+		})
+	}
+	visit(i)
+
+	deps := make([]Dependency, 0, len(order))
+	for _, face := range order {
+		dep := Dependency{Interface: face}
+		face.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+			if used[td] {
+				dep.TypeDefs = append(dep.TypeDefs, td)
+			}
+			return true
+		})
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// String returns the dependency in the form of a WIT use statement, with the
+// versioned ID of the interface and the names of its types, e.g.
+// wasi:io/streams@0.2.0.{input-stream, output-stream}.
+func (dep Dependency) String() string {
+	var b strings.Builder
+	b.WriteString(interfacePathName(dep.Interface))
+	if len(dep.TypeDefs) > 0 {
+		b.WriteString(".{")
+		for i, td := range dep.TypeDefs {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(escape(*td.Name))
+		}
+		b.WriteString("}")
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package wit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterfaceDependencies(t *testing.T) {
+	res, err := LoadJSON("../testdata/wasi/0.2.0/http.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"wasi:io/error@0.2.0", nil},
+		{"wasi:io/streams@0.2.0", []string{
+			"wasi:io/error@0.2.0.{error}",
+			"wasi:io/poll@0.2.0.{pollable}",
+		}},
+		{"wasi:http/outgoing-handler@0.2.0", []string{
+			"wasi:io/poll@0.2.0.{pollable}",
+			"wasi:clocks/monotonic-clock@0.2.0.{duration}",
+			"wasi:io/error@0.2.0.{error}",
+			"wasi:io/streams@0.2.0.{input-stream, output-stream}",
+			"wasi:http/types@0.2.0.{DNS-error-payload, TLS-alert-received-payload, field-size-payload, error-code, outgoing-request, request-options, future-incoming-response}",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i, err := res.Interface(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, dep := range i.Dependencies() {
+				got = append(got, dep.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Dependencies: %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
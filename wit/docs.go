@@ -29,6 +29,18 @@
 //
 //	// Do something with res
 //
+// # Comments
+//
+// [LoadFS], like recent versions of wasm-tools, treats the comments on the lines directly above
+// a definition as its doc comments, stored in [Docs]. Other comments in WIT text are attached to
+// the nearest definition as [Comments]: free-standing comments separated from a definition by
+// a blank line, a comment on the same line as the end of a definition, and comments before
+// a closing brace or at the end of a file. [Resolve.WIT] prints them with the definition,
+// so they survive a round trip through a [Resolve]. Comments on use statements, includes,
+// and imports or exports of named interfaces, which have no [Docs], and comments within
+// a definition, such as in a function signature, are attached to the next definition.
+// JSON generated by wasm-tools contains only doc comments.
+//
 // [WebAssembly Interface Type]: https://component-model.bytecodealliance.org/design/wit.html
 // [WebAssembly Component Model]: https://component-model.bytecodealliance.org/introduction.html
 // [wit-parser]: https://docs.rs/wit-parser/latest/wit_parser/
@@ -0,0 +1,360 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// EncodeJSON encodes res as JSON to w, in the format produced by
+// wasm-tools component wit -j and read by [DecodeJSON].
+func EncodeJSON(w io.Writer, res *Resolve) error {
+	b, err := res.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// MarshalJSON implements the [json.Marshaler] interface, returning the
+// indented JSON representation of res. See [EncodeJSON] for more information.
+func (res *Resolve) MarshalJSON() ([]byte, error) {
+	e := &encoder{
+		worlds:     indexes(res.Worlds),
+		interfaces: indexes(res.Interfaces),
+		typeDefs:   indexes(res.TypeDefs),
+		packages:   indexes(res.Packages),
+	}
+	v := jsonObject{
+		{"worlds", mapSlice(res.Worlds, e.world)},
+		{"interfaces", mapSlice(res.Interfaces, e.iface)},
+		{"types", mapSlice(res.TypeDefs, e.typeDef)},
+		{"packages", mapSlice(res.Packages, e.pkg)},
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	var b bytes.Buffer
+	err := writeJSON(&b, v, "")
+	if err != nil {
+		return nil, err
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// encoder translates the pointer graph of a [Resolve] into JSON values,
+// replacing references with indexes.
+type encoder struct {
+	worlds     map[*World]int
+	interfaces map[*Interface]int
+	typeDefs   map[*TypeDef]int
+	packages   map[*Package]int
+	err        error
+}
+
+func (e *encoder) world(w *World) any {
+	return jsonObject{
+		{"name", w.Name},
+		{"imports", e.worldItems(&w.Imports, w.ImportStability)},
+		{"exports", e.worldItems(&w.Exports, w.ExportStability)},
+		{"package", e.pkgRef(w.Package)},
+	}.withDocs(w.Docs).withStability(w.Stability)
+}
+
+// worldItems encodes the imports or exports of a world. An interface with
+// stability is encoded as an object with fields "id" and "stability",
+// as newer versions of wasm-tools encode it.
+func (e *encoder) worldItems(m *ordered.Map[string, WorldItem], stability map[string]*Stability) any {
+	o := jsonObject{}
+	m.All()(func(name string, item WorldItem) bool {
+		o = append(o, jsonMember{name, e.worldItem(item, stability[name])})
+		return true
+	})
+	return o
+}
+
+func (e *encoder) worldItem(item WorldItem, s *Stability) any {
+	switch item := item.(type) {
+	case *Interface:
+		if s != nil {
+			return jsonObject{{"interface", jsonObject{{"id", e.ifaceRef(item)}}.withStability(s)}}
+		}
+		return jsonObject{{"interface", e.ifaceRef(item)}}
+	case *TypeDef:
+		return jsonObject{{"type", e.typeDefRef(item)}}
+	case *Function:
+		return jsonObject{{"function", e.function(item)}}
+	}
+	e.fail(fmt.Errorf("unknown world item %T", item))
+	return nil
+}
+
+func (e *encoder) iface(i *Interface) any {
+	var name any
+	if i.Name != nil {
+		name = *i.Name
+	}
+	return jsonObject{
+		{"name", name},
+		{"types", mapOrdered(&i.TypeDefs, e.typeDefRef)},
+		{"functions", mapOrdered(&i.Functions, e.function)},
+	}.withDocs(i.Docs).withStability(i.Stability).with("package", e.pkgRef(i.Package))
+}
+
+func (e *encoder) typeDef(t *TypeDef) any {
+	var name any
+	if t.Name != nil {
+		name = *t.Name
+	}
+	var owner any
+	switch o := t.Owner.(type) {
+	case *Interface:
+		owner = jsonObject{{"interface", e.ifaceRef(o)}}
+	case *World:
+		owner = jsonObject{{"world", e.worldRef(o)}}
+	}
+	return jsonObject{
+		{"name", name},
+		{"kind", e.typeDefKind(t.Kind)},
+		{"owner", owner},
+	}.withDocs(t.Docs).withStability(t.Stability)
+}
+
+func (e *encoder) typeDefKind(kind TypeDefKind) any {
+	switch kind := kind.(type) {
+	case Type:
+		return jsonObject{{"type", e.typ(kind)}}
+	case *Resource:
+		return "resource"
+	case *Record:
+		return jsonObject{{"record", jsonObject{{"fields", mapSlice(kind.Fields, func(f Field) any {
+			return jsonObject{{"name", f.Name}, {"type", e.typ(f.Type)}}.withDocs(f.Docs)
+		})}}}}
+	case *Own:
+		return jsonObject{{"handle", jsonObject{{"own", e.typeDefRef(kind.Type)}}}}
+	case *Borrow:
+		return jsonObject{{"handle", jsonObject{{"borrow", e.typeDefRef(kind.Type)}}}}
+	case *Flags:
+		return jsonObject{{"flags", jsonObject{{"flags", mapSlice(kind.Flags, func(f Flag) any {
+			return jsonObject{{"name", f.Name}}.withDocs(f.Docs)
+		})}}}}
+	case *Tuple:
+		return jsonObject{{"tuple", jsonObject{{"types", mapSlice(kind.Types, e.typ)}}}}
+	case *Variant:
+		return jsonObject{{"variant", jsonObject{{"cases", mapSlice(kind.Cases, func(c Case) any {
+			return jsonObject{{"name", c.Name}, {"type", e.typ(c.Type)}}.withDocs(c.Docs)
+		})}}}}
+	case *Enum:
+		return jsonObject{{"enum", jsonObject{{"cases", mapSlice(kind.Cases, func(c EnumCase) any {
+			return jsonObject{{"name", c.Name}}.withDocs(c.Docs)
+		})}}}}
+	case *Option:
+		return jsonObject{{"option", e.typ(kind.Type)}}
+	case *Result:
+		return jsonObject{{"result", jsonObject{{"ok", e.typ(kind.OK)}, {"err", e.typ(kind.Err)}}}}
+	case *List:
+		return jsonObject{{"list", e.typ(kind.Type)}}
+	case *Future:
+		return jsonObject{{"future", e.typ(kind.Type)}}
+	case *Stream:
+		return jsonObject{{"stream", jsonObject{{"element", e.typ(kind.Element)}, {"end", e.typ(kind.End)}}}}
+	}
+	e.fail(fmt.Errorf("cannot encode type kind %T", kind))
+	return nil
+}
+
+func (e *encoder) typ(t Type) any {
+	switch t := t.(type) {
+	case nil:
+		return nil
+	case *TypeDef:
+		return e.typeDefRef(t)
+	}
+	return t.TypeName()
+}
+
+func (e *encoder) function(f *Function) any {
+	var kind any
+	switch k := f.Kind.(type) {
+	case *Freestanding:
+		kind = "freestanding"
+	case *Method:
+		kind = jsonObject{{"method", e.typ(k.Type)}}
+	case *Static:
+		kind = jsonObject{{"static", e.typ(k.Type)}}
+	case *Constructor:
+		kind = jsonObject{{"constructor", e.typ(k.Type)}}
+	default:
+		e.fail(fmt.Errorf("cannot encode function kind %T", k))
+	}
+	return jsonObject{
+		{"name", f.Name},
+		{"kind", kind},
+		{"params", mapSlice(f.Params, e.param)},
+		{"results", mapSlice(f.Results, e.param)},
+	}.withDocs(f.Docs).withStability(f.Stability)
+}
+
+func (e *encoder) param(p Param) any {
+	if p.Name == "" {
+		return jsonObject{{"type", e.typ(p.Type)}}
+	}
+	return jsonObject{{"name", p.Name}, {"type", e.typ(p.Type)}}
+}
+
+func (e *encoder) pkg(p *Package) any {
+	return jsonObject{{"name", p.Name.String()}}.
+		withDocs(p.Docs).
+		with("interfaces", mapOrdered(&p.Interfaces, e.ifaceRef)).
+		with("worlds", mapOrdered(&p.Worlds, e.worldRef))
+}
+
+func (e *encoder) worldRef(w *World) any             { return ref(e, e.worlds, w, "world") }
+func (e *encoder) ifaceRef(i *Interface) any         { return ref(e, e.interfaces, i, "interface") }
+func (e *encoder) typeDefRef(t *TypeDef) any         { return ref(e, e.typeDefs, t, "type") }
+func (e *encoder) pkgRef(p *Package) any             { return ref(e, e.packages, p, "package") }
+func (e *encoder) fail(err error)                    { e.err = errors.Join(e.err, err) }
+func (o jsonObject) with(k string, v any) jsonObject { return append(o, jsonMember{k, v}) }
+
+// withDocs appends docs to o if not empty.
+func (o jsonObject) withDocs(docs Docs) jsonObject {
+	if docs.Contents == "" {
+		return o
+	}
+	return o.with("docs", jsonObject{{"contents", docs.Contents}})
+}
+
+// withStability appends stability to o if not nil.
+func (o jsonObject) withStability(s *Stability) jsonObject {
+	if s == nil {
+		return o
+	}
+	var v jsonObject
+	if s.Since != nil {
+		v = v.with("since", s.Since.String())
+	}
+	if s.Feature != "" {
+		v = v.with("feature", s.Feature)
+	}
+	if s.Deprecated != nil {
+		v = v.with("deprecated", s.Deprecated.String())
+	}
+	kind := "stable"
+	if s.Feature != "" {
+		kind = "unstable"
+	}
+	return o.with("stability", jsonObject{{kind, v}})
+}
+
+// ref returns the index of v in m, or nil if v is nil.
+func ref[T any](e *encoder, m map[*T]int, v *T, kind string) any {
+	if v == nil {
+		return nil
+	}
+	i, ok := m[v]
+	if !ok {
+		e.fail(fmt.Errorf("%s not found in Resolve", kind))
+		return nil
+	}
+	return i
+}
+
+func indexes[T comparable](s []T) map[T]int {
+	m := make(map[T]int, len(s))
+	for i, v := range s {
+		m[v] = i
+	}
+	return m
+}
+
+func mapSlice[T any](s []T, f func(T) any) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = f(v)
+	}
+	return out
+}
+
+func mapOrdered[V any](m *ordered.Map[string, V], f func(V) any) jsonObject {
+	o := jsonObject{}
+	m.All()(func(k string, v V) bool {
+		o = append(o, jsonMember{k, f(v)})
+		return true
+	})
+	return o
+}
+
+// jsonObject is a JSON object that preserves the order of its members.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	key   string
+	value any
+}
+
+// writeJSON writes v as indented JSON to b. Unlike [json.MarshalIndent],
+// it preserves object member order and does not escape HTML characters.
+func writeJSON(b *bytes.Buffer, v any, indent string) error {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case int:
+		b.WriteString(strconv.Itoa(v))
+	case string:
+		enc := json.NewEncoder(b)
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(v)
+		if err != nil {
+			return err
+		}
+		b.Truncate(b.Len() - 1) // trailing newline
+	case []any:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			return nil
+		}
+		b.WriteString("[\n")
+		for i, elem := range v {
+			b.WriteString(indent + "  ")
+			if err := writeJSON(b, elem, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "]")
+	case jsonObject:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return nil
+		}
+		b.WriteString("{\n")
+		for i, m := range v {
+			b.WriteString(indent + "  ")
+			if err := writeJSON(b, m.key, ""); err != nil {
+				return err
+			}
+			b.WriteString(": ")
+			if err := writeJSON(b, m.value, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "}")
+	default:
+		return fmt.Errorf("cannot encode %T as JSON", v)
+	}
+	return nil
+}
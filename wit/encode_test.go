@@ -0,0 +1,123 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// TestEncodeJSONRoundTrip verifies that every WIT JSON file in the testdata corpus
+// survives a decode, encode, decode round trip. The re-encoded JSON must match
+// the original, ignoring leading and trailing whitespace, and the decoded [Resolve] must produce the
+// same WIT text as the golden file. JSON written by newer versions of wasm-tools is
+// compared after rewriting it to the schema written by [EncodeJSON]; see [olderSchema].
+//
+// To add a file to the corpus, generate its JSON with the wasm-tools version in version.WasmToolsLatest,
+// from a WIT source in testdata/corpus:
+//
+//	wasm-tools component wit -j testdata/corpus/name.wit > testdata/corpus/name.wit.json
+//
+// Then run go test ./wit -update to write its golden WIT file.
+func TestEncodeJSONRoundTrip(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			data, err := res.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			orig, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(data) {
+				t.Fatal("invalid JSON")
+			}
+			var want any
+			if err := json.Unmarshal(orig, &want); err != nil {
+				t.Fatal(err)
+			}
+			if olderSchema(want) {
+				var got any
+				if err := json.Unmarshal(data, &got); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("re-encoded JSON does not match %s in the older schema:\n%s", path, data)
+				}
+			} else if got, want := bytes.TrimSpace(data), bytes.TrimSpace(orig); !bytes.Equal(got, want) {
+				dmp := diffmatchpatch.New()
+				diffs := dmp.DiffMain(string(want), string(got), false)
+				t.Errorf("re-encoded JSON does not match %s:\n%v", path, dmp.DiffPrettyText(diffs))
+			}
+
+			res2, err := DecodeJSON(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res2.WIT(nil, ""), res.WIT(nil, ""); got != want {
+				t.Errorf("WIT for re-encoded %s does not match:\n%s", path, got)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// olderSchema rewrites v, WIT JSON decoded by [json.Unmarshal], from the schema written
+// by newer versions of wasm-tools to the schema written by [EncodeJSON]. Newer versions
+// write a function result as "result", omitted if the function has no result, rather than
+// "results"; and an interface imported or exported by a world as {"id": N}, rather than N.
+// It reports whether v was rewritten.
+func olderSchema(v any) bool {
+	var rewritten bool
+	function := func(f map[string]any) {
+		if _, ok := f["results"]; ok {
+			return
+		}
+		results := []any{}
+		if r, ok := f["result"]; ok {
+			results = append(results, map[string]any{"type": r})
+			delete(f, "result")
+		}
+		f["results"] = results
+		rewritten = true
+	}
+	doc := asObject(v)
+	for _, i := range asArray(doc["interfaces"]) {
+		for _, f := range asObject(asObject(i)["functions"]) {
+			function(asObject(f))
+		}
+	}
+	for _, w := range asArray(doc["worlds"]) {
+		for _, key := range []string{"imports", "exports"} {
+			for _, item := range asObject(asObject(w)[key]) {
+				item := asObject(item)
+				if f, ok := item["function"]; ok {
+					function(asObject(f))
+				}
+				if i := asObject(item["interface"]); len(i) == 1 {
+					item["interface"] = i["id"]
+					rewritten = true
+				}
+			}
+		}
+	}
+	return rewritten
+}
+
+func asObject(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asArray(v any) []any {
+	a, _ := v.([]any)
+	return a
+}
@@ -0,0 +1,66 @@
+package wit
+
+import "fmt"
+
+// StringEncoding represents a [Canonical ABI] string-encoding option, which determines
+// how a component represents strings in linear memory when it lifts or lowers values.
+// The representation of a string value is the same for each encoding: a pointer
+// and a length, flattened to (i32, i32). The encoding determines the unit of the length,
+// and the alignment of the string data.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#canonical-abi
+type StringEncoding uint8
+
+const (
+	// UTF8 is the default string encoding. The length of a string is its number of bytes.
+	UTF8 StringEncoding = iota
+
+	// UTF16 encodes strings as UTF-16 code units, as used by JavaScript.
+	// The length of a string is its number of 16-bit code units.
+	UTF16
+
+	// Latin1UTF16 encodes each string as either Latin-1, with one byte per character,
+	// or UTF-16 if the string contains a character that cannot be represented in Latin-1.
+	// The high bit of the length, [UTF16Tag], is set if the string is encoded as UTF-16.
+	Latin1UTF16
+)
+
+// UTF16Tag is set in the length of a string encoded with [Latin1UTF16]
+// if the string is encoded as UTF-16 rather than Latin-1.
+const UTF16Tag = 1 << 31
+
+// ParseStringEncoding parses a string encoding in the form used by
+// canonical options in the WebAssembly text format: utf8, utf16, or latin1+utf16.
+// It also accepts compact-utf16, the name of [Latin1UTF16] used by wasm-tools.
+func ParseStringEncoding(s string) (StringEncoding, error) {
+	switch s {
+	case "utf8":
+		return UTF8, nil
+	case "utf16":
+		return UTF16, nil
+	case "latin1+utf16", "compact-utf16":
+		return Latin1UTF16, nil
+	}
+	return 0, fmt.Errorf("unknown string encoding %q", s)
+}
+
+// String returns the name of e as a canonical option: utf8, utf16, or latin1+utf16.
+func (e StringEncoding) String() string {
+	switch e {
+	case UTF8:
+		return "utf8"
+	case UTF16:
+		return "utf16"
+	case Latin1UTF16:
+		return "latin1+utf16"
+	}
+	return fmt.Sprintf("StringEncoding(%d)", e)
+}
+
+// Align returns the alignment of string data encoded with e in linear memory.
+func (e StringEncoding) Align() uintptr {
+	if e == UTF8 {
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,69 @@
+package wit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStringEncoding(t *testing.T) {
+	tests := []struct {
+		enc   StringEncoding
+		s     string
+		align uintptr
+	}{
+		{UTF8, "utf8", 1},
+		{UTF16, "utf16", 2},
+		{Latin1UTF16, "latin1+utf16", 2},
+	}
+	for _, tt := range tests {
+		if got := tt.enc.String(); got != tt.s {
+			t.Errorf("String(): %q, expected %q", got, tt.s)
+		}
+		if got := tt.enc.Align(); got != tt.align {
+			t.Errorf("%s.Align(): %d, expected %d", tt.s, got, tt.align)
+		}
+		enc, err := ParseStringEncoding(tt.s)
+		if err != nil || enc != tt.enc {
+			t.Errorf("ParseStringEncoding(%q): %v, %v, expected %v", tt.s, enc, err, tt.enc)
+		}
+	}
+	if enc, err := ParseStringEncoding("compact-utf16"); err != nil || enc != Latin1UTF16 {
+		t.Errorf("ParseStringEncoding(%q): %v, %v, expected %v", "compact-utf16", enc, err, Latin1UTF16)
+	}
+	if _, err := ParseStringEncoding("utf32"); err == nil {
+		t.Errorf("ParseStringEncoding(%q): expected error", "utf32")
+	}
+}
+
+func TestWorldComponentTypeWithEncoding(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(componentTypeJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	utf8, err := w.ComponentType()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The string encoding follows the metadata version in the wit-component-encoding section,
+	// as emitted by wasm-tools component embed --encoding.
+	section := []byte("wit-component-encoding\x04")
+	i := bytes.Index(utf8, section) + len(section)
+	for _, enc := range []StringEncoding{UTF8, UTF16, Latin1UTF16} {
+		b, err := w.ComponentTypeWithEncoding(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := b[i], byte(enc); got != want {
+			t.Errorf("ComponentTypeWithEncoding(%s): encoding %#x, expected %#x", enc, got, want)
+		}
+		if !bytes.Equal(b[:i], utf8[:i]) || !bytes.Equal(b[i+1:], utf8[i+1:]) {
+			t.Errorf("ComponentTypeWithEncoding(%s): differs from ComponentType() in more than the encoding", enc)
+		}
+	}
+	if _, err := w.ComponentTypeWithEncoding(Latin1UTF16 + 1); err == nil {
+		t.Errorf("ComponentTypeWithEncoding(%d): expected error", Latin1UTF16+1)
+	}
+}
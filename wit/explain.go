@@ -0,0 +1,128 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain returns a line-based description of the fully-resolved structure of [TypeDef] t,
+// for debugging Canonical ABI mismatches. Each line describes a type with its size and
+// alignment, and the offset of a record field or variant payload. Type aliases, nested types,
+// and handles are expanded, and named types are identified by their fully-qualified name.
+// For example:
+//
+//	record ex:a/i#point (size 8, align 4)
+//	  x: u32 (offset 0, size 4, align 4)
+//	  y: type ex:a/i#coord = u32 (offset 4, size 4, align 4)
+//	    u32 (size 4, align 4)
+func (t *TypeDef) Explain() string {
+	var b strings.Builder
+	explainType(&b, 0, "", -1, t)
+	return b.String()
+}
+
+// explainType writes a line describing t to b, followed by a line for each type it contains,
+// indented by depth. If offset is non-negative, it is the offset of t in its containing type.
+func explainType(b *strings.Builder, depth int, label string, offset int, t Type) {
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(label)
+	b.WriteString(explainName(t))
+	b.WriteString(" (")
+	if offset >= 0 {
+		fmt.Fprintf(b, "offset %d, ", offset)
+	}
+	fmt.Fprintf(b, "size %d, align %d)\n", t.Size(), t.Align())
+
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return
+	}
+	depth++
+	// Tuples are explained as records, and enums, options, and results as variants.
+	switch kind := Despecialize(td.Kind).(type) {
+	case *Record:
+		explainFields(b, depth, kind.Fields)
+	case *Variant:
+		explainCases(b, depth, kind)
+	case *Flags:
+		for i, f := range kind.Flags {
+			fmt.Fprintf(b, "%s%s (bit %d)\n", strings.Repeat("  ", depth), escape(f.Name), i)
+		}
+	case *Own:
+		explainType(b, depth, "", -1, kind.Type)
+	case *Borrow:
+		explainType(b, depth, "", -1, kind.Type)
+	case *List:
+		explainType(b, depth, "", -1, kind.Type)
+	case *Future:
+		if kind.Type != nil {
+			explainType(b, depth, "", -1, kind.Type)
+		}
+	case *Stream:
+		if kind.Element != nil {
+			explainType(b, depth, "", -1, kind.Element)
+		}
+		if kind.End != nil {
+			explainType(b, depth, "end: ", -1, kind.End)
+		}
+	case Type:
+		// Type alias, e.g. type a = b, or a primitive type alias, e.g. type size = u32.
+		explainType(b, depth, "", -1, kind)
+	}
+}
+
+func explainFields(b *strings.Builder, depth int, fields []Field) {
+	var offset uintptr
+	for _, f := range fields {
+		offset = Align(offset, f.Type.Align())
+		explainType(b, depth, escape(f.Name)+": ", int(offset), f.Type)
+		offset += f.Type.Size()
+	}
+}
+
+func explainCases(b *strings.Builder, depth int, v *Variant) {
+	offset := Align(Discriminant(len(v.Cases)).Size(), v.maxCaseAlign())
+	for i, c := range v.Cases {
+		if c.Type == nil {
+			fmt.Fprintf(b, "%s%s (case %d)\n", strings.Repeat("  ", depth), escape(c.Name), i)
+			continue
+		}
+		explainType(b, depth, fmt.Sprintf("%s (case %d): ", escape(c.Name), i), int(offset), c.Type)
+	}
+}
+
+// explainName returns the name of t: the name of a primitive type, the kind and
+// fully-qualified name of a named type, or the WIT syntax of an anonymous type.
+func explainName(t Type) string {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return t.TypeName()
+	}
+	if td.Name == nil {
+		return explainRef(td)
+	}
+	switch kind := td.Kind.(type) {
+	case *Record, *Variant, *Enum, *Flags, *Resource:
+		return kind.WITKind() + " " + typeDefPathName(td)
+	case *TypeDef:
+		return "type " + typeDefPathName(td) + " = " + explainRef(kind)
+	case Type:
+		return "type " + typeDefPathName(td) + " = " + kind.TypeName()
+	}
+	return "type " + typeDefPathName(td) + " = " + explainRef(&TypeDef{Kind: td.Kind})
+}
+
+// explainRef returns the WIT syntax of a reference to t, with owned handles written as own<r>.
+func explainRef(t Type) string {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return t.TypeName()
+	}
+	if td.Name != nil {
+		return escape(*td.Name)
+	}
+	if own, ok := td.Kind.(*Own); ok {
+		return "own<" + explainRef(own.Type) + ">"
+	}
+	return td.Kind.WIT(td.Kind, "")
+}
@@ -0,0 +1,61 @@
+package wit
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestExplain(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(`package ex:a;
+interface i {
+	resource r;
+	type coord = u32;
+	flags perms { read, write }
+	record point { x: coord, y: u64 }
+	record opts {
+		p: option<point>,
+		handles: list<own<r>>,
+		pair: tuple<u8, borrow<r>>,
+		perms: perms,
+		res: result<_, string>,
+	}
+}
+`)}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	td, err := res.TypeDef("ex:a/i#opts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `record ex:a/i#opts (size 56, align 8)
+  p: option<point> (offset 0, size 24, align 8)
+    none (case 0)
+    some (case 1): record ex:a/i#point (offset 8, size 16, align 8)
+      x: type ex:a/i#coord = u32 (offset 0, size 4, align 4)
+        u32 (size 4, align 4)
+      y: u64 (offset 8, size 8, align 8)
+  handles: list<r> (offset 24, size 8, align 4)
+    own<r> (size 4, align 4)
+      resource ex:a/i#r (size 4, align 4)
+  pair: tuple<u8, borrow<r>> (offset 32, size 8, align 4)
+    0: u8 (offset 0, size 1, align 1)
+    1: borrow<r> (offset 4, size 4, align 4)
+      resource ex:a/i#r (size 4, align 4)
+  perms: flags ex:a/i#perms (offset 40, size 1, align 1)
+    read (bit 0)
+    write (bit 1)
+  res: result<_, string> (offset 44, size 12, align 4)
+    ok (case 0)
+    error (case 1): string (offset 4, size 8, align 4)
+`
+	if got := td.Explain(); got != want {
+		t.Errorf("Explain():\n%s\nexpected:\n%s", got, want)
+	}
+
+	for _, id := range []string{"ex:a/i", "ex:a/i#missing", "ex:a/j#opts"} {
+		if _, err := res.TypeDef(id); err == nil {
+			t.Errorf("TypeDef(%q): nil error, expected error", id)
+		}
+	}
+}
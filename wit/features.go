@@ -0,0 +1,250 @@
+package wit
+
+import (
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Feature is a set of [Component Model] features that WIT may require of a toolchain or runtime.
+// Features are combined with the | operator.
+//
+// FeatureAsync, FeatureFixedSizeLists, and FeatureErrorContext cannot be represented in a
+// [Resolve], so WIT or WIT JSON that requires them fails to load with a [FeatureError].
+//
+// [Component Model]: https://github.com/WebAssembly/component-model
+type Feature uint
+
+const (
+	// FeatureResources is required by resource types and own and borrow handles.
+	FeatureResources Feature = 1 << iota
+
+	// FeatureFutures is required by future types.
+	FeatureFutures
+
+	// FeatureStreams is required by stream types.
+	FeatureStreams
+
+	// FeatureAsync is required by async functions.
+	FeatureAsync
+
+	// FeatureFixedSizeLists is required by fixed-size list types, e.g. list<u8, 4>.
+	FeatureFixedSizeLists
+
+	// FeatureErrorContext is required by the error-context type.
+	FeatureErrorContext
+)
+
+var featureNames = []string{
+	"resources",
+	"futures",
+	"streams",
+	"async",
+	"fixed-size-lists",
+	"error-context",
+}
+
+// String returns the names of the features in f, separated by commas,
+// e.g. "resources, streams". It returns "none" if f is empty.
+func (f Feature) String() string {
+	if f == 0 {
+		return "none"
+	}
+	var names []string
+	for i, name := range featureNames {
+		if f&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// Has returns true if f includes all features in g.
+func (f Feature) Has(g Feature) bool {
+	return f&g == g
+}
+
+// FeatureError is returned when WIT requires a [Feature] that is not supported,
+// either by package wit, or by a toolchain or runtime, as reported by [World.CheckFeatures].
+type FeatureError struct {
+	// Feature is the unsupported feature or features.
+	Feature Feature
+
+	// Path is the name of the item that requires Feature, e.g. "wasi:io/streams@0.2.0#input-stream",
+	// if known.
+	Path string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements the error interface.
+func (err *FeatureError) Error() string {
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	if err.Path == "" {
+		return "unsupported Component Model feature: " + err.Feature.String()
+	}
+	return err.Path + " requires unsupported Component Model feature: " + err.Feature.String()
+}
+
+// Unwrap returns the underlying error, if any.
+func (err *FeatureError) Unwrap() error {
+	return err.Err
+}
+
+// featureError returns a [FeatureError] wrapping err if name is the
+// WIT JSON name of a type or function kind that requires a [Feature]
+// that cannot be represented in a [Resolve]. Otherwise it returns err.
+func featureError(name string, err error) error {
+	var f Feature
+	switch name {
+	case "async-freestanding", "async-method", "async-static":
+		f = FeatureAsync
+	case "fixed-size-list":
+		f = FeatureFixedSizeLists
+	case "error-context":
+		f = FeatureErrorContext
+	default:
+		return err
+	}
+	return &FeatureError{Feature: f, Err: err}
+}
+
+// Features returns the set of features required by the types in res.
+func (res *Resolve) Features() Feature {
+	var f Feature
+	for _, t := range res.TypeDefs {
+		f |= kindFeatures(t.Kind)
+	}
+	return f
+}
+
+// Features returns the set of features required by the types and functions
+// imported or exported by w, including types used from other interfaces.
+func (w *World) Features() Feature {
+	var f Feature
+	w.walkFeatures(func(_ string, g Feature) bool {
+		f |= g
+		return true
+	})
+	return f
+}
+
+// CheckFeatures returns a [FeatureError] if w requires a [Feature] not in supported,
+// naming the first type or function that requires it.
+// Toolchains can call CheckFeatures to fail early when targeting a runtime that does
+// not support features such as resources or streams.
+func (w *World) CheckFeatures(supported Feature) error {
+	var err error
+	w.walkFeatures(func(path string, f Feature) bool {
+		if missing := f &^ supported; missing != 0 {
+			err = &FeatureError{Feature: missing, Path: path}
+		}
+		return err == nil
+	})
+	return err
+}
+
+// walkFeatures calls yield with the path name and features required by each named
+// type and function reachable from w, until yield returns false. The features
+// required by anonymous types are attributed to the type or function that uses them.
+func (w *World) walkFeatures(yield func(path string, f Feature) bool) {
+	seen := make(map[*TypeDef]bool)
+	var visitType func(t Type) bool
+	visitType = func(t Type) bool {
+		td, ok := t.(*TypeDef)
+		if !ok || seen[td] {
+			return true
+		}
+		seen[td] = true
+		if td.Name != nil {
+			f := kindFeatures(td.Kind)
+			for _, t := range typeDefChildren(td) {
+				f |= anonFeatures(t)
+			}
+			if f != 0 && !yield(typeDefPathName(td), f) {
+				return false
+			}
+		}
+		for _, t := range typeDefChildren(td) {
+			if !visitType(t) {
+				return false
+			}
+		}
+		return true
+	}
+	visitFunction := func(prefix string, f *Function) bool {
+		var features Feature
+		for _, params := range [][]Param{f.Params, f.Results} {
+			for _, p := range params {
+				if !visitType(p.Type) {
+					return false
+				}
+				features |= anonFeatures(p.Type)
+			}
+		}
+		return features == 0 || yield(prefix+f.Name, features)
+	}
+
+	prefix := w.Name + "#"
+	if w.Package != nil {
+		prefix = worldID(w) + "#"
+	}
+	done := false
+	for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+		items.All()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *Interface:
+				prefix := interfacePathName(item) + "#"
+				item.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+					done = !visitType(t)
+					return !done
+				})
+				if !done {
+					item.Functions.All()(func(_ string, f *Function) bool {
+						done = !visitFunction(prefix, f)
+						return !done
+					})
+				}
+			case *TypeDef:
+				done = !visitType(item)
+			case *Function:
+				done = !visitFunction(prefix, item)
+			}
+			return !done
+		})
+		if done {
+			return
+		}
+	}
+}
+
+// kindFeatures returns the features required by a type of kind k,
+// excluding the types it contains.
+func kindFeatures(k TypeDefKind) Feature {
+	switch k.(type) {
+	case *Resource, *Own, *Borrow:
+		return FeatureResources
+	case *Future:
+		return FeatureFutures
+	case *Stream:
+		return FeatureStreams
+	}
+	return 0
+}
+
+// anonFeatures returns the features required by t if it is an anonymous type,
+// including the anonymous types it contains. Named types are reported separately.
+func anonFeatures(t Type) Feature {
+	td, ok := t.(*TypeDef)
+	if !ok || td.Name != nil {
+		return 0
+	}
+	f := kindFeatures(td.Kind)
+	for _, t := range typeDefChildren(td) {
+		f |= anonFeatures(t)
+	}
+	return f
+}
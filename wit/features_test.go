@@ -0,0 +1,167 @@
+package wit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFeatures(t *testing.T) {
+	const src = `package a:b@0.1.0;
+
+interface files {
+	resource file;
+}
+
+interface io {
+	use files.{file};
+	read: func(f: borrow<file>) -> option<stream<u8>>;
+}
+
+interface plain {
+	record point { x: u32, y: u32 }
+	add: func(a: point, b: point) -> point;
+}
+
+world plain-world {
+	import plain;
+}
+
+world io-world {
+	import plain;
+	export io;
+}
+`
+	res, err := LoadFS(fstest.MapFS{"a.wit": {Data: []byte(src)}}, "a.wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := res.Features(), FeatureResources|FeatureStreams; got != want {
+		t.Errorf("Resolve.Features(): %v, expected %v", got, want)
+	}
+
+	worlds := make(map[string]*World)
+	for _, w := range res.Worlds {
+		worlds[w.Name] = w
+	}
+
+	if got := worlds["plain-world"].Features(); got != 0 {
+		t.Errorf("plain-world Features(): %v, expected none", got)
+	}
+	if err := worlds["plain-world"].CheckFeatures(0); err != nil {
+		t.Errorf("plain-world CheckFeatures(0): %v", err)
+	}
+
+	w := worlds["io-world"]
+	if got, want := w.Features(), FeatureResources|FeatureStreams; got != want {
+		t.Errorf("io-world Features(): %v, expected %v", got, want)
+	}
+	if err := w.CheckFeatures(FeatureResources | FeatureStreams); err != nil {
+		t.Errorf("io-world CheckFeatures: %v", err)
+	}
+	err = w.CheckFeatures(FeatureResources)
+	var ferr *FeatureError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("io-world CheckFeatures(FeatureResources): %v, expected a *FeatureError", err)
+	}
+	if ferr.Feature != FeatureStreams || ferr.Path != "a:b/io@0.1.0#read" {
+		t.Errorf("io-world CheckFeatures(FeatureResources): %v, expected streams required by a:b/io@0.1.0#read", err)
+	}
+	err = w.CheckFeatures(FeatureStreams)
+	if !errors.As(err, &ferr) || ferr.Feature != FeatureResources || ferr.Path != "a:b/files@0.1.0#file" {
+		t.Errorf("io-world CheckFeatures(FeatureStreams): %v, expected resources required by a:b/files@0.1.0#file", err)
+	}
+}
+
+func TestFeatureString(t *testing.T) {
+	tests := []struct {
+		f    Feature
+		want string
+	}{
+		{0, "none"},
+		{FeatureResources, "resources"},
+		{FeatureResources | FeatureStreams, "resources, streams"},
+		{FeatureAsync | FeatureFixedSizeLists | FeatureErrorContext, "async, fixed-size-lists, error-context"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.String(); got != tt.want {
+			t.Errorf("Feature(%d).String(): %q, expected %q", tt.f, got, tt.want)
+		}
+	}
+	if !(FeatureResources | FeatureStreams).Has(FeatureStreams) {
+		t.Error("Has: expected true")
+	}
+	if FeatureStreams.Has(FeatureResources | FeatureStreams) {
+		t.Error("Has: expected false")
+	}
+}
+
+func TestUnsupportedFeatureErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		load func() error
+		want Feature
+	}{
+		{
+			"JSON async function",
+			func() error {
+				_, err := DecodeJSON(strings.NewReader(`{"interfaces": [{"name": "i", "functions": {"f": {"name": "f", "kind": "async-freestanding", "params": []}}}]}`))
+				return err
+			},
+			FeatureAsync,
+		},
+		{
+			"JSON fixed-size list",
+			func() error {
+				_, err := DecodeJSON(strings.NewReader(`{"types": [{"name": "l", "kind": {"fixed-size-list": ["u8", 4]}, "owner": null}]}`))
+				return err
+			},
+			FeatureFixedSizeLists,
+		},
+		{
+			"JSON error-context",
+			func() error {
+				_, err := DecodeJSON(strings.NewReader(`{"types": [{"name": "e", "kind": {"type": "error-context"}, "owner": null}]}`))
+				return err
+			},
+			FeatureErrorContext,
+		},
+		{
+			"WIT async function",
+			func() error {
+				_, err := LoadFS(fstest.MapFS{"a.wit": {Data: []byte("package a:b;\ninterface i {\n\tf: async func();\n}\n")}}, "a.wit")
+				return err
+			},
+			FeatureAsync,
+		},
+		{
+			"WIT fixed-size list",
+			func() error {
+				_, err := LoadFS(fstest.MapFS{"a.wit": {Data: []byte("package a:b;\ninterface i {\n\ttype t = list<u8, 4>;\n}\n")}}, "a.wit")
+				return err
+			},
+			FeatureFixedSizeLists,
+		},
+		{
+			"WIT error-context",
+			func() error {
+				_, err := LoadFS(fstest.MapFS{"a.wit": {Data: []byte("package a:b;\ninterface i {\n\ttype t = error-context;\n}\n")}}, "a.wit")
+				return err
+			},
+			FeatureErrorContext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.load()
+			var ferr *FeatureError
+			if !errors.As(err, &ferr) {
+				t.Fatalf("error: %v, expected a *FeatureError", err)
+			}
+			if ferr.Feature != tt.want {
+				t.Errorf("Feature: %v, expected %v", ferr.Feature, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package wit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+)
+
+// FingerprintPrefix is the prefix of a fingerprint returned by
+// [Function.Fingerprint] or [Interface.Fingerprint].
+const FingerprintPrefix = "sha256:"
+
+// Fingerprint returns a content hash of the name and canonicalized signature of [Function] f,
+// e.g. "sha256:" followed by 64 hex digits. Two functions with the same fingerprint have the same
+// Canonical ABI, so bindings generated for one are compatible with components compiled for the other.
+//
+// The signature includes the names of params and results, and the structure of each type, with
+// type aliases resolved. Anonymous and named types with the same structure are equivalent,
+// except resources, which are identified by their fully-qualified name. Documentation and
+// feature gates such as @since are excluded.
+func (f *Function) Fingerprint() string {
+	var b strings.Builder
+	f.fingerprint(&b)
+	return fingerprintOf(b.String())
+}
+
+func (f *Function) fingerprint(b *strings.Builder) {
+	b.WriteString(f.Name)
+	b.WriteString(": func")
+	fingerprintParams(b, f.Params)
+	b.WriteString(" -> ")
+	fingerprintParams(b, f.Results)
+}
+
+// Fingerprint returns a content hash of the canonicalized signatures of the functions and
+// resources in [Interface] i, in the format of [Function.Fingerprint]. It excludes the name
+// of i, and the declaration order of its functions and types, which do not affect the
+// Canonical ABI. Types that are not used by a function are excluded, except resources,
+// whose intrinsics, such as [resource-drop], are imported with the interface.
+func (i *Interface) Fingerprint() string {
+	var lines []string
+	i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+		if _, ok := t.Kind.(*Resource); ok {
+			lines = append(lines, "resource "+typeDefPathName(t))
+		}
+		return true
+	})
+	i.Functions.All()(func(_ string, f *Function) bool {
+		var b strings.Builder
+		f.fingerprint(&b)
+		lines = append(lines, b.String())
+		return true
+	})
+	slices.Sort(lines)
+	return fingerprintOf(strings.Join(lines, "\n"))
+}
+
+func fingerprintOf(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return FingerprintPrefix + hex.EncodeToString(sum[:])
+}
+
+func fingerprintParams(b *strings.Builder, params []Param) {
+	b.WriteByte('(')
+	for i, p := range params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if p.Name != "" {
+			b.WriteString(p.Name)
+			b.WriteString(": ")
+		}
+		fingerprintType(b, p.Type)
+	}
+	b.WriteByte(')')
+}
+
+// fingerprintType writes the canonical structure of t to b.
+// Resources are written by name, so the structure of a valid type is finite.
+func fingerprintType(b *strings.Builder, t Type) {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		if t == nil {
+			b.WriteByte('_')
+		} else {
+			b.WriteString(t.TypeName())
+		}
+		return
+	}
+	switch kind := td.Kind.(type) {
+	case *TypeDef:
+		fingerprintType(b, kind)
+	case *Resource:
+		b.WriteString("resource ")
+		b.WriteString(typeDefPathName(td))
+	case *Own:
+		b.WriteString("own<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case *Borrow:
+		b.WriteString("borrow<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case *Record:
+		b.WriteString("record {")
+		for i, f := range kind.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(f.Name)
+			b.WriteString(": ")
+			fingerprintType(b, f.Type)
+		}
+		b.WriteByte('}')
+	case *Flags:
+		b.WriteString("flags {")
+		for i, f := range kind.Flags {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(f.Name)
+		}
+		b.WriteByte('}')
+	case *Variant:
+		b.WriteString("variant {")
+		for i, c := range kind.Cases {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.Name)
+			if c.Type != nil {
+				b.WriteByte('(')
+				fingerprintType(b, c.Type)
+				b.WriteByte(')')
+			}
+		}
+		b.WriteByte('}')
+	case *Enum:
+		b.WriteString("enum {")
+		for i, c := range kind.Cases {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(c.Name)
+		}
+		b.WriteByte('}')
+	case *Tuple:
+		b.WriteString("tuple<")
+		for i, t := range kind.Types {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fingerprintType(b, t)
+		}
+		b.WriteByte('>')
+	case *Option:
+		b.WriteString("option<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case *Result:
+		b.WriteString("result<")
+		fingerprintType(b, kind.OK)
+		b.WriteString(", ")
+		fingerprintType(b, kind.Err)
+		b.WriteByte('>')
+	case *List:
+		b.WriteString("list<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case *Future:
+		b.WriteString("future<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case *Stream:
+		b.WriteString("stream<")
+		fingerprintType(b, kind.Element)
+		b.WriteString(", ")
+		fingerprintType(b, kind.End)
+		b.WriteByte('>')
+	case *Pointer:
+		b.WriteString("pointer<")
+		fingerprintType(b, kind.Type)
+		b.WriteByte('>')
+	case Type:
+		// Primitive type alias, e.g. type size = u32.
+		fingerprintType(b, kind)
+	default:
+		b.WriteString(kind.WITKind())
+	}
+}
@@ -0,0 +1,106 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFingerprint(t *testing.T) {
+	load := func(t *testing.T, src string) *Interface {
+		t.Helper()
+		res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(src)}}, "wit")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, i := range res.Interfaces {
+			if i.Name != nil && *i.Name == "i" {
+				return i
+			}
+		}
+		t.Fatal("interface i not found")
+		return nil
+	}
+	fingerprint := func(t *testing.T, src, fn string) string {
+		t.Helper()
+		f := load(t, src).Functions.Get(fn)
+		if f == nil {
+			t.Fatalf("function %s not found", fn)
+		}
+		return f.Fingerprint()
+	}
+
+	const base = `package ex:a;
+interface i {
+	resource r;
+	record point { x: u32, y: u32 }
+	f: func(p: point, r: borrow<r>) -> result<list<point>, string>;
+	g: func();
+}
+`
+	want := fingerprint(t, base, "f")
+	if !strings.HasPrefix(want, FingerprintPrefix) || len(want) != len(FingerprintPrefix)+64 {
+		t.Errorf("Fingerprint: %q, expected %s and 64 hex digits", want, FingerprintPrefix)
+	}
+
+	same := []struct {
+		name, src string
+	}{
+		{"docs and gates", `package ex:a;
+interface i {
+	resource r;
+	/// A point.
+	record point { x: u32, y: u32 }
+	/// f does things.
+	@unstable(feature = foo)
+	f: func(p: point, r: borrow<r>) -> result<list<point>, string>;
+}
+`},
+		{"aliases", `package ex:a;
+interface i {
+	resource r;
+	type coord = u32;
+	record pt { x: coord, y: coord }
+	type point = pt;
+	type points = list<point>;
+	f: func(p: point, r: borrow<r>) -> result<points, string>;
+}
+`},
+	}
+	for _, tt := range same {
+		if got := fingerprint(t, tt.src, "f"); got != want {
+			t.Errorf("%s: Fingerprint: %s, expected %s", tt.name, got, want)
+		}
+	}
+
+	different := []struct {
+		name, src string
+	}{
+		{"param name", strings.Replace(base, "p: point", "q: point", 1)},
+		{"field name", strings.Replace(base, "x: u32, y: u32", "x: u32, z: u32", 1)},
+		{"field type", strings.Replace(base, "x: u32, y: u32", "x: u32, y: u64", 1)},
+		{"resource name", strings.NewReplacer(" r;", " s;", "<r>", "<s>").Replace(base)},
+		{"handle", strings.Replace(base, "borrow<r>", "r", 1)},
+	}
+	for _, tt := range different {
+		if got := fingerprint(t, tt.src, "f"); got == want {
+			t.Errorf("%s: Fingerprint: %s, expected a different fingerprint", tt.name, got)
+		}
+	}
+
+	// Interface fingerprints do not depend on declaration order.
+	reordered := `package ex:a;
+interface i {
+	g: func();
+	record point { x: u32, y: u32 }
+	f: func(p: point, r: borrow<r>) -> result<list<point>, string>;
+	resource r;
+}
+`
+	if a, b := load(t, base).Fingerprint(), load(t, reordered).Fingerprint(); a != b {
+		t.Errorf("Interface.Fingerprint: %s, expected %s for reordered interface", b, a)
+	}
+	if a, b := load(t, base).Fingerprint(), load(t, strings.Replace(base, "g: func();", "", 1)).Fingerprint(); a == b {
+		t.Errorf("Interface.Fingerprint: %s for interface without function g, expected a different fingerprint", b)
+	}
+}
@@ -0,0 +1,120 @@
+package wit
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/internal/relpath"
+)
+
+// maxSeedSize is the size of the largest testdata file used to seed a fuzz test.
+// Larger files, such as the WASI corpus, slow the fuzzer to a few executions per second.
+const maxSeedSize = 16 << 10
+
+// FuzzDecodeJSON verifies that DecodeJSON does not panic on malformed input,
+// and that any successfully decoded [Resolve] can be printed as WIT and re-encoded as JSON.
+// The seed corpus is the WIT JSON files in testdata no larger than maxSeedSize.
+// Failing inputs found by go test -fuzz are written to testdata/fuzz/FuzzDecodeJSON
+// and should be committed as regression tests once fixed.
+func FuzzDecodeJSON(f *testing.F) {
+	err := loadTestdata(func(path string, _ *Resolve) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if len(data) <= maxSeedSize {
+			f.Add(data)
+		}
+		return nil
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		res, err := DecodeJSON(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		_ = res.WIT(nil, "")
+		_, _ = res.MarshalJSON()
+	})
+}
+
+// FuzzLoadWIT verifies that LoadFS does not panic on malformed WIT, and that any
+// successfully loaded [Resolve] can be printed as WIT, parsed again, and printed
+// identically. The seed corpus is the WIT files in testdata no larger than maxSeedSize.
+// Failing inputs found by go test -fuzz are written to testdata/fuzz/FuzzLoadWIT
+// and should be committed as regression tests once fixed.
+func FuzzLoadWIT(f *testing.F) {
+	err := relpath.Walk(testdataPath, func(path string) error {
+		if strings.HasSuffix(path, ".golden.wit") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if len(data) <= maxSeedSize {
+			f.Add(data)
+		}
+		return nil
+	}, "*.wit")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		res, err := LoadFS(fstest.MapFS{"fuzz.wit": {Data: data}}, "fuzz.wit")
+		if err != nil {
+			return
+		}
+		want := res.WIT(nil, "")
+		res2, err := LoadFS(splitPackages(want), ".")
+		if err != nil {
+			t.Fatalf("LoadFS printed WIT: %v\n%s", err, want)
+		}
+		if got := res2.WIT(nil, ""); got != want {
+			t.Errorf("WIT after round trip:\n%s\nexpected:\n%s", got, want)
+		}
+	})
+}
+
+func FuzzParseType(f *testing.F) {
+	for _, s := range []string{"bool", "s8", "u64", "f32", "float64", "char", "string", "", "u128"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		typ, err := ParseType(s)
+		if err != nil {
+			return
+		}
+		typ2, err := ParseType(typ.TypeName())
+		if err != nil {
+			t.Fatalf("ParseType(%q): %v", typ.TypeName(), err)
+		}
+		if typ2 != typ {
+			t.Errorf("ParseType(%q): %v, expected %v", typ.TypeName(), typ2, typ)
+		}
+	})
+}
+
+func FuzzParseIdent(f *testing.F) {
+	for _, s := range []string{"wasi:io", "wasi:io/streams", "wasi:io/streams@0.2.0", "wasi:cli/command@0.2.0-rc.1", "a:b@", ":", "@"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		id, err := ParseIdent(s)
+		if err != nil {
+			return
+		}
+		id2, err := ParseIdent(id.String())
+		if err != nil {
+			t.Fatalf("ParseIdent(%q): %v", id.String(), err)
+		}
+		if id2.String() != id.String() {
+			t.Errorf("ParseIdent(%q).String(): %q, expected %q", id.String(), id2.String(), id.String())
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package wit
+
+// HandleError is returned when a [Resolve] uses an [Own] or [Borrow] handle
+// in a position not permitted by the Component Model.
+type HandleError struct {
+	// Path is the name of the function or type that uses the handle,
+	// e.g. "wasi:io/streams#[method]input-stream.read".
+	Path string
+
+	// Reason describes the problem.
+	Reason string
+}
+
+// Error implements the error interface.
+func (err *HandleError) Error() string {
+	return "invalid handle in " + err.Path + ": " + err.Reason
+}
+
+// checkHandleTargets returns a [HandleError] if an [Own] or [Borrow] handle in res
+// does not refer to a [Resource], either directly or through type aliases.
+// A handle that refers to itself, such as type h = own<h>, would otherwise recurse
+// without end when printing WIT or computing ABI layout.
+//
+// It must be called before [Resolve.checkCycles], which would report a handle
+// that refers to itself as a cyclic type definition.
+func (res *Resolve) checkHandleTargets() error {
+	for _, t := range res.TypeDefs {
+		var target *TypeDef
+		switch kind := t.Kind.(type) {
+		case *Own:
+			target = kind.Type
+		case *Borrow:
+			target = kind.Type
+		default:
+			continue
+		}
+		if err := checkHandleTarget(t, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkHandleTarget follows the type aliases from td, the type of handle h,
+// and returns a [HandleError] unless they end in a [Resource].
+func checkHandleTarget(h, td *TypeDef) error {
+	seen := map[*TypeDef]bool{h: true}
+	for {
+		if td == h {
+			return &HandleError{typeDefPathName(h), "handle refers to itself"}
+		}
+		if seen[td] {
+			return &HandleError{typeDefPathName(h), "handle to cyclic type alias " + typeDefPathName(td)}
+		}
+		seen[td] = true
+		switch kind := td.Kind.(type) {
+		case *Resource:
+			return nil
+		case *TypeDef:
+			td = kind
+		default:
+			name := typeDefPathName(td)
+			if td.Name != nil {
+				name += " (" + kind.WITKind() + ")"
+			}
+			return &HandleError{typeDefPathName(h), "handle to non-resource type " + name}
+		}
+	}
+}
+
+// checkHandles returns a [HandleError] if res uses a borrowed or owned
+// handle in a position not permitted by the Component Model:
+//
+//   - A borrowed handle may only appear in function params, not in results,
+//     because a borrow cannot outlive the call that lends it.
+//   - A future or stream may not contain a borrowed handle.
+//   - The self param of a method must be a borrowed handle to its resource,
+//     not an owned handle, which would transfer ownership to the callee.
+//
+// It must be called after [Resolve.checkCycles].
+func (res *Resolve) checkHandles() error {
+	for _, t := range res.TypeDefs {
+		var elem Type
+		switch kind := t.Kind.(type) {
+		case *Future:
+			elem = kind.Type
+		case *Stream:
+			if HasBorrow(kind.End) {
+				elem = kind.End
+			} else {
+				elem = kind.Element
+			}
+		}
+		if elem != nil && HasBorrow(elem) {
+			return &HandleError{typeDefPathName(t), t.Kind.WITKind() + " contains a borrowed handle"}
+		}
+	}
+
+	var err error
+	check := func(prefix string, f *Function) bool {
+		err = checkFunctionHandles(prefix, f)
+		return err == nil
+	}
+	for _, w := range res.Worlds {
+		prefix := w.Name + "#"
+		if w.Package != nil {
+			prefix = worldID(w) + "#"
+		}
+		w.AllFunctions()(func(f *Function) bool {
+			return check(prefix, f)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, i := range res.Interfaces {
+		prefix := interfacePathName(i) + "#"
+		i.Functions.All()(func(_ string, f *Function) bool {
+			return check(prefix, f)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFunctionHandles returns a [HandleError] if f uses a handle
+// in a position not permitted by the Component Model.
+func checkFunctionHandles(prefix string, f *Function) error {
+	for _, r := range f.Results {
+		if HasBorrow(r.Type) {
+			return &HandleError{prefix + f.Name, "result contains a borrowed handle"}
+		}
+	}
+	if kind, ok := f.Kind.(*Method); ok && len(f.Params) > 0 {
+		if h := KindOf[*Own](f.Params[0].Type); h != nil && h.Type == kind.Type {
+			return &HandleError{prefix + f.Name, "method takes an owned handle to self, expected borrow<" + typeName(kind.Type) + ">"}
+		}
+	}
+	return nil
+}
+
+func typeName(t Type) string {
+	if td, ok := t.(*TypeDef); ok && td.Name != nil {
+		return *td.Name
+	}
+	return t.WITKind()
+}
@@ -0,0 +1,132 @@
+package wit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckHandles(t *testing.T) {
+	const types = `
+		{"name": "r", "kind": "resource", "owner": {"interface": 0}},
+		{"name": null, "kind": {"handle": {"borrow": 0}}, "owner": null},
+		{"name": null, "kind": {"handle": {"own": 0}}, "owner": null},
+		{"name": null, "kind": {"option": 1}, "owner": null}`
+	tests := []struct {
+		name      string
+		functions string
+		types     string
+		want      string
+	}{
+		{
+			"valid",
+			`"[constructor]r": {"name": "[constructor]r", "kind": {"constructor": 0}, "params": [], "results": [{"type": 2}]},
+			"[method]r.f": {"name": "[method]r.f", "kind": {"method": 0}, "params": [{"name": "self", "type": 1}, {"name": "other", "type": 3}], "results": [{"type": 2}]},
+			"f": {"name": "f", "kind": "freestanding", "params": [{"name": "a", "type": 1}], "results": []}`,
+			"",
+			"",
+		},
+		{
+			"freestanding function returns borrow",
+			`"f": {"name": "f", "kind": "freestanding", "params": [], "results": [{"type": 1}]}`,
+			"",
+			"invalid handle in foo:bar/a#f: result contains a borrowed handle",
+		},
+		{
+			"method returns option of borrow",
+			`"[method]r.f": {"name": "[method]r.f", "kind": {"method": 0}, "params": [{"name": "self", "type": 1}], "results": [{"type": 3}]}`,
+			"",
+			"invalid handle in foo:bar/a#[method]r.f: result contains a borrowed handle",
+		},
+		{
+			"method takes owned self",
+			`"[method]r.f": {"name": "[method]r.f", "kind": {"method": 0}, "params": [{"name": "self", "type": 2}], "results": []}`,
+			"",
+			"invalid handle in foo:bar/a#[method]r.f: method takes an owned handle to self, expected borrow<r>",
+		},
+		{
+			"future of borrow",
+			"",
+			`,
+			{"name": null, "kind": {"future": 1}, "owner": null}`,
+			"invalid handle in (anonymous future): future contains a borrowed handle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := `{
+				"interfaces": [{"name": "a", "types": {"r": 0}, "functions": {` + tt.functions + `}, "package": 0}],
+				"types": [` + types + tt.types + `],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {}}]
+			}`
+			_, err := DecodeJSON(strings.NewReader(json))
+			checkHandleError(t, err, tt.want)
+		})
+	}
+
+}
+
+func TestCheckHandleTargets(t *testing.T) {
+	tests := []struct {
+		name  string
+		types string
+		want  string
+	}{
+		{
+			"own resource",
+			`{"name": "x", "kind": "resource", "owner": {"interface": 0}},
+			{"name": null, "kind": {"handle": {"own": 0}}, "owner": null}`,
+			"",
+		},
+		{
+			"borrow alias of resource",
+			`{"name": "x", "kind": "resource", "owner": {"interface": 0}},
+			{"name": "y", "kind": {"type": 0}, "owner": {"interface": 0}},
+			{"name": null, "kind": {"handle": {"borrow": 1}}, "owner": null}`,
+			"",
+		},
+		{
+			"own record",
+			`{"name": "x", "kind": {"record": {"fields": [{"name": "f", "type": "u32"}]}}, "owner": {"interface": 0}},
+			{"name": null, "kind": {"handle": {"own": 0}}, "owner": null}`,
+			"invalid handle in (anonymous owned handle): handle to non-resource type foo:bar/a#x (record)",
+		},
+		{
+			"borrow itself via alias",
+			`{"name": "x", "kind": {"handle": {"borrow": 1}}, "owner": {"interface": 0}},
+			{"name": "y", "kind": {"type": 0}, "owner": {"interface": 0}}`,
+			"invalid handle in foo:bar/a#x: handle refers to itself",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := `{
+				"interfaces": [{"name": "a", "types": {}, "functions": {}, "package": 0}],
+				"types": [` + tt.types + `],
+				"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {}}]
+			}`
+			_, err := DecodeJSON(strings.NewReader(json))
+			checkHandleError(t, err, tt.want)
+		})
+	}
+}
+
+func checkHandleError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Errorf("DecodeJSON: unexpected error: %v", err)
+		}
+		return
+	}
+	var herr *HandleError
+	if !errors.As(err, &herr) {
+		t.Errorf("DecodeJSON: got %v, expected a *HandleError", err)
+		return
+	}
+	if got := err.Error(); got != want {
+		t.Errorf("DecodeJSON: got %q, expected %q", got, want)
+	}
+}
@@ -2,9 +2,11 @@ package wit
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/coreos/go-semver/semver"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
 )
 
 // Ident represents a [Component Model] identifier for a [Package], [World], or [Interface],
@@ -51,6 +53,27 @@ func ParseIdent(s string) (Ident, error) {
 	return id, id.Validate()
 }
 
+// ParsePackageName parses a WIT package name with an optional version,
+// e.g. wasi:http or wasi:http@0.2.0, into an [Ident] with an empty Extension.
+// Unlike [ParseIdent], it returns an error if s names an interface or world,
+// e.g. wasi:http/types, or if the namespace or package name is not a valid WIT identifier.
+// Use [Ident.String] and [Ident.UnversionedString] to format the name with or without its version.
+func ParsePackageName(s string) (Ident, error) {
+	id, err := ParseIdent(s)
+	if err != nil {
+		return Ident{}, fmt.Errorf("invalid package name %q: %w", s, err)
+	}
+	if id.Extension != "" {
+		return Ident{}, fmt.Errorf("invalid package name %q: expected namespace:name or namespace:name@version", s)
+	}
+	for _, name := range []string{id.Namespace, id.Package} {
+		if err := validateName(name); err != nil {
+			return Ident{}, fmt.Errorf("invalid package name %q: %w", s, err)
+		}
+	}
+	return id, nil
+}
+
 // Validate validates id, returning any errors.
 func (id *Ident) Validate() error {
 	switch {
@@ -80,3 +103,134 @@ func (id *Ident) UnversionedString() string {
 	}
 	return id.Namespace + ":" + id.Package + "/" + id.Extension
 }
+
+// ID returns the fully-qualified [Ident] of [World] w, e.g. wasi:cli/command@0.2.0.
+func (w *World) ID() Ident {
+	id := w.Package.Name
+	id.Extension = w.Name
+	return id
+}
+
+// ID returns the fully-qualified [Ident] of [Interface] i, e.g. wasi:io/streams@0.2.0.
+// It returns false if i is an anonymous interface declared inline in a [World],
+// or is not in a [Package].
+//
+// The ID of an imported or exported named interface is the Core WebAssembly module name
+// of its functions, e.g. the module name of [method]input-stream.read is wasi:io/streams@0.2.0.
+func (i *Interface) ID() (Ident, bool) {
+	if i.Name == nil || i.Package == nil {
+		return Ident{}, false
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id, true
+}
+
+// InterfaceID returns a deterministic [Ident] for [Interface] i imported or exported by [World] w.
+// If i is a named interface, it returns the ID of i. If i is an anonymous interface declared
+// inline in w, the returned Ident has the package of w and a synthetic extension of the world
+// and item names, e.g. the ID of "import foo: interface { … }" in world a:b/c is a:b/c/foo.
+// An inline export with the same name as an import has the extension world/export/item.
+// It returns false if w does not import or export i.
+//
+// Synthetic IDs are stable across runs, and distinct from the IDs of named interfaces and worlds,
+// as interface and world names cannot contain a slash. They are not valid WIT identifiers.
+func (w *World) InterfaceID(i *Interface) (Ident, bool) {
+	imported, iok := interfaceItemName(&w.Imports, i)
+	exported, eok := interfaceItemName(&w.Exports, i)
+	if !iok && !eok {
+		return Ident{}, false
+	}
+	if id, ok := i.ID(); ok {
+		return id, true
+	}
+	if w.Package == nil {
+		return Ident{}, false
+	}
+	id := w.Package.Name
+	switch _, shadowed := w.Imports.GetOK(exported); {
+	case iok:
+		id.Extension = w.Name + "/" + imported
+	case shadowed:
+		id.Extension = w.Name + "/export/" + exported
+	default:
+		id.Extension = w.Name + "/" + exported
+	}
+	return id, true
+}
+
+// interfaceItemName returns the name of the first item in items that is [Interface] i.
+func interfaceItemName(items *ordered.Map[string, WorldItem], i *Interface) (name string, ok bool) {
+	items.All()(func(k string, item WorldItem) bool {
+		if item == WorldItem(i) {
+			name, ok = k, true
+		}
+		return !ok
+	})
+	return name, ok
+}
+
+// World returns the [World] in r identified by id, e.g. wasi:cli/command@0.2.0.
+// If id has no version, it matches the first world with the same namespace, package, and name.
+// It returns an error if id is invalid or if no world matches.
+func (r *Resolve) World(id string) (*World, error) {
+	match, err := identMatcher(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range r.Worlds {
+		if w.Package != nil && match(w.ID()) {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("world %s not found", id)
+}
+
+// Interface returns the named [Interface] in r identified by id, e.g. wasi:io/streams@0.2.0.
+// If id has no version, it matches the first interface with the same namespace, package, and name.
+// It returns an error if id is invalid or if no interface matches.
+func (r *Resolve) Interface(id string) (*Interface, error) {
+	match, err := identMatcher(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range r.Interfaces {
+		if iid, ok := i.ID(); ok && match(iid) {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("interface %s not found", id)
+}
+
+// TypeDef returns the named [TypeDef] in r identified by id, e.g. wasi:http/types@0.2.0#request-options.
+// The interface is matched as in [Resolve.Interface].
+// It returns an error if id is invalid or if no type matches.
+func (r *Resolve) TypeDef(id string) (*TypeDef, error) {
+	iid, name, ok := strings.Cut(id, "#")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("invalid identifier %q: expected namespace:package/interface#type", id)
+	}
+	i, err := r.Interface(iid)
+	if err != nil {
+		return nil, err
+	}
+	t := i.TypeDefs.Get(name)
+	if t == nil {
+		return nil, fmt.Errorf("type %s not found", id)
+	}
+	return t, nil
+}
+
+func identMatcher(s string) (func(Ident) bool, error) {
+	id, err := ParseIdent(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identifier %q: %w", s, err)
+	}
+	if id.Extension == "" {
+		return nil, fmt.Errorf("invalid identifier %q: expected namespace:package/name", s)
+	}
+	if id.Version == nil {
+		return func(x Ident) bool { return x.UnversionedString() == s }, nil
+	}
+	return func(x Ident) bool { return x.String() == s }, nil
+}
@@ -3,8 +3,10 @@ package wit
 import (
 	"reflect"
 	"testing"
+	"testing/fstest"
 
 	"github.com/coreos/go-semver/semver"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
 )
 
 func TestIdent(t *testing.T) {
@@ -46,3 +48,145 @@ func TestIdent(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePackageName(t *testing.T) {
+	tests := []struct {
+		s           string
+		want        Ident
+		unversioned string
+		wantErr     bool
+	}{
+		{"wasi:http", Ident{Namespace: "wasi", Package: "http"}, "wasi:http", false},
+		{"wasi:http@0.2.0", Ident{Namespace: "wasi", Package: "http", Version: semver.New("0.2.0")}, "wasi:http", false},
+		{"my-org:my-pkg@1.0.0-rc.1", Ident{Namespace: "my-org", Package: "my-pkg", Version: semver.New("1.0.0-rc.1")}, "my-org:my-pkg", false},
+		{"WASI:HTTP", Ident{Namespace: "WASI", Package: "HTTP"}, "WASI:HTTP", false},
+
+		// Errors
+		{"", Ident{}, "", true},
+		{"wasi", Ident{}, "", true},
+		{"wasi:http/types", Ident{}, "", true},
+		{"wasi:http/types@0.2.0", Ident{}, "", true},
+		{"wasi:http@", Ident{}, "", true},
+		{"wasi:Http", Ident{}, "", true},
+		{"wasi:http_client", Ident{}, "", true},
+		{"wasi:2http", Ident{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParsePackageName(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePackageName(%q): expected error, got %v", tt.s, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePackageName(%q): %v", tt.s, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePackageName(%q): %v, expected %v", tt.s, got, tt.want)
+			}
+			if s := got.String(); s != tt.s {
+				t.Errorf("String(): %q, expected %q", s, tt.s)
+			}
+			if s := got.UnversionedString(); s != tt.unversioned {
+				t.Errorf("UnversionedString(): %q, expected %q", s, tt.unversioned)
+			}
+		})
+	}
+}
+
+func TestResolveIDs(t *testing.T) {
+	res, err := LoadJSON("../testdata/wasi/0.2.0/cli.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := res.World("wasi:cli/command@0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.ID(), "wasi:cli/command@0.2.0"; got.String() != want {
+		t.Errorf("World.ID(): %s, expected %s", got.String(), want)
+	}
+	if w2, err := res.World("wasi:cli/command"); err != nil || w2 != w {
+		t.Errorf("Resolve.World(%q): %v, %v, expected %v", "wasi:cli/command", w2, err, w)
+	}
+
+	i, err := res.Interface("wasi:io/streams@0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, ok := i.ID()
+	if got, want := id.String(), "wasi:io/streams@0.2.0"; !ok || got != want {
+		t.Errorf("Interface.ID(): %s, %t, expected %s", got, ok, want)
+	}
+
+	for _, s := range []string{"wasi:io/streams@0.2.1", "wasi:io", "wasi", "wasi:cli/command@0.2.0"} {
+		if _, err := res.Interface(s); err == nil {
+			t.Errorf("Resolve.Interface(%q): expected error", s)
+		}
+	}
+	if _, err := res.World("wasi:io/streams@0.2.0"); err == nil {
+		t.Errorf("Resolve.World(%q): expected error", "wasi:io/streams@0.2.0")
+	}
+
+	name := "anonymous"
+	if _, ok := (&Interface{Package: w.Package}).ID(); ok {
+		t.Errorf("Interface.ID(): expected false for an anonymous interface")
+	}
+	if _, ok := (&Interface{Name: &name}).ID(); ok {
+		t.Errorf("Interface.ID(): expected false for an interface without a package")
+	}
+}
+
+func TestWorldInterfaceID(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wit/world.wit": {Data: []byte(`package ex:pkg@0.1.0;
+
+interface named {
+	f: func();
+}
+
+world w {
+	import named;
+	import foo: interface {
+		f: func();
+	}
+	export foo: interface {
+		f: func();
+	}
+	export bar: interface {
+		f: func();
+	}
+}
+`)},
+	}
+	res, err := LoadFS(fsys, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := res.World("ex:pkg/w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+		items.All()(func(_ string, item WorldItem) bool {
+			id, ok := w.InterfaceID(item.(*Interface))
+			if !ok {
+				t.Errorf("World.InterfaceID: expected true for %v", item)
+			}
+			got = append(got, id.String())
+			return true
+		})
+	}
+	want := []string{"ex:pkg/named@0.1.0", "ex:pkg/w/foo@0.1.0", "ex:pkg/w/export/foo@0.1.0", "ex:pkg/w/bar@0.1.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("World.InterfaceID: %v, expected %v", got, want)
+	}
+
+	if _, ok := w.InterfaceID(&Interface{Package: w.Package}); ok {
+		t.Errorf("World.InterfaceID: expected false for an interface not in the world")
+	}
+}
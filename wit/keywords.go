@@ -0,0 +1,60 @@
+package wit
+
+// keywords are the WIT keywords, which must be %-escaped to be used as identifiers.
+// The parser rejects a keyword where an identifier is expected, the printer escapes
+// identifiers that are keywords, and [Resolve.Rename] rejects escaped names.
+// It matches the keywords recognized by wasm-tools 1.230.
+var keywords = map[string]bool{
+	"as":            true,
+	"async":         true,
+	"bool":          true,
+	"borrow":        true,
+	"char":          true,
+	"constructor":   true,
+	"enum":          true,
+	"error-context": true,
+	"export":        true,
+	"f32":           true,
+	"f64":           true,
+	"flags":         true,
+	"float32":       true,
+	"float64":       true,
+	"from":          true,
+	"func":          true,
+	"future":        true,
+	"import":        true,
+	"include":       true,
+	"interface":     true,
+	"list":          true,
+	"option":        true,
+	"own":           true,
+	"package":       true,
+	"record":        true,
+	"resource":      true,
+	"result":        true,
+	"s16":           true,
+	"s32":           true,
+	"s64":           true,
+	"s8":            true,
+	"static":        true,
+	"stream":        true,
+	"string":        true,
+	"tuple":         true,
+	"type":          true,
+	"u16":           true,
+	"u32":           true,
+	"u64":           true,
+	"u8":            true,
+	"use":           true,
+	"variant":       true,
+	"with":          true,
+	"world":         true,
+}
+
+// escape returns name, prefixed with % if it is a WIT keyword.
+func escape(name string) string {
+	if keywords[name] {
+		return "%" + name
+	}
+	return name
+}
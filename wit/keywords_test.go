@@ -0,0 +1,87 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// keywordWIT returns a WIT package that uses %-escaped keyword kw as an identifier
+// everywhere an identifier can appear.
+func keywordWIT(kw string) string {
+	return strings.ReplaceAll(`package %kw:%kw;
+
+interface %kw {
+	record %kw {
+		%kw: u32,
+	}
+	variant v {
+		%kw(u32),
+	}
+	enum e {
+		%kw,
+	}
+	flags f {
+		%kw,
+	}
+	resource r {
+		constructor(%kw: u32);
+		%kw: func(%kw: u32);
+	}
+	resource s {
+		%kw: static func() -> %kw;
+	}
+	g: func(%kw: %kw) -> %kw;
+}
+
+interface i {
+	use %kw.{%kw as t};
+	type %kw = t;
+}
+
+world w {
+	import %kw;
+	export %kw: func(%kw: u32);
+}
+`, "kw", kw)
+}
+
+func TestKeywordsRoundTrip(t *testing.T) {
+	for kw := range keywords {
+		t.Run(kw, func(t *testing.T) {
+			src := keywordWIT(kw)
+			res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(src)}}, "wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := res.WIT(nil, "")
+			if want, got := strings.Count(src, "%"+kw), strings.Count(got, "%"+kw); got != want {
+				t.Errorf("WIT has %d escaped %%%s, expected %d", got, kw, want)
+			}
+			res2, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(got)}}, "wit")
+			if err != nil {
+				t.Fatalf("LoadFS(WIT): %v\n%s", err, got)
+			}
+			if got2 := res2.WIT(nil, ""); got2 != got {
+				t.Errorf("WIT:\n%s\nexpected:\n%s", got2, got)
+			}
+		})
+	}
+}
+
+func TestRenameEscapedKeyword(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte("package a:b;\ninterface i {\n\ttype t = u32;\n}\n")}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := res.TypeDefs[0]
+	if err := res.Rename(td, "%type"); err == nil || !strings.Contains(err.Error(), `use "type"`) {
+		t.Errorf("Rename(%%type): %v, expected an error", err)
+	}
+	if err := res.Rename(td, "type"); err != nil {
+		t.Fatalf("Rename(type): %v", err)
+	}
+	if got := res.WIT(nil, ""); !strings.Contains(got, "type %type = u32;") {
+		t.Errorf("WIT does not contain escaped type %%type:\n%s", got)
+	}
+}
@@ -2,9 +2,14 @@ package wit
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"os/exec"
+	"path"
 	"strings"
 )
 
@@ -36,25 +41,149 @@ func LoadWIT(path string) (*Resolve, error) {
 		return nil, err
 	}
 
-	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
 	cmd := exec.Command(wasmTools, "component", "wit", "-j")
-	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if path == "" || path == "-" {
 		cmd.Stdin = os.Stdin
 	} else {
 		cmd.Args = append(cmd.Args, path)
 	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("%s\n", strings.Join(cmd.Args, " "))
+	slog.Debug("running wasm-tools", "command", strings.Join(cmd.Args, " "))
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
 
-	err = cmd.Run()
+	// Decode the output as wasm-tools writes it, rather than buffering it.
+	// Drain stdout before waiting, so wasm-tools does not block on a full pipe.
+	res, decodeErr := DecodeJSON(stdout)
+	io.Copy(io.Discard, stdout)
+	err = cmd.Wait()
 	if err != nil {
 		fmt.Fprint(os.Stderr, stderr.String())
 		return nil, err
 	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return res, nil
+}
+
+// LoadFS loads [WIT] from name in fsys without using wasm-tools, so WIT embedded
+// with go:embed or stored in a virtual filesystem can be loaded without touching the OS.
+//
+// If name is a directory, the .wit files in it form the root package, and each entry in
+// its deps directory is a dependency: either a directory of .wit files, or a single .wit file.
+// Otherwise name must be a .wit file. A file may declare nested packages with package { ... }.
+// References between packages are resolved as wasm-tools resolves them, and items gated
+// with @unstable are included regardless of feature.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func LoadFS(fsys fs.FS, name string) (*Resolve, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		root, err := parseFSFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		return resolveWIT(nil, root)
+	}
 
-	return DecodeJSON(&stdout)
+	root, err := parseFSDir(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var deps []*astPackage
+	entries, err := fs.ReadDir(fsys, path.Join(name, "deps"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		var pkgs []*astPackage
+		depName := path.Join(name, "deps", e.Name())
+		switch {
+		case e.IsDir():
+			pkgs, err = parseFSDir(fsys, depName)
+		case strings.HasSuffix(e.Name(), ".wit"):
+			pkgs, err = parseFSFile(fsys, depName)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, pkgs...)
+	}
+	return resolveWIT(deps, root)
+}
+
+// parseFSFile parses a WIT file. It returns the package declared by the file,
+// followed by any nested packages. A file that only declares nested packages
+// returns them in order, the last of which is considered the file's package.
+func parseFSFile(fsys fs.FS, name string) ([]*astPackage, error) {
+	src, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := parseWIT(name, src)
+	if err != nil {
+		return nil, err
+	}
+	if top := pkgs[0]; len(pkgs) > 1 && top.name == nil && top.files[0].empty() {
+		pkgs = append(pkgs[len(pkgs)-1:], pkgs[1:len(pkgs)-1]...)
+	}
+	return pkgs, nil
+}
+
+// parseFSDir parses the .wit files in dir as a single package, followed by any
+// nested packages declared in them.
+func parseFSDir(fsys fs.FS, dir string) ([]*astPackage, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &astPackage{}
+	var nested []*astPackage
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".wit") {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pkgs, err := parseWIT(path.Join(dir, e.Name()), src)
+		if err != nil {
+			return nil, err
+		}
+		top := pkgs[0]
+		if top.name != nil {
+			if pkg.name != nil && pkg.name.String() != top.name.String() {
+				return nil, fmt.Errorf("%s: package %s conflicts with package %s in the same directory",
+					top.files[0].path, top.name.String(), pkg.name.String())
+			}
+			pkg.name = top.name
+		}
+		if pkg.docs.Contents == "" {
+			pkg.docs.Contents = top.docs.Contents
+		}
+		pkg.docs.merge(top.docs.Comments)
+		pkg.files = append(pkg.files, top.files...)
+		nested = append(nested, pkgs[1:]...)
+	}
+	if len(pkg.files) == 0 {
+		return nil, fmt.Errorf("%s: no WIT files found", dir)
+	}
+	return append([]*astPackage{pkg}, nested...), nil
 }
@@ -0,0 +1,416 @@
+package wit
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/internal/relpath"
+)
+
+// TestLoadFSGolden parses each golden WIT file, which contains one or more packages,
+// laid out as a root package with deps, and verifies that printing the result
+// reproduces the golden file.
+func TestLoadFSGolden(t *testing.T) {
+	err := relpath.Walk(testdataPath, func(path string) error {
+		t.Run(path, func(t *testing.T) {
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fsys := splitPackages(string(golden))
+			res, err := LoadFS(fsys, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := res.Verify(); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			if depsOrder[filepath.Base(path)] {
+				return
+			}
+			if got := res.WIT(nil, ""); got != string(golden) {
+				t.Errorf("WIT:\n%s\nexpected:\n%s", got, golden)
+			}
+		})
+		return nil
+	}, "*.golden.wit")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// depsOrder lists golden files generated by versions of wasm-tools
+// that did not order dependencies by package name.
+var depsOrder = map[string]bool{
+	"kinds-of-deps.wit.json.golden.wit": true,
+}
+
+// splitPackages splits WIT text declaring multiple packages into a filesystem with
+// the last package in root.wit, and each preceding package in a file in deps.
+func splitPackages(s string) fstest.MapFS {
+	lines := strings.SplitAfter(s, "\n")
+	var starts []int
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "package ") {
+			continue
+		}
+		// Doc comments precede the package declaration.
+		for i > 0 && strings.HasPrefix(lines[i-1], "//") {
+			i--
+		}
+		starts = append(starts, i)
+	}
+	fsys := make(fstest.MapFS)
+	for n, start := range starts {
+		end := len(lines)
+		if n+1 < len(starts) {
+			end = starts[n+1]
+		}
+		name := "root.wit"
+		if n+1 < len(starts) {
+			name = "deps/" + strconv.Itoa(n) + ".wit"
+		}
+		fsys[name] = &fstest.MapFile{Data: []byte(strings.Join(lines[start:end], ""))}
+	}
+	return fsys
+}
+
+// staleSources lists WIT sources in testdata that were edited after
+// their JSON was generated.
+var staleSources = map[string]bool{
+	"escaped-names.wit": true,
+	"use-of-export.wit": true,
+	"use-of-import.wit": true,
+}
+
+// TestLoadFSSources loads the WIT sources in testdata and compares them
+// to the golden files generated from wasm-tools JSON.
+func TestLoadFSSources(t *testing.T) {
+	err := relpath.Walk(testdataPath, func(path string) error {
+		if strings.HasSuffix(path, ".golden.wit") {
+			return nil
+		}
+		t.Run(path, func(t *testing.T) {
+			res, err := LoadFS(os.DirFS(testdataPath), filepath.ToSlash(relpath.Rel(testdataPath, path)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if staleSources[filepath.Base(path)] {
+				return
+			}
+			golden, err := os.ReadFile(path + ".json.golden.wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.WIT(nil, ""); got != string(golden) {
+				t.Errorf("WIT:\n%s\nexpected:\n%s", got, golden)
+			}
+		})
+		return nil
+	}, "*.wit")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"wit/types.wit": {Data: []byte(`// The root package.
+package test:root@1.0.0;
+
+use wasi:io/streams@0.2.0 as s;
+
+interface types {
+	use s.{input-stream, stream-error as serr};
+	/// A pair.
+	record pair { a: option<u32>, b: list<input-stream> }
+	type bytes = list<u8>;
+	get: func(p: pair) -> result<bytes, serr>;
+}
+`)},
+		"wit/world.wit": {Data: []byte(`package test:root@1.0.0;
+
+world base {
+	export run: func();
+	import log: func(msg: string);
+	export types;
+}
+
+world full {
+	include base with { log as print }
+	resource thing {
+		constructor();
+		get: static func() -> thing;
+	}
+}
+`)},
+		"wit/deps/io/error.wit": {Data: []byte(`package wasi:io@0.2.0;
+
+interface error {
+	/** An error. */
+	resource error;
+}
+`)},
+		"wit/deps/io/streams.wit": {Data: []byte(`package wasi:io@0.2.0;
+
+interface streams {
+	use error.{error};
+	variant stream-error {
+		last-operation-failed(error),
+		closed,
+	}
+	resource input-stream {
+		read: func(len: u64) -> result<list<u8>, stream-error>;
+	}
+}
+`)},
+		"wit/deps/README.md": {Data: []byte(`Not WIT.`)},
+	}
+	res, err := LoadFS(fsys, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Verify(); err != nil {
+		t.Error(err)
+	}
+	want := `package wasi:io@0.2.0;
+
+interface error {
+	/// An error.
+	resource error;
+}
+
+interface streams {
+	use error.{error};
+	variant stream-error {
+		last-operation-failed(error),
+		closed,
+	}
+	resource input-stream {
+		read: func(len: u64) -> result<list<u8>, stream-error>;
+	}
+}
+
+
+/// The root package.
+package test:root@1.0.0;
+
+interface types {
+	use wasi:io/streams@0.2.0.{input-stream};
+	use wasi:io/streams@0.2.0.{stream-error as serr};
+
+	/// A pair.
+	record pair {
+		a: option<u32>,
+		b: list<input-stream>,
+	}
+	type bytes = list<u8>;
+	get: func(p: pair) -> result<bytes, serr>;
+}
+
+world base {
+	import wasi:io/error@0.2.0;
+	import wasi:io/streams@0.2.0;
+	import log: func(msg: string);
+	export run: func();
+	export types;
+}
+world full {
+	import wasi:io/error@0.2.0;
+	import wasi:io/streams@0.2.0;
+	resource thing {
+		constructor();
+		get: static func() -> thing;
+	}
+	import print: func(msg: string);
+	export run: func();
+	export types;
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestLoadFSErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"syntax", "package a:b;\ninterface i {\n\tf: func(;\n}\n", "a.wit:3: expected an identifier, found ';'"},
+		{"keyword", "package a:b;\ninterface type {}\n", "a.wit:2: expected an identifier, found keyword type"},
+		{"no package", "interface i {}\n", "a.wit: no package declaration"},
+		{"unknown type", "package a:b;\ninterface i {\n\tf: func() -> t;\n}\n", "a.wit:3: type t does not exist"},
+		{"cycle", "package a:b;\ninterface i {\n\ttype a = list<b>;\n\ttype b = list<a>;\n}\n", "type a depends on itself"},
+		{"not a resource", "package a:b;\ninterface i {\n\ttype r = u32;\n\ttype t = borrow<r>;\n}\n", "a.wit:4: type r is not a resource"},
+		{"unknown package", "package a:b;\ninterface i {\n\tuse c:d/e.{f};\n}\n", "a.wit:3: package c:d not found"},
+		{"async", "package a:b;\ninterface i {\n\tf: async func();\n}\n", "a.wit:3: async functions are not supported"},
+		{"include", "package a:b;\nworld w {\n\tinclude v;\n}\nworld v {\n\timport f: func();\n\timport g: func();\n}\nworld x {\n\tinclude v;\n\tinclude w;\n}\n", "a.wit:11: import of f from world w conflicts with import of f from world v included at a.wit:10; rename it with include w with { f as ... }"},
+		{"include declared", "package a:b;\nworld v {\n\texport run: func();\n}\nworld w {\n\texport run: func();\n\tinclude v;\n}\n", "a.wit:7: export of run from world v conflicts with export run declared at a.wit:6; rename it with include v with { run as ... }"},
+		{"include with", "package a:b;\nworld v {\n\timport f: func();\n\timport g: func();\n}\nworld w {\n\timport h: func();\n\tinclude v with { g as h }\n}\n", "a.wit:8: import of h from world v conflicts with import h declared at a.wit:7; rename it with include v with { g as ... }"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"a.wit": {Data: []byte(tt.src)}}
+			_, err := LoadFS(fsys, "a.wit")
+			if err == nil {
+				t.Fatal("LoadFS: expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFS: %q, expected an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocContents(t *testing.T) {
+	tests := []struct {
+		comments []string
+		want     string
+	}{
+		{[]string{"/// a", "///   b"}, "a\n  b"},
+		{[]string{"// a", "/// b"}, "a\nb"},
+		{[]string{"/** a\n * b\n */"}, "a\n * b"},
+		{[]string{"///"}, ""},
+	}
+	for _, tt := range tests {
+		if got := docContents(tt.comments); got != tt.want {
+			t.Errorf("docContents(%q): %q, expected %q", tt.comments, got, tt.want)
+		}
+	}
+}
+
+func TestLoadFSComments(t *testing.T) {
+	tests := []struct {
+		name string
+		wit  string
+		want string // if empty, wit
+	}{
+		{
+			"attached",
+			`// Copyright header.
+
+/// Package docs.
+package a:comments; // trailing package
+
+interface j {
+	type u = u8;
+}
+
+// Leading interface comment.
+
+/// Interface docs.
+interface i {
+	use j.{u};
+
+	/// Record docs.
+	record r {
+		a: u32, // trailing field
+		b: string,
+
+		// end of record
+	}
+	enum e {
+		x, // trailing case
+		y // last case
+	}
+	resource res {
+		get: func() -> u32; // trailing method
+
+		// end of resource
+	}
+	type t = u32; // trailing type
+
+	// Leading function comment.
+
+	f: func(); // trailing function
+
+	// end of interface
+} // trailing interface
+
+world w {
+	// Leading import comment.
+
+	import f: func(); // trailing import
+
+	// end of world
+}
+
+// end of file
+`,
+			"",
+		},
+		{
+			"carried",
+			`package a:comments;
+
+interface j {
+	type u = u8;
+}
+
+interface i { // after brace
+	// Comment on a use statement.
+	use j.{u}; // trailing use
+	f: func(a: u32 /* in params */);
+}
+
+world w {
+	import i; // trailing import
+}
+`,
+			`package a:comments;
+
+interface j {
+	type u = u8;
+}
+
+interface i {
+	use j.{u};
+
+	// after brace
+	// Comment on a use statement.
+	// trailing use
+
+	f: func(a: u32);
+
+	/* in params */
+}
+
+world w {
+	import j;
+	import i;
+
+	// trailing import
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == "" {
+				want = tt.wit
+			}
+			res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(tt.wit)}}, "wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := res.WIT(nil, "")
+			if got != want {
+				t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+			}
+			res2, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(got)}}, "wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got2 := res2.WIT(nil, ""); got2 != got {
+				t.Errorf("WIT after a round trip:\n%s\nexpected:\n%s", got2, got)
+			}
+		})
+	}
+}
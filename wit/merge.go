@@ -0,0 +1,270 @@
+package wit
+
+import (
+	"fmt"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Merge merges the packages, worlds, interfaces, and types in other into res.
+// This is used to combine WIT from multiple sources, such as separately
+// resolved packages with shared dependencies.
+//
+// A [Package] in other with the same name as a package in res is unified with it:
+// interfaces, worlds, and types with matching names are assumed to be identical,
+// and references to them are rewritten to point to their counterparts in res.
+// Definitions not present in res are moved into the matching package.
+//
+// Merge modifies the definitions in other, which must not be used after Merge returns.
+func (res *Resolve) Merge(other *Resolve) error {
+	m := &merger{
+		packages:   make(map[*Package]*Package),
+		interfaces: make(map[*Interface]*Interface),
+		worlds:     make(map[*World]*World),
+		typeDefs:   make(map[*TypeDef]*TypeDef),
+	}
+
+	packages := make(map[string]*Package, len(res.Packages))
+	for _, pkg := range res.Packages {
+		packages[pkg.Name.String()] = pkg
+	}
+
+	// Find duplicate definitions
+	for _, pkg := range other.Packages {
+		p, ok := packages[pkg.Name.String()]
+		if !ok {
+			continue
+		}
+		m.packages[pkg] = p
+		var err error
+		pkg.Interfaces.All()(func(name string, i *Interface) bool {
+			if pi, ok := p.Interfaces.GetOK(name); ok {
+				err = m.mergeInterface(i, pi)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		pkg.Worlds.All()(func(name string, w *World) bool {
+			if pw, ok := p.Worlds.GetOK(name); ok {
+				err = m.mergeWorld(w, pw)
+			}
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Move non-duplicate definitions into res, rewriting references
+	for _, t := range other.TypeDefs {
+		if _, ok := m.typeDefs[t]; ok {
+			continue
+		}
+		m.rewriteTypeDef(t)
+		res.TypeDefs = append(res.TypeDefs, t)
+	}
+	for _, i := range other.Interfaces {
+		if _, ok := m.interfaces[i]; ok {
+			continue
+		}
+		m.rewriteInterface(i)
+		if p, ok := m.packages[i.Package]; ok && i.Name != nil {
+			p.Interfaces.Set(*i.Name, i)
+		}
+		i.Package = m.pkg(i.Package)
+		res.Interfaces = append(res.Interfaces, i)
+	}
+	for _, w := range other.Worlds {
+		if _, ok := m.worlds[w]; ok {
+			continue
+		}
+		m.rewriteWorld(w)
+		if p, ok := m.packages[w.Package]; ok {
+			p.Worlds.Set(w.Name, w)
+		}
+		w.Package = m.pkg(w.Package)
+		res.Worlds = append(res.Worlds, w)
+	}
+	for _, pkg := range other.Packages {
+		if _, ok := m.packages[pkg]; ok {
+			continue
+		}
+		res.Packages = append(res.Packages, pkg)
+	}
+
+	return nil
+}
+
+// merger maps definitions in a [Resolve] being merged to their counterparts in the destination.
+type merger struct {
+	packages   map[*Package]*Package
+	interfaces map[*Interface]*Interface
+	worlds     map[*World]*World
+	typeDefs   map[*TypeDef]*TypeDef
+}
+
+func (m *merger) mergeInterface(i, into *Interface) error {
+	m.interfaces[i] = into
+	var err error
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		pt, ok := into.TypeDefs.GetOK(name)
+		if !ok {
+			err = fmt.Errorf("cannot merge interface %s: type %s not found", interfacePathName(into), name)
+			return false
+		}
+		m.typeDefs[t] = pt
+		return true
+	})
+	return err
+}
+
+func (m *merger) mergeWorld(w, into *World) error {
+	m.worlds[w] = into
+	var err error
+	merge := func(intoItems *ordered.Map[string, WorldItem]) func(name string, item WorldItem) bool {
+		return func(name string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *Interface:
+				if item.Name != nil {
+					return true // named interfaces are merged via their package
+				}
+				pi, ok := intoItems.Get(name).(*Interface)
+				if !ok {
+					err = fmt.Errorf("cannot merge world %s: interface %s not found", w.Name, name)
+					return false
+				}
+				err = m.mergeInterface(item, pi)
+			case *TypeDef:
+				pt, ok := intoItems.Get(name).(*TypeDef)
+				if !ok {
+					err = fmt.Errorf("cannot merge world %s: type %s not found", w.Name, name)
+					return false
+				}
+				m.typeDefs[item] = pt
+			}
+			return err == nil
+		}
+	}
+	w.Imports.All()(merge(&into.Imports))
+	if err != nil {
+		return err
+	}
+	w.Exports.All()(merge(&into.Exports))
+	return err
+}
+
+func (m *merger) pkg(p *Package) *Package {
+	if x, ok := m.packages[p]; ok {
+		return x
+	}
+	return p
+}
+
+func (m *merger) iface(i *Interface) *Interface {
+	if x, ok := m.interfaces[i]; ok {
+		return x
+	}
+	return i
+}
+
+func (m *merger) typeDef(t *TypeDef) *TypeDef {
+	if x, ok := m.typeDefs[t]; ok {
+		return x
+	}
+	return t
+}
+
+func (m *merger) typ(t Type) Type {
+	if t, ok := t.(*TypeDef); ok {
+		return m.typeDef(t)
+	}
+	return t
+}
+
+func (m *merger) rewriteTypeDef(t *TypeDef) {
+	switch owner := t.Owner.(type) {
+	case *Interface:
+		t.Owner = m.iface(owner)
+	case *World:
+		if w, ok := m.worlds[owner]; ok {
+			t.Owner = w
+		}
+	}
+
+	switch kind := t.Kind.(type) {
+	case *TypeDef:
+		t.Kind = m.typeDef(kind)
+	case *Record:
+		for i := range kind.Fields {
+			kind.Fields[i].Type = m.typ(kind.Fields[i].Type)
+		}
+	case *Own:
+		kind.Type = m.typeDef(kind.Type)
+	case *Borrow:
+		kind.Type = m.typeDef(kind.Type)
+	case *Tuple:
+		for i := range kind.Types {
+			kind.Types[i] = m.typ(kind.Types[i])
+		}
+	case *Variant:
+		for i := range kind.Cases {
+			kind.Cases[i].Type = m.typ(kind.Cases[i].Type)
+		}
+	case *Option:
+		kind.Type = m.typ(kind.Type)
+	case *Result:
+		kind.OK = m.typ(kind.OK)
+		kind.Err = m.typ(kind.Err)
+	case *List:
+		kind.Type = m.typ(kind.Type)
+	case *Future:
+		kind.Type = m.typ(kind.Type)
+	case *Stream:
+		kind.Element = m.typ(kind.Element)
+		kind.End = m.typ(kind.End)
+	}
+}
+
+func (m *merger) rewriteInterface(i *Interface) {
+	i.Functions.All()(func(_ string, f *Function) bool {
+		m.rewriteFunction(f)
+		return true
+	})
+}
+
+func (m *merger) rewriteWorld(w *World) {
+	rewrite := func(items *ordered.Map[string, WorldItem]) {
+		items.All()(func(name string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *Interface:
+				items.Set(name, m.iface(item))
+			case *TypeDef:
+				items.Set(name, m.typeDef(item))
+			case *Function:
+				m.rewriteFunction(item)
+			}
+			return true
+		})
+	}
+	rewrite(&w.Imports)
+	rewrite(&w.Exports)
+}
+
+func (m *merger) rewriteFunction(f *Function) {
+	switch kind := f.Kind.(type) {
+	case *Method:
+		kind.Type = m.typ(kind.Type)
+	case *Static:
+		kind.Type = m.typ(kind.Type)
+	case *Constructor:
+		kind.Type = m.typ(kind.Type)
+	}
+	for i := range f.Params {
+		f.Params[i].Type = m.typ(f.Params[i].Type)
+	}
+	for i := range f.Results {
+		f.Results[i].Type = m.typ(f.Results[i].Type)
+	}
+}
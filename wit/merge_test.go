@@ -0,0 +1,171 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(`{
+		"worlds": [],
+		"interfaces": [{"name": "i", "types": {"x": 0}, "functions": {}, "package": 0}],
+		"types": [{"name": "x", "kind": {"type": "u32"}, "owner": {"interface": 0}}],
+		"packages": [{"name": "foo:a", "interfaces": {"i": 0}, "worlds": {}}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := DecodeJSON(strings.NewReader(`{
+		"worlds": [],
+		"interfaces": [
+			{"name": "i", "types": {"x": 0}, "functions": {}, "package": 0},
+			{"name": "j", "types": {"x": 1}, "functions": {"f": {"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": 1}], "results": []}}, "package": 0},
+			{"name": "k", "types": {"x": 2}, "functions": {}, "package": 1}
+		],
+		"types": [
+			{"name": "x", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+			{"name": "x", "kind": {"type": 0}, "owner": {"interface": 1}},
+			{"name": "x", "kind": {"type": 0}, "owner": {"interface": 2}}
+		],
+		"packages": [
+			{"name": "foo:a", "interfaces": {"i": 0, "j": 1}, "worlds": {}},
+			{"name": "foo:b", "interfaces": {"k": 2}, "worlds": {}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := res.TypeDefs[0]
+
+	err = res.Merge(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(res.Packages), 2; got != want {
+		t.Errorf("len(Packages): %d, expected %d", got, want)
+	}
+	if got, want := len(res.Interfaces), 3; got != want {
+		t.Errorf("len(Interfaces): %d, expected %d", got, want)
+	}
+	if got, want := len(res.TypeDefs), 3; got != want {
+		t.Errorf("len(TypeDefs): %d, expected %d", got, want)
+	}
+	pkg := res.Packages[0]
+	j := pkg.Interfaces.Get("j")
+	if j == nil {
+		t.Fatalf("interface j not merged into package %s", pkg.Name.String())
+	}
+	if j.Package != pkg {
+		t.Errorf("j.Package: %p, expected %p", j.Package, pkg)
+	}
+	if got := j.TypeDefs.Get("x").Kind; got != x {
+		t.Errorf("j.x: %v, expected %v", got, x)
+	}
+	if got := j.Functions.Get("f").Params[0].Type; got != j.TypeDefs.Get("x") {
+		t.Errorf("j.f param: %v, expected %v", got, j.TypeDefs.Get("x"))
+	}
+	if got := res.Packages[1].Interfaces.Get("k").TypeDefs.Get("x").Kind; got != x {
+		t.Errorf("k.x: %v, expected %v", got, x)
+	}
+
+	verifyMerged(t, res)
+}
+
+func TestMergeIdentical(t *testing.T) {
+	path := testdataPath + "/wasi/0.2.0/cli.wit.json"
+	res, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := res.WIT(nil, "")
+	packages, interfaces, worlds, typeDefs := len(res.Packages), len(res.Interfaces), len(res.Worlds), len(res.TypeDefs)
+
+	err = res.Merge(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(res.Packages); got != packages {
+		t.Errorf("len(Packages): %d, expected %d", got, packages)
+	}
+	if got := len(res.Interfaces); got != interfaces {
+		t.Errorf("len(Interfaces): %d, expected %d", got, interfaces)
+	}
+	if got := len(res.Worlds); got != worlds {
+		t.Errorf("len(Worlds): %d, expected %d", got, worlds)
+	}
+	// Anonymous types are not deduplicated, so only check named types.
+	if got := len(res.TypeDefs); got < typeDefs {
+		t.Errorf("len(TypeDefs): %d, expected at least %d", got, typeDefs)
+	}
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT output changed after merging an identical Resolve")
+	}
+
+	verifyMerged(t, res)
+}
+
+// verifyMerged verifies that every definition reachable from res is contained in res.
+func verifyMerged(t *testing.T, res *Resolve) {
+	if err := res.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	interfaces := make(map[*Interface]bool)
+	for _, i := range res.Interfaces {
+		interfaces[i] = true
+	}
+	typeDefs := make(map[*TypeDef]bool)
+	for _, td := range res.TypeDefs {
+		typeDefs[td] = true
+	}
+	checkType := func(t2 Type) {
+		if td, ok := t2.(*TypeDef); ok && !typeDefs[td] {
+			t.Errorf("type %s not found in Resolve", typeDefPathName(td))
+		}
+	}
+	checkFunction := func(f *Function) {
+		for _, p := range f.Params {
+			checkType(p.Type)
+		}
+		for _, r := range f.Results {
+			checkType(r.Type)
+		}
+	}
+
+	for _, td := range res.TypeDefs {
+		if i, ok := td.Owner.(*Interface); ok && !interfaces[i] {
+			t.Errorf("owner of type %s not found in Resolve", typeDefPathName(td))
+		}
+		for _, t2 := range typeDefChildren(td) {
+			checkType(t2)
+		}
+	}
+	for _, i := range res.Interfaces {
+		i.Functions.All()(func(_ string, f *Function) bool {
+			checkFunction(f)
+			return true
+		})
+	}
+	for _, w := range res.Worlds {
+		check := func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *Interface:
+				if !interfaces[item] {
+					t.Errorf("interface %s in world %s not found in Resolve", interfacePathName(item), w.Name)
+				}
+			case *TypeDef:
+				checkType(item)
+			case *Function:
+				checkFunction(item)
+			}
+			return true
+		}
+		w.Imports.All()(check)
+		w.Exports.All()(check)
+	}
+}
@@ -0,0 +1,127 @@
+package wit
+
+import (
+	"bufio"
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ydnar/wasm-tools-go/internal/codec"
+	"github.com/ydnar/wasm-tools-go/internal/codec/json"
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+)
+
+// Metadata describes the WIT packages in a [Resolve], without their types or functions.
+// It is returned by [DecodeMetadata].
+type Metadata struct {
+	Packages []*PackageMetadata
+}
+
+// PackageMetadata describes a WIT package: its name and version, documentation,
+// and the names of its interfaces and worlds, in order.
+type PackageMetadata struct {
+	Name       Ident
+	Docs       Docs
+	Interfaces []string
+	Worlds     []string
+}
+
+// DecodeMetadata decodes the [Metadata] of the WIT packages in r, which contains either
+// WIT JSON or a WIT package encoded as a WebAssembly component, such as the output of
+// wasm-tools component wit --wasm. It does not decode or validate worlds, interfaces, types,
+// or functions, so it is faster than [DecodeJSON] for listing or indexing packages.
+//
+// A component contains a single package, and only its documentation, if any.
+func DecodeMetadata(r io.Reader) (*Metadata, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(len(wasm.ComponentHeader))
+	if !bytes.Equal(header, wasm.ComponentHeader) {
+		m := &Metadata{}
+		err := json.NewDecoder(br, m).Decode(m)
+		return m, err
+	}
+
+	b, err := io.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	p, err := wasm.DecodePackage(b)
+	if err != nil {
+		return nil, err
+	}
+	m := &Metadata{}
+	var pkg *PackageMetadata
+	for _, exp := range p.Exports {
+		id, err := ParseIdent(exp.Name)
+		if err != nil {
+			return nil, err
+		}
+		name := id.Extension
+		id.Extension = ""
+		if pkg == nil || pkg.Name.String() != id.String() {
+			pkg = &PackageMetadata{Name: id}
+			m.Packages = append(m.Packages, pkg)
+		}
+		if exp.World {
+			pkg.Worlds = append(pkg.Worlds, name)
+		} else {
+			pkg.Interfaces = append(pkg.Interfaces, name)
+		}
+	}
+	if len(p.Docs) > 0 && len(m.Packages) == 1 {
+		// The package-docs section is a version byte followed by JSON.
+		var docs struct {
+			Docs string `json:"docs"`
+		}
+		if p.Docs[0] != 0 && p.Docs[0] != 1 {
+			return nil, fmt.Errorf("%s: unknown version %d", wasm.PackageDocsSection, p.Docs[0])
+		}
+		if err := stdjson.Unmarshal(p.Docs[1:], &docs); err != nil {
+			return nil, fmt.Errorf("%s: %w", wasm.PackageDocsSection, err)
+		}
+		m.Packages[0].Docs.Contents = docs.Docs
+	}
+	return m, nil
+}
+
+// ResolveCodec implements the [codec.Resolver] interface.
+func (m *Metadata) ResolveCodec(v any) codec.Codec {
+	if p, ok := v.(**PackageMetadata); ok {
+		return codec.Must(p)
+	}
+	return nil
+}
+
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object. Fields other than packages are skipped.
+func (m *Metadata) DecodeField(dec codec.Decoder, name string) error {
+	if name == "packages" {
+		return codec.DecodeSlice(dec, &m.Packages)
+	}
+	return nil
+}
+
+// DecodeField implements the [codec.FieldDecoder] interface
+// to decode a struct or JSON object.
+func (p *PackageMetadata) DecodeField(dec codec.Decoder, name string) error {
+	switch name {
+	case "name":
+		return dec.Decode(&p.Name)
+	case "docs":
+		return dec.Decode(&p.Docs)
+	case "interfaces":
+		return dec.Decode((*metadataNames)(&p.Interfaces))
+	case "worlds":
+		return dec.Decode((*metadataNames)(&p.Worlds))
+	}
+	return nil
+}
+
+// metadataNames decodes the keys of a JSON object, skipping the values.
+type metadataNames []string
+
+func (names *metadataNames) DecodeField(dec codec.Decoder, name string) error {
+	*names = append(*names, name)
+	return nil
+}
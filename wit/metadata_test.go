@@ -0,0 +1,92 @@
+package wit
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ydnar/wasm-tools-go/internal/wasm"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+func TestDecodeMetadataTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		m, err := DecodeMetadata(f)
+		if err != nil {
+			t.Errorf("%s: DecodeMetadata: %v", path, err)
+			return nil
+		}
+		if len(m.Packages) != len(res.Packages) {
+			t.Errorf("%s: DecodeMetadata: %d packages, expected %d", path, len(m.Packages), len(res.Packages))
+			return nil
+		}
+		for i, pkg := range res.Packages {
+			want := &PackageMetadata{
+				Name:       pkg.Name,
+				Docs:       pkg.Docs,
+				Interfaces: keys(&pkg.Interfaces),
+				Worlds:     keys(&pkg.Worlds),
+			}
+			if got := m.Packages[i]; !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: DecodeMetadata: package %d: %+v, expected %+v", path, i, got, want)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func keys[V any](m *ordered.Map[string, V]) []string {
+	var keys []string
+	m.All()(func(k string, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestDecodeMetadataComponent(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(`package ex:a@1.0.0;
+interface i {
+	resource r { constructor(); m: func() -> result<list<r>, string>; }
+	variant v { a(u32), b }
+	f: func(x: v) -> option<own<r>>;
+}
+world w {
+	import i;
+	export f: func(x: borrow<r>);
+	use i.{r};
+}
+`)}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := res.Worlds[0].ComponentType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = wasm.AppendCustomSection(b, wasm.PackageDocsSection, []byte("\x00{\"docs\":\"Package docs.\"}"))
+
+	m, err := DecodeMetadata(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := ParseIdent("ex:a@1.0.0")
+	want := &Metadata{Packages: []*PackageMetadata{{Name: id, Docs: Docs{Contents: "Package docs."}, Worlds: []string{"w"}}}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("DecodeMetadata: %+v, expected %+v", m.Packages[0], want.Packages[0])
+	}
+
+	if _, err := DecodeMetadata(bytes.NewReader(wasm.ComponentHeader[:6])); err == nil {
+		t.Errorf("DecodeMetadata(truncated header): nil error, expected error")
+	}
+}
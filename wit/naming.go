@@ -0,0 +1,132 @@
+package wit
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// TypeNamer names anonymous types, such as tuple<string, u32> or option<descriptor>,
+// so that code generators can declare named types for them.
+// Code that refers to a generated name depends on the name being stable,
+// so a TypeNamer should return the same name for the same type each time
+// a [Resolve] is decoded, and names should not change when unrelated
+// definitions are added or removed.
+//
+// Names returned by a TypeNamer are not guaranteed to be unique.
+// Callers must handle collisions.
+type TypeNamer interface {
+	// TypeName returns a kebab-case WIT identifier for anonymous type t,
+	// which is used by the param or result named param of function f.
+	// Param is empty for an unnamed result. F may be nil if t is not used by a function.
+	TypeName(t *TypeDef, f *Function, param string) string
+}
+
+// TypeNamerFunc is a function that implements [TypeNamer].
+type TypeNamerFunc func(t *TypeDef, f *Function, param string) string
+
+// TypeName implements the [TypeNamer] interface.
+func (fn TypeNamerFunc) TypeName(t *TypeDef, f *Function, param string) string {
+	return fn(t, f, param)
+}
+
+var (
+	// StructuralTypeNames names an anonymous type after its structure,
+	// e.g. "tuple-string-u32" for tuple<string, u32>, "option-descriptor" for
+	// option<descriptor>, or "result-void-error-code" for result<_, error-code>.
+	// Names change only if the type changes.
+	StructuralTypeNames TypeNamer = TypeNamerFunc(structuralTypeName)
+
+	// PositionalTypeNames names an anonymous type after where it is used,
+	// e.g. "descriptor-read-result" for the unnamed result of method
+	// [method]descriptor.read, or "open-flags" for the param flags of function open.
+	// Names change only if the function or param is renamed.
+	// Types not used by a function are named with [StructuralTypeNames].
+	PositionalTypeNames TypeNamer = TypeNamerFunc(positionalTypeName)
+
+	// HashedTypeNames names an anonymous type with a hash of its WIT text format,
+	// e.g. "anonymous-5e9dd4e2". Names are short, and change only if the type changes.
+	HashedTypeNames TypeNamer = TypeNamerFunc(hashedTypeName)
+)
+
+// DefaultTypeNamer is the default [TypeNamer], [StructuralTypeNames].
+var DefaultTypeNamer = StructuralTypeNames
+
+func structuralTypeName(t *TypeDef, _ *Function, _ string) string {
+	return structuralName(t)
+}
+
+func structuralName(t Type) string {
+	td, ok := t.(*TypeDef)
+	switch {
+	case t == nil:
+		return "void"
+	case !ok:
+		return t.TypeName()
+	case td.Name != nil:
+		return *td.Name
+	}
+	var names []string
+	switch kind := td.Kind.(type) {
+	case *TypeDef:
+		return structuralName(kind)
+	case *Tuple:
+		names = append(names, "tuple")
+		for _, t := range kind.Types {
+			names = append(names, structuralName(t))
+		}
+	case *Option:
+		names = append(names, "option", structuralName(kind.Type))
+	case *Result:
+		names = append(names, "result")
+		if kind.OK != nil || kind.Err != nil {
+			names = append(names, structuralName(kind.OK))
+		}
+		if kind.Err != nil {
+			names = append(names, structuralName(kind.Err))
+		}
+	case *List:
+		names = append(names, "list", structuralName(kind.Type))
+	case *Own:
+		// As in WIT, own<T> is written as T.
+		return structuralName(kind.Type)
+	case *Borrow:
+		names = append(names, "borrow", structuralName(kind.Type))
+	case *Future:
+		names = append(names, "future")
+		if kind.Type != nil {
+			names = append(names, structuralName(kind.Type))
+		}
+	case *Stream:
+		names = append(names, "stream")
+		if kind.Element != nil || kind.End != nil {
+			names = append(names, structuralName(kind.Element))
+		}
+		if kind.End != nil {
+			names = append(names, structuralName(kind.End))
+		}
+	default:
+		names = append(names, kind.WITKind())
+	}
+	return strings.Join(names, "-")
+}
+
+func positionalTypeName(t *TypeDef, f *Function, param string) string {
+	if f == nil {
+		return structuralName(t)
+	}
+	name := f.BaseName()
+	if r, ok := f.Type().(*TypeDef); ok && r.Name != nil {
+		name = *r.Name + "-" + name
+	}
+	if param == "" {
+		param = "result"
+	}
+	return name + "-" + param
+}
+
+func hashedTypeName(t *TypeDef, _ *Function, _ string) string {
+	h := fnv.New32a()
+	h.Write([]byte(t.WIT(nil, "")))
+	return fmt.Sprintf("anonymous-%08x", h.Sum32())
+}
@@ -0,0 +1,64 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeNamers(t *testing.T) {
+	name := func(s string) *string { return &s }
+	descriptor := &TypeDef{Name: name("descriptor"), Kind: &Resource{}}
+	errorCode := &TypeDef{Name: name("error-code"), Kind: &Enum{Cases: []EnumCase{{Name: "access"}}}}
+	bytes := &TypeDef{Kind: &List{Type: U8{}}}
+	tuple := &TypeDef{Kind: &Tuple{Types: []Type{bytes, Bool{}}}}
+	result := &TypeDef{Kind: &Result{OK: tuple, Err: errorCode}}
+	option := &TypeDef{Kind: &Option{Type: &TypeDef{Kind: &Own{Type: descriptor}}}}
+	read := &Function{
+		Name:    "[method]descriptor.read",
+		Kind:    &Method{Type: descriptor},
+		Params:  []Param{{Name: "self", Type: &TypeDef{Kind: &Borrow{Type: descriptor}}}, {Name: "length", Type: U64{}}},
+		Results: []Param{{Type: result}},
+	}
+	open := &Function{
+		Name:   "open",
+		Kind:   &Freestanding{},
+		Params: []Param{{Name: "path", Type: option}},
+	}
+
+	tests := []struct {
+		namer TypeNamer
+		t     *TypeDef
+		f     *Function
+		param string
+		want  string
+	}{
+		{StructuralTypeNames, bytes, nil, "", "list-u8"},
+		{StructuralTypeNames, tuple, nil, "", "tuple-list-u8-bool"},
+		{StructuralTypeNames, result, read, "", "result-tuple-list-u8-bool-error-code"},
+		{StructuralTypeNames, &TypeDef{Kind: &Result{Err: errorCode}}, nil, "", "result-void-error-code"},
+		{StructuralTypeNames, &TypeDef{Kind: &Result{}}, nil, "", "result"},
+		{StructuralTypeNames, option, open, "path", "option-descriptor"},
+		{StructuralTypeNames, read.Params[0].Type.(*TypeDef), read, "self", "borrow-descriptor"},
+		{PositionalTypeNames, result, read, "", "descriptor-read-result"},
+		{PositionalTypeNames, option, open, "path", "open-path"},
+		{PositionalTypeNames, tuple, nil, "", "tuple-list-u8-bool"},
+		{DefaultTypeNamer, tuple, nil, "", "tuple-list-u8-bool"},
+	}
+	for _, tt := range tests {
+		got := tt.namer.TypeName(tt.t, tt.f, tt.param)
+		if got != tt.want {
+			t.Errorf("TypeName(%s): %q, expected %q", tt.t.WIT(nil, ""), got, tt.want)
+		}
+	}
+
+	h := HashedTypeNames.TypeName(tuple, nil, "")
+	if !strings.HasPrefix(h, "anonymous-") || len(h) != len("anonymous-")+8 {
+		t.Errorf("HashedTypeNames: %q, expected anonymous- and 8 hex digits", h)
+	}
+	if got := HashedTypeNames.TypeName(&TypeDef{Kind: &Tuple{Types: []Type{bytes, Bool{}}}}, open, "path"); got != h {
+		t.Errorf("HashedTypeNames: %q for an identical type, expected %q", got, h)
+	}
+	if got := HashedTypeNames.TypeName(result, nil, ""); got == h {
+		t.Errorf("HashedTypeNames: %q for a different type, expected a different name", got)
+	}
+}
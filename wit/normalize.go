@@ -0,0 +1,174 @@
+package wit
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Normalize rewrites res into a normal form, so the WIT text format of two [Resolve] values
+// with the same definitions can be compared textually, regardless of the order in which
+// they were declared. Normalize:
+//
+//   - Removes documentation, as with [StripOptions] Docs.
+//   - Sorts packages by name, then orders them so each package precedes the packages that use it.
+//   - Sorts the interfaces and worlds in each package, the types and functions in each interface,
+//     and the imports and exports of each world by name.
+//   - Expands type aliases: each reference to a type alias declared in the same interface or world,
+//     e.g. type a = b or type size = u32, refers to the aliased type instead.
+//     The alias declarations remain.
+//
+// The order of record fields, variant and enum cases, and flags is significant
+// to the Canonical ABI, and is not changed.
+func (res *Resolve) Normalize() {
+	res.stripDocs()
+
+	byName := func(a, b *Package) int {
+		return cmp.Compare(a.Name.String(), b.Name.String())
+	}
+	slices.SortStableFunc(res.Packages, byName)
+	res.Packages = topological(res.Packages, func(pkg *Package) []*Package {
+		deps := packageDependencies(pkg)
+		slices.SortFunc(deps, byName)
+		return deps
+	})
+	for _, pkg := range res.Packages {
+		sortMap(&pkg.Interfaces, func(name string, _ *Interface) string { return name })
+		sortMap(&pkg.Worlds, func(name string, _ *World) string { return name })
+	}
+	for _, i := range res.Interfaces {
+		sortMap(&i.TypeDefs, func(name string, _ *TypeDef) string { return name })
+		sortMap(&i.Functions, func(name string, _ *Function) string { return name })
+	}
+	for _, w := range res.Worlds {
+		sortMap(&w.Imports, worldItemName)
+		sortMap(&w.Exports, worldItemName)
+	}
+
+	for _, t := range res.TypeDefs {
+		expandAliasesTypeDef(t)
+	}
+	for _, i := range res.Interfaces {
+		i.Functions.All()(func(_ string, f *Function) bool {
+			expandAliasesFunction(f)
+			return true
+		})
+	}
+	for _, w := range res.Worlds {
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			items.All()(func(_ string, item WorldItem) bool {
+				if f, ok := item.(*Function); ok {
+					expandAliasesFunction(f)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// sortMap sorts the entries in m by the key returned by sortKey.
+func sortMap[V any](m *ordered.Map[string, V], sortKey func(string, V) string) {
+	type entry struct {
+		name, key string
+		v         V
+	}
+	var entries []entry
+	m.All()(func(name string, v V) bool {
+		entries = append(entries, entry{name, sortKey(name, v), v})
+		return true
+	})
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return cmp.Compare(a.key, b.key)
+	})
+	*m = ordered.Map[string, V]{}
+	for _, e := range entries {
+		m.Set(e.name, e.v)
+	}
+}
+
+// expandAlias returns the type aliased by t, if t is a type alias declared
+// in the same interface or world as the type it aliases, otherwise t.
+func expandAlias(t Type) Type {
+	for {
+		td, ok := t.(*TypeDef)
+		if !ok || td.Name == nil {
+			return t
+		}
+		switch kind := td.Kind.(type) {
+		case *TypeDef:
+			if kind.Name != nil && kind.Owner != td.Owner {
+				return t // use
+			}
+			t = kind
+		case Type:
+			t = kind
+		default:
+			return t
+		}
+	}
+}
+
+// expandAliasesTypeDef expands each type alias referred to by t.
+func expandAliasesTypeDef(t *TypeDef) {
+	typeDef := func(td *TypeDef) *TypeDef {
+		if td, ok := expandAlias(td).(*TypeDef); ok {
+			return td
+		}
+		return td
+	}
+	switch kind := t.Kind.(type) {
+	case *TypeDef:
+		t.Kind = expandAlias(kind)
+	case *Record:
+		for i := range kind.Fields {
+			kind.Fields[i].Type = expandAlias(kind.Fields[i].Type)
+		}
+	case *Own:
+		kind.Type = typeDef(kind.Type)
+	case *Borrow:
+		kind.Type = typeDef(kind.Type)
+	case *Tuple:
+		for i := range kind.Types {
+			kind.Types[i] = expandAlias(kind.Types[i])
+		}
+	case *Variant:
+		for i := range kind.Cases {
+			if kind.Cases[i].Type != nil {
+				kind.Cases[i].Type = expandAlias(kind.Cases[i].Type)
+			}
+		}
+	case *Option:
+		kind.Type = expandAlias(kind.Type)
+	case *Result:
+		if kind.OK != nil {
+			kind.OK = expandAlias(kind.OK)
+		}
+		if kind.Err != nil {
+			kind.Err = expandAlias(kind.Err)
+		}
+	case *List:
+		kind.Type = expandAlias(kind.Type)
+	case *Future:
+		if kind.Type != nil {
+			kind.Type = expandAlias(kind.Type)
+		}
+	case *Stream:
+		if kind.Element != nil {
+			kind.Element = expandAlias(kind.Element)
+		}
+		if kind.End != nil {
+			kind.End = expandAlias(kind.End)
+		}
+	}
+}
+
+// expandAliasesFunction expands each type alias referred to by the params and results of f.
+func expandAliasesFunction(f *Function) {
+	for i := range f.Params {
+		f.Params[i].Type = expandAlias(f.Params[i].Type)
+	}
+	for i := range f.Results {
+		f.Results[i].Type = expandAlias(f.Results[i].Type)
+	}
+}
@@ -0,0 +1,90 @@
+package wit
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	a := loadStripWIT(t, `package ex:norm;
+
+/// Interface b.
+interface b {
+	type size = u32;
+	type count = size;
+	record r { z: count, a: list<size> }
+	f: func(x: count) -> option<size>;
+	enum e { y, x }
+}
+
+interface a {
+	use b.{r};
+	g: func(r: r);
+}
+
+world w {
+	export a;
+	import b;
+}
+`)
+	b := loadStripWIT(t, `package ex:norm;
+
+interface a {
+	use b.{r};
+	g: func(r: r);
+}
+
+world w {
+	import b;
+	export a;
+}
+
+interface b {
+	enum e { y, x }
+	f: func(x: u32) -> option<u32>;
+	/// Record r.
+	record r { z: u32, a: list<u32> }
+	type count = u32;
+	type size = u32;
+}
+`)
+	a.Normalize()
+	b.Normalize()
+	got, want := a.WIT(nil, ""), b.WIT(nil, "")
+	if got != want {
+		t.Errorf("Normalize:\n%s\nexpected:\n%s", got, want)
+	}
+	if err := a.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+
+	// The normal form is valid WIT, and is unchanged by normalizing it again.
+	res := loadStripWIT(t, got)
+	res.Normalize()
+	if got2 := res.WIT(nil, ""); got2 != got {
+		t.Errorf("Normalize(Normalize):\n%s\nexpected:\n%s", got2, got)
+	}
+}
+
+func TestNormalizeTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			res.Normalize()
+			if err := res.Verify(); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			got := res.WIT(nil, "")
+			res2, err := LoadFS(splitPackages(got), ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			res2.Normalize()
+			if got2 := res2.WIT(nil, ""); got2 != got {
+				t.Errorf("LoadFS(Normalize):\n%s\nexpected:\n%s", got2, got)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
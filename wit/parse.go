@@ -0,0 +1,1316 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// This file implements a parser for the WIT text format, which produces
+// an unresolved syntax tree for each package. See resolver.go for name resolution.
+
+// astPackage is a WIT package as parsed from one or more files, before name resolution.
+type astPackage struct {
+	name  *Ident // nil if no file declared the package name
+	docs  Docs
+	files []*astFile
+}
+
+// astFile holds the declarations in a single WIT file or nested package block.
+// Top-level use statements are scoped to the file that declares them.
+type astFile struct {
+	path       string
+	uses       []*astTopUse
+	interfaces []*astInterface
+	worlds     []*astWorld
+}
+
+// astTopUse is a top-level use statement, e.g. use wasi:io/streams@0.2.0 as streams;
+type astTopUse struct {
+	path astPath
+	as   string
+}
+
+// astPath refers to an interface or world, either in the same package
+// (pkg == nil) or in another package, e.g. wasi:io/streams@0.2.0.
+type astPath struct {
+	pos  Position
+	pkg  *Ident
+	name string
+}
+
+func (p *astPath) String() string {
+	if p.pkg == nil {
+		return p.name
+	}
+	id := *p.pkg
+	id.Extension = p.name
+	return id.String()
+}
+
+// astUse is a use statement in an interface or world, e.g. use types.{a, b as c};
+type astUse struct {
+	path      astPath
+	names     []astUseName
+	stability *Stability
+}
+
+type astUseName struct {
+	pos  Position
+	name string
+	as   string // name, unless renamed
+}
+
+type astInterface struct {
+	pos       Position
+	file      *astFile
+	name      string
+	docs      Docs
+	stability *Stability
+	items     []any // *astUse, *astTypeDef, or *astFunc
+}
+
+type astWorld struct {
+	pos       Position
+	file      *astFile
+	name      string
+	docs      Docs
+	stability *Stability
+	items     []any // *astUse, *astTypeDef, *astWorldItem, or *astInclude
+}
+
+// astWorldItem is an import or export of an interface or function.
+// Exactly one of path, iface, or fn is set.
+type astWorldItem struct {
+	pos       Position
+	export    bool
+	path      *astPath
+	stability *Stability    // of path; an inline interface or function has its own
+	iface     *astInterface // inline interface; name is the import or export name
+	fn        *astFunc
+}
+
+type astInclude struct {
+	path  astPath
+	names []astUseName // with { a as b }
+}
+
+type astTypeDef struct {
+	pos       Position
+	name      string
+	docs      Docs
+	stability *Stability
+	kind      any // *astRecord, *astVariant, *astEnum, *astFlags, *astResource, or an astType for type aliases
+}
+
+type astRecord struct{ fields []astField }
+
+type astField struct {
+	pos  Position
+	name string
+	typ  astType
+	docs Docs
+}
+
+type astVariant struct{ cases []astField } // typ is nil for cases without a payload
+
+type astEnum struct{ cases []astField }
+
+type astFlags struct{ flags []astField }
+
+type astResource struct{ funcs []*astFunc }
+
+type astFunc struct {
+	pos       Position
+	name      string
+	docs      Docs
+	stability *Stability
+	kind      string // "freestanding", "constructor", "method", or "static"
+	params    []astField
+	results   []astField // a single result is unnamed
+}
+
+// astType is a reference to a type: a [Primitive], *astName, or another ast type below.
+type astType any
+
+type astName struct {
+	pos  Position
+	name string
+}
+
+type astList struct{ typ astType }
+
+type astOption struct{ typ astType }
+
+type astResult struct{ ok, err astType } // either may be nil
+
+type astTuple struct{ types []astType }
+
+type astHandle struct {
+	borrow bool
+	name   astName
+}
+
+type astFuture struct{ typ astType } // typ may be nil
+
+type astStream struct{ typ, end astType } // either may be nil
+
+// parseWIT parses a WIT file. It returns the package declared at the top of the file,
+// which may be unnamed, followed by any nested package blocks.
+func parseWIT(path string, src []byte) ([]*astPackage, error) {
+	p := &parser{path: path, lex: lexer{src: string(src), line: 1}}
+	p.advance()
+	return p.parseFile()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenInt
+	tokenPunct
+)
+
+type token struct {
+	kind     tokenKind
+	text     string // identifiers exclude a leading %
+	escaped  bool   // identifier is %-escaped
+	offset   int
+	line     int
+	comments []comment // comments preceding the token
+}
+
+// comment is a line or block comment, which ends on endLine.
+type comment struct {
+	text          string
+	line, endLine int
+}
+
+func (t *token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of file"
+	case tokenIdent:
+		if t.escaped {
+			return "identifier %" + t.text
+		}
+		if keywords[t.text] {
+			return "keyword " + t.text
+		}
+		return "identifier " + t.text
+	}
+	return "'" + t.text + "'"
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	err  error
+}
+
+// next scans the next token, collecting the comments that precede it.
+func (l *lexer) next() token {
+	var comments []comment
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				end = len(l.src) - l.pos
+			}
+			comments = append(comments, comment{text: l.src[l.pos : l.pos+end], line: l.line, endLine: l.line})
+			l.pos += end
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			// Block comments nest.
+			start, line := l.pos, l.line
+			depth := 0
+			for l.pos < len(l.src) {
+				switch {
+				case strings.HasPrefix(l.src[l.pos:], "/*"):
+					depth++
+					l.pos += 2
+				case strings.HasPrefix(l.src[l.pos:], "*/"):
+					depth--
+					l.pos += 2
+				default:
+					if l.src[l.pos] == '\n' {
+						l.line++
+					}
+					l.pos++
+				}
+				if depth == 0 {
+					break
+				}
+			}
+			if depth != 0 {
+				l.err = fmt.Errorf("line %d: unterminated block comment", line)
+				return token{kind: tokenEOF, offset: l.pos, line: l.line}
+			}
+			comments = append(comments, comment{text: l.blockComment(start), line: line, endLine: l.line})
+		default:
+			return l.scan(comments)
+		}
+	}
+	return token{kind: tokenEOF, offset: l.pos, line: l.line, comments: comments}
+}
+
+// blockComment returns the block comment from start to the current position,
+// with the indentation of its first line removed from the lines that follow.
+func (l *lexer) blockComment(start int) string {
+	text := l.src[start:l.pos]
+	i := strings.LastIndexByte(l.src[:start], '\n') + 1
+	if prefix := l.src[i:start]; prefix != "" && strings.Trim(prefix, " \t") == "" {
+		text = strings.ReplaceAll(text, "\n"+prefix, "\n")
+	}
+	return text
+}
+
+func (l *lexer) scan(comments []comment) token {
+	t := token{offset: l.pos, line: l.line, comments: comments}
+	c := l.src[l.pos]
+	switch {
+	case c == '%' || isLetter(c):
+		start := l.pos
+		if c == '%' {
+			t.escaped = true
+			start++
+		}
+		l.pos = start
+		for l.pos < len(l.src) && (isLetter(l.src[l.pos]) || isDigit(l.src[l.pos]) || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		t.kind = tokenIdent
+		t.text = l.src[start:l.pos]
+		if t.text == "" {
+			l.err = fmt.Errorf("line %d: expected an identifier after %%", l.line)
+			t.kind = tokenEOF
+		}
+	case isDigit(c):
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		t.kind = tokenInt
+		t.text = l.src[t.offset:l.pos]
+	case strings.HasPrefix(l.src[l.pos:], "->"):
+		l.pos += 2
+		t.kind = tokenPunct
+		t.text = "->"
+	case strings.IndexByte("{}()<>,;:.=@/*_", c) >= 0:
+		l.pos++
+		t.kind = tokenPunct
+		t.text = string(c)
+	default:
+		l.err = fmt.Errorf("line %d: unexpected character %q", l.line, c)
+		t.kind = tokenEOF
+	}
+	return t
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+type parser struct {
+	path string
+	lex  lexer
+	tok  token // the next token
+	line int   // line of the last token consumed
+
+	// pending are comments not yet attached to a definition, which are attached
+	// as leading comments to the next definition, or as end comments of the enclosing one.
+	pending []string
+}
+
+func (p *parser) advance() token {
+	t := p.tok
+	p.pending = append(p.pending, commentText(t.comments)...)
+	p.line = t.line
+	p.tok = p.lex.next()
+	return t
+}
+
+// peek returns the token after the next token.
+func (p *parser) peek() token {
+	l := p.lex
+	return l.next()
+}
+
+func (p *parser) pos() Position {
+	return Position{Path: p.path, Line: p.tok.line}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	if p.lex.err != nil {
+		return fmt.Errorf("%s: %w", p.path, p.lex.err)
+	}
+	return fmt.Errorf("%s: %s", p.pos(), fmt.Sprintf(format, args...))
+}
+
+func (p *parser) unexpected(want string) error {
+	return p.errorf("expected %s, found %s", want, p.tok.String())
+}
+
+// is reports whether the next token is the punctuation s.
+func (p *parser) is(s string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == s
+}
+
+// isKeyword reports whether the next token is the keyword s.
+func (p *parser) isKeyword(s string) bool {
+	return p.tok.kind == tokenIdent && !p.tok.escaped && p.tok.text == s
+}
+
+// accept consumes the next token if it is the punctuation or keyword s.
+func (p *parser) accept(s string) bool {
+	if p.is(s) || p.isKeyword(s) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.accept(s) {
+		return p.unexpected("'" + s + "'")
+	}
+	return nil
+}
+
+// ident consumes an identifier, which must be %-escaped if it is a keyword.
+func (p *parser) ident() (string, error) {
+	if p.tok.kind != tokenIdent || (!p.tok.escaped && keywords[p.tok.text]) {
+		return "", p.unexpected("an identifier")
+	}
+	return p.advance().text, nil
+}
+
+// docs consumes the comments preceding the next token, returning the doc comments
+// directly above it, and the free-standing comments before them as leading comments.
+func (p *parser) docs() Docs {
+	return newDocs(p.comments())
+}
+
+func newDocs(leading, docs []string) Docs {
+	d := Docs{Contents: docContents(docs)}
+	if len(leading) > 0 {
+		d.Comments = &Comments{Leading: leading}
+	}
+	return d
+}
+
+func (d *Docs) setTrailing(comment string) {
+	if comment != "" {
+		d.comments().Trailing = comment
+	}
+}
+
+func (d *Docs) setEnd(comments []string) {
+	if len(comments) > 0 {
+		d.comments().End = comments
+	}
+}
+
+// merge merges the comments of a package from another file, c, into d.
+func (d *Docs) merge(c *Comments) {
+	if c == nil {
+		return
+	}
+	dc := d.comments()
+	dc.Leading = append(dc.Leading, c.Leading...)
+	if dc.Trailing == "" {
+		dc.Trailing = c.Trailing
+	}
+	dc.End = append(dc.End, c.End...)
+}
+
+func (d *Docs) comments() *Comments {
+	if d.Comments == nil {
+		d.Comments = &Comments{}
+	}
+	return d.Comments
+}
+
+// comments consumes the comments preceding the next token, returning the free-standing
+// comments, including any pending comments, and the doc comments on the lines directly
+// above the token. A comment on the same line as the previous token is free-standing.
+func (p *parser) comments() (leading, docs []string) {
+	comments := p.tok.comments
+	p.tok.comments = nil
+	i := len(comments)
+	for next := p.tok.line; i > 0 && comments[i-1].endLine >= next-1 && comments[i-1].line > p.line; i-- {
+		next = comments[i-1].line
+	}
+	leading = append(p.pending, commentText(comments[:i])...)
+	p.pending = nil
+	return leading, commentText(comments[i:])
+}
+
+// carry keeps comments for the next definition, for statements that have no [Docs],
+// such as use statements, includes, and imports or exports of named interfaces.
+func (p *parser) carry(comments ...string) {
+	for _, c := range comments {
+		if c != "" {
+			p.pending = append(p.pending, c)
+		}
+	}
+}
+
+// trailing consumes a comment on the same line as the previous token,
+// which ends a definition, and returns it, or an empty string if there is none.
+func (p *parser) trailing() string {
+	if len(p.tok.comments) == 0 || p.tok.comments[0].line != p.line {
+		return ""
+	}
+	c := p.tok.comments[0].text
+	p.tok.comments = p.tok.comments[1:]
+	return c
+}
+
+// close consumes a closing brace, if it is the next token, attaching the comments
+// that precede it to docs as end comments.
+func (p *parser) close(docs *Docs) bool {
+	if !p.is("}") {
+		return false
+	}
+	docs.setEnd(append(p.pending, commentText(p.tok.comments)...))
+	p.pending = nil
+	p.tok.comments = nil
+	p.advance()
+	return true
+}
+
+func commentText(comments []comment) []string {
+	var text []string
+	for _, c := range comments {
+		text = append(text, c.text)
+	}
+	return text
+}
+
+// docContents converts comments to doc text. Line comments are stripped of their
+// leading slashes and common indentation, and /** */ comments of their delimiters.
+func docContents(comments []string) string {
+	var lines []string
+	var indented []int // indexes of lines from line comments
+	for _, c := range comments {
+		if body, ok := strings.CutPrefix(c, "/**"); ok && !strings.HasPrefix(body, "/") {
+			body = strings.TrimSuffix(body, "*/")
+			blines := strings.Split(body, "\n")
+			for i, line := range blines {
+				if i == 0 {
+					line = strings.TrimSpace(line)
+				} else {
+					line = strings.TrimRight(line, " \t\r")
+				}
+				blines[i] = line
+			}
+			for len(blines) > 0 && blines[len(blines)-1] == "" {
+				blines = blines[:len(blines)-1]
+			}
+			lines = append(lines, blines...)
+			continue
+		}
+		indented = append(indented, len(lines))
+		lines = append(lines, strings.TrimRight(strings.TrimLeft(c, "/"), " \t\r"))
+	}
+	indent := -1
+	for _, i := range indented {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for _, i := range indented {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// version consumes a semantic version, e.g. 0.2.0-rc.1.
+// The lexer does not tokenize versions, so version scans the source directly.
+func (p *parser) version() (*semver.Version, error) {
+	if p.tok.kind != tokenInt {
+		return nil, p.unexpected("a version")
+	}
+	src := p.lex.src
+	start := p.tok.offset
+	end := start
+	for end < len(src) {
+		c := src[end]
+		if c == '.' && end+1 < len(src) && src[end+1] == '{' {
+			break // use a:b/c@1.0.0.{d}
+		}
+		if !isLetter(c) && !isDigit(c) && c != '.' && c != '-' && c != '+' {
+			break
+		}
+		end++
+	}
+	v, err := semver.NewVersion(src[start:end])
+	if err != nil {
+		return nil, p.errorf("invalid version %q: %v", src[start:end], err)
+	}
+	p.lex.pos = end
+	p.lex.line = p.tok.line
+	p.advance()
+	return v, nil
+}
+
+// packageName consumes a package name, e.g. wasi:io@0.2.0.
+func (p *parser) packageName() (Ident, error) {
+	var id Ident
+	var err error
+	if id.Namespace, err = p.ident(); err != nil {
+		return id, err
+	}
+	if err = p.expect(":"); err != nil {
+		return id, err
+	}
+	if id.Package, err = p.ident(); err != nil {
+		return id, err
+	}
+	if p.accept("@") {
+		if id.Version, err = p.version(); err != nil {
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+// parsePath consumes a reference to an interface or world: either a name, or a
+// fully-qualified path, e.g. wasi:io/streams@0.2.0.
+func (p *parser) parsePath() (astPath, error) {
+	path := astPath{pos: p.pos()}
+	name, err := p.ident()
+	if err != nil {
+		return path, err
+	}
+	if !p.is(":") {
+		path.name = name
+		return path, nil
+	}
+	p.advance()
+	id := Ident{Namespace: name}
+	if id.Package, err = p.ident(); err != nil {
+		return path, err
+	}
+	if err = p.expect("/"); err != nil {
+		return path, err
+	}
+	if path.name, err = p.ident(); err != nil {
+		return path, err
+	}
+	if p.accept("@") {
+		if id.Version, err = p.version(); err != nil {
+			return path, err
+		}
+	}
+	path.pkg = &id
+	return path, nil
+}
+
+// stability consumes any @since, @unstable, or @deprecated annotations.
+func (p *parser) stability() (*Stability, error) {
+	var s *Stability
+	for p.is("@") {
+		p.advance()
+		if s == nil {
+			s = &Stability{}
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		for !p.is(")") {
+			key, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			switch {
+			case key == "version" && name == "since":
+				s.Since, err = p.version()
+			case key == "version" && name == "deprecated":
+				s.Deprecated, err = p.version()
+			case key == "feature" && (name == "unstable" || name == "since"):
+				s.Feature, err = p.ident()
+			default:
+				return nil, p.errorf("unknown annotation @%s(%s)", name, key)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) parseFile() ([]*astPackage, error) {
+	top := &astPackage{files: []*astFile{{path: p.path}}}
+	pkgs := []*astPackage{top}
+	first := true
+	for p.tok.kind != tokenEOF {
+		if p.isKeyword("package") {
+			docs := p.docs()
+			p.advance()
+			id, err := p.packageName()
+			if err != nil {
+				return nil, err
+			}
+			if p.accept("{") {
+				nested := &astPackage{name: &id, docs: docs, files: []*astFile{{path: p.path}}}
+				if err := p.parseDecls(nested); err != nil {
+					return nil, err
+				}
+				nested.docs.setTrailing(p.trailing())
+				pkgs = append(pkgs, nested)
+				first = false
+				continue
+			}
+			if !first {
+				return nil, p.errorf("package declaration must come first")
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			top.name = &id
+			top.docs = docs
+			top.docs.setTrailing(p.trailing())
+			first = false
+			continue
+		}
+		first = false
+		if err := p.parseDecl(top.files[0]); err != nil {
+			return nil, err
+		}
+	}
+	if p.lex.err != nil {
+		return nil, p.errorf("")
+	}
+	leading, docs := p.comments()
+	top.docs.setEnd(append(leading, docs...))
+	return pkgs, nil
+}
+
+// parseDecls parses declarations until the closing brace of nested package pkg.
+func (p *parser) parseDecls(pkg *astPackage) error {
+	for !p.close(&pkg.docs) {
+		if p.tok.kind == tokenEOF {
+			return p.unexpected("'}'")
+		}
+		if err := p.parseDecl(pkg.files[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDecl parses a top-level use, interface, or world, or a nested package.
+func (p *parser) parseDecl(file *astFile) error {
+	if p.tok.kind == tokenEOF {
+		return nil
+	}
+	pos := p.pos()
+	leading, text := p.comments()
+	docs := newDocs(leading, text)
+	stability, err := p.stability()
+	if err != nil {
+		return err
+	}
+	switch {
+	case p.accept("use"):
+		path, err := p.parsePath()
+		if err != nil {
+			return err
+		}
+		use := &astTopUse{path: path, as: path.name}
+		if p.accept("as") {
+			if use.as, err = p.ident(); err != nil {
+				return err
+			}
+		}
+		file.uses = append(file.uses, use)
+		if err := p.expect(";"); err != nil {
+			return err
+		}
+		p.carry(leading...)
+		p.carry(text...)
+		p.carry(p.trailing())
+		return nil
+
+	case p.accept("interface"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		iface := &astInterface{pos: pos, file: file, name: name, docs: docs, stability: stability}
+		if err := p.parseInterfaceBody(iface); err != nil {
+			return err
+		}
+		iface.docs.setTrailing(p.trailing())
+		file.interfaces = append(file.interfaces, iface)
+		return nil
+
+	case p.accept("world"):
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		w := &astWorld{pos: pos, file: file, name: name, docs: docs, stability: stability}
+		if err := p.parseWorldBody(w); err != nil {
+			return err
+		}
+		w.docs.setTrailing(p.trailing())
+		file.worlds = append(file.worlds, w)
+		return nil
+
+	case p.isKeyword("package"):
+		return p.errorf("nested packages must be declared at the top level")
+	}
+	return p.unexpected("'use', 'interface', or 'world'")
+}
+
+func (p *parser) parseInterfaceBody(iface *astInterface) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.close(&iface.docs) {
+		pos := p.pos()
+		leading, text := p.comments()
+		docs := newDocs(leading, text)
+		stability, err := p.stability()
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.isKeyword("use"):
+			use, err := p.parseUse()
+			if err != nil {
+				return err
+			}
+			use.stability = stability
+			iface.items = append(iface.items, use)
+			p.carry(leading...)
+			p.carry(text...)
+			p.carry(p.trailing())
+		case isTypeDefKeyword(p):
+			td, err := p.parseTypeDef(docs)
+			if err != nil {
+				return err
+			}
+			td.pos, td.stability = pos, stability
+			td.docs.setTrailing(p.trailing())
+			iface.items = append(iface.items, td)
+		default:
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			fn, err := p.parseFunc(name, "freestanding")
+			if err != nil {
+				return err
+			}
+			fn.pos, fn.docs, fn.stability = pos, docs, stability
+			iface.items = append(iface.items, fn)
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+			fn.docs.setTrailing(p.trailing())
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseWorldBody(w *astWorld) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.close(&w.docs) {
+		pos := p.pos()
+		leading, text := p.comments()
+		docs := newDocs(leading, text)
+		stability, err := p.stability()
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.isKeyword("use"):
+			use, err := p.parseUse()
+			if err != nil {
+				return err
+			}
+			use.stability = stability
+			w.items = append(w.items, use)
+			p.carry(leading...)
+			p.carry(text...)
+			p.carry(p.trailing())
+
+		case isTypeDefKeyword(p):
+			td, err := p.parseTypeDef(docs)
+			if err != nil {
+				return err
+			}
+			td.pos, td.stability = pos, stability
+			td.docs.setTrailing(p.trailing())
+			w.items = append(w.items, td)
+
+		case p.isKeyword("import"), p.isKeyword("export"):
+			item := &astWorldItem{pos: pos, export: p.advance().text == "export"}
+			next := p.peek()
+			if p.tok.kind == tokenIdent && next.kind == tokenPunct && next.text == ":" &&
+				(p.peek2Keyword("func") || p.peek2Keyword("interface") || p.peek2Keyword("async")) {
+				name, err := p.ident()
+				if err != nil {
+					return err
+				}
+				p.advance() // :
+				if p.accept("interface") {
+					item.iface = &astInterface{pos: pos, file: w.file, name: name, docs: docs, stability: stability}
+					if err := p.parseInterfaceBody(item.iface); err != nil {
+						return err
+					}
+					item.iface.docs.setTrailing(p.trailing())
+				} else {
+					if item.fn, err = p.parseFunc(name, "freestanding"); err != nil {
+						return err
+					}
+					item.fn.pos, item.fn.docs, item.fn.stability = pos, docs, stability
+					if err := p.expect(";"); err != nil {
+						return err
+					}
+					item.fn.docs.setTrailing(p.trailing())
+				}
+			} else {
+				path, err := p.parsePath()
+				if err != nil {
+					return err
+				}
+				item.path, item.stability = &path, stability
+				if err := p.expect(";"); err != nil {
+					return err
+				}
+				p.carry(leading...)
+				p.carry(text...)
+				p.carry(p.trailing())
+			}
+			w.items = append(w.items, item)
+
+		case p.accept("include"):
+			path, err := p.parsePath()
+			if err != nil {
+				return err
+			}
+			inc := &astInclude{path: path}
+			if p.accept("with") {
+				if err := p.expect("{"); err != nil {
+					return err
+				}
+				for !p.accept("}") {
+					n := astUseName{pos: p.pos()}
+					if n.name, err = p.ident(); err != nil {
+						return err
+					}
+					if err := p.expect("as"); err != nil {
+						return err
+					}
+					if n.as, err = p.ident(); err != nil {
+						return err
+					}
+					inc.names = append(inc.names, n)
+					if !p.accept(",") && !p.is("}") {
+						return p.unexpected("',' or '}'")
+					}
+				}
+			} else if err := p.expect(";"); err != nil {
+				return err
+			}
+			p.carry(leading...)
+			p.carry(text...)
+			p.carry(p.trailing())
+			w.items = append(w.items, inc)
+
+		default:
+			return p.unexpected("'use', 'import', 'export', 'include', or a type definition")
+		}
+	}
+	return nil
+}
+
+// peek2Keyword reports whether the token two tokens ahead is the keyword s.
+func (p *parser) peek2Keyword(s string) bool {
+	l := p.lex
+	l.next()
+	t := l.next()
+	return t.kind == tokenIdent && !t.escaped && t.text == s
+}
+
+func isTypeDefKeyword(p *parser) bool {
+	for _, s := range []string{"type", "record", "variant", "enum", "flags", "resource"} {
+		if p.isKeyword(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUse parses use path.{a, b as c};
+func (p *parser) parseUse() (*astUse, error) {
+	p.advance() // use
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	use := &astUse{path: path}
+	if err := p.expect("."); err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		n := astUseName{pos: p.pos()}
+		if n.name, err = p.ident(); err != nil {
+			return nil, err
+		}
+		n.as = n.name
+		if p.accept("as") {
+			if n.as, err = p.ident(); err != nil {
+				return nil, err
+			}
+		}
+		use.names = append(use.names, n)
+		if !p.accept(",") && !p.is("}") {
+			return nil, p.unexpected("',' or '}'")
+		}
+	}
+	return use, p.expect(";")
+}
+
+// parseTypeDef parses a type definition with docs, which include the comments
+// before the closing brace of a resource, record, variant, enum, or flags.
+func (p *parser) parseTypeDef(docs Docs) (*astTypeDef, error) {
+	keyword := p.advance().text
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	td := &astTypeDef{name: name, docs: docs}
+	switch keyword {
+	case "type":
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		if td.kind, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		return td, p.expect(";")
+
+	case "resource":
+		r := &astResource{}
+		td.kind = r
+		if p.accept(";") {
+			return td, nil
+		}
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		for !p.close(&td.docs) {
+			pos := p.pos()
+			docs := p.docs()
+			stability, err := p.stability()
+			if err != nil {
+				return nil, err
+			}
+			var fn *astFunc
+			if p.accept("constructor") {
+				fn = &astFunc{kind: "constructor"}
+				if fn.params, err = p.parseParams(); err != nil {
+					return nil, err
+				}
+				if p.is("->") {
+					return nil, p.errorf("constructor results are not supported")
+				}
+			} else {
+				name, err := p.ident()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				kind := "method"
+				if p.accept("static") {
+					kind = "static"
+				}
+				if fn, err = p.parseFunc(name, kind); err != nil {
+					return nil, err
+				}
+			}
+			fn.pos, fn.docs, fn.stability = pos, docs, stability
+			r.funcs = append(r.funcs, fn)
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			fn.docs.setTrailing(p.trailing())
+		}
+		return td, nil
+	}
+
+	// record, variant, enum, or flags
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var fields []astField
+	for !p.close(&td.docs) {
+		f := astField{pos: p.pos(), docs: p.docs()}
+		if f.name, err = p.ident(); err != nil {
+			return nil, err
+		}
+		switch {
+		case keyword == "record":
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if f.typ, err = p.parseType(); err != nil {
+				return nil, err
+			}
+		case keyword == "variant" && p.accept("("):
+			if f.typ, err = p.parseType(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.accept(",") && !p.is("}") {
+			return nil, p.unexpected("',' or '}'")
+		}
+		f.docs.setTrailing(p.trailing())
+		fields = append(fields, f)
+	}
+	switch keyword {
+	case "record":
+		td.kind = &astRecord{fields: fields}
+	case "variant":
+		td.kind = &astVariant{cases: fields}
+	case "enum":
+		td.kind = &astEnum{cases: fields}
+	case "flags":
+		td.kind = &astFlags{flags: fields}
+	}
+	return td, nil
+}
+
+// parseFunc parses a function signature after name:, e.g. func(a: u32) -> string.
+func (p *parser) parseFunc(name, kind string) (*astFunc, error) {
+	if p.isKeyword("async") {
+		return nil, &FeatureError{Feature: FeatureAsync, Err: p.errorf("async functions are not supported")}
+	}
+	if err := p.expect("func"); err != nil {
+		return nil, err
+	}
+	fn := &astFunc{name: name, kind: kind}
+	var err error
+	if fn.params, err = p.parseParams(); err != nil {
+		return nil, err
+	}
+	if !p.accept("->") {
+		return fn, nil
+	}
+	if p.is("(") {
+		// Named results
+		fn.results, err = p.parseParams()
+		return fn, err
+	}
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	fn.results = []astField{{typ: t}}
+	return fn, nil
+}
+
+func (p *parser) parseParams() ([]astField, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var params []astField
+	for !p.accept(")") {
+		f := astField{pos: p.pos()}
+		var err error
+		if f.name, err = p.ident(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if f.typ, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		params = append(params, f)
+		if !p.accept(",") && !p.is(")") {
+			return nil, p.unexpected("',' or ')'")
+		}
+	}
+	return params, nil
+}
+
+func (p *parser) parseType() (astType, error) {
+	if p.tok.kind != tokenIdent {
+		return nil, p.unexpected("a type")
+	}
+	if p.tok.escaped || !keywords[p.tok.text] {
+		return &astName{pos: p.pos(), name: p.advance().text}, nil
+	}
+	if t, err := ParseType(p.tok.text); err == nil {
+		p.advance()
+		return t, nil
+	}
+	keyword := p.tok.text
+	p.advance()
+	switch keyword {
+	case "list", "option":
+		t, err := p.parseTypeParam()
+		if err != nil {
+			return nil, err
+		}
+		if p.is(",") {
+			return nil, &FeatureError{Feature: FeatureFixedSizeLists, Err: p.errorf("fixed-size lists are not supported")}
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+		if keyword == "list" {
+			return &astList{typ: t}, nil
+		}
+		return &astOption{typ: t}, nil
+
+	case "result":
+		r := &astResult{}
+		if !p.accept("<") {
+			return r, nil
+		}
+		var err error
+		if !p.accept("_") {
+			if r.ok, err = p.parseType(); err != nil {
+				return nil, err
+			}
+			if p.accept(">") {
+				return r, nil
+			}
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if r.err, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		return r, p.expect(">")
+
+	case "tuple":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		tup := &astTuple{}
+		for !p.accept(">") {
+			t, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			tup.types = append(tup.types, t)
+			if !p.accept(",") && !p.is(">") {
+				return nil, p.unexpected("',' or '>'")
+			}
+		}
+		return tup, nil
+
+	case "own", "borrow":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		h := &astHandle{borrow: keyword == "borrow", name: astName{pos: p.pos()}}
+		var err error
+		if h.name.name, err = p.ident(); err != nil {
+			return nil, err
+		}
+		return h, p.expect(">")
+
+	case "future":
+		f := &astFuture{}
+		if p.is("<") {
+			var err error
+			if f.typ, err = p.parseTypeParam(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(">"); err != nil {
+				return nil, err
+			}
+		}
+		return f, nil
+
+	case "stream":
+		// Older versions of WIT declared streams as stream<T, E>, where either may be _.
+		s := &astStream{}
+		if !p.accept("<") {
+			return s, nil
+		}
+		var err error
+		if !p.accept("_") {
+			if s.typ, err = p.parseType(); err != nil {
+				return nil, err
+			}
+		}
+		if p.accept(",") {
+			if s.end, err = p.parseType(); err != nil {
+				return nil, err
+			}
+		}
+		return s, p.expect(">")
+	}
+	err := p.errorf("type %s is not supported", keyword)
+	if keyword == "error-context" {
+		return nil, &FeatureError{Feature: FeatureErrorContext, Err: err}
+	}
+	return nil, err
+}
+
+// parseTypeParam parses <T, leaving the closing > or , to the caller.
+func (p *parser) parseTypeParam() (astType, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	return p.parseType()
+}
+
+// empty reports whether f declares nothing.
+func (f *astFile) empty() bool {
+	return len(f.uses) == 0 && len(f.interfaces) == 0 && len(f.worlds) == 0
+}
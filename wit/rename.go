@@ -0,0 +1,284 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Rename renames node to newName, updating every map in res that refers to node by name.
+// Node must be an [Interface], [TypeDef], [Function], or [World] contained in res.
+//
+// References to definitions in a Resolve are pointers, so use statements, function signatures,
+// and world items continue to refer to the renamed node. Types that use a renamed type
+// without an alias, e.g. use a.{x}, are renamed too, as are the methods, static functions,
+// and constructor of a renamed resource.
+//
+// Rename returns an error if newName is not a valid WIT identifier, or if it conflicts
+// with an existing name. If Rename returns an error, res is not modified.
+func (res *Resolve) Rename(node Node, newName string) error {
+	err := validateName(newName)
+	if err != nil {
+		return err
+	}
+	switch node := node.(type) {
+	case *Interface:
+		return res.renameInterface(node, newName)
+	case *TypeDef:
+		return res.renameTypeDef(node, newName)
+	case *Function:
+		return res.renameFunction(node, newName)
+	case *World:
+		return res.renameWorld(node, newName)
+	}
+	return fmt.Errorf("cannot rename %s", node.WITKind())
+}
+
+func (res *Resolve) renameInterface(i *Interface, name string) error {
+	if i.Name == nil {
+		// Anonymous interfaces are named by the world item that contains them.
+		for _, w := range res.Worlds {
+			for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+				if from, ok := findKey(items, WorldItem(i)); ok {
+					return renameKeys(renameKey(items, from, name))
+				}
+			}
+		}
+		return errors.New("cannot rename interface: not found in a world")
+	}
+	if i.Package == nil {
+		return fmt.Errorf("cannot rename interface %s: no package", *i.Name)
+	}
+	err := renameKeys(renameKey(&i.Package.Interfaces, *i.Name, name))
+	if err != nil {
+		return err
+	}
+	i.Name = &name
+	return nil
+}
+
+func (res *Resolve) renameWorld(w *World, name string) error {
+	if w.Package == nil {
+		return fmt.Errorf("cannot rename world %s: no package", w.Name)
+	}
+	err := renameKeys(renameKey(&w.Package.Worlds, w.Name, name))
+	if err != nil {
+		return err
+	}
+	w.Name = name
+	return nil
+}
+
+func (res *Resolve) renameFunction(f *Function, name string) error {
+	var newName string
+	switch f.Kind.(type) {
+	case *Freestanding:
+		newName = name
+	case *Method, *Static:
+		prefix, _, ok := strings.Cut(f.Name, ".")
+		if !ok {
+			return fmt.Errorf("cannot rename function %s: malformed name", f.Name)
+		}
+		newName = prefix + "." + name
+	default:
+		return fmt.Errorf("cannot rename %s", f.Name)
+	}
+	r, err := res.renameFunctionKey(f, newName)
+	if err != nil {
+		return err
+	}
+	err = renameKeys(r)
+	if err != nil {
+		return err
+	}
+	f.Name = newName
+	return nil
+}
+
+// renameFunctionKey returns a renamer for the map that contains f.
+func (res *Resolve) renameFunctionKey(f *Function, name string) (renamer, error) {
+	for _, i := range res.Interfaces {
+		if from, ok := findKey(&i.Functions, f); ok {
+			return renameKey(&i.Functions, from, name), nil
+		}
+	}
+	for _, w := range res.Worlds {
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			if from, ok := findKey(items, WorldItem(f)); ok {
+				return renameKey(items, from, name), nil
+			}
+		}
+	}
+	return renamer{}, fmt.Errorf("cannot rename function %s: not found", f.Name)
+}
+
+func (res *Resolve) renameTypeDef(t *TypeDef, name string) error {
+	if t.Name == nil {
+		return errors.New("cannot rename anonymous type")
+	}
+
+	// Collect t and any types that use t without an alias.
+	types := []*TypeDef{t}
+	for i := 0; i < len(types); i++ {
+		for _, u := range res.TypeDefs {
+			if u.Kind == types[i] && u.Name != nil && *u.Name == *t.Name && u.Owner != types[i].Owner {
+				types = append(types, u)
+			}
+		}
+	}
+
+	var renamers []renamer
+	var funcs []*Function
+	var names []string
+	for _, t := range types {
+		switch owner := t.Owner.(type) {
+		case *Interface:
+			renamers = append(renamers, renameKey(&owner.TypeDefs, *t.Name, name))
+		case *World:
+			renamers = append(renamers, renameKey(&owner.Imports, *t.Name, name))
+		default:
+			return fmt.Errorf("cannot rename type %s: no owner", *t.Name)
+		}
+
+		// Rename resource methods, static functions, and constructor.
+		if _, ok := t.Kind.(*Resource); !ok {
+			continue
+		}
+		var err error
+		t.Owner.AllFunctions()(func(f *Function) bool {
+			if f.Type() != Type(t) {
+				return true
+			}
+			prefix, rest, _ := strings.Cut(f.Name, "]")
+			_, base, hasBase := strings.Cut(rest, ".")
+			newName := prefix + "]" + name
+			if hasBase {
+				newName += "." + base
+			}
+			var r renamer
+			r, err = res.renameFunctionKey(f, newName)
+			renamers = append(renamers, r)
+			funcs = append(funcs, f)
+			names = append(names, newName)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := renameKeys(renamers...)
+	if err != nil {
+		return err
+	}
+	for _, t := range types {
+		t.Name = &name
+	}
+	for i, f := range funcs {
+		f.Name = names[i]
+	}
+	return nil
+}
+
+// renamer renames a key in an ordered map.
+type renamer struct {
+	check func() error
+	apply func()
+}
+
+// renameKey returns a renamer that renames key from to key to in m, preserving order.
+func renameKey[V any](m *ordered.Map[string, V], from, to string) renamer {
+	return renamer{
+		check: func() error {
+			if from == to {
+				return nil
+			}
+			if _, ok := m.GetOK(to); ok {
+				return fmt.Errorf("cannot rename %s to %s: name already exists", from, to)
+			}
+			return nil
+		},
+		apply: func() {
+			var keys []string
+			var values []V
+			m.All()(func(k string, v V) bool {
+				if k == from {
+					k = to
+				}
+				keys = append(keys, k)
+				values = append(values, v)
+				return true
+			})
+			*m = ordered.Map[string, V]{}
+			for i := range keys {
+				m.Set(keys[i], values[i])
+			}
+		},
+	}
+}
+
+// renameKeys checks each renamer before applying any of them.
+func renameKeys(renamers ...renamer) error {
+	for _, r := range renamers {
+		if err := r.check(); err != nil {
+			return err
+		}
+	}
+	for _, r := range renamers {
+		r.apply()
+	}
+	return nil
+}
+
+// findKey returns the key for value v in m, if present.
+func findKey[V comparable](m *ordered.Map[string, V], v V) (key string, found bool) {
+	m.All()(func(k string, x V) bool {
+		if x == v {
+			key, found = k, true
+			return false
+		}
+		return true
+	})
+	return key, found
+}
+
+// validateName returns an error if name is not a valid WIT identifier:
+// one or more hyphen-separated words, each either all lowercase or all uppercase,
+// starting with a letter. Keywords are valid names, as they are escaped when printed,
+// but escaped names such as %type are not.
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("empty WIT identifier")
+	}
+	if name[0] == '%' && keywords[name[1:]] {
+		return fmt.Errorf("invalid WIT identifier %q: use %q, which is escaped when printed", name, name[1:])
+	}
+	for _, word := range strings.Split(name, "-") {
+		if word == "" || !isLetter(word[0]) {
+			return fmt.Errorf("invalid WIT identifier %q", name)
+		}
+		var lower, upper bool
+		for i := 0; i < len(word); i++ {
+			c := word[i]
+			switch {
+			case c >= 'a' && c <= 'z':
+				lower = true
+			case c >= 'A' && c <= 'Z':
+				upper = true
+			case c >= '0' && c <= '9':
+			default:
+				return fmt.Errorf("invalid WIT identifier %q", name)
+			}
+		}
+		if lower && upper {
+			return fmt.Errorf("invalid WIT identifier %q: mixed case", name)
+		}
+	}
+	return nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,162 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+const renameJSON = `{
+	"worlds": [
+		{"name": "w", "imports": {"interface-0": {"interface": 0}, "f": {"function": {"name": "f", "kind": "freestanding", "params": [], "results": []}}}, "exports": {}, "package": 0}
+	],
+	"interfaces": [
+		{
+			"name": "a",
+			"types": {"r": 0, "x": 1},
+			"functions": {
+				"[constructor]r": {"name": "[constructor]r", "kind": {"constructor": 0}, "params": [], "results": [{"type": 2}]},
+				"[method]r.m": {"name": "[method]r.m", "kind": {"method": 0}, "params": [{"name": "self", "type": 3}], "results": []},
+				"g": {"name": "g", "kind": "freestanding", "params": [{"name": "v", "type": 1}], "results": []}
+			},
+			"package": 0
+		},
+		{"name": "b", "types": {"x": 4, "y": 5}, "functions": {}, "package": 0}
+	],
+	"types": [
+		{"name": "r", "kind": "resource", "owner": {"interface": 0}},
+		{"name": "x", "kind": {"record": {"fields": [{"name": "f", "type": "u32"}]}}, "owner": {"interface": 0}},
+		{"name": null, "kind": {"handle": {"own": 0}}, "owner": null},
+		{"name": null, "kind": {"handle": {"borrow": 0}}, "owner": null},
+		{"name": "x", "kind": {"type": 1}, "owner": {"interface": 1}},
+		{"name": "y", "kind": {"type": "u32"}, "owner": {"interface": 1}}
+	],
+	"packages": [{"name": "foo:bar", "interfaces": {"a": 0, "b": 1}, "worlds": {"w": 0}}]
+}`
+
+func TestRename(t *testing.T) {
+	load := func(t *testing.T) *Resolve {
+		res, err := DecodeJSON(strings.NewReader(renameJSON))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	t.Run("interface", func(t *testing.T) {
+		res := load(t)
+		a := res.Interfaces[0]
+		if err := res.Rename(a, "c"); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := *a.Name, "c"; got != want {
+			t.Errorf("Name: %q, expected %q", got, want)
+		}
+		if got := res.Packages[0].Interfaces.Get("c"); got != a {
+			t.Errorf("Package.Interfaces[c]: %v, expected %v", got, a)
+		}
+		if _, ok := res.Packages[0].Interfaces.GetOK("a"); ok {
+			t.Errorf("Package.Interfaces[a]: found, expected not found")
+		}
+		wantWIT(t, res, "use c.{x};", "import c;")
+	})
+
+	t.Run("type used by another interface", func(t *testing.T) {
+		res := load(t)
+		x := res.TypeDefs[1]
+		if err := res.Rename(x, "z"); err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Interfaces[1].TypeDefs.Get("z"); got != res.TypeDefs[4] || *got.Name != "z" {
+			t.Errorf("b.TypeDefs[z]: %v, expected %v", got, res.TypeDefs[4])
+		}
+		wantWIT(t, res, "record z {", "g: func(v: z);", "use a.{z};")
+	})
+
+	t.Run("resource", func(t *testing.T) {
+		res := load(t)
+		r := res.TypeDefs[0]
+		if err := res.Rename(r, "s"); err != nil {
+			t.Fatal(err)
+		}
+		a := res.Interfaces[0]
+		for _, name := range []string{"[constructor]s", "[method]s.m"} {
+			f, ok := a.Functions.GetOK(name)
+			if !ok || f.Name != name {
+				t.Errorf("function %s not found", name)
+			}
+		}
+		wantWIT(t, res, "resource s {", "constructor();", "m: func();")
+	})
+
+	t.Run("method", func(t *testing.T) {
+		res := load(t)
+		m := res.Interfaces[0].Functions.Get("[method]r.m")
+		if err := res.Rename(m, "n"); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := m.Name, "[method]r.n"; got != want {
+			t.Errorf("Name: %q, expected %q", got, want)
+		}
+		wantWIT(t, res, "n: func();")
+	})
+
+	t.Run("world function", func(t *testing.T) {
+		res := load(t)
+		w := res.Worlds[0]
+		f := w.Imports.Get("f").(*Function)
+		if err := res.Rename(f, "h"); err != nil {
+			t.Fatal(err)
+		}
+		if got := w.Imports.Get("h"); got != f {
+			t.Errorf("Imports[h]: %v, expected %v", got, f)
+		}
+		wantWIT(t, res, "import h: func();")
+	})
+
+	t.Run("world", func(t *testing.T) {
+		res := load(t)
+		w := res.Worlds[0]
+		if err := res.Rename(w, "v"); err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Packages[0].Worlds.Get("v"); got != w {
+			t.Errorf("Package.Worlds[v]: %v, expected %v", got, w)
+		}
+		wantWIT(t, res, "world v {")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		res := load(t)
+		want := res.WIT(nil, "")
+		tests := []struct {
+			node Node
+			name string
+		}{
+			{res.Interfaces[0], "b"},
+			{res.Interfaces[1], "Not-Valid"},
+			{res.Interfaces[1], "1b"},
+			{res.TypeDefs[1], "r"},
+			{res.TypeDefs[2], "own"},
+			{res.Interfaces[0].Functions.Get("[constructor]r"), "new"},
+			{res.Interfaces[0].Functions.Get("g"), ""},
+		}
+		for _, tt := range tests {
+			if err := res.Rename(tt.node, tt.name); err == nil {
+				t.Errorf("Rename(%s, %q): expected error", tt.node.WITKind(), tt.name)
+			}
+		}
+		if got := res.WIT(nil, ""); got != want {
+			t.Errorf("Resolve modified by failed Rename")
+		}
+	})
+}
+
+func wantWIT(t *testing.T, res *Resolve, want ...string) {
+	t.Helper()
+	got := res.WIT(nil, "")
+	for _, s := range want {
+		if !strings.Contains(got, s) {
+			t.Errorf("WIT does not contain %q:\n%s", s, got)
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"unsafe"
 
+	"github.com/coreos/go-semver/semver"
+
 	"github.com/ydnar/wasm-tools-go/wit/iterate"
 	"github.com/ydnar/wasm-tools-go/wit/ordered"
 )
@@ -57,9 +59,16 @@ type World struct {
 	Imports ordered.Map[string, WorldItem]
 	Exports ordered.Map[string, WorldItem]
 
+	// ImportStability and ExportStability hold the @since, @unstable, or @deprecated gates
+	// of the interfaces in Imports and Exports, by name. For an interface declared inline,
+	// the gate is also its Stability. Either map may be nil.
+	ImportStability map[string]*Stability
+	ExportStability map[string]*Stability
+
 	// The [Package] that this World belongs to. It must be non-nil when fully resolved.
-	Package *Package
-	Docs    Docs
+	Package   *Package
+	Docs      Docs
+	Stability *Stability // WIT @since, @unstable, or @deprecated gates; may be nil
 }
 
 // AllFunctions returns a [sequence] that yields each [Function] in a [World].
@@ -116,8 +125,9 @@ type Interface struct {
 	Functions ordered.Map[string, *Function]
 
 	// The [Package] that this Interface belongs to. It must be non-nil when fully resolved.
-	Package *Package
-	Docs    Docs
+	Package   *Package
+	Docs      Docs
+	Stability *Stability // WIT @since, @unstable, or @deprecated gates; may be nil
 }
 
 // AllFunctions returns a [sequence] that yields each [Function] in an [Interface].
@@ -138,10 +148,11 @@ func (i *Interface) AllFunctions() iterate.Seq[*Function] {
 type TypeDef struct {
 	_type
 	_worldItem
-	Name  *string
-	Kind  TypeDefKind
-	Owner TypeOwner
-	Docs  Docs
+	Name      *string
+	Kind      TypeDefKind
+	Owner     TypeOwner
+	Docs      Docs
+	Stability *Stability // WIT @since, @unstable, or @deprecated gates; may be nil
 }
 
 // TypeName returns the [WIT] type name for t.
@@ -170,6 +181,23 @@ func (t *TypeDef) Root() *TypeDef {
 	}
 }
 
+// UseSource returns the [TypeDef] that t refers to and true if t was declared by a use statement,
+// e.g. use wasi:io/streams.{input-stream} or use types.{a as b}, which refers to a type owned
+// by another interface. The name of the returned TypeDef is the name in the use statement;
+// if t is renamed with as, the name of t differs from the name of the source.
+// It returns nil and false if t is not a use, including a type alias such as type b = a,
+// which refers to a type with the same owner.
+//
+// Use statements and type aliases are both represented as a TypeDef whose Kind is another
+// TypeDef, as in the WIT JSON format, and are distinguished by their owners.
+func (t *TypeDef) UseSource() (*TypeDef, bool) {
+	src, ok := t.Kind.(*TypeDef)
+	if !ok || src.Owner == t.Owner {
+		return nil, false
+	}
+	return src, true
+}
+
 // Package returns the [Package] that t is associated with, if any.
 func (t *TypeDef) Package() *Package {
 	switch owner := t.Owner.(type) {
@@ -313,7 +341,8 @@ type Record struct {
 	Fields []Field
 }
 
-// Size returns the [ABI byte size] for [Record] r.
+// Size returns the [ABI byte size] for [Record] r,
+// including any trailing padding to the alignment of r.
 //
 // [ABI byte size]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#size
 func (r *Record) Size() uintptr {
@@ -322,7 +351,7 @@ func (r *Record) Size() uintptr {
 		s = Align(s, f.Type.Align())
 		s += f.Type.Size()
 	}
-	return s
+	return Align(s, r.Align())
 }
 
 // Align returns the [ABI byte alignment] for [Record] r.
@@ -652,7 +681,7 @@ func (v *Variant) Flat() []Type {
 	for _, t := range v.Types() {
 		for i, f := range t.Flat() {
 			if i >= len(flat) {
-				flat = append(flat, t)
+				flat = append(flat, f)
 			} else if f.Size() > flat[i].Size() {
 				flat[i] = f
 			}
@@ -890,7 +919,7 @@ func (*List) Size() uintptr { return 8 } // [2]int32
 // Align returns the [ABI byte alignment] a [List].
 //
 // [ABI byte alignment]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#alignment
-func (*List) Align() uintptr { return 8 } // [2]int32
+func (*List) Align() uintptr { return 4 } // [2]int32
 
 // Flat returns the [flattened] ABI representation of [List].
 //
@@ -1265,15 +1294,16 @@ type String struct{ _primitive[string] }
 // [function]: https://component-model.bytecodealliance.org/design/wit.html#functions
 type Function struct {
 	_worldItem
-	Name    string
-	Kind    FunctionKind
-	Params  []Param // arguments to the function
-	Results []Param // a function can have a single anonymous result, or > 1 named results
-	Docs    Docs
+	Name      string
+	Kind      FunctionKind
+	Params    []Param // arguments to the function
+	Results   []Param // a function can have a single anonymous result, or > 1 named results
+	Docs      Docs
+	Stability *Stability // WIT @since, @unstable, or @deprecated gates; may be nil
 }
 
 // BaseName returns the base name of [Function] f.
-// For static functions, this returns the function name unchanged.
+// For freestanding functions, this returns the function name unchanged.
 // For constructors, this removes the [constructor] and type prefix.
 // For static functions, this removes the [static] and type prefix.
 // For methods, this removes the [method] and type prefix.
@@ -1433,4 +1463,56 @@ type Package struct {
 // Docs represent WIT documentation text extracted from comments.
 type Docs struct {
 	Contents string // may be empty
+
+	// Comments are the other comments attached to a definition parsed from WIT text,
+	// which are printed with it. It is nil if there are none. Comments are not encoded in JSON.
+	Comments *Comments
+}
+
+// Comments are the comments in WIT text that are not doc comments, attached to
+// the nearest definition so they survive a round trip through a [Resolve].
+// Each comment is its source text, including the // or /* */ delimiters.
+type Comments struct {
+	// Leading are free-standing comments before a definition and its docs,
+	// separated from them by a blank line.
+	Leading []string
+
+	// Trailing is a comment on the same line as the end of a definition,
+	// e.g. f: func(); // trailing
+	Trailing string
+
+	// End are the comments before the closing brace of an interface, world, type,
+	// or nested package, or at the end of the file for a package.
+	End []string
+}
+
+// Stability represents the feature gates of a WIT item, declared with the
+// @since, @unstable, and @deprecated annotations.
+type Stability struct {
+	// Since is the package version that stabilized the item, from @since(version = ...).
+	Since *semver.Version
+
+	// Feature is the feature that gates an unstable item, from @unstable(feature = ...).
+	// It is empty if the item is stable.
+	Feature string
+
+	// Deprecated is the package version that deprecated the item, from @deprecated(version = ...).
+	Deprecated *semver.Version
+}
+
+// setStability sets the stability of name in *m to s, allocating *m if necessary.
+// It does nothing if s is nil.
+func setStability(m *map[string]*Stability, name string, s *Stability) {
+	if s == nil {
+		return
+	}
+	if *m == nil {
+		*m = make(map[string]*Stability)
+	}
+	(*m)[name] = s
+}
+
+// IsDeprecated returns true if s is non-nil and has a deprecation version.
+func (s *Stability) IsDeprecated() bool {
+	return s != nil && s.Deprecated != nil
 }
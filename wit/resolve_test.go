@@ -0,0 +1,112 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFunctionKinds(t *testing.T) {
+	name := "r"
+	r := &TypeDef{Name: &name, Kind: &Resource{}}
+	self := []Param{{Name: "self", Type: &TypeDef{Kind: &Borrow{Type: r}}}}
+	own := []Param{{Type: &TypeDef{Kind: &Own{Type: r}}}}
+
+	tests := []struct {
+		f             *Function
+		freestanding  bool
+		constructor   bool
+		method        bool
+		static        bool
+		baseName, wit string
+	}{
+		{&Function{Name: "f", Kind: &Freestanding{}}, true, false, false, false, "f", "f: func();"},
+		{&Function{Name: "[constructor]r", Kind: &Constructor{Type: r}, Results: own}, false, true, false, false, "constructor", "constructor();"},
+		{&Function{Name: "[method]r.get-name", Kind: &Method{Type: r}, Params: self, Results: []Param{{Type: String{}}}}, false, false, true, false, "get-name", "get-name: func() -> string;"},
+		{&Function{Name: "[static]r.make", Kind: &Static{Type: r}, Results: own}, false, false, false, true, "make", "make: static func() -> r;"},
+		{&Function{Name: "[method]r.no-self", Kind: &Method{Type: r}}, false, false, false, false, "no-self", "no-self: func();"},
+		{&Function{Name: "[static]r.nil", Kind: &Static{}}, false, false, false, false, "nil", "nil: static func();"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.f.Name, func(t *testing.T) {
+			if got, want := tt.f.IsFreestanding(), tt.freestanding; got != want {
+				t.Errorf("IsFreestanding(): %t, expected %t", got, want)
+			}
+			if got, want := tt.f.IsConstructor(), tt.constructor; got != want {
+				t.Errorf("IsConstructor(): %t, expected %t", got, want)
+			}
+			if got, want := tt.f.IsMethod(), tt.method; got != want {
+				t.Errorf("IsMethod(): %t, expected %t", got, want)
+			}
+			if got, want := tt.f.IsStatic(), tt.static; got != want {
+				t.Errorf("IsStatic(): %t, expected %t", got, want)
+			}
+			if got, want := tt.f.BaseName(), tt.baseName; got != want {
+				t.Errorf("BaseName(): %q, expected %q", got, want)
+			}
+			if got, want := tt.f.WIT(nil, ""), tt.wit; got != want {
+				t.Errorf("WIT(): %q, expected %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeDefUseSource(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{
+		"wit/use.wit": {Data: []byte(`package ex:uses;
+
+interface types {
+	record a { x: u32 }
+}
+
+interface consumer {
+	use types.{a as b, a};
+	type c = b;
+}
+
+world w {
+	use types.{a as wa};
+	import f: func(x: wa);
+}
+`)},
+	}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	types, err := res.Interface("ex:uses/types")
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer, err := res.Interface("ex:uses/consumer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := res.World("ex:uses/w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := types.TypeDefs.Get("a")
+
+	tests := []struct {
+		name string
+		t    *TypeDef
+		want *TypeDef
+	}{
+		{"renamed use", consumer.TypeDefs.Get("b"), a},
+		{"use", consumer.TypeDefs.Get("a"), a},
+		{"world use", w.Imports.Get("wa").(*TypeDef), a},
+		{"alias", consumer.TypeDefs.Get("c"), nil},
+		{"record", a, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.t.UseSource()
+			if got != tt.want || ok != (tt.want != nil) {
+				t.Errorf("UseSource(): %v, %t, expected %v", got, ok, tt.want)
+			}
+		})
+	}
+	if got := consumer.WIT(nil, ""); !strings.Contains(got, "use types.{a as b};") || !strings.Contains(got, "type c = b;") {
+		t.Errorf("Interface.WIT(): expected renamed use and alias:\n%s", got)
+	}
+}
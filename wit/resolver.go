@@ -0,0 +1,1142 @@
+package wit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// resolveWIT resolves parsed WIT packages into a [Resolve]. Packages are resolved in
+// dependency order: the nested packages of the root, deps sorted by name, then root.
+// Within a package, interfaces, worlds, and types are ordered as wasm-tools orders them.
+func resolveWIT(deps []*astPackage, root []*astPackage) (*Resolve, error) {
+	sort.SliceStable(deps, func(i, j int) bool {
+		return identLess(deps[i].name, deps[j].name)
+	})
+	all := append(root[1:len(root):len(root)], deps...)
+	all = append(all, root[0])
+	for _, p := range all {
+		if p.name == nil {
+			return nil, fmt.Errorf("%s: no package declaration", p.files[0].path)
+		}
+	}
+	all = topological(all, func(p *astPackage) []*astPackage {
+		var deps []*astPackage
+		for _, id := range p.references() {
+			if dep := matchASTPackage(all, id); dep != nil && dep != p {
+				deps = append(deps, dep)
+			}
+		}
+		return deps
+	})
+
+	r := &resolver{
+		res:            &Resolve{},
+		packages:       make(map[string]*Package),
+		interfaceIndex: make(map[*Interface]int),
+	}
+	for _, p := range all {
+		if err := r.resolvePackage(p); err != nil {
+			return nil, err
+		}
+	}
+	return r.res, nil
+}
+
+func identLess(a, b *Ident) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Package != b.Package {
+		return a.Package < b.Package
+	}
+	if a.Version == nil || b.Version == nil {
+		return a.Version == nil && b.Version != nil
+	}
+	return a.Version.LessThan(*b.Version)
+}
+
+// identMatches reports whether ref, which may be unversioned, refers to package id.
+func identMatches(ref, id Ident, candidates int) bool {
+	if ref.Namespace != id.Namespace || ref.Package != id.Package {
+		return false
+	}
+	if ref.Version == nil {
+		return id.Version == nil || candidates == 1
+	}
+	return id.Version != nil && ref.Version.Equal(*id.Version)
+}
+
+func matchASTPackage(pkgs []*astPackage, ref Ident) *astPackage {
+	var matches []*astPackage
+	for _, p := range pkgs {
+		if p.name.Namespace == ref.Namespace && p.name.Package == ref.Package {
+			matches = append(matches, p)
+		}
+	}
+	for _, p := range matches {
+		if identMatches(ref, *p.name, len(matches)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// references returns the names of the packages referred to by p.
+func (p *astPackage) references() []Ident {
+	var ids []Ident
+	add := func(path *astPath) {
+		if path.pkg != nil {
+			ids = append(ids, *path.pkg)
+		}
+	}
+	addInterface := func(i *astInterface) {
+		for _, item := range i.items {
+			if use, ok := item.(*astUse); ok {
+				add(&use.path)
+			}
+		}
+	}
+	for _, f := range p.files {
+		for _, use := range f.uses {
+			add(&use.path)
+		}
+		for _, i := range f.interfaces {
+			addInterface(i)
+		}
+		for _, w := range f.worlds {
+			for _, item := range w.items {
+				switch item := item.(type) {
+				case *astUse:
+					add(&item.path)
+				case *astInclude:
+					add(&item.path)
+				case *astWorldItem:
+					if item.path != nil {
+						add(item.path)
+					} else if item.iface != nil {
+						addInterface(item.iface)
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// resolver holds the state of resolving parsed packages into a [Resolve].
+type resolver struct {
+	res            *Resolve
+	packages       map[string]*Package // by name, including version
+	interfaceIndex map[*Interface]int  // index in res.Interfaces
+
+	// The package being resolved
+	pkg  *Package
+	anon map[string]*TypeDef // anonymous types in pkg, deduplicated by anonKey
+}
+
+func (r *resolver) resolvePackage(p *astPackage) error {
+	name := p.name.String()
+	if _, ok := r.packages[name]; ok {
+		return fmt.Errorf("%s: package %s is defined more than once", p.files[0].path, name)
+	}
+	r.pkg = &Package{Name: *p.name, Docs: p.docs}
+	r.anon = make(map[string]*TypeDef)
+	r.res.Packages = append(r.res.Packages, r.pkg)
+	r.packages[name] = r.pkg
+
+	// Interfaces and worlds share a namespace within a package.
+	var interfaces []*astInterface
+	var worlds []*astWorld
+	names := make(map[string]bool)
+	for _, f := range p.files {
+		for _, i := range f.interfaces {
+			if names[i.name] {
+				return fmt.Errorf("%s: %s is defined more than once in package %s", i.pos, i.name, name)
+			}
+			names[i.name] = true
+			interfaces = append(interfaces, i)
+		}
+		for _, w := range f.worlds {
+			if names[w.name] {
+				return fmt.Errorf("%s: %s is defined more than once in package %s", w.pos, w.name, name)
+			}
+			names[w.name] = true
+			worlds = append(worlds, w)
+		}
+	}
+
+	// Interfaces follow the interfaces they use.
+	interfaces, err := toposort(interfaces, func(i *astInterface) []*astInterface {
+		var deps []*astInterface
+		for _, item := range i.items {
+			if use, ok := item.(*astUse); ok {
+				if dep := localItem(r, i.file, use.path, interfaces); dep != nil {
+					deps = append(deps, dep)
+				}
+			}
+		}
+		return deps
+	})
+	if err != nil {
+		return fmt.Errorf("%s: interface %s depends on itself", err.pos, err.name)
+	}
+	for _, ai := range interfaces {
+		i, err := r.resolveInterface(ai, &ai.name)
+		if err != nil {
+			return err
+		}
+		r.pkg.Interfaces.Set(ai.name, i)
+	}
+
+	// Worlds follow the worlds they include.
+	worlds, err = toposort(worlds, func(w *astWorld) []*astWorld {
+		var deps []*astWorld
+		for _, item := range w.items {
+			if inc, ok := item.(*astInclude); ok {
+				if dep := localItem(r, w.file, inc.path, worlds); dep != nil {
+					deps = append(deps, dep)
+				}
+			}
+		}
+		return deps
+	})
+	if err != nil {
+		return fmt.Errorf("%s: world %s depends on itself", err.pos, err.name)
+	}
+	for _, aw := range worlds {
+		if err := r.resolveWorld(aw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// astNamed is an interface, world, or type definition in the syntax tree.
+type astNamed interface {
+	*astInterface | *astWorld | *astTypeDef
+}
+
+// cycleError reports a dependency cycle found by [toposort].
+type cycleError struct {
+	pos  Position
+	name string
+}
+
+// toposort returns items ordered so that each item follows its dependencies returned
+// by deps. Of the items whose dependencies are satisfied, the earliest in items is
+// placed first, matching wasm-tools. Dependencies not in items are ignored.
+func toposort[T astNamed](items []T, deps func(T) []T) ([]T, *cycleError) {
+	index := make(map[T]int, len(items))
+	for i, item := range items {
+		index[item] = i
+	}
+	counts := make([]int, len(items))
+	reverse := make([][]int, len(items))
+	for i, item := range items {
+		for _, dep := range deps(item) {
+			if j, ok := index[dep]; ok {
+				counts[i]++
+				reverse[j] = append(reverse[j], i)
+			}
+		}
+	}
+	var ready []int // indexes in items, sorted
+	for i, n := range counts {
+		if n == 0 {
+			ready = append(ready, i)
+		}
+	}
+	sorted := make([]T, 0, len(items))
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, items[i])
+		for _, j := range reverse[i] {
+			counts[j]--
+			if counts[j] == 0 {
+				k := sort.SearchInts(ready, j)
+				ready = append(ready[:k], append([]int{j}, ready[k:]...)...)
+			}
+		}
+	}
+	for i, n := range counts {
+		if n > 0 {
+			switch item := any(items[i]).(type) {
+			case *astInterface:
+				return nil, &cycleError{item.pos, item.name}
+			case *astWorld:
+				return nil, &cycleError{item.pos, item.name}
+			case *astTypeDef:
+				return nil, &cycleError{item.pos, item.name}
+			}
+		}
+	}
+	return sorted, nil
+}
+
+// localItem returns the interface or world in items referred to by path from file,
+// or nil if path refers to an item in another package.
+func localItem[T *astInterface | *astWorld](r *resolver, file *astFile, path astPath, items []T) T {
+	name, ok := r.localName(file, path)
+	if !ok {
+		return nil
+	}
+	for _, item := range items {
+		switch item := any(item).(type) {
+		case *astInterface:
+			if item.name == name {
+				return any(item).(T)
+			}
+		case *astWorld:
+			if item.name == name {
+				return any(item).(T)
+			}
+		}
+	}
+	return nil
+}
+
+// localName returns the name of the item in the current package referred to by path,
+// following top-level use statements in file.
+func (r *resolver) localName(file *astFile, path astPath) (string, bool) {
+	if path.pkg == nil {
+		if use := topUse(file, path.name); use != nil {
+			path = use.path
+		}
+	}
+	if path.pkg != nil && path.pkg.String() != r.pkg.Name.String() {
+		return "", false
+	}
+	return path.name, true
+}
+
+func topUse(file *astFile, name string) *astTopUse {
+	for _, use := range file.uses {
+		if use.as == name {
+			return use
+		}
+	}
+	return nil
+}
+
+// lookupPackage returns the resolved package named by ref, which may be unversioned.
+func (r *resolver) lookupPackage(ref Ident) *Package {
+	if p, ok := r.packages[ref.String()]; ok {
+		return p
+	}
+	var matches []*Package
+	for _, p := range r.res.Packages {
+		if p.Name.Namespace == ref.Namespace && p.Name.Package == ref.Package {
+			matches = append(matches, p)
+		}
+	}
+	for _, p := range matches {
+		if identMatches(ref, p.Name, len(matches)) {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *resolver) lookupInterface(file *astFile, path astPath) (*Interface, error) {
+	if name, ok := r.localName(file, path); ok {
+		if i := r.pkg.Interfaces.Get(name); i != nil {
+			return i, nil
+		}
+		return nil, fmt.Errorf("%s: interface %s not found in package %s", path.pos, name, r.pkg.Name.String())
+	}
+	if path.pkg == nil {
+		path = topUse(file, path.name).path
+	}
+	p := r.lookupPackage(*path.pkg)
+	if p == nil {
+		return nil, fmt.Errorf("%s: package %s not found", path.pos, path.pkg.String())
+	}
+	if i := p.Interfaces.Get(path.name); i != nil {
+		return i, nil
+	}
+	return nil, fmt.Errorf("%s: interface %s not found", path.pos, path.String())
+}
+
+func (r *resolver) lookupWorld(file *astFile, path astPath) (*World, error) {
+	if name, ok := r.localName(file, path); ok {
+		if w := r.pkg.Worlds.Get(name); w != nil {
+			return w, nil
+		}
+		return nil, fmt.Errorf("%s: world %s not found in package %s", path.pos, name, r.pkg.Name.String())
+	}
+	if path.pkg == nil {
+		path = topUse(file, path.name).path
+	}
+	p := r.lookupPackage(*path.pkg)
+	if p == nil {
+		return nil, fmt.Errorf("%s: package %s not found", path.pos, path.pkg.String())
+	}
+	if w := p.Worlds.Get(path.name); w != nil {
+		return w, nil
+	}
+	return nil, fmt.Errorf("%s: world %s not found", path.pos, path.String())
+}
+
+// interfaceKey returns the key of an imported or exported interface i in a [World].
+func (r *resolver) interfaceKey(i *Interface) string {
+	return "interface-" + strconv.Itoa(r.interfaceIndex[i])
+}
+
+// resolveInterface resolves a named interface, or an anonymous interface declared in a world.
+func (r *resolver) resolveInterface(ai *astInterface, name *string) (*Interface, error) {
+	i := &Interface{
+		Name:      name,
+		Package:   r.pkg,
+		Docs:      ai.docs,
+		Stability: ai.stability,
+	}
+	r.interfaceIndex[i] = len(r.res.Interfaces)
+	r.res.Interfaces = append(r.res.Interfaces, i)
+
+	scope := make(map[string]*TypeDef)
+	var typeDefs []*astTypeDef
+	for _, item := range ai.items {
+		switch item := item.(type) {
+		case *astUse:
+			err := r.resolveUse(ai.file, item, i, scope, func(td *TypeDef) {
+				i.TypeDefs.Set(*td.Name, td)
+			})
+			if err != nil {
+				return nil, err
+			}
+		case *astTypeDef:
+			typeDefs = append(typeDefs, item)
+		}
+	}
+	err := r.resolveTypeDefs(typeDefs, i, scope, func(td *TypeDef) {
+		i.TypeDefs.Set(*td.Name, td)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Functions, including resource functions, are in declaration order.
+	add := func(af *astFunc, resource *TypeDef) error {
+		f, err := r.resolveFunction(af, resource, scope)
+		if err != nil {
+			return err
+		}
+		if i.Functions.Get(f.Name) != nil {
+			return fmt.Errorf("%s: function %s is defined more than once", af.pos, f.Name)
+		}
+		i.Functions.Set(f.Name, f)
+		return nil
+	}
+	for _, item := range ai.items {
+		switch item := item.(type) {
+		case *astFunc:
+			if err := add(item, nil); err != nil {
+				return nil, err
+			}
+		case *astTypeDef:
+			if res, ok := item.kind.(*astResource); ok {
+				for _, af := range res.funcs {
+					if err := add(af, scope[item.name]); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return i, nil
+}
+
+// resolveUse resolves a use statement in an interface or world, creating a type alias
+// owned by owner for each name used.
+func (r *resolver) resolveUse(file *astFile, use *astUse, owner TypeOwner, scope map[string]*TypeDef, add func(*TypeDef)) error {
+	from, err := r.lookupInterface(file, use.path)
+	if err != nil {
+		return err
+	}
+	for _, n := range use.names {
+		target := from.TypeDefs.Get(n.name)
+		if target == nil {
+			return fmt.Errorf("%s: type %s not found in interface %s", n.pos, n.name, use.path.String())
+		}
+		if scope[n.as] != nil {
+			return fmt.Errorf("%s: type %s is defined more than once", n.pos, n.as)
+		}
+		name := n.as
+		td := &TypeDef{Name: &name, Kind: target, Owner: owner, Stability: use.stability}
+		r.res.TypeDefs = append(r.res.TypeDefs, td)
+		scope[name] = td
+		add(td)
+	}
+	return nil
+}
+
+// resolveTypeDefs resolves the type definitions in an interface or world,
+// in dependency order.
+func (r *resolver) resolveTypeDefs(typeDefs []*astTypeDef, owner TypeOwner, scope map[string]*TypeDef, add func(*TypeDef)) error {
+	byName := make(map[string]*astTypeDef, len(typeDefs))
+	for _, atd := range typeDefs {
+		if byName[atd.name] != nil || scope[atd.name] != nil {
+			return fmt.Errorf("%s: type %s is defined more than once", atd.pos, atd.name)
+		}
+		byName[atd.name] = atd
+	}
+	typeDefs, cycle := toposort(typeDefs, func(atd *astTypeDef) []*astTypeDef {
+		var deps []*astTypeDef
+		walkASTNames(atd.kind, func(name string) {
+			if dep := byName[name]; dep != nil {
+				deps = append(deps, dep)
+			}
+		})
+		return deps
+	})
+	if cycle != nil {
+		return fmt.Errorf("%s: type %s depends on itself", cycle.pos, cycle.name)
+	}
+	for _, atd := range typeDefs {
+		kind, err := r.resolveTypeDefKind(atd.kind, scope)
+		if err != nil {
+			return err
+		}
+		name := atd.name
+		td := &TypeDef{
+			Name:      &name,
+			Kind:      kind,
+			Owner:     owner,
+			Docs:      atd.docs,
+			Stability: atd.stability,
+		}
+		r.res.TypeDefs = append(r.res.TypeDefs, td)
+		scope[name] = td
+		add(td)
+	}
+	return nil
+}
+
+// walkASTNames calls f with each type name referred to by t.
+func walkASTNames(t any, f func(name string)) {
+	switch t := t.(type) {
+	case *astName:
+		f(t.name)
+	case *astHandle:
+		f(t.name.name)
+	case *astRecord:
+		for _, field := range t.fields {
+			walkASTNames(field.typ, f)
+		}
+	case *astVariant:
+		for _, c := range t.cases {
+			walkASTNames(c.typ, f)
+		}
+	case *astList:
+		walkASTNames(t.typ, f)
+	case *astOption:
+		walkASTNames(t.typ, f)
+	case *astResult:
+		walkASTNames(t.ok, f)
+		walkASTNames(t.err, f)
+	case *astTuple:
+		for _, t := range t.types {
+			walkASTNames(t, f)
+		}
+	case *astFuture:
+		walkASTNames(t.typ, f)
+	case *astStream:
+		walkASTNames(t.typ, f)
+		walkASTNames(t.end, f)
+	}
+}
+
+// resolveTypeDefKind resolves the kind of a named type definition.
+// A type alias refers directly to its target, even if the target is a resource.
+func (r *resolver) resolveTypeDefKind(kind any, scope map[string]*TypeDef) (TypeDefKind, error) {
+	switch kind := kind.(type) {
+	case *astRecord:
+		var fields []Field
+		for _, af := range kind.fields {
+			t, err := r.resolveType(af.typ, scope)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, Field{Name: af.name, Type: t, Docs: af.docs})
+		}
+		if err := checkNames(kind.fields, "field"); err != nil {
+			return nil, err
+		}
+		return &Record{Fields: fields}, nil
+	case *astVariant:
+		var cases []Case
+		for _, ac := range kind.cases {
+			var t Type
+			if ac.typ != nil {
+				var err error
+				if t, err = r.resolveType(ac.typ, scope); err != nil {
+					return nil, err
+				}
+			}
+			cases = append(cases, Case{Name: ac.name, Type: t, Docs: ac.docs})
+		}
+		if err := checkNames(kind.cases, "case"); err != nil {
+			return nil, err
+		}
+		return &Variant{Cases: cases}, nil
+	case *astEnum:
+		var cases []EnumCase
+		for _, ac := range kind.cases {
+			cases = append(cases, EnumCase{Name: ac.name, Docs: ac.docs})
+		}
+		if err := checkNames(kind.cases, "case"); err != nil {
+			return nil, err
+		}
+		return &Enum{Cases: cases}, nil
+	case *astFlags:
+		var flags []Flag
+		for _, af := range kind.flags {
+			flags = append(flags, Flag{Name: af.name, Docs: af.docs})
+		}
+		if err := checkNames(kind.flags, "flag"); err != nil {
+			return nil, err
+		}
+		return &Flags{Flags: flags}, nil
+	case *astResource:
+		return &Resource{}, nil
+	case *astName:
+		return r.lookupType(kind, scope)
+	case Primitive:
+		return kind, nil
+	}
+	return r.resolveTypeKind(kind, scope)
+}
+
+func checkNames(fields []astField, what string) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if seen[f.name] {
+			return fmt.Errorf("%s: %s %s is defined more than once", f.pos, what, f.name)
+		}
+		seen[f.name] = true
+	}
+	return nil
+}
+
+func (r *resolver) lookupType(name *astName, scope map[string]*TypeDef) (*TypeDef, error) {
+	td := scope[name.name]
+	if td == nil {
+		return nil, fmt.Errorf("%s: type %s does not exist", name.pos, name.name)
+	}
+	return td, nil
+}
+
+// resolveType resolves a type reference. A reference to a resource by name,
+// directly or through a type alias, is an owned handle to the resource.
+func (r *resolver) resolveType(t astType, scope map[string]*TypeDef) (Type, error) {
+	switch t := t.(type) {
+	case Primitive:
+		return t, nil
+	case *astName:
+		td, err := r.lookupType(t, scope)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := td.Root().Kind.(*Resource); ok {
+			return r.anonType(&Own{Type: td}), nil
+		}
+		return td, nil
+	}
+	kind, err := r.resolveTypeKind(t, scope)
+	if err != nil {
+		return nil, err
+	}
+	return r.anonType(kind), nil
+}
+
+// resolveTypeKind resolves the kind of an anonymous type, e.g. list<u8>.
+func (r *resolver) resolveTypeKind(t astType, scope map[string]*TypeDef) (TypeDefKind, error) {
+	var err error
+	switch t := t.(type) {
+	case *astList:
+		var k List
+		k.Type, err = r.resolveType(t.typ, scope)
+		return &k, err
+	case *astOption:
+		var k Option
+		k.Type, err = r.resolveType(t.typ, scope)
+		return &k, err
+	case *astResult:
+		var k Result
+		if t.ok != nil {
+			if k.OK, err = r.resolveType(t.ok, scope); err != nil {
+				return nil, err
+			}
+		}
+		if t.err != nil {
+			k.Err, err = r.resolveType(t.err, scope)
+		}
+		return &k, err
+	case *astTuple:
+		var k Tuple
+		for _, t := range t.types {
+			tt, err := r.resolveType(t, scope)
+			if err != nil {
+				return nil, err
+			}
+			k.Types = append(k.Types, tt)
+		}
+		return &k, nil
+	case *astHandle:
+		td, err := r.lookupType(&t.name, scope)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := td.Root().Kind.(*Resource); !ok {
+			return nil, fmt.Errorf("%s: type %s is not a resource", t.name.pos, t.name.name)
+		}
+		if t.borrow {
+			return &Borrow{Type: td}, nil
+		}
+		return &Own{Type: td}, nil
+	case *astFuture:
+		var k Future
+		if t.typ != nil {
+			k.Type, err = r.resolveType(t.typ, scope)
+		}
+		return &k, err
+	case *astStream:
+		var k Stream
+		if t.typ != nil {
+			if k.Element, err = r.resolveType(t.typ, scope); err != nil {
+				return nil, err
+			}
+		}
+		if t.end != nil {
+			k.End, err = r.resolveType(t.end, scope)
+		}
+		return &k, err
+	}
+	return nil, fmt.Errorf("BUG: unknown type %T", t)
+}
+
+// anonType returns an anonymous [TypeDef] of kind, reusing an identical type in the current package.
+func (r *resolver) anonType(kind TypeDefKind) *TypeDef {
+	key := anonKey(kind)
+	if td := r.anon[key]; td != nil {
+		return td
+	}
+	td := &TypeDef{Kind: kind}
+	r.res.TypeDefs = append(r.res.TypeDefs, td)
+	r.anon[key] = td
+	return td
+}
+
+func anonKey(kind TypeDefKind) string {
+	key := func(t Type) string {
+		switch t := t.(type) {
+		case nil:
+			return "_"
+		case *TypeDef:
+			return fmt.Sprintf("%p", t)
+		}
+		return t.TypeName()
+	}
+	switch kind := kind.(type) {
+	case *List:
+		return "list<" + key(kind.Type) + ">"
+	case *Option:
+		return "option<" + key(kind.Type) + ">"
+	case *Result:
+		return "result<" + key(kind.OK) + "," + key(kind.Err) + ">"
+	case *Tuple:
+		s := "tuple<"
+		for _, t := range kind.Types {
+			s += key(t) + ","
+		}
+		return s + ">"
+	case *Own:
+		return "own<" + key(kind.Type) + ">"
+	case *Borrow:
+		return "borrow<" + key(kind.Type) + ">"
+	case *Future:
+		return "future<" + key(kind.Type) + ">"
+	case *Stream:
+		return "stream<" + key(kind.Element) + "," + key(kind.End) + ">"
+	}
+	return fmt.Sprintf("%p", kind)
+}
+
+// resolveFunction resolves a freestanding function, or a function of resource.
+func (r *resolver) resolveFunction(af *astFunc, resource *TypeDef, scope map[string]*TypeDef) (*Function, error) {
+	f := &Function{Docs: af.docs, Stability: af.stability}
+	switch af.kind {
+	case "freestanding":
+		f.Name = af.name
+		f.Kind = &Freestanding{}
+	case "constructor":
+		f.Name = "[constructor]" + *resource.Name
+		f.Kind = &Constructor{Type: resource}
+		f.Results = []Param{{Type: r.anonType(&Own{Type: resource})}}
+	case "method":
+		f.Name = "[method]" + *resource.Name + "." + af.name
+		f.Kind = &Method{Type: resource}
+		f.Params = []Param{{Name: "self", Type: r.anonType(&Borrow{Type: resource})}}
+	case "static":
+		f.Name = "[static]" + *resource.Name + "." + af.name
+		f.Kind = &Static{Type: resource}
+	}
+	params := af.params
+	if af.kind == "method" {
+		params = append([]astField{{name: "self"}}, params...)
+	}
+	if err := checkNames(params, "parameter"); err != nil {
+		return nil, err
+	}
+	for _, p := range af.params {
+		t, err := r.resolveType(p.typ, scope)
+		if err != nil {
+			return nil, err
+		}
+		f.Params = append(f.Params, Param{Name: p.name, Type: t})
+	}
+	for _, p := range af.results {
+		t, err := r.resolveType(p.typ, scope)
+		if err != nil {
+			return nil, err
+		}
+		f.Results = append(f.Results, Param{Name: p.name, Type: t})
+	}
+	return f, nil
+}
+
+// worldEntry is an import or export of a [World] during resolution.
+type worldEntry struct {
+	key       string
+	item      WorldItem
+	stability *Stability // gates of an interface, or nil
+
+	// pos is the position of the declaration or include of the entry, and from is
+	// the path of the included world it came from, or "" if declared in the world.
+	pos  Position
+	from string
+}
+
+// describe returns a description of e for errors, e.g. import of f from world v included at a.wit:3.
+func (e *worldEntry) describe(motion string) string {
+	if e.from == "" {
+		return fmt.Sprintf("%s %s declared at %s", motion, e.key, e.pos)
+	}
+	return fmt.Sprintf("%s of %s from world %s included at %s", motion, e.key, e.from, e.pos)
+}
+
+func (r *resolver) resolveWorld(aw *astWorld) error {
+	w := &World{
+		Name:      aw.name,
+		Package:   r.pkg,
+		Docs:      aw.docs,
+		Stability: aw.stability,
+	}
+	r.res.Worlds = append(r.res.Worlds, w)
+	r.pkg.Worlds.Set(aw.name, w)
+
+	var imports, exports []worldEntry
+	add := func(entries *[]worldEntry, key string, item WorldItem, stability *Stability, pos Position) error {
+		for _, e := range *entries {
+			if e.key == key {
+				return fmt.Errorf("%s: %s is defined more than once in world %s", pos, key, aw.name)
+			}
+		}
+		*entries = append(*entries, worldEntry{key: key, item: item, stability: stability, pos: pos})
+		return nil
+	}
+
+	scope := make(map[string]*TypeDef)
+	typePos := make(map[string]Position)
+	var typeDefs []*astTypeDef
+	for _, item := range aw.items {
+		switch item := item.(type) {
+		case *astUse:
+			for _, n := range item.names {
+				typePos[n.as] = n.pos
+			}
+			err := r.resolveUse(aw.file, item, w, scope, func(td *TypeDef) {
+				imports = append(imports, worldEntry{key: *td.Name, item: td, pos: typePos[*td.Name]})
+			})
+			if err != nil {
+				return err
+			}
+		case *astTypeDef:
+			typePos[item.name] = item.pos
+			typeDefs = append(typeDefs, item)
+		}
+	}
+	err := r.resolveTypeDefs(typeDefs, w, scope, func(td *TypeDef) {
+		imports = append(imports, worldEntry{key: *td.Name, item: td, pos: typePos[*td.Name]})
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range aw.items {
+		switch item := item.(type) {
+		case *astWorldItem:
+			entries := &imports
+			if item.export {
+				entries = &exports
+			}
+			var err error
+			switch {
+			case item.path != nil:
+				var i *Interface
+				if i, err = r.lookupInterface(aw.file, *item.path); err == nil {
+					err = add(entries, r.interfaceKey(i), i, item.stability, item.pos)
+				}
+			case item.iface != nil:
+				var i *Interface
+				if i, err = r.resolveInterface(item.iface, nil); err == nil {
+					err = add(entries, item.iface.name, i, i.Stability, item.pos)
+				}
+			case item.fn != nil:
+				var f *Function
+				if f, err = r.resolveFunction(item.fn, nil, scope); err == nil {
+					err = add(entries, f.Name, f, nil, item.pos)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		case *astTypeDef:
+			if res, ok := item.kind.(*astResource); ok {
+				for _, af := range res.funcs {
+					f, err := r.resolveFunction(af, scope[item.name], scope)
+					if err != nil {
+						return err
+					}
+					if err := add(&imports, f.Name, f, nil, af.pos); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	for _, item := range aw.items {
+		if inc, ok := item.(*astInclude); ok {
+			if err := r.include(aw, inc, &imports, &exports); err != nil {
+				return err
+			}
+		}
+	}
+
+	return r.elaborateWorld(w, imports, exports)
+}
+
+// include merges the imports and exports of the world included by inc.
+// Interfaces imported or exported by both worlds are merged; other names must not conflict.
+// A conflict is reported with the declaration or include of both entries, as the later
+// entry would otherwise replace the earlier one when the world is elaborated.
+func (r *resolver) include(aw *astWorld, inc *astInclude, imports, exports *[]worldEntry) error {
+	from, err := r.lookupWorld(aw.file, inc.path)
+	if err != nil {
+		return err
+	}
+	renames := make(map[string]string, len(inc.names))
+	for _, n := range inc.names {
+		renames[n.name] = n.as
+	}
+	used := make(map[string]bool, len(inc.names))
+	merge := func(entries *[]worldEntry, items func(func(string, WorldItem) bool), stability map[string]*Stability, motion string) error {
+		var err error
+		items(func(key string, item WorldItem) bool {
+			s := stability[key]
+			if i, ok := item.(*Interface); ok && key == r.interfaceKey(i) {
+				for _, e := range *entries {
+					if e.key == key {
+						return true
+					}
+				}
+				*entries = append(*entries, worldEntry{key: key, item: item, stability: s, pos: inc.path.pos, from: inc.path.String()})
+				return true
+			}
+			name := key
+			if as, ok := renames[key]; ok {
+				used[key] = true
+				key = as
+			}
+			for _, e := range *entries {
+				if e.key == key {
+					err = fmt.Errorf("%s: %s of %s from world %s conflicts with %s; rename it with include %s with { %s as ... }",
+						inc.path.pos, motion, key, inc.path.String(), e.describe(motion), inc.path.String(), escape(name))
+					return false
+				}
+			}
+			*entries = append(*entries, worldEntry{key: key, item: item, stability: s, pos: inc.path.pos, from: inc.path.String()})
+			return true
+		})
+		return err
+	}
+	if err := merge(imports, from.Imports.All(), from.ImportStability, "import"); err != nil {
+		return err
+	}
+	if err := merge(exports, from.Exports.All(), from.ExportStability, "export"); err != nil {
+		return err
+	}
+	for _, n := range inc.names {
+		if !used[n.name] {
+			return fmt.Errorf("%s: %s not found in world %s", n.pos, n.name, inc.path.String())
+		}
+	}
+	return nil
+}
+
+// elaborateWorld sets the imports and exports of w, adding the interfaces that
+// its imports and exports depend on, in the order that wasm-tools elaborates worlds.
+func (r *resolver) elaborateWorld(w *World, imports, exports []worldEntry) error {
+	sort.SliceStable(imports, func(i, j int) bool {
+		return worldItemOrder(imports[i].item) < worldItemOrder(imports[j].item)
+	})
+
+	var newImports []worldEntry
+	has := func(entries []worldEntry, key string) bool {
+		for _, e := range entries {
+			if e.key == key {
+				return true
+			}
+		}
+		return false
+	}
+	var importInterface func(key string, i *Interface)
+	importInterface = func(key string, i *Interface) {
+		if has(newImports, key) {
+			return
+		}
+		for _, dep := range interfaceDeps(i) {
+			importInterface(r.interfaceKey(dep), dep)
+		}
+		newImports = append(newImports, worldEntry{key: key, item: i})
+	}
+	for _, e := range imports {
+		switch item := e.item.(type) {
+		case *Interface:
+			importInterface(e.key, item)
+		case *TypeDef:
+			if dep := typeInterfaceDep(item); dep != nil {
+				importInterface(r.interfaceKey(dep), dep)
+			}
+			newImports = append(newImports, e)
+		case *Function:
+			newImports = append(newImports, e)
+		}
+	}
+
+	// Exported functions precede exported interfaces. Interfaces that exported
+	// interfaces depend on are imported unless they are also exported.
+	var newExports []worldEntry
+	exported := make(map[*Interface]bool)
+	for _, e := range exports {
+		switch item := e.item.(type) {
+		case *Function:
+			newExports = append(newExports, e)
+		case *Interface:
+			exported[item] = true
+		}
+	}
+	required := make(map[*Interface]bool)
+	var exportInterface func(key string, i *Interface, export bool) bool
+	exportInterface = func(key string, i *Interface, export bool) bool {
+		if has(newExports, key) {
+			return export
+		}
+		if !export && required[i] {
+			return true
+		}
+		for _, dep := range interfaceDeps(i) {
+			if !exportInterface(r.interfaceKey(dep), dep, export && exported[dep]) {
+				return false
+			}
+		}
+		if export {
+			if required[i] {
+				return false
+			}
+			newExports = append(newExports, worldEntry{key: key, item: i})
+		} else {
+			required[i] = true
+			if !has(newImports, key) {
+				newImports = append(newImports, worldEntry{key: key, item: i})
+			}
+		}
+		return true
+	}
+	for _, e := range exports {
+		if i, ok := e.item.(*Interface); ok && !exportInterface(e.key, i, true) {
+			return fmt.Errorf("world %s: export %s depends on an interface that is both imported and exported",
+				w.Name, interfacePathName(i))
+		}
+	}
+
+	sort.SliceStable(newImports, func(i, j int) bool {
+		return worldItemOrder(newImports[i].item) < worldItemOrder(newImports[j].item)
+	})
+	for _, e := range newImports {
+		w.Imports.Set(e.key, e.item)
+		setStability(&w.ImportStability, e.key, entryStability(imports, e.key))
+	}
+	for _, e := range newExports {
+		w.Exports.Set(e.key, e.item)
+		setStability(&w.ExportStability, e.key, entryStability(exports, e.key))
+	}
+	return nil
+}
+
+// entryStability returns the stability of the entry with key in entries, or nil.
+// Interfaces added to a world as dependencies have no stability of their own.
+func entryStability(entries []worldEntry, key string) *Stability {
+	for _, e := range entries {
+		if e.key == key {
+			return e.stability
+		}
+	}
+	return nil
+}
+
+// worldItemOrder returns the sort order of a world import: interfaces,
+// then types used from interfaces, then other types, then freestanding
+// functions, then resource functions.
+func worldItemOrder(item WorldItem) int {
+	switch item := item.(type) {
+	case *Interface:
+		return 0
+	case *TypeDef:
+		if t, ok := item.Kind.(*TypeDef); ok && t.Owner != item.Owner {
+			return 1
+		}
+		return 2
+	case *Function:
+		if item.IsFreestanding() {
+			return 3
+		}
+		return 4
+	}
+	return 5
+}
+
+// typeInterfaceDep returns the interface that owns the target of type alias td, if any.
+func typeInterfaceDep(td *TypeDef) *Interface {
+	if t, ok := td.Kind.(*TypeDef); ok {
+		if i, ok := t.Owner.(*Interface); ok {
+			return i
+		}
+	}
+	return nil
+}
+
+// interfaceDeps returns the interfaces that i uses types from, in order of use.
+func interfaceDeps(i *Interface) []*Interface {
+	var deps []*Interface
+	i.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+		if dep := typeInterfaceDep(td); dep != nil && dep != i {
+			deps = append(deps, dep)
+		}
+		return true
+	})
+	return deps
+}
@@ -0,0 +1,262 @@
+package wit
+
+import (
+	"fmt"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Retarget rewrites each reference to an interface or type in a package named name,
+// such as "wasi:io", to refer to the same interface or type in the version of that
+// package specified by version, such as "0.2.0". This is used to unify WIT that
+// refers to more than one version of a package, for example a world that imports
+// wasi:io/streams@0.2.1 and exports wasi:http/incoming-handler@0.2.0, which uses
+// wasi:io/streams@0.2.0.
+//
+// The package with the target version must be in res, and contain each interface
+// and named type in the other versions of the package that is referenced.
+// Other versions of the package are removed from res, along with their interfaces,
+// types, and worlds. World imports and exports of interfaces in other versions are
+// renamed to the target version; if a world imports or exports an interface in more
+// than one version, they are combined into the first.
+//
+// If Retarget returns an error, res is not modified.
+func (res *Resolve) Retarget(name string, version string) error {
+	id, err := ParsePackageName(name)
+	if err != nil {
+		return fmt.Errorf("retarget: %w", err)
+	}
+	if id.Version != nil {
+		return fmt.Errorf("retarget %s: expected a package name without a version, e.g. wasi:io", name)
+	}
+	id.Version, err = semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("retarget %s: %w", name, err)
+	}
+
+	var target *Package
+	var others []*Package
+	for _, pkg := range res.Packages {
+		if pkg.Name.Namespace != id.Namespace || pkg.Name.Package != id.Package {
+			continue
+		}
+		if pkg.Name.Version != nil && pkg.Name.Version.Equal(*id.Version) {
+			target = pkg
+		} else {
+			others = append(others, pkg)
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("retarget %s: package %s not found", name, id.String())
+	}
+
+	// Map each definition in other versions to its counterpart in the target version.
+	// Definitions that are missing in the target are an error only if they are used.
+	m := &merger{
+		packages:   make(map[*Package]*Package),
+		interfaces: make(map[*Interface]*Interface),
+		worlds:     make(map[*World]*World),
+		typeDefs:   make(map[*TypeDef]*TypeDef),
+	}
+	removed := make(map[TypeOwner]bool)
+	missing := make(map[Node]string)
+	for _, pkg := range others {
+		m.packages[pkg] = target
+		pkg.Interfaces.All()(func(iname string, i *Interface) bool {
+			removed[i] = true
+			ti, ok := target.Interfaces.GetOK(iname)
+			if !ok {
+				missing[i] = "interface " + interfacePathName(i)
+				return true
+			}
+			m.interfaces[i] = ti
+			i.TypeDefs.All()(func(tname string, t *TypeDef) bool {
+				if tt, ok := ti.TypeDefs.GetOK(tname); ok {
+					m.typeDefs[t] = tt
+				} else {
+					missing[t] = "type " + typeDefPathName(t)
+				}
+				return true
+			})
+			return true
+		})
+		pkg.Worlds.All()(func(_ string, w *World) bool {
+			removed[w] = true
+			return true
+		})
+	}
+
+	// Check that no remaining definition uses a definition missing from the target.
+	// The first index of a definition in any version of the package is recorded,
+	// so the target version can be moved before definitions that used other versions.
+	inTarget := func(o TypeOwner) bool { return typeOwnerPackage(o) == target }
+	firstTypeDef, firstInterface := len(res.TypeDefs), len(res.Interfaces)
+	var typeDefs []*TypeDef
+	for _, t := range res.TypeDefs {
+		if (removed[t.Owner] || inTarget(t.Owner)) && firstTypeDef > len(typeDefs) {
+			firstTypeDef = len(typeDefs)
+		}
+		if removed[t.Owner] {
+			continue
+		}
+		typeDefs = append(typeDefs, t)
+		for _, child := range typeDefChildren(t) {
+			if err := checkRetarget(missing, child, id); err != nil {
+				return err
+			}
+		}
+	}
+	var interfaces []*Interface
+	for _, i := range res.Interfaces {
+		if (removed[i] || inTarget(i)) && firstInterface > len(interfaces) {
+			firstInterface = len(interfaces)
+		}
+		if removed[i] {
+			continue
+		}
+		interfaces = append(interfaces, i)
+		var err error
+		i.Functions.All()(func(_ string, f *Function) bool {
+			err = checkRetargetFunction(missing, f, id)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	var worlds []*World
+	for _, w := range res.Worlds {
+		if removed[w] {
+			continue
+		}
+		worlds = append(worlds, w)
+		var err error
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			items.All()(func(_ string, item WorldItem) bool {
+				switch item := item.(type) {
+				case *Interface:
+					err = checkRetarget(missing, item, id)
+				case *TypeDef:
+					for _, child := range typeDefChildren(item) {
+						if err = checkRetarget(missing, child, id); err != nil {
+							break
+						}
+					}
+				case *Function:
+					err = checkRetargetFunction(missing, item, id)
+				}
+				return err == nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	interfaces = moveUp(interfaces, firstInterface, func(i *Interface) bool { return inTarget(i) })
+	typeDefs = moveUp(typeDefs, firstTypeDef, func(t *TypeDef) bool { return inTarget(t.Owner) })
+
+	// Rewrite references and remove the other versions.
+	for _, t := range typeDefs {
+		m.rewriteTypeDef(t)
+	}
+	for _, i := range interfaces {
+		m.rewriteInterface(i)
+	}
+	for _, w := range worlds {
+		m.rewriteWorld(w)
+		retargetWorldItems(&w.Imports, &w.ImportStability)
+		retargetWorldItems(&w.Exports, &w.ExportStability)
+	}
+	var packages []*Package
+	for _, pkg := range res.Packages {
+		if _, ok := m.packages[pkg]; !ok {
+			packages = append(packages, pkg)
+		}
+	}
+	res.Packages = packages
+	res.Packages = res.TopologicalPackages() // The target version may follow packages that used other versions.
+	res.Worlds = worlds
+	res.Interfaces = interfaces
+	res.TypeDefs = typeDefs
+	return nil
+}
+
+// checkRetarget returns an error if node is an interface or type that Retarget cannot map to the target package.
+func checkRetarget(missing map[Node]string, node Node, target Ident) error {
+	if what, ok := missing[node]; ok {
+		return fmt.Errorf("retarget %s: %s not found in %s", target.UnversionedString(), what, target.String())
+	}
+	return nil
+}
+
+func checkRetargetFunction(missing map[Node]string, f *Function, target Ident) error {
+	var types []Type
+	switch kind := f.Kind.(type) {
+	case *Method:
+		types = append(types, kind.Type)
+	case *Static:
+		types = append(types, kind.Type)
+	case *Constructor:
+		types = append(types, kind.Type)
+	}
+	for _, p := range f.Params {
+		types = append(types, p.Type)
+	}
+	for _, p := range f.Results {
+		types = append(types, p.Type)
+	}
+	for _, t := range types {
+		if td, ok := t.(*TypeDef); ok {
+			if err := checkRetarget(missing, td, target); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// retargetWorldItems renames the keys of interfaces in items to match their
+// (retargeted) interface IDs, combining duplicate interfaces into the first,
+// and renames their keys in stability.
+func retargetWorldItems(items *ordered.Map[string, WorldItem], stability *map[string]*Stability) {
+	var out ordered.Map[string, WorldItem]
+	var outStability map[string]*Stability
+	seen := make(map[*Interface]bool)
+	items.All()(func(key string, item WorldItem) bool {
+		s := (*stability)[key]
+		if i, ok := item.(*Interface); ok && i.Name != nil {
+			if seen[i] {
+				return true
+			}
+			seen[i] = true
+			if id, ok := i.ID(); ok {
+				key = id.String()
+			}
+		}
+		out.Set(key, item)
+		setStability(&outStability, key, s)
+		return true
+	})
+	*items = out
+	*stability = outStability
+}
+
+// moveUp returns items with the elements at or after index i for which move returns true
+// moved to index i, preserving the order of moved and other elements.
+func moveUp[T any](items []T, i int, move func(T) bool) []T {
+	out := make([]T, 0, len(items))
+	out = append(out, items[:i]...)
+	for _, v := range items[i:] {
+		if move(v) {
+			out = append(out, v)
+		}
+	}
+	for _, v := range items[i:] {
+		if !move(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
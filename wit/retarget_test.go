@@ -0,0 +1,174 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func retargetFS() fstest.MapFS {
+	return fstest.MapFS{
+		"wit/app.wit": {Data: []byte(`package ex:app;
+
+interface body {
+	use wasi:io/streams@0.2.1.{input-stream};
+	read: func(s: borrow<input-stream>) -> list<u8>;
+}
+
+world app {
+	import wasi:io/streams@0.2.1;
+	import body;
+	export wasi:http/incoming-handler@0.2.0;
+}
+`)},
+		"wit/deps/io-0.2.0/streams.wit": {Data: []byte(`package wasi:io@0.2.0;
+
+interface streams {
+	resource input-stream;
+}
+`)},
+		"wit/deps/io-0.2.1/streams.wit": {Data: []byte(`package wasi:io@0.2.1;
+
+interface streams {
+	resource input-stream;
+	resource output-stream;
+}
+`)},
+		"wit/deps/http/http.wit": {Data: []byte(`package wasi:http@0.2.0;
+
+interface types {
+	use wasi:io/streams@0.2.0.{input-stream};
+	body: func() -> input-stream;
+}
+
+interface incoming-handler {
+	use types.{input-stream};
+	handle: func(s: input-stream);
+}
+`)},
+	}
+}
+
+func TestRetarget(t *testing.T) {
+	res, err := LoadFS(retargetFS(), "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `world app {
+	import wasi:io/streams@0.2.1;
+	import body;
+	import wasi:io/streams@0.2.0;
+	import wasi:http/types@0.2.0;
+	export wasi:http/incoming-handler@0.2.0;
+}`
+	if got := res.Worlds[0].WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+
+	err = res.Retarget("wasi:io", "0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	want = `package wasi:io@0.2.1;
+
+interface streams {
+	resource input-stream;
+	resource output-stream;
+}
+
+
+package wasi:http@0.2.0;
+
+interface types {
+	use wasi:io/streams@0.2.1.{input-stream};
+	body: func() -> input-stream;
+}
+
+interface incoming-handler {
+	use types.{input-stream};
+	handle: func(s: input-stream);
+}
+
+
+package ex:app;
+
+interface body {
+	use wasi:io/streams@0.2.1.{input-stream};
+	read: func(s: borrow<input-stream>) -> list<u8>;
+}
+
+world app {
+	import wasi:io/streams@0.2.1;
+	import body;
+	import wasi:http/types@0.2.0;
+	export wasi:http/incoming-handler@0.2.0;
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestRetargetOlder(t *testing.T) {
+	res, err := LoadFS(retargetFS(), "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = res.Retarget("wasi:io", "0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := res.Verify(); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+	for _, p := range res.Packages {
+		if p.Name.String() == "wasi:io@0.2.1" {
+			t.Errorf("package %s not removed", p.Name.String())
+		}
+	}
+	got := res.WIT(nil, "")
+	for _, want := range []string{
+		"\tuse wasi:io/streams@0.2.0.{input-stream};\n\tread: func",
+		"\timport wasi:io/streams@0.2.0;\n\timport body;\n\timport wasi:http/types@0.2.0;\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WIT does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRetargetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     string
+		version string
+		want    string
+	}{
+		{"version in name", "wasi:io@0.2.0", "0.2.0", "expected a package name without a version"},
+		{"invalid version", "wasi:io", "x", "retarget wasi:io"},
+		{"not found", "wasi:io", "0.3.0", "package wasi:io@0.3.0 not found"},
+		{"missing type", "wasi:io", "0.2.0", "type wasi:io/streams@0.2.1#output-stream not found in wasi:io@0.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := retargetFS()
+			fsys["wit/app.wit"].Data = []byte(strings.Replace(string(fsys["wit/app.wit"].Data),
+				"list<u8>;", "list<u8>;\n\tuse wasi:io/streams@0.2.1.{output-stream};\n\twrite: func(s: borrow<output-stream>);", 1))
+			res, err := LoadFS(fsys, "wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := res.WIT(nil, "")
+			err = res.Retarget(tt.pkg, tt.version)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Retarget: %v, expected an error containing %q", err, tt.want)
+			}
+			if after := res.WIT(nil, ""); after != before {
+				t.Errorf("Retarget modified res after an error:\n%s", after)
+			}
+		})
+	}
+}
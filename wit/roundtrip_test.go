@@ -0,0 +1,517 @@
+package wit
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+var roundTrips = flag.Int("roundtrips", 200, "number of random resolves to round-trip in TestRoundTripRandom")
+
+// TestRoundTripRandom generates random valid resolves, prints each as WIT, parses it,
+// and verifies that the parsed resolve is equivalent and prints the same WIT.
+// Run with -roundtrips to check more resolves.
+func TestRoundTripRandom(t *testing.T) {
+	for seed := int64(0); seed < int64(*roundTrips); seed++ {
+		checkRoundTrip(t, seed)
+	}
+}
+
+// FuzzRoundTrip round-trips the random resolve generated from each seed.
+// Failing seeds found by go test -fuzz are written to testdata/fuzz/FuzzRoundTrip
+// and should be committed as regression tests once fixed.
+func FuzzRoundTrip(f *testing.F) {
+	for seed := int64(0); seed < 8; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkRoundTrip(t, seed)
+	})
+}
+
+// checkRoundTrip checks that the random resolve generated from seed is valid,
+// and that it survives printing and parsing.
+func checkRoundTrip(t *testing.T, seed int64) {
+	t.Helper()
+	res := randomResolve(rand.New(rand.NewSource(seed)))
+	if err := res.Verify(); err != nil {
+		t.Fatalf("seed %d: Verify: %v", seed, err)
+	}
+	want := res.WIT(nil, "")
+	got, err := LoadFS(splitPackages(want), ".")
+	if err != nil {
+		t.Fatalf("seed %d: LoadFS: %v\n%s", seed, err, want)
+	}
+	if err := got.Verify(); err != nil {
+		t.Errorf("seed %d: Verify parsed: %v", seed, err)
+	}
+	if diff := diffResolves(res, got); len(diff) > 0 {
+		t.Errorf("seed %d: parsed resolve differs:\n\t%s\nWIT:\n%s", seed, strings.Join(diff, "\n\t"), want)
+	}
+	if s := got.WIT(nil, ""); s != want {
+		t.Errorf("seed %d: WIT after round trip:\n%s\nexpected:\n%s", seed, s, want)
+	}
+}
+
+// diffResolves returns a description of each semantic difference between want and got.
+// Packages, interfaces, worlds, types, and functions are matched by name. Declaration order
+// is compared where WIT preserves it, except for the types and functions of an interface,
+// which the printer groups by kind.
+func diffResolves(want, got *Resolve) []string {
+	var diff []string
+	check := func(path, kind string, want, got string) {
+		if got != want {
+			diff = append(diff, fmt.Sprintf("%s %s: %v, expected %v", path, kind, got, want))
+		}
+	}
+	pkgNames := func(res *Resolve) []string {
+		var names []string
+		for _, p := range res.Packages {
+			names = append(names, p.Name.String())
+		}
+		return names
+	}
+	check("resolve", "packages", quote(pkgNames(want)), quote(pkgNames(got)))
+	if len(diff) > 0 {
+		return diff
+	}
+	for i, wp := range want.Packages {
+		gp := got.Packages[i]
+		path := wp.Name.String()
+		check(path, "docs", wp.Docs.Contents, gp.Docs.Contents)
+		check(path, "interfaces", quote(keys(&wp.Interfaces)), quote(keys(&gp.Interfaces)))
+		check(path, "worlds", quote(keys(&wp.Worlds)), quote(keys(&gp.Worlds)))
+		wp.Interfaces.All()(func(name string, wi *Interface) bool {
+			if gi := gp.Interfaces.Get(name); gi != nil {
+				diff = append(diff, diffInterfaces(wi, gi)...)
+			}
+			return true
+		})
+		wp.Worlds.All()(func(name string, ww *World) bool {
+			gw := gp.Worlds.Get(name)
+			if gw == nil {
+				return true
+			}
+			path := path + "/" + name
+			check(path, "docs", ww.Docs.Contents, gw.Docs.Contents)
+			check(path, "stability", ww.Stability.WIT(nil, ""), gw.Stability.WIT(nil, ""))
+			check(path, "imports", quote(worldItems(&ww.Imports)), quote(worldItems(&gw.Imports)))
+			check(path, "exports", quote(worldItems(&ww.Exports)), quote(worldItems(&gw.Exports)))
+			return true
+		})
+	}
+	return diff
+}
+
+func diffInterfaces(want, got *Interface) []string {
+	var diff []string
+	path := interfacePathName(want)
+	check := func(path, kind string, want, got string) {
+		if got != want {
+			diff = append(diff, fmt.Sprintf("%s %s: %v, expected %v", path, kind, got, want))
+		}
+	}
+	check(path, "docs", want.Docs.Contents, got.Docs.Contents)
+	check(path, "stability", want.Stability.WIT(nil, ""), got.Stability.WIT(nil, ""))
+	check(path, "fingerprint", want.Fingerprint(), got.Fingerprint())
+	check(path, "types", quote(typeDecls(want)), quote(typeDecls(got)))
+	check(path, "functions", quote(functionDecls(want)), quote(functionDecls(got)))
+	return diff
+}
+
+// typeDecls returns a sorted description of each type declared in i.
+func typeDecls(i *Interface) []string {
+	var decls []string
+	i.TypeDefs.All()(func(name string, td *TypeDef) bool {
+		var b strings.Builder
+		b.WriteString(name)
+		if alias, ok := td.Kind.(*TypeDef); ok && alias.Name != nil {
+			b.WriteString(" = ")
+			b.WriteString(typeDefPathName(alias))
+		} else {
+			b.WriteString(": ")
+			fingerprintType(&b, td)
+		}
+		decls = append(decls, b.String()+docsAndGates(td.Docs, td.Stability))
+		return true
+	})
+	slices.Sort(decls)
+	return decls
+}
+
+// functionDecls returns a sorted description of each function in i.
+func functionDecls(i *Interface) []string {
+	var decls []string
+	i.Functions.All()(func(_ string, f *Function) bool {
+		var b strings.Builder
+		f.fingerprint(&b)
+		decls = append(decls, b.String()+docsAndGates(f.Docs, f.Stability))
+		return true
+	})
+	slices.Sort(decls)
+	return decls
+}
+
+func docsAndGates(docs Docs, s *Stability) string {
+	return " " + strconv.Quote(docs.Contents) + " " + strconv.Quote(s.WIT(nil, ""))
+}
+
+// worldItems returns a description of each item in m, with interfaces identified by name
+// rather than by key, which depends on the order of interfaces in a [Resolve].
+func worldItems(m *ordered.Map[string, WorldItem]) []string {
+	var items []string
+	m.All()(func(name string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *Interface:
+			if item.Name != nil {
+				name = interfacePathName(item)
+			}
+			items = append(items, "interface "+name)
+		case *Function:
+			var b strings.Builder
+			item.fingerprint(&b)
+			items = append(items, b.String()+docsAndGates(item.Docs, item.Stability))
+		case *TypeDef:
+			items = append(items, "type "+name)
+		}
+		return true
+	})
+	return items
+}
+
+func quote(s []string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+// randomResolve returns a random valid [Resolve], built as the resolver would build it from
+// WIT, with one to three packages. Each package depends only on the packages before it,
+// and each interface uses types only from the interfaces before it.
+func randomResolve(r *rand.Rand) *Resolve {
+	g := &resolveGen{r: r, res: &Resolve{}}
+	namespace := g.pick("rt", "test", "world")
+	n := 1 + r.Intn(3)
+	for i := 0; i < n; i++ {
+		name := Ident{Namespace: namespace, Package: "dep-" + string(rune('a'+i))}
+		if i == n-1 {
+			name.Package = g.pick("root", "main", "interface")
+		}
+		if r.Intn(2) == 0 {
+			name.Version = &semver.Version{Minor: int64(1 + r.Intn(3))}
+		}
+		g.pkg(name, i == n-1)
+	}
+	return g.res
+}
+
+// resolveGen generates a random [Resolve].
+type resolveGen struct {
+	r   *rand.Rand
+	res *Resolve
+}
+
+// names are the words in generated names, including keywords, which must be escaped.
+var names = []string{
+	"a", "b", "count", "id", "name", "value", "get-URL", "http-request", "x", "y",
+	"as", "bool", "borrow", "constructor", "enum", "export", "flags", "func", "import",
+	"interface", "list", "option", "own", "package", "record", "resource", "result",
+	"static", "string", "tuple", "type", "u8", "use", "variant", "with", "world",
+}
+
+func (g *resolveGen) pick(s ...string) string {
+	return s[g.r.Intn(len(s))]
+}
+
+// name returns a random name that is not in used, and adds it to used.
+func (g *resolveGen) name(used map[string]bool) string {
+	name := g.pick(names...)
+	for n := 2; used[name]; n++ {
+		name = g.pick(names...) + "-n" + strconv.Itoa(n)
+	}
+	used[name] = true
+	return name
+}
+
+func (g *resolveGen) docs() Docs {
+	switch g.r.Intn(4) {
+	case 0:
+		return Docs{Contents: "Docs for " + g.pick(names...) + "."}
+	case 1:
+		return Docs{Contents: "First line.\nSecond line with `code`."}
+	}
+	return Docs{}
+}
+
+// stability returns random feature gates for an item in pkg. Only items that
+// no other item refers to may be unstable, otherwise wasm-tools rejects the WIT
+// unless the feature is enabled.
+func (g *resolveGen) stability(pkg *Package, unstable bool) *Stability {
+	switch g.r.Intn(6) {
+	case 0:
+		if pkg.Name.Version != nil {
+			return &Stability{Since: pkg.Name.Version}
+		}
+	case 1:
+		if !unstable {
+			break
+		}
+		s := &Stability{Feature: g.pick("alpha", "beta-two")}
+		if g.r.Intn(2) == 0 {
+			s.Deprecated = &semver.Version{Major: 1}
+		}
+		return s
+	}
+	return nil
+}
+
+func (g *resolveGen) pkg(name Ident, root bool) {
+	p := &Package{Name: name, Docs: g.docs()}
+	g.res.Packages = append(g.res.Packages, p)
+	used := make(map[string]bool)
+	n := 1 + g.r.Intn(3)
+	for i := 0; i < n; i++ {
+		g.iface(p, g.name(used))
+	}
+	if root && g.r.Intn(2) == 0 {
+		g.world(p, g.name(used))
+	}
+}
+
+// anon returns a new anonymous type of kind.
+func (g *resolveGen) anon(kind TypeDefKind) *TypeDef {
+	td := &TypeDef{Kind: kind}
+	g.res.TypeDefs = append(g.res.TypeDefs, td)
+	return td
+}
+
+var primitives = []Type{Bool{}, S8{}, U8{}, S16{}, U16{}, S32{}, U32{}, S64{}, U64{}, F32{}, F64{}, Char{}, String{}}
+
+// typ returns a random type that refers only to the named types in scope.
+// Resources in scope are referred to by handle; borrow handles are permitted only in params.
+func (g *resolveGen) typ(scope []*TypeDef, depth int, param bool) Type {
+	switch n := g.r.Intn(10); {
+	case n < 2 && len(scope) > 0:
+		td := scope[g.r.Intn(len(scope))]
+		if _, ok := td.Root().Kind.(*Resource); !ok {
+			return td
+		}
+		if param && g.r.Intn(2) == 0 {
+			return g.anon(&Borrow{Type: td})
+		}
+		return g.anon(&Own{Type: td})
+	case n < 6 && depth > 0:
+		switch g.r.Intn(4) {
+		case 0:
+			return g.anon(&List{Type: g.typ(scope, depth-1, false)})
+		case 1:
+			return g.anon(&Option{Type: g.typ(scope, depth-1, false)})
+		case 2:
+			res := &Result{}
+			if g.r.Intn(3) > 0 {
+				res.OK = g.typ(scope, depth-1, false)
+			}
+			if g.r.Intn(3) > 0 {
+				res.Err = g.typ(scope, depth-1, false)
+			}
+			return g.anon(res)
+		default:
+			tup := &Tuple{}
+			for i := g.r.Intn(3); i >= 0; i-- {
+				tup.Types = append(tup.Types, g.typ(scope, depth-1, false))
+			}
+			return g.anon(tup)
+		}
+	}
+	return primitives[g.r.Intn(len(primitives))]
+}
+
+func (g *resolveGen) iface(p *Package, name string) {
+	i := &Interface{Name: &name, Package: p, Docs: g.docs(), Stability: g.stability(p, false)}
+	used := make(map[string]bool)
+	var scope []*TypeDef
+
+	// Use types from earlier interfaces.
+	if len(g.res.Interfaces) > 0 {
+		for n := g.r.Intn(3); n > 0; n-- {
+			from := g.res.Interfaces[g.r.Intn(len(g.res.Interfaces))]
+			types := keys(&from.TypeDefs)
+			if len(types) == 0 {
+				continue
+			}
+			t := from.TypeDefs.Get(types[g.r.Intn(len(types))])
+			local := *t.Name
+			if used[local] || g.r.Intn(3) == 0 {
+				local = g.name(used)
+			}
+			used[local] = true
+			td := &TypeDef{Name: &local, Kind: t, Owner: i}
+			g.res.TypeDefs = append(g.res.TypeDefs, td)
+			i.TypeDefs.Set(local, td)
+			scope = append(scope, td)
+		}
+	}
+
+	// Declare types, each referring to the types before it.
+	for n := g.r.Intn(6); n > 0; n-- {
+		td := g.typeDef(p, i, g.name(used), scope)
+		i.TypeDefs.Set(*td.Name, td)
+		scope = append(scope, td)
+		if _, ok := td.Kind.(*Resource); ok {
+			g.resourceFunctions(p, i, td, scope)
+		}
+	}
+
+	for n := g.r.Intn(4); n > 0; n-- {
+		f := g.function(p, g.name(used), &Freestanding{}, scope)
+		i.Functions.Set(f.Name, f)
+	}
+
+	g.res.Interfaces = append(g.res.Interfaces, i)
+	p.Interfaces.Set(name, i)
+}
+
+func (g *resolveGen) typeDef(p *Package, owner TypeOwner, name string, scope []*TypeDef) *TypeDef {
+	td := &TypeDef{Name: &name, Owner: owner, Docs: g.docs(), Stability: g.stability(p, false)}
+	used := make(map[string]bool)
+	switch g.r.Intn(7) {
+	case 0:
+		kind := &Record{}
+		for n := 1 + g.r.Intn(3); n > 0; n-- {
+			kind.Fields = append(kind.Fields, Field{Name: g.name(used), Type: g.typ(scope, 2, false), Docs: g.docs()})
+		}
+		td.Kind = kind
+	case 1:
+		kind := &Variant{}
+		for n := 1 + g.r.Intn(3); n > 0; n-- {
+			c := Case{Name: g.name(used), Docs: g.docs()}
+			if g.r.Intn(2) == 0 {
+				c.Type = g.typ(scope, 2, false)
+			}
+			kind.Cases = append(kind.Cases, c)
+		}
+		td.Kind = kind
+	case 2:
+		kind := &Enum{}
+		for n := 1 + g.r.Intn(4); n > 0; n-- {
+			kind.Cases = append(kind.Cases, EnumCase{Name: g.name(used), Docs: g.docs()})
+		}
+		td.Kind = kind
+	case 3:
+		kind := &Flags{}
+		for n := 1 + g.r.Intn(4); n > 0; n-- {
+			kind.Flags = append(kind.Flags, Flag{Name: g.name(used), Docs: g.docs()})
+		}
+		td.Kind = kind
+	case 4, 5:
+		td.Kind = &Resource{}
+	default:
+		// An alias of a named type, a primitive, or an anonymous type.
+		switch t := g.typ(scope, 2, false).(type) {
+		case *TypeDef:
+			if t.Name != nil {
+				td.Kind = t
+			} else {
+				td.Kind = t.Kind
+				g.res.TypeDefs = slices.DeleteFunc(g.res.TypeDefs, func(td *TypeDef) bool { return td == t })
+			}
+		default:
+			td.Kind = t.(TypeDefKind)
+		}
+	}
+	g.res.TypeDefs = append(g.res.TypeDefs, td)
+	return td
+}
+
+// resourceFunctions adds a constructor, methods, and static functions for resource r to i.
+func (g *resolveGen) resourceFunctions(p *Package, i *Interface, r *TypeDef, scope []*TypeDef) {
+	used := make(map[string]bool)
+	if g.r.Intn(2) == 0 {
+		f := g.function(p, "[constructor]"+*r.Name, &Constructor{Type: r}, scope)
+		f.Results = []Param{{Type: g.anon(&Own{Type: r})}}
+		i.Functions.Set(f.Name, f)
+	}
+	for n := g.r.Intn(3); n > 0; n-- {
+		f := g.function(p, "[method]"+*r.Name+"."+g.name(used), &Method{Type: r}, scope)
+		f.Params = append([]Param{{Name: "self", Type: g.anon(&Borrow{Type: r})}}, f.Params...)
+		i.Functions.Set(f.Name, f)
+	}
+	for n := g.r.Intn(2); n > 0; n-- {
+		f := g.function(p, "[static]"+*r.Name+"."+g.name(used), &Static{Type: r}, scope)
+		i.Functions.Set(f.Name, f)
+	}
+}
+
+func (g *resolveGen) function(p *Package, name string, kind FunctionKind, scope []*TypeDef) *Function {
+	f := &Function{Name: name, Kind: kind, Docs: g.docs(), Stability: g.stability(p, true)}
+	used := map[string]bool{"self": true}
+	for n := g.r.Intn(4); n > 0; n-- {
+		f.Params = append(f.Params, Param{Name: g.name(used), Type: g.typ(scope, 2, true)})
+	}
+	if g.r.Intn(3) > 0 {
+		f.Results = []Param{{Type: g.typ(scope, 2, false)}}
+	}
+	return f
+}
+
+// world adds a world to p that imports and exports random interfaces and functions,
+// elaborated as the resolver elaborates a world: each imported interface follows the
+// interfaces it depends on, and the dependencies of exported interfaces are imported.
+func (g *resolveGen) world(p *Package, name string) {
+	w := &World{Name: name, Package: p, Docs: g.docs(), Stability: g.stability(p, true)}
+	key := func(i *Interface) string {
+		return "interface-" + strconv.Itoa(slices.Index(g.res.Interfaces, i))
+	}
+	var importInterface func(i *Interface)
+	importInterface = func(i *Interface) {
+		if w.Imports.Get(key(i)) != nil {
+			return
+		}
+		for _, dep := range interfaceDeps(i) {
+			importInterface(dep)
+		}
+		w.Imports.Set(key(i), i)
+	}
+	for n := g.r.Intn(3); n > 0; n-- {
+		importInterface(g.res.Interfaces[g.r.Intn(len(g.res.Interfaces))])
+	}
+
+	var exports []*Interface
+	for n := g.r.Intn(2); n > 0; n-- {
+		i := g.res.Interfaces[g.r.Intn(len(g.res.Interfaces))]
+		if w.Imports.Get(key(i)) == nil {
+			for _, dep := range interfaceDeps(i) {
+				importInterface(dep)
+			}
+			exports = append(exports, i)
+		}
+	}
+
+	used := make(map[string]bool)
+	w.Imports.All()(func(_ string, item WorldItem) bool {
+		used[*item.(*Interface).Name] = true
+		return true
+	})
+	for _, i := range exports {
+		used[*i.Name] = true
+	}
+	for n := g.r.Intn(3); n > 0; n-- {
+		f := g.function(p, g.name(used), &Freestanding{}, nil)
+		w.Imports.Set(f.Name, f)
+	}
+	for n := g.r.Intn(3); n > 0; n-- {
+		f := g.function(p, g.name(used), &Freestanding{}, nil)
+		w.Exports.Set(f.Name, f)
+	}
+	for _, i := range exports {
+		w.Exports.Set(key(i), i)
+	}
+
+	g.res.Worlds = append(g.res.Worlds, w)
+	p.Worlds.Set(name, w)
+}
@@ -0,0 +1,354 @@
+package wit
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Position is the location of a declaration in a WIT source file.
+type Position struct {
+	// Path is the path of the WIT file, as found by [FindSources].
+	Path string
+
+	// Line is the 1-based line number of the declaration.
+	Line int
+}
+
+// String returns p in the form path:line, e.g. wit/world.wit:12.
+func (p Position) String() string {
+	return p.Path + ":" + strconv.Itoa(p.Line)
+}
+
+// SourceMap maps definitions in a [Resolve] to the location of their declarations
+// in WIT source files. Keys are *[World], *[Interface], *[TypeDef], or *[Function] values.
+type SourceMap map[Node]Position
+
+// FindSources scans the WIT files at paths, each of which may be a .wit file or a directory,
+// and returns a [SourceMap] for the worlds, interfaces, named types, and functions in res
+// declared in them. Directories are scanned recursively, including any deps directory.
+//
+// WIT JSON does not record source locations, so FindSources locates declarations by
+// scanning the WIT text, then matches each to a definition in res by package and name.
+// Declarations without a matching definition in res are ignored.
+func FindSources(res *Resolve, paths ...string) (SourceMap, error) {
+	var decls []sourceDecl
+	for _, path := range paths {
+		dirs := make(map[string][]string)
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(path, ".wit") {
+				dir := filepath.Dir(path)
+				dirs[dir] = append(dirs[dir], path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, files := range dirs {
+			d, err := scanSourceDir(files)
+			if err != nil {
+				return nil, err
+			}
+			decls = append(decls, d...)
+		}
+	}
+	return matchSources(res, decls), nil
+}
+
+// sourceDecl is a declaration found by scanning WIT text.
+type sourceDecl struct {
+	pos   Position
+	pkg   string // declared package name, e.g. wasi:io@0.2.0
+	kind  string // "interface", "world", "type", or "function"
+	owner string // name of the interface or world that contains a type or function
+	name  string // name of the declaration; functions use the names in WIT JSON, e.g. [method]r.f
+}
+
+// scanSourceDir scans the WIT files in a single package directory.
+// Files without a package declaration belong to the package declared by another file.
+func scanSourceDir(files []string) ([]sourceDecl, error) {
+	var decls []sourceDecl
+	var dirPkg string
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		d, pkg := scanSource(filepath.ToSlash(path), src)
+		if pkg != "" && dirPkg == "" {
+			dirPkg = pkg
+		}
+		decls = append(decls, d...)
+	}
+	for i := range decls {
+		if decls[i].pkg == "" {
+			decls[i].pkg = dirPkg
+		}
+	}
+	return decls, nil
+}
+
+// scanSource scans WIT source src from path for declarations.
+// It returns the declarations and the package declared at the top of the file, if any.
+// Scanning is lenient: malformed WIT yields fewer declarations rather than an error.
+func scanSource(path string, src []byte) (decls []sourceDecl, filePkg string) {
+	toks := tokenize(string(src))
+
+	// Each brace-delimited block is either a package, interface, world, resource, or other block.
+	type block struct {
+		kind string // "package", "interface", "world", "resource", or ""
+		name string
+		pkg  string
+	}
+	stack := []block{{kind: "package"}}
+	var pending block
+
+	for i := 0; i < len(toks); i++ {
+		top := &stack[len(stack)-1]
+		tok := toks[i]
+		next := func(n int) string {
+			if i+n < len(toks) {
+				return toks[i+n].text
+			}
+			return ""
+		}
+		add := func(kind, owner, name string, line int) {
+			decls = append(decls, sourceDecl{
+				pos:   Position{Path: path, Line: line},
+				pkg:   top.pkg,
+				kind:  kind,
+				owner: owner,
+				name:  name,
+			})
+		}
+
+		switch tok.text {
+		case "{":
+			if pending.kind == "" {
+				pending.pkg = top.pkg
+			}
+			stack = append(stack, pending)
+			pending = block{}
+			continue
+		case "}":
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			pending = block{}
+			continue
+		case ";":
+			pending = block{}
+			continue
+		}
+
+		switch top.kind {
+		case "package":
+			switch {
+			case tok.text == "package":
+				var name strings.Builder
+				for i++; i < len(toks) && toks[i].text != ";" && toks[i].text != "{"; i++ {
+					name.WriteString(toks[i].text)
+				}
+				if i < len(toks) && toks[i].text == "{" {
+					// Nested package block.
+					stack = append(stack, block{kind: "package", pkg: name.String()})
+				} else {
+					// Later package declarations, as printed by [Resolve.WIT], apply to following declarations.
+					top.pkg = name.String()
+					if filePkg == "" {
+						filePkg = top.pkg
+					}
+				}
+			case (tok.text == "interface" || tok.text == "world") && isSourceName(next(1)) && next(2) == "{":
+				name := sourceName(next(1))
+				add(tok.text, "", name, tok.line)
+				pending = block{kind: tok.text, name: name, pkg: top.pkg}
+				i++
+			}
+
+		case "interface", "world":
+			switch {
+			case tok.text == "use":
+				// use path.{a, b as c}; declares types a and c.
+				for i++; i < len(toks) && toks[i].text != "{" && toks[i].text != ";"; i++ {
+				}
+				for i++; i < len(toks) && toks[i].text != "}" && toks[i].text != ";"; i++ {
+					if !isSourceName(toks[i].text) || toks[i].text == "as" || next(1) == "as" {
+						continue
+					}
+					add("type", top.name, sourceName(toks[i].text), toks[i].line)
+				}
+			case isTypeKeyword(tok.text) && isSourceName(next(1)):
+				name := sourceName(next(1))
+				add("type", top.name, name, tok.line)
+				if tok.text == "resource" {
+					pending = block{kind: "resource", name: name, pkg: top.pkg}
+				}
+				i++
+			case isSourceName(tok.text) && next(1) == ":" && isFuncKeyword(next(2)):
+				add("function", top.name, sourceName(tok.text), tok.line)
+				i += 2
+			}
+
+		case "resource":
+			switch {
+			case tok.text == "constructor" && next(1) == "(":
+				add("function", stack[len(stack)-2].name, "[constructor]"+top.name, tok.line)
+			case isSourceName(tok.text) && next(1) == ":" && next(2) == "static":
+				add("function", stack[len(stack)-2].name, "[static]"+top.name+"."+sourceName(tok.text), tok.line)
+				i += 2
+			case isSourceName(tok.text) && next(1) == ":" && isFuncKeyword(next(2)):
+				add("function", stack[len(stack)-2].name, "[method]"+top.name+"."+sourceName(tok.text), tok.line)
+				i += 2
+			}
+		}
+	}
+	return decls, filePkg
+}
+
+func isTypeKeyword(s string) bool {
+	switch s {
+	case "record", "variant", "enum", "flags", "resource", "type":
+		return true
+	}
+	return false
+}
+
+func isFuncKeyword(s string) bool {
+	return s == "func" || s == "async"
+}
+
+// isSourceName reports whether s is a WIT identifier, optionally %-escaped.
+func isSourceName(s string) bool {
+	s = strings.TrimPrefix(s, "%")
+	if s == "" {
+		return false
+	}
+	c := s[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// sourceName returns identifier s without a leading %.
+func sourceName(s string) string {
+	return strings.TrimPrefix(s, "%")
+}
+
+// sourceToken is a token in WIT source text.
+type sourceToken struct {
+	text string
+	line int
+}
+
+// tokenize splits WIT source text into identifiers, numbers, and single-character punctuation,
+// skipping whitespace and comments.
+func tokenize(src string) []sourceToken {
+	var toks []sourceToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case isWordByte(c):
+			j := i
+			for j < len(src) && isWordByte(src[j]) {
+				j++
+			}
+			toks = append(toks, sourceToken{src[i:j], line})
+			i = j
+		default:
+			toks = append(toks, sourceToken{src[i : i+1], line})
+			i++
+		}
+	}
+	return toks
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '%'
+}
+
+// matchSources matches declarations to definitions in res.
+func matchSources(res *Resolve, decls []sourceDecl) SourceMap {
+	packages := make(map[string]*Package, len(res.Packages))
+	for _, pkg := range res.Packages {
+		packages[pkg.Name.String()] = pkg
+	}
+	m := make(SourceMap)
+	for _, d := range decls {
+		id, err := ParseIdent(d.pkg)
+		if err != nil {
+			continue
+		}
+		pkg := packages[id.String()]
+		if pkg == nil {
+			continue
+		}
+		var node Node
+		switch d.kind {
+		case "interface":
+			if i := pkg.Interfaces.Get(d.name); i != nil {
+				node = i
+			}
+		case "world":
+			if w := pkg.Worlds.Get(d.name); w != nil {
+				node = w
+			}
+		case "type", "function":
+			node = matchSourceItem(pkg, d)
+		}
+		if node != nil {
+			m[node] = d.pos
+		}
+	}
+	return m
+}
+
+// matchSourceItem returns the type or function declared by d in an interface or world in pkg.
+func matchSourceItem(pkg *Package, d sourceDecl) Node {
+	if i := pkg.Interfaces.Get(d.owner); i != nil {
+		if d.kind == "type" {
+			if td := i.TypeDefs.Get(d.name); td != nil {
+				return td
+			}
+		} else if f := i.Functions.Get(d.name); f != nil {
+			return f
+		}
+		return nil
+	}
+	if w := pkg.Worlds.Get(d.owner); w != nil {
+		for _, item := range []WorldItem{w.Imports.Get(d.name), w.Exports.Get(d.name)} {
+			switch item := item.(type) {
+			case *TypeDef:
+				if d.kind == "type" {
+					return item
+				}
+			case *Function:
+				if d.kind == "function" {
+					return item
+				}
+			}
+		}
+	}
+	return nil
+}
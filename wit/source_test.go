@@ -0,0 +1,150 @@
+package wit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "world.wit")
+	const src = `package example:app@0.1.0;
+
+/// Docs.
+interface api {
+	/* A block
+	   comment. */
+	record point { x: u32, y: u32 }
+	resource %stream {
+		constructor();
+		read: func() -> list<u8>;
+		open: static func() -> %stream;
+	}
+	get: func(p: point) -> u32;
+}
+
+world app {
+	import api;
+	type id = u32;
+	export run: func(id: id);
+}
+`
+	err := os.WriteFile(path, []byte(src), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := DecodeJSON(strings.NewReader(sourcesJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := FindSources(res, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := res.Interfaces[0]
+	w := res.Worlds[0]
+	tests := []struct {
+		node Node
+		line int
+	}{
+		{api, 4},
+		{api.TypeDefs.Get("point"), 7},
+		{api.TypeDefs.Get("stream"), 8},
+		{api.Functions.Get("[constructor]stream"), 9},
+		{api.Functions.Get("[method]stream.read"), 10},
+		{api.Functions.Get("[static]stream.open"), 11},
+		{api.Functions.Get("get"), 13},
+		{w, 16},
+		{w.Imports.Get("id").(Node), 18},
+		{w.Exports.Get("run").(Node), 19},
+	}
+	for _, tt := range tests {
+		want := Position{Path: filepath.ToSlash(path), Line: tt.line}
+		if got := sources[tt.node]; got != want {
+			t.Errorf("%s %s: %v, expected %v", tt.node.WITKind(), tt.node.WIT(nil, ""), got, want)
+		}
+	}
+}
+
+// TestFindSourcesTestdata verifies that each named definition in the testdata
+// is found in its golden WIT file, on a line that contains its name.
+func TestFindSourcesTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			golden := path + ".golden.wit"
+			sources, err := FindSources(res, golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(string(data), "\n")
+			check := func(node Node, name string) {
+				pos, ok := sources[node]
+				if !ok {
+					t.Errorf("%s %s: not found", node.WITKind(), name)
+					return
+				}
+				if !strings.Contains(lines[pos.Line-1], name) {
+					t.Errorf("%s %s: found at line %d: %q", node.WITKind(), name, pos.Line, lines[pos.Line-1])
+				}
+			}
+			for _, pkg := range res.Packages {
+				pkg.Interfaces.All()(func(name string, i *Interface) bool {
+					check(i, name)
+					i.TypeDefs.All()(func(name string, td *TypeDef) bool {
+						check(td, name)
+						return true
+					})
+					i.Functions.All()(func(_ string, f *Function) bool {
+						check(f, f.BaseName())
+						return true
+					})
+					return true
+				})
+				pkg.Worlds.All()(func(name string, w *World) bool {
+					check(w, name)
+					return true
+				})
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+const sourcesJSON = `{
+	"worlds": [{
+		"name": "app",
+		"imports": {"interface-0": {"interface": 0}, "id": {"type": 5}},
+		"exports": {"run": {"function": {"name": "run", "kind": "freestanding", "params": [{"name": "id", "type": 5}], "results": []}}},
+		"package": 0
+	}],
+	"interfaces": [{
+		"name": "api",
+		"types": {"point": 0, "stream": 1},
+		"functions": {
+			"[constructor]stream": {"name": "[constructor]stream", "kind": {"constructor": 1}, "params": [], "results": [{"type": 3}]},
+			"[method]stream.read": {"name": "[method]stream.read", "kind": {"method": 1}, "params": [{"name": "self", "type": 2}], "results": [{"type": 4}]},
+			"[static]stream.open": {"name": "[static]stream.open", "kind": {"static": 1}, "params": [], "results": [{"type": 3}]},
+			"get": {"name": "get", "kind": "freestanding", "params": [{"name": "p", "type": 0}], "results": [{"type": "u32"}]}
+		},
+		"package": 0
+	}],
+	"types": [
+		{"name": "point", "kind": {"record": {"fields": [{"name": "x", "type": "u32"}, {"name": "y", "type": "u32"}]}}, "owner": {"interface": 0}},
+		{"name": "stream", "kind": "resource", "owner": {"interface": 0}},
+		{"name": null, "kind": {"handle": {"borrow": 1}}, "owner": null},
+		{"name": null, "kind": {"handle": {"own": 1}}, "owner": null},
+		{"name": null, "kind": {"list": "u8"}, "owner": null},
+		{"name": "id", "kind": {"type": "u32"}, "owner": {"world": 0}}
+	],
+	"packages": [{"name": "example:app@0.1.0", "interfaces": {"api": 0}, "worlds": {"app": 0}}]
+}`
@@ -0,0 +1,356 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ydnar/wasm-tools-go/wit/iterate"
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// StripOptions specifies what [Resolve.Strip] removes from a [Resolve].
+type StripOptions struct {
+	// Docs removes the documentation of each definition.
+	Docs bool
+
+	// Unstable removes worlds, interfaces, types, and functions gated by @unstable,
+	// unless their feature is in Features.
+	Unstable bool
+
+	// Features are the features whose @unstable items are kept if Unstable is set.
+	Features []string
+
+	// World, if non-nil, removes each world other than World, and each interface
+	// and type not reachable from its imports and exports.
+	World *World
+}
+
+// Strip removes documentation, unstable items, or items not reachable from a world
+// from res, as specified by opts, producing minimal WIT, for example to embed in a binary
+// or to publish. Packages left without interfaces or worlds are removed. Anonymous types
+// no longer used by a function or named type are removed.
+//
+// Strip returns an error if a remaining definition refers to a removed type, such as a
+// stable function with a param of an unstable type. If Strip returns an error, res is not modified.
+func (res *Resolve) Strip(opts *StripOptions) error {
+	removed := make(map[Node]bool)
+	if opts.Unstable {
+		res.unstableItems(opts.Features, removed)
+	}
+	if w := opts.World; w != nil {
+		if !slices.Contains(res.Worlds, w) {
+			return errors.New("strip: world " + worldPathName(w) + " not found")
+		}
+		if removed[w] {
+			return errors.New("strip: world " + worldPathName(w) + " is unstable")
+		}
+		res.unreachableItems(w, removed)
+	}
+	if err := res.checkStripped(removed); err != nil {
+		return err
+	}
+	res.removeItems(removed)
+	if opts.Docs {
+		res.stripDocs()
+	}
+	return nil
+}
+
+// isUnstable reports whether s gates an item behind a feature not in features.
+func isUnstable(s *Stability, features []string) bool {
+	return s != nil && s.Feature != "" && !slices.Contains(features, s.Feature)
+}
+
+// unstableItems adds the unstable definitions in res to removed.
+func (res *Resolve) unstableItems(features []string, removed map[Node]bool) {
+	for _, w := range res.Worlds {
+		if isUnstable(w.Stability, features) {
+			removed[w] = true
+		}
+	}
+	for _, i := range res.Interfaces {
+		if isUnstable(i.Stability, features) {
+			removed[i] = true
+		}
+	}
+	for _, t := range res.TypeDefs {
+		if isUnstable(t.Stability, features) {
+			removed[t] = true
+		}
+	}
+	res.AllFunctions()(func(f *Function) bool {
+		if isUnstable(f.Stability, features) {
+			removed[f] = true
+		}
+		return true
+	})
+}
+
+// unreachableItems adds the definitions in res not reachable from w to removed.
+// Interfaces are kept whole, as a world imports every type and function in an interface.
+func (res *Resolve) unreachableItems(w *World, removed map[Node]bool) {
+	keep := map[Node]bool{w: true}
+	var markType func(t Type)
+	var markInterface func(i *Interface)
+	markFunction := func(f *Function) {
+		for _, p := range slices.Concat(f.Params, f.Results) {
+			markType(p.Type)
+		}
+	}
+	markType = func(t Type) {
+		td, ok := t.(*TypeDef)
+		if !ok || keep[td] {
+			return
+		}
+		keep[td] = true
+		if i, ok := td.Owner.(*Interface); ok {
+			markInterface(i)
+		}
+		for _, t := range typeDefChildren(td) {
+			markType(t)
+		}
+	}
+	markInterface = func(i *Interface) {
+		if keep[i] {
+			return
+		}
+		keep[i] = true
+		i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			markType(t)
+			return true
+		})
+		i.Functions.All()(func(_ string, f *Function) bool {
+			markFunction(f)
+			return true
+		})
+	}
+	for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+		items.All()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *Interface:
+				markInterface(item)
+			case *TypeDef:
+				markType(item)
+			case *Function:
+				markFunction(item)
+			}
+			return true
+		})
+	}
+
+	for _, w := range res.Worlds {
+		if !keep[w] {
+			removed[w] = true
+		}
+	}
+	for _, i := range res.Interfaces {
+		if !keep[i] {
+			removed[i] = true
+		}
+	}
+	for _, t := range res.TypeDefs {
+		if !keep[t] {
+			removed[t] = true
+		}
+	}
+}
+
+// checkStripped returns an error if a definition not in removed refers to a type in removed,
+// or to a type in an interface or world in removed.
+func (res *Resolve) checkStripped(removed map[Node]bool) error {
+	var check func(path string, t Type) error
+	check = func(path string, t Type) error {
+		td, ok := t.(*TypeDef)
+		switch {
+		case !ok:
+			return nil
+		case removed[td] || removed[td.Owner]:
+			return fmt.Errorf("strip: %s refers to removed type %s", path, typeDefPathName(td))
+		case td.Name == nil:
+			for _, child := range typeDefChildren(td) {
+				if err := check(path, child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, t := range res.TypeDefs {
+		if t.Name == nil || removed[t] || removed[t.Owner] {
+			continue
+		}
+		for _, child := range typeDefChildren(t) {
+			if err := check("type "+typeDefPathName(t), child); err != nil {
+				return err
+			}
+		}
+	}
+	checkFunctions := func(owner Node, path string, functions iterate.Seq[*Function]) (err error) {
+		if removed[owner] {
+			return nil
+		}
+		functions(func(f *Function) bool {
+			if removed[f] {
+				return true
+			}
+			for _, p := range slices.Concat(f.Params, f.Results) {
+				if err = check("function "+path+"#"+f.Name, p.Type); err != nil {
+					return false
+				}
+			}
+			return true
+		})
+		return err
+	}
+	for _, w := range res.Worlds {
+		if err := checkFunctions(w, worldPathName(w), w.AllFunctions()); err != nil {
+			return err
+		}
+	}
+	for _, i := range res.Interfaces {
+		if err := checkFunctions(i, interfacePathName(i), i.AllFunctions()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeItems removes the definitions in removed from res, then removes empty packages
+// and unused anonymous types.
+func (res *Resolve) removeItems(removed map[Node]bool) {
+	if len(removed) == 0 {
+		return
+	}
+	deleteItems := func(items *ordered.Map[string, WorldItem], stability map[string]*Stability) {
+		var names []string
+		items.All()(func(name string, item WorldItem) bool {
+			if removed[item] {
+				names = append(names, name)
+			}
+			return true
+		})
+		for _, name := range names {
+			items.Delete(name)
+			delete(stability, name)
+		}
+	}
+	for _, w := range res.Worlds {
+		deleteItems(&w.Imports, w.ImportStability)
+		deleteItems(&w.Exports, w.ExportStability)
+	}
+	for _, i := range res.Interfaces {
+		var types, funcs []string
+		i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+			if removed[t] {
+				types = append(types, name)
+			}
+			return true
+		})
+		i.Functions.All()(func(name string, f *Function) bool {
+			if removed[f] {
+				funcs = append(funcs, name)
+			}
+			return true
+		})
+		for _, name := range types {
+			i.TypeDefs.Delete(name)
+		}
+		for _, name := range funcs {
+			i.Functions.Delete(name)
+		}
+	}
+	for _, pkg := range res.Packages {
+		var ifaces, worlds []string
+		pkg.Interfaces.All()(func(name string, i *Interface) bool {
+			if removed[i] {
+				ifaces = append(ifaces, name)
+			}
+			return true
+		})
+		pkg.Worlds.All()(func(name string, w *World) bool {
+			if removed[w] {
+				worlds = append(worlds, name)
+			}
+			return true
+		})
+		for _, name := range ifaces {
+			pkg.Interfaces.Delete(name)
+		}
+		for _, name := range worlds {
+			pkg.Worlds.Delete(name)
+		}
+		if pkg.Interfaces.Len() == 0 && pkg.Worlds.Len() == 0 {
+			removed[pkg] = true
+		}
+	}
+
+	res.Worlds = slices.DeleteFunc(res.Worlds, func(w *World) bool { return removed[w] })
+	res.Interfaces = slices.DeleteFunc(res.Interfaces, func(i *Interface) bool { return removed[i] })
+	res.Packages = slices.DeleteFunc(res.Packages, func(pkg *Package) bool { return removed[pkg] })
+	res.TypeDefs = slices.DeleteFunc(res.TypeDefs, func(t *TypeDef) bool { return removed[t] || removed[t.Owner] })
+
+	// Anonymous types are kept if used by a remaining function or named type.
+	used := make(map[*TypeDef]bool)
+	var use func(t Type)
+	use = func(t Type) {
+		td, ok := t.(*TypeDef)
+		if !ok || used[td] {
+			return
+		}
+		used[td] = true
+		for _, t := range typeDefChildren(td) {
+			use(t)
+		}
+	}
+	for _, t := range res.TypeDefs {
+		if t.Owner != nil {
+			use(t)
+		}
+	}
+	res.AllFunctions()(func(f *Function) bool {
+		for _, p := range slices.Concat(f.Params, f.Results) {
+			use(p.Type)
+		}
+		return true
+	})
+	res.TypeDefs = slices.DeleteFunc(res.TypeDefs, func(t *TypeDef) bool { return t.Owner == nil && !used[t] })
+}
+
+// stripDocs removes the documentation of each definition in res.
+func (res *Resolve) stripDocs() {
+	for _, pkg := range res.Packages {
+		pkg.Docs = Docs{}
+	}
+	for _, w := range res.Worlds {
+		w.Docs = Docs{}
+	}
+	for _, i := range res.Interfaces {
+		i.Docs = Docs{}
+	}
+	for _, t := range res.TypeDefs {
+		t.Docs = Docs{}
+		switch kind := t.Kind.(type) {
+		case *Record:
+			for i := range kind.Fields {
+				kind.Fields[i].Docs = Docs{}
+			}
+		case *Flags:
+			for i := range kind.Flags {
+				kind.Flags[i].Docs = Docs{}
+			}
+		case *Variant:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = Docs{}
+			}
+		case *Enum:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = Docs{}
+			}
+		}
+	}
+	res.AllFunctions()(func(f *Function) bool {
+		f.Docs = Docs{}
+		return true
+	})
+}
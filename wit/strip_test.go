@@ -0,0 +1,152 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const stripWIT = `package ex:strip@0.1.0;
+
+/// Interface a.
+interface a {
+	/// Type t.
+	record t {
+		/// Field x.
+		x: u32,
+	}
+	f: func(x: t) -> list<t>;
+	@unstable(feature = fancy)
+	g: func() -> option<string>;
+}
+
+interface b {
+	use a.{t};
+	h: func(x: t);
+}
+
+interface unused {
+	u: func();
+}
+
+@unstable(feature = fancy)
+interface fancy {
+	record r { y: u64 }
+	k: func() -> list<r>;
+}
+
+world w {
+	import b;
+	@unstable(feature = fancy)
+	import fancy;
+	export z: func();
+}
+
+world other {
+	import unused;
+}
+`
+
+func loadStripWIT(t *testing.T, src string) *Resolve {
+	t.Helper()
+	res, err := LoadFS(fstest.MapFS{"wit/strip.wit": {Data: []byte(src)}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     StripOptions
+		world    string
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:     "docs",
+			opts:     StripOptions{Docs: true},
+			want:     []string{"interface a", "g: func()", "interface fancy"},
+			dontWant: []string{"///"},
+		},
+		{
+			name:     "unstable",
+			opts:     StripOptions{Unstable: true},
+			want:     []string{"/// Type t.", "f: func(x: t)", "world w"},
+			dontWant: []string{"g: func()", "fancy", "option<string>"},
+		},
+		{
+			name: "unstable with feature",
+			opts: StripOptions{Unstable: true, Features: []string{"fancy"}},
+			want: []string{"g: func()", "interface fancy", "import fancy"},
+		},
+		{
+			name:     "world",
+			opts:     StripOptions{Unstable: true},
+			world:    "ex:strip/w",
+			want:     []string{"interface a", "interface b", "world w", "export z: func();"},
+			dontWant: []string{"unused", "world other", "fancy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := loadStripWIT(t, stripWIT)
+			if tt.world != "" {
+				w, err := res.World(tt.world)
+				if err != nil {
+					t.Fatal(err)
+				}
+				tt.opts.World = w
+			}
+			if err := res.Strip(&tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			got := res.WIT(nil, "")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Strip: WIT does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(got, dontWant) {
+					t.Errorf("Strip: WIT contains %q:\n%s", dontWant, got)
+				}
+			}
+			if err := res.Verify(); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+
+			// The stripped WIT is valid.
+			res2 := loadStripWIT(t, got)
+			if got2 := res2.WIT(nil, ""); got2 != got {
+				t.Errorf("LoadFS(Strip):\n%s\nexpected:\n%s", got2, got)
+			}
+		})
+	}
+}
+
+func TestStripErrors(t *testing.T) {
+	res := loadStripWIT(t, `package ex:strip;
+
+interface i {
+	@unstable(feature = fancy)
+	record r { x: u32 }
+	f: func(x: list<r>);
+}
+`)
+	before := res.WIT(nil, "")
+	err := res.Strip(&StripOptions{Unstable: true})
+	if err == nil || !strings.Contains(err.Error(), "function ex:strip/i#f refers to removed type ex:strip/i#r") {
+		t.Errorf("Strip: %v, expected an error referring to removed type r", err)
+	}
+	if after := res.WIT(nil, ""); after != before {
+		t.Errorf("Strip modified res after an error:\n%s", after)
+	}
+
+	other := loadStripWIT(t, "package ex:other;\nworld w {}\n")
+	err = res.Strip(&StripOptions{World: other.Worlds[0]})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Strip: %v, expected world not found", err)
+	}
+}
@@ -0,0 +1,127 @@
+package wit
+
+import (
+	"fmt"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Syntax is a version of the [WIT] text format, which [Resolve.PrintWIT] prints with
+// [PrintOptions] so WIT can be read by older tools, such as earlier releases of wasm-tools,
+// or by tools that implement newer drafts of the specification.
+// Syntax versions are ordered, and the zero value is the latest syntax.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+type Syntax int
+
+const (
+	// SyntaxLatest is the latest syntax: every WIT construct in a [Resolve] is printed.
+	SyntaxLatest Syntax = iota
+
+	// SyntaxPreResources is the syntax of WASI Preview 2 drafts before resources,
+	// without resource types, own and borrow handles, or stability gates.
+	SyntaxPreResources
+
+	// SyntaxResources is the syntax of WASI 0.2.0, with resources,
+	// but without stability gates or nested packages.
+	SyntaxResources
+
+	// SyntaxGates is the syntax of WASI 0.2.1 and later, with @since, @unstable,
+	// and @deprecated gates, and nested package declarations.
+	SyntaxGates
+
+	// SyntaxAsync is the syntax of WASI 0.3 drafts, where a stream has an element type
+	// but no end type, e.g. stream<u8>.
+	SyntaxAsync
+)
+
+var syntaxNames = []string{
+	"latest",
+	"pre-resources",
+	"0.2.0",
+	"0.2.1",
+	"0.3",
+}
+
+// String returns the name of syntax s, e.g. "0.2.0".
+func (s Syntax) String() string {
+	if s < 0 || int(s) >= len(syntaxNames) {
+		return fmt.Sprintf("Syntax(%d)", int(s))
+	}
+	return syntaxNames[s]
+}
+
+// AtLeast returns true if s is the same version as t or later.
+// [SyntaxLatest] is later than every other version.
+func (s Syntax) AtLeast(t Syntax) bool {
+	return s == SyntaxLatest || (t != SyntaxLatest && s >= t)
+}
+
+// CheckSyntax returns an error naming the first item in r that cannot be printed in syntax s.
+// Stability gates other than @unstable are omitted from syntax without them,
+// but an @unstable item must first be removed, e.g. with [Resolve.Strip].
+func (r *Resolve) CheckSyntax(s Syntax) error {
+	if !s.AtLeast(SyntaxResources) {
+		for _, t := range r.TypeDefs {
+			if _, ok := t.Kind.(*Resource); ok {
+				return fmt.Errorf("resource %s is not supported by WIT %s syntax", typeDefPathName(t), s)
+			}
+		}
+	}
+	if s == SyntaxAsync {
+		for _, t := range r.TypeDefs {
+			if stream, ok := t.Kind.(*Stream); ok && stream.End != nil {
+				return fmt.Errorf("%s is not supported by WIT %s syntax: streams have no end type", stream.WIT(nil, ""), s)
+			}
+		}
+	}
+	if !s.AtLeast(SyntaxGates) {
+		if path := r.firstUnstable(); path != "" {
+			return fmt.Errorf("@unstable %s is not supported by WIT %s syntax", path, s)
+		}
+	}
+	return nil
+}
+
+// firstUnstable returns the path name of the first @unstable world, interface, type,
+// or function in r, or an empty string if there are none.
+func (r *Resolve) firstUnstable() string {
+	for _, w := range r.Worlds {
+		if isUnstable(w.Stability, nil) {
+			return worldPathName(w)
+		}
+		var path string
+		for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+			items.All()(func(name string, item WorldItem) bool {
+				if f, ok := item.(*Function); ok && isUnstable(f.Stability, nil) {
+					path = worldPathName(w) + "#" + name
+				}
+				return path == ""
+			})
+			if path != "" {
+				return path
+			}
+		}
+	}
+	for _, i := range r.Interfaces {
+		if isUnstable(i.Stability, nil) {
+			return interfacePathName(i)
+		}
+		var path string
+		i.Functions.All()(func(name string, f *Function) bool {
+			if isUnstable(f.Stability, nil) {
+				path = interfacePathName(i) + "#" + name
+			}
+			return path == ""
+		})
+		if path != "" {
+			return path
+		}
+	}
+	for _, t := range r.TypeDefs {
+		if isUnstable(t.Stability, nil) {
+			return typeDefPathName(t)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSyntaxAtLeast(t *testing.T) {
+	tests := []struct {
+		s, t Syntax
+		want bool
+	}{
+		{SyntaxLatest, SyntaxAsync, true},
+		{SyntaxLatest, SyntaxLatest, true},
+		{SyntaxAsync, SyntaxLatest, false},
+		{SyntaxGates, SyntaxResources, true},
+		{SyntaxGates, SyntaxGates, true},
+		{SyntaxResources, SyntaxGates, false},
+		{SyntaxPreResources, SyntaxResources, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.AtLeast(tt.t); got != tt.want {
+			t.Errorf("Syntax(%s).AtLeast(%s): %t, expected %t", tt.s, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestCheckSyntax(t *testing.T) {
+	tests := []struct {
+		name   string
+		wit    string
+		syntax Syntax
+		err    string
+	}{
+		{"records", "interface i { record r { a: u32 } }", SyntaxPreResources, ""},
+		{"resources", "interface i { resource r; }", SyntaxResources, ""},
+		{"pre-resources", "interface i { resource r; }", SyntaxPreResources, "resource a:syntax/i#r is not supported by WIT pre-resources syntax"},
+		{"since", "@since(version = 0.1.0) interface i { @since(version = 0.1.0) f: func(); }", SyntaxResources, ""},
+		{"unstable", "interface i { @unstable(feature = x) f: func(); }", SyntaxResources, "@unstable a:syntax/i#f is not supported by WIT 0.2.0 syntax"},
+		{"unstable world", "world w { @unstable(feature = x) import f: func(); }", SyntaxResources, "@unstable a:syntax/w#f is not supported by WIT 0.2.0 syntax"},
+		{"unstable gates", "interface i { @unstable(feature = x) f: func(); }", SyntaxGates, ""},
+		{"stream", "interface i { f: func() -> stream<u8>; }", SyntaxAsync, ""},
+		{"stream end", "interface i { f: func() -> stream<u8, string>; }", SyntaxAsync, "stream<u8, string> is not supported by WIT 0.3 syntax: streams have no end type"},
+		{"stream end latest", "interface i { f: func() -> stream<u8, string>; }", SyntaxLatest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := LoadFS(fstest.MapFS{
+				"wit/a.wit": {Data: []byte("package a:syntax;\n" + tt.wit + "\n")},
+			}, "wit")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = res.CheckSyntax(tt.syntax)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("CheckSyntax(%s): %v", tt.syntax, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("CheckSyntax(%s): %v, expected %s", tt.syntax, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestPrintWITSyntax(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{
+		"wit/a.wit": {Data: []byte(`package a:syntax@0.2.0;
+
+@since(version = 0.1.0)
+interface i {
+	use b:dep/j.{t};
+
+	@since(version = 0.1.0)
+	@deprecated(version = 0.2.0)
+	resource r {
+		@since(version = 0.2.0)
+		get: func() -> u32;
+	}
+
+	@since(version = 0.1.0)
+	f: func();
+}
+
+@since(version = 0.1.0)
+world w {
+	@since(version = 0.1.0)
+	import i;
+}
+`)},
+		"wit/deps/b.wit": {Data: []byte("package b:dep;\ninterface j { type t = u32; }\n")},
+	}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest := res.PrintWIT(&PrintOptions{NestedPackages: true})
+	if !strings.Contains(latest, "package b:dep {") {
+		t.Fatalf("PrintWIT: expected nested packages:\n%s", latest)
+	}
+	for _, syntax := range []Syntax{SyntaxLatest, SyntaxGates, SyntaxAsync} {
+		got := res.PrintWIT(&PrintOptions{Syntax: syntax, NestedPackages: true})
+		if got != latest {
+			t.Errorf("PrintWIT(%s): expected the latest syntax, got:\n%s", syntax, got)
+		}
+	}
+
+	got := res.PrintWIT(&PrintOptions{Syntax: SyntaxResources, NestedPackages: true})
+	if strings.Contains(got, "@since") || strings.Contains(got, "@deprecated") {
+		t.Errorf("PrintWIT(%s): expected no stability gates:\n%s", SyntaxResources, got)
+	}
+	if strings.Contains(got, "package b:dep {") {
+		t.Errorf("PrintWIT(%s): expected no nested packages:\n%s", SyntaxResources, got)
+	}
+	if !strings.Contains(got, "\tget: func() -> u32;") || !strings.Contains(got, "\tf: func();") {
+		t.Errorf("PrintWIT(%s): expected functions:\n%s", SyntaxResources, got)
+	}
+	if _, err := LoadFS(splitPackages(got), "."); err != nil {
+		t.Errorf("PrintWIT(%s): %v:\n%s", SyntaxResources, err, got)
+	}
+}
@@ -0,0 +1,262 @@
+package wit
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// UnionWorlds returns a new [World] that imports the items imported by a or b,
+// and exports the items exported by a or b, in order. Items are matched by
+// import or export name; named interfaces are matched by their fully-qualified
+// name, e.g. wasi:io/streams@0.2.0, even if a and b are from different [Resolve] values.
+// It returns an error if a and b have an item with the same name but a different definition.
+//
+// The returned world has the name and package of a. It is not added to the package,
+// and shares its items with a and b.
+func UnionWorlds(a, b *World) (*World, error) {
+	w := &World{Name: a.Name, Package: a.Package, Docs: a.Docs}
+	for _, items := range []struct {
+		motion     string
+		into       *ordered.Map[string, WorldItem]
+		a, b       *ordered.Map[string, WorldItem]
+		stability  *map[string]*Stability
+		aStability map[string]*Stability
+		bStability map[string]*Stability
+	}{
+		{"import", &w.Imports, &a.Imports, &b.Imports, &w.ImportStability, a.ImportStability, b.ImportStability},
+		{"export", &w.Exports, &a.Exports, &b.Exports, &w.ExportStability, a.ExportStability, b.ExportStability},
+	} {
+		names := make(map[string]WorldItem)
+		items.a.All()(func(key string, item WorldItem) bool {
+			names[worldItemName(key, item)] = item
+			items.into.Set(key, item)
+			setStability(items.stability, key, items.aStability[key])
+			return true
+		})
+		var err error
+		items.b.All()(func(key string, item WorldItem) bool {
+			name := worldItemName(key, item)
+			if x, ok := names[name]; ok {
+				if !sameWorldItem(x, item) {
+					err = fmt.Errorf("cannot union worlds %s and %s: %s %s has different definitions",
+						worldPathName(a), worldPathName(b), items.motion, name)
+				}
+				return err == nil
+			}
+			s := items.bStability[key]
+			if _, ok := items.into.GetOK(key); ok {
+				key = name
+			}
+			items.into.Set(key, item)
+			setStability(items.stability, key, s)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// TargetsError is returned by [World.Targets] if a [World] does not target another.
+type TargetsError struct {
+	// World and Target are the fully-qualified names of the worlds compared,
+	// e.g. wasi:cli/command@0.2.0.
+	World  string
+	Target string
+
+	// Mismatches describe each import or export that does not match,
+	// e.g. "import wasi:sockets/tcp@0.2.0: not imported by target".
+	Mismatches []string
+}
+
+// Error implements the error interface.
+func (err *TargetsError) Error() string {
+	return "world " + err.World + " does not target " + err.Target + ": " + strings.Join(err.Mismatches, "; ")
+}
+
+// Targets returns a [TargetsError] if w does not target world target,
+// otherwise nil. A component that implements w can run in a host that implements
+// target if w imports a subset of the items imported by target, and w exports every
+// item exported by target. Items are matched as in [UnionWorlds].
+func (w *World) Targets(target *World) error {
+	err := &TargetsError{World: worldPathName(w), Target: worldPathName(target)}
+	mismatch := func(motion, name, reason string) {
+		err.Mismatches = append(err.Mismatches, motion+" "+name+": "+reason)
+	}
+
+	imports := worldItemsByName(&target.Imports)
+	w.Imports.All()(func(key string, item WorldItem) bool {
+		name := worldItemName(key, item)
+		x, ok := imports[name]
+		switch {
+		case !ok:
+			mismatch("import", name, "not imported by target")
+		case !sameWorldItem(x, item):
+			mismatch("import", name, differsReason(x, item))
+		}
+		return true
+	})
+
+	exports := worldItemsByName(&w.Exports)
+	target.Exports.All()(func(key string, item WorldItem) bool {
+		name := worldItemName(key, item)
+		x, ok := exports[name]
+		switch {
+		case !ok:
+			mismatch("export", name, "exported by target, but not by world")
+		case !sameWorldItem(x, item):
+			mismatch("export", name, differsReason(x, item))
+		}
+		return true
+	})
+
+	if len(err.Mismatches) > 0 {
+		return err
+	}
+	return nil
+}
+
+// differsReason describes why world items a and b, which do not have the same definition,
+// do not match. Reordered enum cases, variant cases, or flags are reported specifically:
+// although their names are unchanged, reordering changes the discriminant of each case
+// or the bit of each flag, which breaks compatibility.
+func differsReason(a, b WorldItem) string {
+	reason := "definition differs from target"
+	var reordered []string
+	switch a := a.(type) {
+	case *TypeDef:
+		if b, ok := b.(*TypeDef); ok {
+			reordered = appendReordered(reordered, a, b)
+		}
+	case *Interface:
+		if b, ok := b.(*Interface); ok {
+			a.TypeDefs.All()(func(name string, ta *TypeDef) bool {
+				if tb, ok := b.TypeDefs.GetOK(name); ok {
+					reordered = appendReordered(reordered, ta, tb)
+				}
+				return true
+			})
+		}
+	}
+	if len(reordered) > 0 {
+		reason += ": " + strings.Join(reordered, ", ")
+	}
+	return reason
+}
+
+// appendReordered appends a description to reordered if a and b are enums, variants,
+// or flags with the same case or flag names in a different order.
+func appendReordered(reordered []string, a, b *TypeDef) []string {
+	var an, bn []string
+	var what string
+	switch ak := a.Kind.(type) {
+	case *Enum:
+		bk, ok := b.Kind.(*Enum)
+		if !ok {
+			return reordered
+		}
+		an, bn = enumCaseNames(ak.Cases), enumCaseNames(bk.Cases)
+		what = "cases reordered, changing discriminants"
+	case *Variant:
+		bk, ok := b.Kind.(*Variant)
+		if !ok {
+			return reordered
+		}
+		an, bn = caseNames(ak.Cases), caseNames(bk.Cases)
+		what = "cases reordered, changing discriminants"
+	case *Flags:
+		bk, ok := b.Kind.(*Flags)
+		if !ok {
+			return reordered
+		}
+		an, bn = flagNames(ak.Flags), flagNames(bk.Flags)
+		what = "reordered, changing bits"
+	default:
+		return reordered
+	}
+	if len(an) != len(bn) || slices.Equal(an, bn) {
+		return reordered
+	}
+	as, bs := slices.Clone(an), slices.Clone(bn)
+	slices.Sort(as)
+	slices.Sort(bs)
+	if !slices.Equal(as, bs) {
+		return reordered
+	}
+	name := a.WITKind()
+	if a.Name != nil {
+		name += " " + escape(*a.Name)
+	}
+	return append(reordered, name+" "+what)
+}
+
+func enumCaseNames(cases []EnumCase) []string {
+	names := make([]string, len(cases))
+	for i := range cases {
+		names[i] = cases[i].Name
+	}
+	return names
+}
+
+func caseNames(cases []Case) []string {
+	names := make([]string, len(cases))
+	for i := range cases {
+		names[i] = cases[i].Name
+	}
+	return names
+}
+
+func flagNames(flags []Flag) []string {
+	names := make([]string, len(flags))
+	for i := range flags {
+		names[i] = flags[i].Name
+	}
+	return names
+}
+
+func worldItemsByName(items *ordered.Map[string, WorldItem]) map[string]WorldItem {
+	m := make(map[string]WorldItem)
+	items.All()(func(key string, item WorldItem) bool {
+		m[worldItemName(key, item)] = item
+		return true
+	})
+	return m
+}
+
+// sameWorldItem reports whether world items a and b have the same definition.
+// Named interfaces with the same fully-qualified name are assumed to be identical,
+// as in [Resolve.Merge]. Other items are compared by their WIT text format.
+func sameWorldItem(a, b WorldItem) bool {
+	if a == b {
+		return true
+	}
+	switch a := a.(type) {
+	case *Interface:
+		b, ok := b.(*Interface)
+		if !ok {
+			return false
+		}
+		if a.Name != nil && b.Name != nil {
+			return interfacePathName(a) == interfacePathName(b)
+		}
+		return a.WIT(nil, "") == b.WIT(nil, "")
+	case *TypeDef:
+		b, ok := b.(*TypeDef)
+		return ok && a.WIT(nil, "") == b.WIT(nil, "")
+	case *Function:
+		b, ok := b.(*Function)
+		return ok && a.WIT(nil, "") == b.WIT(nil, "")
+	}
+	return false
+}
+
+func worldPathName(w *World) string {
+	if w.Package == nil {
+		return w.Name
+	}
+	return worldID(w)
+}
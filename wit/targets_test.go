@@ -0,0 +1,155 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const targetsJSON = `{
+	"worlds": [
+		{
+			"name": "a",
+			"imports": {"interface-0": {"interface": 0}, "interface-1": {"interface": 1}, "f": {"function": {"name": "f", "kind": "freestanding", "params": [], "results": []}}},
+			"exports": {"interface-2": {"interface": 2}},
+			"package": 0
+		},
+		{
+			"name": "b",
+			"imports": {"interface-0": {"interface": 0}, "f": {"function": {"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": "u32"}], "results": []}}},
+			"exports": {"interface-2": {"interface": 2}},
+			"package": 0
+		},
+		{
+			"name": "host",
+			"imports": {"interface-0": {"interface": 0}, "interface-1": {"interface": 1}},
+			"exports": {"interface-2": {"interface": 2}},
+			"package": 0
+		}
+	],
+	"interfaces": [
+		{"name": "i", "types": {}, "functions": {}, "package": 0},
+		{"name": "j", "types": {}, "functions": {}, "package": 0},
+		{"name": "k", "types": {}, "functions": {}, "package": 0}
+	],
+	"types": [],
+	"packages": [{"name": "foo:a", "interfaces": {"i": 0, "j": 1, "k": 2}, "worlds": {"a": 0, "b": 1, "host": 2}}]
+}`
+
+func TestUnionWorlds(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(targetsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, host := res.Worlds[0], res.Worlds[1], res.Worlds[2]
+
+	w, err := UnionWorlds(host, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Imports.Len(), 3; got != want {
+		t.Errorf("len(Imports): %d, expected %d", got, want)
+	}
+	if got, want := w.Exports.Len(), 1; got != want {
+		t.Errorf("len(Exports): %d, expected %d", got, want)
+	}
+	if got := w.Imports.Get("f"); got != a.Imports.Get("f") {
+		t.Errorf("Imports[f]: %v, expected %v", got, a.Imports.Get("f"))
+	}
+	if w.Name != host.Name || w.Package != host.Package {
+		t.Errorf("UnionWorlds: world %s, expected %s", worldPathName(w), worldPathName(host))
+	}
+
+	_, err = UnionWorlds(a, b)
+	if err == nil {
+		t.Fatal("UnionWorlds(a, b): expected error")
+	}
+	if want := "import f has different definitions"; !strings.Contains(err.Error(), want) {
+		t.Errorf("UnionWorlds(a, b): %v, expected %q", err, want)
+	}
+}
+
+func TestWorldTargets(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(targetsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, host := res.Worlds[0], res.Worlds[1], res.Worlds[2]
+
+	tests := []struct {
+		w, target *World
+		want      []string
+	}{
+		{host, a, nil},
+		{host, host, nil},
+		{a, host, []string{"import f: not imported by target"}},
+		{b, a, []string{"import f: definition differs from target"}},
+		{a, b, []string{"import foo:a/j: not imported by target", "import f: definition differs from target"}},
+	}
+	for _, tt := range tests {
+		err := tt.w.Targets(tt.target)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s.Targets(%s): %v, expected nil", tt.w.Name, tt.target.Name, err)
+			}
+			continue
+		}
+		terr, ok := err.(*TargetsError)
+		if !ok {
+			t.Errorf("%s.Targets(%s): %v, expected *TargetsError", tt.w.Name, tt.target.Name, err)
+			continue
+		}
+		if got, want := strings.Join(terr.Mismatches, "\n"), strings.Join(tt.want, "\n"); got != want {
+			t.Errorf("%s.Targets(%s) mismatches:\n%s\nexpected:\n%s", tt.w.Name, tt.target.Name, got, want)
+		}
+	}
+
+	// A world with no exports does not target a world with exports.
+	err = (&World{Name: "empty"}).Targets(host)
+	if want := "export foo:a/k: exported by target, but not by world"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("empty.Targets(host): %v, expected %q", err, want)
+	}
+}
+
+func TestWorldTargetsReordered(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{"wit/a.wit": {Data: []byte(`package foo:a;
+
+world w {
+	enum e { a, b, c }
+	variant v { x(u32), y }
+	import i: interface {
+		flags f { r, w, x }
+		enum g { a, b }
+	}
+	import h: func(e: e, v: v);
+}
+
+world host {
+	enum e { b, a, c }
+	variant v { y, x(u32) }
+	import i: interface {
+		flags f { w, r, x }
+		enum g { a, b, c }
+	}
+	import h: func(e: e, v: v);
+}
+`)}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, host := res.Worlds[0], res.Worlds[1]
+	err = w.Targets(host)
+	terr, ok := err.(*TargetsError)
+	if !ok {
+		t.Fatalf("w.Targets(host): %v, expected *TargetsError", err)
+	}
+	// Enum g has a new case, which is not reported as reordered.
+	want := []string{
+		"import i: definition differs from target: flags f reordered, changing bits",
+		"import e: definition differs from target: enum e cases reordered, changing discriminants",
+		"import v: definition differs from target: variant v cases reordered, changing discriminants",
+	}
+	if got, want := strings.Join(terr.Mismatches, "\n"), strings.Join(want, "\n"); got != want {
+		t.Errorf("w.Targets(host) mismatches:\n%s\nexpected:\n%s", got, want)
+	}
+}
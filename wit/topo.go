@@ -0,0 +1,164 @@
+package wit
+
+// TopologicalPackages returns the packages in r, ordered so that each [Package]
+// precedes the packages that use its interfaces or types.
+// The order is stable: packages without a dependency between them
+// remain in the order of r.Packages.
+//
+// WIT does not permit cyclic package dependencies. If r contains a cycle,
+// each package is still returned once, in an unspecified order within the cycle.
+func (r *Resolve) TopologicalPackages() []*Package {
+	deps := make(map[*Package][]*Package, len(r.Packages))
+	for _, pkg := range r.Packages {
+		deps[pkg] = packageDependencies(pkg)
+	}
+	return topological(r.Packages, func(pkg *Package) []*Package {
+		return deps[pkg]
+	})
+}
+
+// TopologicalTypeDefs returns the type definitions in i, ordered so that each [TypeDef]
+// precedes the type definitions in i that refer to it, directly or through anonymous types.
+// The order is stable: type definitions without a dependency between them
+// remain in the order they are declared in i.
+func (i *Interface) TopologicalTypeDefs() []*TypeDef {
+	var typeDefs []*TypeDef
+	i.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+		typeDefs = append(typeDefs, td)
+		return true
+	})
+	return topological(typeDefs, func(td *TypeDef) []*TypeDef {
+		var deps []*TypeDef
+		walkNamedTypeDefs(td, func(dep *TypeDef) {
+			if dep != td && dep.Owner == td.Owner {
+				deps = append(deps, dep)
+			}
+		})
+		return deps
+	})
+}
+
+// topological returns items ordered with a depth-first search,
+// so that each item follows its dependencies returned by deps.
+// Dependencies not in items are ignored.
+func topological[T comparable](items []T, deps func(T) []T) []T {
+	in := make(map[T]bool, len(items))
+	for _, item := range items {
+		in[item] = true
+	}
+	visited := make(map[T]bool, len(items))
+	sorted := make([]T, 0, len(items))
+	var visit func(item T)
+	visit = func(item T) {
+		if visited[item] || !in[item] {
+			return
+		}
+		visited[item] = true
+		for _, dep := range deps(item) {
+			visit(dep)
+		}
+		sorted = append(sorted, item)
+	}
+	for _, item := range items {
+		visit(item)
+	}
+	return sorted
+}
+
+// packageDependencies returns the packages, other than pkg, that own an interface
+// or type used by an interface or world in pkg, in order of first use.
+func packageDependencies(pkg *Package) []*Package {
+	var deps []*Package
+	seen := map[*Package]bool{pkg: true}
+	add := func(p *Package) {
+		if p != nil && !seen[p] {
+			seen[p] = true
+			deps = append(deps, p)
+		}
+	}
+	addType := func(t Type) {
+		walkNamedTypeDefs(t, func(td *TypeDef) {
+			add(typeOwnerPackage(td.Owner))
+		})
+	}
+	addFunction := func(f *Function) {
+		for _, p := range f.Params {
+			addType(p.Type)
+		}
+		for _, r := range f.Results {
+			addType(r.Type)
+		}
+	}
+	addInterface := func(i *Interface) {
+		i.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+			addType(td)
+			return true
+		})
+		i.Functions.All()(func(_ string, f *Function) bool {
+			addFunction(f)
+			return true
+		})
+	}
+	pkg.Interfaces.All()(func(_ string, i *Interface) bool {
+		addInterface(i)
+		return true
+	})
+	pkg.Worlds.All()(func(_ string, w *World) bool {
+		for _, items := range []func(func(string, WorldItem) bool){w.Imports.All(), w.Exports.All()} {
+			items(func(_ string, item WorldItem) bool {
+				switch item := item.(type) {
+				case *Interface:
+					add(item.Package)
+					if item.Name == nil {
+						// Anonymous interfaces declared in a world are part of pkg.
+						addInterface(item)
+					}
+				case *TypeDef:
+					addType(item)
+				case *Function:
+					addFunction(item)
+				}
+				return true
+			})
+		}
+		return true
+	})
+	return deps
+}
+
+// walkNamedTypeDefs calls f for each named [TypeDef] referred to by t,
+// including t itself if named, without descending into named types other than t.
+func walkNamedTypeDefs(t Type, f func(*TypeDef)) {
+	root, ok := t.(*TypeDef)
+	if !ok {
+		return
+	}
+	var walk func(t Type)
+	walk = func(t Type) {
+		td, ok := t.(*TypeDef)
+		if !ok {
+			return
+		}
+		if td != root && td.Name != nil {
+			f(td)
+			return
+		}
+		for _, child := range typeDefChildren(td) {
+			walk(child)
+		}
+	}
+	if root.Name != nil {
+		f(root)
+	}
+	walk(root)
+}
+
+func typeOwnerPackage(owner TypeOwner) *Package {
+	switch owner := owner.(type) {
+	case *Interface:
+		return owner.Package
+	case *World:
+		return owner.Package
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package wit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterfaceTopologicalTypeDefs(t *testing.T) {
+	name := func(s string) *string { return &s }
+	i := &Interface{Name: name("i")}
+	// record r { e: e, l: list<option<a>> } is declared before its dependencies.
+	a := &TypeDef{Name: name("a"), Kind: U32{}, Owner: i}
+	e := &TypeDef{Name: name("e"), Kind: &Enum{Cases: []EnumCase{{Name: "x"}}}, Owner: i}
+	r := &TypeDef{Name: name("r"), Kind: &Record{Fields: []Field{
+		{Name: "e", Type: e},
+		{Name: "l", Type: &TypeDef{Kind: &List{Type: &TypeDef{Kind: &Option{Type: a}}}}},
+	}}, Owner: i}
+	b := &TypeDef{Name: name("b"), Kind: r, Owner: i}
+	c := &TypeDef{Name: name("c"), Kind: String{}, Owner: i}
+	for _, td := range []*TypeDef{b, r, c, e, a} {
+		i.TypeDefs.Set(*td.Name, td)
+	}
+
+	got := i.TopologicalTypeDefs()
+	want := []*TypeDef{e, a, r, b, c}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopologicalTypeDefs(): %v, expected %v", typeDefNames(got), typeDefNames(want))
+	}
+}
+
+func typeDefNames(typeDefs []*TypeDef) []string {
+	var names []string
+	for _, td := range typeDefs {
+		names = append(names, *td.Name)
+	}
+	return names
+}
+
+func TestResolveTopologicalPackages(t *testing.T) {
+	res, err := LoadJSON("../testdata/wasi/0.2.0/http.wit.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reverse the packages, so each package precedes its dependencies.
+	reversed := *res
+	reversed.Packages = nil
+	for i := len(res.Packages) - 1; i >= 0; i-- {
+		reversed.Packages = append(reversed.Packages, res.Packages[i])
+	}
+
+	for _, r := range []*Resolve{res, &reversed} {
+		sorted := r.TopologicalPackages()
+		if len(sorted) != len(r.Packages) {
+			t.Fatalf("TopologicalPackages(): %d packages, expected %d", len(sorted), len(r.Packages))
+		}
+		index := make(map[*Package]int)
+		for i, pkg := range sorted {
+			index[pkg] = i
+		}
+		for _, pkg := range sorted {
+			for _, dep := range packageDependencies(pkg) {
+				if index[dep] > index[pkg] {
+					t.Errorf("TopologicalPackages(): %s precedes its dependency %s", pkg.Name.String(), dep.Name.String())
+				}
+			}
+		}
+	}
+
+	for _, pkg := range res.Packages {
+		if pkg.Name.Package == "http" && len(packageDependencies(pkg)) == 0 {
+			t.Errorf("packageDependencies(%s): no dependencies", pkg.Name.String())
+		}
+	}
+
+	// Packages decoded from JSON are already in topological order.
+	if got := res.TopologicalPackages(); !reflect.DeepEqual(got, res.Packages) {
+		t.Errorf("TopologicalPackages(): order differs from Resolve.Packages")
+	}
+}
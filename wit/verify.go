@@ -0,0 +1,247 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ydnar/wasm-tools-go/wit/ordered"
+)
+
+// Verify checks the internal invariants of r, and returns an error describing
+// each violation found, or nil if r is consistent. It verifies that:
+//
+//   - r.Worlds, r.Interfaces, r.TypeDefs, and r.Packages do not contain nil values.
+//   - Each [TypeDef] has a kind, and each [Type] it refers to is non-nil and in r.
+//   - The owner of each [TypeDef] is in r, and each named TypeDef is reachable from its owner by name.
+//   - Each [Interface] and [World] is in its [Package], and each Package refers back to its interfaces and worlds.
+//   - Each [WorldItem] and [Function] refers only to interfaces and types in r.
+//   - Each [Own] and [Borrow] handle refers to a [Resource], and no type or interface
+//     refers to itself, which would otherwise recurse without end when printing WIT
+//     or computing ABI layout. These are checked only if r is otherwise consistent.
+//
+// [DecodeJSON] always produces a consistent [Resolve]. Verify is useful
+// after a Resolve is constructed or modified programmatically, or merged.
+func (r *Resolve) Verify() error {
+	v := &verifier{
+		worlds:     set(r.Worlds),
+		interfaces: set(r.Interfaces),
+		typeDefs:   set(r.TypeDefs),
+		packages:   set(r.Packages),
+	}
+	for i, w := range r.Worlds {
+		if w == nil {
+			v.fail("world %d: nil", i)
+			continue
+		}
+		v.world(w)
+	}
+	for i, face := range r.Interfaces {
+		if face == nil {
+			v.fail("interface %d: nil", i)
+			continue
+		}
+		v.iface(face)
+	}
+	for i, td := range r.TypeDefs {
+		if td == nil {
+			v.fail("type %d: nil", i)
+			continue
+		}
+		v.typeDef(td)
+	}
+	for i, pkg := range r.Packages {
+		if pkg == nil {
+			v.fail("package %d: nil", i)
+			continue
+		}
+		v.pkg(pkg)
+	}
+	if v.err == nil {
+		v.err = r.checkHandleTargets()
+	}
+	if v.err == nil {
+		v.err = r.checkCycles()
+	}
+	if v.err == nil {
+		v.err = r.checkHandles()
+	}
+	return v.err
+}
+
+// verifier accumulates the errors found by [Resolve.Verify].
+type verifier struct {
+	worlds     map[*World]bool
+	interfaces map[*Interface]bool
+	typeDefs   map[*TypeDef]bool
+	packages   map[*Package]bool
+	err        error
+}
+
+func (v *verifier) fail(format string, args ...any) {
+	v.err = errors.Join(v.err, fmt.Errorf(format, args...))
+}
+
+func (v *verifier) world(w *World) {
+	name := "world " + w.Name
+	if w.Package == nil {
+		v.fail("%s: no package", name)
+	} else {
+		name = "world " + worldID(w)
+		if !v.packages[w.Package] {
+			v.fail("%s: package not in Resolve", name)
+		} else if w.Package.Worlds.Get(w.Name) != w {
+			v.fail("%s: not in package %s", name, w.Package.Name.String())
+		}
+	}
+	for _, items := range []*ordered.Map[string, WorldItem]{&w.Imports, &w.Exports} {
+		items.All()(func(key string, item WorldItem) bool {
+			itemName := name + " item " + key
+			switch item := item.(type) {
+			case *Interface:
+				if !v.interfaces[item] {
+					v.fail("%s: interface not in Resolve", itemName)
+				}
+			case *TypeDef:
+				v.typ(itemName, item)
+				if item.Owner != TypeOwner(w) {
+					v.fail("%s: owner is not the world", itemName)
+				}
+				if item.Name == nil || *item.Name != key {
+					v.fail("%s: type name does not match", itemName)
+				}
+			case *Function:
+				v.function(itemName, item)
+			default:
+				v.fail("%s: nil", itemName)
+			}
+			return true
+		})
+	}
+}
+
+func (v *verifier) iface(i *Interface) {
+	name := interfacePathName(i)
+	if i.Package != nil {
+		if !v.packages[i.Package] {
+			v.fail("interface %s: package not in Resolve", name)
+		} else if i.Name != nil && i.Package.Interfaces.Get(*i.Name) != i {
+			v.fail("interface %s: not in package %s", name, i.Package.Name.String())
+		}
+	}
+	i.TypeDefs.All()(func(key string, td *TypeDef) bool {
+		typeName := "interface " + name + " type " + key
+		switch {
+		case td == nil:
+			v.fail("%s: nil", typeName)
+		case !v.typeDefs[td]:
+			v.fail("%s: not in Resolve", typeName)
+		case td.Owner != TypeOwner(i):
+			v.fail("%s: owner is not the interface", typeName)
+		case td.Name == nil || *td.Name != key:
+			v.fail("%s: type name does not match", typeName)
+		}
+		return true
+	})
+	i.Functions.All()(func(key string, f *Function) bool {
+		v.function("interface "+name+" function "+key, f)
+		return true
+	})
+}
+
+func (v *verifier) typeDef(td *TypeDef) {
+	if td.Kind == nil {
+		name := "(anonymous type)"
+		if td.Name != nil {
+			name = *td.Name
+		}
+		v.fail("type %s: no kind", name)
+		return
+	}
+	name := "type " + typeDefPathName(td)
+	switch owner := td.Owner.(type) {
+	case nil:
+		if td.Name != nil {
+			v.fail("%s: named type has no owner", name)
+		}
+	case *Interface:
+		if !v.interfaces[owner] {
+			v.fail("%s: owner not in Resolve", name)
+		} else if td.Name != nil && owner.TypeDefs.Get(*td.Name) != td {
+			v.fail("%s: not reachable from interface %s", name, interfacePathName(owner))
+		}
+	case *World:
+		if !v.worlds[owner] {
+			v.fail("%s: owner not in Resolve", name)
+		} else if td.Name != nil && !worldHasType(owner, *td.Name, td) {
+			v.fail("%s: not reachable from world %s", name, owner.Name)
+		}
+	}
+	for _, t := range typeDefChildren(td) {
+		v.typ(name, t)
+	}
+}
+
+func (v *verifier) pkg(p *Package) {
+	name := "package " + p.Name.String()
+	p.Interfaces.All()(func(key string, i *Interface) bool {
+		switch {
+		case i == nil:
+			v.fail("%s interface %s: nil", name, key)
+		case !v.interfaces[i]:
+			v.fail("%s interface %s: not in Resolve", name, key)
+		case i.Package != p:
+			v.fail("%s interface %s: package is not %s", name, key, p.Name.String())
+		}
+		return true
+	})
+	p.Worlds.All()(func(key string, w *World) bool {
+		switch {
+		case w == nil:
+			v.fail("%s world %s: nil", name, key)
+		case !v.worlds[w]:
+			v.fail("%s world %s: not in Resolve", name, key)
+		case w.Package != p:
+			v.fail("%s world %s: package is not %s", name, key, p.Name.String())
+		}
+		return true
+	})
+}
+
+func (v *verifier) function(name string, f *Function) {
+	if f == nil {
+		v.fail("%s: nil", name)
+		return
+	}
+	switch kind := f.Kind.(type) {
+	case nil:
+		v.fail("%s: no kind", name)
+	case *Method:
+		v.typ(name, kind.Type)
+	case *Static:
+		v.typ(name, kind.Type)
+	case *Constructor:
+		v.typ(name, kind.Type)
+	}
+	for _, p := range f.Params {
+		v.typ(name+" param "+p.Name, p.Type)
+	}
+	for _, p := range f.Results {
+		v.typ(name+" result", p.Type)
+	}
+}
+
+// typ checks t, which must be a primitive type or a [TypeDef] in the Resolve.
+func (v *verifier) typ(name string, t Type) {
+	switch t := t.(type) {
+	case nil:
+		v.fail("%s: nil type", name)
+	case *TypeDef:
+		if !v.typeDefs[t] {
+			v.fail("%s: type not in Resolve", name)
+		}
+	}
+}
+
+func worldHasType(w *World, name string, td *TypeDef) bool {
+	return w.Imports.Get(name) == WorldItem(td) || w.Exports.Get(name) == WorldItem(td)
+}
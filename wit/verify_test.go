@@ -0,0 +1,128 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			if err := res.Verify(); err != nil {
+				t.Error(err)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(res *Resolve)
+		want   []string
+	}{
+		{
+			"nil type",
+			func(res *Resolve) { res.TypeDefs = append(res.TypeDefs, nil) },
+			[]string{"type 2: nil"},
+		},
+		{
+			"type not reachable from owner",
+			func(res *Resolve) { res.Interfaces[0].TypeDefs.Delete("r") },
+			[]string{"type foo:bar/a#r: not reachable from interface foo:bar/a"},
+		},
+		{
+			"wrong owner",
+			func(res *Resolve) { res.TypeDefs[0].Owner = res.Worlds[0] },
+			[]string{
+				"interface foo:bar/a type r: owner is not the interface",
+				"type foo:bar/w#r: not reachable from world w",
+			},
+		},
+		{
+			"interface not in package",
+			func(res *Resolve) { res.Packages[0].Interfaces.Delete("a") },
+			[]string{"interface foo:bar/a: not in package foo:bar"},
+		},
+		{
+			"world item not in resolve",
+			func(res *Resolve) { res.Worlds[0].Imports.Set("a", &Interface{}) },
+			[]string{"world foo:bar/w item a: interface not in Resolve"},
+		},
+		{
+			"function type not in resolve",
+			func(res *Resolve) {
+				f := res.Interfaces[0].Functions.Get("f")
+				f.Params[0].Type = &TypeDef{Kind: &List{Type: U8{}}}
+			},
+			[]string{"interface foo:bar/a function f param x: type not in Resolve"},
+		},
+		{
+			"nil result type",
+			func(res *Resolve) {
+				res.Interfaces[0].Functions.Get("f").Results = []Param{{}}
+			},
+			[]string{"interface foo:bar/a function f result: nil type"},
+		},
+		{
+			"handle refers to itself",
+			func(res *Resolve) { res.TypeDefs[1].Kind = &Own{Type: res.TypeDefs[1]} },
+			[]string{"invalid handle in (anonymous owned handle): handle refers to itself"},
+		},
+		{
+			"handle to non-resource",
+			func(res *Resolve) { res.TypeDefs[0].Kind = &List{Type: U8{}} },
+			[]string{"invalid handle in (anonymous owned handle): handle to non-resource type foo:bar/a#r (list)"},
+		},
+		{
+			"type contains itself",
+			func(res *Resolve) {
+				res.TypeDefs[0].Kind = &Record{Fields: []Field{{Name: "r", Type: res.TypeDefs[0]}}}
+				res.TypeDefs = res.TypeDefs[:1]
+				res.Interfaces[0].Functions.Get("f").Params[0].Type = U8{}
+			},
+			[]string{"cyclic type definition: foo:bar/a#r → foo:bar/a#r"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := DecodeJSON(strings.NewReader(verifyJSON))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := res.Verify(); err != nil {
+				t.Fatalf("Verify before mutation: %v", err)
+			}
+			tt.mutate(res)
+			err = res.Verify()
+			if err == nil {
+				t.Fatal("Verify: expected error")
+			}
+			got := err.Error()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Verify: %q, expected an error containing %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+const verifyJSON = `{
+	"worlds": [{"name": "w", "imports": {"interface-0": {"interface": 0}}, "exports": {}, "package": 0}],
+	"interfaces": [{
+		"name": "a",
+		"types": {"r": 0},
+		"functions": {"f": {"name": "f", "kind": "freestanding", "params": [{"name": "x", "type": 1}], "results": [{"type": "u32"}]}},
+		"package": 0
+	}],
+	"types": [
+		{"name": "r", "kind": "resource", "owner": {"interface": 0}},
+		{"name": null, "kind": {"handle": {"own": 0}}, "owner": null}
+	],
+	"packages": [{"name": "foo:bar", "interfaces": {"a": 0}, "worlds": {"w": 0}}]
+}`
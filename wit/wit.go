@@ -23,16 +23,59 @@ func indent(s string) string {
 	return strings.ReplaceAll(strings.TrimSuffix(ws+strings.ReplaceAll(s, "\n", "\n"+ws), ws), ws+"\n", "\n")
 }
 
-// unwrap unwraps a multiline string into a single line, if:
-// 1. its length is <= 50 chars
-// 2. its line count is <= 5
+// Default line wrapping of record, flags, variant, and enum declarations.
+// See [PrintOptions].
+const (
+	defaultMaxWidth = 50
+	defaultMaxLines = 5
+)
+
+// unwrap unwraps the multiline WIT declaration s of kind into a single line, if:
+// 1. it has no comments
+// 2. its length is <= MaxWidth chars
+// 3. its line count after the first line is <= MaxLines
+// subject to the ExpandRecords and CompactEnums options in pr.
 // This is used for single-line [Record], [Flags], [Variant], and [Enum] declarations.
-func unwrap(s string) string {
-	const chars = 50
-	const lines = 5
-	if len(s) > chars || strings.Count(s, "\n") > lines || strings.Contains(s, "//") {
+func (pr *printer) unwrap(kind TypeDefKind, s string) string {
+	var opts PrintOptions
+	if pr != nil {
+		opts = pr.opts
+	}
+	if strings.Contains(s, "//") {
+		return s
+	}
+	switch kind.(type) {
+	case *Record:
+		if opts.ExpandRecords {
+			return s
+		}
+	case *Enum:
+		if opts.CompactEnums {
+			return joinLines(s)
+		}
+	}
+	width := opts.MaxWidth
+	if width == 0 {
+		width = defaultMaxWidth
+	}
+	lines := opts.MaxLines
+	if lines == 0 {
+		lines = defaultMaxLines
+	}
+	if width < 0 || len(s) > width || strings.Count(s, "\n") > lines {
 		return s
 	}
+	return joinLines(s)
+}
+
+// multiliner is implemented by [Record], [Flags], [Variant], and [Enum],
+// whose declarations may be printed on a single line by [printer.unwrap].
+type multiliner interface {
+	multilineWIT(ctx Node, name string) string
+}
+
+// joinLines joins the lines of multiline declaration s into a single line.
+func joinLines(s string) string {
 	var b strings.Builder
 	for i, line := range strings.Split(s, "\n") {
 		if i > 0 {
@@ -55,28 +98,142 @@ func (*Resolve) WITKind() string { return "resolve" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (r *Resolve) WIT(_ Node, _ string) string {
+	return r.PrintWIT(nil)
+}
+
+// PrintOptions configure the [WIT] text format produced by [Resolve.PrintWIT].
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+type PrintOptions struct {
+	// NestedPackages prints a [Resolve] with multiple packages as a single valid WIT file.
+	// The last package, which is typically the package that depends on the others,
+	// is printed with a package declaration at the top of the file, followed by the
+	// remaining packages in the nested form: package foo:bar { ... }.
+	NestedPackages bool
+
+	// ElideVersions omits the version of a package from its declaration
+	// and from references to it, e.g. wasi:io/streams instead of wasi:io/streams@0.2.0.
+	// Versions are kept for a package if the Resolve contains another version of it.
+	ElideVersions bool
+
+	// MaxWidth is the maximum length of a record, flags, variant, or enum declaration,
+	// measured with one field or case per line, that is printed on a single line,
+	// e.g. enum color { red, green, blue }. Longer declarations are printed with
+	// one field or case per line. If zero, the default is 50.
+	// If negative, declarations are never printed on a single line.
+	MaxWidth int
+
+	// MaxLines is the maximum number of lines after the first line of a declaration
+	// printed on a single line. If zero, the default is 5.
+	MaxLines int
+
+	// ExpandRecords prints each record with one field per line, regardless of MaxWidth.
+	ExpandRecords bool
+
+	// CompactEnums prints each enum on a single line, regardless of MaxWidth and MaxLines,
+	// unless its cases have docs.
+	CompactEnums bool
+
+	// Syntax is the version of the WIT syntax to print, so the output can be read by
+	// older or newer tools. If zero, the latest syntax is printed.
+	// Stability gates are omitted before [SyntaxGates], as is NestedPackages.
+	// Call [Resolve.CheckSyntax] first to check that the Resolve can be printed in Syntax.
+	Syntax Syntax
+}
+
+// PrintWIT returns the [WIT] text format for [Resolve] r with options opts, which may be nil.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (r *Resolve) PrintWIT(opts *PrintOptions) string {
+	pr := newPrinter(r, opts)
 	var b strings.Builder
+	if pr.opts.NestedPackages && pr.opts.Syntax.AtLeast(SyntaxGates) && len(r.Packages) > 0 {
+		main := r.Packages[len(r.Packages)-1]
+		b.WriteString(main.wit(pr, r))
+		for _, p := range r.Packages[:len(r.Packages)-1] {
+			b.WriteRune('\n')
+			b.WriteString(p.Docs.WIT(r, ""))
+			b.WriteString("package ")
+			name := pr.packageName(p)
+			b.WriteString(name.String())
+			b.WriteString(withEnd(" {\n"+indent(p.itemsWIT(pr))+"}", &p.Docs))
+			b.WriteString(p.Docs.trailingWIT())
+			b.WriteRune('\n')
+		}
+		return b.String()
+	}
 	for i, p := range r.Packages {
 		if i > 0 {
 			b.WriteRune('\n')
 			b.WriteRune('\n')
 		}
-		b.WriteString(p.WIT(r, ""))
+		b.WriteString(p.wit(pr, r))
 	}
 	return b.String()
 }
 
+// printer holds the state for printing a [Resolve] with [PrintOptions].
+// A nil *printer prints with the default options.
+type printer struct {
+	opts PrintOptions
+
+	// elided is the set of packages printed without a version.
+	elided map[*Package]bool
+}
+
+func newPrinter(r *Resolve, opts *PrintOptions) *printer {
+	pr := &printer{}
+	if opts != nil {
+		pr.opts = *opts
+	}
+	if pr.opts.ElideVersions {
+		counts := make(map[string]int)
+		for _, p := range r.Packages {
+			counts[p.Name.Namespace+":"+p.Name.Package]++
+		}
+		pr.elided = make(map[*Package]bool)
+		for _, p := range r.Packages {
+			if counts[p.Name.Namespace+":"+p.Name.Package] == 1 {
+				pr.elided[p] = true
+			}
+		}
+	}
+	return pr
+}
+
+// stability returns the WIT text format for stability gates s,
+// or an empty string if gates are not supported by the printed syntax.
+func (pr *printer) stability(s *Stability) string {
+	if pr != nil && !pr.opts.Syntax.AtLeast(SyntaxGates) {
+		return ""
+	}
+	return s.WIT(nil, "")
+}
+
+// packageName returns the printed name of package p, with its namespace and name escaped.
+func (pr *printer) packageName(p *Package) Ident {
+	name := p.Name
+	name.Namespace = escape(name.Namespace)
+	name.Package = escape(name.Package)
+	if pr != nil && pr.elided[p] {
+		name.Version = nil
+	}
+	return name
+}
+
 // WITKind returns the WIT kind.
 func (*Docs) WITKind() string { return "docs" }
 
-// WIT returns the [WIT] text format for [Docs] d.
+// WIT returns the [WIT] text format for [Docs] d: any leading comments,
+// followed by a blank line, then the doc comments.
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (d *Docs) WIT(_ Node, _ string) string {
+	var b strings.Builder
+	b.WriteString(d.leadingWIT())
 	if d.Contents == "" {
-		return ""
+		return b.String()
 	}
-	var b strings.Builder
 	lineLength := 0
 	for _, c := range d.Contents {
 		if lineLength == 0 {
@@ -113,6 +270,69 @@ func (d *Docs) WIT(_ Node, _ string) string {
 	return b.String()
 }
 
+// hasDocs reports whether d has doc comments or leading comments,
+// which are printed on the lines before a definition.
+func (d *Docs) hasDocs() bool {
+	return d.Contents != "" || (d.Comments != nil && len(d.Comments.Leading) > 0)
+}
+
+// leadingWIT returns the leading comments of d, each on its own line, followed by a blank line.
+func (d *Docs) leadingWIT() string {
+	if d.Comments == nil || len(d.Comments.Leading) == 0 {
+		return ""
+	}
+	return strings.Join(d.Comments.Leading, "\n") + "\n\n"
+}
+
+// trailingWIT returns the trailing comment of d, preceded by a space.
+func (d *Docs) trailingWIT() string {
+	if d.Comments == nil || d.Comments.Trailing == "" {
+		return ""
+	}
+	return " " + d.Comments.Trailing
+}
+
+// endWIT returns the end comments of d, each on its own line.
+func (d *Docs) endWIT() string {
+	if d.Comments == nil || len(d.Comments.End) == 0 {
+		return ""
+	}
+	return strings.Join(d.Comments.End, "\n") + "\n"
+}
+
+// withEnd returns block s, which ends with a closing brace, with the end comments of d
+// indented on the lines before the brace, separated from any items in the block by a blank line.
+func withEnd(s string, d *Docs) string {
+	end := d.endWIT()
+	if end == "" {
+		return s
+	}
+	return strings.TrimSuffix(s, "}") + "\n" + indent(end) + "}"
+}
+
+// WITKind returns the WIT kind.
+func (*Stability) WITKind() string { return "stability" }
+
+// WIT returns the [WIT] text format for [Stability] s: an @since or @unstable annotation,
+// followed by an @deprecated annotation, each on its own line. It returns an empty string if s is nil.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (s *Stability) WIT(_ Node, _ string) string {
+	if s == nil {
+		return ""
+	}
+	var b strings.Builder
+	if s.Feature != "" {
+		b.WriteString("@unstable(feature = " + escape(s.Feature) + ")\n")
+	} else if s.Since != nil {
+		b.WriteString("@since(version = " + s.Since.String() + ")\n")
+	}
+	if s.Deprecated != nil {
+		b.WriteString("@deprecated(version = " + s.Deprecated.String() + ")\n")
+	}
+	return b.String()
+}
+
 const (
 	DocPrefix  = "///"
 	LineLength = 80
@@ -125,11 +345,16 @@ func (*World) WITKind() string { return "world" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (w *World) WIT(ctx Node, name string) string {
+	return w.wit(nil, ctx, name)
+}
+
+func (w *World) wit(pr *printer, ctx Node, name string) string {
 	if name == "" {
 		name = w.Name
 	}
 	var b strings.Builder
 	b.WriteString(w.Docs.WIT(ctx, ""))
+	b.WriteString(pr.stability(w.Stability))
 	b.WriteString("world ")
 	b.WriteString(escape(name)) // TODO: compare to w.Name?
 	b.WriteString(" {")
@@ -143,7 +368,7 @@ func (w *World) WIT(ctx Node, name string) string {
 		if n == 0 {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(w.itemWIT("import", name, i)))
+		b.WriteString(indent(w.itemWIT(pr, "import", name, i)))
 		b.WriteRune('\n')
 		n++
 		return true
@@ -152,25 +377,54 @@ func (w *World) WIT(ctx Node, name string) string {
 		if n == 0 {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(w.itemWIT("export", name, i)))
+		b.WriteString(indent(w.itemWIT(pr, "export", name, i)))
 		b.WriteRune('\n')
 		n++
 		return true
 	})
 	b.WriteRune('}')
-	return b.String()
+	return withEnd(b.String(), &w.Docs) + w.Docs.trailingWIT()
 }
 
-func (w *World) itemWIT(motion, name string, v WorldItem) string {
+func (w *World) itemWIT(pr *printer, motion, name string, v WorldItem) string {
 	switch v := v.(type) {
-	case *Interface, *Function:
-		return motion + " " + v.WIT(w, name) // TODO: handle resource methods?
+	case *Interface:
+		if v.Name == nil {
+			return withMotion(motion, &v.Docs, v.wit(pr, w, name))
+		}
+		// The gates of an interface imported or exported by reference belong to the world.
+		s := w.ImportStability[name]
+		if motion == "export" {
+			s = w.ExportStability[name]
+		}
+		return withMotion(motion, &v.Docs, pr.stability(s)+v.wit(pr, w, name))
+	case *Function:
+		return withMotion(motion, &v.Docs, v.wit(pr, w, name)) // TODO: handle resource methods?
 	case *TypeDef:
-		return v.WIT(w, name) // no motion, in Imports only
+		return v.wit(pr, w, name) // no motion, in Imports only
 	}
 	panic("BUG: unknown WorldItem")
 }
 
+// withMotion returns world item s prefixed with motion (import or export),
+// following any comments, docs d, or feature gates that precede the item.
+func withMotion(motion string, d *Docs, s string) string {
+	lead := d.leadingWIT()
+	if !strings.HasPrefix(s, lead) {
+		lead = ""
+	}
+	s = s[len(lead):]
+	i := 0
+	for strings.HasPrefix(s[i:], DocPrefix) || strings.HasPrefix(s[i:], "@") {
+		n := strings.IndexByte(s[i:], '\n')
+		if n < 0 {
+			break
+		}
+		i += n + 1
+	}
+	return lead + s[:i] + motion + " " + s[i:]
+}
+
 // WITKind returns the WIT kind.
 func (*Interface) WITKind() string { return "interface" }
 
@@ -178,6 +432,10 @@ func (*Interface) WITKind() string { return "interface" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (i *Interface) WIT(ctx Node, name string) string {
+	return i.wit(nil, ctx, name)
+}
+
+func (i *Interface) wit(pr *printer, ctx Node, name string) string {
 	if i.Name != nil && name == "" {
 		name = *i.Name
 	}
@@ -187,17 +445,19 @@ func (i *Interface) WIT(ctx Node, name string) string {
 	switch ctx := ctx.(type) {
 	case *Package:
 		b.WriteString(i.Docs.WIT(ctx, ""))
+		b.WriteString(pr.stability(i.Stability))
 		b.WriteString("interface ")
 		b.WriteString(escape(name))
 		b.WriteRune(' ')
 	case *World:
-		rname := relativeName(i, ctx.Package)
+		rname := pr.relativeName(i, ctx.Package)
 		if rname != "" {
 			return escape(rname) + ";"
 		}
 
 		// Otherwise, this is an inline interface decl.
 		b.WriteString(i.Docs.WIT(ctx, ""))
+		b.WriteString(pr.stability(i.Stability))
 		b.WriteString(escape(name))
 		b.WriteString(": interface ")
 	}
@@ -210,10 +470,10 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if td.Root().Owner == td.Owner {
 			return true // Skip declarations
 		}
-		if n == 0 || td.Docs.Contents != "" {
+		if n == 0 || td.Docs.hasDocs() {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(td.WIT(i, name)))
+		b.WriteString(indent(td.wit(pr, i, name)))
 		b.WriteRune('\n')
 		n++
 		return true
@@ -224,10 +484,10 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if td.Root().Owner != td.Owner {
 			return true // Skip use statements
 		}
-		if n == 0 || td.Docs.Contents != "" {
+		if n == 0 || td.Docs.hasDocs() {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(td.WIT(i, name)))
+		b.WriteString(indent(td.wit(pr, i, name)))
 		b.WriteRune('\n')
 		n++
 		return true
@@ -238,17 +498,17 @@ func (i *Interface) WIT(ctx Node, name string) string {
 		if !f.IsFreestanding() {
 			return true
 		}
-		if n == 0 || f.Docs.Contents != "" {
+		if n == 0 || f.Docs.hasDocs() {
 			b.WriteRune('\n')
 		}
-		b.WriteString(indent(f.WIT(i, name)))
+		b.WriteString(indent(f.wit(pr, i, name)))
 		b.WriteRune('\n')
 		n++
 		return true
 	})
 
 	b.WriteRune('}')
-	return b.String()
+	return withEnd(b.String(), &i.Docs) + i.Docs.trailingWIT()
 }
 
 // WITKind returns the [WIT] kind.
@@ -261,6 +521,10 @@ func (t *TypeDef) WITKind() string {
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (t *TypeDef) WIT(ctx Node, name string) string {
+	return t.wit(nil, ctx, name)
+}
+
+func (t *TypeDef) wit(pr *printer, ctx Node, name string) string {
 	if t.Name != nil && name == "" {
 		name = *t.Name
 	}
@@ -271,10 +535,10 @@ func (t *TypeDef) WIT(ctx Node, name string) string {
 	// If context is another TypeDef, then this is an imported type.
 	case *TypeDef:
 		// Emit an type alias if same Owner.
-		if t.Owner == ctx.Owner && t.Name != nil {
+		if _, ok := ctx.UseSource(); !ok && t.Name != nil {
 			return "type " + escape(name) + " = " + escape(*t.Name)
 		}
-		ownerName := relativeName(t.Owner, ctx.Package())
+		ownerName := pr.relativeName(t.Owner, ctx.Package())
 		if t.Name != nil && *t.Name != name {
 			return fmt.Sprintf("use %s.{%s as %s};", ownerName, escape(*t.Name), escape(name))
 		}
@@ -283,43 +547,54 @@ func (t *TypeDef) WIT(ctx Node, name string) string {
 	case *World, *Interface:
 		var b strings.Builder
 		b.WriteString(t.Docs.WIT(ctx, ""))
-		b.WriteString(t.Kind.WIT(t, name))
+		b.WriteString(pr.stability(t.Stability))
+		if alias, ok := t.Kind.(*TypeDef); ok {
+			b.WriteString(alias.wit(pr, t, name))
+		} else if kind, ok := t.Kind.(multiliner); ok {
+			b.WriteString(pr.unwrap(t.Kind, withEnd(kind.multilineWIT(t, name), &t.Docs)))
+		} else {
+			b.WriteString(t.Kind.WIT(t, name))
+		}
 		constructor := t.Constructor()
 		methods := t.Methods()
 		statics := t.StaticFunctions()
-		if constructor != nil || len(methods) > 0 || len(statics) > 0 {
+		_, isResource := t.Kind.(*Resource)
+		if constructor != nil || len(methods) > 0 || len(statics) > 0 || (isResource && t.Docs.endWIT() != "") {
 			b.WriteString(" {\n")
 			n := 0
 			if constructor != nil {
-				b.WriteString(indent(constructor.WIT(t, "constructor")))
+				b.WriteString(indent(constructor.wit(pr, t, "constructor")))
 				b.WriteRune('\n')
 				n++
 			}
 			slices.SortFunc(methods, functionCompare)
 			for _, f := range methods {
-				if f.Docs.Contents != "" {
+				if f.Docs.hasDocs() {
 					b.WriteRune('\n')
 				}
-				b.WriteString(indent(f.WIT(t, "")))
+				b.WriteString(indent(f.wit(pr, t, "")))
 				b.WriteRune('\n')
 				n++
 			}
 			slices.SortFunc(statics, functionCompare)
 			for _, f := range statics {
-				if f.Docs.Contents != "" {
+				if f.Docs.hasDocs() {
 					b.WriteRune('\n')
 				}
-				b.WriteString(indent(f.WIT(t, "")))
+				b.WriteString(indent(f.wit(pr, t, "")))
 				b.WriteRune('\n')
 				n++
 			}
 			b.WriteRune('}')
 		}
 		s := b.String()
+		if isResource {
+			s = withEnd(s, &t.Docs)
+		}
 		if s[len(s)-1] != '}' && s[len(s)-1] != ';' {
-			b.WriteRune(';')
+			s += ";"
 		}
-		return b.String()
+		return s + t.Docs.trailingWIT()
 	}
 	if name != "" {
 		return escape(name)
@@ -331,32 +606,7 @@ func functionCompare(a, b *Function) int {
 	return strings.Compare(a.Name, b.Name)
 }
 
-func escape(name string) string {
-	if witKeywords[name] {
-		return "%" + name
-	}
-	return name
-}
-
-var witKeywords = map[string]bool{
-	"enum":      true,
-	"export":    true,
-	"flags":     true,
-	"func":      true,
-	"import":    true,
-	"include":   true,
-	"interface": true,
-	"package":   true,
-	"record":    true,
-	"resource":  true,
-	"result":    true,
-	"static":    true,
-	"type":      true,
-	"variant":   true,
-	"world":     true,
-}
-
-func relativeName(o TypeOwner, p *Package) string {
+func (pr *printer) relativeName(o TypeOwner, p *Package) string {
 	var op *Package
 	var name string
 	switch o := o.(type) {
@@ -372,13 +622,13 @@ func relativeName(o TypeOwner, p *Package) string {
 		name = o.Name
 	}
 	if op == p {
-		return name
+		return escape(name)
 	}
 	if op == nil {
 		return ""
 	}
-	qualifiedName := op.Name
-	qualifiedName.Package += "/" + name
+	qualifiedName := pr.packageName(op)
+	qualifiedName.Package += "/" + escape(name)
 	return qualifiedName.String()
 }
 
@@ -409,6 +659,15 @@ func (*Record) WITKind() string { return "record" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (r *Record) WIT(ctx Node, name string) string {
+	s := r.multilineWIT(ctx, name)
+	if ctx == nil {
+		return s
+	}
+	return (*printer)(nil).unwrap(r, s)
+}
+
+// multilineWIT returns the WIT text format for [Record] r with each field on a separate line.
+func (r *Record) multilineWIT(ctx Node, name string) string {
 	var b strings.Builder
 	b.WriteString("record ")
 	b.WriteString(escape(name))
@@ -417,14 +676,15 @@ func (r *Record) WIT(ctx Node, name string) string {
 		b.WriteRune('\n')
 		for i := range r.Fields {
 			b.WriteString(indent(r.Fields[i].WIT(ctx, "")))
-			b.WriteString(",\n")
+			b.WriteRune(',')
+			if ctx != nil {
+				b.WriteString(r.Fields[i].Docs.trailingWIT())
+			}
+			b.WriteRune('\n')
 		}
 	}
 	b.WriteRune('}')
-	if ctx == nil {
-		return b.String()
-	}
-	return unwrap(b.String())
+	return b.String()
 }
 
 // WITKind returns the WIT kind.
@@ -498,6 +758,15 @@ func (*Flags) WITKind() string { return "flags" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (f *Flags) WIT(ctx Node, name string) string {
+	s := f.multilineWIT(ctx, name)
+	if ctx == nil {
+		return s
+	}
+	return (*printer)(nil).unwrap(f, s)
+}
+
+// multilineWIT returns the WIT text format for [Flags] f with each flag on a separate line.
+func (f *Flags) multilineWIT(ctx Node, name string) string {
 	var b strings.Builder
 	b.WriteString("flags ")
 	b.WriteString(escape(name))
@@ -505,14 +774,15 @@ func (f *Flags) WIT(ctx Node, name string) string {
 	if len(f.Flags) > 0 {
 		for i := range f.Flags {
 			b.WriteString(indent(f.Flags[i].WIT(ctx, "")))
-			b.WriteString(",\n")
+			b.WriteRune(',')
+			if ctx != nil {
+				b.WriteString(f.Flags[i].Docs.trailingWIT())
+			}
+			b.WriteRune('\n')
 		}
 	}
 	b.WriteRune('}')
-	if ctx == nil {
-		return b.String()
-	}
-	return unwrap(b.String())
+	return b.String()
 }
 
 // WITKind returns the WIT kind.
@@ -560,6 +830,15 @@ func (*Variant) WITKind() string { return "variant" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (v *Variant) WIT(ctx Node, name string) string {
+	s := v.multilineWIT(ctx, name)
+	if ctx == nil {
+		return s
+	}
+	return (*printer)(nil).unwrap(v, s)
+}
+
+// multilineWIT returns the WIT text format for [Variant] v with each case on a separate line.
+func (v *Variant) multilineWIT(ctx Node, name string) string {
 	var b strings.Builder
 	b.WriteString("variant ")
 	b.WriteString(escape(name))
@@ -568,14 +847,15 @@ func (v *Variant) WIT(ctx Node, name string) string {
 		b.WriteRune('\n')
 		for i := range v.Cases {
 			b.WriteString(indent(v.Cases[i].WIT(ctx, "")))
-			b.WriteString(",\n")
+			b.WriteRune(',')
+			if ctx != nil {
+				b.WriteString(v.Cases[i].Docs.trailingWIT())
+			}
+			b.WriteRune('\n')
 		}
 	}
 	b.WriteRune('}')
-	if ctx == nil {
-		return b.String()
-	}
-	return unwrap(b.String())
+	return b.String()
 }
 
 // WITKind returns the WIT kind.
@@ -605,6 +885,15 @@ func (*Enum) WITKind() string { return "enum" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (e *Enum) WIT(ctx Node, name string) string {
+	s := e.multilineWIT(ctx, name)
+	if ctx == nil {
+		return s
+	}
+	return (*printer)(nil).unwrap(e, s)
+}
+
+// multilineWIT returns the WIT text format for [Enum] e with each case on a separate line.
+func (e *Enum) multilineWIT(ctx Node, name string) string {
 	var b strings.Builder
 	b.WriteString("enum ")
 	b.WriteString(escape(name))
@@ -612,18 +901,18 @@ func (e *Enum) WIT(ctx Node, name string) string {
 	if len(e.Cases) > 0 {
 		b.WriteRune('\n')
 		for i := range e.Cases {
-			if i > 0 {
-				b.WriteString(",\n")
-			}
 			b.WriteString(indent(e.Cases[i].WIT(ctx, "")))
+			if i < len(e.Cases)-1 {
+				b.WriteRune(',')
+			}
+			if ctx != nil {
+				b.WriteString(e.Cases[i].Docs.trailingWIT())
+			}
+			b.WriteRune('\n')
 		}
-		b.WriteRune('\n')
 	}
 	b.WriteRune('}')
-	if ctx == nil {
-		return b.String()
-	}
-	return unwrap(b.String())
+	return b.String()
 }
 
 // WITKind returns the WIT kind.
@@ -770,7 +1059,7 @@ func (_primitive[T]) WITKind() string { return "type" }
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (p _primitive[T]) WIT(_ Node, name string) string {
 	if name != "" {
-		return "type " + name + " = " + p.TypeName()
+		return "type " + escape(name) + " = " + p.TypeName()
 	}
 	return p.TypeName()
 }
@@ -795,26 +1084,30 @@ func (f *Function) WITKind() string {
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (f *Function) WIT(ctx Node, name string) string {
+	return f.wit(nil, ctx, name)
+}
+
+func (f *Function) wit(pr *printer, ctx Node, name string) string {
 	if name == "" {
-		name = f.Name
-		if _, after, found := strings.Cut(name, "."); found {
-			name = after
-		}
+		name = f.BaseName()
 	}
 	var b strings.Builder
 	if ctx != nil {
 		b.WriteString(f.Docs.WIT(ctx, ""))
+		b.WriteString(pr.stability(f.Stability))
 	}
-	b.WriteString(escape(name))
 	var isConstructor, isMethod bool
 	switch f.Kind.(type) {
 	case *Constructor:
+		b.WriteString(name)
 		b.WriteRune('(')
 		isConstructor = true
 	case *Freestanding, *Method:
+		b.WriteString(escape(name))
 		b.WriteString(": func(")
 		isMethod = true
 	case *Static:
+		b.WriteString(escape(name))
 		b.WriteString(": static func(")
 	}
 	b.WriteString(paramsWIT(f.Params, isMethod))
@@ -831,6 +1124,9 @@ func (f *Function) WIT(ctx Node, name string) string {
 		}
 	}
 	b.WriteRune(';')
+	if ctx != nil {
+		b.WriteString(f.Docs.trailingWIT())
+	}
 	return b.String()
 }
 
@@ -860,7 +1156,7 @@ func (p *Param) WIT(_ Node, _ string) string {
 	if p.Name == "" {
 		return p.Type.WIT(p, "")
 	}
-	return p.Name + ": " + p.Type.WIT(p, "")
+	return escape(p.Name) + ": " + p.Type.WIT(p, "")
 }
 
 // WITKind returns the WIT kind.
@@ -870,32 +1166,55 @@ func (*Package) WITKind() string { return "package" }
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (p *Package) WIT(ctx Node, _ string) string {
+	return p.wit(nil, ctx)
+}
+
+func (p *Package) wit(pr *printer, ctx Node) string {
 	var b strings.Builder
 	b.WriteString(p.Docs.WIT(ctx, ""))
 	b.WriteString("package ")
-	b.WriteString(p.Name.String())
-	b.WriteString(";\n")
-	if p.Interfaces.Len() > 0 {
+	name := pr.packageName(p)
+	b.WriteString(name.String())
+	b.WriteRune(';')
+	b.WriteString(p.Docs.trailingWIT())
+	b.WriteRune('\n')
+	items := p.itemsWIT(pr)
+	if items != "" {
 		b.WriteRune('\n')
+		b.WriteString(items)
+	}
+	if end := p.Docs.endWIT(); end != "" {
+		b.WriteRune('\n')
+		b.WriteString(end)
+	}
+	return b.String()
+}
+
+// itemsWIT returns the WIT text format for the interfaces and worlds in package p.
+func (p *Package) itemsWIT(pr *printer) string {
+	var b strings.Builder
+	if p.Interfaces.Len() > 0 {
 		i := 0
 		p.Interfaces.All()(func(name string, face *Interface) bool {
 			if i > 0 {
 				b.WriteRune('\n')
 			}
-			b.WriteString(face.WIT(p, name))
+			b.WriteString(face.wit(pr, p, name))
 			b.WriteRune('\n')
 			i++
 			return true
 		})
 	}
 	if p.Worlds.Len() > 0 {
-		b.WriteRune('\n')
+		if p.Interfaces.Len() > 0 {
+			b.WriteRune('\n')
+		}
 		i := 0
 		p.Worlds.All()(func(name string, w *World) bool {
 			if i > 0 {
 				b.WriteRune('\n')
 			}
-			b.WriteString(w.WIT(p, name))
+			b.WriteString(w.wit(pr, p, name))
 			b.WriteRune('\n')
 			return true
 		})
@@ -0,0 +1,183 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// printJSON represents two versions of package a:dep and package b:dep, used by package c:main.
+const printJSON = `{
+	"worlds": [
+		{"name": "w", "imports": {"interface-0": {"interface": 0}, "interface-1": {"interface": 1}, "interface-2": {"interface": 2}}, "exports": {}, "package": 3}
+	],
+	"interfaces": [
+		{"name": "i", "types": {"t": 0}, "functions": {}, "package": 0},
+		{"name": "i", "types": {}, "functions": {}, "package": 1},
+		{"name": "j", "types": {"t": 1}, "functions": {}, "package": 2}
+	],
+	"types": [
+		{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+		{"name": "t", "kind": {"type": 0}, "owner": {"interface": 2}}
+	],
+	"packages": [
+		{"name": "a:dep@0.1.0", "interfaces": {"i": 0}, "worlds": {}},
+		{"name": "a:dep@0.2.0", "interfaces": {"i": 1}, "worlds": {}},
+		{"name": "b:dep@1.0.0", "interfaces": {"j": 2}, "worlds": {}},
+		{"name": "c:main@2.0.0", "interfaces": {}, "worlds": {"w": 0}}
+	]
+}`
+
+func TestPrintWIT(t *testing.T) {
+	res, err := DecodeJSON(strings.NewReader(printJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts *PrintOptions
+		want string
+	}{
+		{"default", nil, res.WIT(nil, "")},
+		{"nested", &PrintOptions{NestedPackages: true}, `package c:main@2.0.0;
+
+world w {
+	import a:dep/i@0.1.0;
+	import a:dep/i@0.2.0;
+	import b:dep/j@1.0.0;
+}
+
+package a:dep@0.1.0 {
+	interface i {
+		type t = u32;
+	}
+}
+
+package a:dep@0.2.0 {
+	interface i {}
+}
+
+package b:dep@1.0.0 {
+	interface j {
+		use a:dep/i@0.1.0.{t};
+	}
+}
+`},
+		{"elide versions", &PrintOptions{ElideVersions: true}, `package a:dep@0.1.0;
+
+interface i {
+	type t = u32;
+}
+
+
+package a:dep@0.2.0;
+
+interface i {}
+
+
+package b:dep;
+
+interface j {
+	use a:dep/i@0.1.0.{t};
+}
+
+
+package c:main;
+
+world w {
+	import a:dep/i@0.1.0;
+	import a:dep/i@0.2.0;
+	import b:dep/j;
+}
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := res.PrintWIT(tt.opts)
+			if got != tt.want {
+				t.Errorf("PrintWIT:\n%s\nexpected:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintWITWrapping(t *testing.T) {
+	res, err := LoadFS(fstest.MapFS{
+		"wit/w.wit": {Data: []byte(`package a:wrap;
+
+interface i {
+	record point { x: u32, y: u32 }
+	enum color { red, green, blue, cyan, magenta, yellow }
+	flags perms { read, write }
+}
+`)},
+	}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts *PrintOptions
+		want []string
+	}{
+		{"default", nil, []string{
+			"\trecord point { x: u32, y: u32 }\n",
+			"\tenum color {\n\t\tred,\n",
+			"\tflags perms { read, write }\n",
+		}},
+		{"max width", &PrintOptions{MaxWidth: 20}, []string{
+			"\trecord point {\n\t\tx: u32,\n",
+			"\tflags perms {\n\t\tread,\n",
+		}},
+		{"never unwrap", &PrintOptions{MaxWidth: -1}, []string{
+			"\trecord point {\n\t\tx: u32,\n",
+			"\tflags perms {\n\t\tread,\n",
+		}},
+		{"max lines", &PrintOptions{MaxWidth: 100, MaxLines: 10}, []string{
+			"\tenum color { red, green, blue, cyan, magenta, yellow }\n",
+		}},
+		{"expand records", &PrintOptions{ExpandRecords: true}, []string{
+			"\trecord point {\n\t\tx: u32,\n",
+			"\tflags perms { read, write }\n",
+		}},
+		{"compact enums", &PrintOptions{CompactEnums: true}, []string{
+			"\trecord point { x: u32, y: u32 }\n",
+			"\tenum color { red, green, blue, cyan, magenta, yellow }\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := res.PrintWIT(tt.opts)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("PrintWIT: expected %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintWorldItemDocs(t *testing.T) {
+	const src = `package a:docs;
+
+world w {
+	/// Imports f.
+	import f: func();
+	/// Exports g.
+	@since(version = 0.1.0)
+	export g: func();
+}
+`
+	res, err := LoadFS(fstest.MapFS{"wit/w.wit": {Data: []byte(src)}}, "wit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := res.WIT(nil, "")
+	for _, want := range []string{"\t/// Imports f.\n\timport f: func();\n", "\t/// Exports g.\n\t@since(version = 0.1.0)\n\texport g: func();\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WIT: expected %q in:\n%s", want, got)
+		}
+	}
+}